@@ -58,6 +58,15 @@ type ACL struct {
 	// We use an iradix for the purposes of ordered iteration.
 	wildcardHostVolumes *iradix.Tree
 
+	// variables maps a namespace and variables path, separated by a null
+	// byte, to a capabilitySet
+	variables *iradix.Tree
+
+	// wildcardVariables maps a namespace and variables path, separated by a
+	// null byte, to a capabilitySet where either contains a glob pattern. We
+	// use an iradix for the purposes of ordered iteration.
+	wildcardVariables *iradix.Tree
+
 	agent    string
 	node     string
 	operator string
@@ -95,6 +104,8 @@ func NewACL(management bool, policies []*Policy) (*ACL, error) {
 	wnsTxn := iradix.New().Txn()
 	hvTxn := iradix.New().Txn()
 	whvTxn := iradix.New().Txn()
+	varTxn := iradix.New().Txn()
+	wvarTxn := iradix.New().Txn()
 
 	for _, policy := range policies {
 	NAMESPACES:
@@ -102,6 +113,11 @@ func NewACL(management bool, policies []*Policy) (*ACL, error) {
 			// Should the namespace be matched using a glob?
 			globDefinition := strings.Contains(ns.Name, "*")
 
+			// Add the variables capabilities before the namespace
+			// capabilities, as a namespace deny skips the remainder of the
+			// loop.
+			insertVariablesCapabilities(varTxn, wvarTxn, ns)
+
 			// Check for existing capabilities
 			var capabilities capabilitySet
 
@@ -206,10 +222,67 @@ func NewACL(management bool, policies []*Policy) (*ACL, error) {
 	acl.wildcardNamespaces = wnsTxn.Commit()
 	acl.hostVolumes = hvTxn.Commit()
 	acl.wildcardHostVolumes = whvTxn.Commit()
+	acl.variables = varTxn.Commit()
+	acl.wildcardVariables = wvarTxn.Commit()
 
 	return acl, nil
 }
 
+// insertVariablesCapabilities adds the variables capabilities of the
+// namespace policy to the transactions. The short hand namespace policy grants
+// capabilities on all paths within the namespace, and is combined with any
+// capabilities granted on explicit paths.
+func insertVariablesCapabilities(varTxn, wvarTxn *iradix.Txn, ns *NamespacePolicy) {
+	insert := func(path string, caps []string) {
+		if len(caps) == 0 {
+			return
+		}
+
+		key := []byte(variablesKey(ns.Name, path))
+		txn := varTxn
+		if strings.Contains(ns.Name, "*") || strings.Contains(path, "*") {
+			txn = wvarTxn
+		}
+
+		var capabilities capabilitySet
+		if raw, ok := txn.Get(key); ok {
+			capabilities = raw.(capabilitySet)
+		} else {
+			capabilities = make(capabilitySet)
+			txn.Insert(key, capabilities)
+		}
+
+		// Deny always takes precedence
+		if capabilities.Check(VariablesCapabilityDeny) {
+			return
+		}
+		for _, cap := range caps {
+			if cap == VariablesCapabilityDeny {
+				capabilities.Clear()
+				capabilities.Set(VariablesCapabilityDeny)
+				return
+			}
+			capabilities.Set(cap)
+		}
+	}
+
+	if ns.Policy != "" {
+		insert("*", expandVariablesPolicy(ns.Policy))
+	}
+	if ns.Variables != nil {
+		for _, pathPolicy := range ns.Variables.Paths {
+			insert(pathPolicy.PathSpec, pathPolicy.Capabilities)
+		}
+	}
+}
+
+// variablesKey returns the key used to store the capabilities of a variables
+// path within the variables radix trees. Namespaces and paths cannot contain
+// a null byte, so the key cannot be ambiguous.
+func variablesKey(ns, path string) string {
+	return ns + "\x00" + path
+}
+
 // AllowNsOp is shorthand for AllowNamespaceOperation
 func (a *ACL) AllowNsOp(ns string, op string) bool {
 	return a.AllowNamespaceOperation(ns, op)
@@ -291,6 +364,40 @@ func (a *ACL) AllowHostVolume(ns string) bool {
 	return !capabilities.Check(PolicyDeny)
 }
 
+// AllowVariableOperation checks if a given operation is allowed for a
+// variables path within a namespace
+func (a *ACL) AllowVariableOperation(ns, path, op string) bool {
+	// Hot path management tokens
+	if a.management {
+		return true
+	}
+
+	// Check for a matching capability set
+	capabilities, ok := a.matchingVariablesCapabilitySet(ns, path)
+	if !ok {
+		return false
+	}
+
+	// Check if the capability has been granted
+	return capabilities.Check(op)
+}
+
+// matchingVariablesCapabilitySet looks for a capabilitySet that matches the
+// namespace and variables path, if no concrete definitions are found, then we
+// return the closest matching glob.
+func (a *ACL) matchingVariablesCapabilitySet(ns, path string) (capabilitySet, bool) {
+	key := variablesKey(ns, path)
+
+	// Check for a concrete matching capability set
+	raw, ok := a.variables.Get([]byte(key))
+	if ok {
+		return raw.(capabilitySet), true
+	}
+
+	// We didn't find a concrete match, so lets try and evaluate globs.
+	return a.findClosestMatchingGlob(a.wildcardVariables, key)
+}
+
 // matchingNamespaceCapabilitySet looks for a capabilitySet that matches the namespace,
 // if no concrete definitions are found, then we return the closest matching
 // glob.
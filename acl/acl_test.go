@@ -5,6 +5,7 @@ import (
 
 	"github.com/hashicorp/nomad/ci"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCapabilitySet(t *testing.T) {
@@ -327,6 +328,131 @@ func TestWildcardNamespaceMatching(t *testing.T) {
 	}
 }
 
+func TestVariablesMatching(t *testing.T) {
+	ci.Parallel(t)
+
+	tests := []struct {
+		name   string
+		policy string
+		ns     string
+		path   string
+		op     string
+		allow  bool
+	}{
+		{
+			name:   "namespace read policy grants read",
+			policy: `namespace "default" { policy = "read" }`,
+			ns:     "default",
+			path:   "foo/bar",
+			op:     VariablesCapabilityRead,
+			allow:  true,
+		},
+		{
+			name:   "namespace read policy does not grant write",
+			policy: `namespace "default" { policy = "read" }`,
+			ns:     "default",
+			path:   "foo/bar",
+			op:     VariablesCapabilityWrite,
+			allow:  false,
+		},
+		{
+			name:   "namespace write policy grants destroy",
+			policy: `namespace "default" { policy = "write" }`,
+			ns:     "default",
+			path:   "foo/bar",
+			op:     VariablesCapabilityDestroy,
+			allow:  true,
+		},
+		{
+			name: "path glob matches",
+			policy: `namespace "default" {
+			           variables {
+			             path "foo/*" { capabilities = ["read"] }
+			           }
+			         }`,
+			ns:    "default",
+			path:  "foo/bar",
+			op:    VariablesCapabilityRead,
+			allow: true,
+		},
+		{
+			name: "path glob does not match other paths",
+			policy: `namespace "default" {
+			           variables {
+			             path "foo/*" { capabilities = ["read"] }
+			           }
+			         }`,
+			ns:    "default",
+			path:  "bar/foo",
+			op:    VariablesCapabilityRead,
+			allow: false,
+		},
+		{
+			name: "path does not match other namespaces",
+			policy: `namespace "default" {
+			           variables {
+			             path "foo/*" { capabilities = ["read"] }
+			           }
+			         }`,
+			ns:    "other",
+			path:  "foo/bar",
+			op:    VariablesCapabilityRead,
+			allow: false,
+		},
+		{
+			name: "namespace glob matches",
+			policy: `namespace "prod-*" {
+			           variables {
+			             path "foo" { capabilities = ["read"] }
+			           }
+			         }`,
+			ns:    "prod-api",
+			path:  "foo",
+			op:    VariablesCapabilityRead,
+			allow: true,
+		},
+		{
+			name: "concrete path takes precedence",
+			policy: `namespace "default" {
+			           policy = "write"
+			           variables {
+			             path "secret" { capabilities = ["deny"] }
+			           }
+			         }`,
+			ns:    "default",
+			path:  "secret",
+			op:    VariablesCapabilityRead,
+			allow: false,
+		},
+		{
+			name: "closest glob match wins",
+			policy: `namespace "default" {
+			           variables {
+			             path "*" { capabilities = ["read"] }
+			             path "secret/*" { capabilities = ["deny"] }
+			           }
+			         }`,
+			ns:    "default",
+			path:  "secret/key",
+			op:    VariablesCapabilityRead,
+			allow: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			policy, err := Parse(tc.policy)
+			require.NoError(t, err)
+
+			acl, err := NewACL(false, []*Policy{policy})
+			require.NoError(t, err)
+
+			require.Equal(t, tc.allow, acl.AllowVariableOperation(tc.ns, tc.path, tc.op))
+			require.True(t, ManagementACL.AllowVariableOperation(tc.ns, tc.path, tc.op))
+		})
+	}
+}
+
 func TestWildcardHostVolumeMatching(t *testing.T) {
 	ci.Parallel(t)
 
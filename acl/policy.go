@@ -52,6 +52,23 @@ var (
 	validNamespace = regexp.MustCompile("^[a-zA-Z0-9-*]{1,128}$")
 )
 
+const (
+	// The following are the fine-grained capabilities that can be granted for
+	// a variables path. When capabilities are combined we take the union of
+	// all capabilities. If the deny capability is present, it takes
+	// precedence and overwrites all other capabilities.
+
+	VariablesCapabilityDeny    = "deny"
+	VariablesCapabilityRead    = "read"
+	VariablesCapabilityWrite   = "write"
+	VariablesCapabilityList    = "list"
+	VariablesCapabilityDestroy = "destroy"
+)
+
+var (
+	validVariablesPath = regexp.MustCompile("^[a-zA-Z0-9-_~/*]{1,128}$")
+)
+
 const (
 	// The following are the fine-grained capabilities that can be granted for a volume set.
 	// The Policy stanza is a short hand for granting several of these. When capabilities are
@@ -96,6 +113,19 @@ type NamespacePolicy struct {
 	Name         string `hcl:",key"`
 	Policy       string
 	Capabilities []string
+	Variables    *VariablesPolicy `hcl:"variables"`
+}
+
+// VariablesPolicy is the policy for the variables within a namespace
+type VariablesPolicy struct {
+	Paths []*VariablesPathPolicy `hcl:"path"`
+}
+
+// VariablesPathPolicy is the policy for a variables path, which may include
+// glob wildcards
+type VariablesPathPolicy struct {
+	PathSpec     string `hcl:",key"`
+	Capabilities []string
 }
 
 // HostVolumePolicy is the policy for a specific named host volume
@@ -211,6 +241,38 @@ func expandNamespacePolicy(policy string) []string {
 	}
 }
 
+// isVariablesCapabilityValid ensures the given capability is valid for a
+// variables path policy
+func isVariablesCapabilityValid(cap string) bool {
+	switch cap {
+	case VariablesCapabilityDeny, VariablesCapabilityRead, VariablesCapabilityWrite,
+		VariablesCapabilityList, VariablesCapabilityDestroy:
+		return true
+	default:
+		return false
+	}
+}
+
+// expandVariablesPolicy provides the equivalent set of variables capabilities
+// for a namespace policy. These are granted on all paths within the namespace.
+func expandVariablesPolicy(policy string) []string {
+	switch policy {
+	case PolicyDeny:
+		return []string{VariablesCapabilityDeny}
+	case PolicyRead:
+		return []string{VariablesCapabilityRead, VariablesCapabilityList}
+	case PolicyWrite:
+		return []string{
+			VariablesCapabilityRead,
+			VariablesCapabilityList,
+			VariablesCapabilityWrite,
+			VariablesCapabilityDestroy,
+		}
+	default:
+		return nil
+	}
+}
+
 func isHostVolumeCapabilityValid(cap string) bool {
 	switch cap {
 	case HostVolumeCapabilityDeny, HostVolumeCapabilityMountReadOnly, HostVolumeCapabilityMountReadWrite:
@@ -268,6 +330,22 @@ func Parse(rules string) (*Policy, error) {
 				return nil, fmt.Errorf("Invalid namespace capability '%s': %#v", cap, ns)
 			}
 		}
+		if ns.Variables != nil {
+			if len(ns.Variables.Paths) == 0 {
+				return nil, fmt.Errorf("Invalid variables policy: no paths in namespace %q", ns.Name)
+			}
+			for _, pathPolicy := range ns.Variables.Paths {
+				if !validVariablesPath.MatchString(pathPolicy.PathSpec) {
+					return nil, fmt.Errorf("Invalid variables path %q in namespace %q", pathPolicy.PathSpec, ns.Name)
+				}
+				for _, cap := range pathPolicy.Capabilities {
+					if !isVariablesCapabilityValid(cap) {
+						return nil, fmt.Errorf("Invalid variables capability '%s' on path %q in namespace %q",
+							cap, pathPolicy.PathSpec, ns.Name)
+					}
+				}
+			}
+		}
 
 		// Expand the short hand policy to the capabilities and
 		// add to any existing capabilities
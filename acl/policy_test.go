@@ -290,6 +290,66 @@ func TestParse(t *testing.T) {
 			"Invalid host volume name",
 			nil,
 		},
+		{
+			`
+			namespace "default" {
+				variables {
+					path "nomad/jobs/*" {
+						capabilities = ["read", "list"]
+					}
+					path "project/*" {
+						capabilities = ["write", "destroy"]
+					}
+				}
+			}
+			`,
+			"",
+			&Policy{
+				Namespaces: []*NamespacePolicy{
+					{
+						Name: "default",
+						Variables: &VariablesPolicy{
+							Paths: []*VariablesPathPolicy{
+								{
+									PathSpec:     "nomad/jobs/*",
+									Capabilities: []string{VariablesCapabilityRead, VariablesCapabilityList},
+								},
+								{
+									PathSpec:     "project/*",
+									Capabilities: []string{VariablesCapabilityWrite, VariablesCapabilityDestroy},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			`
+			namespace "default" {
+				variables {
+					path "nomad/jobs/*" {
+						capabilities = ["read-job"]
+					}
+				}
+			}
+			`,
+			"Invalid variables capability",
+			nil,
+		},
+		{
+			`
+			namespace "default" {
+				variables {
+					path "path with spaces" {
+						capabilities = ["read"]
+					}
+				}
+			}
+			`,
+			"Invalid variables path",
+			nil,
+		},
 		{
 			`
 			plugin {
@@ -2,9 +2,11 @@ package api
 
 import (
 	"testing"
+	"time"
 
 	"github.com/hashicorp/nomad/api/internal/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestACLPolicies_ListUpsert(t *testing.T) {
@@ -299,3 +301,189 @@ func TestACLTokens_BootstrapValidToken(t *testing.T) {
 	assertWriteMeta(t, wm)
 	assert.Equal(t, bootkn, out.SecretID)
 }
+
+func TestACLRoles(t *testing.T) {
+	testutil.Parallel(t)
+
+	testClient, testServer, _ := makeACLClient(t, nil, nil)
+	defer testServer.Stop()
+
+	// An initial listing shouldn't return any results.
+	aclRoleListResp, queryMeta, err := testClient.ACLRoles().List(nil)
+	require.NoError(t, err)
+	require.Empty(t, aclRoleListResp)
+	assertQueryMeta(t, queryMeta)
+
+	// Create an ACL policy that can be referenced within the ACL role.
+	aclPolicy := ACLPolicy{
+		Name: "acl-role-api-test",
+		Rules: `namespace "default" {
+			policy = "read"
+		}
+		`,
+	}
+	writeMeta, err := testClient.ACLPolicies().Upsert(&aclPolicy, nil)
+	require.NoError(t, err)
+	assertWriteMeta(t, writeMeta)
+
+	// Create an ACL role referencing the previously created policy.
+	role := ACLRole{
+		Name:     "acl-role-api-test",
+		Policies: []*ACLRolePolicyLink{{Name: aclPolicy.Name}},
+	}
+	aclRoleCreateResp, writeMeta, err := testClient.ACLRoles().Create(&role, nil)
+	require.NoError(t, err)
+	assertWriteMeta(t, writeMeta)
+	require.NotEmpty(t, aclRoleCreateResp.ID)
+	require.Equal(t, role.Name, aclRoleCreateResp.Name)
+
+	// Another listing should return one result.
+	aclRoleListResp, queryMeta, err = testClient.ACLRoles().List(nil)
+	require.NoError(t, err)
+	require.Len(t, aclRoleListResp, 1)
+	assertQueryMeta(t, queryMeta)
+
+	// Read the role using its ID.
+	aclRoleReadResp, queryMeta, err := testClient.ACLRoles().Get(aclRoleCreateResp.ID, nil)
+	require.NoError(t, err)
+	assertQueryMeta(t, queryMeta)
+	require.Equal(t, aclRoleCreateResp, aclRoleReadResp)
+
+	// Read the role using its name.
+	aclRoleReadResp, queryMeta, err = testClient.ACLRoles().GetByName(aclRoleCreateResp.Name, nil)
+	require.NoError(t, err)
+	assertQueryMeta(t, queryMeta)
+	require.Equal(t, aclRoleCreateResp, aclRoleReadResp)
+
+	// Update the role name.
+	role.ID = aclRoleCreateResp.ID
+	role.Name = "acl-role-api-test-badger-badger-badger"
+	aclRoleUpdateResp, writeMeta, err := testClient.ACLRoles().Update(&role, nil)
+	require.NoError(t, err)
+	assertWriteMeta(t, writeMeta)
+	require.Equal(t, role.Name, aclRoleUpdateResp.Name)
+	require.Equal(t, role.ID, aclRoleUpdateResp.ID)
+
+	// Create a token linked to the role, using its name.
+	token := ACLToken{
+		Name:  "acl-role-api-test",
+		Type:  "client",
+		Roles: []*ACLTokenRoleLink{{Name: role.Name}},
+	}
+	aclTokenCreateResp, _, err := testClient.ACLTokens().Create(&token, nil)
+	require.NoError(t, err)
+	require.Equal(t, []*ACLTokenRoleLink{{ID: role.ID, Name: role.Name}}, aclTokenCreateResp.Roles)
+
+	// Delete the role.
+	writeMeta, err = testClient.ACLRoles().Delete(aclRoleCreateResp.ID, nil)
+	require.NoError(t, err)
+	assertWriteMeta(t, writeMeta)
+
+	// Make sure there are no ACL roles now present.
+	aclRoleListResp, queryMeta, err = testClient.ACLRoles().List(nil)
+	require.NoError(t, err)
+	require.Empty(t, aclRoleListResp)
+	assertQueryMeta(t, queryMeta)
+}
+
+func TestACLAuthMethods(t *testing.T) {
+	testutil.Parallel(t)
+
+	testClient, testServer, _ := makeACLClient(t, nil, nil)
+	defer testServer.Stop()
+
+	// An initial listing shouldn't return any results.
+	aclAuthMethodsListResp, queryMeta, err := testClient.ACLAuthMethods().List(nil)
+	require.NoError(t, err)
+	require.Empty(t, aclAuthMethodsListResp)
+	assertQueryMeta(t, queryMeta)
+
+	// Create an ACL auth method.
+	authMethod := ACLAuthMethod{
+		Name:          "acl-auth-method-api-test",
+		Type:          ACLAuthMethodTypeOIDC,
+		TokenLocality: ACLAuthMethodTokenLocalityLocal,
+		MaxTokenTTL:   15 * time.Minute,
+		Default:       true,
+		Config: &ACLAuthMethodConfig{
+			OIDCDiscoveryURL:    "http://example.com",
+			OIDCClientID:        "mock",
+			OIDCClientSecret:    "very secret secret",
+			AllowedRedirectURIs: []string{"http://localhost:4649/oidc/callback"},
+		},
+	}
+	createdAuthMethod, writeMeta, err := testClient.ACLAuthMethods().Create(&authMethod, nil)
+	require.NoError(t, err)
+	assertWriteMeta(t, writeMeta)
+	require.Equal(t, authMethod.Name, createdAuthMethod.Name)
+	require.Equal(t, authMethod.MaxTokenTTL, createdAuthMethod.MaxTokenTTL)
+
+	// Another listing should return one result.
+	aclAuthMethodsListResp, queryMeta, err = testClient.ACLAuthMethods().List(nil)
+	require.NoError(t, err)
+	require.Len(t, aclAuthMethodsListResp, 1)
+	require.Equal(t, authMethod.Name, aclAuthMethodsListResp[0].Name)
+	require.True(t, aclAuthMethodsListResp[0].Default)
+	assertQueryMeta(t, queryMeta)
+
+	// Read the auth method.
+	aclAuthMethodReadResp, queryMeta, err := testClient.ACLAuthMethods().Get(authMethod.Name, nil)
+	require.NoError(t, err)
+	assertQueryMeta(t, queryMeta)
+	require.Equal(t, createdAuthMethod, aclAuthMethodReadResp)
+
+	// Update the auth method.
+	authMethod.MaxTokenTTL = time.Hour
+	updatedAuthMethod, writeMeta, err := testClient.ACLAuthMethods().Update(&authMethod, nil)
+	require.NoError(t, err)
+	assertWriteMeta(t, writeMeta)
+	require.Equal(t, time.Hour, updatedAuthMethod.MaxTokenTTL)
+
+	// Create a binding rule linked to the auth method.
+	bindingRule := ACLBindingRule{
+		Description: "acl-binding-rule-api-test",
+		AuthMethod:  authMethod.Name,
+		Selector:    "engineering in list.roles",
+		BindType:    ACLBindingRuleBindTypeManagement,
+	}
+	createdBindingRule, writeMeta, err := testClient.ACLBindingRules().Create(&bindingRule, nil)
+	require.NoError(t, err)
+	assertWriteMeta(t, writeMeta)
+	require.NotEmpty(t, createdBindingRule.ID)
+
+	// Binding rule IDs are generated by the server.
+	_, _, err = testClient.ACLBindingRules().Create(createdBindingRule, nil)
+	require.Error(t, err)
+
+	// Read and update the binding rule.
+	aclBindingRuleReadResp, queryMeta, err := testClient.ACLBindingRules().Get(createdBindingRule.ID, nil)
+	require.NoError(t, err)
+	assertQueryMeta(t, queryMeta)
+	require.Equal(t, createdBindingRule, aclBindingRuleReadResp)
+
+	aclBindingRuleReadResp.Description = "updated-description"
+	updatedBindingRule, writeMeta, err := testClient.ACLBindingRules().Update(aclBindingRuleReadResp, nil)
+	require.NoError(t, err)
+	assertWriteMeta(t, writeMeta)
+	require.Equal(t, "updated-description", updatedBindingRule.Description)
+
+	aclBindingRulesListResp, queryMeta, err := testClient.ACLBindingRules().List(nil)
+	require.NoError(t, err)
+	require.Len(t, aclBindingRulesListResp, 1)
+	assertQueryMeta(t, queryMeta)
+
+	// Delete the binding rule and then the auth method.
+	writeMeta, err = testClient.ACLBindingRules().Delete(createdBindingRule.ID, nil)
+	require.NoError(t, err)
+	assertWriteMeta(t, writeMeta)
+
+	writeMeta, err = testClient.ACLAuthMethods().Delete(authMethod.Name, nil)
+	require.NoError(t, err)
+	assertWriteMeta(t, writeMeta)
+
+	// Make sure there are no ACL auth methods now present.
+	aclAuthMethodsListResp, queryMeta, err = testClient.ACLAuthMethods().List(nil)
+	require.NoError(t, err)
+	require.Empty(t, aclAuthMethodsListResp)
+	assertQueryMeta(t, queryMeta)
+}
@@ -203,6 +203,8 @@ type AllocationMetric struct {
 	ClassExhausted     map[string]int
 	DimensionExhausted map[string]int
 	QuotaExhausted     []string
+	PreemptionRejected map[string]int
+	SpreadSkewExceeded map[string]int
 	ResourcesExhausted map[string]*Resources
 	// Deprecated, replaced with ScoreMetaData
 	Scores            map[string]float64
@@ -444,6 +446,11 @@ type DesiredTransition struct {
 	// Reschedule is used to indicate that this allocation is eligible to be
 	// rescheduled.
 	Reschedule *bool
+
+	// Checkpoint is used to indicate that the tasks of a migrated allocation
+	// should be checkpointed when stopped, so that they can be restored on
+	// the node of the replacement allocation.
+	Checkpoint *bool
 }
 
 // ShouldMigrate returns whether the transition object dictates a migration.
@@ -105,6 +105,7 @@ type RegisterOptions struct {
 	PolicyOverride bool
 	PreserveCounts bool
 	EvalPriority   int
+	Submission     *JobSubmission
 }
 
 // Register is used to register a new job. It returns the ID
@@ -134,6 +135,7 @@ func (j *Jobs) RegisterOpts(job *Job, opts *RegisterOptions, q *WriteOptions) (*
 		req.PolicyOverride = opts.PolicyOverride
 		req.PreserveCounts = opts.PreserveCounts
 		req.EvalPriority = opts.EvalPriority
+		req.Submission = opts.Submission
 	}
 
 	var resp JobRegisterResponse
@@ -220,6 +222,17 @@ func (j *Jobs) Versions(jobID string, diffs bool, q *QueryOptions) ([]*Job, []*J
 	return resp.Versions, resp.Diffs, qm, nil
 }
 
+// Submission is used to retrieve the source a version of a job was
+// submitted from.
+func (j *Jobs) Submission(jobID string, version int, q *QueryOptions) (*JobSubmission, *QueryMeta, error) {
+	var sub JobSubmission
+	qm, err := j.client.query(fmt.Sprintf("/v1/job/%s/submission?version=%d", url.PathEscape(jobID), version), &sub, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &sub, qm, nil
+}
+
 // Allocations is used to return the allocs for a given job ID.
 func (j *Jobs) Allocations(jobID string, allAllocs bool, q *QueryOptions) ([]*AllocationListStub, *QueryMeta, error) {
 	var resp []*AllocationListStub
@@ -840,8 +853,10 @@ type Job struct {
 	Name             *string                 `hcl:"name,optional"`
 	Type             *string                 `hcl:"type,optional"`
 	Priority         *int                    `hcl:"priority,optional"`
+	Scheduler        *string                 `hcl:"scheduler,optional"`
 	AllAtOnce        *bool                   `mapstructure:"all_at_once" hcl:"all_at_once,optional"`
 	Datacenters      []string                `hcl:"datacenters,optional"`
+	NodePool         *string                 `mapstructure:"node_pool" hcl:"node_pool,optional"`
 	Constraints      []*Constraint           `hcl:"constraint,block"`
 	Affinities       []*Affinity             `hcl:"affinity,block"`
 	TaskGroups       []*TaskGroup            `hcl:"group,block"`
@@ -1039,6 +1054,7 @@ type JobListStub struct {
 	Name              string
 	Namespace         string `json:",omitempty"`
 	Datacenters       []string
+	NodePool          string
 	Type              string
 	Priority          int
 	Periodic          bool
@@ -1231,9 +1247,44 @@ type JobRegisterRequest struct {
 	// change the job priority which also impacts preemption.
 	EvalPriority int `json:",omitempty"`
 
+	// Submission is the original jobspec source the job was parsed from.
+	Submission *JobSubmission `json:",omitempty"`
+
 	WriteRequest
 }
 
+const (
+	// JobSubmissionFormatHCL1, JobSubmissionFormatHCL2 and
+	// JobSubmissionFormatJSON are the formats of the source a job was
+	// submitted from.
+	JobSubmissionFormatHCL1 = "hcl1"
+	JobSubmissionFormatHCL2 = "hcl2"
+	JobSubmissionFormatJSON = "json"
+)
+
+// JobSubmission is the original source a version of a job was parsed from,
+// along with the HCL2 variables used to render it.
+type JobSubmission struct {
+	// Source is the jobspec as it was submitted.
+	Source string
+
+	// Format is the format of the source, one of hcl1, hcl2 or json.
+	Format string
+
+	// VariableFlags are the HCL2 variables set with the -var flag.
+	VariableFlags map[string]string `json:",omitempty"`
+
+	// Variables is the content of the HCL2 variable files.
+	Variables string `json:",omitempty"`
+
+	// Namespace, JobID and Version identify the job version the source was
+	// submitted for. They are set by the server.
+	Namespace      string `json:",omitempty"`
+	JobID          string `json:",omitempty"`
+	Version        uint64 `json:",omitempty"`
+	JobModifyIndex uint64 `json:",omitempty"`
+}
+
 // JobRegisterResponse is used to respond to a job registration
 type JobRegisterResponse struct {
 	EvalID          string
@@ -1317,6 +1368,16 @@ type ObjectDiff struct {
 type PlanAnnotations struct {
 	DesiredTGUpdates map[string]*DesiredUpdates
 	PreemptedAllocs  []*AllocationListStub
+	SpreadViolations []*SpreadViolation
+}
+
+// SpreadViolation describes a spread of a task group whose max skew would be
+// exceeded once the plan is applied.
+type SpreadViolation struct {
+	TaskGroup string
+	Attribute string
+	Skew      int
+	MaxSkew   int
 }
 
 type DesiredUpdates struct {
@@ -1487,6 +1487,37 @@ func TestJobs_Versions(t *testing.T) {
 	}
 }
 
+func TestJobs_Submission(t *testing.T) {
+	testutil.Parallel(t)
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+	jobs := c.Jobs()
+
+	// Register the job with its source
+	job := testJob()
+	sub := &JobSubmission{
+		Source:        `job "job1" {}`,
+		Format:        JobSubmissionFormatHCL2,
+		VariableFlags: map[string]string{"count": "1"},
+	}
+	_, wm, err := jobs.RegisterOpts(job, &RegisterOptions{Submission: sub}, nil)
+	require.NoError(t, err)
+	assertWriteMeta(t, wm)
+
+	// Query the submission of the first version
+	result, qm, err := jobs.Submission(*job.ID, 0, nil)
+	require.NoError(t, err)
+	assertQueryMeta(t, qm)
+	require.Equal(t, sub.Source, result.Source)
+	require.Equal(t, sub.Format, result.Format)
+	require.Equal(t, sub.VariableFlags, result.VariableFlags)
+
+	// Querying a version without a submission returns an error
+	_, _, err = jobs.Submission(*job.ID, 1, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not found")
+}
+
 func TestJobs_PrefixList(t *testing.T) {
 	testutil.Parallel(t)
 	c, s := makeClient(t, nil, nil)
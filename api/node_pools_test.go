@@ -0,0 +1,48 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api/internal/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodePools_Register(t *testing.T) {
+	testutil.Parallel(t)
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+	nodePools := c.NodePools()
+
+	// Create a node pool and register it
+	pool := &NodePool{
+		Name:        "gpu",
+		Description: "nodes with gpus",
+		SchedulerConfiguration: &NodePoolSchedulerConfiguration{
+			SchedulerAlgorithm: SchedulerAlgorithmSpread,
+		},
+	}
+	wm, err := nodePools.Register(pool, nil)
+	require.NoError(t, err)
+	assertWriteMeta(t, wm)
+
+	// Query the node pools back out again
+	resp, qm, err := nodePools.List(nil)
+	require.NoError(t, err)
+	assertQueryMeta(t, qm)
+	require.Len(t, resp, 3)
+
+	out, qm, err := nodePools.Info(pool.Name, nil)
+	require.NoError(t, err)
+	assertQueryMeta(t, qm)
+	require.Equal(t, pool.Description, out.Description)
+	require.Equal(t, SchedulerAlgorithmSpread, out.SchedulerConfiguration.SchedulerAlgorithm)
+
+	// Delete the node pool
+	wm, err = nodePools.Delete(pool.Name, nil)
+	require.NoError(t, err)
+	assertWriteMeta(t, wm)
+
+	resp, _, err = nodePools.PrefixList("gp", nil)
+	require.NoError(t, err)
+	require.Empty(t, resp)
+}
@@ -538,6 +538,7 @@ type DrainMetadata struct {
 type Node struct {
 	ID                    string
 	Datacenter            string
+	NodePool              string
 	Name                  string
 	HTTPAddr              string
 	TLSEnabled            bool
@@ -573,6 +574,7 @@ type NodeResources struct {
 	Disk     NodeDiskResources
 	Networks []*NetworkResource
 	Devices  []*NodeDeviceResource
+	NUMA     *NodeNUMAResources
 
 	MinDynamicPort int
 	MaxDynamicPort int
@@ -584,6 +586,21 @@ type NodeCpuResources struct {
 	ReservableCpuCores []uint16
 }
 
+// NodeNUMAResources is the NUMA topology of a node.
+type NodeNUMAResources struct {
+	Nodes []*NodeNUMANode
+}
+
+// NodeNUMANode describes a NUMA node: a set of cores together with the
+// memory local to them.
+type NodeNUMANode struct {
+	ID           uint16
+	Socket       uint16
+	Cores        []uint16
+	CoreSiblings [][]uint16
+	MemoryMB     int64
+}
+
 type NodeMemoryResources struct {
 	MemoryMB int64
 }
@@ -729,6 +746,10 @@ type DrainSpec struct {
 	// IgnoreSystemJobs allows systems jobs to remain on the node even though it
 	// has been marked for draining.
 	IgnoreSystemJobs bool
+
+	// Checkpoint prefers checkpointing the tasks of migrated allocations and
+	// restoring them on their new node over restarting them.
+	Checkpoint bool
 }
 
 func (d *DrainStrategy) Equal(o *DrainStrategy) bool {
@@ -745,6 +766,9 @@ func (d *DrainStrategy) Equal(o *DrainStrategy) bool {
 	if d.IgnoreSystemJobs != o.IgnoreSystemJobs {
 		return false
 	}
+	if d.Checkpoint != o.Checkpoint {
+		return false
+	}
 
 	return true
 }
@@ -908,6 +932,7 @@ type NodeListStub struct {
 	ID                    string
 	Attributes            map[string]string `json:",omitempty"`
 	Datacenter            string
+	NodePool              string
 	Name                  string
 	NodeClass             string
 	Version               string
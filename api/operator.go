@@ -130,6 +130,14 @@ type SchedulerConfiguration struct {
 	// MemoryOversubscriptionEnabled specifies whether memory oversubscription is enabled
 	MemoryOversubscriptionEnabled bool
 
+	// UtilizationScoringEnabled specifies whether nodes are also scored on
+	// the CPU and memory utilization reported by their clients
+	UtilizationScoringEnabled bool
+
+	// RebalanceConfig specifies whether and how the leader periodically
+	// migrates running allocations to rebalance the cluster.
+	RebalanceConfig RebalanceConfig
+
 	// RejectJobRegistration disables new job registrations except with a
 	// management ACL token
 	RejectJobRegistration bool
@@ -175,6 +183,44 @@ type PreemptionConfig struct {
 	ServiceSchedulerEnabled  bool
 }
 
+// RebalanceConfig specifies how running allocations are migrated to correct
+// skewed spreads, overly dense nodes and violated affinities.
+type RebalanceConfig struct {
+	Enabled          bool
+	MaxMigrations    int
+	DensityThreshold int
+}
+
+// SchedulerRebalanceRequest is used to rebalance the allocations of the
+// cluster.
+type SchedulerRebalanceRequest struct {
+	// DryRun only reports the migrations without applying them.
+	DryRun bool
+}
+
+// SchedulerRebalanceResponse is the response of a rebalancing request.
+type SchedulerRebalanceResponse struct {
+	// Migrations are the allocations selected for migration.
+	Migrations []*RebalanceMigration
+
+	// EvalIDs are the evaluations created to migrate the allocations. It is
+	// empty for dry runs.
+	EvalIDs []string
+
+	WriteMeta
+}
+
+// RebalanceMigration is an allocation selected for migration by the
+// rebalancer, along with the reason it was selected.
+type RebalanceMigration struct {
+	AllocID   string
+	Namespace string
+	JobID     string
+	TaskGroup string
+	NodeID    string
+	Reason    string
+}
+
 // SchedulerGetConfiguration is used to query the current Scheduler configuration.
 func (op *Operator) SchedulerGetConfiguration(q *QueryOptions) (*SchedulerConfigurationResponse, *QueryMeta, error) {
 	var resp SchedulerConfigurationResponse
@@ -208,6 +254,18 @@ func (op *Operator) SchedulerCASConfiguration(conf *SchedulerConfiguration, q *W
 	return &out, wm, nil
 }
 
+// SchedulerRebalance is used to migrate the allocations placed on skewed
+// spreads, overly dense nodes and nodes violating their affinities. Dry runs
+// only report the allocations which would be migrated.
+func (op *Operator) SchedulerRebalance(req *SchedulerRebalanceRequest, q *WriteOptions) (*SchedulerRebalanceResponse, *WriteMeta, error) {
+	var out SchedulerRebalanceResponse
+	wm, err := op.c.write("/v1/operator/scheduler/rebalance", req, &out, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &out, wm, nil
+}
+
 // Snapshot is used to capture a snapshot state of a running cluster.
 // The returned reader that must be consumed fully
 func (op *Operator) Snapshot(q *QueryOptions) (io.ReadCloser, error) {
@@ -14,6 +14,7 @@ type Resources struct {
 	DiskMB      *int               `mapstructure:"disk" hcl:"disk,optional"`
 	Networks    []*NetworkResource `hcl:"network,block"`
 	Devices     []*RequestedDevice `hcl:"device,block"`
+	NUMA        *NUMAResource      `hcl:"numa,block"`
 
 	// COMPAT(0.10)
 	// XXX Deprecated. Please do not use. The field will be removed in Nomad
@@ -47,6 +48,7 @@ func (r *Resources) Canonicalize() {
 	for _, d := range r.Devices {
 		d.Canonicalize()
 	}
+	r.NUMA.Canonicalize()
 }
 
 // DefaultResources is a small resources object that contains the
@@ -94,6 +96,31 @@ func (r *Resources) Merge(other *Resources) {
 	if len(other.Devices) != 0 {
 		r.Devices = other.Devices
 	}
+	if other.NUMA != nil {
+		r.NUMA = other.NUMA
+	}
+}
+
+const (
+	NUMAAffinityNone    = "none"
+	NUMAAffinityPrefer  = "prefer"
+	NUMAAffinityRequire = "require"
+)
+
+// NUMAResource is used to request that the reserved cores, memory and
+// devices of a task are placed on the same NUMA node.
+type NUMAResource struct {
+	// Affinity is one of "none", "prefer" or "require".
+	Affinity string `hcl:"affinity,optional"`
+}
+
+func (n *NUMAResource) Canonicalize() {
+	if n == nil {
+		return
+	}
+	if n.Affinity == "" {
+		n.Affinity = NUMAAffinityNone
+	}
 }
 
 type Port struct {
@@ -239,6 +266,9 @@ func (a Attribute) String() string {
 type NodeDeviceLocality struct {
 	// PciBusID is the PCI Bus ID for the device.
 	PciBusID string
+
+	// NUMANode is the NUMA node the device is attached to, if known.
+	NUMANode *uint16
 }
 
 // RequestedDevice is used to request a device for a task.
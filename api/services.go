@@ -56,10 +56,36 @@ type ServiceRegistration struct {
 	// is determined by a combination of factors on the client.
 	Port int
 
+	// Checks contains the most recent status of the health checks defined
+	// within the service block.
+	Checks []*ServiceRegistrationCheck
+
 	CreateIndex uint64
 	ModifyIndex uint64
 }
 
+// ServiceRegistrationCheck is the status of an individual health check of a
+// service registration.
+type ServiceRegistrationCheck struct {
+
+	// ID is the unique identifier of the check.
+	ID string
+
+	// Name is the name of the check as defined in the service block.
+	Name string
+
+	// Type indicates whether this is a http, tcp, or script check.
+	Type string
+
+	// Status is the current status of the check and is one of "passing",
+	// "warning", or "critical".
+	Status string
+
+	// Output is the output of the check execution that resulted in the
+	// current status.
+	Output string
+}
+
 // ServiceRegistrationListStub represents all service registrations held within a
 // single namespace.
 type ServiceRegistrationListStub struct {
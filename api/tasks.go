@@ -258,6 +258,7 @@ type Spread struct {
 	Attribute    string          `hcl:"attribute,optional"`
 	Weight       *int8           `hcl:"weight,optional"`
 	SpreadTarget []*SpreadTarget `hcl:"target,block"`
+	MaxSkew      *int            `mapstructure:"max_skew" hcl:"max_skew,optional"`
 }
 
 // SpreadTarget is used to serialize target allocation spread percentages
@@ -375,6 +376,25 @@ func (m *MigrateStrategy) Copy() *MigrateStrategy {
 	return nm
 }
 
+// PreemptionPolicy limits how the allocations of a task group may be
+// preempted by higher priority jobs.
+type PreemptionPolicy struct {
+	Preemptible   *bool `hcl:"preemptible,optional"`
+	MaxDisruption *int  `mapstructure:"max_disruption" hcl:"max_disruption,optional"`
+}
+
+func (p *PreemptionPolicy) Canonicalize() {
+	if p == nil {
+		return
+	}
+	if p.Preemptible == nil {
+		p.Preemptible = boolToPtr(true)
+	}
+	if p.MaxDisruption == nil {
+		p.MaxDisruption = intToPtr(0)
+	}
+}
+
 // VolumeRequest is a representation of a storage volume that a TaskGroup wishes to use.
 type VolumeRequest struct {
 	Name           string           `hcl:"name,label"`
@@ -432,6 +452,10 @@ type TaskGroup struct {
 	ShutdownDelay             *time.Duration            `mapstructure:"shutdown_delay" hcl:"shutdown_delay,optional"`
 	StopAfterClientDisconnect *time.Duration            `mapstructure:"stop_after_client_disconnect" hcl:"stop_after_client_disconnect,optional"`
 	MaxClientDisconnect       *time.Duration            `mapstructure:"max_client_disconnect" hcl:"max_client_disconnect,optional"`
+	Placement                 *string                   `hcl:"placement,optional"`
+	Preemption                *PreemptionPolicy         `hcl:"preemption,block"`
+	DependsOn                 []string                  `mapstructure:"depends_on" hcl:"depends_on,optional"`
+	DependsOnCondition        *string                   `mapstructure:"depends_on_condition" hcl:"depends_on_condition,optional"`
 	Scaling                   *ScalingPolicy            `hcl:"scaling,block"`
 	Consul                    *Consul                   `hcl:"consul,block"`
 }
@@ -523,6 +547,8 @@ func (g *TaskGroup) Canonicalize(job *Job) {
 		g.Migrate.Canonicalize()
 	}
 
+	g.Preemption.Canonicalize()
+
 	var defaultRestartPolicy *RestartPolicy
 	switch *job.Type {
 	case "service", "system":
@@ -615,16 +641,46 @@ func (g *TaskGroup) AddSpread(s *Spread) *TaskGroup {
 	return g
 }
 
-// LogConfig provides configuration for log rotation
+// LogConfig provides configuration for log rotation and the sinks the
+// output of a task is shipped to
 type LogConfig struct {
-	MaxFiles      *int `mapstructure:"max_files" hcl:"max_files,optional"`
-	MaxFileSizeMB *int `mapstructure:"max_file_size" hcl:"max_file_size,optional"`
+	MaxFiles       *int           `mapstructure:"max_files" hcl:"max_files,optional"`
+	MaxFileSizeMB  *int           `mapstructure:"max_file_size" hcl:"max_file_size,optional"`
+	MaxFileAge     *time.Duration `mapstructure:"max_file_age" hcl:"max_file_age,optional"`
+	Compress       *bool          `mapstructure:"compress" hcl:"compress,optional"`
+	MaxAllocSizeMB *int           `mapstructure:"max_alloc_size" hcl:"max_alloc_size,optional"`
+	Sinks          []*LogSink     `hcl:"sink,block"`
+}
+
+// LogSink is a destination the output of a task is shipped to
+type LogSink struct {
+	Type       string `hcl:"type,label"`
+	Address    string `mapstructure:"address" hcl:"address,optional"`
+	Tag        string `mapstructure:"tag" hcl:"tag,optional"`
+	Facility   string `mapstructure:"facility" hcl:"facility,optional"`
+	BufferSize *int   `mapstructure:"buffer_size" hcl:"buffer_size,optional"`
+	Overflow   string `mapstructure:"overflow" hcl:"overflow,optional"`
+}
+
+func (s *LogSink) Canonicalize() {
+	if s.Type == "file" {
+		return
+	}
+	if s.BufferSize == nil {
+		s.BufferSize = intToPtr(1000)
+	}
+	if s.Overflow == "" {
+		s.Overflow = "drop"
+	}
 }
 
 func DefaultLogConfig() *LogConfig {
 	return &LogConfig{
-		MaxFiles:      intToPtr(10),
-		MaxFileSizeMB: intToPtr(10),
+		MaxFiles:       intToPtr(10),
+		MaxFileSizeMB:  intToPtr(10),
+		MaxFileAge:     timeToPtr(0),
+		Compress:       boolToPtr(false),
+		MaxAllocSizeMB: intToPtr(0),
 	}
 }
 
@@ -635,6 +691,18 @@ func (l *LogConfig) Canonicalize() {
 	if l.MaxFileSizeMB == nil {
 		l.MaxFileSizeMB = intToPtr(10)
 	}
+	if l.MaxFileAge == nil {
+		l.MaxFileAge = timeToPtr(0)
+	}
+	if l.Compress == nil {
+		l.Compress = boolToPtr(false)
+	}
+	if l.MaxAllocSizeMB == nil {
+		l.MaxAllocSizeMB = intToPtr(0)
+	}
+	for _, sink := range l.Sinks {
+		sink.Canonicalize()
+	}
 }
 
 // DispatchPayloadConfig configures how a task gets its input from a job dispatch
@@ -680,6 +748,7 @@ type Task struct {
 	DispatchPayload *DispatchPayloadConfig `hcl:"dispatch_payload,block"`
 	VolumeMounts    []*VolumeMount         `hcl:"volume_mount,block"`
 	CSIPluginConfig *TaskCSIPluginConfig   `mapstructure:"csi_plugin" json:",omitempty" hcl:"csi_plugin,block"`
+	Identity        *WorkloadIdentity      `hcl:"identity,block"`
 	Leader          bool                   `hcl:"leader,optional"`
 	ShutdownDelay   time.Duration          `mapstructure:"shutdown_delay" hcl:"shutdown_delay,optional"`
 	KillSignal      string                 `mapstructure:"kill_signal" hcl:"kill_signal,optional"`
@@ -735,6 +804,13 @@ func (t *Task) Canonicalize(tg *TaskGroup, job *Job) {
 	}
 }
 
+// WorkloadIdentity is the jobspec block which determines how the signed
+// workload identity of a task is exposed to it.
+type WorkloadIdentity struct {
+	Env  bool `hcl:"env,optional"`
+	File bool `hcl:"file,optional"`
+}
+
 // TaskArtifact is used to download artifacts before running a task.
 type TaskArtifact struct {
 	GetterSource  *string           `mapstructure:"source" hcl:"source,optional"`
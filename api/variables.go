@@ -0,0 +1,140 @@
+package api
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrVariablePathRequired is returned when a variable operation is attempted
+// without a path.
+var ErrVariablePathRequired = errors.New("variable path is required")
+
+// Variable is a set of items stored at a path within a namespace. The items
+// are encrypted by the Nomad servers and are only returned in cleartext to
+// authorized callers.
+type Variable struct {
+
+	// Namespace is the namespace within which the variable is stored.
+	Namespace string
+
+	// Path is the unique identifier of the variable within its namespace.
+	Path string
+
+	// Items are the key/value pairs held by the variable.
+	Items VariableItems
+
+	CreateIndex uint64
+	CreateTime  int64
+	ModifyIndex uint64
+	ModifyTime  int64
+}
+
+// VariableItems are the key/value pairs held by a variable.
+type VariableItems map[string]string
+
+// VariableMetadata is the stub returned when listing variables. It never
+// contains the items of the variable.
+type VariableMetadata struct {
+	Namespace   string
+	Path        string
+	CreateIndex uint64
+	CreateTime  int64
+	ModifyIndex uint64
+	ModifyTime  int64
+}
+
+// NewVariable returns a new variable at the given path, with an empty set of
+// items.
+func NewVariable(path string) *Variable {
+	return &Variable{
+		Path:  path,
+		Items: make(VariableItems),
+	}
+}
+
+// Variables is used to query the variables endpoints.
+type Variables struct {
+	client *Client
+}
+
+// Variables returns a new handle on the variables endpoints.
+func (c *Client) Variables() *Variables {
+	return &Variables{client: c}
+}
+
+// List is used to list the metadata of all variables within the namespace
+// identified by the query options. The query options Prefix parameter can be
+// used to filter the variables by path prefix.
+func (v *Variables) List(q *QueryOptions) ([]*VariableMetadata, *QueryMeta, error) {
+	var resp []*VariableMetadata
+	qm, err := v.client.query("/v1/vars", &resp, q)
+	if err != nil {
+		return nil, qm, err
+	}
+	return resp, qm, nil
+}
+
+// PrefixList is used to list the metadata of all variables whose path begins
+// with the provided prefix.
+func (v *Variables) PrefixList(prefix string, q *QueryOptions) ([]*VariableMetadata, *QueryMeta, error) {
+	if q == nil {
+		q = &QueryOptions{}
+	}
+	q.Prefix = prefix
+	return v.List(q)
+}
+
+// Read is used to read a single variable, including its items.
+func (v *Variables) Read(path string, q *QueryOptions) (*Variable, *QueryMeta, error) {
+	path = cleanVariablePath(path)
+	if path == "" {
+		return nil, nil, ErrVariablePathRequired
+	}
+
+	var resp Variable
+	qm, err := v.client.query("/v1/var/"+path, &resp, q)
+	if err != nil {
+		return nil, qm, err
+	}
+	return &resp, qm, nil
+}
+
+// Put is used to create or update a variable. The variable returned contains
+// the indexes and times as stored by the servers.
+func (v *Variables) Put(variable *Variable, q *WriteOptions) (*Variable, *WriteMeta, error) {
+	if variable == nil {
+		return nil, nil, errors.New("missing variable")
+	}
+	path := cleanVariablePath(variable.Path)
+	if path == "" {
+		return nil, nil, ErrVariablePathRequired
+	}
+
+	var resp Variable
+	wm, err := v.client.write("/v1/var/"+path, variable, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// Delete is used to delete the variable at the provided path. Deleting a
+// variable that does not exist is not an error.
+func (v *Variables) Delete(path string, q *WriteOptions) (*WriteMeta, error) {
+	path = cleanVariablePath(path)
+	if path == "" {
+		return nil, ErrVariablePathRequired
+	}
+
+	wm, err := v.client.delete("/v1/var/"+path, nil, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// cleanVariablePath removes any leading slashes from the variable path, so
+// callers can use either "foo/bar" or "/foo/bar".
+func cleanVariablePath(path string) string {
+	return strings.TrimLeft(path, "/")
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api/internal/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVariables_CRUD(t *testing.T) {
+	testutil.Parallel(t)
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+	variables := c.Variables()
+
+	// Reading a variable that does not exist returns an error.
+	_, _, err := variables.Read("foo/bar", nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not found")
+
+	// Write a variable. The leader initializes the keyring asynchronously, so
+	// retry until the write succeeds.
+	v := NewVariable("foo/bar")
+	v.Items["username"] = "admin"
+	v.Items["password"] = "hunter2"
+
+	var out *Variable
+	testutil.WaitForResult(func() (bool, error) {
+		var wm *WriteMeta
+		out, wm, err = variables.Put(v, nil)
+		if err != nil {
+			return false, err
+		}
+		assertWriteMeta(t, wm)
+		return true, nil
+	}, func(err error) {
+		require.NoError(t, err)
+	})
+	require.Equal(t, "foo/bar", out.Path)
+	require.Equal(t, "default", out.Namespace)
+	require.NotZero(t, out.CreateIndex)
+	require.Equal(t, v.Items, out.Items)
+
+	// Read the variable back out.
+	read, qm, err := variables.Read("foo/bar", nil)
+	require.NoError(t, err)
+	assertQueryMeta(t, qm)
+	require.Equal(t, v.Items, read.Items)
+
+	// List the variables, including by prefix.
+	list, qm, err := variables.List(nil)
+	require.NoError(t, err)
+	assertQueryMeta(t, qm)
+	require.Len(t, list, 1)
+	require.Equal(t, "foo/bar", list[0].Path)
+
+	list, _, err = variables.PrefixList("baz", nil)
+	require.NoError(t, err)
+	require.Len(t, list, 0)
+
+	// Delete the variable and ensure it is gone.
+	wm, err := variables.Delete("foo/bar", nil)
+	require.NoError(t, err)
+	assertWriteMeta(t, wm)
+
+	_, _, err = variables.Read("foo/bar", nil)
+	require.Error(t, err)
+}
@@ -21,6 +21,11 @@ const (
 	// tokenCacheSize is the number of ACL tokens to keep cached. Tokens have a fetching cost,
 	// so we keep the hot tokens cached to reduce the lookups.
 	tokenCacheSize = 64
+
+	// roleCacheSize is the number of ACL roles to keep cached. Looking up
+	// roles requires an RPC call, so we keep the hot roles cached to reduce
+	// the number of lookups.
+	roleCacheSize = 64
 )
 
 // clientACLResolver holds the state required for client resolution
@@ -34,6 +39,9 @@ type clientACLResolver struct {
 
 	// tokenCache is used to maintain the fetched token objects
 	tokenCache *lru.TwoQueueCache
+
+	// roleCache is used to maintain the fetched ACL role objects
+	roleCache *lru.TwoQueueCache
 }
 
 // init is used to setup the client resolver state
@@ -52,13 +60,18 @@ func (c *clientACLResolver) init() error {
 	if err != nil {
 		return err
 	}
+	c.roleCache, err = lru.New2Q(roleCacheSize)
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
-// cachedACLValue is used to manage ACL Token or Policy TTLs
+// cachedACLValue is used to manage ACL Token, Policy, or Role TTLs
 type cachedACLValue struct {
 	Token     *structs.ACLToken
 	Policy    *structs.ACLPolicy
+	Role      *structs.ACLRole
 	CacheTime time.Time
 }
 
@@ -100,8 +113,15 @@ func (c *Client) resolveTokenAndACL(secretID string) (*acl.ACL, *structs.ACLToke
 		return acl.ManagementACL, token, nil
 	}
 
+	// Resolve the policies linked via the token's roles, adding them to
+	// those linked directly.
+	policyNames, err := c.resolveTokenPolicyNames(token)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// Resolve the policies
-	policies, err := c.resolvePolicies(token.SecretID, token.Policies)
+	policies, err := c.resolvePolicies(token.SecretID, policyNames)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -227,3 +247,106 @@ func (c *Client) resolvePolicies(secretID string, policies []string) ([]*structs
 	// Return the valid policies
 	return out, nil
 }
+
+// resolveTokenPolicyNames returns the names of the policies granted to the
+// token, both directly and via the ACL roles linked to the token.
+func (c *Client) resolveTokenPolicyNames(token *structs.ACLToken) ([]string, error) {
+	if len(token.Roles) == 0 {
+		return token.Policies, nil
+	}
+
+	roleIDs := make([]string, 0, len(token.Roles))
+	for _, roleLink := range token.Roles {
+		roleIDs = append(roleIDs, roleLink.ID)
+	}
+	roles, err := c.resolveRoles(token.SecretID, roleIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	policyNames := make([]string, 0, len(token.Policies))
+	seen := make(map[string]struct{}, len(token.Policies))
+	add := func(name string) {
+		if _, ok := seen[name]; ok {
+			return
+		}
+		seen[name] = struct{}{}
+		policyNames = append(policyNames, name)
+	}
+	for _, policyName := range token.Policies {
+		add(policyName)
+	}
+	for _, role := range roles {
+		for _, policyLink := range role.Policies {
+			add(policyLink.Name)
+		}
+	}
+	return policyNames, nil
+}
+
+// resolveRoles is used to translate a set of ACL role IDs into the objects.
+// Roles are cached and refreshed in the same way as policies, using the
+// policy TTL, and the cache TTL will be ignored if a server cannot be reached.
+func (c *Client) resolveRoles(secretID string, roleIDs []string) ([]*structs.ACLRole, error) {
+	var out []*structs.ACLRole
+	var expired []*structs.ACLRole
+	var missing []string
+
+	// Scan the cache for each role
+	for _, roleID := range roleIDs {
+		raw, ok := c.roleCache.Get(roleID)
+		if !ok {
+			missing = append(missing, roleID)
+			continue
+		}
+
+		// Check if the cached value is valid or expired
+		cached := raw.(*cachedACLValue)
+		if cached.Age() <= c.config.ACLPolicyTTL {
+			out = append(out, cached.Role)
+		} else {
+			expired = append(expired, cached.Role)
+		}
+	}
+
+	// Hot-path if we have no missing or expired roles
+	if len(missing)+len(expired) == 0 {
+		return out, nil
+	}
+
+	// Lookup the missing and expired roles
+	fetch := missing
+	for _, r := range expired {
+		fetch = append(fetch, r.ID)
+	}
+	req := structs.ACLRolesByIDRequest{
+		ACLRoleIDs: fetch,
+		QueryOptions: structs.QueryOptions{
+			Region:     c.Region(),
+			AuthToken:  secretID,
+			AllowStale: true,
+		},
+	}
+	var resp structs.ACLRolesByIDResponse
+	if err := c.RPC(structs.ACLGetRolesByIDRPCMethod, &req, &resp); err != nil {
+		// If we encounter an error but have cached roles, mask the error and extend the cache
+		if len(missing) == 0 {
+			c.logger.Warn("failed to resolve ACL roles, using expired cached value", "error", err)
+			out = append(out, expired...)
+			return out, nil
+		}
+		return nil, err
+	}
+
+	// Handle each output
+	for _, role := range resp.ACLRoles {
+		c.roleCache.Add(role.ID, &cachedACLValue{
+			Role:      role,
+			CacheTime: time.Now(),
+		})
+		out = append(out, role)
+	}
+
+	// Return the valid roles
+	return out, nil
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestClient_ACL_resolveTokenValue(t *testing.T) {
@@ -175,6 +176,56 @@ func TestClient_ACL_ResolveToken(t *testing.T) {
 	assert.Nil(t, out4)
 }
 
+func TestClient_ACL_ResolveToken_Roles(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, _, _, cleanupS1 := testACLServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	c1, cleanup := TestClient(t, func(c *config.Config) {
+		c.RPCHandler = s1
+		c.ACLEnabled = true
+	})
+	defer cleanup()
+
+	// Create the policies linked via the ACL role, and a token which is only
+	// linked to the role.
+	policy1 := mock.ACLPolicy()
+	policy1.Name = "mocked-test-policy-1"
+	policy2 := mock.ACLPolicy()
+	policy2.Name = "mocked-test-policy-2"
+	require.NoError(t, s1.State().UpsertACLPolicies(
+		structs.MsgTypeTestSetup, 100, []*structs.ACLPolicy{policy1, policy2}))
+
+	aclRole := mock.ACLRole()
+	require.NoError(t, s1.State().UpsertACLRoles(
+		structs.MsgTypeTestSetup, 110, []*structs.ACLRole{aclRole}, false))
+
+	token := mock.ACLToken()
+	token.Policies = nil
+	token.Roles = []*structs.ACLTokenRoleLink{{ID: aclRole.ID}}
+	require.NoError(t, s1.State().UpsertACLTokens(
+		structs.MsgTypeTestSetup, 120, []*structs.ACLToken{token}))
+
+	// Test the client resolution, which should include the permissions of
+	// the policies linked via the role.
+	out, err := c1.ResolveToken(token.SecretID)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.True(t, out.AllowNamespaceOperation("default", acl.NamespaceCapabilityListJobs))
+
+	// The role should now be cached.
+	cached, ok := c1.roleCache.Get(aclRole.ID)
+	require.True(t, ok)
+	require.Equal(t, aclRole.ID, cached.(*cachedACLValue).Role.ID)
+
+	// Test caching
+	out2, err := c1.ResolveToken(token.SecretID)
+	require.NoError(t, err)
+	require.Same(t, out, out2)
+}
+
 func TestClient_ACL_ResolveSecretToken(t *testing.T) {
 	ci.Parallel(t)
 
@@ -47,6 +47,11 @@ var (
 	// task.
 	TmpDirName = "tmp"
 
+	// CheckpointDirName is the name of the directory in each alloc holding
+	// the checkpoints of its tasks. It is not shared with tasks, as the
+	// checkpoints include their memory, but included in snapshots.
+	CheckpointDirName = "checkpoint"
+
 	// The set of directories that exist inside each shared alloc directory.
 	SharedAllocDirs = []string{LogDirName, TmpDirName, SharedDataDir}
 
@@ -68,6 +73,10 @@ var (
 	// AllocHTTPSocket is the path relative to the task dir root for the unix
 	// socket connected to Consul's HTTP endpoint.
 	AllocHTTPSocket = filepath.Join(SharedAllocName, TmpDirName, "consul_http.sock")
+
+	// AllocTaskAPISocket is the path relative to the task dir root for the
+	// unix socket serving the Nomad Task API.
+	AllocTaskAPISocket = filepath.Join(SharedAllocName, TmpDirName, "nomad_api.sock")
 )
 
 // AllocDir allows creating, destroying, and accessing an allocation's
@@ -84,6 +93,12 @@ type AllocDir struct {
 	// TaskDirs is a mapping of task names to their non-shared directory.
 	TaskDirs map[string]*TaskDir
 
+	// Rootless is true if the client runs as an unprivileged user. Chroots
+	// are then built without changing the owner of files or mounting the
+	// shared alloc dir, which drivers must mount in the task's namespace.
+	// It must be set before creating task dirs.
+	Rootless bool
+
 	// clientAllocDir is the client agent's root alloc directory. It must
 	// be excluded from chroots and is configured via client.alloc_dir.
 	clientAllocDir string
@@ -125,13 +140,13 @@ func (d *AllocDir) NewTaskDir(name string) *TaskDir {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
-	td := newTaskDir(d.logger, d.clientAllocDir, d.AllocDir, name)
+	td := newTaskDir(d.logger, d.clientAllocDir, d.AllocDir, name, d.Rootless)
 	d.TaskDirs[name] = td
 	return td
 }
 
 // Snapshot creates an archive of the files and directories in the data dir of
-// the allocation and the task local directories
+// the allocation, the task local directories and the task checkpoints
 //
 // Since a valid tar may have been written even when an error occurs, a special
 // file "NOMAD-${ALLOC_ID}-ERROR.log" will be appended to the tar with the
@@ -146,6 +161,12 @@ func (d *AllocDir) Snapshot(w io.Writer) error {
 		rootPaths = append(rootPaths, taskdir.LocalDir)
 	}
 
+	// Checkpoints are only written when tasks are checkpointed
+	checkpointDir := filepath.Join(d.AllocDir, CheckpointDirName)
+	if _, err := os.Stat(checkpointDir); err == nil {
+		rootPaths = append(rootPaths, checkpointDir)
+	}
+
 	tw := tar.NewWriter(w)
 	defer tw.Close()
 
@@ -215,7 +236,8 @@ func (d *AllocDir) Snapshot(w io.Writer) error {
 	return nil
 }
 
-// Move other alloc directory's shared path and local dir to this alloc dir.
+// Move other alloc directory's shared path, local dir and checkpoints to this
+// alloc dir.
 func (d *AllocDir) Move(other *AllocDir, tasks []*structs.Task) error {
 	d.mu.RLock()
 	if !d.built {
@@ -257,6 +279,15 @@ func (d *AllocDir) Move(other *AllocDir, tasks []*structs.Task) error {
 		}
 	}
 
+	// Move the task checkpoints
+	otherCheckpointDir := filepath.Join(other.AllocDir, CheckpointDirName)
+	if fileInfo, err := os.Stat(otherCheckpointDir); fileInfo != nil && err == nil {
+		checkpointDir := filepath.Join(d.AllocDir, CheckpointDirName)
+		if err := os.Rename(otherCheckpointDir, checkpointDir); err != nil {
+			return fmt.Errorf("error moving checkpoint dir: %v", err)
+		}
+	}
+
 	return nil
 }
 
@@ -286,8 +317,9 @@ func (d *AllocDir) UnmountAll() error {
 
 	var mErr multierror.Error
 	for _, dir := range d.TaskDirs {
-		// Check if the directory has the shared alloc mounted.
-		if pathExists(dir.SharedTaskDir) {
+		// Check if the directory has the shared alloc mounted. Rootless task
+		// dirs only have it mounted in the namespace of the task.
+		if pathExists(dir.SharedTaskDir) && !d.Rootless {
 			if err := unlinkDir(dir.SharedTaskDir); err != nil {
 				mErr.Errors = append(mErr.Errors,
 					fmt.Errorf("failed to unmount shared alloc dir %q: %v", dir.SharedTaskDir, err))
@@ -305,6 +337,9 @@ func (d *AllocDir) UnmountAll() error {
 		}
 
 		// Unmount dev/ and proc/ have been mounted.
+		if d.Rootless {
+			continue
+		}
 		if err := dir.unmountSpecialDirs(); err != nil {
 			mErr.Errors = append(mErr.Errors, err)
 		}
@@ -512,7 +547,7 @@ func fileCopy(src, dst string, uid, gid int, perm os.FileMode) error {
 	// Do a simple copy.
 	srcFile, err := os.Open(src)
 	if err != nil {
-		return fmt.Errorf("Couldn't open src file %v: %v", src, err)
+		return fmt.Errorf("Couldn't open src file %v: %w", src, err)
 	}
 	defer srcFile.Close()
 
@@ -562,8 +597,9 @@ func pathEmpty(path string) (bool, error) {
 
 // createDir creates a directory structure inside the basepath. This functions
 // preserves the permissions of each of the subdirectories in the relative path
-// by looking up the permissions in the host.
-func createDir(basePath, relPath string) error {
+// by looking up the permissions in the host. Rootless directories are owned by
+// the user running the client, who must be able to write to them.
+func createDir(basePath, relPath string, rootless bool) error {
 	filePerms, err := splitPath(relPath)
 	if err != nil {
 		return err
@@ -574,6 +610,13 @@ func createDir(basePath, relPath string) error {
 	for i := len(filePerms) - 1; i >= 0; i-- {
 		fi := filePerms[i]
 		destDir := filepath.Join(basePath, fi.Name)
+		if rootless {
+			if err := os.MkdirAll(destDir, fi.Perm|0700); err != nil {
+				return err
+			}
+			continue
+		}
+
 		if err := os.MkdirAll(destDir, fi.Perm); err != nil {
 			return err
 		}
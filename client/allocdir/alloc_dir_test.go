@@ -185,6 +185,14 @@ func TestAllocDir_Snapshot(t *testing.T) {
 		t.Fatalf("couldn't write symlink to task local directory :%v", err)
 	}
 
+	// Write a file to the task checkpoint
+	if err := os.MkdirAll(td1.CheckpointDir, 0700); err != nil {
+		t.Fatalf("couldn't create task checkpoint directory: %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(td1.CheckpointDir, "pages-1.img"), exp, 0600); err != nil {
+		t.Fatalf("couldn't write file to task checkpoint directory: %v", err)
+	}
+
 	var b bytes.Buffer
 	if err := d.Snapshot(&b); err != nil {
 		t.Fatalf("err: %v", err)
@@ -208,7 +216,7 @@ func TestAllocDir_Snapshot(t *testing.T) {
 		}
 	}
 
-	if len(files) != 2 {
+	if len(files) != 3 {
 		t.Fatalf("bad files: %#v", files)
 	}
 	if len(links) != 2 {
@@ -260,6 +268,15 @@ func TestAllocDir_Move(t *testing.T) {
 		t.Fatalf("couldn't write to task local directory: %v", err)
 	}
 
+	// Write a file to the task checkpoint
+	if err := os.MkdirAll(td1.CheckpointDir, 0700); err != nil {
+		t.Fatalf("couldn't create task checkpoint directory: %v", err)
+	}
+	file3 := "pages-1.img"
+	if err := ioutil.WriteFile(filepath.Join(td1.CheckpointDir, file3), exp2, 0600); err != nil {
+		t.Fatalf("couldn't write to task checkpoint directory: %v", err)
+	}
+
 	// Move the d1 allocdir to d2
 	if err := d2.Move(d1, []*structs.Task{t1}); err != nil {
 		t.Fatalf("err: %v", err)
@@ -275,6 +292,11 @@ func TestAllocDir_Move(t *testing.T) {
 	if err != nil || fi == nil {
 		t.Fatalf("task local dir was not moved")
 	}
+
+	fi, err = os.Stat(filepath.Join(d2.TaskDirs[t1.Name].CheckpointDir, file3))
+	if err != nil || fi == nil {
+		t.Fatalf("task checkpoint dir was not moved")
+	}
 }
 
 func TestAllocDir_EscapeChecking(t *testing.T) {
@@ -386,7 +408,7 @@ func TestAllocDir_CreateDir(t *testing.T) {
 	// Create the above hierarchy under another destination
 	dir1 := t.TempDir()
 
-	if err := createDir(dir1, subdir); err != nil {
+	if err := createDir(dir1, subdir, false); err != nil {
 		t.Fatalf("err: %v", err)
 	}
 
@@ -1,6 +1,7 @@
 package allocdir
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -39,10 +40,19 @@ type TaskDir struct {
 	// <task_dir>/secrets/
 	SecretsDir string
 
+	// CheckpointDir is the path to the task's checkpoint directory on the
+	// host. It is only created when the task is checkpointed.
+	// <alloc_dir>/checkpoint/<task_name>/
+	CheckpointDir string
+
 	// skip embedding these paths in chroots. Used for avoiding embedding
 	// client.alloc_dir recursively.
 	skip map[string]struct{}
 
+	// rootless task dirs are built by an unprivileged client, which can
+	// neither change the owner of files nor mount directories
+	rootless bool
+
 	logger hclog.Logger
 }
 
@@ -50,7 +60,7 @@ type TaskDir struct {
 // create paths on disk.
 //
 // Call AllocDir.NewTaskDir to create new TaskDirs
-func newTaskDir(logger hclog.Logger, clientAllocDir, allocDir, taskName string) *TaskDir {
+func newTaskDir(logger hclog.Logger, clientAllocDir, allocDir, taskName string, rootless bool) *TaskDir {
 	taskDir := filepath.Join(allocDir, taskName)
 
 	logger = logger.Named("task_dir").With("task_name", taskName)
@@ -66,7 +76,9 @@ func newTaskDir(logger hclog.Logger, clientAllocDir, allocDir, taskName string)
 		SharedTaskDir:  filepath.Join(taskDir, SharedAllocName),
 		LocalDir:       filepath.Join(taskDir, TaskLocal),
 		SecretsDir:     filepath.Join(taskDir, TaskSecrets),
+		CheckpointDir:  filepath.Join(allocDir, CheckpointDirName, taskName),
 		skip:           skip,
+		rootless:       rootless,
 		logger:         logger,
 	}
 }
@@ -108,8 +120,9 @@ func (t *TaskDir) Build(createChroot bool, chroot map[string]string) error {
 	// Only link alloc dir into task dir for chroot fs isolation.
 	// Image based isolation will bind the shared alloc dir in the driver.
 	// If there's no isolation the task will use the host path to the
-	// shared alloc dir.
-	if createChroot {
+	// shared alloc dir. Rootless clients cannot mount, so the driver binds
+	// the shared alloc dir in the task's namespace instead.
+	if createChroot && !t.rootless {
 		// If the path doesn't exist OR it exists and is empty, link it
 		empty, _ := pathEmpty(t.SharedTaskDir)
 		if !pathExists(t.SharedTaskDir) || empty {
@@ -141,7 +154,8 @@ func (t *TaskDir) Build(createChroot bool, chroot map[string]string) error {
 // buildChroot takes a mapping of absolute directory or file paths on the host
 // to their intended, relative location within the task directory. This
 // attempts hardlink and then defaults to copying. If the path exists on the
-// host and can't be embedded an error is returned.
+// host and can't be embedded an error is returned, unless the task dir is
+// rootless and the path isn't readable by the client.
 func (t *TaskDir) buildChroot(entries map[string]string) error {
 	return t.embedDirs(entries)
 }
@@ -162,14 +176,17 @@ func (t *TaskDir) embedDirs(entries map[string]string) error {
 
 		// Embedding a single file
 		if !s.IsDir() {
-			if err := createDir(t.Dir, filepath.Dir(dest)); err != nil {
+			if err := createDir(t.Dir, filepath.Dir(dest), t.rootless); err != nil {
 				return fmt.Errorf("Couldn't create destination directory %v: %v", dest, err)
 			}
 
 			// Copy the file.
 			taskEntry := filepath.Join(t.Dir, dest)
-			uid, gid := getOwner(s)
+			uid, gid := t.getOwner(s)
 			if err := linkOrCopy(source, taskEntry, uid, gid, s.Mode().Perm()); err != nil {
+				if t.skipUnreadable(source, err) {
+					continue
+				}
 				return err
 			}
 
@@ -179,13 +196,16 @@ func (t *TaskDir) embedDirs(entries map[string]string) error {
 		// Create destination directory.
 		destDir := filepath.Join(t.Dir, dest)
 
-		if err := createDir(t.Dir, dest); err != nil {
+		if err := createDir(t.Dir, dest, t.rootless); err != nil {
 			return fmt.Errorf("Couldn't create destination directory %v: %v", destDir, err)
 		}
 
 		// Enumerate the files in source.
 		dirEntries, err := ioutil.ReadDir(source)
 		if err != nil {
+			if t.skipUnreadable(source, err) {
+				continue
+			}
 			return fmt.Errorf("Couldn't read directory %v: %v", source, err)
 		}
 
@@ -223,8 +243,11 @@ func (t *TaskDir) embedDirs(entries map[string]string) error {
 				continue
 			}
 
-			uid, gid := getOwner(entry)
+			uid, gid := t.getOwner(entry)
 			if err := linkOrCopy(hostEntry, taskEntry, uid, gid, entry.Mode().Perm()); err != nil {
+				if t.skipUnreadable(hostEntry, err) {
+					continue
+				}
 				return err
 			}
 		}
@@ -237,3 +260,23 @@ func (t *TaskDir) embedDirs(entries map[string]string) error {
 
 	return nil
 }
+
+// getOwner returns the owner of files embedded in the chroot, which is the
+// owner on the host unless the task dir is rootless.
+func (t *TaskDir) getOwner(fi os.FileInfo) (int, int) {
+	if t.rootless {
+		return idUnsupported, idUnsupported
+	}
+	return getOwner(fi)
+}
+
+// skipUnreadable returns true if the error embedding path is due to the path
+// not being readable by a rootless client, in which case it is left out of
+// the chroot.
+func (t *TaskDir) skipUnreadable(path string, err error) bool {
+	if !t.rootless || !errors.Is(err, os.ErrPermission) {
+		return false
+	}
+	t.logger.Trace("skipping unreadable path in chroot", "path", path)
+	return true
+}
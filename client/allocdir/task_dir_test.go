@@ -123,3 +123,56 @@ func TestTaskDir_NonRoot(t *testing.T) {
 		t.Fatalf("Expected a NotExist error for shared alloc dir in task dir: %q", td.SharedTaskDir)
 	}
 }
+
+// Test that rootless task dirs with chroot isolation don't require root and
+// skip host files the client can't read.
+func TestTaskDir_Rootless_Chroot(t *testing.T) {
+	ci.Parallel(t)
+	if os.Geteuid() == 0 {
+		t.Skip("test should be run as non-root user")
+	}
+
+	tmp := t.TempDir()
+
+	d := NewAllocDir(testlog.HCLogger(t), tmp, "test")
+	d.Rootless = true
+	defer d.Destroy()
+	td := d.NewTaskDir(t1.Name)
+	if err := d.Build(); err != nil {
+		t.Fatalf("Build() failed: %v", err)
+	}
+
+	// Create a fake host directory with a readable file, an unreadable file
+	// and an unreadable subfolder.
+	host := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(host, "readable"), []byte{'a'}, 0644); err != nil {
+		t.Fatalf("Couldn't create file in host dir %v: %v", host, err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(host, "unreadable"), []byte{'a'}, 0000); err != nil {
+		t.Fatalf("Couldn't create file in host dir %v: %v", host, err)
+	}
+	if err := os.Mkdir(filepath.Join(host, "private"), 0000); err != nil {
+		t.Fatalf("Couldn't create subdir in host dir %v: %v", host, err)
+	}
+	defer os.Chmod(filepath.Join(host, "private"), 0700)
+
+	if err := td.Build(true, map[string]string{host: "bin"}); err != nil {
+		t.Fatalf("TaskDir.Build failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(td.Dir, "bin", "readable")); err != nil {
+		t.Fatalf("File readable not embedded: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(td.Dir, "bin", "unreadable")); !os.IsNotExist(err) {
+		t.Fatalf("Expected unreadable file to be skipped: %v", err)
+	}
+
+	// ${TASK_DIR}/alloc is mounted by the driver in the task's namespace
+	if _, err := os.Stat(td.SharedTaskDir); !os.IsNotExist(err) {
+		t.Fatalf("Expected a NotExist error for shared alloc dir in task dir: %q", td.SharedTaskDir)
+	}
+
+	if err := d.UnmountAll(); err != nil {
+		t.Fatalf("UnmountAll failed: %v", err)
+	}
+}
@@ -240,6 +240,7 @@ func NewAllocRunner(config *Config) (*allocRunner, error) {
 
 	// Create alloc dir
 	ar.allocDir = allocdir.NewAllocDir(ar.logger, config.ClientConfig.AllocDir, alloc.ID)
+	ar.allocDir.Rootless = config.ClientConfig.Rootless
 
 	ar.taskHookCoordinator = newTaskHookCoordinator(ar.logger, tg.Tasks)
 
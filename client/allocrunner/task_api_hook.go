@@ -0,0 +1,228 @@
+package allocrunner
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-msgpack/codec"
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/helper/bufconndialer"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	taskAPIHookName = "task_api"
+)
+
+// taskAPIHook serves a restricted subset of the Nomad HTTP API on a Unix
+// socket within the allocation directory. Requests must be authenticated
+// with the workload identity of one of the tasks of the allocation, and are
+// limited to:
+//
+//   - reading the allocation itself, which is served by the client
+//   - reading Nomad native services, proxied using the node's secret ID
+//   - reading variables, proxied using the workload identity, so the servers
+//     enforce the implicit workload identity policy
+//
+// Noop if the agent does not provide a dialer for its HTTP API.
+type taskAPIHook struct {
+	logger       hclog.Logger
+	allocDir     *allocdir.AllocDir
+	dialer       *bufconndialer.BufConnWrapper
+	nodeSecretID string
+
+	// lock synchronizes alloc and server which may be mutated and read
+	// concurrently via Prerun, Update, Postrun, and request handling.
+	lock   sync.RWMutex
+	alloc  *structs.Allocation
+	server *http.Server
+	proxy  *httputil.ReverseProxy
+}
+
+func newTaskAPIHook(logger hclog.Logger, alloc *structs.Allocation, allocDir *allocdir.AllocDir,
+	dialer *bufconndialer.BufConnWrapper, nodeSecretID string) *taskAPIHook {
+
+	h := &taskAPIHook{
+		logger:       logger.Named(taskAPIHookName),
+		alloc:        alloc,
+		allocDir:     allocDir,
+		dialer:       dialer,
+		nodeSecretID: nodeSecretID,
+	}
+
+	if dialer != nil {
+		h.proxy = &httputil.ReverseProxy{
+			// The Director only needs to point the request at the agent,
+			// as the headers are set by the handler before proxying.
+			Director: func(req *http.Request) {
+				req.URL.Scheme = "http"
+				req.URL.Host = "127.0.0.1"
+			},
+			Transport: &http.Transport{DialContext: dialer.DialContext},
+		}
+	}
+	return h
+}
+
+func (*taskAPIHook) Name() string {
+	return taskAPIHookName
+}
+
+func (h *taskAPIHook) Prerun() error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.dialer == nil || h.server != nil {
+		return nil
+	}
+
+	hostSockPath := filepath.Join(h.allocDir.AllocDir, allocdir.AllocTaskAPISocket)
+	if err := maybeRemoveOldSocket(hostSockPath); err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("unix", hostSockPath)
+	if err != nil {
+		return fmt.Errorf("unable to create unix socket for Task API: %w", err)
+	}
+
+	// The socket should be usable by all users in case a task is running as
+	// a non-privileged user. Unix does not allow setting domain socket
+	// permissions when creating the file, so we must manually call chmod
+	// afterwards.
+	if err := os.Chmod(hostSockPath, os.ModePerm); err != nil {
+		listener.Close()
+		return fmt.Errorf("unable to set permissions on unix socket: %w", err)
+	}
+
+	h.server = &http.Server{Handler: h}
+	go func(server *http.Server) {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			h.logger.Warn("error serving Task API", "error", err)
+		}
+	}(h.server)
+
+	return nil
+}
+
+func (h *taskAPIHook) Update(req *interfaces.RunnerUpdateRequest) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.alloc = req.Alloc
+	return nil
+}
+
+func (h *taskAPIHook) Postrun() error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.server == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), socketProxyStopWaitTime)
+	defer cancel()
+
+	if err := h.server.Shutdown(ctx); err != nil {
+		// Only log a failure to stop, worst case is the server leaks a
+		// goroutine.
+		h.logger.Warn("error stopping Task API", "error", err)
+	}
+	h.server = nil
+	return nil
+}
+
+// ServeHTTP authenticates and routes requests made to the Task API.
+func (h *taskAPIHook) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	h.lock.RLock()
+	alloc := h.alloc
+	h.lock.RUnlock()
+
+	token := parseTaskAPIToken(req)
+	if !isAllocIdentity(alloc, token) {
+		http.Error(resp, structs.ErrPermissionDenied.Error(), http.StatusForbidden)
+		return
+	}
+
+	if req.Method != http.MethodGet {
+		http.Error(resp, "Invalid method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	path := req.URL.Path
+	switch {
+	case path == "/v1/allocation/"+alloc.ID:
+		h.serveAlloc(resp, alloc)
+	case path == "/v1/services" || strings.HasPrefix(path, "/v1/service/"):
+		h.serveProxied(resp, req, alloc, h.nodeSecretID)
+	case path == "/v1/vars" || strings.HasPrefix(path, "/v1/var/"):
+		h.serveProxied(resp, req, alloc, token)
+	default:
+		http.Error(resp, "Not found", http.StatusNotFound)
+	}
+}
+
+// serveAlloc writes the allocation as JSON, in the same form as the
+// /v1/allocation/:id HTTP API.
+func (h *taskAPIHook) serveAlloc(resp http.ResponseWriter, alloc *structs.Allocation) {
+	resp.Header().Set("Content-Type", "application/json")
+	if err := codec.NewEncoder(resp, structs.JsonHandleWithExtensions).Encode(alloc); err != nil {
+		h.logger.Warn("failed to encode allocation", "error", err)
+	}
+}
+
+// serveProxied proxies the request to the agent's HTTP API, using the given
+// token and restricting the request to the namespace of the allocation.
+func (h *taskAPIHook) serveProxied(resp http.ResponseWriter, req *http.Request,
+	alloc *structs.Allocation, token string) {
+
+	outReq := req.Clone(req.Context())
+	outReq.Header.Del("Authorization")
+	outReq.Header.Set("X-Nomad-Token", token)
+
+	query := outReq.URL.Query()
+	query.Set("namespace", alloc.Namespace)
+	outReq.URL.RawQuery = query.Encode()
+
+	h.proxy.ServeHTTP(resp, outReq)
+}
+
+// parseTaskAPIToken returns the token of the request, which is read from
+// either the X-Nomad-Token header or a bearer Authorization header.
+func parseTaskAPIToken(req *http.Request) string {
+	if token := req.Header.Get("X-Nomad-Token"); token != "" {
+		return token
+	}
+
+	scheme, token, ok := strings.Cut(req.Header.Get("Authorization"), " ")
+	if ok && strings.EqualFold(scheme, "bearer") {
+		return strings.TrimSpace(token)
+	}
+	return ""
+}
+
+// isAllocIdentity returns whether the token is the signed workload identity
+// of one of the tasks of the allocation.
+func isAllocIdentity(alloc *structs.Allocation, token string) bool {
+	if token == "" {
+		return false
+	}
+	for _, identity := range alloc.SignedIdentities {
+		if subtle.ConstantTimeCompare([]byte(identity), []byte(token)) == 1 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+package allocrunner
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/helper/bufconndialer"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// taskAPIRequest performs a GET request against the Task API socket using
+// the given token.
+func taskAPIRequest(t *testing.T, sockPath, path, token string) (int, []byte) {
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", sockPath)
+			},
+		},
+	}
+
+	req, err := http.NewRequest(http.MethodGet, "http://localhost"+path, nil)
+	require.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, body
+}
+
+func TestTaskAPIHook_PrerunPostrun(t *testing.T) {
+	ci.Parallel(t)
+
+	logger := testlog.HCLogger(t)
+	alloc := mock.Alloc()
+	alloc.SignedIdentities = map[string]string{"web": "workload.identity.token"}
+
+	// fake agent HTTP API which records the proxied requests
+	listener, dialer := bufconndialer.New()
+	defer listener.Close()
+
+	proxied := make(chan *http.Request, 1)
+	go http.Serve(listener, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied <- r
+		w.Write([]byte("[]"))
+	}))
+
+	allocDir, cleanupDir := allocdir.TestAllocDir(t, logger, "TaskAPI", alloc.ID)
+	defer cleanupDir()
+
+	h := newTaskAPIHook(logger, alloc, allocDir, dialer, "node-secret")
+	require.NoError(t, h.Prerun())
+
+	sockPath := filepath.Join(allocDir.AllocDir, allocdir.AllocTaskAPISocket)
+
+	// requests without a workload identity of the alloc are rejected
+	code, _ := taskAPIRequest(t, sockPath, "/v1/allocation/"+alloc.ID, "")
+	require.Equal(t, http.StatusForbidden, code)
+	code, _ = taskAPIRequest(t, sockPath, "/v1/allocation/"+alloc.ID, "bogus")
+	require.Equal(t, http.StatusForbidden, code)
+
+	// the alloc is served locally
+	code, body := taskAPIRequest(t, sockPath, "/v1/allocation/"+alloc.ID, "workload.identity.token")
+	require.Equal(t, http.StatusOK, code)
+	var out map[string]interface{}
+	require.NoError(t, json.Unmarshal(body, &out))
+	require.Equal(t, alloc.ID, out["ID"])
+	require.NotContains(t, out, "SignedIdentities")
+
+	// other allocs and endpoints are not exposed
+	code, _ = taskAPIRequest(t, sockPath, "/v1/allocation/"+alloc.NodeID, "workload.identity.token")
+	require.Equal(t, http.StatusNotFound, code)
+	code, _ = taskAPIRequest(t, sockPath, "/v1/jobs", "workload.identity.token")
+	require.Equal(t, http.StatusNotFound, code)
+
+	// services are proxied with the node secret ID in the alloc namespace
+	code, _ = taskAPIRequest(t, sockPath, "/v1/services?namespace=other", "workload.identity.token")
+	require.Equal(t, http.StatusOK, code)
+	req := <-proxied
+	require.Equal(t, "/v1/services", req.URL.Path)
+	require.Equal(t, "node-secret", req.Header.Get("X-Nomad-Token"))
+	require.Empty(t, req.Header.Get("Authorization"))
+	require.Equal(t, alloc.Namespace, req.URL.Query().Get("namespace"))
+
+	// variables are proxied with the workload identity
+	code, _ = taskAPIRequest(t, sockPath, "/v1/var/nomad/jobs/web", "workload.identity.token")
+	require.Equal(t, http.StatusOK, code)
+	req = <-proxied
+	require.Equal(t, "/v1/var/nomad/jobs/web", req.URL.Path)
+	require.Equal(t, "workload.identity.token", req.Header.Get("X-Nomad-Token"))
+
+	// updated allocs replace the accepted identities
+	updated := alloc.Copy()
+	updated.SignedIdentities = map[string]string{"web": "new.identity.token"}
+	require.NoError(t, h.Update(&interfaces.RunnerUpdateRequest{Alloc: updated}))
+	code, _ = taskAPIRequest(t, sockPath, "/v1/allocation/"+alloc.ID, "workload.identity.token")
+	require.Equal(t, http.StatusForbidden, code)
+
+	require.NoError(t, h.Postrun())
+
+	// the socket no longer accepts connections
+	_, err := net.Dial("unix", sockPath)
+	require.Error(t, err)
+}
+
+func TestTaskAPIHook_NoDialer(t *testing.T) {
+	ci.Parallel(t)
+
+	logger := testlog.HCLogger(t)
+	alloc := mock.Alloc()
+
+	allocDir, cleanupDir := allocdir.TestAllocDir(t, logger, "TaskAPI", alloc.ID)
+	defer cleanupDir()
+
+	h := newTaskAPIHook(logger, alloc, allocDir, nil, "node-secret")
+	require.NoError(t, h.Prerun())
+	require.NoFileExists(t, filepath.Join(allocDir.AllocDir, allocdir.AllocTaskAPISocket))
+	require.NoError(t, h.Postrun())
+}
@@ -48,6 +48,11 @@ func (h *DriverHandle) Kill() error {
 	return h.driver.StopTask(h.taskID, h.killTimeout, h.killSignal)
 }
 
+// Checkpoint checkpoints the task into dir, which stops it.
+func (h *DriverHandle) Checkpoint(dir string) error {
+	return h.driver.CheckpointTask(h.taskID, dir)
+}
+
 func (h *DriverHandle) Stats(ctx context.Context, interval time.Duration) (<-chan *cstructs.TaskResourceUsage, error) {
 	return h.driver.TaskStats(ctx, h.taskID, interval)
 }
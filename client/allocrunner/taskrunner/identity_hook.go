@@ -0,0 +1,100 @@
+package taskrunner
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/taskenv"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// identityHook exposes the signed workload identity of the task to it, via
+// its environment and secrets directory as configured by the task's identity
+// block.
+type identityHook struct {
+	envBuilder *taskenv.Builder
+	taskName   string
+	identity   *structs.WorkloadIdentity
+	logger     log.Logger
+
+	// token is the signed workload identity of the task, which is updated
+	// when the allocation is.
+	token string
+
+	// tokenPath is the path in which to write the token, and is only set
+	// once the task directory has been built.
+	tokenPath string
+	lock      sync.Mutex
+}
+
+func newIdentityHook(alloc *structs.Allocation, task *structs.Task,
+	envBuilder *taskenv.Builder, logger log.Logger) *identityHook {
+
+	identity := task.Identity
+	if identity == nil {
+		identity = structs.DefaultWorkloadIdentity()
+	}
+
+	h := &identityHook{
+		envBuilder: envBuilder,
+		taskName:   task.Name,
+		identity:   identity,
+		token:      alloc.SignedIdentities[task.Name],
+	}
+	h.logger = logger.Named(h.Name())
+	return h
+}
+
+func (*identityHook) Name() string {
+	return "identity"
+}
+
+func (h *identityHook) Prestart(ctx context.Context, req *interfaces.TaskPrestartRequest, resp *interfaces.TaskPrestartResponse) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.tokenPath = filepath.Join(req.TaskDir.SecretsDir, structs.WorkloadIdentityFile)
+	return h.setToken()
+}
+
+func (h *identityHook) Update(_ context.Context, req *interfaces.TaskUpdateRequest, _ *interfaces.TaskUpdateResponse) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	token := req.Alloc.SignedIdentities[h.taskName]
+	if token == "" || token == h.token {
+		return nil
+	}
+	h.token = token
+
+	// If the task has not been started yet, the token will be set when it
+	// is.
+	if h.tokenPath == "" {
+		return nil
+	}
+	return h.setToken()
+}
+
+// setToken exposes the signed workload identity to the task. Allocations
+// created before workload identities were introduced have no signed
+// identities, in which case nothing is exposed.
+func (h *identityHook) setToken() error {
+	if h.token == "" {
+		return nil
+	}
+
+	h.envBuilder.SetWorkloadToken(h.token, h.identity.Env)
+
+	if h.identity.File {
+		if err := ioutil.WriteFile(h.tokenPath, []byte(h.token), 0666); err != nil {
+			return fmt.Errorf("failed to write workload identity: %v", err)
+		}
+		h.logger.Trace("workload identity written", "path", h.tokenPath)
+	}
+	return nil
+}
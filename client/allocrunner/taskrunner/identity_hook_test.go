@@ -0,0 +1,130 @@
+package taskrunner
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/taskenv"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// Statically assert the identity hook implements the expected interfaces
+var _ interfaces.TaskPrestartHook = (*identityHook)(nil)
+var _ interfaces.TaskUpdateHook = (*identityHook)(nil)
+
+func TestIdentityHook_Prestart(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name        string
+		identity    *structs.WorkloadIdentity
+		expectEnv   bool
+		expectFile  bool
+		signedToken string
+	}{
+		{
+			name:        "default",
+			expectFile:  true,
+			signedToken: "foo.bar.baz",
+		},
+		{
+			name:        "env and file",
+			identity:    &structs.WorkloadIdentity{Env: true, File: true},
+			expectEnv:   true,
+			expectFile:  true,
+			signedToken: "foo.bar.baz",
+		},
+		{
+			name:        "env only",
+			identity:    &structs.WorkloadIdentity{Env: true},
+			expectEnv:   true,
+			signedToken: "foo.bar.baz",
+		},
+		{
+			name:     "unsigned alloc",
+			identity: &structs.WorkloadIdentity{Env: true, File: true},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := testlog.HCLogger(t)
+
+			alloc := mock.BatchAlloc()
+			task := alloc.Job.TaskGroups[0].Tasks[0]
+			task.Identity = tc.identity
+			if tc.signedToken != "" {
+				alloc.SignedIdentities = map[string]string{task.Name: tc.signedToken}
+			}
+
+			allocDir := allocdir.NewAllocDir(logger, "nomadtest_identity", alloc.ID)
+			defer allocDir.Destroy()
+			taskDir := allocDir.NewTaskDir(task.Name)
+			require.NoError(t, taskDir.Build(false, nil))
+
+			envBuilder := taskenv.NewBuilder(mock.Node(), alloc, task, "global")
+			h := newIdentityHook(alloc, task, envBuilder, logger)
+
+			req := &interfaces.TaskPrestartRequest{Task: task, TaskDir: taskDir}
+			require.NoError(t, h.Prestart(context.Background(), req, &interfaces.TaskPrestartResponse{}))
+
+			env := envBuilder.Build().Map()
+			if tc.expectEnv {
+				require.Equal(t, tc.signedToken, env[taskenv.WorkloadToken])
+			} else {
+				require.NotContains(t, env, taskenv.WorkloadToken)
+			}
+
+			token, err := ioutil.ReadFile(filepath.Join(taskDir.SecretsDir, structs.WorkloadIdentityFile))
+			if tc.expectFile {
+				require.NoError(t, err)
+				require.Equal(t, tc.signedToken, string(token))
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestIdentityHook_Update(t *testing.T) {
+	ci.Parallel(t)
+
+	logger := testlog.HCLogger(t)
+
+	alloc := mock.BatchAlloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.Identity = &structs.WorkloadIdentity{Env: true, File: true}
+	alloc.SignedIdentities = map[string]string{task.Name: "foo.bar.baz"}
+
+	allocDir := allocdir.NewAllocDir(logger, "nomadtest_identity_update", alloc.ID)
+	defer allocDir.Destroy()
+	taskDir := allocDir.NewTaskDir(task.Name)
+	require.NoError(t, taskDir.Build(false, nil))
+
+	envBuilder := taskenv.NewBuilder(mock.Node(), alloc, task, "global")
+	h := newIdentityHook(alloc, task, envBuilder, logger)
+
+	req := &interfaces.TaskPrestartRequest{Task: task, TaskDir: taskDir}
+	require.NoError(t, h.Prestart(context.Background(), req, &interfaces.TaskPrestartResponse{}))
+
+	// Update the allocation with a newly signed identity, which should be
+	// exposed to the task.
+	updated := alloc.Copy()
+	updated.SignedIdentities[task.Name] = "new.signed.token"
+	updateReq := &interfaces.TaskUpdateRequest{Alloc: updated}
+	require.NoError(t, h.Update(context.Background(), updateReq, &interfaces.TaskUpdateResponse{}))
+
+	require.Equal(t, "new.signed.token", envBuilder.Build().Map()[taskenv.WorkloadToken])
+
+	token, err := ioutil.ReadFile(filepath.Join(taskDir.SecretsDir, structs.WorkloadIdentityFile))
+	require.NoError(t, err)
+	require.Equal(t, "new.signed.token", string(token))
+}
@@ -12,6 +12,7 @@ import (
 	plugin "github.com/hashicorp/go-plugin"
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
 	"github.com/hashicorp/nomad/client/logmon"
+	"github.com/hashicorp/nomad/client/logmon/logging"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/structs"
 	bstructs "github.com/hashicorp/nomad/plugins/base/structs"
@@ -176,14 +177,26 @@ func (h *logmonHook) prestartOneLoop(ctx context.Context, req *interfaces.TaskPr
 		}
 	}
 
+	alloc := h.runner.Alloc()
 	err := h.logmon.Start(&logmon.LogConfig{
-		LogDir:        h.config.logDir,
-		StdoutLogFile: fmt.Sprintf("%s.stdout", req.Task.Name),
-		StderrLogFile: fmt.Sprintf("%s.stderr", req.Task.Name),
-		StdoutFifo:    h.config.stdoutFifo,
-		StderrFifo:    h.config.stderrFifo,
-		MaxFiles:      req.Task.LogConfig.MaxFiles,
-		MaxFileSizeMB: req.Task.LogConfig.MaxFileSizeMB,
+		LogDir:         h.config.logDir,
+		StdoutLogFile:  fmt.Sprintf("%s.stdout", req.Task.Name),
+		StderrLogFile:  fmt.Sprintf("%s.stderr", req.Task.Name),
+		StdoutFifo:     h.config.stdoutFifo,
+		StderrFifo:     h.config.stderrFifo,
+		MaxFiles:       req.Task.LogConfig.MaxFiles,
+		MaxFileSizeMB:  req.Task.LogConfig.MaxFileSizeMB,
+		MaxFileAge:     req.Task.LogConfig.MaxFileAge,
+		Compress:       req.Task.LogConfig.Compress,
+		MaxAllocSizeMB: req.Task.LogConfig.MaxAllocSizeMB,
+		Sinks:          logSinkConfigs(req.Task.LogConfig.Sinks),
+		Metadata: map[string]string{
+			"alloc_id":  alloc.ID,
+			"namespace": alloc.Namespace,
+			"job":       alloc.JobID,
+			"group":     alloc.TaskGroup,
+			"task":      req.Task.Name,
+		},
 	})
 	if err != nil {
 		h.logger.Error("failed to start logmon", "error", err)
@@ -193,6 +206,25 @@ func (h *logmonHook) prestartOneLoop(ctx context.Context, req *interfaces.TaskPr
 	return nil
 }
 
+// logSinkConfigs returns the logmon configuration of the sinks of a task.
+func logSinkConfigs(sinks []*structs.LogSink) []*logging.SinkConfig {
+	if len(sinks) == 0 {
+		return nil
+	}
+	out := make([]*logging.SinkConfig, len(sinks))
+	for i, sink := range sinks {
+		out[i] = &logging.SinkConfig{
+			Type:       sink.Type,
+			Address:    sink.Address,
+			Tag:        sink.Tag,
+			Facility:   sink.Facility,
+			BufferSize: sink.BufferSize,
+			Overflow:   sink.Overflow,
+		}
+	}
+	return out
+}
+
 func (h *logmonHook) Stop(_ context.Context, req *interfaces.TaskStopRequest, _ *interfaces.TaskStopResponse) error {
 
 	// It's possible that Stop was called without calling Prestart on agent
@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
 	tinterfaces "github.com/hashicorp/nomad/client/allocrunner/taskrunner/interfaces"
 	"github.com/hashicorp/nomad/client/serviceregistration"
+	"github.com/hashicorp/nomad/client/serviceregistration/wrapper"
 	"github.com/hashicorp/nomad/client/taskenv"
 	agentconsul "github.com/hashicorp/nomad/command/agent/consul"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -29,18 +30,24 @@ type scriptCheckHookConfig struct {
 	consul       serviceregistration.Handler
 	logger       log.Logger
 	shutdownWait time.Duration
+
+	// serviceRegWrapper is used to find the handler for script checks of
+	// services which use the Nomad provider. It may be nil, in which case
+	// all script checks report to the consul handler.
+	serviceRegWrapper *wrapper.HandlerWrapper
 }
 
 // scriptCheckHook implements a task runner hook for running script
 // checks in the context of a task
 type scriptCheckHook struct {
-	consul          serviceregistration.Handler
-	consulNamespace string
-	alloc           *structs.Allocation
-	task            *structs.Task
-	logger          log.Logger
-	shutdownWait    time.Duration // max time to wait for scripts to shutdown
-	shutdownCh      chan struct{} // closed when all scripts should shutdown
+	consul            serviceregistration.Handler
+	serviceRegWrapper *wrapper.HandlerWrapper
+	consulNamespace   string
+	alloc             *structs.Allocation
+	task              *structs.Task
+	logger            log.Logger
+	shutdownWait      time.Duration // max time to wait for scripts to shutdown
+	shutdownCh        chan struct{} // closed when all scripts should shutdown
 
 	// The following fields can be changed by Update()
 	driverExec tinterfaces.ScriptExecutor
@@ -60,14 +67,15 @@ type scriptCheckHook struct {
 // in Poststart() or Update()
 func newScriptCheckHook(c scriptCheckHookConfig) *scriptCheckHook {
 	h := &scriptCheckHook{
-		consul:          c.consul,
-		consulNamespace: c.alloc.Job.LookupTaskGroup(c.alloc.TaskGroup).Consul.GetNamespace(),
-		alloc:           c.alloc,
-		task:            c.task,
-		scripts:         make(map[string]*scriptCheck),
-		runningScripts:  make(map[string]*taskletHandle),
-		shutdownWait:    defaultShutdownWait,
-		shutdownCh:      make(chan struct{}),
+		consul:            c.consul,
+		serviceRegWrapper: c.serviceRegWrapper,
+		consulNamespace:   c.alloc.Job.LookupTaskGroup(c.alloc.TaskGroup).Consul.GetNamespace(),
+		alloc:             c.alloc,
+		task:              c.task,
+		scripts:           make(map[string]*scriptCheck),
+		runningScripts:    make(map[string]*taskletHandle),
+		shutdownWait:      defaultShutdownWait,
+		shutdownCh:        make(chan struct{}),
 	}
 
 	if c.shutdownWait != 0 {
@@ -190,7 +198,7 @@ func (h *scriptCheckHook) newScriptChecks() map[string]*scriptCheck {
 				taskName:        h.task.Name,
 				check:           check,
 				serviceID:       serviceID,
-				ttlUpdater:      h.consul,
+				ttlUpdater:      h.ttlUpdater(service.Provider),
 				driverExec:      h.driverExec,
 				taskEnv:         h.taskEnv,
 				logger:          h.logger,
@@ -230,7 +238,7 @@ func (h *scriptCheckHook) newScriptChecks() map[string]*scriptCheck {
 				taskName:        groupTaskName,
 				check:           check,
 				serviceID:       serviceID,
-				ttlUpdater:      h.consul,
+				ttlUpdater:      h.ttlUpdater(service.Provider),
 				driverExec:      h.driverExec,
 				taskEnv:         h.taskEnv,
 				logger:          h.logger,
@@ -245,6 +253,15 @@ func (h *scriptCheckHook) newScriptChecks() map[string]*scriptCheck {
 	return scriptChecks
 }
 
+// ttlUpdater returns the handler which script checks of a service using the
+// passed provider report their status to.
+func (h *scriptCheckHook) ttlUpdater(provider string) TTLUpdater {
+	if provider == structs.ServiceProviderNomad && h.serviceRegWrapper != nil {
+		return h.serviceRegWrapper.ProviderHandler(provider)
+	}
+	return h.consul
+}
+
 // associated returns true if the script check is associated with the task. This
 // would be the case if the check.task is the same as task, or if the service.task
 // is the same as the task _and_ check.task is not configured (i.e. the check
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"strings"
 	"sync"
 	"time"
@@ -845,8 +847,14 @@ func (tr *TaskRunner) runDriver() error {
 		return nil
 	}
 
-	// Start the job if there's no existing handle (or if RecoverTask failed)
-	handle, net, err := tr.driver.StartTask(taskConfig)
+	// Restore the task if it was checkpointed by the previous allocation,
+	// otherwise start the job if there's no existing handle (or if
+	// RecoverTask failed)
+	handle, net, restored := tr.restoreTask(taskConfig)
+	var err error
+	if !restored {
+		handle, net, err = tr.driver.StartTask(taskConfig)
+	}
 	if err != nil {
 		// The plugin has died, try relaunching it
 		if err == bstructs.ErrPluginShutdown {
@@ -955,12 +963,18 @@ func (tr *TaskRunner) handleKill(resultCh <-chan *drivers.ExitResult) *drivers.E
 		return nil
 	}
 
-	// Kill the task using an exponential backoff in-case of failures.
-	result, killErr := tr.killTask(handle, resultCh)
-	if killErr != nil {
-		// We couldn't successfully destroy the resource created.
-		tr.logger.Error("failed to kill task. Resources may have been leaked", "error", killErr)
-		tr.setKillErr(killErr)
+	// Checkpointing the task stops it, so it only has to be killed if it
+	// isn't checkpointed.
+	var result *drivers.ExitResult
+	var killErr error
+	if !tr.checkpointTask(handle) {
+		// Kill the task using an exponential backoff in-case of failures.
+		result, killErr = tr.killTask(handle, resultCh)
+		if killErr != nil {
+			// We couldn't successfully destroy the resource created.
+			tr.logger.Error("failed to kill task. Resources may have been leaked", "error", killErr)
+			tr.setKillErr(killErr)
+		}
 	}
 
 	if result != nil {
@@ -1024,6 +1038,83 @@ func (tr *TaskRunner) killTask(handle *DriverHandle, resultCh <-chan *drivers.Ex
 	return nil, err
 }
 
+// shouldCheckpoint returns whether the task must be checkpointed when killed,
+// which is the case when its allocation is migrated with a drain preferring
+// checkpoints, the ephemeral disk holding the checkpoint is migrated and the
+// driver supports checkpoints.
+func (tr *TaskRunner) shouldCheckpoint() bool {
+	if tr.driverCapabilities == nil || !tr.driverCapabilities.Checkpoint {
+		return false
+	}
+
+	alloc := tr.Alloc()
+	if !alloc.DesiredTransition.ShouldCheckpoint() {
+		return false
+	}
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	return tg != nil && tg.EphemeralDisk != nil && tg.EphemeralDisk.Migrate
+}
+
+// checkpointTask checkpoints the task into its checkpoint directory if it
+// must be checkpointed. Returns true if the task was checkpointed, in which
+// case it was stopped by the driver, or false if it must still be killed.
+func (tr *TaskRunner) checkpointTask(handle *DriverHandle) bool {
+	if !tr.shouldCheckpoint() {
+		return false
+	}
+
+	dir := tr.taskDir.CheckpointDir
+	if err := handle.Checkpoint(dir); err != nil {
+		tr.logger.Error("failed to checkpoint task, killing it", "task_id", handle.ID(), "error", err)
+		tr.EmitEvent(structs.NewTaskEvent(structs.TaskCheckpointFailed).
+			SetMessage(fmt.Sprintf("Failed to checkpoint task, killing it: %v", err)))
+
+		// Don't let the replacement allocation restore a partial checkpoint
+		if err := os.RemoveAll(dir); err != nil {
+			tr.logger.Warn("failed to remove checkpoint", "path", dir, "error", err)
+		}
+		return false
+	}
+
+	tr.logger.Info("checkpointed task", "task_id", handle.ID(), "path", dir)
+	tr.EmitEvent(structs.NewTaskEvent(structs.TaskCheckpointed))
+	return true
+}
+
+// restoreTask restores the task from the checkpoint migrated from the
+// previous allocation, if there is one and the driver supports checkpoints.
+// Returns false if the task must be started instead. The checkpoint is
+// removed either way so that restarts of the task start it from scratch.
+func (tr *TaskRunner) restoreTask(taskConfig *drivers.TaskConfig) (*drivers.TaskHandle, *drivers.DriverNetwork, bool) {
+	dir := tr.taskDir.CheckpointDir
+	if entries, err := ioutil.ReadDir(dir); err != nil || len(entries) == 0 {
+		return nil, nil, false
+	}
+
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			tr.logger.Warn("failed to remove checkpoint", "path", dir, "error", err)
+		}
+	}()
+
+	if tr.driverCapabilities == nil || !tr.driverCapabilities.Checkpoint {
+		tr.logger.Warn("driver does not support checkpoints, starting task", "path", dir)
+		return nil, nil, false
+	}
+
+	handle, net, err := tr.driver.RestoreTask(taskConfig, dir)
+	if err != nil {
+		tr.logger.Error("failed to restore task from checkpoint, starting it", "path", dir, "error", err)
+		tr.EmitEvent(structs.NewTaskEvent(structs.TaskCheckpointFailed).
+			SetMessage(fmt.Sprintf("Failed to restore task from checkpoint, starting it: %v", err)))
+		return nil, nil, false
+	}
+
+	tr.logger.Info("restored task from checkpoint", "task_id", taskConfig.ID, "path", dir)
+	tr.EmitEvent(structs.NewTaskEvent(structs.TaskRestoredFromCheckpoint))
+	return handle, net, true
+}
+
 // persistLocalState persists local state to disk synchronously.
 func (tr *TaskRunner) persistLocalState() error {
 	tr.stateLock.RLock()
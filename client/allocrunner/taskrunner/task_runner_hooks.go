@@ -63,6 +63,7 @@ func (tr *TaskRunner) initHooks() {
 		newTaskDirHook(tr, hookLogger),
 		newLogMonHook(tr, hookLogger),
 		newDispatchHook(alloc, hookLogger),
+		newIdentityHook(alloc, task, tr.envBuilder, hookLogger),
 		newVolumeHook(tr, hookLogger),
 		newArtifactHook(tr, tr.getter, hookLogger),
 		newStatsHook(tr, tr.clientConfig.StatsCollectionInterval, hookLogger),
@@ -159,10 +160,11 @@ func (tr *TaskRunner) initHooks() {
 	// initial registration may be updated to include script checks, which must
 	// be handled with this hook.
 	tr.runnerHooks = append(tr.runnerHooks, newScriptCheckHook(scriptCheckHookConfig{
-		alloc:  tr.Alloc(),
-		task:   tr.Task(),
-		consul: tr.consulServiceClient,
-		logger: hookLogger,
+		alloc:             tr.Alloc(),
+		task:              tr.Task(),
+		consul:            tr.consulServiceClient,
+		serviceRegWrapper: tr.serviceRegWrapper,
+		logger:            hookLogger,
 	}))
 
 	// If this task driver has remote capabilities, add the remote task
@@ -571,6 +571,7 @@ func maskProcessEnv(env map[string]string) map[string]string {
 func parseTemplateConfigs(config *TaskTemplateManagerConfig) (map[*ctconf.TemplateConfig]*structs.Template, error) {
 	sandboxEnabled := !config.ClientConfig.TemplateConfig.DisableSandbox
 	taskEnv := config.EnvBuilder.Build()
+	readVariable := newVariableReader(config)
 
 	ctmpls := make(map[*ctconf.TemplateConfig]*structs.Template, len(config.Templates))
 	for _, tmpl := range config.Templates {
@@ -591,10 +592,25 @@ func parseTemplateConfigs(config *TaskTemplateManagerConfig) (map[*ctconf.Templa
 			}
 		}
 
+		// Render any Nomad variables used by the template into its contents.
+		// Templates read from a source file which use variables are rendered
+		// from their rewritten contents instead.
+		contents := tmpl.EmbeddedTmpl
+		if src != "" {
+			if srcContents, err := readTemplateSource(src); err == nil &&
+				strings.Contains(srcContents, variableFuncName) {
+				contents, src = srcContents, ""
+			}
+		}
+		contents, err := renderVariables(contents, tmpl.LeftDelim, tmpl.RightDelim, readVariable)
+		if err != nil {
+			return nil, err
+		}
+
 		ct := ctconf.DefaultTemplateConfig()
 		ct.Source = &src
 		ct.Destination = &dest
-		ct.Contents = &tmpl.EmbeddedTmpl
+		ct.Contents = &contents
 		ct.LeftDelim = &tmpl.LeftDelim
 		ct.RightDelim = &tmpl.RightDelim
 		ct.FunctionDenylist = config.ClientConfig.TemplateConfig.FunctionDenylist
@@ -0,0 +1,252 @@
+package template
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template/parse"
+
+	"github.com/hashicorp/nomad/api"
+)
+
+const (
+	// variableFuncName is the name of the template function used to read
+	// Nomad variables.
+	variableFuncName = "nomadVar"
+
+	// variableRenderFunc is the consul-template function the nomadVar
+	// function is rewritten to. It converts the JSON encoded items of the
+	// variable into a map, which can be accessed within the template.
+	variableRenderFunc = "parseJSON"
+)
+
+// variableReader returns the items of the variable at the given path.
+type variableReader func(path string) (map[string]string, error)
+
+// newVariableReader returns a variableReader which reads variables from the
+// local Nomad agent, using the Node's SecretID to authenticate. The servers
+// only permit the node to read variables belonging to the job of the
+// allocation being run.
+func newVariableReader(config *TaskTemplateManagerConfig) variableReader {
+	return func(path string) (map[string]string, error) {
+		cc := config.ClientConfig
+		if cc.TemplateDialer == nil {
+			return nil, fmt.Errorf("Nomad API is not available to read variable %q", path)
+		}
+
+		client, err := api.NewClient(&api.Config{
+			Address:   "http://127.0.0.1",
+			Namespace: config.NomadNamespace,
+			SecretID:  cc.Node.SecretID,
+			HttpClient: &http.Client{
+				Transport: &http.Transport{DialContext: cc.TemplateDialer.DialContext},
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		defer client.Close()
+
+		variable, _, err := client.Variables().Read(path, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read variable %q: %v", path, err)
+		}
+		return variable.Items, nil
+	}
+}
+
+// renderVariables replaces each call to the nomadVar function within the
+// template with the items of the variable, as consul-template does not allow
+// us to register additional template functions. The variable path must be a
+// string literal. Variables are read when the template is parsed, so changes
+// to a variable are picked up when the task is restarted.
+func renderVariables(contents, leftDelim, rightDelim string, read variableReader) (string, error) {
+
+	// Avoid parsing templates which do not use variables, so any parse errors
+	// are reported by consul-template as they always have been.
+	if !strings.Contains(contents, variableFuncName) {
+		return contents, nil
+	}
+
+	tree := parse.New("nomadVar")
+	tree.Mode = parse.SkipFuncCheck
+	treeSet := make(map[string]*parse.Tree)
+	if _, err := tree.Parse(contents, leftDelim, rightDelim, treeSet); err != nil {
+		return "", fmt.Errorf("failed to parse template: %v", err)
+	}
+
+	var calls []*variableCall
+	for _, t := range treeSet {
+		if t.Root == nil {
+			continue
+		}
+		found, err := findVariableCalls(t.Root)
+		if err != nil {
+			return "", err
+		}
+		calls = append(calls, found...)
+	}
+	if len(calls) == 0 {
+		return contents, nil
+	}
+
+	// Replace the calls from the end of the template backwards, so the
+	// offsets of the remaining calls stay valid.
+	sort.Slice(calls, func(i, j int) bool { return calls[i].start > calls[j].start })
+
+	items := make(map[string]string)
+	last := len(contents) + 1
+	for _, call := range calls {
+		if call.start == last {
+			continue
+		}
+		last = call.start
+
+		buf, ok := items[call.path]
+		if !ok {
+			vars, err := read(call.path)
+			if err != nil {
+				return "", err
+			}
+			out, err := json.Marshal(vars)
+			if err != nil {
+				return "", err
+			}
+			buf = string(out)
+			items[call.path] = buf
+		}
+
+		contents = contents[:call.start] +
+			variableRenderFunc + " " + strconv.Quote(buf) +
+			contents[call.end:]
+	}
+	return contents, nil
+}
+
+// variableCall is a single call of the nomadVar function within a template.
+// The start and end offsets cover the function name and its argument.
+type variableCall struct {
+	path       string
+	start, end int
+}
+
+// findVariableCalls walks the template tree below the node and returns all
+// calls of the nomadVar function.
+func findVariableCalls(node parse.Node) ([]*variableCall, error) {
+	var calls []*variableCall
+
+	var walk func(parse.Node) error
+	walk = func(node parse.Node) error {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return nil
+			}
+			for _, child := range n.Nodes {
+				if err := walk(child); err != nil {
+					return err
+				}
+			}
+		case *parse.ActionNode:
+			return walk(n.Pipe)
+		case *parse.IfNode:
+			return walkBranch(walk, &n.BranchNode)
+		case *parse.RangeNode:
+			return walkBranch(walk, &n.BranchNode)
+		case *parse.WithNode:
+			return walkBranch(walk, &n.BranchNode)
+		case *parse.TemplateNode:
+			if n.Pipe != nil {
+				return walk(n.Pipe)
+			}
+		case *parse.PipeNode:
+			if n == nil {
+				return nil
+			}
+			for _, cmd := range n.Cmds {
+				if err := walk(cmd); err != nil {
+					return err
+				}
+			}
+		case *parse.ChainNode:
+			return walk(n.Node)
+		case *parse.CommandNode:
+			call, err := parseVariableCall(n)
+			if err != nil {
+				return err
+			}
+			if call != nil {
+				calls = append(calls, call)
+			}
+			for _, arg := range n.Args {
+				if err := walk(arg); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	if err := walk(node); err != nil {
+		return nil, err
+	}
+	return calls, nil
+}
+
+// walkBranch walks the pipeline and lists of an if, range, or with node.
+func walkBranch(walk func(parse.Node) error, n *parse.BranchNode) error {
+	if n.Pipe != nil {
+		if err := walk(n.Pipe); err != nil {
+			return err
+		}
+	}
+	if n.List != nil {
+		if err := walk(n.List); err != nil {
+			return err
+		}
+	}
+	if n.ElseList != nil {
+		return walk(n.ElseList)
+	}
+	return nil
+}
+
+// parseVariableCall returns the nomadVar call made by the command, or nil if
+// the command does not call nomadVar.
+func parseVariableCall(n *parse.CommandNode) (*variableCall, error) {
+	if len(n.Args) == 0 {
+		return nil, nil
+	}
+	ident, ok := n.Args[0].(*parse.IdentifierNode)
+	if !ok || ident.Ident != variableFuncName {
+		return nil, nil
+	}
+
+	if len(n.Args) != 2 {
+		return nil, fmt.Errorf("%s requires a single variable path argument", variableFuncName)
+	}
+	path, ok := n.Args[1].(*parse.StringNode)
+	if !ok {
+		return nil, fmt.Errorf("%s requires the variable path to be a string literal", variableFuncName)
+	}
+
+	return &variableCall{
+		path:  path.Text,
+		start: int(ident.Pos),
+		end:   int(path.Pos) + len(path.Quoted),
+	}, nil
+}
+
+// readTemplateSource reads the template source file, so that any variables
+// it uses can be rendered into its contents.
+func readTemplateSource(src string) (string, error) {
+	buf, err := ioutil.ReadFile(src)
+	if err != nil {
+		return "", fmt.Errorf("failed to read template source %q: %v", src, err)
+	}
+	return string(buf), nil
+}
@@ -0,0 +1,94 @@
+package template
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderVariables(t *testing.T) {
+	ci.Parallel(t)
+
+	vars := map[string]map[string]string{
+		"nomad/jobs/example":     {"user": "admin", "pass": `hun"ter2`},
+		"nomad/jobs/example/web": {"port": "8080"},
+	}
+
+	reads := 0
+	read := func(path string) (map[string]string, error) {
+		reads++
+		items, ok := vars[path]
+		if !ok {
+			return nil, fmt.Errorf("variable %q not found", path)
+		}
+		return items, nil
+	}
+
+	testCases := []struct {
+		name          string
+		contents      string
+		left, right   string
+		expected      string
+		expectedErr   string
+		expectedReads int
+	}{
+		{
+			name:     "no variables",
+			contents: `{{ env "NOMAD_TASK_NAME" }}`,
+			expected: `{{ env "NOMAD_TASK_NAME" }}`,
+		},
+		{
+			name:          "single call",
+			contents:      `{{ with nomadVar "nomad/jobs/example/web" }}{{ .port }}{{ end }}`,
+			expected:      `{{ with parseJSON "{\"port\":\"8080\"}" }}{{ .port }}{{ end }}`,
+			expectedReads: 1,
+		},
+		{
+			name:          "repeated and nested calls",
+			contents:      `{{ (nomadVar "nomad/jobs/example").user }}{{ range $k, $v := nomadVar ` + "`nomad/jobs/example`" + ` }}{{ $k }}{{ end }}`,
+			expected:      `{{ (parseJSON "{\"pass\":\"hun\\\"ter2\",\"user\":\"admin\"}").user }}{{ range $k, $v := parseJSON "{\"pass\":\"hun\\\"ter2\",\"user\":\"admin\"}" }}{{ $k }}{{ end }}`,
+			expectedReads: 1,
+		},
+		{
+			name:          "custom delimiters and define",
+			contents:      `[[ define "x" ]][[ nomadVar "nomad/jobs/example/web" | toJSON ]][[ end ]][[ template "x" ]]`,
+			left:          "[[",
+			right:         "]]",
+			expected:      `[[ define "x" ]][[ parseJSON "{\"port\":\"8080\"}" | toJSON ]][[ end ]][[ template "x" ]]`,
+			expectedReads: 1,
+		},
+		{
+			name:        "non-literal path",
+			contents:    `{{ nomadVar (env "PATH") }}`,
+			expectedErr: "string literal",
+		},
+		{
+			name:        "missing path",
+			contents:    `{{ nomadVar }}`,
+			expectedErr: "single variable path argument",
+		},
+		{
+			name:          "missing variable",
+			contents:      `{{ nomadVar "nomad/jobs/missing" }}`,
+			expectedErr:   `variable "nomad/jobs/missing" not found`,
+			expectedReads: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reads = 0
+			out, err := renderVariables(tc.contents, tc.left, tc.right, read)
+			if tc.expectedErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expectedErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expected, out)
+			}
+			require.Equal(t, tc.expectedReads, reads)
+		})
+	}
+}
@@ -359,6 +359,19 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulProxie
 	// Create the logger
 	logger := cfg.Logger.ResetNamedIntercept("client")
 
+	// Rootless clients manage cgroups inside the subtree delegated to them.
+	// Without delegation tasks cannot be limited, but drivers which do not
+	// require cgroups remain usable.
+	if cfg.Rootless {
+		parent, err := cgutil.RootlessCgroupParent(cfg.CgroupParent)
+		if err != nil {
+			logger.Warn("failed to set up delegated cgroup for rootless client, cgroup management disabled", "error", err)
+		} else {
+			logger.Info("running rootless client", "cgroup_parent", parent)
+			cfg.CgroupParent = parent
+		}
+	}
+
 	// Create the client
 	c := &Client{
 		config:               cfg,
@@ -1436,6 +1449,9 @@ func (c *Client) setupNode() error {
 	if node.Datacenter == "" {
 		node.Datacenter = "dc1"
 	}
+	if node.NodePool == "" {
+		node.NodePool = structs.NodePoolDefault
+	}
 	if node.Name == "" {
 		node.Name, _ = os.Hostname()
 	}
@@ -1878,12 +1894,27 @@ func (c *Client) registerNode() error {
 	return nil
 }
 
+// nodeUtilization returns the resource utilization of the host, as last
+// collected by the host stats collector, to be sent with heartbeats. It
+// returns nil if the host stats are not available.
+func (c *Client) nodeUtilization() *structs.NodeUtilization {
+	hStats := c.hostStatsCollector.Stats()
+	if hStats == nil || hStats.Memory == nil {
+		return nil
+	}
+	return &structs.NodeUtilization{
+		CpuUsedMHz:   int64(hStats.CPUTicksConsumed),
+		MemoryUsedMB: int64(hStats.Memory.Used / 1024 / 1024),
+	}
+}
+
 // updateNodeStatus is used to heartbeat and update the status of the node
 func (c *Client) updateNodeStatus() error {
 	start := time.Now()
 	req := structs.NodeUpdateStatusRequest{
 		NodeID:       c.NodeID(),
 		Status:       structs.NodeStatusReady,
+		Utilization:  c.nodeUtilization(),
 		WriteRequest: structs.WriteRequest{Region: c.Region()},
 	}
 	var resp structs.NodeUpdateResponse
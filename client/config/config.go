@@ -280,6 +280,11 @@ type Config struct {
 	// Currently this only includes the 'cpuset' cgroup subsystem.
 	CgroupParent string
 
+	// Rootless indicates the client runs as an unprivileged user. Cgroups are
+	// then managed inside the subtree delegated to that user, and allocation
+	// directories are built without changing ownership or mounting.
+	Rootless bool
+
 	// ReservableCores if set overrides the set of reservable cores reported in fingerprinting.
 	ReservableCores []uint16
 
@@ -880,6 +885,7 @@ func (c *Config) NomadPluginConfig() *base.AgentConfig {
 		Driver: &base.ClientDriverConfig{
 			ClientMinPort: c.ClientMinPort,
 			ClientMaxPort: c.ClientMaxPort,
+			Rootless:      c.Rootless,
 		},
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/hashicorp/nomad/client/lib/numa"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/plugins/device"
 	psstructs "github.com/hashicorp/nomad/plugins/shared/structs"
@@ -87,7 +88,11 @@ func convertHwLocality(l *device.DeviceLocality) *structs.NodeDeviceLocality {
 		return nil
 	}
 
-	return &structs.NodeDeviceLocality{
+	locality := &structs.NodeDeviceLocality{
 		PciBusID: l.PciBusID,
 	}
+	if node, ok := numa.DeviceNode(numa.SysfsRoot, l.PciBusID); ok {
+		locality.NUMANode = &node
+	}
+	return locality
 }
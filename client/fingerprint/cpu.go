@@ -32,7 +32,7 @@ func NewCPUFingerprint(logger log.Logger) Fingerprint {
 
 func (f *CPUFingerprint) Fingerprint(req *FingerprintRequest, resp *FingerprintResponse) error {
 	cfg := req.Config
-	setResourcesCPU := func(totalCompute int, totalCores uint16, reservableCores []uint16) {
+	setResourcesCPU := func(totalCompute int, totalCores uint16, reservableCores []uint16, topology *structs.NodeNUMAResources) {
 		// COMPAT(0.10): Remove in 0.10
 		resp.Resources = &structs.Resources{
 			CPU: totalCompute,
@@ -44,6 +44,7 @@ func (f *CPUFingerprint) Fingerprint(req *FingerprintRequest, resp *FingerprintR
 				TotalCpuCores:      totalCores,
 				ReservableCpuCores: reservableCores,
 			},
+			NUMA: topology,
 		}
 	}
 
@@ -81,6 +82,19 @@ func (f *CPUFingerprint) Fingerprint(req *FingerprintRequest, resp *FingerprintR
 		}
 	}
 
+	topology, err := f.deriveNUMATopology()
+	if err != nil {
+		f.logger.Warn("failed to detect NUMA topology", "error", err)
+	} else if topology != nil {
+		sockets := make(map[uint16]struct{}, len(topology.Nodes))
+		for _, node := range topology.Nodes {
+			sockets[node.Socket] = struct{}{}
+		}
+		resp.AddAttribute("numa.node_count", fmt.Sprintf("%d", len(topology.Nodes)))
+		resp.AddAttribute("numa.socket_count", fmt.Sprintf("%d", len(sockets)))
+		f.logger.Debug("detected NUMA topology", "nodes", len(topology.Nodes), "sockets", len(sockets))
+	}
+
 	tt := int(stats.TotalTicksAvailable())
 	if cfg.CpuCompute > 0 {
 		f.logger.Debug("using user specified cpu compute", "cpu_compute", cfg.CpuCompute)
@@ -97,7 +111,7 @@ func (f *CPUFingerprint) Fingerprint(req *FingerprintRequest, resp *FingerprintR
 	}
 
 	resp.AddAttribute("cpu.totalcompute", fmt.Sprintf("%d", tt))
-	setResourcesCPU(tt, uint16(numCores), reservableCores)
+	setResourcesCPU(tt, uint16(numCores), reservableCores, topology)
 	resp.Detected = true
 
 	return nil
@@ -2,6 +2,8 @@ package fingerprint
 
 import (
 	"github.com/hashicorp/nomad/client/lib/cgutil"
+	"github.com/hashicorp/nomad/client/lib/numa"
+	"github.com/hashicorp/nomad/nomad/structs"
 )
 
 func (f *CPUFingerprint) deriveReservableCores(req *FingerprintRequest) ([]uint16, error) {
@@ -10,3 +12,7 @@ func (f *CPUFingerprint) deriveReservableCores(req *FingerprintRequest) ([]uint1
 	// We may assume the hierarchy is already setup.
 	return cgutil.GetCPUsFromCgroup(req.Config.CgroupParent)
 }
+
+func (f *CPUFingerprint) deriveNUMATopology() (*structs.NodeNUMAResources, error) {
+	return numa.Scan(numa.SysfsRoot)
+}
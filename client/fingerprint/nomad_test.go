@@ -60,4 +60,5 @@ func TestNomadFingerprint(t *testing.T) {
 
 	serviceDisco := response.Attributes["nomad.service_discovery"]
 	require.Equal(t, "true", serviceDisco, "service_discovery attr incorrect")
+	require.Equal(t, "false", response.Attributes["nomad.rootless"], "rootless attr incorrect")
 }
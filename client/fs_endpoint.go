@@ -2,7 +2,9 @@ package client
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/binary"
 	"fmt"
 	"io"
 	"math"
@@ -22,6 +24,7 @@ import (
 	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/client/allocdir"
 	sframer "github.com/hashicorp/nomad/client/lib/streamframer"
+	"github.com/hashicorp/nomad/client/logmon/logging"
 	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -557,6 +560,11 @@ func (f *FileSystem) logsImpl(ctx context.Context, follow, plain bool, offset in
 			return fmt.Errorf("failed to list entries: %v", err)
 		}
 
+		// Offsets are relative to the uncompressed content of the logs
+		if err := uncompressedLogSizes(fs, logPath, entries); err != nil {
+			return err
+		}
+
 		// If we are not following logs, determine the max index for the logs we are
 		// interested in so we can stop there.
 		maxIndex := int64(math.MaxInt64)
@@ -573,6 +581,9 @@ func (f *FileSystem) logsImpl(ctx context.Context, follow, plain bool, offset in
 			return err
 		}
 
+		// Compressed logs have been rotated and are complete
+		compressed := strings.HasSuffix(logEntry.Name, logging.CompressedSuffix)
+
 		var eofCancelCh chan error
 		cancelAfterFirstEof := false
 		exitAfter := false
@@ -583,12 +594,16 @@ func (f *FileSystem) logsImpl(ctx context.Context, follow, plain bool, offset in
 			// At the end
 			cancelAfterFirstEof = true
 			exitAfter = true
-		} else {
+		} else if !compressed {
 			eofCancelCh = blockUntilNextLog(ctx, fs, logPath, task, logType, idx+1)
 		}
 
 		p := filepath.Join(logPath, logEntry.Name)
-		err = f.streamFile(ctx, openOffset, p, 0, fs, framer, eofCancelCh, cancelAfterFirstEof)
+		if compressed {
+			err = f.streamCompressedFile(ctx, openOffset, p, fs, framer)
+		} else {
+			err = f.streamFile(ctx, openOffset, p, 0, fs, framer, eofCancelCh, cancelAfterFirstEof)
+		}
 
 		// Check if the context is cancelled
 		select {
@@ -778,6 +793,87 @@ OUTER:
 	}
 }
 
+// streamCompressedFile streams the uncompressed content of a compressed log
+// file from the offset. Compressed files are never written to so the stream
+// ends at EOF. If the connection is broken an EPIPE error is returned.
+func (f *FileSystem) streamCompressedFile(ctx context.Context, offset int64, path string,
+	fs allocdir.AllocDirFS, framer *sframer.StreamFramer) error {
+
+	file, err := fs.ReadAt(path, 0)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gr, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+
+	// Skip to the offset as compressed files can't be seeked
+	if _, err := io.CopyN(io.Discard, gr, offset); err != nil && err != io.EOF {
+		return err
+	}
+
+	data := make([]byte, streamFrameSize)
+	for {
+		n, readErr := io.ReadFull(gr, data)
+		offset += int64(n)
+
+		if n != 0 {
+			if err := framer.Send(path, "", data[:n], offset); err != nil {
+				return parseFramerErr(err)
+			}
+		}
+
+		switch readErr {
+		case nil:
+		case io.EOF, io.ErrUnexpectedEOF:
+			return nil
+		default:
+			return readErr
+		}
+
+		select {
+		case <-framer.ExitCh():
+			return nil
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+	}
+}
+
+// uncompressedLogSizes sets the size of the entries of compressed log files
+// to the size of their uncompressed content, as stored in the trailer of the
+// gzip stream. Log files are never larger than the 4GiB the trailer can hold.
+func uncompressedLogSizes(fs allocdir.AllocDirFS, logPath string, entries []*cstructs.AllocFileInfo) error {
+	for _, entry := range entries {
+		if entry.IsDir || !strings.HasSuffix(entry.Name, logging.CompressedSuffix) || entry.Size < 4 {
+			continue
+		}
+
+		file, err := fs.ReadAt(filepath.Join(logPath, entry.Name), entry.Size-4)
+		if err != nil {
+			if os.IsNotExist(err) {
+				// Rotated out since listed
+				continue
+			}
+			return fmt.Errorf("failed to read size of %q: %v", entry.Name, err)
+		}
+
+		var trailer [4]byte
+		_, err = io.ReadFull(file, trailer[:])
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read size of %q: %v", entry.Name, err)
+		}
+		entry.Size = int64(binary.LittleEndian.Uint32(trailer[:]))
+	}
+	return nil
+}
+
 // blockUntilNextLog returns a channel that will have data sent when the next
 // log index or anything greater is created.
 func blockUntilNextLog(ctx context.Context, fs allocdir.AllocDirFS, logPath, task, logType string, nextIndex int64) chan error {
@@ -850,10 +946,12 @@ func (a indexTupleArray) Less(i, j int) bool { return a[i].idx < a[j].idx }
 func (a indexTupleArray) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 
 // logIndexes takes a set of entries and returns a indexTupleArray of
-// the desired log file entries. If the indexes could not be determined, an
-// error is returned.
+// the desired log file entries. Compressed log files are only returned if
+// they are not still being compressed, in which case the uncompressed file is
+// returned. If the indexes could not be determined, an error is returned.
 func logIndexes(entries []*cstructs.AllocFileInfo, task, logType string) (indexTupleArray, error) {
 	var indexes []indexTuple
+	positions := make(map[int64]int)
 	prefix := fmt.Sprintf("%s.%s.", task, logType)
 	for _, entry := range entries {
 		if entry.IsDir {
@@ -866,13 +964,24 @@ func logIndexes(entries []*cstructs.AllocFileInfo, task, logType string) (indexT
 			continue
 		}
 
+		compressed := strings.HasSuffix(idxStr, logging.CompressedSuffix)
+		idxStr = strings.TrimSuffix(idxStr, logging.CompressedSuffix)
+
 		// Convert to an int
 		idx, err := strconv.Atoi(idxStr)
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert %q to a log index: %v", idxStr, err)
 		}
 
-		indexes = append(indexes, indexTuple{idx: int64(idx), entry: entry})
+		tuple := indexTuple{idx: int64(idx), entry: entry}
+		if pos, ok := positions[tuple.idx]; ok {
+			if !compressed {
+				indexes[pos] = tuple
+			}
+			continue
+		}
+		positions[tuple.idx] = len(indexes)
+		indexes = append(indexes, tuple)
 	}
 
 	return indexTupleArray(indexes), nil
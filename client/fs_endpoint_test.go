@@ -1,6 +1,8 @@
 package client
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"fmt"
 	"io"
@@ -11,6 +13,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
 	"testing"
@@ -22,6 +25,7 @@ import (
 	"github.com/hashicorp/nomad/client/allocdir"
 	"github.com/hashicorp/nomad/client/config"
 	sframer "github.com/hashicorp/nomad/client/lib/streamframer"
+	"github.com/hashicorp/nomad/client/logmon/logging"
 	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/helper/uuid"
@@ -1900,6 +1904,92 @@ func TestFS_logsImpl_NoFollow(t *testing.T) {
 	}
 }
 
+func TestFS_logsImpl_Compressed(t *testing.T) {
+	ci.Parallel(t)
+
+	c, cleanup := TestClient(t, nil)
+	defer cleanup()
+
+	// Get a temp alloc dir and create the log dir
+	ad := tempAllocDir(t)
+	require.NoError(t, ad.Build())
+	defer ad.Destroy()
+
+	logDir := filepath.Join(ad.SharedDir, allocdir.LogDirName)
+	require.NoError(t, os.MkdirAll(logDir, 0777))
+
+	task := "foo"
+	logType := "stdout"
+	writeFile := func(name string, data []byte, compress bool) {
+		if compress {
+			var buf bytes.Buffer
+			gw := gzip.NewWriter(&buf)
+			_, err := gw.Write(data)
+			require.NoError(t, err)
+			require.NoError(t, gw.Close())
+			data = buf.Bytes()
+			name += logging.CompressedSuffix
+		}
+		require.NoError(t, ioutil.WriteFile(filepath.Join(logDir, name), data, 0777))
+	}
+
+	// The first file is still being compressed, the second one is
+	// compressed and the last one is being written to
+	writeFile("foo.stdout.0", []byte("abc"), false)
+	writeFile("foo.stdout.0", []byte("abc"), true)
+	writeFile("foo.stdout.1", []byte("def"), true)
+	writeFile("foo.stdout.2", []byte("ghi"), false)
+
+	entries, err := ad.List(filepath.Join(allocdir.SharedAllocName, allocdir.LogDirName))
+	require.NoError(t, err)
+	indexes, err := logIndexes(entries, task, logType)
+	require.NoError(t, err)
+	require.Len(t, indexes, 3)
+	sort.Sort(indexes)
+	require.Equal(t, "foo.stdout.0", indexes[0].entry.Name)
+	require.Equal(t, "foo.stdout.1"+logging.CompressedSuffix, indexes[1].entry.Name)
+	require.Equal(t, "foo.stdout.2", indexes[2].entry.Name)
+
+	cases := []struct {
+		name     string
+		origin   string
+		offset   int64
+		expected string
+	}{
+		{
+			name:     "from start",
+			origin:   OriginStart,
+			offset:   4,
+			expected: "efghi",
+		},
+		{
+			name:     "from end",
+			origin:   OriginEnd,
+			offset:   5,
+			expected: "efghi",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			frames := make(chan *sframer.StreamFrame, 32)
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			require.NoError(t, c.endpoints.FileSystem.logsImpl(
+				ctx, false, false, tc.offset,
+				tc.origin, task, logType, ad, frames))
+
+			// The frames are flushed and closed once streaming returns
+			var received []byte
+			for frame := range frames {
+				received = append(received, frame.Data...)
+			}
+			require.Equal(t, tc.expected, string(received))
+		})
+	}
+}
+
 func TestFS_logsImpl_Follow(t *testing.T) {
 	ci.Parallel(t)
 
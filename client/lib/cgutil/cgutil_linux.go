@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/nomad/helper/uuid"
@@ -115,7 +116,8 @@ func FindCgroupMountpointDir() (string, error) {
 	return mount[0].Mountpoint, nil
 }
 
-// CopyCpuset copies the cpuset.cpus value from source into destination.
+// CopyCpuset copies the cpuset.cpus value from source into destination, and
+// the cpuset.mems value if source binds its memory to NUMA nodes.
 func CopyCpuset(source, destination string) error {
 	correct, err := cgroups.ReadFile(source, "cpuset.cpus")
 	if err != nil {
@@ -127,5 +129,16 @@ func CopyCpuset(source, destination string) error {
 		return err
 	}
 
+	mems, err := cgroups.ReadFile(source, "cpuset.mems")
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(mems) != "" {
+		if err := cgroups.WriteFile(destination, "cpuset.mems", mems); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
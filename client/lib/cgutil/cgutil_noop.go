@@ -3,6 +3,8 @@
 package cgutil
 
 import (
+	"errors"
+
 	"github.com/hashicorp/go-hclog"
 )
 
@@ -16,6 +18,11 @@ const (
 // This is a read-only value.
 var UseV2 = false
 
+// Rootless is always false on non-Linux systems.
+//
+// This is a read-only value.
+var Rootless = false
+
 // CreateCPUSetManager creates a no-op CpusetManager for non-Linux operating systems.
 func CreateCPUSetManager(string, hclog.Logger) CpusetManager {
 	return new(NoopCpusetManager)
@@ -40,3 +47,13 @@ func GetCPUsFromCgroup(string) ([]uint16, error) {
 func CgroupScope(allocID, task string) string {
 	return ""
 }
+
+// DelegatedCgroup returns an error for non-Linux operating systems.
+func DelegatedCgroup() (string, error) {
+	return "", errors.New("cgroup delegation is only supported on Linux")
+}
+
+// RootlessCgroupParent returns an error for non-Linux operating systems.
+func RootlessCgroupParent(string) (string, error) {
+	return "", errors.New("cgroup delegation is only supported on Linux")
+}
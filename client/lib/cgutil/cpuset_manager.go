@@ -60,7 +60,11 @@ type TaskCgroupInfo struct {
 	CgroupPath         string
 	RelativeCgroupPath string
 	Cpuset             cpuset.CPUSet
-	Error              error
+
+	// Mems is the set of NUMA nodes the memory of the task is bound to. It
+	// is empty when the task may use the memory of any NUMA node.
+	Mems  cpuset.CPUSet
+	Error error
 }
 
 // identity is the "<allocID>.<taskName>" string that uniquely identifies an
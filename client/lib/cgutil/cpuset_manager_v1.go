@@ -78,6 +78,7 @@ func (c *cpusetManagerV1) AddAlloc(alloc *structs.Allocation) {
 			CgroupPath:         cgroupPath,
 			RelativeCgroupPath: relativeCgroupPath,
 			Cpuset:             taskCpuset,
+			Mems:               cpuset.New(resources.Cpu.NUMANodes...),
 		}
 	}
 	c.mu.Lock()
@@ -255,15 +256,20 @@ func (c *cpusetManagerV1) reconcileCpusets() {
 			continue
 		}
 
-		// copy cpuset.mems from parent
-		_, parentMems, err := getCpusetSubsystemSettingsV1(filepath.Dir(info.CgroupPath))
-		if err != nil {
-			c.logger.Error("failed to read parent cgroup settings for task", "path", info.CgroupPath, "error", err)
-			info.Error = err
-			continue
+		// bind the memory of the task to its NUMA nodes, or copy cpuset.mems
+		// from parent if it isn't bound to any
+		mems := info.Mems.String()
+		if info.Mems.Size() == 0 {
+			_, parentMems, err := getCpusetSubsystemSettingsV1(filepath.Dir(info.CgroupPath))
+			if err != nil {
+				c.logger.Error("failed to read parent cgroup settings for task", "path", info.CgroupPath, "error", err)
+				info.Error = err
+				continue
+			}
+			mems = parentMems
 		}
-		if err := cgroups.WriteFile(info.CgroupPath, "cpuset.mems", parentMems); err != nil {
-			c.logger.Error("failed to write cgroup cpuset.mems setting for task", "path", info.CgroupPath, "mems", parentMems, "error", err)
+		if err := cgroups.WriteFile(info.CgroupPath, "cpuset.mems", mems); err != nil {
+			c.logger.Error("failed to write cgroup cpuset.mems setting for task", "path", info.CgroupPath, "mems", mems, "error", err)
 			info.Error = err
 			continue
 		}
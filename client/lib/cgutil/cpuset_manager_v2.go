@@ -31,10 +31,6 @@ const (
 	// in case for e.g. Nomad tasks should be further constrained by an externally
 	// configured systemd cgroup.
 	DefaultCgroupParentV2 = "nomad.slice"
-
-	// rootless is (for now) always false; Nomad clients require root, so we
-	// assume to not need to do the extra plumbing for rootless cgroups.
-	rootless = false
 )
 
 // nothing is used for treating a map like a set with no values
@@ -54,6 +50,7 @@ type cpusetManagerV2 struct {
 	pool      cpuset.CPUSet              // pool of cores being shared among all tasks
 	sharing   map[identity]nothing       // sharing tasks using cores only from the pool
 	isolating map[identity]cpuset.CPUSet // isolating tasks using cores from the pool + reserved cores
+	mems      map[identity]cpuset.CPUSet // NUMA nodes the memory of isolating tasks is bound to
 }
 
 func NewCpusetManagerV2(parent string, logger hclog.Logger) CpusetManager {
@@ -63,6 +60,7 @@ func NewCpusetManagerV2(parent string, logger hclog.Logger) CpusetManager {
 		logger:    logger,
 		sharing:   make(map[identity]nothing),
 		isolating: make(map[identity]cpuset.CPUSet),
+		mems:      make(map[identity]cpuset.CPUSet),
 	}
 }
 
@@ -91,6 +89,9 @@ func (c *cpusetManagerV2) AddAlloc(alloc *structs.Allocation) {
 		id := makeID(alloc.ID, task)
 		if len(resources.Cpu.ReservedCores) > 0 {
 			c.isolating[id] = cpuset.New(resources.Cpu.ReservedCores...)
+			if len(resources.Cpu.NUMANodes) > 0 {
+				c.mems[id] = cpuset.New(resources.Cpu.NUMANodes...)
+			}
 		} else {
 			c.sharing[id] = present
 		}
@@ -123,6 +124,7 @@ func (c *cpusetManagerV2) RemoveAlloc(allocID string) {
 	for id := range c.isolating {
 		if strings.HasPrefix(string(id), allocID) {
 			delete(c.isolating, id)
+			delete(c.mems, id)
 		}
 	}
 
@@ -145,7 +147,7 @@ func (c *cpusetManagerV2) CgroupPathFor(allocID, task string) CgroupPathGetter {
 
 		for {
 			path := c.pathOf(makeID(allocID, task))
-			mgr, err := fs2.NewManager(nil, path, rootless)
+			mgr, err := fs2.NewManager(nil, path, Rootless)
 			if err != nil {
 				return "", err
 			}
@@ -180,11 +182,11 @@ func (c *cpusetManagerV2) recalculate() {
 // must be called while holding c.lock
 func (c *cpusetManagerV2) reconcile() {
 	for id := range c.sharing {
-		c.write(id, c.pool)
+		c.write(id, c.pool, cpuset.New())
 	}
 
 	for id, set := range c.isolating {
-		c.write(id, c.pool.Union(set))
+		c.write(id, c.pool.Union(set), c.mems[id])
 	}
 }
 
@@ -239,7 +241,7 @@ func (c *cpusetManagerV2) pathOf(id identity) string {
 // We avoid removing a cgroup if it still contains a PID, as the cpuset manager
 // may be initially empty on a Nomad client restart.
 func (c *cpusetManagerV2) remove(path string) {
-	mgr, err := fs2.NewManager(nil, path, rootless)
+	mgr, err := fs2.NewManager(nil, path, Rootless)
 	if err != nil {
 		c.logger.Warn("failed to create manager", "path", path, "err", err)
 		return
@@ -262,12 +264,13 @@ func (c *cpusetManagerV2) remove(path string) {
 	}
 }
 
-// write does the actual write of cpuset set for cgroup id
-func (c *cpusetManagerV2) write(id identity, set cpuset.CPUSet) {
+// write does the actual write of cpuset set for cgroup id, binding its memory
+// to the NUMA nodes in mems unless mems is empty
+func (c *cpusetManagerV2) write(id identity, set, mems cpuset.CPUSet) {
 	path := c.pathOf(id)
 
 	// make a manager for the cgroup
-	m, err := fs2.NewManager(nil, path, rootless)
+	m, err := fs2.NewManager(nil, path, Rootless)
 	if err != nil {
 		c.logger.Error("failed to manage cgroup", "path", path, "err", err)
 	}
@@ -280,6 +283,7 @@ func (c *cpusetManagerV2) write(id identity, set cpuset.CPUSet) {
 	// set the cpuset value for the cgroup
 	if err = m.Set(&configs.Resources{
 		CpusetCpus: set.String(),
+		CpusetMems: mems.String(),
 	}); err != nil {
 		c.logger.Error("failed to set cgroup", "path", path, "err", err)
 	}
@@ -288,7 +292,7 @@ func (c *cpusetManagerV2) write(id identity, set cpuset.CPUSet) {
 // ensureParentCgroup will create parent cgroup for the manager if it does not
 // exist yet. No PIDs are added to any cgroup yet.
 func (c *cpusetManagerV2) ensureParent() error {
-	mgr, err := fs2.NewManager(nil, c.parentAbs, rootless)
+	mgr, err := fs2.NewManager(nil, c.parentAbs, Rootless)
 	if err != nil {
 		return err
 	}
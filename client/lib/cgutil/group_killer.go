@@ -109,23 +109,24 @@ func (d *killer) v2(cgroup *configs.Cgroup) error {
 
 	d.logger.Trace("killing processes", "cgroup_path", path, "cgroup_version", "v2", "executor_pid", d.pid, "existing_pids", existingPIDs)
 
-	mgr, err := fs2.NewManager(cgroup, "", rootless)
+	mgr, err := fs2.NewManager(cgroup, "", Rootless)
 	if err != nil {
 		return fmt.Errorf("failed to create v2 cgroup manager: %w", err)
 	}
 
-	// move executor PID into the root init.scope so we can kill the task pids
-	// without killing the executor (which is the process running this code, doing
-	// the killing)
-	init, err := fs2.NewManager(nil, filepath.Join(CgroupRoot, "init.scope"), rootless)
+	// move executor PID into the root init.scope (or the agent cgroup when
+	// rootless) so we can kill the task pids without killing the executor
+	// (which is the process running this code, doing the killing)
+	escape := escapeCgroup(path)
+	init, err := fs2.NewManager(nil, escape, Rootless)
 	if err != nil {
 		return fmt.Errorf("failed to create v2 init cgroup manager: %w", err)
 	}
 	if err = init.Apply(d.pid); err != nil {
-		return fmt.Errorf("failed to move executor pid into init.scope cgroup: %w", err)
+		return fmt.Errorf("failed to move executor pid into %s cgroup: %w", filepath.Base(escape), err)
 	}
 
-	d.logger.Trace("move of executor pid into init.scope complete", "pid", d.pid)
+	d.logger.Trace("move of executor pid out of task cgroup complete", "pid", d.pid, "cgroup", escape)
 
 	// ability to freeze the cgroup
 	freeze := func() {
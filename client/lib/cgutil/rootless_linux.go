@@ -0,0 +1,133 @@
+//go:build linux
+
+package cgutil
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+	"golang.org/x/sys/unix"
+)
+
+// RootlessAgentCgroup is the name of the leaf cgroup a rootless client moves
+// itself into. In cgroups v2 controllers can only be enabled for the children
+// of cgroups without processes, so the client cannot stay in the cgroup
+// delegated to it.
+const RootlessAgentCgroup = "agent.scope"
+
+// rootlessControllers are the controllers a rootless client enables for its
+// children, among the ones delegated to the user running it.
+var rootlessControllers = []string{"cpu", "cpuset", "io", "memory", "pids"}
+
+// Rootless indicates whether Nomad is running as an unprivileged user, in
+// which case cgroups are managed inside the subtree delegated to that user and
+// failures to configure controllers which were not delegated are tolerated.
+//
+// This is a read-only value.
+var Rootless = os.Geteuid() != 0
+
+// DelegatedCgroup returns the cgroup delegated to the user running Nomad,
+// relative to the cgroup root. This is the cgroup of the process, or its
+// parent once a rootless client moved itself into RootlessAgentCgroup.
+func DelegatedCgroup() (string, error) {
+	if !UseV2 {
+		return "", errors.New("cgroup delegation requires cgroups v2")
+	}
+
+	paths, err := cgroups.ParseCgroupFile("/proc/self/cgroup")
+	if err != nil {
+		return "", fmt.Errorf("failed to read cgroup of process: %w", err)
+	}
+	group, ok := paths[""]
+	if !ok {
+		return "", errors.New("failed to find cgroup v2 of process")
+	}
+	if filepath.Base(group) == RootlessAgentCgroup {
+		group = filepath.Dir(group)
+	}
+
+	// the cgroup is delegated if the user may move processes into it and
+	// enable controllers for its children
+	path := fromRoot(group)
+	for _, file := range []string{"cgroup.procs", "cgroup.subtree_control"} {
+		if err := unix.Access(filepath.Join(path, file), unix.W_OK); err != nil {
+			return "", fmt.Errorf("cgroup %q is not delegated to uid %d: %w", group, os.Geteuid(), err)
+		}
+	}
+	return group, nil
+}
+
+// RootlessCgroupParent prepares the cgroup delegated to the user running a
+// rootless client and returns the parent cgroup under which Nomad manages the
+// cgroups of tasks, relative to the cgroup root.
+//
+// The client moves itself into RootlessAgentCgroup and enables the delegated
+// controllers, so that parent is created as a sibling of the client's cgroup.
+func RootlessCgroupParent(parent string) (string, error) {
+	delegated, err := DelegatedCgroup()
+	if err != nil {
+		return "", err
+	}
+
+	leaf := fromRoot(filepath.Join(delegated, RootlessAgentCgroup))
+	if err := os.Mkdir(leaf, 0755); err != nil && !os.IsExist(err) {
+		return "", fmt.Errorf("failed to create agent cgroup: %w", err)
+	}
+	if err := cgroups.WriteCgroupProc(leaf, os.Getpid()); err != nil {
+		return "", fmt.Errorf("failed to move agent into cgroup %q: %w", leaf, err)
+	}
+
+	if err := enableControllers(fromRoot(delegated)); err != nil {
+		return "", err
+	}
+
+	return filepath.Join(delegated, getParentV2(parent)), nil
+}
+
+// enableControllers enables the rootless controllers available in the cgroup
+// at path for its children.
+func enableControllers(path string) error {
+	available, err := cgroups.ReadFile(path, "cgroup.controllers")
+	if err != nil {
+		return fmt.Errorf("failed to read delegated controllers: %w", err)
+	}
+
+	var enable []string
+	for _, controller := range strings.Fields(available) {
+		for _, wanted := range rootlessControllers {
+			if controller == wanted {
+				enable = append(enable, "+"+controller)
+			}
+		}
+	}
+	if len(enable) == 0 {
+		return nil
+	}
+
+	// this fails if processes other than the client are left in the
+	// delegated cgroup
+	if err := cgroups.WriteFile(path, "cgroup.subtree_control", strings.Join(enable, " ")); err != nil {
+		return fmt.Errorf("failed to enable controllers %s: %w", strings.Join(enable, " "), err)
+	}
+	return nil
+}
+
+// escapeCgroup returns the absolute path of the cgroup the executor moves
+// itself into before killing the processes of the task in the cgroup at path.
+// This is the root init.scope, unless Nomad runs rootless and the root cgroup
+// is not delegated, in which case it is the cgroup of the rootless client.
+func escapeCgroup(path string) string {
+	if Rootless {
+		for dir := filepath.Dir(path); strings.HasPrefix(dir, CgroupRoot+"/"); dir = filepath.Dir(dir) {
+			leaf := filepath.Join(dir, RootlessAgentCgroup)
+			if _, err := os.Stat(leaf); err == nil {
+				return leaf
+			}
+		}
+	}
+	return filepath.Join(CgroupRoot, "init.scope")
+}
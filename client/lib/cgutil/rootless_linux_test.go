@@ -0,0 +1,38 @@
+//go:build linux
+
+package cgutil
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/testutil"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func TestRootless_DelegatedCgroup(t *testing.T) {
+	ci.Parallel(t)
+	testutil.CgroupsCompatibleV2(t)
+	if !Rootless {
+		t.Skip("test should be run as non-root user")
+	}
+
+	group, err := DelegatedCgroup()
+	if err != nil {
+		t.Skipf("cgroup of test is not delegated: %v", err)
+	}
+	require.NotEqual(t, RootlessAgentCgroup, filepath.Base(group))
+	require.NoError(t, unix.Access(filepath.Join(CgroupRoot, group, "cgroup.subtree_control"), unix.W_OK))
+}
+
+func TestRootless_escapeCgroup(t *testing.T) {
+	ci.Parallel(t)
+	if Rootless {
+		t.Skip("test should be run as root")
+	}
+
+	path := filepath.Join(CgroupRoot, DefaultCgroupParentV2, CgroupScope("abc", "web"))
+	require.Equal(t, filepath.Join(CgroupRoot, "init.scope"), escapeCgroup(path))
+}
@@ -0,0 +1,176 @@
+// Package numa discovers the NUMA topology of the host from sysfs.
+package numa
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/nomad/lib/cpuset"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// SysfsRoot is the mount point of sysfs on Linux hosts.
+const SysfsRoot = "/sys"
+
+// Scan reads the NUMA topology of the host from the sysfs mounted at root.
+// It returns nil if the host does not expose any NUMA node, which is the case
+// on non-Linux hosts and on kernels built without NUMA support.
+func Scan(root string) (*structs.NodeNUMAResources, error) {
+	nodeDirs, err := filepath.Glob(filepath.Join(root, "devices", "system", "node", "node[0-9]*"))
+	if err != nil {
+		return nil, err
+	}
+	if len(nodeDirs) == 0 {
+		return nil, nil
+	}
+
+	topology := &structs.NodeNUMAResources{
+		Nodes: make([]*structs.NodeNUMANode, 0, len(nodeDirs)),
+	}
+	for _, dir := range nodeDirs {
+		node, err := scanNode(root, dir)
+		if err != nil {
+			return nil, err
+		}
+		topology.Nodes = append(topology.Nodes, node)
+	}
+
+	sort.Slice(topology.Nodes, func(i, j int) bool {
+		return topology.Nodes[i].ID < topology.Nodes[j].ID
+	})
+	return topology, nil
+}
+
+// scanNode reads the cores and memory of the NUMA node found in dir.
+func scanNode(root, dir string) (*structs.NodeNUMANode, error) {
+	id, err := strconv.ParseUint(strings.TrimPrefix(filepath.Base(dir), "node"), 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid NUMA node %q: %v", dir, err)
+	}
+	node := &structs.NodeNUMANode{ID: uint16(id)}
+
+	cpulist, err := readFile(filepath.Join(dir, "cpulist"))
+	if err != nil {
+		return nil, err
+	}
+	cores, err := cpuset.Parse(cpulist)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse cores of NUMA node %d: %v", id, err)
+	}
+	node.Cores = cores.ToSlice()
+
+	if node.MemoryMB, err = nodeMemoryMB(filepath.Join(dir, "meminfo")); err != nil {
+		return nil, err
+	}
+
+	// Memory only NUMA nodes have no core to read the topology of.
+	if len(node.Cores) == 0 {
+		return node, nil
+	}
+
+	cpuDir := filepath.Join(root, "devices", "system", "cpu")
+	socket, err := readFile(filepath.Join(cpuDir, fmt.Sprintf("cpu%d", node.Cores[0]), "topology", "physical_package_id"))
+	if err == nil {
+		if s, err := strconv.ParseUint(socket, 10, 16); err == nil {
+			node.Socket = uint16(s)
+		}
+	}
+
+	// Group the cores of the node by physical core, keeping the order of the
+	// first core of every group.
+	seen := make(map[uint16]struct{}, len(node.Cores))
+	for _, core := range node.Cores {
+		if _, ok := seen[core]; ok {
+			continue
+		}
+
+		siblings := cpuset.New(core)
+		list, err := readFile(filepath.Join(cpuDir, fmt.Sprintf("cpu%d", core), "topology", "thread_siblings_list"))
+		if err == nil {
+			if set, err := cpuset.Parse(list); err == nil {
+				// Only keep the siblings which are part of this node
+				siblings = siblings.Union(set.Intersect(cores))
+			}
+		}
+
+		group := siblings.ToSlice()
+		for _, sibling := range group {
+			seen[sibling] = struct{}{}
+		}
+		node.CoreSiblings = append(node.CoreSiblings, group)
+	}
+
+	return node, nil
+}
+
+// nodeMemoryMB returns the total memory of a NUMA node from its meminfo file,
+// whose lines look like "Node 0 MemTotal:       32768000 kB".
+func nodeMemoryMB(path string) (int64, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		// Not every kernel exposes the memory of NUMA nodes
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || fields[2] != "MemTotal:" {
+			continue
+		}
+		kb, err := strconv.ParseInt(fields[3], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse %q: %v", path, err)
+		}
+		return kb / 1024, nil
+	}
+	return 0, scanner.Err()
+}
+
+// DeviceNode returns the NUMA node the PCI device with the given bus ID is
+// attached to, and whether it is known.
+func DeviceNode(root, pciBusID string) (uint16, bool) {
+	if pciBusID == "" {
+		return 0, false
+	}
+
+	s, err := readFile(filepath.Join(root, "bus", "pci", "devices", normalizePCIBusID(pciBusID), "numa_node"))
+	if err != nil {
+		return 0, false
+	}
+
+	// The kernel reports -1 when the device is not attached to a NUMA node
+	id, err := strconv.ParseUint(s, 10, 16)
+	if err != nil {
+		return 0, false
+	}
+	return uint16(id), true
+}
+
+// normalizePCIBusID converts a PCI bus ID to the form used by sysfs. Device
+// plugins may report the domain on 8 digits and in upper case (for example
+// "00000000:3B:00.0") while sysfs uses "0000:3b:00.0".
+func normalizePCIBusID(id string) string {
+	id = strings.ToLower(id)
+	if domain, rest, ok := strings.Cut(id, ":"); ok && len(domain) > 4 {
+		id = domain[len(domain)-4:] + ":" + rest
+	}
+	return id
+}
+
+func readFile(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
@@ -0,0 +1,143 @@
+package numa
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSysfs creates the files in a fake sysfs rooted at root.
+func writeSysfs(t *testing.T, root string, files map[string]string) {
+	for path, content := range files {
+		full := filepath.Join(root, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0755))
+		require.NoError(t, os.WriteFile(full, []byte(content), 0644))
+	}
+}
+
+// cpuTopology returns the sysfs files describing the socket and the thread
+// siblings of a core.
+func cpuTopology(core, socket, siblings string) map[string]string {
+	dir := filepath.Join("devices", "system", "cpu", "cpu"+core, "topology")
+	return map[string]string{
+		filepath.Join(dir, "physical_package_id"):  socket + "\n",
+		filepath.Join(dir, "thread_siblings_list"): siblings + "\n",
+	}
+}
+
+func TestScan(t *testing.T) {
+	ci.Parallel(t)
+
+	root := t.TempDir()
+	files := map[string]string{
+		"devices/system/node/node0/cpulist": "0-1,4-5\n",
+		"devices/system/node/node0/meminfo": "Node 0 MemTotal:       16777216 kB\nNode 0 MemFree:        1024 kB\n",
+		"devices/system/node/node1/cpulist": "2-3,6-7\n",
+		"devices/system/node/node1/meminfo": "Node 1 MemTotal:       8388608 kB\n",
+		"devices/system/node/online":        "0-1\n",
+	}
+	for _, topology := range []map[string]string{
+		cpuTopology("0", "0", "0,4"),
+		cpuTopology("1", "0", "1,5"),
+		cpuTopology("4", "0", "0,4"),
+		cpuTopology("5", "0", "1,5"),
+		cpuTopology("2", "1", "2,6"),
+		cpuTopology("3", "1", "3,7"),
+		cpuTopology("6", "1", "2,6"),
+		cpuTopology("7", "1", "3,7"),
+	} {
+		for k, v := range topology {
+			files[k] = v
+		}
+	}
+	writeSysfs(t, root, files)
+
+	topology, err := Scan(root)
+	require.NoError(t, err)
+	require.Equal(t, &structs.NodeNUMAResources{
+		Nodes: []*structs.NodeNUMANode{
+			{
+				ID:           0,
+				Socket:       0,
+				Cores:        []uint16{0, 1, 4, 5},
+				CoreSiblings: [][]uint16{{0, 4}, {1, 5}},
+				MemoryMB:     16384,
+			},
+			{
+				ID:           1,
+				Socket:       1,
+				Cores:        []uint16{2, 3, 6, 7},
+				CoreSiblings: [][]uint16{{2, 6}, {3, 7}},
+				MemoryMB:     8192,
+			},
+		},
+	}, topology)
+
+	require.Equal(t, uint16(1), topology.NodeOfCore(6).ID)
+	require.Nil(t, topology.NodeOfCore(8))
+}
+
+func TestScan_NoNUMA(t *testing.T) {
+	ci.Parallel(t)
+
+	topology, err := Scan(t.TempDir())
+	require.NoError(t, err)
+	require.Nil(t, topology)
+}
+
+func TestScan_MemoryOnlyNode(t *testing.T) {
+	ci.Parallel(t)
+
+	root := t.TempDir()
+	files := map[string]string{
+		"devices/system/node/node0/cpulist": "0\n",
+		"devices/system/node/node1/cpulist": "\n",
+		"devices/system/node/node1/meminfo": "Node 1 MemTotal:       1048576 kB\n",
+	}
+	writeSysfs(t, root, files)
+
+	topology, err := Scan(root)
+	require.NoError(t, err)
+	require.Len(t, topology.Nodes, 2)
+
+	// Missing topology files are tolerated
+	require.Equal(t, []uint16{0}, topology.Nodes[0].Cores)
+	require.Equal(t, [][]uint16{{0}}, topology.Nodes[0].CoreSiblings)
+	require.Zero(t, topology.Nodes[0].MemoryMB)
+
+	require.Empty(t, topology.Nodes[1].Cores)
+	require.Nil(t, topology.Nodes[1].CoreSiblings)
+	require.EqualValues(t, 1024, topology.Nodes[1].MemoryMB)
+}
+
+func TestDeviceNode(t *testing.T) {
+	ci.Parallel(t)
+
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"bus/pci/devices/0000:3b:00.0/numa_node": "1\n",
+		"bus/pci/devices/0000:af:00.0/numa_node": "-1\n",
+	})
+
+	node, ok := DeviceNode(root, "0000:3b:00.0")
+	require.True(t, ok)
+	require.Equal(t, uint16(1), node)
+
+	// Bus IDs reported with a long domain in upper case are normalized
+	node, ok = DeviceNode(root, "00000000:3B:00.0")
+	require.True(t, ok)
+	require.Equal(t, uint16(1), node)
+
+	_, ok = DeviceNode(root, "0000:af:00.0")
+	require.False(t, ok)
+
+	_, ok = DeviceNode(root, "0000:00:01.0")
+	require.False(t, ok)
+
+	_, ok = DeviceNode(root, "")
+	require.False(t, ok)
+}
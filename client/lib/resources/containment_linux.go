@@ -36,7 +36,7 @@ func (c *containment) Apply(pid int) error {
 
 	// for v2 use manager to create and enter the cgroup
 	if cgutil.UseV2 {
-		mgr, err := fs2.NewManager(c.cgroup, "", false)
+		mgr, err := fs2.NewManager(c.cgroup, "", cgutil.Rootless)
 		if err != nil {
 			return fmt.Errorf("failed to create v2 cgroup manager for containment: %w", err)
 		}
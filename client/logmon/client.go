@@ -19,13 +19,27 @@ const logmonRPCTimeout = 1 * time.Minute
 
 func (c *logmonClient) Start(cfg *LogConfig) error {
 	req := &proto.StartRequest{
-		LogDir:         cfg.LogDir,
-		StdoutFileName: cfg.StdoutLogFile,
-		StderrFileName: cfg.StderrLogFile,
-		MaxFiles:       uint32(cfg.MaxFiles),
-		MaxFileSizeMb:  uint32(cfg.MaxFileSizeMB),
-		StdoutFifo:     cfg.StdoutFifo,
-		StderrFifo:     cfg.StderrFifo,
+		LogDir:          cfg.LogDir,
+		StdoutFileName:  cfg.StdoutLogFile,
+		StderrFileName:  cfg.StderrLogFile,
+		MaxFiles:        uint32(cfg.MaxFiles),
+		MaxFileSizeMb:   uint32(cfg.MaxFileSizeMB),
+		MaxFileAgeNanos: cfg.MaxFileAge.Nanoseconds(),
+		Compress:        cfg.Compress,
+		MaxAllocSizeMb:  uint32(cfg.MaxAllocSizeMB),
+		StdoutFifo:      cfg.StdoutFifo,
+		StderrFifo:      cfg.StderrFifo,
+		Metadata:        cfg.Metadata,
+	}
+	for _, sink := range cfg.Sinks {
+		req.Sinks = append(req.Sinks, &proto.LogSink{
+			Type:       sink.Type,
+			Address:    sink.Address,
+			Tag:        sink.Tag,
+			Facility:   sink.Facility,
+			BufferSize: uint32(sink.BufferSize),
+			Overflow:   sink.Overflow,
+		})
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), logmonRPCTimeout)
 	defer cancel()
@@ -0,0 +1,98 @@
+package logging
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/hashicorp/go-msgpack/codec"
+)
+
+const (
+	// defaultFluentAddress is the address of the default forward input of
+	// Fluentd and Fluent Bit.
+	defaultFluentAddress = "127.0.0.1:24224"
+
+	// fluentLogKey is the key of the record holding the line.
+	fluentLogKey = "log"
+)
+
+// fluentShipper ships lines to a Fluentd or Fluent Bit forward input, as
+// messages in the message mode of the forward protocol. The record of each
+// line holds the line along with the metadata of the task.
+type fluentShipper struct {
+	address  string
+	tag      string
+	metadata map[string]string
+
+	handle *codec.MsgpackHandle
+	buf    bytes.Buffer
+	conn   net.Conn
+}
+
+func newFluentShipper(cfg *SinkConfig, metadata map[string]string) (*fluentShipper, error) {
+	s := &fluentShipper{
+		address:  defaultFluentAddress,
+		tag:      defaultSinkTag,
+		metadata: metadata,
+		handle:   &codec.MsgpackHandle{},
+	}
+	if cfg.Address != "" {
+		if _, _, err := net.SplitHostPort(cfg.Address); err != nil {
+			return nil, fmt.Errorf("invalid fluent forward address %q: %v", cfg.Address, err)
+		}
+		s.address = cfg.Address
+	}
+	if cfg.Tag != "" {
+		s.tag = cfg.Tag
+	}
+	return s, nil
+}
+
+func (s *fluentShipper) encode(line *sinkLine) ([]byte, error) {
+	record := make(map[string]string, len(s.metadata)+1)
+	for k, v := range s.metadata {
+		record[k] = v
+	}
+	record[fluentLogKey] = string(line.data)
+
+	s.buf.Reset()
+	msg := []interface{}{s.tag, line.time.Unix(), record}
+	if err := codec.NewEncoder(&s.buf, s.handle).Encode(msg); err != nil {
+		return nil, err
+	}
+	return s.buf.Bytes(), nil
+}
+
+func (s *fluentShipper) Ship(line *sinkLine) error {
+	msg, err := s.encode(line)
+	if err != nil {
+		return err
+	}
+
+	if s.conn == nil {
+		conn, err := net.DialTimeout("tcp", s.address, sinkDialTimeout)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+
+	s.conn.SetWriteDeadline(time.Now().Add(sinkWriteTimeout))
+	if _, err := s.conn.Write(msg); err != nil {
+		s.conn.Close()
+		s.conn = nil
+		return err
+	}
+	return nil
+}
+
+func (s *fluentShipper) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
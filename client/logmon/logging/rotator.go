@@ -3,7 +3,9 @@ package logging
 import (
 	"bufio"
 	"bytes"
+	"compress/gzip"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -31,28 +33,61 @@ const (
 
 	// newLineDelimiter is the delimiter used for new lines.
 	newLineDelimiter = '\n'
+
+	// CompressedSuffix is the suffix of the rotated files once compressed.
+	CompressedSuffix = ".gz"
 )
 
+// FileRotatorOptions configures the optional rotation policies of a
+// FileRotator
+type FileRotatorOptions struct {
+	// MaxAge is the age after which the current file is rotated, on the next
+	// write, even if it hasn't reached the maximum size. Zero disables time
+	// based rotation.
+	MaxAge time.Duration
+
+	// Compress enables the gzip compression of rotated files
+	Compress bool
+
+	// MaxTotalSize caps the total size of the log files in the path,
+	// including the files of other rotators. The oldest rotated files are
+	// removed first and the files being written to are never removed. Zero
+	// disables the cap.
+	MaxTotalSize int64
+}
+
 // FileRotator writes bytes to a rotated set of files
 type FileRotator struct {
-	MaxFiles int   // MaxFiles is the maximum number of rotated files allowed in a path
-	FileSize int64 // FileSize is the size a rotated file is allowed to grow
+	MaxFiles     int           // MaxFiles is the maximum number of rotated files allowed in a path
+	FileSize     int64         // FileSize is the size a rotated file is allowed to grow
+	MaxAge       time.Duration // MaxAge is the age after which the current file is rotated
+	Compress     bool          // Compress enables the compression of rotated files
+	MaxTotalSize int64         // MaxTotalSize is the maximum size of the log files in a path
 
 	path             string // path is the path on the file system where the rotated set of files are opened
 	baseFileName     string // baseFileName is the base file name of the rotated files
 	logFileIdx       int    // logFileIdx is the current index of the rotated files
 	oldestLogFileIdx int    // oldestLogFileIdx is the index of the oldest log file in a path
 
-	currentFile *os.File // currentFile is the file that is currently getting written
-	currentWr   int64    // currentWr is the number of bytes written to the current file
-	bufw        *bufio.Writer
-	bufLock     sync.Mutex
+	currentFile  *os.File  // currentFile is the file that is currently getting written
+	currentWr    int64     // currentWr is the number of bytes written to the current file
+	currentStart time.Time // currentStart is the time the current file was opened at
+	bufw         *bufio.Writer
+	bufLock      sync.Mutex
 
 	flushTicker *time.Ticker
 	logger      hclog.Logger
 	purgeCh     chan struct{}
 	doneCh      chan struct{}
 
+	// compressCh is notified of the rotated files queued in toCompress, and
+	// compressDoneCh is closed once the queued files are compressed after
+	// the rotator is closed
+	compressCh     chan struct{}
+	compressDoneCh chan struct{}
+	toCompress     []string
+	compressLock   sync.Mutex
+
 	closed     bool
 	closedLock sync.Mutex
 }
@@ -60,6 +95,13 @@ type FileRotator struct {
 // NewFileRotator returns a new file rotator
 func NewFileRotator(path string, baseFile string, maxFiles int,
 	fileSize int64, logger hclog.Logger) (*FileRotator, error) {
+	return NewFileRotatorWithOptions(path, baseFile, maxFiles, fileSize, nil, logger)
+}
+
+// NewFileRotatorWithOptions returns a new file rotator with the optional
+// rotation policies of opts.
+func NewFileRotatorWithOptions(path string, baseFile string, maxFiles int,
+	fileSize int64, opts *FileRotatorOptions, logger hclog.Logger) (*FileRotator, error) {
 	logger = logger.Named("rotator")
 	rotator := &FileRotator{
 		MaxFiles: maxFiles,
@@ -68,10 +110,17 @@ func NewFileRotator(path string, baseFile string, maxFiles int,
 		path:         path,
 		baseFileName: baseFile,
 
-		flushTicker: time.NewTicker(bufferFlushDuration),
-		logger:      logger,
-		purgeCh:     make(chan struct{}, 1),
-		doneCh:      make(chan struct{}),
+		flushTicker:    time.NewTicker(bufferFlushDuration),
+		logger:         logger,
+		purgeCh:        make(chan struct{}, 1),
+		doneCh:         make(chan struct{}),
+		compressCh:     make(chan struct{}, 1),
+		compressDoneCh: make(chan struct{}),
+	}
+	if opts != nil {
+		rotator.MaxAge = opts.MaxAge
+		rotator.Compress = opts.Compress
+		rotator.MaxTotalSize = opts.MaxTotalSize
 	}
 
 	if err := rotator.lastFile(); err != nil {
@@ -79,6 +128,11 @@ func NewFileRotator(path string, baseFile string, maxFiles int,
 	}
 	go rotator.purgeOldFiles()
 	go rotator.flushPeriodically()
+	if rotator.Compress {
+		go rotator.compressRotatedFiles()
+	} else {
+		close(rotator.compressDoneCh)
+	}
 	return rotator, nil
 }
 
@@ -91,14 +145,18 @@ func (f *FileRotator) Write(p []byte) (n int, err error) {
 	for n < len(p) {
 		// Check if we still have space in the current file, otherwise close and
 		// open the next file
-		if forceRotate || f.currentWr >= f.FileSize {
+		if forceRotate || f.currentWr >= f.FileSize || f.expired() {
 			forceRotate = false
 			f.flushBuffer()
 			f.currentFile.Close()
+			rotated := f.currentFile.Name()
 			if err := f.nextFile(); err != nil {
 				f.logger.Error("error creating next file", "err", err)
 				return 0, err
 			}
+			if f.Compress {
+				f.queueCompression(rotated)
+			}
 		}
 		// Calculate the remaining size on this file and how much we have left
 		// to write
@@ -157,6 +215,12 @@ func (f *FileRotator) Write(p []byte) (n int, err error) {
 	return
 }
 
+// expired returns whether the current file has been written to for longer
+// than the maximum age.
+func (f *FileRotator) expired() bool {
+	return f.MaxAge > 0 && f.currentWr > 0 && time.Since(f.currentStart) >= f.MaxAge
+}
+
 // nextFile opens the next file and purges older files if the number of rotated
 // files is larger than the maximum files configured by the user
 func (f *FileRotator) nextFile() error {
@@ -169,6 +233,9 @@ func (f *FileRotator) nextFile() error {
 				continue
 			}
 		}
+		if _, err := os.Stat(logFileName + CompressedSuffix); err == nil {
+			continue
+		}
 		f.logFileIdx = nextFileIdx
 		if err := f.createFile(); err != nil {
 			return err
@@ -178,7 +245,7 @@ func (f *FileRotator) nextFile() error {
 	// Purge old files if we have more files than MaxFiles
 	f.closedLock.Lock()
 	defer f.closedLock.Unlock()
-	if f.logFileIdx-f.oldestLogFileIdx >= f.MaxFiles && !f.closed {
+	if (f.logFileIdx-f.oldestLogFileIdx >= f.MaxFiles || f.MaxTotalSize > 0) && !f.closed {
 		select {
 		case f.purgeCh <- struct{}{}:
 		default:
@@ -194,28 +261,66 @@ func (f *FileRotator) lastFile() error {
 		return err
 	}
 
-	prefix := fmt.Sprintf("%s.", f.baseFileName)
+	var uncompressed []int
+	lastIdx, lastCompressed := -1, false
 	for _, fi := range finfos {
 		if fi.IsDir() {
 			continue
 		}
-		if strings.HasPrefix(fi.Name(), prefix) {
-			fileIdx := strings.TrimPrefix(fi.Name(), prefix)
-			n, err := strconv.Atoi(fileIdx)
-			if err != nil {
-				continue
-			}
-			if n > f.logFileIdx {
-				f.logFileIdx = n
-			}
+		base, n, compressed, ok := parseLogFileName(fi.Name())
+		if !ok || base != f.baseFileName {
+			continue
+		}
+		if !compressed {
+			uncompressed = append(uncompressed, n)
+		}
+		if n > lastIdx {
+			lastIdx, lastCompressed = n, compressed
+		} else if n == lastIdx && !compressed {
+			lastCompressed = false
+		}
+	}
+	if lastIdx >= 0 {
+		f.logFileIdx = lastIdx
+
+		// Compressed files are never written to again
+		if lastCompressed {
+			f.logFileIdx++
 		}
 	}
 	if err := f.createFile(); err != nil {
 		return err
 	}
+
+	// Compress the files rotated before the rotator was last closed
+	if f.Compress {
+		for _, n := range uncompressed {
+			if n < f.logFileIdx {
+				f.queueCompression(filepath.Join(f.path, fmt.Sprintf("%s.%d", f.baseFileName, n)))
+			}
+		}
+	}
 	return nil
 }
 
+// parseLogFileName returns the base name and index of a rotated file and
+// whether it is compressed. It returns false if the name is not the name of
+// a rotated file.
+func parseLogFileName(name string) (string, int, bool, bool) {
+	compressed := strings.HasSuffix(name, CompressedSuffix)
+	name = strings.TrimSuffix(name, CompressedSuffix)
+
+	idx := strings.LastIndexByte(name, '.')
+	if idx <= 0 {
+		return "", 0, false, false
+	}
+	n, err := strconv.Atoi(name[idx+1:])
+	if err != nil || n < 0 {
+		return "", 0, false, false
+	}
+	return name[:idx], n, compressed, true
+}
+
 // createFile opens a new or existing file for writing
 func (f *FileRotator) createFile() error {
 	logFileName := filepath.Join(f.path, fmt.Sprintf("%s.%d", f.baseFileName, f.logFileIdx))
@@ -230,6 +335,7 @@ func (f *FileRotator) createFile() error {
 		return err
 	}
 	f.currentWr = fi.Size()
+	f.currentStart = time.Now()
 	f.createOrResetBuffer()
 	return nil
 }
@@ -248,10 +354,10 @@ func (f *FileRotator) flushPeriodically() {
 	}
 }
 
-// Close flushes and closes the rotator. It never returns an error.
+// Close flushes and closes the rotator, waiting for the rotated files to be
+// compressed. It never returns an error.
 func (f *FileRotator) Close() error {
 	f.closedLock.Lock()
-	defer f.closedLock.Unlock()
 
 	// Stop the ticker and flush for one last time
 	f.flushTicker.Stop()
@@ -264,7 +370,9 @@ func (f *FileRotator) Close() error {
 		f.closed = true
 		f.currentFile.Close()
 	}
+	f.closedLock.Unlock()
 
+	<-f.compressDoneCh
 	return nil
 }
 
@@ -280,19 +388,29 @@ func (f *FileRotator) purgeOldFiles() {
 				f.logger.Error("error getting directory listing", "err", err)
 				return
 			}
-			// Inserting all the rotated files in a slice
+			// Inserting all the rotated files in a slice, counting the
+			// compressed and uncompressed files of an index once as they
+			// briefly coexist while compressing
+			seen := make(map[int]struct{})
 			for _, fi := range files {
 				if strings.HasPrefix(fi.Name(), f.baseFileName) {
-					fileIdx := strings.TrimPrefix(fi.Name(), fmt.Sprintf("%s.", f.baseFileName))
-					n, err := strconv.Atoi(fileIdx)
-					if err != nil {
-						f.logger.Error("error extracting file index", "err", err)
+					base, n, _, ok := parseLogFileName(fi.Name())
+					if !ok || base != f.baseFileName {
+						f.logger.Error("error extracting file index", "filename", fi.Name())
+						continue
+					}
+					if _, ok := seen[n]; ok {
 						continue
 					}
+					seen[n] = struct{}{}
 					fIndexes = append(fIndexes, n)
 				}
 			}
 
+			if f.MaxTotalSize > 0 {
+				f.purgeTotalSize(files)
+			}
+
 			// Not continuing to delete files if the number of files is not more
 			// than MaxFiles
 			if len(fIndexes) <= f.MaxFiles {
@@ -305,9 +423,11 @@ func (f *FileRotator) purgeOldFiles() {
 			toDelete := fIndexes[0 : len(fIndexes)-f.MaxFiles]
 			for _, fIndex := range toDelete {
 				fname := filepath.Join(f.path, fmt.Sprintf("%s.%d", f.baseFileName, fIndex))
-				err := os.RemoveAll(fname)
-				if err != nil {
-					f.logger.Error("error removing file", "filename", fname, "err", err)
+				for _, name := range []string{fname, fname + CompressedSuffix} {
+					err := os.RemoveAll(name)
+					if err != nil {
+						f.logger.Error("error removing file", "filename", name, "err", err)
+					}
 				}
 			}
 			f.oldestLogFileIdx = fIndexes[0]
@@ -317,6 +437,151 @@ func (f *FileRotator) purgeOldFiles() {
 	}
 }
 
+// purgeTotalSize removes the oldest rotated files in the path, including the
+// files of other rotators, until the log files fit in the maximum total size.
+// The last file of each rotator is being written to and is never removed.
+func (f *FileRotator) purgeTotalSize(files []os.FileInfo) {
+	type logFile struct {
+		info os.FileInfo
+		base string
+		idx  int
+	}
+
+	var logFiles []*logFile
+	var total int64
+	lastIdx := make(map[string]int)
+	for _, fi := range files {
+		if fi.IsDir() {
+			continue
+		}
+		base, n, _, ok := parseLogFileName(fi.Name())
+		if !ok {
+			continue
+		}
+		logFiles = append(logFiles, &logFile{info: fi, base: base, idx: n})
+		total += fi.Size()
+		if last, ok := lastIdx[base]; !ok || n > last {
+			lastIdx[base] = n
+		}
+	}
+	if total <= f.MaxTotalSize {
+		return
+	}
+
+	// Remove the least recently written files first
+	sort.Slice(logFiles, func(i, j int) bool {
+		a, b := logFiles[i], logFiles[j]
+		if !a.info.ModTime().Equal(b.info.ModTime()) {
+			return a.info.ModTime().Before(b.info.ModTime())
+		}
+		return a.idx < b.idx
+	})
+	for _, lf := range logFiles {
+		if total <= f.MaxTotalSize {
+			return
+		}
+		if lf.idx == lastIdx[lf.base] {
+			continue
+		}
+
+		fname := filepath.Join(f.path, lf.info.Name())
+		if err := os.Remove(fname); err != nil && !os.IsNotExist(err) {
+			f.logger.Error("error removing file", "filename", fname, "err", err)
+			continue
+		}
+		total -= lf.info.Size()
+	}
+}
+
+// queueCompression queues the rotated file at path to be compressed.
+func (f *FileRotator) queueCompression(path string) {
+	f.compressLock.Lock()
+	f.toCompress = append(f.toCompress, path)
+	f.compressLock.Unlock()
+
+	select {
+	case f.compressCh <- struct{}{}:
+	default:
+	}
+}
+
+// compressRotatedFiles compresses the queued rotated files until the rotator
+// is closed and the queue is empty.
+func (f *FileRotator) compressRotatedFiles() {
+	defer close(f.compressDoneCh)
+	for {
+		select {
+		case <-f.compressCh:
+		case <-f.doneCh:
+		}
+
+		for {
+			f.compressLock.Lock()
+			if len(f.toCompress) == 0 {
+				f.compressLock.Unlock()
+				break
+			}
+			path := f.toCompress[0]
+			f.toCompress = f.toCompress[1:]
+			f.compressLock.Unlock()
+
+			if err := compressFile(path); err != nil {
+				f.logger.Error("error compressing file", "filename", path, "err", err)
+			}
+		}
+
+		select {
+		case <-f.doneCh:
+			return
+		default:
+		}
+	}
+}
+
+// compressFile replaces the file at path with a gzip compressed copy suffixed
+// with CompressedSuffix. The copy is written to a hidden temporary file first
+// so that readers never see a partial copy.
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// Purged before it could be compressed
+			return nil
+		}
+		return err
+	}
+	defer src.Close()
+
+	tmpPath := filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+CompressedSuffix+".tmp")
+	dst, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpPath)
+
+	gw := gzip.NewWriter(dst)
+	if _, err := io.Copy(gw, src); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+
+	// Don't resurrect a file purged while it was being compressed
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+	if err := os.Rename(tmpPath, path+CompressedSuffix); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
 // flushBuffer flushes the buffer
 func (f *FileRotator) flushBuffer() error {
 	f.bufLock.Lock()
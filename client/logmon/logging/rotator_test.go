@@ -1,12 +1,15 @@
 package logging
 
 import (
+	"compress/gzip"
 	"fmt"
 	"io/ioutil"
 	"math/rand"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/testutil"
@@ -309,3 +312,194 @@ func benchmarkRotatorWithInputSize(size int, b *testing.B) {
 		require.NoError(b, err)
 	}
 }
+
+func TestFileRotator_MaxAge(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	path := t.TempDir()
+
+	opts := &FileRotatorOptions{MaxAge: 50 * time.Millisecond}
+	fr, err := NewFileRotatorWithOptions(path, baseFileName, 10, 1024, opts, testlog.HCLogger(t))
+	require.NoError(t, err)
+	defer fr.Close()
+
+	_, err = fr.Write([]byte("abc\n"))
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(path, "redis.stdout.0"), fr.currentFile.Name())
+
+	// The file is rotated on the first write after it expired
+	time.Sleep(100 * time.Millisecond)
+	_, err = fr.Write([]byte("def\n"))
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(path, "redis.stdout.1"), fr.currentFile.Name())
+}
+
+func TestFileRotator_Compress(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	path := t.TempDir()
+
+	opts := &FileRotatorOptions{Compress: true}
+	fr, err := NewFileRotatorWithOptions(path, baseFileName, 10, 5, opts, testlog.HCLogger(t))
+	require.NoError(t, err)
+
+	_, err = fr.Write([]byte("abcdefgh"))
+	require.NoError(t, err)
+
+	// Closing waits for the rotated files to be compressed
+	require.NoError(t, fr.Close())
+
+	_, err = os.Stat(filepath.Join(path, "redis.stdout.0"))
+	require.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(path, "redis.stdout.1"+CompressedSuffix))
+	require.True(t, os.IsNotExist(err))
+
+	f, err := os.Open(filepath.Join(path, "redis.stdout.0"+CompressedSuffix))
+	require.NoError(t, err)
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	content, err := ioutil.ReadAll(gr)
+	require.NoError(t, err)
+	require.Equal(t, "abcde", string(content))
+
+	// Reopening writes to a new file after the compressed one
+	fr, err = NewFileRotatorWithOptions(path, baseFileName, 10, 5, opts, testlog.HCLogger(t))
+	require.NoError(t, err)
+	defer fr.Close()
+	require.Equal(t, filepath.Join(path, "redis.stdout.1"), fr.currentFile.Name())
+}
+
+func TestFileRotator_CompressOnOpen(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	path := t.TempDir()
+
+	for i := 0; i < 3; i++ {
+		fname := filepath.Join(path, fmt.Sprintf("redis.stdout.%d", i))
+		require.NoError(t, ioutil.WriteFile(fname, []byte("abc"), 0600))
+	}
+	fname := filepath.Join(path, "redis.stdout.3"+CompressedSuffix)
+	require.NoError(t, ioutil.WriteFile(fname, []byte("abc"), 0600))
+
+	// The files rotated before are compressed
+	opts := &FileRotatorOptions{Compress: true}
+	fr, err := NewFileRotatorWithOptions(path, baseFileName, 10, 5, opts, testlog.HCLogger(t))
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(path, "redis.stdout.4"), fr.currentFile.Name())
+	require.NoError(t, fr.Close())
+
+	for i := 0; i < 3; i++ {
+		fname := filepath.Join(path, fmt.Sprintf("redis.stdout.%d", i))
+		_, err := os.Stat(fname + CompressedSuffix)
+		require.NoError(t, err)
+		_, err = os.Stat(fname)
+		require.True(t, os.IsNotExist(err))
+	}
+}
+
+func TestFileRotator_PurgeCompressedFiles(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	path := t.TempDir()
+
+	for i := 0; i < 3; i++ {
+		fname := filepath.Join(path, fmt.Sprintf("redis.stdout.%d%s", i, CompressedSuffix))
+		require.NoError(t, ioutil.WriteFile(fname, []byte("abc"), 0600))
+	}
+
+	fr, err := NewFileRotator(path, baseFileName, 2, 5, testlog.HCLogger(t))
+	require.NoError(t, err)
+	defer fr.Close()
+
+	_, err = fr.Write([]byte("abcdefgh"))
+	require.NoError(t, err)
+
+	testutil.WaitForResult(func() (bool, error) {
+		files, err := ioutil.ReadDir(path)
+		if err != nil {
+			return false, err
+		}
+		var names []string
+		for _, fi := range files {
+			names = append(names, fi.Name())
+		}
+		expected := []string{"redis.stdout.3", "redis.stdout.4"}
+		if !reflect.DeepEqual(names, expected) {
+			return false, fmt.Errorf("expected files %v, found %v", expected, names)
+		}
+		return true, nil
+	}, func(err error) {
+		require.NoError(t, err)
+	})
+}
+
+func TestFileRotator_MaxTotalSize(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	path := t.TempDir()
+
+	// The files of another task count towards the total size
+	now := time.Now()
+	for i, name := range []string{"web.stdout.0", "web.stdout.1", "web.stderr.0"} {
+		fname := filepath.Join(path, name)
+		require.NoError(t, ioutil.WriteFile(fname, []byte("1234567890"), 0600))
+		mtime := now.Add(time.Duration(i-10) * time.Minute)
+		require.NoError(t, os.Chtimes(fname, mtime, mtime))
+	}
+
+	opts := &FileRotatorOptions{MaxTotalSize: 30}
+	fr, err := NewFileRotatorWithOptions(path, baseFileName, 10, 5, opts, testlog.HCLogger(t))
+	require.NoError(t, err)
+	defer fr.Close()
+
+	_, err = fr.Write([]byte("abcdefgh"))
+	require.NoError(t, err)
+
+	// The oldest rotated file is removed while the last files of each
+	// rotator are kept
+	testutil.WaitForResult(func() (bool, error) {
+		files, err := ioutil.ReadDir(path)
+		if err != nil {
+			return false, err
+		}
+		var names []string
+		for _, fi := range files {
+			names = append(names, fi.Name())
+		}
+		expected := []string{"redis.stdout.0", "redis.stdout.1", "web.stderr.0", "web.stdout.1"}
+		if !reflect.DeepEqual(names, expected) {
+			return false, fmt.Errorf("expected files %v, found %v", expected, names)
+		}
+		return true, nil
+	}, func(err error) {
+		require.NoError(t, err)
+	})
+}
+
+func TestParseLogFileName(t *testing.T) {
+	cases := []struct {
+		name       string
+		base       string
+		idx        int
+		compressed bool
+		ok         bool
+	}{
+		{name: "redis.stdout.0", base: "redis.stdout", idx: 0, ok: true},
+		{name: "redis.stdout.12.gz", base: "redis.stdout", idx: 12, compressed: true, ok: true},
+		{name: ".redis.stdout.fifo"},
+		{name: ".redis.stdout.1.gz.tmp"},
+		{name: "redis.stdout.-1"},
+		{name: "12"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			base, idx, compressed, ok := parseLogFileName(tc.name)
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.base, base)
+			require.Equal(t, tc.idx, idx)
+			require.Equal(t, tc.compressed, compressed)
+		})
+	}
+}
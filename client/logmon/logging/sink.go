@@ -0,0 +1,322 @@
+package logging
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+)
+
+const (
+	// SinkTypeFile is the sink writing to rotated files.
+	SinkTypeFile = "file"
+
+	// SinkTypeSyslog is the sink shipping lines to a syslog server.
+	SinkTypeSyslog = "syslog"
+
+	// SinkTypeFluentForward is the sink shipping lines to a Fluentd or Fluent
+	// Bit forward input.
+	SinkTypeFluentForward = "fluent-forward"
+
+	// SinkOverflowBlock blocks writes while the buffer of a sink is full
+	// rather than dropping the lines.
+	SinkOverflowBlock = "block"
+
+	// defaultSinkBufferSize is the number of lines buffered by sinks which do
+	// not set a buffer size.
+	defaultSinkBufferSize = 1000
+
+	// defaultSinkTag is the tag of the lines of sinks which do not set one.
+	defaultSinkTag = "nomad"
+
+	// sinkLineLimit is the maximum size of a line shipped to a sink. Longer
+	// lines are split.
+	sinkLineLimit = 16 * 1024
+
+	// sinkDialTimeout and sinkWriteTimeout bound the time spent connecting
+	// and writing to the destination of a sink.
+	sinkDialTimeout  = 5 * time.Second
+	sinkWriteTimeout = 5 * time.Second
+
+	// sinkRetryInterval is the initial interval between attempts to ship a
+	// line, doubling up to sinkMaxRetryInterval.
+	sinkRetryInterval    = 1 * time.Second
+	sinkMaxRetryInterval = 30 * time.Second
+
+	// sinkCloseTimeout is how long buffered lines are shipped for once the
+	// sink is closed.
+	sinkCloseTimeout = 5 * time.Second
+
+	// sinkDropReportInterval is the interval at which the number of dropped
+	// lines is logged.
+	sinkDropReportInterval = 30 * time.Second
+)
+
+// SinkConfig configures a sink the output of a task is shipped to
+type SinkConfig struct {
+	// Type is the type of the sink
+	Type string
+
+	// Address is the address of the destination
+	Address string
+
+	// Tag is the syslog application name or the Fluent tag of the lines
+	Tag string
+
+	// Facility is the syslog facility of the lines
+	Facility string
+
+	// BufferSize is the number of lines buffered while the destination is
+	// slow or unreachable
+	BufferSize int
+
+	// Overflow is what happens to lines written while the buffer is full
+	Overflow string
+}
+
+// sinkLine is a line of output along with the time it was written at.
+type sinkLine struct {
+	data []byte
+	time time.Time
+}
+
+// shipper sends lines to the destination of a sink. Shippers connect lazily
+// and reconnect on the next line after an error. They are only used from a
+// single goroutine.
+type shipper interface {
+	Ship(*sinkLine) error
+	Close() error
+}
+
+// SinkWriter splits the output of a task into lines and ships them to the
+// destination of a sink in the background, along with the metadata of the
+// task. Lines are buffered while the destination is slow or unreachable and,
+// once the buffer is full, either dropped or written once there is room in
+// it, blocking the writer.
+type SinkWriter struct {
+	logger  hclog.Logger
+	shipper shipper
+	block   bool
+
+	lines   chan *sinkLine
+	dropped uint64
+
+	// partial is the last line written, until its newline is written
+	partial []byte
+	closed  bool
+	lock    sync.Mutex
+
+	closeCh   chan struct{}
+	drainCh   chan struct{}
+	doneCh    chan struct{}
+	closeOnce sync.Once
+}
+
+// NewSinkWriter returns a SinkWriter shipping lines to the sink, along with
+// the metadata. The destination is only connected to once lines are written.
+func NewSinkWriter(cfg *SinkConfig, metadata map[string]string, logger hclog.Logger) (*SinkWriter, error) {
+	var s shipper
+	var err error
+	switch cfg.Type {
+	case SinkTypeSyslog:
+		s, err = newSyslogShipper(cfg, metadata)
+	case SinkTypeFluentForward:
+		s, err = newFluentShipper(cfg, metadata)
+	default:
+		err = fmt.Errorf("unknown sink type %q", cfg.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	bufferSize := cfg.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = defaultSinkBufferSize
+	}
+
+	w := &SinkWriter{
+		logger:  logger.Named("sink").With("type", cfg.Type, "stream", metadata["stream"]),
+		shipper: s,
+		block:   cfg.Overflow == SinkOverflowBlock,
+		lines:   make(chan *sinkLine, bufferSize),
+		closeCh: make(chan struct{}),
+		drainCh: make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+	go w.run()
+	return w, nil
+}
+
+// Write buffers the complete lines of p to be shipped. It never fails so
+// that a failing sink doesn't interrupt the other outputs of the task.
+func (w *SinkWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.closed {
+		return len(p), nil
+	}
+
+	now := time.Now()
+	data := p
+	for len(data) > 0 {
+		idx := bytes.IndexByte(data, newLineDelimiter)
+		if idx < 0 {
+			w.partial = append(w.partial, data...)
+			break
+		}
+
+		w.partial = append(w.partial, data[:idx]...)
+		w.flushPartial(now, true)
+		data = data[idx+1:]
+	}
+
+	// Ship overly long lines in chunks
+	w.flushPartial(now, false)
+	return len(p), nil
+}
+
+// flushPartial sends the partial line in chunks of at most sinkLineLimit. If
+// complete is false, only full chunks are sent and the remainder is kept.
+func (w *SinkWriter) flushPartial(now time.Time, complete bool) {
+	for len(w.partial) >= sinkLineLimit {
+		w.send(w.partial[:sinkLineLimit], now)
+		w.partial = w.partial[sinkLineLimit:]
+	}
+	if !complete {
+		return
+	}
+
+	line := bytes.TrimSuffix(w.partial, []byte{'\r'})
+	if len(line) > 0 {
+		w.send(line, now)
+	}
+	w.partial = w.partial[:0]
+}
+
+// send copies the line into the buffer, dropping it or blocking if the buffer
+// is full, depending on the overflow of the sink.
+func (w *SinkWriter) send(data []byte, now time.Time) {
+	line := &sinkLine{
+		data: append([]byte(nil), data...),
+		time: now,
+	}
+
+	select {
+	case w.lines <- line:
+		return
+	default:
+	}
+
+	if !w.block {
+		atomic.AddUint64(&w.dropped, 1)
+		return
+	}
+
+	select {
+	case w.lines <- line:
+	case <-w.closeCh:
+		atomic.AddUint64(&w.dropped, 1)
+	}
+}
+
+// run ships the buffered lines until the writer is closed.
+func (w *SinkWriter) run() {
+	defer close(w.doneCh)
+	defer w.shipper.Close()
+
+	ticker := time.NewTicker(sinkDropReportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case line := <-w.lines:
+			w.ship(line)
+		case <-ticker.C:
+			w.reportDropped()
+		case <-w.drainCh:
+			w.drain()
+			w.reportDropped()
+			return
+		}
+	}
+}
+
+// ship ships the line, retrying with a backoff until it succeeds or the
+// writer is closed.
+func (w *SinkWriter) ship(line *sinkLine) {
+	backoff := sinkRetryInterval
+	for {
+		err := w.shipper.Ship(line)
+		if err == nil {
+			return
+		}
+
+		w.logger.Warn("failed to ship log line", "error", err, "retry", backoff)
+		select {
+		case <-time.After(backoff):
+		case <-w.closeCh:
+			atomic.AddUint64(&w.dropped, 1)
+			return
+		}
+
+		backoff *= 2
+		if backoff > sinkMaxRetryInterval {
+			backoff = sinkMaxRetryInterval
+		}
+	}
+}
+
+// drain makes a single attempt to ship the lines remaining in the buffer,
+// until the close timeout.
+func (w *SinkWriter) drain() {
+	deadline := time.Now().Add(sinkCloseTimeout)
+	for {
+		select {
+		case line := <-w.lines:
+			if time.Now().After(deadline) {
+				atomic.AddUint64(&w.dropped, uint64(len(w.lines)+1))
+				return
+			}
+			if err := w.shipper.Ship(line); err != nil {
+				w.logger.Warn("failed to ship log lines on close", "error", err)
+				atomic.AddUint64(&w.dropped, uint64(len(w.lines)+1))
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (w *SinkWriter) reportDropped() {
+	if dropped := atomic.SwapUint64(&w.dropped, 0); dropped > 0 {
+		w.logger.Warn("dropped log lines", "dropped", dropped)
+	}
+}
+
+// Close ships the last partial line and the buffered lines, giving up after
+// a timeout.
+func (w *SinkWriter) Close() error {
+	w.closeOnce.Do(func() {
+		// Unblock writes waiting for room in the buffer
+		close(w.closeCh)
+
+		w.lock.Lock()
+		w.flushPartial(time.Now(), true)
+		w.closed = true
+		w.lock.Unlock()
+
+		close(w.drainCh)
+	})
+
+	select {
+	case <-w.doneCh:
+	case <-time.After(2 * sinkCloseTimeout):
+		w.logger.Warn("timed out shipping remaining log lines")
+	}
+	return nil
+}
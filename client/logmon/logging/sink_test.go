@@ -0,0 +1,265 @@
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-msgpack/codec"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// testShipper records the lines shipped to it and fails while err is set.
+type testShipper struct {
+	lock  sync.Mutex
+	lines []string
+	err   error
+}
+
+func (s *testShipper) Ship(line *sinkLine) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.lines = append(s.lines, string(line.data))
+	return nil
+}
+
+func (s *testShipper) Close() error { return nil }
+
+func (s *testShipper) shipped() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]string(nil), s.lines...)
+}
+
+func newTestSinkWriter(t *testing.T, s shipper, bufferSize int, block bool) *SinkWriter {
+	w := &SinkWriter{
+		logger:  testlog.HCLogger(t),
+		shipper: s,
+		block:   block,
+		lines:   make(chan *sinkLine, bufferSize),
+		closeCh: make(chan struct{}),
+		drainCh: make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+func TestSinkWriter_Lines(t *testing.T) {
+	s := &testShipper{}
+	w := newTestSinkWriter(t, s, 10, false)
+
+	_, err := w.Write([]byte("first\nsec"))
+	require.NoError(t, err)
+	_, err = w.Write([]byte("ond\r\n\nthird"))
+	require.NoError(t, err)
+
+	// Empty lines are skipped and the partial line is kept until closed
+	testutil.WaitForResult(func() (bool, error) {
+		lines := s.shipped()
+		return len(lines) == 2, nil
+	}, func(err error) {
+		t.Fatalf("expected 2 lines, got %v", s.shipped())
+	})
+
+	require.NoError(t, w.Close())
+	require.Equal(t, []string{"first", "second", "third"}, s.shipped())
+
+	// Writes after close are discarded
+	n, err := w.Write([]byte("fourth\n"))
+	require.NoError(t, err)
+	require.Equal(t, 7, n)
+	require.Len(t, s.shipped(), 3)
+}
+
+func TestSinkWriter_LongLines(t *testing.T) {
+	s := &testShipper{}
+	w := newTestSinkWriter(t, s, 10, false)
+
+	long := strings.Repeat("a", sinkLineLimit+10)
+	_, err := w.Write([]byte(long + "\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	lines := s.shipped()
+	require.Len(t, lines, 2)
+	require.Len(t, lines[0], sinkLineLimit)
+	require.Len(t, lines[1], 10)
+}
+
+func TestSinkWriter_Drop(t *testing.T) {
+	// The shipper fails so that the buffer fills up
+	s := &testShipper{err: errors.New("unreachable")}
+	w := newTestSinkWriter(t, s, 2, false)
+	defer w.Close()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 10; i++ {
+			w.Write([]byte("line\n"))
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("writes blocked on a full buffer")
+	}
+
+	// At most one line is being shipped and two are buffered
+	require.GreaterOrEqual(t, atomic.LoadUint64(&w.dropped), uint64(7))
+}
+
+func TestSinkWriter_Block(t *testing.T) {
+	s := &testShipper{err: errors.New("unreachable")}
+	w := newTestSinkWriter(t, s, 1, true)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 5; i++ {
+			w.Write([]byte("line\n"))
+		}
+	}()
+
+	select {
+	case <-done:
+		t.Fatalf("writes did not block on a full buffer")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Closing the writer unblocks the writes
+	require.NoError(t, w.Close())
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("writes still blocked after close")
+	}
+}
+
+func TestSyslogShipper_Format(t *testing.T) {
+	metadata := map[string]string{
+		"alloc_id": "1234",
+		"task":     `web"1]`,
+		"stream":   "stderr",
+	}
+	s, err := newSyslogShipper(&SinkConfig{
+		Type:     SinkTypeSyslog,
+		Address:  "tcp://127.0.0.1:514",
+		Tag:      "web",
+		Facility: "local3",
+	}, metadata)
+	require.NoError(t, err)
+	require.Equal(t, "tcp", s.network)
+	require.Equal(t, "127.0.0.1:514", s.address)
+
+	now := time.Date(2022, 1, 2, 3, 4, 5, 6000, time.UTC)
+	msg := string(s.format(&sinkLine{data: []byte("hello"), time: now}))
+
+	// local3 and err are 19*8+3
+	body := `<155>1 2022-01-02T03:04:05.000006Z ` + s.header + ` hello`
+	require.True(t, strings.HasSuffix(s.header, `[nomad@32473 alloc_id="1234" stream="stderr" task="web\"1\]"]`), s.header)
+	require.Contains(t, s.header, " web - stderr ")
+	require.Equal(t, strconv.Itoa(len(body))+" "+body, msg)
+}
+
+func TestSyslogShipper_Invalid(t *testing.T) {
+	_, err := newSyslogShipper(&SinkConfig{Address: "http://127.0.0.1:514"}, nil)
+	require.Error(t, err)
+
+	_, err = newSyslogShipper(&SinkConfig{Facility: "local9"}, nil)
+	require.Error(t, err)
+}
+
+func TestSyslogShipper_UDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	w, err := NewSinkWriter(&SinkConfig{
+		Type:    SinkTypeSyslog,
+		Address: "udp://" + conn.LocalAddr().String(),
+	}, map[string]string{"stream": "stdout"}, testlog.HCLogger(t))
+	require.NoError(t, err)
+	defer w.Close()
+
+	_, err = w.Write([]byte("hello\n"))
+	require.NoError(t, err)
+
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+
+	// user and info are 1*8+6, and datagrams are not framed
+	msg := string(buf[:n])
+	require.True(t, strings.HasPrefix(msg, "<14>1 "), msg)
+	require.True(t, strings.HasSuffix(msg, `nomad - stdout [nomad@32473 stream="stdout"] hello`), msg)
+}
+
+func TestFluentShipper_Forward(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	w, err := NewSinkWriter(&SinkConfig{
+		Type:    SinkTypeFluentForward,
+		Address: ln.Addr().String(),
+		Tag:     "web",
+	}, map[string]string{"alloc_id": "1234", "stream": "stdout"}, testlog.HCLogger(t))
+	require.NoError(t, err)
+	defer w.Close()
+
+	_, err = w.Write([]byte("hello\n"))
+	require.NoError(t, err)
+
+	conn, err := ln.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var msg []interface{}
+	dec := codec.NewDecoder(bufio.NewReader(conn), &codec.MsgpackHandle{RawToString: true})
+	require.NoError(t, dec.Decode(&msg))
+	require.Len(t, msg, 3)
+	require.Equal(t, "web", msg[0])
+
+	record, ok := msg[2].(map[interface{}]interface{})
+	require.True(t, ok, "unexpected record %#v", msg[2])
+	require.Equal(t, "hello", record["log"])
+	require.Equal(t, "1234", record["alloc_id"])
+	require.Equal(t, "stdout", record["stream"])
+}
+
+func TestFluentShipper_Encode(t *testing.T) {
+	s, err := newFluentShipper(&SinkConfig{Type: SinkTypeFluentForward}, map[string]string{"task": "web"})
+	require.NoError(t, err)
+	require.Equal(t, defaultFluentAddress, s.address)
+	require.Equal(t, defaultSinkTag, s.tag)
+
+	now := time.Unix(1641092645, 0)
+	buf, err := s.encode(&sinkLine{data: []byte("hello"), time: now})
+	require.NoError(t, err)
+
+	var msg []interface{}
+	dec := codec.NewDecoder(bytes.NewReader(buf), &codec.MsgpackHandle{RawToString: true})
+	require.NoError(t, dec.Decode(&msg))
+	require.Equal(t, "nomad", msg[0])
+	require.EqualValues(t, 1641092645, msg[1])
+
+	_, err = newFluentShipper(&SinkConfig{Address: "127.0.0.1"}, nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,175 @@
+package logging
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// syslogSDID is the ID of the structured data element carrying the
+	// metadata of the task.
+	syslogSDID = "nomad@32473"
+
+	// syslogTimeFormat is the RFC 5424 timestamp format.
+	syslogTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+
+	// syslogSeverityErr and syslogSeverityInfo are the severities of the lines
+	// written to stderr and stdout.
+	syslogSeverityErr  = 3
+	syslogSeverityInfo = 6
+)
+
+// syslogFacilities maps the names of the syslog facilities to their codes.
+var syslogFacilities = map[string]int{
+	"kern":     0,
+	"user":     1,
+	"mail":     2,
+	"daemon":   3,
+	"auth":     4,
+	"syslog":   5,
+	"lpr":      6,
+	"news":     7,
+	"uucp":     8,
+	"cron":     9,
+	"authpriv": 10,
+	"ftp":      11,
+	"local0":   16,
+	"local1":   17,
+	"local2":   18,
+	"local3":   19,
+	"local4":   20,
+	"local5":   21,
+	"local6":   22,
+	"local7":   23,
+}
+
+// syslogShipper ships lines as RFC 5424 messages carrying the metadata of the
+// task as structured data. Messages sent over stream transports are framed
+// with their length as described in RFC 6587.
+type syslogShipper struct {
+	network string
+	address string
+	framed  bool
+
+	// header is the part of the messages following the priority and timestamp
+	priority int
+	header   string
+
+	conn net.Conn
+}
+
+func newSyslogShipper(cfg *SinkConfig, metadata map[string]string) (*syslogShipper, error) {
+	s := &syslogShipper{
+		network: "unixgram",
+		address: "/dev/log",
+	}
+	if cfg.Address != "" {
+		u, err := url.Parse(cfg.Address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid syslog address %q: %v", cfg.Address, err)
+		}
+		switch u.Scheme {
+		case "udp", "tcp":
+			s.address = u.Host
+		case "unix", "unixgram":
+			s.address = u.Path
+		default:
+			return nil, fmt.Errorf("unsupported syslog address scheme %q", u.Scheme)
+		}
+		s.network = u.Scheme
+	}
+	s.framed = s.network == "tcp" || s.network == "unix"
+
+	facility := "user"
+	if cfg.Facility != "" {
+		facility = cfg.Facility
+	}
+	code, ok := syslogFacilities[facility]
+	if !ok {
+		return nil, fmt.Errorf("unknown syslog facility %q", facility)
+	}
+	severity := syslogSeverityInfo
+	if metadata["stream"] == "stderr" {
+		severity = syslogSeverityErr
+	}
+	s.priority = code*8 + severity
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	tag := defaultSinkTag
+	if cfg.Tag != "" {
+		tag = cfg.Tag
+	}
+	msgID := metadata["stream"]
+	if msgID == "" {
+		msgID = "-"
+	}
+	s.header = fmt.Sprintf("%s %s - %s %s", hostname, tag, msgID, syslogStructuredData(metadata))
+	return s, nil
+}
+
+// syslogStructuredData returns the structured data element carrying the
+// metadata, sorted by key.
+func syslogStructuredData(metadata map[string]string) string {
+	if len(metadata) == 0 {
+		return "-"
+	}
+
+	keys := make([]string, 0, len(metadata))
+	for k := range metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+	var b strings.Builder
+	b.WriteString("[" + syslogSDID)
+	for _, k := range keys {
+		fmt.Fprintf(&b, ` %s="%s"`, k, escaper.Replace(metadata[k]))
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+func (s *syslogShipper) format(line *sinkLine) []byte {
+	msg := fmt.Sprintf("<%d>1 %s %s %s",
+		s.priority, line.time.UTC().Format(syslogTimeFormat), s.header, line.data)
+	if s.framed {
+		msg = fmt.Sprintf("%d %s", len(msg), msg)
+	}
+	return []byte(msg)
+}
+
+func (s *syslogShipper) Ship(line *sinkLine) error {
+	if s.conn == nil {
+		conn, err := net.DialTimeout(s.network, s.address, sinkDialTimeout)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+
+	s.conn.SetWriteDeadline(time.Now().Add(sinkWriteTimeout))
+	if _, err := s.conn.Write(s.format(line)); err != nil {
+		s.conn.Close()
+		s.conn = nil
+		return err
+	}
+	return nil
+}
+
+func (s *syslogShipper) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/nomad/client/lib/fifo"
 	"github.com/hashicorp/nomad/client/logmon/logging"
 )
@@ -41,6 +42,38 @@ type LogConfig struct {
 
 	// MaxFileSizeMB is the max log file size in MB allowed before rotation occures
 	MaxFileSizeMB int
+
+	// MaxFileAge is the age after which log files are rotated regardless of
+	// their size. Zero disables time based rotation.
+	MaxFileAge time.Duration
+
+	// Compress enables the gzip compression of rotated log files
+	Compress bool
+
+	// MaxAllocSizeMB caps the total size of the log files in LogDir. Zero
+	// disables the cap.
+	MaxAllocSizeMB int
+
+	// Sinks are the sinks the logs are shipped to. If empty, logs are only
+	// written to rotated files in LogDir.
+	Sinks []*logging.SinkConfig
+
+	// Metadata is attached to the lines shipped to sinks, along with the
+	// stream they were written to
+	Metadata map[string]string
+}
+
+// writesFiles returns whether logs are written to rotated files in LogDir.
+func (c *LogConfig) writesFiles() bool {
+	if len(c.Sinks) == 0 {
+		return true
+	}
+	for _, sink := range c.Sinks {
+		if sink.Type == logging.SinkTypeFile {
+			return true
+		}
+	}
+	return false
 }
 
 type LogMon interface {
@@ -138,11 +171,9 @@ func (tl *TaskLogger) Close() {
 func NewTaskLogger(cfg *LogConfig, logger hclog.Logger) (*TaskLogger, error) {
 	tl := &TaskLogger{config: cfg}
 
-	logFileSize := int64(cfg.MaxFileSizeMB * 1024 * 1024)
-	lro, err := logging.NewFileRotator(cfg.LogDir, cfg.StdoutLogFile,
-		cfg.MaxFiles, logFileSize, logger)
+	lro, err := newStreamWriter(cfg, "stdout", cfg.StdoutLogFile, logger)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create stdout logfile for %q: %v", cfg.StdoutLogFile, err)
+		return nil, err
 	}
 
 	wrapperOut, err := newLogRotatorWrapper(cfg.StdoutFifo, logger, lro)
@@ -152,10 +183,9 @@ func NewTaskLogger(cfg *LogConfig, logger hclog.Logger) (*TaskLogger, error) {
 
 	tl.lro = wrapperOut
 
-	lre, err := logging.NewFileRotator(cfg.LogDir, cfg.StderrLogFile,
-		cfg.MaxFiles, logFileSize, logger)
+	lre, err := newStreamWriter(cfg, "stderr", cfg.StderrLogFile, logger)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create stderr logfile for %q: %v", cfg.StderrLogFile, err)
+		return nil, err
 	}
 
 	wrapperErr, err := newLogRotatorWrapper(cfg.StderrFifo, logger, lre)
@@ -169,6 +199,78 @@ func NewTaskLogger(cfg *LogConfig, logger hclog.Logger) (*TaskLogger, error) {
 
 }
 
+// newStreamWriter returns the writer of the output of a stream, writing to
+// the rotated log file and shipping to the sinks of the config.
+func newStreamWriter(cfg *LogConfig, stream, logFile string, logger hclog.Logger) (io.WriteCloser, error) {
+	var writers multiWriteCloser
+
+	if cfg.writesFiles() {
+		logFileSize := int64(cfg.MaxFileSizeMB * 1024 * 1024)
+		opts := &logging.FileRotatorOptions{
+			MaxAge:       cfg.MaxFileAge,
+			Compress:     cfg.Compress,
+			MaxTotalSize: int64(cfg.MaxAllocSizeMB) * 1024 * 1024,
+		}
+		rotator, err := logging.NewFileRotatorWithOptions(cfg.LogDir, logFile,
+			cfg.MaxFiles, logFileSize, opts, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s logfile for %q: %v", stream, logFile, err)
+		}
+		writers = append(writers, rotator)
+	}
+
+	metadata := make(map[string]string, len(cfg.Metadata)+1)
+	for k, v := range cfg.Metadata {
+		metadata[k] = v
+	}
+	metadata["stream"] = stream
+
+	for _, sink := range cfg.Sinks {
+		if sink.Type == logging.SinkTypeFile {
+			continue
+		}
+		w, err := logging.NewSinkWriter(sink, metadata, logger)
+		if err != nil {
+			writers.Close()
+			return nil, fmt.Errorf("failed to create %s sink for %s: %v", sink.Type, stream, err)
+		}
+		writers = append(writers, w)
+	}
+
+	// Rotated files are the common case, so avoid the indirection
+	if len(writers) == 1 {
+		return writers[0], nil
+	}
+	return writers, nil
+}
+
+// multiWriteCloser writes to and closes all of its writers. Writes stop at
+// the first error, as with io.MultiWriter.
+type multiWriteCloser []io.WriteCloser
+
+func (m multiWriteCloser) Write(p []byte) (int, error) {
+	for _, w := range m {
+		n, err := w.Write(p)
+		if err != nil {
+			return n, err
+		}
+		if n != len(p) {
+			return n, io.ErrShortWrite
+		}
+	}
+	return len(p), nil
+}
+
+func (m multiWriteCloser) Close() error {
+	var mErr multierror.Error
+	for _, w := range m {
+		if err := w.Close(); err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
 // logRotatorWrapper wraps our log rotator and exposes a pipe that can feed the
 // log rotator data. The processOutWriter should be attached to the process and
 // data will be copied from the reader to the rotator.
@@ -25,16 +25,21 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
 type StartRequest struct {
-	LogDir               string   `protobuf:"bytes,1,opt,name=log_dir,json=logDir,proto3" json:"log_dir,omitempty"`
-	StdoutFileName       string   `protobuf:"bytes,2,opt,name=stdout_file_name,json=stdoutFileName,proto3" json:"stdout_file_name,omitempty"`
-	StderrFileName       string   `protobuf:"bytes,3,opt,name=stderr_file_name,json=stderrFileName,proto3" json:"stderr_file_name,omitempty"`
-	MaxFiles             uint32   `protobuf:"varint,4,opt,name=max_files,json=maxFiles,proto3" json:"max_files,omitempty"`
-	MaxFileSizeMb        uint32   `protobuf:"varint,5,opt,name=max_file_size_mb,json=maxFileSizeMb,proto3" json:"max_file_size_mb,omitempty"`
-	StdoutFifo           string   `protobuf:"bytes,6,opt,name=stdout_fifo,json=stdoutFifo,proto3" json:"stdout_fifo,omitempty"`
-	StderrFifo           string   `protobuf:"bytes,7,opt,name=stderr_fifo,json=stderrFifo,proto3" json:"stderr_fifo,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	LogDir               string            `protobuf:"bytes,1,opt,name=log_dir,json=logDir,proto3" json:"log_dir,omitempty"`
+	StdoutFileName       string            `protobuf:"bytes,2,opt,name=stdout_file_name,json=stdoutFileName,proto3" json:"stdout_file_name,omitempty"`
+	StderrFileName       string            `protobuf:"bytes,3,opt,name=stderr_file_name,json=stderrFileName,proto3" json:"stderr_file_name,omitempty"`
+	MaxFiles             uint32            `protobuf:"varint,4,opt,name=max_files,json=maxFiles,proto3" json:"max_files,omitempty"`
+	MaxFileSizeMb        uint32            `protobuf:"varint,5,opt,name=max_file_size_mb,json=maxFileSizeMb,proto3" json:"max_file_size_mb,omitempty"`
+	StdoutFifo           string            `protobuf:"bytes,6,opt,name=stdout_fifo,json=stdoutFifo,proto3" json:"stdout_fifo,omitempty"`
+	StderrFifo           string            `protobuf:"bytes,7,opt,name=stderr_fifo,json=stderrFifo,proto3" json:"stderr_fifo,omitempty"`
+	Sinks                []*LogSink        `protobuf:"bytes,8,rep,name=sinks,proto3" json:"sinks,omitempty"`
+	Metadata             map[string]string `protobuf:"bytes,9,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	MaxFileAgeNanos      int64             `protobuf:"varint,10,opt,name=max_file_age_nanos,json=maxFileAgeNanos,proto3" json:"max_file_age_nanos,omitempty"`
+	Compress             bool              `protobuf:"varint,11,opt,name=compress,proto3" json:"compress,omitempty"`
+	MaxAllocSizeMb       uint32            `protobuf:"varint,12,opt,name=max_alloc_size_mb,json=maxAllocSizeMb,proto3" json:"max_alloc_size_mb,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *StartRequest) Reset()         { *m = StartRequest{} }
@@ -111,6 +116,120 @@ func (m *StartRequest) GetStderrFifo() string {
 	return ""
 }
 
+func (m *StartRequest) GetSinks() []*LogSink {
+	if m != nil {
+		return m.Sinks
+	}
+	return nil
+}
+
+func (m *StartRequest) GetMetadata() map[string]string {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func (m *StartRequest) GetMaxFileAgeNanos() int64 {
+	if m != nil {
+		return m.MaxFileAgeNanos
+	}
+	return 0
+}
+
+func (m *StartRequest) GetCompress() bool {
+	if m != nil {
+		return m.Compress
+	}
+	return false
+}
+
+func (m *StartRequest) GetMaxAllocSizeMb() uint32 {
+	if m != nil {
+		return m.MaxAllocSizeMb
+	}
+	return 0
+}
+
+type LogSink struct {
+	Type                 string   `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Address              string   `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	Tag                  string   `protobuf:"bytes,3,opt,name=tag,proto3" json:"tag,omitempty"`
+	Facility             string   `protobuf:"bytes,4,opt,name=facility,proto3" json:"facility,omitempty"`
+	BufferSize           uint32   `protobuf:"varint,5,opt,name=buffer_size,json=bufferSize,proto3" json:"buffer_size,omitempty"`
+	Overflow             string   `protobuf:"bytes,6,opt,name=overflow,proto3" json:"overflow,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogSink) Reset()         { *m = LogSink{} }
+func (m *LogSink) String() string { return proto.CompactTextString(m) }
+func (*LogSink) ProtoMessage()    {}
+func (*LogSink) Descriptor() ([]byte, []int) {
+	return fileDescriptor_be72d5e24d2ecba6, []int{1}
+}
+
+func (m *LogSink) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogSink.Unmarshal(m, b)
+}
+func (m *LogSink) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogSink.Marshal(b, m, deterministic)
+}
+func (m *LogSink) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogSink.Merge(m, src)
+}
+func (m *LogSink) XXX_Size() int {
+	return xxx_messageInfo_LogSink.Size(m)
+}
+func (m *LogSink) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogSink.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogSink proto.InternalMessageInfo
+
+func (m *LogSink) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *LogSink) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *LogSink) GetTag() string {
+	if m != nil {
+		return m.Tag
+	}
+	return ""
+}
+
+func (m *LogSink) GetFacility() string {
+	if m != nil {
+		return m.Facility
+	}
+	return ""
+}
+
+func (m *LogSink) GetBufferSize() uint32 {
+	if m != nil {
+		return m.BufferSize
+	}
+	return 0
+}
+
+func (m *LogSink) GetOverflow() string {
+	if m != nil {
+		return m.Overflow
+	}
+	return ""
+}
+
 type StartResponse struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -121,7 +240,7 @@ func (m *StartResponse) Reset()         { *m = StartResponse{} }
 func (m *StartResponse) String() string { return proto.CompactTextString(m) }
 func (*StartResponse) ProtoMessage()    {}
 func (*StartResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_be72d5e24d2ecba6, []int{1}
+	return fileDescriptor_be72d5e24d2ecba6, []int{2}
 }
 
 func (m *StartResponse) XXX_Unmarshal(b []byte) error {
@@ -152,7 +271,7 @@ func (m *StopRequest) Reset()         { *m = StopRequest{} }
 func (m *StopRequest) String() string { return proto.CompactTextString(m) }
 func (*StopRequest) ProtoMessage()    {}
 func (*StopRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_be72d5e24d2ecba6, []int{2}
+	return fileDescriptor_be72d5e24d2ecba6, []int{3}
 }
 
 func (m *StopRequest) XXX_Unmarshal(b []byte) error {
@@ -183,7 +302,7 @@ func (m *StopResponse) Reset()         { *m = StopResponse{} }
 func (m *StopResponse) String() string { return proto.CompactTextString(m) }
 func (*StopResponse) ProtoMessage()    {}
 func (*StopResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_be72d5e24d2ecba6, []int{3}
+	return fileDescriptor_be72d5e24d2ecba6, []int{4}
 }
 
 func (m *StopResponse) XXX_Unmarshal(b []byte) error {
@@ -206,6 +325,8 @@ var xxx_messageInfo_StopResponse proto.InternalMessageInfo
 
 func init() {
 	proto.RegisterType((*StartRequest)(nil), "hashicorp.nomad.client.logmon.proto.StartRequest")
+	proto.RegisterMapType((map[string]string)(nil), "hashicorp.nomad.client.logmon.proto.StartRequest.MetadataEntry")
+	proto.RegisterType((*LogSink)(nil), "hashicorp.nomad.client.logmon.proto.LogSink")
 	proto.RegisterType((*StartResponse)(nil), "hashicorp.nomad.client.logmon.proto.StartResponse")
 	proto.RegisterType((*StopRequest)(nil), "hashicorp.nomad.client.logmon.proto.StopRequest")
 	proto.RegisterType((*StopResponse)(nil), "hashicorp.nomad.client.logmon.proto.StopResponse")
@@ -216,27 +337,41 @@ func init() {
 }
 
 var fileDescriptor_be72d5e24d2ecba6 = []byte{
-	// 320 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x90, 0x31, 0x6f, 0xc2, 0x30,
-	0x10, 0x85, 0x1b, 0x0a, 0xa1, 0x1c, 0x0d, 0x45, 0x5e, 0x1a, 0xd1, 0xa1, 0x28, 0x1d, 0xca, 0x14,
-	0x0a, 0xfd, 0x07, 0x55, 0xd5, 0xa9, 0x74, 0x80, 0xad, 0x4b, 0x64, 0xc0, 0x09, 0x96, 0x62, 0x5f,
-	0x6a, 0x1b, 0x09, 0xb1, 0xf6, 0xd7, 0xf6, 0x5f, 0x54, 0x71, 0x4c, 0x94, 0x11, 0x26, 0xeb, 0xee,
-	0x7d, 0x4f, 0xf7, 0xfc, 0x60, 0xbc, 0xc9, 0x39, 0x93, 0x66, 0x9a, 0x63, 0x26, 0x50, 0x4e, 0x0b,
-	0x85, 0x06, 0xdd, 0x10, 0xdb, 0x81, 0x3c, 0xed, 0xa8, 0xde, 0xf1, 0x0d, 0xaa, 0x22, 0x96, 0x28,
-	0xe8, 0x36, 0xae, 0x1c, 0x71, 0x13, 0x8a, 0x7e, 0x5b, 0x70, 0xbb, 0x32, 0x54, 0x99, 0x25, 0xfb,
-	0xd9, 0x33, 0x6d, 0xc8, 0x3d, 0x74, 0x73, 0xcc, 0x92, 0x2d, 0x57, 0xa1, 0x37, 0xf6, 0x26, 0xbd,
-	0xa5, 0x9f, 0x63, 0xf6, 0xce, 0x15, 0x99, 0xc0, 0x50, 0x9b, 0x2d, 0xee, 0x4d, 0x92, 0xf2, 0x9c,
-	0x25, 0x92, 0x0a, 0x16, 0xb6, 0x2c, 0x31, 0xa8, 0xf6, 0x1f, 0x3c, 0x67, 0x5f, 0x54, 0x30, 0x47,
-	0x32, 0xa5, 0x1a, 0xe4, 0x75, 0x4d, 0x32, 0xa5, 0x6a, 0xf2, 0x01, 0x7a, 0x82, 0x1e, 0x2c, 0xa6,
-	0xc3, 0xf6, 0xd8, 0x9b, 0x04, 0xcb, 0x1b, 0x41, 0x0f, 0xa5, 0xae, 0xc9, 0x33, 0x0c, 0x4f, 0x62,
-	0xa2, 0xf9, 0x91, 0x25, 0x62, 0x1d, 0x76, 0x2c, 0x13, 0x38, 0x66, 0xc5, 0x8f, 0x6c, 0xb1, 0x26,
-	0x8f, 0xd0, 0xaf, 0x93, 0xa5, 0x18, 0xfa, 0xf6, 0x14, 0x9c, 0x42, 0xa5, 0xe8, 0x80, 0x2a, 0x50,
-	0x8a, 0x61, 0xb7, 0x06, 0x6c, 0x96, 0x14, 0xa3, 0x3b, 0x08, 0x5c, 0x09, 0xba, 0x40, 0xa9, 0x59,
-	0x14, 0x40, 0x7f, 0x65, 0xb0, 0x70, 0xa5, 0x44, 0x83, 0xb2, 0xa4, 0x72, 0xac, 0xe4, 0xf9, 0x9f,
-	0x07, 0xfe, 0x27, 0x66, 0x0b, 0x94, 0xa4, 0x80, 0x8e, 0xb5, 0x92, 0x59, 0x7c, 0x46, 0xdf, 0x71,
-	0xb3, 0xeb, 0xd1, 0xfc, 0x12, 0x8b, 0x4b, 0x76, 0x45, 0x04, 0xb4, 0xcb, 0x30, 0xe4, 0xe5, 0x4c,
-	0x77, 0xfd, 0x8d, 0xd1, 0xec, 0x02, 0xc7, 0xe9, 0xdc, 0x5b, 0xf7, 0xbb, 0x63, 0xf7, 0x6b, 0xdf,
-	0x3e, 0xaf, 0xff, 0x01, 0x00, 0x00, 0xff, 0xff, 0xd8, 0xb4, 0x87, 0xfc, 0x7a, 0x02, 0x00, 0x00,
+	// 540 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x52, 0xcd, 0x8e, 0xd3, 0x3c,
+	0x14, 0xfd, 0x32, 0xfd, 0xbf, 0x9d, 0x76, 0xfa, 0x59, 0x48, 0x44, 0x65, 0x41, 0x54, 0x16, 0x04,
+	0x81, 0x32, 0x4c, 0xd9, 0x20, 0x58, 0xa0, 0x19, 0x01, 0xab, 0xe9, 0x2c, 0xd2, 0x1d, 0x2c, 0x2a,
+	0xb7, 0x71, 0x32, 0x56, 0x93, 0xdc, 0x60, 0xbb, 0x43, 0x3b, 0x8f, 0xc3, 0x3b, 0xf1, 0x10, 0xbc,
+	0x05, 0xb2, 0xe3, 0x5a, 0x65, 0xd7, 0xae, 0x92, 0x73, 0x7d, 0xce, 0xf5, 0xb9, 0xc7, 0x17, 0x82,
+	0x55, 0xce, 0x59, 0xa9, 0x2e, 0x73, 0xcc, 0x0a, 0x2c, 0x2f, 0x2b, 0x81, 0x0a, 0x2d, 0x88, 0x0c,
+	0x20, 0x2f, 0xee, 0xa9, 0xbc, 0xe7, 0x2b, 0x14, 0x55, 0x54, 0x62, 0x41, 0x93, 0xa8, 0x56, 0x44,
+	0x87, 0xa4, 0xc9, 0xef, 0x26, 0x9c, 0xcf, 0x15, 0x15, 0x2a, 0x66, 0x3f, 0x36, 0x4c, 0x2a, 0xf2,
+	0x14, 0x3a, 0x39, 0x66, 0x8b, 0x84, 0x0b, 0xdf, 0x0b, 0xbc, 0xb0, 0x17, 0xb7, 0x73, 0xcc, 0x3e,
+	0x73, 0x41, 0x42, 0x18, 0x49, 0x95, 0xe0, 0x46, 0x2d, 0x52, 0x9e, 0xb3, 0x45, 0x49, 0x0b, 0xe6,
+	0x9f, 0x19, 0xc6, 0xb0, 0xae, 0x7f, 0xe5, 0x39, 0xbb, 0xa3, 0x05, 0xb3, 0x4c, 0x26, 0xc4, 0x01,
+	0xb3, 0xe1, 0x98, 0x4c, 0x08, 0xc7, 0x7c, 0x06, 0xbd, 0x82, 0x6e, 0x0d, 0x4d, 0xfa, 0xcd, 0xc0,
+	0x0b, 0x07, 0x71, 0xb7, 0xa0, 0x5b, 0x7d, 0x2e, 0xc9, 0x4b, 0x18, 0xed, 0x0f, 0x17, 0x92, 0x3f,
+	0xb2, 0x45, 0xb1, 0xf4, 0x5b, 0x86, 0x33, 0xb0, 0x9c, 0x39, 0x7f, 0x64, 0xb3, 0x25, 0x79, 0x0e,
+	0x7d, 0xe7, 0x2c, 0x45, 0xbf, 0x6d, 0xae, 0x82, 0xbd, 0xa9, 0x14, 0x2d, 0xa1, 0x36, 0x94, 0xa2,
+	0xdf, 0x71, 0x04, 0xe3, 0x25, 0x45, 0x72, 0x03, 0x2d, 0xc9, 0xcb, 0xb5, 0xf4, 0xbb, 0x41, 0x23,
+	0xec, 0x4f, 0xdf, 0x44, 0x47, 0x44, 0x17, 0xdd, 0x62, 0x36, 0xe7, 0xe5, 0x3a, 0xae, 0xa5, 0xe4,
+	0x3b, 0x74, 0x0b, 0xa6, 0x68, 0x42, 0x15, 0xf5, 0x7b, 0xa6, 0xcd, 0xa7, 0xa3, 0xda, 0x1c, 0xa6,
+	0x1f, 0xcd, 0x6c, 0x87, 0x2f, 0xa5, 0x12, 0xbb, 0xd8, 0x35, 0x24, 0xaf, 0x81, 0xb8, 0x2c, 0x68,
+	0xa6, 0x33, 0x2d, 0x51, 0xfa, 0x10, 0x78, 0x61, 0x23, 0xbe, 0xb0, 0x69, 0x5c, 0x67, 0xec, 0x4e,
+	0x97, 0xc9, 0x18, 0xba, 0x2b, 0x2c, 0x2a, 0xc1, 0xa4, 0xf4, 0xfb, 0x81, 0x17, 0x76, 0x63, 0x87,
+	0xc9, 0x2b, 0xf8, 0x5f, 0x37, 0xa2, 0x79, 0x8e, 0x2b, 0x97, 0xea, 0xb9, 0x49, 0x75, 0x58, 0xd0,
+	0xed, 0xb5, 0xae, 0xd7, 0xb1, 0x8e, 0x3f, 0xc2, 0xe0, 0x1f, 0x3b, 0x64, 0x04, 0x8d, 0x35, 0xdb,
+	0xd9, 0xb5, 0xd0, 0xbf, 0xe4, 0x09, 0xb4, 0x1e, 0x68, 0xbe, 0xd9, 0x2f, 0x42, 0x0d, 0x3e, 0x9c,
+	0xbd, 0xf7, 0x26, 0xbf, 0x3c, 0xe8, 0xd8, 0x80, 0x08, 0x81, 0xa6, 0xda, 0x55, 0xcc, 0x0a, 0xcd,
+	0x3f, 0xf1, 0xa1, 0x43, 0x93, 0xc4, 0x58, 0xac, 0xb5, 0x7b, 0xa8, 0x6f, 0x51, 0x34, 0xb3, 0x0b,
+	0xa3, 0x7f, 0xf5, 0x3c, 0x29, 0x5d, 0xf1, 0x9c, 0xab, 0x9d, 0x59, 0x92, 0x5e, 0xec, 0xb0, 0x7e,
+	0xda, 0xe5, 0x26, 0x4d, 0x99, 0x30, 0xc3, 0xd8, 0xfd, 0x80, 0xba, 0xa4, 0xe7, 0xd0, 0x62, 0x7c,
+	0x60, 0x22, 0xcd, 0xf1, 0xa7, 0xdd, 0x0c, 0x87, 0x27, 0x17, 0x30, 0xb0, 0xe9, 0xcb, 0x0a, 0x4b,
+	0xc9, 0x26, 0x03, 0xe8, 0xcf, 0x15, 0x56, 0xf6, 0x35, 0x26, 0x43, 0x38, 0xaf, 0x61, 0x7d, 0x3c,
+	0xfd, 0xe3, 0x41, 0xfb, 0x16, 0xb3, 0x19, 0x96, 0xa4, 0x82, 0x96, 0x91, 0x92, 0xab, 0x93, 0x1f,
+	0x79, 0x3c, 0x3d, 0x45, 0x62, 0x9d, 0xfd, 0x47, 0x0a, 0x68, 0x6a, 0x33, 0xe4, 0xed, 0x91, 0x6a,
+	0x37, 0xc6, 0xf8, 0xea, 0x04, 0xc5, 0xfe, 0xba, 0x9b, 0xce, 0xb7, 0x96, 0xa9, 0x2f, 0xdb, 0xe6,
+	0xf3, 0xee, 0xef, 0x00, 0xbb, 0x8f, 0xf4, 0x81, 0x71, 0x04, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1,9 +1,12 @@
 package logmon
 
 import (
+	"time"
+
 	"golang.org/x/net/context"
 
 	plugin "github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/nomad/client/logmon/logging"
 	"github.com/hashicorp/nomad/client/logmon/proto"
 )
 
@@ -14,13 +17,27 @@ type logmonServer struct {
 
 func (s *logmonServer) Start(ctx context.Context, req *proto.StartRequest) (*proto.StartResponse, error) {
 	cfg := &LogConfig{
-		LogDir:        req.LogDir,
-		StdoutLogFile: req.StdoutFileName,
-		StderrLogFile: req.StderrFileName,
-		MaxFiles:      int(req.MaxFiles),
-		MaxFileSizeMB: int(req.MaxFileSizeMb),
-		StdoutFifo:    req.StdoutFifo,
-		StderrFifo:    req.StderrFifo,
+		LogDir:         req.LogDir,
+		StdoutLogFile:  req.StdoutFileName,
+		StderrLogFile:  req.StderrFileName,
+		MaxFiles:       int(req.MaxFiles),
+		MaxFileSizeMB:  int(req.MaxFileSizeMb),
+		MaxFileAge:     time.Duration(req.MaxFileAgeNanos),
+		Compress:       req.Compress,
+		MaxAllocSizeMB: int(req.MaxAllocSizeMb),
+		StdoutFifo:     req.StdoutFifo,
+		StderrFifo:     req.StderrFifo,
+		Metadata:       req.Metadata,
+	}
+	for _, sink := range req.Sinks {
+		cfg.Sinks = append(cfg.Sinks, &logging.SinkConfig{
+			Type:       sink.Type,
+			Address:    sink.Address,
+			Tag:        sink.Tag,
+			Facility:   sink.Facility,
+			BufferSize: int(sink.BufferSize),
+			Overflow:   sink.Overflow,
+		})
 	}
 
 	err := s.impl.Start(cfg)
@@ -0,0 +1,253 @@
+package nsd
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// checkOutputMaxSize is the maximum number of bytes of check output which
+	// is stored within the service registration. This mirrors the Consul
+	// agent default and protects Raft from excessively chatty checks.
+	checkOutputMaxSize = 4 * 1024
+
+	// checkHTTPStatusTooManyRequests is the HTTP status code which results in
+	// a warning status, rather than critical, mirroring Consul.
+	checkHTTPStatusTooManyRequests = 429
+)
+
+// checkStatusUpdateFn is the function used by a checkRunner to report a
+// change in status of the check it is executing.
+type checkStatusUpdateFn func(checkID, status, output string) error
+
+// checkRunner periodically executes a single http or tcp check against the
+// address of a Nomad service registration. Script checks are not executed by
+// a checkRunner, as they need to run in the context of the task; the script
+// check hook executes them and reports back via UpdateTTL.
+type checkRunner struct {
+	log hclog.Logger
+
+	// id is the check ID as generated by serviceregistration.MakeCheckID.
+	id    string
+	check *structs.ServiceCheck
+
+	// address is the host:port the check will be executed against.
+	address string
+
+	// status is the last status reported via the update function.
+	status string
+	update checkStatusUpdateFn
+
+	// successes and failures track the number of consecutive results, so the
+	// SuccessBeforePassing and FailuresBeforeCritical thresholds can be
+	// honoured.
+	successes int
+	failures  int
+
+	client *http.Client
+}
+
+// newCheckRunner returns a checkRunner ready to be run. The status is the
+// status the check currently has within the service registration.
+func newCheckRunner(log hclog.Logger, id, address, status string,
+	check *structs.ServiceCheck, update checkStatusUpdateFn) *checkRunner {
+
+	runner := checkRunner{
+		log:     log.With("check_id", id, "check_name", check.Name),
+		id:      id,
+		check:   check,
+		address: address,
+		status:  status,
+		update:  update,
+	}
+
+	if check.Type == structs.ServiceCheckHTTP {
+		runner.client = &http.Client{
+			Timeout: check.Timeout,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: check.TLSSkipVerify},
+			},
+		}
+	}
+
+	return &runner
+}
+
+// run executes the check at the configured interval until the context is
+// cancelled or the shutdown channel is closed.
+func (c *checkRunner) run(ctx context.Context, shutdownCh <-chan struct{}) {
+
+	// Execute the check immediately, rather than waiting for the interval to
+	// pass, so the service becomes healthy as soon as possible.
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-shutdownCh:
+			return
+		case <-timer.C:
+		}
+
+		status, output := c.execute(ctx)
+		c.handleResult(ctx, status, output)
+		timer.Reset(c.check.Interval)
+	}
+}
+
+// execute performs a single execution of the check, returning the resulting
+// status and output.
+func (c *checkRunner) execute(ctx context.Context) (string, string) {
+	switch c.check.Type {
+	case structs.ServiceCheckHTTP:
+		return c.executeHTTP(ctx)
+	case structs.ServiceCheckTCP:
+		return c.executeTCP(ctx)
+	default:
+		return structs.ServiceCheckStatusCritical,
+			fmt.Sprintf("unsupported check type %q", c.check.Type)
+	}
+}
+
+func (c *checkRunner) executeHTTP(ctx context.Context) (string, string) {
+
+	// The check path has been validated as a relative URL, which may include
+	// a query string, during job registration.
+	u, err := url.Parse(c.check.Path)
+	if err != nil {
+		return structs.ServiceCheckStatusCritical, err.Error()
+	}
+	u.Scheme = c.check.Protocol
+	if u.Scheme == "" {
+		u.Scheme = "http"
+	}
+	u.Host = c.address
+
+	method := c.check.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, c.check.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(reqCtx, method, u.String(), strings.NewReader(c.check.Body))
+	if err != nil {
+		return structs.ServiceCheckStatusCritical, err.Error()
+	}
+	for header, values := range c.check.Header {
+		for _, value := range values {
+			req.Header.Add(header, value)
+		}
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return structs.ServiceCheckStatusCritical, err.Error()
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, checkOutputMaxSize))
+	output := fmt.Sprintf("HTTP %s %s: %s Output: %s", method, u.String(), resp.Status, body)
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode <= 299:
+		return structs.ServiceCheckStatusPassing, output
+	case resp.StatusCode == checkHTTPStatusTooManyRequests:
+		return structs.ServiceCheckStatusWarning, output
+	default:
+		return structs.ServiceCheckStatusCritical, output
+	}
+}
+
+func (c *checkRunner) executeTCP(ctx context.Context) (string, string) {
+	dialer := net.Dialer{Timeout: c.check.Timeout}
+
+	conn, err := dialer.DialContext(ctx, "tcp", c.address)
+	if err != nil {
+		return structs.ServiceCheckStatusCritical, err.Error()
+	}
+	_ = conn.Close()
+
+	return structs.ServiceCheckStatusPassing, fmt.Sprintf("TCP connect %s: Success", c.address)
+}
+
+// handleResult applies the success and failure thresholds to the result of a
+// check execution and reports the status if it changed.
+func (c *checkRunner) handleResult(ctx context.Context, status, output string) {
+	switch status {
+	case structs.ServiceCheckStatusPassing:
+		c.failures = 0
+		c.successes++
+		if c.successes < c.check.SuccessBeforePassing {
+			return
+		}
+	case structs.ServiceCheckStatusCritical:
+		c.successes = 0
+		c.failures++
+		if c.failures < c.check.FailuresBeforeCritical {
+			return
+		}
+	default:
+		c.successes, c.failures = 0, 0
+	}
+
+	if status == c.status {
+		return
+	}
+
+	// Do not report the status if the check has been stopped whilst it was
+	// being executed, as the registration has likely been removed.
+	select {
+	case <-ctx.Done():
+		return
+	default:
+	}
+
+	if err := c.update(c.id, status, output); err != nil {
+		c.log.Error("failed to update check status", "error", err)
+		return
+	}
+
+	c.log.Debug("check status changed", "old_status", c.status, "new_status", status)
+	c.status = status
+}
+
+// checkAddress returns the host:port address the check should be executed
+// against. Checks without their own port or address mode use the address of
+// the service registration.
+func checkAddress(check *structs.ServiceCheck, reg *structs.ServiceRegistration,
+	addrFn func(addrMode, portLabel string) (string, int, error)) (string, error) {
+
+	if check.PortLabel == "" && check.AddressMode == "" {
+		return net.JoinHostPort(reg.Address, strconv.Itoa(reg.Port)), nil
+	}
+
+	ip, port, err := addrFn(check.AddressMode, check.PortLabel)
+	if err != nil {
+		return "", fmt.Errorf("unable to get address for check %q: %v", check.Name, err)
+	}
+	return net.JoinHostPort(ip, strconv.Itoa(port)), nil
+}
+
+// truncateCheckOutput ensures the check output does not exceed
+// checkOutputMaxSize.
+func truncateCheckOutput(output string) string {
+	if len(output) > checkOutputMaxSize {
+		return output[:checkOutputMaxSize]
+	}
+	return output
+}
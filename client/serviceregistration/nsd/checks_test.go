@@ -0,0 +1,165 @@
+package nsd
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/serviceregistration"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_checkRunner_execute(t *testing.T) {
+
+	// Set up an HTTP server which responds according to the request path.
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/healthy":
+			w.WriteHeader(http.StatusOK)
+		case "/busy":
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer httpServer.Close()
+	httpAddr := strings.TrimPrefix(httpServer.URL, "http://")
+
+	// Grab a listener address, then close it, so we have an address which is
+	// very unlikely to be listening.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedAddr := l.Addr().String()
+	require.NoError(t, l.Close())
+
+	testCases := []struct {
+		inputCheck     *structs.ServiceCheck
+		inputAddress   string
+		expectedStatus string
+		name           string
+	}{
+		{
+			inputCheck:     &structs.ServiceCheck{Type: "http", Path: "/healthy", Timeout: time.Second},
+			inputAddress:   httpAddr,
+			expectedStatus: structs.ServiceCheckStatusPassing,
+			name:           "http passing",
+		},
+		{
+			inputCheck:     &structs.ServiceCheck{Type: "http", Path: "/busy", Timeout: time.Second},
+			inputAddress:   httpAddr,
+			expectedStatus: structs.ServiceCheckStatusWarning,
+			name:           "http warning",
+		},
+		{
+			inputCheck:     &structs.ServiceCheck{Type: "http", Path: "/broken", Timeout: time.Second},
+			inputAddress:   httpAddr,
+			expectedStatus: structs.ServiceCheckStatusCritical,
+			name:           "http critical",
+		},
+		{
+			inputCheck:     &structs.ServiceCheck{Type: "tcp", Timeout: time.Second},
+			inputAddress:   httpAddr,
+			expectedStatus: structs.ServiceCheckStatusPassing,
+			name:           "tcp passing",
+		},
+		{
+			inputCheck:     &structs.ServiceCheck{Type: "tcp", Timeout: time.Second},
+			inputAddress:   closedAddr,
+			expectedStatus: structs.ServiceCheckStatusCritical,
+			name:           "tcp critical",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			runner := newCheckRunner(hclog.NewNullLogger(), "id", tc.inputAddress,
+				structs.ServiceCheckStatusCritical, tc.inputCheck, nil)
+			actualStatus, _ := runner.execute(context.Background())
+			require.Equal(t, tc.expectedStatus, actualStatus)
+		})
+	}
+}
+
+func Test_checkRunner_handleResult(t *testing.T) {
+
+	var updates []string
+	updateFn := func(_, status, _ string) error {
+		updates = append(updates, status)
+		return nil
+	}
+
+	check := &structs.ServiceCheck{
+		Type:                   "tcp",
+		SuccessBeforePassing:   2,
+		FailuresBeforeCritical: 2,
+	}
+	runner := newCheckRunner(hclog.NewNullLogger(), "id", "127.0.0.1:80",
+		structs.ServiceCheckStatusCritical, check, updateFn)
+
+	ctx := context.Background()
+
+	// The first success should not be reported due to the threshold.
+	runner.handleResult(ctx, structs.ServiceCheckStatusPassing, "")
+	require.Empty(t, updates)
+
+	runner.handleResult(ctx, structs.ServiceCheckStatusPassing, "")
+	require.Equal(t, []string{structs.ServiceCheckStatusPassing}, updates)
+
+	// Subsequent successes do not change the status.
+	runner.handleResult(ctx, structs.ServiceCheckStatusPassing, "")
+	require.Len(t, updates, 1)
+
+	// A single failure should not be reported due to the threshold.
+	runner.handleResult(ctx, structs.ServiceCheckStatusCritical, "")
+	require.Len(t, updates, 1)
+
+	runner.handleResult(ctx, structs.ServiceCheckStatusCritical, "")
+	require.Equal(t, []string{structs.ServiceCheckStatusPassing, structs.ServiceCheckStatusCritical}, updates)
+}
+
+func TestServiceRegistrationHandler_UpdateTTL(t *testing.T) {
+
+	mockRPC := mockRPC{callCounts: map[string]int{}}
+	h := NewServiceRegistrationHandler(hclog.NewNullLogger(), &ServiceRegistrationHandlerCfg{
+		Enabled: true,
+		RPCFn:   mockRPC.RPC,
+	})
+
+	workload := mockWorkload()
+	workload.Services[0].Checks = []*structs.ServiceCheck{
+		{
+			Name:     "script",
+			Type:     structs.ServiceCheckScript,
+			Command:  "/bin/true",
+			Interval: time.Second,
+			Timeout:  time.Second,
+		},
+	}
+	serviceID := serviceregistration.MakeAllocServiceID(workload.AllocID, workload.Name(), workload.Services[0])
+	checkID := serviceregistration.MakeCheckID(serviceID, workload.Services[0].Checks[0])
+
+	// Updating the check before the service is registered should error, so
+	// the script check hook retries.
+	require.Error(t, h.UpdateTTL(checkID, "default", "", structs.ServiceCheckStatusPassing))
+
+	require.NoError(t, h.RegisterWorkload(workload))
+	require.Equal(t, map[string]int{structs.ServiceRegistrationUpsertRPCMethod: 1}, mockRPC.calls())
+
+	// The check starts as critical, so a passing update results in an upsert
+	// whereas a repeated status does not.
+	require.NoError(t, h.UpdateTTL(checkID, "default", "ok", structs.ServiceCheckStatusPassing))
+	require.Equal(t, map[string]int{structs.ServiceRegistrationUpsertRPCMethod: 2}, mockRPC.calls())
+
+	require.NoError(t, h.UpdateTTL(checkID, "default", "ok", structs.ServiceCheckStatusPassing))
+	require.Equal(t, map[string]int{structs.ServiceRegistrationUpsertRPCMethod: 2}, mockRPC.calls())
+
+	// Removing the workload stops tracking the check.
+	h.RemoveWorkload(workload)
+	require.Error(t, h.UpdateTTL(checkID, "default", "", structs.ServiceCheckStatusCritical))
+}
@@ -1,9 +1,11 @@
 package nsd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-multierror"
@@ -25,6 +27,24 @@ type ServiceRegistrationHandler struct {
 	// shutDownCh coordinates shutting down the handler and any long-running
 	// processes, such as the RPC retry.
 	shutDownCh chan struct{}
+
+	// registrations tracks the registrations of services which include
+	// checks, keyed by the service ID. The check statuses within these
+	// objects are the statuses last successfully written to the servers.
+	//
+	// checkRunners tracks the cancel functions of the running http and tcp
+	// checks, keyed by the check ID.
+	//
+	// checksLock must be held when accessing either map.
+	registrations map[string]*structs.ServiceRegistration
+	checkRunners  map[string]context.CancelFunc
+	checksLock    sync.Mutex
+
+	// upsertLock serializes the RPCs which write registrations including
+	// check statuses and the deletion of registrations. This ensures a check
+	// status update cannot overwrite a newer registration, or recreate a
+	// registration which has just been removed.
+	upsertLock sync.Mutex
 }
 
 // ServiceRegistrationHandlerCfg holds critical information used during the
@@ -61,6 +81,8 @@ func NewServiceRegistrationHandler(
 		log:                 log.Named("service_registration.nomad"),
 		registrationEnabled: cfg.Enabled,
 		shutDownCh:          make(chan struct{}),
+		registrations:       make(map[string]*structs.ServiceRegistration),
+		checkRunners:        make(map[string]context.CancelFunc),
 	}
 }
 
@@ -73,20 +95,33 @@ func (s *ServiceRegistrationHandler) RegisterWorkload(workload *serviceregistrat
 		return errors.New(`service registration provider "nomad" not enabled`)
 	}
 
+	// Hold the upsert lock for the duration of the registration, so any check
+	// status update cannot be overwritten by the statuses we read below.
+	s.upsertLock.Lock()
+	defer s.upsertLock.Unlock()
+
 	// Collect all errors generating service registrations.
 	var mErr multierror.Error
 
 	registrations := make([]*structs.ServiceRegistration, len(workload.Services))
+	var runners []*checkRunner
 
 	// Iterate over the services and generate a hydrated registration object for
 	// each. All services are part of a single allocation, therefore we cannot
 	// have one failure without all becoming a failure.
 	for i, serviceSpec := range workload.Services {
 		serviceRegistration, err := s.generateNomadServiceRegistration(serviceSpec, workload)
+		if err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+			continue
+		}
+
+		serviceRunners, err := s.generateCheckRunners(serviceSpec, serviceRegistration, workload)
 		if err != nil {
 			mErr.Errors = append(mErr.Errors, err)
 		} else if mErr.ErrorOrNil() == nil {
 			registrations[i] = serviceRegistration
+			runners = append(runners, serviceRunners...)
 		}
 	}
 
@@ -105,7 +140,12 @@ func (s *ServiceRegistrationHandler) RegisterWorkload(workload *serviceregistrat
 
 	var resp structs.ServiceRegistrationUpsertResponse
 
-	return s.cfg.RPCFn(structs.ServiceRegistrationUpsertRPCMethod, &args, &resp)
+	if err := s.cfg.RPCFn(structs.ServiceRegistrationUpsertRPCMethod, &args, &resp); err != nil {
+		return err
+	}
+
+	s.startChecks(registrations, runners)
+	return nil
 }
 
 // RemoveWorkload iterates the services and removes them from the service
@@ -116,15 +156,22 @@ func (s *ServiceRegistrationHandler) RegisterWorkload(workload *serviceregistrat
 // allocations which, when stopped need their registrations removed.
 func (s *ServiceRegistrationHandler) RemoveWorkload(workload *serviceregistration.WorkloadServices) {
 	for _, serviceSpec := range workload.Services {
-		go s.removeWorkload(workload, serviceSpec)
+
+		// Generate the consistent ID for this service, so we know what to
+		// remove. Stop any checks synchronously, so they do not attempt to
+		// update the registration once removed.
+		id := serviceregistration.MakeAllocServiceID(workload.AllocID, workload.Name(), serviceSpec)
+		s.stopChecks(id)
+
+		go s.removeWorkload(workload, id)
 	}
 }
 
 func (s *ServiceRegistrationHandler) removeWorkload(
-	workload *serviceregistration.WorkloadServices, serviceSpec *structs.Service) {
+	workload *serviceregistration.WorkloadServices, id string) {
 
-	// Generate the consistent ID for this service, so we know what to remove.
-	id := serviceregistration.MakeAllocServiceID(workload.AllocID, workload.Name(), serviceSpec)
+	s.upsertLock.Lock()
+	defer s.upsertLock.Unlock()
 
 	deleteArgs := structs.ServiceRegistrationDeleteByIDRequest{
 		ID: id,
@@ -241,16 +288,17 @@ func (s *ServiceRegistrationHandler) dedupUpdatedWorkload(
 }
 
 // AllocRegistrations is currently a noop implementation as the Nomad provider
-// does not support health check which is the sole subsystem caller of this
-// function.
+// does not expose check statuses to the allocation health tracker which is the
+// sole subsystem caller of this function.
 func (s *ServiceRegistrationHandler) AllocRegistrations(_ string) (*serviceregistration.AllocRegistration, error) {
 	return nil, nil
 }
 
-// UpdateTTL is currently a noop implementation as the Nomad provider does not
-// support health check which is the sole subsystem caller of this function.
-func (s *ServiceRegistrationHandler) UpdateTTL(_, _, _, _ string) error {
-	return nil
+// UpdateTTL is used by the script check hook to report the result of a script
+// check execution. The status is written to the service registration if it
+// has changed.
+func (s *ServiceRegistrationHandler) UpdateTTL(id, _, output, status string) error {
+	return s.updateCheckStatus(id, status, output)
 }
 
 // Shutdown is used to initiate shutdown of the handler. This is specifically
@@ -289,8 +337,10 @@ func (s *ServiceRegistrationHandler) generateNomadServiceRegistration(
 		copy(tags, serviceSpec.Tags)
 	}
 
+	id := serviceregistration.MakeAllocServiceID(workload.AllocID, workload.Name(), serviceSpec)
+
 	return &structs.ServiceRegistration{
-		ID:          serviceregistration.MakeAllocServiceID(workload.AllocID, workload.Name(), serviceSpec),
+		ID:          id,
 		ServiceName: serviceSpec.Name,
 		NodeID:      s.cfg.NodeID,
 		JobID:       workload.JobID,
@@ -300,5 +350,228 @@ func (s *ServiceRegistrationHandler) generateNomadServiceRegistration(
 		Tags:        tags,
 		Address:     ip,
 		Port:        port,
+		Checks:      s.generateChecks(id, serviceSpec),
 	}, nil
 }
+
+// generateChecks builds the check status objects for the service
+// registration. Checks which are already known to the handler retain their
+// current status, otherwise the check initial status is used, defaulting to
+// critical.
+func (s *ServiceRegistrationHandler) generateChecks(
+	serviceID string, serviceSpec *structs.Service) []*structs.ServiceRegistrationCheck {
+
+	if len(serviceSpec.Checks) == 0 {
+		return nil
+	}
+
+	s.checksLock.Lock()
+	existing := s.registrations[serviceID]
+	s.checksLock.Unlock()
+
+	checks := make([]*structs.ServiceRegistrationCheck, len(serviceSpec.Checks))
+
+	for i, checkSpec := range serviceSpec.Checks {
+		check := &structs.ServiceRegistrationCheck{
+			ID:     serviceregistration.MakeCheckID(serviceID, checkSpec),
+			Name:   checkSpec.Name,
+			Type:   checkSpec.Type,
+			Status: checkSpec.InitialStatus,
+		}
+		if check.Status == "" {
+			check.Status = structs.ServiceCheckStatusCritical
+		}
+
+		if existing != nil {
+			for _, existingCheck := range existing.Checks {
+				if existingCheck.ID == check.ID {
+					check.Status = existingCheck.Status
+					check.Output = existingCheck.Output
+				}
+			}
+		}
+		checks[i] = check
+	}
+
+	return checks
+}
+
+// generateCheckRunners builds, but does not start, the runners for the http
+// and tcp checks of the service registration.
+func (s *ServiceRegistrationHandler) generateCheckRunners(
+	serviceSpec *structs.Service, reg *structs.ServiceRegistration,
+	workload *serviceregistration.WorkloadServices) ([]*checkRunner, error) {
+
+	// addrFn resolves the address of checks which specify their own port or
+	// address mode, falling back to those of the service.
+	addrFn := func(addrMode, portLabel string) (string, int, error) {
+		if addrMode == "" {
+			addrMode = serviceSpec.AddressMode
+		}
+		if addrMode == "" {
+			addrMode = structs.AddressModeAuto
+		}
+		if portLabel == "" {
+			portLabel = serviceSpec.PortLabel
+		}
+		return serviceregistration.GetAddress(
+			"", addrMode, portLabel, workload.Networks,
+			workload.DriverNetwork, workload.Ports, workload.NetworkStatus)
+	}
+
+	var runners []*checkRunner
+
+	for i, checkSpec := range serviceSpec.Checks {
+		if checkSpec.Type == structs.ServiceCheckScript {
+			continue
+		}
+
+		address, err := checkAddress(checkSpec, reg, addrFn)
+		if err != nil {
+			return nil, err
+		}
+
+		check := reg.Checks[i]
+		runners = append(runners, newCheckRunner(
+			s.log, check.ID, address, check.Status, checkSpec, s.updateCheckStatus))
+	}
+
+	return runners, nil
+}
+
+// startChecks tracks the registrations which include checks and starts the
+// passed check runners unless they are already running. Checks which are no
+// longer part of a tracked registration are stopped.
+func (s *ServiceRegistrationHandler) startChecks(
+	registrations []*structs.ServiceRegistration, runners []*checkRunner) {
+
+	s.checksLock.Lock()
+	defer s.checksLock.Unlock()
+
+	for _, reg := range registrations {
+
+		// Stop any checks of the previous version of the registration which
+		// have been removed or modified.
+		if existing, ok := s.registrations[reg.ID]; ok {
+			for _, existingCheck := range existing.Checks {
+				if !registrationHasCheck(reg, existingCheck.ID) {
+					s.stopCheckLocked(existingCheck.ID)
+				}
+			}
+		}
+
+		if len(reg.Checks) == 0 {
+			delete(s.registrations, reg.ID)
+			continue
+		}
+		s.registrations[reg.ID] = reg.Copy()
+	}
+
+	for _, runner := range runners {
+		if _, ok := s.checkRunners[runner.id]; ok {
+			continue
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		s.checkRunners[runner.id] = cancel
+		go runner.run(ctx, s.shutDownCh)
+	}
+}
+
+// stopChecks stops all the checks of the service registration and stops
+// tracking it.
+func (s *ServiceRegistrationHandler) stopChecks(serviceID string) {
+	s.checksLock.Lock()
+	defer s.checksLock.Unlock()
+
+	reg, ok := s.registrations[serviceID]
+	if !ok {
+		return
+	}
+	for _, check := range reg.Checks {
+		s.stopCheckLocked(check.ID)
+	}
+	delete(s.registrations, serviceID)
+}
+
+// stopCheckLocked stops the runner of the check, if it has one. The caller
+// must hold checksLock.
+func (s *ServiceRegistrationHandler) stopCheckLocked(checkID string) {
+	if cancel, ok := s.checkRunners[checkID]; ok {
+		cancel()
+		delete(s.checkRunners, checkID)
+	}
+}
+
+// updateCheckStatus writes the status of the check to the service
+// registration it belongs to, if the status has changed. An error is returned
+// if the check is not part of a tracked registration, which allows script
+// checks to retry whilst the registration is in flight.
+func (s *ServiceRegistrationHandler) updateCheckStatus(checkID, status, output string) error {
+	s.upsertLock.Lock()
+	defer s.upsertLock.Unlock()
+
+	output = truncateCheckOutput(output)
+
+	s.checksLock.Lock()
+	reg, idx := s.findCheckLocked(checkID)
+	if reg == nil {
+		s.checksLock.Unlock()
+		return fmt.Errorf("check %q not found", checkID)
+	}
+	if reg.Checks[idx].Status == status {
+		s.checksLock.Unlock()
+		return nil
+	}
+
+	regCopy := reg.Copy()
+	regCopy.Checks[idx].Status = status
+	regCopy.Checks[idx].Output = output
+	s.checksLock.Unlock()
+
+	args := structs.ServiceRegistrationUpsertRequest{
+		Services: []*structs.ServiceRegistration{regCopy},
+		WriteRequest: structs.WriteRequest{
+			Region:    s.cfg.Region,
+			AuthToken: s.cfg.NodeSecret,
+		},
+	}
+
+	var resp structs.ServiceRegistrationUpsertResponse
+
+	if err := s.cfg.RPCFn(structs.ServiceRegistrationUpsertRPCMethod, &args, &resp); err != nil {
+		return err
+	}
+
+	// Only store the updated registration if it is still being tracked; it
+	// may have been removed whilst the RPC was in flight.
+	s.checksLock.Lock()
+	if _, ok := s.registrations[regCopy.ID]; ok {
+		s.registrations[regCopy.ID] = regCopy
+	}
+	s.checksLock.Unlock()
+
+	return nil
+}
+
+// findCheckLocked returns the tracked registration containing the check and
+// the index of the check within it. The caller must hold checksLock.
+func (s *ServiceRegistrationHandler) findCheckLocked(checkID string) (*structs.ServiceRegistration, int) {
+	for _, reg := range s.registrations {
+		for i, check := range reg.Checks {
+			if check.ID == checkID {
+				return reg, i
+			}
+		}
+	}
+	return nil, 0
+}
+
+// registrationHasCheck returns whether the registration includes the check.
+func registrationHasCheck(reg *structs.ServiceRegistration, checkID string) bool {
+	for _, check := range reg.Checks {
+		if check.ID == checkID {
+			return true
+		}
+	}
+	return false
+}
@@ -137,3 +137,16 @@ func (h *HandlerWrapper) UpdateWorkload(old, new *serviceregistration.WorkloadSe
 
 	return nil
 }
+
+// ProviderHandler returns the handler for the passed provider. This is used by
+// callers which need to interact with a single provider directly, such as the
+// script check hook updating check statuses. Unknown providers result in the
+// Consul handler being returned, matching the RemoveWorkload default.
+func (h *HandlerWrapper) ProviderHandler(provider string) serviceregistration.Handler {
+	switch provider {
+	case structs.ServiceProviderNomad:
+		return h.nomadServiceProvider
+	default:
+		return h.consulServiceProvider
+	}
+}
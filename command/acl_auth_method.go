@@ -0,0 +1,133 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+)
+
+// Ensure ACLAuthMethodCommand satisfies the cli.Command interface.
+var _ cli.Command = &ACLAuthMethodCommand{}
+
+// ACLAuthMethodCommand implements cli.Command.
+type ACLAuthMethodCommand struct {
+	Meta
+}
+
+// Help satisfies the cli.Command Help function.
+func (a *ACLAuthMethodCommand) Help() string {
+	helpText := `
+Usage: nomad acl auth-method <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with ACL auth methods.
+  Auth methods allow users to log in to Nomad using an external identity
+  provider such as an OIDC provider, and receive an ACL token whose roles and
+  policies are determined by the binding rules of the auth method.
+
+  Create an ACL auth method:
+
+      $ nomad acl auth-method create -name="name" -max-token-ttl="1h" \
+          -config=@config.json
+
+  List all ACL auth methods:
+
+      $ nomad acl auth-method list
+
+  Lookup a specific ACL auth method:
+
+      $ nomad acl auth-method info <acl_auth_method_name>
+
+  Update an ACL auth method:
+
+      $ nomad acl auth-method update -default=true <acl_auth_method_name>
+
+  Delete an ACL auth method:
+
+      $ nomad acl auth-method delete <acl_auth_method_name>
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+// Synopsis satisfies the cli.Command Synopsis function.
+func (a *ACLAuthMethodCommand) Synopsis() string { return "Interact with ACL auth methods" }
+
+// Name returns the name of this command.
+func (a *ACLAuthMethodCommand) Name() string { return "acl auth-method" }
+
+// Run satisfies the cli.Command Run function.
+func (a *ACLAuthMethodCommand) Run(_ []string) int { return cli.RunResultHelp }
+
+// formatAuthMethod formats and converts the ACL auth method API object into a
+// string KV representation suitable for console output.
+func formatAuthMethod(authMethod *api.ACLAuthMethod) string {
+	out := []string{
+		fmt.Sprintf("Name|%s", authMethod.Name),
+		fmt.Sprintf("Type|%s", authMethod.Type),
+		fmt.Sprintf("Locality|%s", authMethod.TokenLocality),
+		fmt.Sprintf("Max Token TTL|%s", authMethod.MaxTokenTTL.String()),
+		fmt.Sprintf("Default|%t", authMethod.Default),
+		fmt.Sprintf("Create Index|%d", authMethod.CreateIndex),
+		fmt.Sprintf("Modify Index|%d", authMethod.ModifyIndex),
+	}
+	return formatKV(out)
+}
+
+// formatAuthMethodConfig formats and converts the ACL auth method config API
+// object into a string KV representation suitable for console output. The
+// client secret is never included.
+func formatAuthMethodConfig(config *api.ACLAuthMethodConfig) string {
+	if config == nil {
+		return "<none>"
+	}
+	out := []string{
+		fmt.Sprintf("OIDC Discovery URL|%s", config.OIDCDiscoveryURL),
+		fmt.Sprintf("OIDC Client ID|%s", config.OIDCClientID),
+		fmt.Sprintf("OIDC Scopes|%s", strings.Join(config.OIDCScopes, ",")),
+		fmt.Sprintf("Bound audiences|%s", strings.Join(config.BoundAudiences, ",")),
+		fmt.Sprintf("Allowed redirects URIs|%s", strings.Join(config.AllowedRedirectURIs, ",")),
+		fmt.Sprintf("Discovery CA pem|%s", strings.Join(config.DiscoveryCaPem, ",")),
+		fmt.Sprintf("Signing algorithms|%s", strings.Join(config.SigningAlgs, ",")),
+		fmt.Sprintf("Claim mappings|%s", formatStringMap(config.ClaimMappings)),
+		fmt.Sprintf("List claim mappings|%s", formatStringMap(config.ListClaimMappings)),
+	}
+	return formatKV(out)
+}
+
+// formatStringMap formats a map of strings as a sorted, comma separated list
+// of key/value pairs.
+func formatStringMap(m map[string]string) string {
+	out := make([]string, 0, len(m))
+	for k, v := range m {
+		out = append(out, fmt.Sprintf("{%s: %s}", k, v))
+	}
+	sort.Strings(out)
+	return strings.Join(out, "; ")
+}
+
+// parseAuthMethodConfig parses the auth method configuration passed to the
+// -config flag. The value is a JSON object, or a path to a file containing
+// one when prefixed with "@".
+func parseAuthMethodConfig(raw string) (*api.ACLAuthMethodConfig, error) {
+	data := []byte(raw)
+
+	if strings.HasPrefix(raw, "@") {
+		fileData, err := ioutil.ReadFile(strings.TrimPrefix(raw, "@"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %v", err)
+		}
+		data = fileData
+	}
+
+	var config api.ACLAuthMethodConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %v", err)
+	}
+	return &config, nil
+}
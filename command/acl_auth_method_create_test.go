@@ -0,0 +1,75 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLAuthMethodCreateCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	// Build a test server with ACLs enabled.
+	srv, _, url := testServer(t, false, func(c *agent.Config) {
+		c.ACL.Enabled = true
+	})
+	defer srv.Shutdown()
+
+	// Ensure we have a bootstrap token.
+	rootACLToken := srv.RootToken
+	require.NotNil(t, rootACLToken)
+
+	ui := cli.NewMockUi()
+	cmd := &ACLAuthMethodCreateCommand{
+		Meta: Meta{
+			Ui:          ui,
+			flagAddress: url,
+		},
+	}
+
+	// Test the basic validation on the command.
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url, "this-command-does-not-take-args"}))
+	require.Contains(t, ui.ErrorWriter.String(), "This command takes no arguments")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url}))
+	require.Contains(t, ui.ErrorWriter.String(), "ACL auth method name must be specified using the -name flag")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url, "-name=acl-auth-method-cli-test"}))
+	require.Contains(t, ui.ErrorWriter.String(), "ACL auth method max token TTL must be specified using the -max-token-ttl flag")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url, "-name=acl-auth-method-cli-test", "-max-token-ttl=1h"}))
+	require.Contains(t, ui.ErrorWriter.String(), "ACL auth method config must be specified using the -config flag")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Create an ACL auth method.
+	args := []string{
+		"-address=" + url, "-token=" + rootACLToken.SecretID, "-name=acl-auth-method-cli-test",
+		"-max-token-ttl=1h", "-default",
+		`-config={"OIDCDiscoveryURL":"http://example.com","OIDCClientID":"mock","OIDCClientSecret":"secret","AllowedRedirectURIs":["http://localhost:4649/oidc/callback"]}`,
+	}
+	require.Equal(t, 0, cmd.Run(args))
+	s := ui.OutputWriter.String()
+	require.Contains(t, s, "Name          = acl-auth-method-cli-test")
+	require.Contains(t, s, "Type          = OIDC")
+	require.Contains(t, s, "Locality      = local")
+	require.Contains(t, s, "Max Token TTL = 1h0m0s")
+	require.Contains(t, s, "Default       = true")
+	require.Contains(t, s, "OIDC Discovery URL     = http://example.com")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+}
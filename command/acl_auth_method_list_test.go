@@ -0,0 +1,59 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLAuthMethodListCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	// Build a test server with ACLs enabled.
+	srv, _, url := testServer(t, false, func(c *agent.Config) {
+		c.ACL.Enabled = true
+	})
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	cmd := &ACLAuthMethodListCommand{
+		Meta: Meta{
+			Ui:          ui,
+			flagAddress: url,
+		},
+	}
+
+	// Perform a list straight away without any auth methods held in state.
+	// Listing auth methods does not require a token.
+	require.Equal(t, 0, cmd.Run([]string{"-address=" + url}))
+	require.Contains(t, ui.OutputWriter.String(), "No ACL auth methods found")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Create an ACL auth method.
+	authMethod := mock.ACLAuthMethod()
+	authMethod.Default = true
+	require.NoError(t, srv.Agent.Server().State().UpsertACLAuthMethods(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLAuthMethod{authMethod}))
+
+	// Perform a listing to get the created auth method.
+	require.Equal(t, 0, cmd.Run([]string{"-address=" + url}))
+	s := ui.OutputWriter.String()
+	require.Contains(t, s, "Name")
+	require.Contains(t, s, "Default")
+	require.Contains(t, s, authMethod.Name)
+	require.Contains(t, s, "true")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// List the auth methods in JSON format.
+	require.Equal(t, 0, cmd.Run([]string{"-address=" + url, "-json"}))
+	require.Contains(t, ui.OutputWriter.String(), "CreateIndex")
+}
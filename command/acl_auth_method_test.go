@@ -0,0 +1,72 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_formatAuthMethod(t *testing.T) {
+	ci.Parallel(t)
+
+	inputAuthMethod := api.ACLAuthMethod{
+		Name:          "this-is-my-friendly-name",
+		Type:          "OIDC",
+		TokenLocality: "global",
+		MaxTokenTTL:   time.Hour,
+		Default:       true,
+		CreateIndex:   13,
+		ModifyIndex:   1313,
+	}
+	expectedOutput := "Name          = this-is-my-friendly-name\nType          = OIDC\nLocality      = global\nMax Token TTL = 1h0m0s\nDefault       = true\nCreate Index  = 13\nModify Index  = 1313"
+	actualOutput := formatAuthMethod(&inputAuthMethod)
+	require.Equal(t, expectedOutput, actualOutput)
+}
+
+func Test_formatAuthMethodConfig(t *testing.T) {
+	ci.Parallel(t)
+
+	require.Equal(t, "<none>", formatAuthMethodConfig(nil))
+
+	inputConfig := api.ACLAuthMethodConfig{
+		OIDCDiscoveryURL:    "http://example.com",
+		OIDCClientID:        "mock",
+		OIDCClientSecret:    "very secret secret",
+		BoundAudiences:      []string{"audience1", "audience2"},
+		AllowedRedirectURIs: []string{"foo", "bar"},
+		ListClaimMappings:   map[string]string{"roles": "roles", "groups": "groups"},
+	}
+	actualOutput := formatAuthMethodConfig(&inputConfig)
+	require.Contains(t, actualOutput, "OIDC Discovery URL     = http://example.com")
+	require.Contains(t, actualOutput, "Bound audiences        = audience1,audience2")
+	require.Contains(t, actualOutput, "List claim mappings    = {groups: groups}; {roles: roles}")
+	require.NotContains(t, actualOutput, "very secret secret")
+}
+
+func Test_parseAuthMethodConfig(t *testing.T) {
+	ci.Parallel(t)
+
+	// Parse the config from a JSON string.
+	config, err := parseAuthMethodConfig(`{"OIDCDiscoveryURL": "http://example.com"}`)
+	require.NoError(t, err)
+	require.Equal(t, "http://example.com", config.OIDCDiscoveryURL)
+
+	// Parse the config from a file.
+	configFile := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, ioutil.WriteFile(configFile, []byte(`{"OIDCClientID": "mock"}`), 0600))
+	config, err = parseAuthMethodConfig("@" + configFile)
+	require.NoError(t, err)
+	require.Equal(t, "mock", config.OIDCClientID)
+
+	// Invalid JSON and missing files should return an error.
+	_, err = parseAuthMethodConfig(`{"OIDCClientID": `)
+	require.Error(t, err)
+	_, err = parseAuthMethodConfig("@" + filepath.Join(os.TempDir(), "this-file-does-not-exist.json"))
+	require.Error(t, err)
+}
@@ -0,0 +1,200 @@
+package command
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+// Ensure ACLAuthMethodUpdateCommand satisfies the cli.Command interface.
+var _ cli.Command = &ACLAuthMethodUpdateCommand{}
+
+// ACLAuthMethodUpdateCommand implements cli.Command.
+type ACLAuthMethodUpdateCommand struct {
+	Meta
+
+	methodType    string
+	tokenLocality string
+	maxTokenTTL   time.Duration
+	isDefault     bool
+	config        string
+	json          bool
+	tmpl          string
+}
+
+// Help satisfies the cli.Command Help function.
+func (a *ACLAuthMethodUpdateCommand) Help() string {
+	helpText := `
+Usage: nomad acl auth-method update [options] <acl_auth_method_name>
+
+  Update is used to update an existing ACL auth method. Only the fields passed
+  as flags are modified. Use requires a management token.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+ACL Auth Method Update Options:
+
+  -type
+    Updates the type of the auth method. Currently the only supported type is
+    "OIDC".
+
+  -max-token-ttl
+    Updates the duration for which tokens generated by the auth method are
+    valid, such as "1h".
+
+  -token-locality
+    Updates the kind of token that this auth method should produce. This can
+    be either "local" or "global".
+
+  -default
+    Specifies whether this auth method should be treated as the default one in
+    case no auth method is explicitly specified for a login command.
+
+  -config
+    Replaces the configuration of the auth method with the passed JSON object.
+    Prefix the value with "@" to read the configuration from a file, such as
+    "-config=@config.json".
+
+  -json
+    Output the ACL auth method in a JSON format.
+
+  -t
+    Format and display the ACL auth method using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (a *ACLAuthMethodUpdateCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(a.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-type":           complete.PredictSet(api.ACLAuthMethodTypeOIDC),
+			"-max-token-ttl":  complete.PredictAnything,
+			"-token-locality": complete.PredictSet(api.ACLAuthMethodTokenLocalityLocal, api.ACLAuthMethodTokenLocalityGlobal),
+			"-default":        complete.PredictSet("true", "false"),
+			"-config":         complete.PredictFiles("*.json"),
+			"-json":           complete.PredictNothing,
+			"-t":              complete.PredictAnything,
+		})
+}
+
+func (a *ACLAuthMethodUpdateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+// Synopsis satisfies the cli.Command Synopsis function.
+func (a *ACLAuthMethodUpdateCommand) Synopsis() string { return "Update an existing ACL auth method" }
+
+// Name returns the name of this command.
+func (a *ACLAuthMethodUpdateCommand) Name() string { return "acl auth-method update" }
+
+// Run satisfies the cli.Command Run function.
+func (a *ACLAuthMethodUpdateCommand) Run(args []string) int {
+
+	flags := a.Meta.FlagSet(a.Name(), FlagSetClient)
+	flags.Usage = func() { a.Ui.Output(a.Help()) }
+	flags.StringVar(&a.methodType, "type", "", "")
+	flags.StringVar(&a.tokenLocality, "token-locality", "", "")
+	flags.DurationVar(&a.maxTokenTTL, "max-token-ttl", 0, "")
+	flags.BoolVar(&a.isDefault, "default", false, "")
+	flags.StringVar(&a.config, "config", "", "")
+	flags.BoolVar(&a.json, "json", false, "")
+	flags.StringVar(&a.tmpl, "t", "", "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one argument which is expected to be the ACL
+	// auth method name.
+	if len(flags.Args()) != 1 {
+		a.Ui.Error("This command takes one argument: <acl_auth_method_name>")
+		a.Ui.Error(commandErrorText(a))
+		return 1
+	}
+
+	// Track which of the update flags were set, so the operator can set a
+	// value such as -default=false.
+	setFlags := make(map[string]struct{})
+	flags.Visit(func(f *flag.Flag) { setFlags[f.Name] = struct{}{} })
+
+	var updated bool
+	for _, name := range []string{"type", "token-locality", "max-token-ttl", "default", "config"} {
+		if _, ok := setFlags[name]; ok {
+			updated = true
+		}
+	}
+	if !updated {
+		a.Ui.Error("Please provide at least one flag to update the ACL auth method")
+		a.Ui.Error(commandErrorText(a))
+		return 1
+	}
+
+	// Get the HTTP client.
+	client, err := a.Meta.Client()
+	if err != nil {
+		a.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	methodName := flags.Args()[0]
+
+	// Read the current auth method, so we can fail better if not found and
+	// merge the updated fields.
+	currentMethod, _, err := client.ACLAuthMethods().Get(methodName, nil)
+	if err != nil {
+		a.Ui.Error(fmt.Sprintf("Error when retrieving ACL auth method: %v", err))
+		return 1
+	}
+
+	updatedMethod := *currentMethod
+
+	if _, ok := setFlags["type"]; ok {
+		updatedMethod.Type = strings.ToUpper(a.methodType)
+	}
+	if _, ok := setFlags["token-locality"]; ok {
+		updatedMethod.TokenLocality = a.tokenLocality
+	}
+	if _, ok := setFlags["max-token-ttl"]; ok {
+		updatedMethod.MaxTokenTTL = a.maxTokenTTL
+	}
+	if _, ok := setFlags["default"]; ok {
+		updatedMethod.Default = a.isDefault
+	}
+	if _, ok := setFlags["config"]; ok {
+		config, err := parseAuthMethodConfig(a.config)
+		if err != nil {
+			a.Ui.Error(fmt.Sprintf("Error parsing ACL auth method config: %s", err))
+			return 1
+		}
+		updatedMethod.Config = config
+	}
+
+	// Update the auth method with the new information via the API.
+	method, _, err := client.ACLAuthMethods().Update(&updatedMethod, nil)
+	if err != nil {
+		a.Ui.Error(fmt.Sprintf("Error updating ACL auth method: %s", err))
+		return 1
+	}
+
+	if a.json || len(a.tmpl) > 0 {
+		out, err := Format(a.json, a.tmpl, method)
+		if err != nil {
+			a.Ui.Error(err.Error())
+			return 1
+		}
+
+		a.Ui.Output(out)
+		return 0
+	}
+
+	a.Ui.Output(formatAuthMethod(method))
+	a.Ui.Output(a.Colorize().Color("\n[bold]Auth Method Config[reset]\n"))
+	a.Ui.Output(formatAuthMethodConfig(method.Config))
+	return 0
+}
@@ -0,0 +1,75 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLBindingRuleCreateCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	// Build a test server with ACLs enabled.
+	srv, _, url := testServer(t, false, func(c *agent.Config) {
+		c.ACL.Enabled = true
+	})
+	defer srv.Shutdown()
+
+	// Ensure we have a bootstrap token.
+	rootACLToken := srv.RootToken
+	require.NotNil(t, rootACLToken)
+
+	ui := cli.NewMockUi()
+	cmd := &ACLBindingRuleCreateCommand{
+		Meta: Meta{
+			Ui:          ui,
+			flagAddress: url,
+		},
+	}
+
+	// Test the basic validation on the command.
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url, "this-command-does-not-take-args"}))
+	require.Contains(t, ui.ErrorWriter.String(), "This command takes no arguments")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url}))
+	require.Contains(t, ui.ErrorWriter.String(), "ACL binding rule auth method must be specified using the -auth-method flag")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url, "-auth-method=auth0"}))
+	require.Contains(t, ui.ErrorWriter.String(), "ACL binding rule bind type must be specified using the -bind-type flag")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Create an ACL auth method which the binding rule can reference.
+	authMethod := mock.ACLAuthMethod()
+	require.NoError(t, srv.Agent.Server().State().UpsertACLAuthMethods(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLAuthMethod{authMethod}))
+
+	// Create an ACL binding rule.
+	args := []string{
+		"-address=" + url, "-token=" + rootACLToken.SecretID, "-auth-method=" + authMethod.Name,
+		"-bind-type=role", "-bind-name=eng-ro", "-selector=engineering in list.roles",
+		"-description=engineering read only",
+	}
+	require.Equal(t, 0, cmd.Run(args))
+	s := ui.OutputWriter.String()
+	require.Contains(t, s, "Description  = engineering read only")
+	require.Contains(t, s, "Auth Method  = "+authMethod.Name)
+	require.Contains(t, s, "Selector     = \"engineering in list.roles\"")
+	require.Contains(t, s, "Bind Type    = role")
+	require.Contains(t, s, "Bind Name    = eng-ro")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+}
@@ -0,0 +1,44 @@
+package command
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_formatACLBindingRule(t *testing.T) {
+	ci.Parallel(t)
+
+	inputBindingRule := api.ACLBindingRule{
+		ID:          "this-is-usually-a-uuid",
+		Description: "this-is-my-description",
+		AuthMethod:  "auth0",
+		Selector:    "engineering in list.roles",
+		BindType:    "role",
+		BindName:    "eng-ro",
+		CreateTime:  time.Date(2022, 12, 8, 11, 12, 22, 0, time.UTC),
+		ModifyTime:  time.Date(2022, 12, 8, 11, 12, 22, 0, time.UTC),
+		CreateIndex: 13,
+		ModifyIndex: 1313,
+	}
+	expectedOutput := "ID           = this-is-usually-a-uuid\nDescription  = this-is-my-description\nAuth Method  = auth0\nSelector     = \"engineering in list.roles\"\nBind Type    = role\nBind Name    = eng-ro\nCreate Time  = 2022-12-08 11:12:22 +0000 UTC\nModify Time  = 2022-12-08 11:12:22 +0000 UTC\nCreate Index = 13\nModify Index = 1313"
+	actualOutput := formatACLBindingRule(&inputBindingRule)
+	require.Equal(t, expectedOutput, actualOutput)
+}
+
+func Test_formatACLBindingRules(t *testing.T) {
+	ci.Parallel(t)
+
+	require.Equal(t, "No ACL binding rules found", formatACLBindingRules(nil))
+
+	inputBindingRules := []*api.ACLBindingRuleListStub{
+		{ID: "binding-rule-1", Description: "description-1", AuthMethod: "auth0"},
+		{ID: "binding-rule-2", Description: "description-2", AuthMethod: "okta"},
+	}
+	expectedOutput := "ID              Description    Auth Method\nbinding-rule-1  description-1  auth0\nbinding-rule-2  description-2  okta"
+	actualOutput := formatACLBindingRules(inputBindingRules)
+	require.Equal(t, expectedOutput, actualOutput)
+}
@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/nomad/api"
 	"github.com/posener/complete"
@@ -153,18 +154,45 @@ func formatKVACLToken(token *api.ACLToken) string {
 		fmt.Sprintf("Global|%v", token.Global),
 	}
 
-	// Special case the policy output
+	// Special case the policy and role output
 	if token.Type == "management" {
-		output = append(output, "Policies|n/a")
+		output = append(output, "Policies|n/a", "Roles|n/a")
 	} else {
-		output = append(output, fmt.Sprintf("Policies|%v", token.Policies))
+		output = append(output,
+			fmt.Sprintf("Policies|%v", token.Policies),
+			fmt.Sprintf("Roles|%v", formatACLTokenRoleLinks(token.Roles)),
+		)
 	}
 
 	// Add the generic output
 	output = append(output,
 		fmt.Sprintf("Create Time|%v", token.CreateTime),
+		fmt.Sprintf("Expiry Time|%s", formatACLTokenExpiry(token.ExpirationTime)),
 		fmt.Sprintf("Create Index|%d", token.CreateIndex),
 		fmt.Sprintf("Modify Index|%d", token.ModifyIndex),
 	)
 	return formatKV(output)
 }
+
+// formatACLTokenExpiry returns the expiry time of a token, or "<none>" if the
+// token does not expire.
+func formatACLTokenExpiry(expiry *time.Time) string {
+	if expiry == nil {
+		return "<none>"
+	}
+	return expiry.String()
+}
+
+// formatACLTokenRoleLinks returns the names of the roles linked to a token,
+// falling back to the role ID if the name is not known.
+func formatACLTokenRoleLinks(roleLinks []*api.ACLTokenRoleLink) []string {
+	out := make([]string, len(roleLinks))
+	for i, roleLink := range roleLinks {
+		if roleLink.Name != "" {
+			out[i] = roleLink.Name
+		} else {
+			out[i] = roleLink.ID
+		}
+	}
+	return out
+}
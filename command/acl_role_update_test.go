@@ -0,0 +1,121 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLRoleUpdateCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	// Build a test server with ACLs enabled.
+	srv, _, url := testServer(t, false, func(c *agent.Config) {
+		c.ACL.Enabled = true
+	})
+	defer srv.Shutdown()
+
+	// Ensure we have a bootstrap token.
+	rootACLToken := srv.RootToken
+	require.NotNil(t, rootACLToken)
+
+	ui := cli.NewMockUi()
+	cmd := &ACLRoleUpdateCommand{
+		Meta: Meta{
+			Ui:          ui,
+			flagAddress: url,
+		},
+	}
+
+	// Try calling the command without setting an ACL Role ID arg.
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url}))
+	require.Contains(t, ui.ErrorWriter.String(), "This command takes one argument")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Try calling the command with an ACL role ID that does not exist.
+	code := cmd.Run([]string{"-address=" + url, "-token=" + rootACLToken.SecretID, "catch-me-if-you-can"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "ACL role not found")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Create the ACL policies that can be referenced within the ACL role.
+	aclPolicy1 := structs.ACLPolicy{
+		Name:  "acl-role-cli-test-policy-1",
+		Rules: acl.PolicyWrite,
+	}
+	aclPolicy1.SetHash()
+	aclPolicy2 := structs.ACLPolicy{
+		Name:  "acl-role-cli-test-policy-2",
+		Rules: acl.PolicyRead,
+	}
+	aclPolicy2.SetHash()
+	require.NoError(t, srv.Agent.Server().State().UpsertACLPolicies(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLPolicy{&aclPolicy1, &aclPolicy2}))
+
+	// Create an ACL role that can be used for updating.
+	aclRole := structs.ACLRole{
+		ID:          "a-role-id",
+		Name:        "acl-role-cli-test",
+		Description: "my-lovely-role",
+		Policies:    []*structs.ACLRolePolicyLink{{Name: aclPolicy1.Name}},
+	}
+	require.NoError(t, srv.Agent.Server().State().UpsertACLRoles(
+		structs.MsgTypeTestSetup, 20, []*structs.ACLRole{&aclRole}, false))
+
+	// Try a merge update without setting any parameters to update.
+	code = cmd.Run([]string{"-address=" + url, "-token=" + rootACLToken.SecretID, aclRole.ID})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "Please provide at least one flag to update the ACL role")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Update the description using the merge method.
+	code = cmd.Run([]string{
+		"-address=" + url, "-token=" + rootACLToken.SecretID, "-description=badger-badger-badger", aclRole.ID})
+	require.Equal(t, 0, code)
+	s := ui.OutputWriter.String()
+	require.Contains(t, s, "Name         = acl-role-cli-test")
+	require.Contains(t, s, "Description  = badger-badger-badger")
+	require.Contains(t, s, "Policies     = acl-role-cli-test-policy-1")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Add a policy using the merge method.
+	code = cmd.Run([]string{
+		"-address=" + url, "-token=" + rootACLToken.SecretID, "-policy-name=" + aclPolicy2.Name, aclRole.ID})
+	require.Equal(t, 0, code)
+	require.Contains(t, ui.OutputWriter.String(), "Policies     = acl-role-cli-test-policy-1,acl-role-cli-test-policy-2")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Try updating the role using no-merge without setting the required
+	// flags.
+	code = cmd.Run([]string{"-address=" + url, "-token=" + rootACLToken.SecretID, "-no-merge", aclRole.ID})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "ACL role name must be specified using the -name flag")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Update the role using no-merge with all required flags set.
+	code = cmd.Run([]string{
+		"-address=" + url, "-token=" + rootACLToken.SecretID, "-no-merge", "-name=update-role-name",
+		"-description=updated-description", "-policy-name=" + aclPolicy2.Name, aclRole.ID})
+	require.Equal(t, 0, code)
+	s = ui.OutputWriter.String()
+	require.Contains(t, s, "Name         = update-role-name")
+	require.Contains(t, s, "Description  = updated-description")
+	require.Contains(t, s, "Policies     = acl-role-cli-test-policy-2")
+}
@@ -40,4 +40,19 @@ func TestACLTokenCreateCommand(t *testing.T) {
 	if !strings.Contains(out, "[foo]") {
 		t.Fatalf("bad: %v", out)
 	}
+	assert.Contains(out, "Expiry Time  = <none>")
+	ui.OutputWriter.Reset()
+
+	// Create a new token that has an expiry TTL set.
+	code = cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, "-policy=foo", "-type=client", "-ttl=10m"})
+	assert.Equal(0, code)
+
+	out = ui.OutputWriter.String()
+	assert.NotContains(out, "Expiry Time  = <none>")
+	ui.OutputWriter.Reset()
+
+	// An invalid TTL should fail before the request is sent.
+	code = cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, "-policy=foo", "-type=client", "-ttl=invalid"})
+	assert.Equal(1, code)
+	assert.Contains(ui.ErrorWriter.String(), "Failed to parse TTL as time duration")
 }
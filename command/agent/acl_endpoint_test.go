@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/nomad/mock"
@@ -558,3 +559,303 @@ func TestHTTP_OneTimeToken(t *testing.T) {
 		require.EqualError(t, err, structs.ErrPermissionDenied.Error())
 	})
 }
+
+func TestHTTPServer_ACLRoles(t *testing.T) {
+	ci.Parallel(t)
+	httpACLTest(t, nil, func(srv *TestAgent) {
+
+		// Create the ACL policies the roles will link to.
+		policy1 := mock.ACLPolicy()
+		policy1.Name = "mocked-test-policy-1"
+		policy2 := mock.ACLPolicy()
+		policy2.Name = "mocked-test-policy-2"
+		require.NoError(t, srv.server.State().UpsertACLPolicies(
+			structs.MsgTypeTestSetup, 10, []*structs.ACLPolicy{policy1, policy2}))
+
+		// Create a role using the PUT /v1/acl/role endpoint.
+		aclRole := mock.ACLRole()
+		aclRole.ID = ""
+
+		req, err := http.NewRequest(http.MethodPut, "/v1/acl/role", encodeReq(aclRole))
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+		setToken(req, srv.RootToken)
+
+		obj, err := srv.Server.ACLRoleRequest(respW, req)
+		require.NoError(t, err)
+		require.NotEmpty(t, respW.Result().Header.Get("X-Nomad-Index"))
+
+		createdRole := obj.(*structs.ACLRole)
+		require.NotEmpty(t, createdRole.ID)
+		require.Equal(t, aclRole.Name, createdRole.Name)
+
+		// List the roles.
+		req, err = http.NewRequest(http.MethodGet, "/v1/acl/roles", nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, srv.RootToken)
+
+		obj, err = srv.Server.ACLRoleListRequest(respW, req)
+		require.NoError(t, err)
+		require.Len(t, obj.([]*structs.ACLRoleListStub), 1)
+
+		// Read the role using its ID and name.
+		req, err = http.NewRequest(http.MethodGet, "/v1/acl/role/"+createdRole.ID, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, srv.RootToken)
+
+		obj, err = srv.Server.ACLRoleSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.Equal(t, createdRole.ID, obj.(*structs.ACLRole).ID)
+
+		req, err = http.NewRequest(http.MethodGet, "/v1/acl/role/name/"+createdRole.Name, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, srv.RootToken)
+
+		obj, err = srv.Server.ACLRoleSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.Equal(t, createdRole.ID, obj.(*structs.ACLRole).ID)
+
+		// Update the role, ensuring the ID in the path must match the body.
+		updatedRole := createdRole.Copy()
+		updatedRole.Description = "updated-description"
+
+		req, err = http.NewRequest(http.MethodPost, "/v1/acl/role/not-the-id", encodeReq(updatedRole))
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, srv.RootToken)
+
+		_, err = srv.Server.ACLRoleSpecificRequest(respW, req)
+		require.ErrorContains(t, err, "does not match request path")
+
+		req, err = http.NewRequest(http.MethodPost, "/v1/acl/role/"+createdRole.ID, encodeReq(updatedRole))
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, srv.RootToken)
+
+		obj, err = srv.Server.ACLRoleSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.Equal(t, "updated-description", obj.(*structs.ACLRole).Description)
+
+		// Delete the role and ensure reading it returns a not found error.
+		req, err = http.NewRequest(http.MethodDelete, "/v1/acl/role/"+createdRole.ID, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, srv.RootToken)
+
+		_, err = srv.Server.ACLRoleSpecificRequest(respW, req)
+		require.NoError(t, err)
+
+		req, err = http.NewRequest(http.MethodGet, "/v1/acl/role/"+createdRole.ID, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, srv.RootToken)
+
+		_, err = srv.Server.ACLRoleSpecificRequest(respW, req)
+		require.ErrorContains(t, err, "ACL role not found")
+
+		// Try an unsupported method on the name lookup.
+		req, err = http.NewRequest(http.MethodDelete, "/v1/acl/role/name/"+createdRole.Name, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, srv.RootToken)
+
+		_, err = srv.Server.ACLRoleSpecificRequest(respW, req)
+		require.ErrorContains(t, err, "Invalid method")
+	})
+}
+
+func TestHTTPServer_ACLAuthMethods(t *testing.T) {
+	ci.Parallel(t)
+	httpACLTest(t, nil, func(srv *TestAgent) {
+
+		// Create an auth method using the PUT /v1/acl/auth-method endpoint.
+		authMethod := mock.ACLAuthMethod()
+
+		req, err := http.NewRequest(http.MethodPut, "/v1/acl/auth-method", encodeReq(authMethod))
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+		setToken(req, srv.RootToken)
+
+		obj, err := srv.Server.ACLAuthMethodRequest(respW, req)
+		require.NoError(t, err)
+		require.NotEmpty(t, respW.Result().Header.Get("X-Nomad-Index"))
+		require.Equal(t, authMethod.Name, obj.(*structs.ACLAuthMethod).Name)
+
+		// List the auth methods. This does not require a token.
+		req, err = http.NewRequest(http.MethodGet, "/v1/acl/auth-methods", nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+
+		obj, err = srv.Server.ACLAuthMethodListRequest(respW, req)
+		require.NoError(t, err)
+		require.Len(t, obj.([]*structs.ACLAuthMethodStub), 1)
+
+		// Read the auth method using its name.
+		req, err = http.NewRequest(http.MethodGet, "/v1/acl/auth-method/"+authMethod.Name, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, srv.RootToken)
+
+		obj, err = srv.Server.ACLAuthMethodSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.Equal(t, authMethod.Name, obj.(*structs.ACLAuthMethod).Name)
+
+		// Update the auth method, ensuring the name in the path must match
+		// the body.
+		updatedMethod := authMethod.Copy()
+		updatedMethod.MaxTokenTTL = 2 * time.Hour
+
+		req, err = http.NewRequest(http.MethodPost, "/v1/acl/auth-method/not-the-name", encodeReq(updatedMethod))
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, srv.RootToken)
+
+		_, err = srv.Server.ACLAuthMethodSpecificRequest(respW, req)
+		require.ErrorContains(t, err, "does not match request path")
+
+		req, err = http.NewRequest(http.MethodPost, "/v1/acl/auth-method/"+authMethod.Name, encodeReq(updatedMethod))
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, srv.RootToken)
+
+		obj, err = srv.Server.ACLAuthMethodSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.Equal(t, 2*time.Hour, obj.(*structs.ACLAuthMethod).MaxTokenTTL)
+
+		// Delete the auth method and ensure reading it returns a not found
+		// error.
+		req, err = http.NewRequest(http.MethodDelete, "/v1/acl/auth-method/"+authMethod.Name, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, srv.RootToken)
+
+		_, err = srv.Server.ACLAuthMethodSpecificRequest(respW, req)
+		require.NoError(t, err)
+
+		req, err = http.NewRequest(http.MethodGet, "/v1/acl/auth-method/"+authMethod.Name, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, srv.RootToken)
+
+		_, err = srv.Server.ACLAuthMethodSpecificRequest(respW, req)
+		require.ErrorContains(t, err, "ACL auth method not found")
+	})
+}
+
+func TestHTTPServer_ACLBindingRules(t *testing.T) {
+	ci.Parallel(t)
+	httpACLTest(t, nil, func(srv *TestAgent) {
+
+		// Create the auth method the binding rule will reference.
+		authMethod := mock.ACLAuthMethod()
+		require.NoError(t, srv.server.State().UpsertACLAuthMethods(
+			structs.MsgTypeTestSetup, 10, []*structs.ACLAuthMethod{authMethod}))
+
+		// Create a binding rule using the PUT /v1/acl/binding-rule endpoint.
+		bindingRule := mock.ACLBindingRule()
+		bindingRule.ID = ""
+		bindingRule.AuthMethod = authMethod.Name
+
+		req, err := http.NewRequest(http.MethodPut, "/v1/acl/binding-rule", encodeReq(bindingRule))
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+		setToken(req, srv.RootToken)
+
+		obj, err := srv.Server.ACLBindingRuleRequest(respW, req)
+		require.NoError(t, err)
+		require.NotEmpty(t, respW.Result().Header.Get("X-Nomad-Index"))
+
+		createdRule := obj.(*structs.ACLBindingRule)
+		require.NotEmpty(t, createdRule.ID)
+
+		// List the binding rules.
+		req, err = http.NewRequest(http.MethodGet, "/v1/acl/binding-rules", nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, srv.RootToken)
+
+		obj, err = srv.Server.ACLBindingRuleListRequest(respW, req)
+		require.NoError(t, err)
+		require.Len(t, obj.([]*structs.ACLBindingRuleListStub), 1)
+
+		// Read the binding rule using its ID.
+		req, err = http.NewRequest(http.MethodGet, "/v1/acl/binding-rule/"+createdRule.ID, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, srv.RootToken)
+
+		obj, err = srv.Server.ACLBindingRuleSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.Equal(t, createdRule.ID, obj.(*structs.ACLBindingRule).ID)
+
+		// Update the binding rule, ensuring the ID in the path must match the
+		// body.
+		updatedRule := createdRule.Copy()
+		updatedRule.Description = "updated-description"
+
+		req, err = http.NewRequest(http.MethodPost, "/v1/acl/binding-rule/not-the-id", encodeReq(updatedRule))
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, srv.RootToken)
+
+		_, err = srv.Server.ACLBindingRuleSpecificRequest(respW, req)
+		require.ErrorContains(t, err, "does not match request path")
+
+		req, err = http.NewRequest(http.MethodPost, "/v1/acl/binding-rule/"+createdRule.ID, encodeReq(updatedRule))
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, srv.RootToken)
+
+		obj, err = srv.Server.ACLBindingRuleSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.Equal(t, "updated-description", obj.(*structs.ACLBindingRule).Description)
+
+		// Delete the binding rule and ensure reading it returns a not found
+		// error.
+		req, err = http.NewRequest(http.MethodDelete, "/v1/acl/binding-rule/"+createdRule.ID, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, srv.RootToken)
+
+		_, err = srv.Server.ACLBindingRuleSpecificRequest(respW, req)
+		require.NoError(t, err)
+
+		req, err = http.NewRequest(http.MethodGet, "/v1/acl/binding-rule/"+createdRule.ID, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, srv.RootToken)
+
+		_, err = srv.Server.ACLBindingRuleSpecificRequest(respW, req)
+		require.ErrorContains(t, err, "ACL binding rule not found")
+	})
+}
+
+func TestHTTPServer_ACLOIDCAuthURLRequest(t *testing.T) {
+	ci.Parallel(t)
+	httpACLTest(t, nil, func(srv *TestAgent) {
+
+		// Only PUT and POST requests are supported.
+		req, err := http.NewRequest(http.MethodGet, "/v1/acl/oidc/auth-url", nil)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+
+		_, err = srv.Server.ACLOIDCAuthURLRequest(respW, req)
+		require.ErrorContains(t, err, "Invalid method")
+
+		// Requests for an auth method which does not exist should fail.
+		authURLReq := structs.ACLOIDCAuthURLRequest{
+			AuthMethodName: "not-a-method",
+			RedirectURI:    "http://localhost:4649/oidc/callback",
+			ClientNonce:    "fpSPuaodKevKfDU3IeXb",
+		}
+		req, err = http.NewRequest(http.MethodPost, "/v1/acl/oidc/auth-url", encodeReq(authURLReq))
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+
+		_, err = srv.Server.ACLOIDCAuthURLRequest(respW, req)
+		require.ErrorContains(t, err, "not found")
+	})
+}
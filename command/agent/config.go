@@ -197,6 +197,10 @@ type ClientConfig struct {
 	// NodeClass is used to group the node by class
 	NodeClass string `hcl:"node_class"`
 
+	// NodePool is the node pool the node belongs to. Nodes which do not
+	// specify a node pool are placed in the default node pool.
+	NodePool string `hcl:"node_pool"`
+
 	// Options is used for configuration of nomad internals,
 	// like fingerprinters and drivers. The format is:
 	//
@@ -318,6 +322,11 @@ type ClientConfig struct {
 	// doest not exist Nomad will attempt to create it during startup. Defaults to '/nomad'
 	CgroupParent string `hcl:"cgroup_parent"`
 
+	// Rootless runs the client as an unprivileged user. Tasks are isolated
+	// using user namespaces and the cgroups v2 subtree delegated to that user,
+	// and drivers requiring root are unavailable.
+	Rootless bool `hcl:"rootless"`
+
 	// NomadServiceDiscovery is a boolean parameter which allows operators to
 	// enable/disable to Nomad native service discovery feature on the client.
 	// This parameter is exposed via the Nomad fingerprinter and used to ensure
@@ -355,6 +364,18 @@ type ACLConfig struct {
 	// within the authoritative region.
 	ReplicationToken string `hcl:"replication_token"`
 
+	// TokenMinExpirationTTL is used to enforce the lowest acceptable value
+	// for ACL token expiration. This is used by the Nomad servers to
+	// validate ACL tokens with an expiration value set upon creation.
+	TokenMinExpirationTTL    time.Duration
+	TokenMinExpirationTTLHCL string `hcl:"token_min_expiration_ttl" json:"-"`
+
+	// TokenMaxExpirationTTL is used to enforce the highest acceptable value
+	// for ACL token expiration. This is used by the Nomad servers to
+	// validate ACL tokens with an expiration value set upon creation.
+	TokenMaxExpirationTTL    time.Duration
+	TokenMaxExpirationTTLHCL string `hcl:"token_max_expiration_ttl" json:"-"`
+
 	// ExtraKeysHCL is used by hcl to surface unexpected keys
 	ExtraKeysHCL []string `hcl:",unusedKeys" json:"-"`
 }
@@ -411,6 +432,10 @@ type ServerConfig struct {
 	// can be used to filter by age.
 	JobGCThreshold string `hcl:"job_gc_threshold"`
 
+	// JobMaxSourceSize limits the size of the jobspec source stored with each
+	// job version, such as "1MB". Setting it to "0" disables storing sources.
+	JobMaxSourceSize string `hcl:"job_max_source_size"`
+
 	// EvalGCThreshold controls how "old" an eval must be to be collected by GC.
 	// Age is not the only requirement for a eval to be GCed but the threshold
 	// can be used to filter by age.
@@ -431,6 +456,10 @@ type ServerConfig struct {
 	// GCed but the threshold can be used to filter by age.
 	CSIPluginGCThreshold string `hcl:"csi_plugin_gc_threshold"`
 
+	// ACLTokenGCThreshold controls how "old" an expired ACL token must be to
+	// be collected by GC.
+	ACLTokenGCThreshold string `hcl:"acl_token_gc_threshold"`
+
 	// HeartbeatGrace is the grace period beyond the TTL to account for network,
 	// processing delays and clock skew before marking a node as "down".
 	HeartbeatGrace    time.Duration
@@ -1473,6 +1502,18 @@ func (a *ACLConfig) Merge(b *ACLConfig) *ACLConfig {
 	if b.ReplicationToken != "" {
 		result.ReplicationToken = b.ReplicationToken
 	}
+	if b.TokenMinExpirationTTL != 0 {
+		result.TokenMinExpirationTTL = b.TokenMinExpirationTTL
+	}
+	if b.TokenMinExpirationTTLHCL != "" {
+		result.TokenMinExpirationTTLHCL = b.TokenMinExpirationTTLHCL
+	}
+	if b.TokenMaxExpirationTTL != 0 {
+		result.TokenMaxExpirationTTL = b.TokenMaxExpirationTTL
+	}
+	if b.TokenMaxExpirationTTLHCL != "" {
+		result.TokenMaxExpirationTTLHCL = b.TokenMaxExpirationTTLHCL
+	}
 	return &result
 }
 
@@ -1514,6 +1555,9 @@ func (s *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 	if b.JobGCThreshold != "" {
 		result.JobGCThreshold = b.JobGCThreshold
 	}
+	if b.JobMaxSourceSize != "" {
+		result.JobMaxSourceSize = b.JobMaxSourceSize
+	}
 	if b.EvalGCThreshold != "" {
 		result.EvalGCThreshold = b.EvalGCThreshold
 	}
@@ -1526,6 +1570,9 @@ func (s *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 	if b.CSIPluginGCThreshold != "" {
 		result.CSIPluginGCThreshold = b.CSIPluginGCThreshold
 	}
+	if b.ACLTokenGCThreshold != "" {
+		result.ACLTokenGCThreshold = b.ACLTokenGCThreshold
+	}
 	if b.HeartbeatGrace != 0 {
 		result.HeartbeatGrace = b.HeartbeatGrace
 	}
@@ -1646,6 +1693,9 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 	if b.NodeClass != "" {
 		result.NodeClass = b.NodeClass
 	}
+	if b.NodePool != "" {
+		result.NodePool = b.NodePool
+	}
 	if b.NetworkInterface != "" {
 		result.NetworkInterface = b.NetworkInterface
 	}
@@ -1783,6 +1833,10 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 		result.CgroupParent = b.CgroupParent
 	}
 
+	if b.Rootless {
+		result.Rootless = b.Rootless
+	}
+
 	result.Artifact = a.Artifact.Merge(b.Artifact)
 
 	return &result
@@ -45,6 +45,7 @@ var basicConfig = &Config{
 		AllocDir:  "/tmp/alloc",
 		Servers:   []string{"a.b.c:80", "127.0.0.1:1234"},
 		NodeClass: "linux-medium-64bit",
+		NodePool:  "linux",
 		ServerJoin: &ServerJoin{
 			RetryJoin:        []string{"1.1.1.1", "2.2.2.2"},
 			RetryInterval:    time.Duration(15) * time.Second,
@@ -104,9 +105,11 @@ var basicConfig = &Config{
 		EvalGCThreshold:           "12h",
 		JobGCInterval:             "3m",
 		JobGCThreshold:            "12h",
+		JobMaxSourceSize:          "2MB",
 		DeploymentGCThreshold:     "12h",
 		CSIVolumeClaimGCThreshold: "12h",
 		CSIPluginGCThreshold:      "12h",
+		ACLTokenGCThreshold:       "12h",
 		HeartbeatGrace:            30 * time.Second,
 		HeartbeatGraceHCL:         "30s",
 		MinHeartbeatTTL:           33 * time.Second,
@@ -143,12 +146,16 @@ var basicConfig = &Config{
 		LicensePath: "/tmp/nomad.hclic",
 	},
 	ACL: &ACLConfig{
-		Enabled:          true,
-		TokenTTL:         60 * time.Second,
-		TokenTTLHCL:      "60s",
-		PolicyTTL:        60 * time.Second,
-		PolicyTTLHCL:     "60s",
-		ReplicationToken: "foobar",
+		Enabled:                  true,
+		TokenTTL:                 60 * time.Second,
+		TokenTTLHCL:              "60s",
+		PolicyTTL:                60 * time.Second,
+		PolicyTTLHCL:             "60s",
+		ReplicationToken:         "foobar",
+		TokenMinExpirationTTL:    1 * time.Hour,
+		TokenMinExpirationTTLHCL: "1h",
+		TokenMaxExpirationTTL:    100 * time.Hour,
+		TokenMaxExpirationTTLHCL: "100h",
 	},
 	Audit: &config.AuditConfig{
 		Enabled: helper.BoolToPtr(true),
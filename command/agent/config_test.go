@@ -108,6 +108,7 @@ func TestConfig_Merge(t *testing.T) {
 			StateDir:  "/tmp/state1",
 			AllocDir:  "/tmp/alloc1",
 			NodeClass: "class1",
+			NodePool:  "pool1",
 			Options: map[string]string{
 				"foo": "bar",
 			},
@@ -286,6 +287,7 @@ func TestConfig_Merge(t *testing.T) {
 			StateDir:  "/tmp/state2",
 			AllocDir:  "/tmp/alloc2",
 			NodeClass: "class2",
+			NodePool:  "pool2",
 			Servers:   []string{"server2"},
 			Meta: map[string]string{
 				"baz": "zip",
@@ -1508,7 +1508,7 @@ func makeAgentServiceID(role string, service *structs.Service) string {
 //
 //  Example Check ID: _nomad-check-434ae42f9a57c5705344974ac38de2aee0ee089d
 func MakeCheckID(serviceID string, check *structs.ServiceCheck) string {
-	return fmt.Sprintf("%s%s", nomadCheckPrefix, check.Hash(serviceID))
+	return serviceregistration.MakeCheckID(serviceID, check)
 }
 
 // createCheckReg creates a Check that can be registered with Consul.
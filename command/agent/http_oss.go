@@ -12,11 +12,6 @@ func (s *HTTPServer) registerEnterpriseHandlers() {
 	s.mux.HandleFunc("/v1/sentinel/policies", s.wrap(s.entOnly))
 	s.mux.HandleFunc("/v1/sentinel/policy/", s.wrap(s.entOnly))
 
-	s.mux.HandleFunc("/v1/quotas", s.wrap(s.entOnly))
-	s.mux.HandleFunc("/v1/quota-usages", s.wrap(s.entOnly))
-	s.mux.HandleFunc("/v1/quota/", s.wrap(s.entOnly))
-	s.mux.HandleFunc("/v1/quota", s.wrap(s.entOnly))
-
 	s.mux.HandleFunc("/v1/recommendation", s.wrap(s.entOnly))
 	s.mux.HandleFunc("/v1/recommendations", s.wrap(s.entOnly))
 	s.mux.HandleFunc("/v1/recommendations/apply", s.wrap(s.entOnly))
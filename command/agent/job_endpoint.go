@@ -75,6 +75,9 @@ func (s *HTTPServer) JobSpecificRequest(resp http.ResponseWriter, req *http.Requ
 	case strings.HasSuffix(path, "/versions"):
 		jobName := strings.TrimSuffix(path, "/versions")
 		return s.jobVersions(resp, req, jobName)
+	case strings.HasSuffix(path, "/submission"):
+		jobName := strings.TrimSuffix(path, "/submission")
+		return s.jobSubmission(resp, req, jobName)
 	case strings.HasSuffix(path, "/revert"):
 		jobName := strings.TrimSuffix(path, "/revert")
 		return s.jobRevert(resp, req, jobName)
@@ -415,6 +418,7 @@ func (s *HTTPServer) jobUpdate(resp http.ResponseWriter, req *http.Request,
 		PolicyOverride: args.PolicyOverride,
 		PreserveCounts: args.PreserveCounts,
 		EvalPriority:   args.EvalPriority,
+		Submission:     ApiJobSubmissionToStructs(args.Submission),
 		WriteRequest:   *writeReq,
 	}
 
@@ -601,6 +605,43 @@ func (s *HTTPServer) jobVersions(resp http.ResponseWriter, req *http.Request,
 	return out, nil
 }
 
+func (s *HTTPServer) jobSubmission(resp http.ResponseWriter, req *http.Request,
+	jobName string) (interface{}, error) {
+
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	versionStr := req.URL.Query().Get("version")
+	if versionStr == "" {
+		return nil, CodedError(400, "missing version parameter")
+	}
+	version, err := strconv.ParseUint(versionStr, 10, 64)
+	if err != nil {
+		return nil, CodedError(400, fmt.Sprintf("Failed to parse value of %q (%v) as a uint64: %v", "version", versionStr, err))
+	}
+
+	args := structs.JobSubmissionRequest{
+		JobID:   jobName,
+		Version: version,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.JobSubmissionResponse
+	if err := s.agent.RPC("Job.GetJobSubmission", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Submission == nil {
+		return nil, CodedError(404, "job source not found")
+	}
+
+	return out.Submission, nil
+}
+
 func (s *HTTPServer) jobRevert(resp http.ResponseWriter, req *http.Request,
 	jobName string) (interface{}, error) {
 
@@ -916,6 +957,14 @@ func ApiJobToStructJob(job *api.Job) *structs.Job {
 		Affinities:     ApiAffinitiesToStructs(job.Affinities),
 	}
 
+	if job.NodePool != nil {
+		j.NodePool = *job.NodePool
+	}
+
+	if job.Scheduler != nil {
+		j.Scheduler = *job.Scheduler
+	}
+
 	// Update has been pushed into the task groups. stagger and max_parallel are
 	// preserved at the job level, but all other values are discarded. The job.Update
 	// api value is merged into TaskGroups already in api.Canonicalize
@@ -1016,6 +1065,15 @@ func ApiTgToStructsTG(job *structs.Job, taskGroup *api.TaskGroup, tg *structs.Ta
 		tg.MaxClientDisconnect = taskGroup.MaxClientDisconnect
 	}
 
+	if taskGroup.Placement != nil {
+		tg.Placement = *taskGroup.Placement
+	}
+
+	tg.DependsOn = helper.CopySliceString(taskGroup.DependsOn)
+	if taskGroup.DependsOnCondition != nil {
+		tg.DependsOnCondition = *taskGroup.DependsOnCondition
+	}
+
 	if taskGroup.ReschedulePolicy != nil {
 		tg.ReschedulePolicy = &structs.ReschedulePolicy{
 			Attempts:      *taskGroup.ReschedulePolicy.Attempts,
@@ -1036,6 +1094,13 @@ func ApiTgToStructsTG(job *structs.Job, taskGroup *api.TaskGroup, tg *structs.Ta
 		}
 	}
 
+	if taskGroup.Preemption != nil {
+		tg.Preemption = &structs.PreemptionPolicy{
+			Preemptible:   *taskGroup.Preemption.Preemptible,
+			MaxDisruption: *taskGroup.Preemption.MaxDisruption,
+		}
+	}
+
 	if taskGroup.Scaling != nil {
 		tg.Scaling = ApiScalingPolicyToStructs(tg.Count, taskGroup.Scaling).TargetTaskGroup(job, tg)
 	}
@@ -1140,6 +1205,13 @@ func ApiTaskToStructsTask(job *structs.Job, group *structs.TaskGroup,
 	structsTask.Affinities = ApiAffinitiesToStructs(apiTask.Affinities)
 	structsTask.CSIPluginConfig = ApiCSIPluginConfigToStructsCSIPluginConfig(apiTask.CSIPluginConfig)
 
+	if apiTask.Identity != nil {
+		structsTask.Identity = &structs.WorkloadIdentity{
+			Env:  apiTask.Identity.Env,
+			File: apiTask.Identity.File,
+		}
+	}
+
 	if apiTask.RestartPolicy != nil {
 		structsTask.RestartPolicy = &structs.RestartPolicy{
 			Attempts: *apiTask.RestartPolicy.Attempts,
@@ -1178,8 +1250,12 @@ func ApiTaskToStructsTask(job *structs.Job, group *structs.TaskGroup,
 	structsTask.Resources = ApiResourcesToStructs(apiTask.Resources)
 
 	structsTask.LogConfig = &structs.LogConfig{
-		MaxFiles:      *apiTask.LogConfig.MaxFiles,
-		MaxFileSizeMB: *apiTask.LogConfig.MaxFileSizeMB,
+		MaxFiles:       *apiTask.LogConfig.MaxFiles,
+		MaxFileSizeMB:  *apiTask.LogConfig.MaxFileSizeMB,
+		MaxFileAge:     *apiTask.LogConfig.MaxFileAge,
+		Compress:       *apiTask.LogConfig.Compress,
+		MaxAllocSizeMB: *apiTask.LogConfig.MaxAllocSizeMB,
+		Sinks:          apiLogSinksToStructs(apiTask.LogConfig.Sinks),
 	}
 
 	if len(apiTask.Artifacts) > 0 {
@@ -1306,6 +1382,12 @@ func ApiResourcesToStructs(in *api.Resources) *structs.Resources {
 		}
 	}
 
+	if in.NUMA != nil {
+		out.NUMA = &structs.NUMA{
+			Affinity: in.NUMA.Affinity,
+		}
+	}
+
 	return out
 }
 
@@ -1711,11 +1793,33 @@ func apiLogConfigToStructs(in *api.LogConfig) *structs.LogConfig {
 		return nil
 	}
 	return &structs.LogConfig{
-		MaxFiles:      dereferenceInt(in.MaxFiles),
-		MaxFileSizeMB: dereferenceInt(in.MaxFileSizeMB),
+		MaxFiles:       dereferenceInt(in.MaxFiles),
+		MaxFileSizeMB:  dereferenceInt(in.MaxFileSizeMB),
+		MaxFileAge:     dereferenceDuration(in.MaxFileAge),
+		Compress:       dereferenceBool(in.Compress),
+		MaxAllocSizeMB: dereferenceInt(in.MaxAllocSizeMB),
+		Sinks:          apiLogSinksToStructs(in.Sinks),
 	}
 }
 
+func apiLogSinksToStructs(in []*api.LogSink) []*structs.LogSink {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make([]*structs.LogSink, len(in))
+	for i, sink := range in {
+		out[i] = &structs.LogSink{
+			Type:       sink.Type,
+			Address:    sink.Address,
+			Tag:        sink.Tag,
+			Facility:   sink.Facility,
+			BufferSize: dereferenceInt(sink.BufferSize),
+			Overflow:   sink.Overflow,
+		}
+	}
+	return out
+}
+
 func dereferenceInt(in *int) int {
 	if in == nil {
 		return 0
@@ -1723,6 +1827,34 @@ func dereferenceInt(in *int) int {
 	return *in
 }
 
+func dereferenceBool(in *bool) bool {
+	if in == nil {
+		return false
+	}
+	return *in
+}
+
+func dereferenceDuration(in *time.Duration) time.Duration {
+	if in == nil {
+		return 0
+	}
+	return *in
+}
+
+// ApiJobSubmissionToStructs converts the jobspec source of a job
+// registration. It handles nil objects.
+func ApiJobSubmissionToStructs(in *api.JobSubmission) *structs.JobSubmission {
+	if in == nil {
+		return nil
+	}
+	return &structs.JobSubmission{
+		Source:        in.Source,
+		Format:        in.Format,
+		VariableFlags: helper.CopyMapStringString(in.VariableFlags),
+		Variables:     in.Variables,
+	}
+}
+
 func ApiConstraintsToStructs(in []*api.Constraint) []*structs.Constraint {
 	if in == nil {
 		return nil
@@ -1774,6 +1906,9 @@ func ApiSpreadToStructs(a1 *api.Spread) *structs.Spread {
 	ret := &structs.Spread{}
 	ret.Attribute = a1.Attribute
 	ret.Weight = *a1.Weight
+	if a1.MaxSkew != nil {
+		ret.MaxSkew = *a1.MaxSkew
+	}
 	if a1.SpreadTarget != nil {
 		ret.SpreadTarget = make([]*structs.SpreadTarget, len(a1.SpreadTarget))
 		for i, st := range a1.SpreadTarget {
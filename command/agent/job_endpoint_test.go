@@ -1605,6 +1605,57 @@ func TestHTTP_JobVersions(t *testing.T) {
 	})
 }
 
+func TestHTTP_JobSubmission(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Create the job with its source
+		job := mock.Job()
+		args := structs.JobRegisterRequest{
+			Job: job,
+			Submission: &structs.JobSubmission{
+				Source: `job "example" {}`,
+				Format: structs.JobSubmissionFormatHCL2,
+			},
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: structs.DefaultNamespace,
+			},
+		}
+		var resp structs.JobRegisterResponse
+		require.NoError(t, s.Agent.RPC("Job.Register", &args, &resp))
+
+		// Make the HTTP request
+		req, err := http.NewRequest("GET", "/v1/job/"+job.ID+"/submission?version=0", nil)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.JobSpecificRequest(respW, req)
+		require.NoError(t, err)
+
+		// Check the response
+		sub := obj.(*structs.JobSubmission)
+		require.Equal(t, args.Submission.Source, sub.Source)
+		require.Equal(t, structs.JobSubmissionFormatHCL2, sub.Format)
+		require.NotEmpty(t, respW.Result().Header.Get("X-Nomad-Index"))
+
+		// A version without a submission is not found
+		req, err = http.NewRequest("GET", "/v1/job/"+job.ID+"/submission?version=1", nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.JobSpecificRequest(respW, req)
+		require.Error(t, err)
+		require.Equal(t, 404, err.(HTTPCodedError).Code())
+
+		// The version is required
+		req, err = http.NewRequest("GET", "/v1/job/"+job.ID+"/submission", nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.JobSpecificRequest(respW, req)
+		require.Error(t, err)
+		require.Equal(t, 400, err.(HTTPCodedError).Code())
+	})
+}
+
 func TestHTTP_PeriodicForce(t *testing.T) {
 	ci.Parallel(t)
 	httpTest(t, nil, func(s *TestAgent) {
@@ -2368,6 +2419,7 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 		ParentID:    helper.StringToPtr("lol"),
 		Name:        helper.StringToPtr("name"),
 		Type:        helper.StringToPtr("service"),
+		Scheduler:   helper.StringToPtr("gang"),
 		Priority:    helper.IntToPtr(50),
 		AllAtOnce:   helper.BoolToPtr(true),
 		Datacenters: []string{"dc1", "dc2"},
@@ -2488,6 +2540,11 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 							},
 						},
 					},
+					{
+						Attribute: "${meta.rack}",
+						Weight:    helper.Int8ToPtr(50),
+						MaxSkew:   helper.IntToPtr(1),
+					},
 				},
 				EphemeralDisk: &api.EphemeralDisk{
 					SizeMB:  helper.IntToPtr(100),
@@ -2562,6 +2619,13 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 					},
 				},
 				MaxClientDisconnect: helper.TimeToPtr(30 * time.Second),
+				Placement:           helper.StringToPtr("all_or_nothing"),
+				Preemption: &api.PreemptionPolicy{
+					Preemptible:   helper.BoolToPtr(false),
+					MaxDisruption: helper.IntToPtr(0),
+				},
+				DependsOn:          []string{"db"},
+				DependsOnCondition: helper.StringToPtr("healthy"),
 				Tasks: []*api.Task{
 					{
 						Name:   "task1",
@@ -2768,6 +2832,7 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 		ID:             "foo",
 		Name:           "name",
 		Type:           "service",
+		Scheduler:      "gang",
 		Priority:       50,
 		AllAtOnce:      true,
 		Datacenters:    []string{"dc1", "dc2"},
@@ -2868,6 +2933,11 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 							},
 						},
 					},
+					{
+						Attribute: "${meta.rack}",
+						Weight:    50,
+						MaxSkew:   1,
+					},
 				},
 				ReschedulePolicy: &structs.ReschedulePolicy{
 					Interval:      12 * time.Hour,
@@ -2961,6 +3031,12 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 					},
 				},
 				MaxClientDisconnect: helper.TimeToPtr(30 * time.Second),
+				Placement:           "all_or_nothing",
+				Preemption: &structs.PreemptionPolicy{
+					Preemptible: false,
+				},
+				DependsOn:          []string{"db"},
+				DependsOnCondition: "healthy",
 				Tasks: []*structs.Task{
 					{
 						Name:   "task1",
@@ -0,0 +1,41 @@
+package agent
+
+import (
+	"crypto/ed25519"
+	"net/http"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	jose "gopkg.in/square/go-jose.v2"
+)
+
+// JWKSRequest is used to handle requests for the public keys used to verify
+// workload identities, in the JSON Web Key Set format. It is callable via
+// the /.well-known/jwks.json HTTP API and does not require an ACL token.
+func (s *HTTPServer) JWKSRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != http.MethodGet {
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+
+	args := structs.GenericRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var rpcReply structs.KeyringListPublicResponse
+	if err := s.agent.RPC(structs.KeyringListPublicRPCMethod, &args, &rpcReply); err != nil {
+		return nil, err
+	}
+	setMeta(resp, &rpcReply.QueryMeta)
+
+	jwks := make([]jose.JSONWebKey, 0, len(rpcReply.PublicKeys))
+	for _, pubKey := range rpcReply.PublicKeys {
+		jwks = append(jwks, jose.JSONWebKey{
+			Key:       ed25519.PublicKey(pubKey.PublicKey),
+			KeyID:     pubKey.KeyID,
+			Algorithm: pubKey.Algorithm,
+			Use:       pubKey.Use,
+		})
+	}
+
+	return &jose.JSONWebKeySet{Keys: jwks}, nil
+}
@@ -0,0 +1,59 @@
+package agent
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+	jose "gopkg.in/square/go-jose.v2"
+)
+
+func TestHTTPServer_JWKS(t *testing.T) {
+	ci.Parallel(t)
+
+	httpACLTest(t, nil, func(s *TestAgent) {
+
+		// The leader initializes the keyring asynchronously, so wait for it
+		// before listing the public keys.
+		var keyMeta *structs.RootKeyMeta
+		testutil.WaitForResult(func() (bool, error) {
+			var err error
+			keyMeta, err = s.Agent.server.State().GetActiveRootKeyMeta(memdb.NewWatchSet())
+			if err != nil {
+				return false, err
+			}
+			if keyMeta == nil {
+				return false, fmt.Errorf("keyring not initialized")
+			}
+			return true, nil
+		}, func(err error) {
+			require.NoError(t, err)
+		})
+
+		// The endpoint must not require an ACL token.
+		req, err := http.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.JWKSRequest(respW, req)
+		require.NoError(t, err)
+
+		jwks := obj.(*jose.JSONWebKeySet)
+		require.Len(t, jwks.Keys, 1)
+		require.Equal(t, keyMeta.KeyID, jwks.Keys[0].KeyID)
+		require.Equal(t, structs.PubKeyAlgEdDSA, jwks.Keys[0].Algorithm)
+		require.Equal(t, structs.PubKeyUseSig, jwks.Keys[0].Use)
+
+		// Only GET requests are supported.
+		req, err = http.NewRequest(http.MethodPost, "/.well-known/jwks.json", nil)
+		require.NoError(t, err)
+		_, err = s.Server.JWKSRequest(respW, req)
+		require.Error(t, err)
+	})
+}
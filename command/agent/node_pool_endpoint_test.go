@@ -0,0 +1,86 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_NodePoolList(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		args := structs.NodePoolUpsertRequest{
+			NodePools:    []*structs.NodePool{mock.NodePool(), mock.NodePool()},
+			WriteRequest: structs.WriteRequest{Region: "global"},
+		}
+		var resp structs.GenericResponse
+		require.NoError(t, s.Agent.RPC("NodePool.UpsertNodePools", &args, &resp))
+
+		// Make the HTTP request
+		req, err := http.NewRequest("GET", "/v1/node/pools", nil)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+
+		// Make the request
+		obj, err := s.Server.NodePoolsRequest(respW, req)
+		require.NoError(t, err)
+
+		// Check for the index
+		require.NotEmpty(t, respW.HeaderMap.Get("X-Nomad-Index"))
+
+		// Check the output (the 2 we register + the built-in node pools)
+		require.Len(t, obj.([]*structs.NodePool), 4)
+	})
+}
+
+func TestHTTP_NodePoolCRUD(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		pool := mock.NodePool()
+		buf := encodeReq(pool)
+
+		// Create the node pool
+		req, err := http.NewRequest("PUT", "/v1/node/pool/"+pool.Name, buf)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+		_, err = s.Server.NodePoolSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.NotEmpty(t, respW.HeaderMap.Get("X-Nomad-Index"))
+
+		// Mismatched names are rejected
+		req, err = http.NewRequest("PUT", "/v1/node/pool/other", encodeReq(pool))
+		require.NoError(t, err)
+		_, err = s.Server.NodePoolSpecificRequest(httptest.NewRecorder(), req)
+		require.ErrorContains(t, err, "does not match")
+
+		// Query the node pool
+		req, err = http.NewRequest("GET", "/v1/node/pool/"+pool.Name, nil)
+		require.NoError(t, err)
+		obj, err := s.Server.NodePoolSpecificRequest(httptest.NewRecorder(), req)
+		require.NoError(t, err)
+		require.Equal(t, pool.Description, obj.(*structs.NodePool).Description)
+
+		// List the nodes of the node pool
+		req, err = http.NewRequest("GET", "/v1/node/pool/"+pool.Name+"/nodes", nil)
+		require.NoError(t, err)
+		obj, err = s.Server.NodePoolSpecificRequest(httptest.NewRecorder(), req)
+		require.NoError(t, err)
+		require.Empty(t, obj.([]*structs.NodeListStub))
+
+		// Delete the node pool
+		req, err = http.NewRequest("DELETE", "/v1/node/pool/"+pool.Name, nil)
+		require.NoError(t, err)
+		_, err = s.Server.NodePoolSpecificRequest(httptest.NewRecorder(), req)
+		require.NoError(t, err)
+
+		req, err = http.NewRequest("GET", "/v1/node/pool/"+pool.Name, nil)
+		require.NoError(t, err)
+		_, err = s.Server.NodePoolSpecificRequest(httptest.NewRecorder(), req)
+		require.ErrorContains(t, err, "not found")
+	})
+}
@@ -261,7 +261,13 @@ func (s *HTTPServer) schedulerUpdateConfig(resp http.ResponseWriter, req *http.R
 	args.Config = structs.SchedulerConfiguration{
 		SchedulerAlgorithm:            structs.SchedulerAlgorithm(conf.SchedulerAlgorithm),
 		MemoryOversubscriptionEnabled: conf.MemoryOversubscriptionEnabled,
+		UtilizationScoringEnabled:     conf.UtilizationScoringEnabled,
 		RejectJobRegistration:         conf.RejectJobRegistration,
+		RebalanceConfig: structs.RebalanceConfig{
+			Enabled:          conf.RebalanceConfig.Enabled,
+			MaxMigrations:    conf.RebalanceConfig.MaxMigrations,
+			DensityThreshold: conf.RebalanceConfig.DensityThreshold,
+		},
 		PreemptionConfig: structs.PreemptionConfig{
 			SystemSchedulerEnabled:   conf.PreemptionConfig.SystemSchedulerEnabled,
 			SysBatchSchedulerEnabled: conf.PreemptionConfig.SysBatchSchedulerEnabled,
@@ -292,6 +298,32 @@ func (s *HTTPServer) schedulerUpdateConfig(resp http.ResponseWriter, req *http.R
 	return reply, nil
 }
 
+// OperatorSchedulerRebalance is used to migrate the allocations placed on
+// skewed spreads, overly dense nodes and nodes violating their affinities,
+// or to only report them on dry runs.
+func (s *HTTPServer) OperatorSchedulerRebalance(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var rebalanceReq api.SchedulerRebalanceRequest
+	if err := decodeBody(req, &rebalanceReq); err != nil {
+		return nil, CodedError(http.StatusBadRequest, fmt.Sprintf("Error parsing rebalance request: %v", err))
+	}
+
+	args := structs.SchedulerRebalanceRequest{
+		DryRun: rebalanceReq.DryRun,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var reply structs.SchedulerRebalanceResponse
+	if err := s.agent.RPC("Operator.SchedulerRebalance", &args, &reply); err != nil {
+		return nil, err
+	}
+	setIndex(resp, reply.Index)
+	return reply, nil
+}
+
 func (s *HTTPServer) SnapshotRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	switch req.Method {
 	case "GET":
@@ -98,6 +98,17 @@ func (s *HTTPServer) serviceGetRequest(
 		return nil, nil
 	}
 
+	// Only healthy registrations are returned unless healthy=false is set,
+	// as they are used to route traffic.
+	args.Healthy = true
+	healthy, err := parseBool(req, "healthy")
+	if err != nil {
+		return nil, CodedError(http.StatusBadRequest, err.Error())
+	}
+	if healthy != nil {
+		args.Healthy = *healthy
+	}
+
 	var reply structs.ServiceRegistrationByNameResponse
 	if err := s.agent.RPC(structs.ServiceRegistrationGetServiceRPCMethod, &args, &reply); err != nil {
 		return nil, err
@@ -307,6 +307,46 @@ func TestHTTPServer_ServiceRegistrationRequest(t *testing.T) {
 				must.NotEq(t, services2[0], services2[1])
 			},
 		},
+		{
+			name: "get service filters unhealthy",
+			testFn: func(s *TestAgent) {
+				// Grab the state so we can manipulate and test against it.
+				testState := s.Agent.server.State()
+
+				// Generate two registrations, one of which has a critical check.
+				serviceRegs := mock.ServiceRegistrations()
+				serviceRegs[1].ServiceName = serviceRegs[0].ServiceName
+				serviceRegs[1].Namespace = serviceRegs[0].Namespace
+				serviceRegs[1].Checks = []*structs.ServiceRegistrationCheck{
+					{ID: "check", Name: "check", Type: "tcp", Status: structs.ServiceCheckStatusCritical},
+				}
+				must.NoError(t, testState.UpsertServiceRegistrations(
+					structs.MsgTypeTestSetup, 10, serviceRegs))
+
+				// Only the healthy registration is returned by default.
+				path := fmt.Sprintf("/v1/service/%s", serviceRegs[0].ServiceName)
+				req, err := http.NewRequest(http.MethodGet, path, nil)
+				must.NoError(t, err)
+				obj, err := s.Server.ServiceRegistrationRequest(httptest.NewRecorder(), req)
+				must.NoError(t, err)
+				services := obj.([]*structs.ServiceRegistration)
+				must.Len(t, 1, services)
+				must.Eq(t, serviceRegs[0].ID, services[0].ID)
+
+				// Every registration is returned when filtering is disabled.
+				req, err = http.NewRequest(http.MethodGet, path+"?healthy=false", nil)
+				must.NoError(t, err)
+				obj, err = s.Server.ServiceRegistrationRequest(httptest.NewRecorder(), req)
+				must.NoError(t, err)
+				must.Len(t, 2, obj.([]*structs.ServiceRegistration))
+
+				// Invalid values are rejected.
+				req, err = http.NewRequest(http.MethodGet, path+"?healthy=maybe", nil)
+				must.NoError(t, err)
+				_, err = s.Server.ServiceRegistrationRequest(httptest.NewRecorder(), req)
+				must.Error(t, err)
+			},
+		},
 		{
 			name: "incorrect URI format",
 			testFn: func(s *TestAgent) {
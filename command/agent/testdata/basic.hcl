@@ -42,6 +42,7 @@ client {
   alloc_dir  = "/tmp/alloc"
   servers    = ["a.b.c:80", "127.0.0.1:1234"]
   node_class = "linux-medium-64bit"
+  node_pool  = "linux"
 
   meta {
     foo = "bar"
@@ -112,10 +113,12 @@ server {
   node_gc_threshold             = "12h"
   job_gc_interval               = "3m"
   job_gc_threshold              = "12h"
+  job_max_source_size           = "2MB"
   eval_gc_threshold             = "12h"
   deployment_gc_threshold       = "12h"
   csi_volume_claim_gc_threshold = "12h"
   csi_plugin_gc_threshold       = "12h"
+  acl_token_gc_threshold        = "12h"
   heartbeat_grace               = "30s"
   min_heartbeat_ttl             = "33s"
   max_heartbeats_per_second     = 11.0
@@ -153,10 +156,12 @@ server {
 }
 
 acl {
-  enabled           = true
-  token_ttl         = "60s"
-  policy_ttl        = "60s"
-  replication_token = "foobar"
+  enabled                  = true
+  token_ttl                = "60s"
+  policy_ttl               = "60s"
+  replication_token        = "foobar"
+  token_min_expiration_ttl = "1h"
+  token_max_expiration_ttl = "100h"
 }
 
 audit {
@@ -0,0 +1,126 @@
+package agent
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// VariablesListRequest performs a listing of variable metadata using the
+// structs.VariablesListRPCMethod RPC endpoint and is callable via the /v1/vars
+// HTTP API.
+func (s *HTTPServer) VariablesListRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+
+	// The endpoint only supports GET requests.
+	if req.Method != http.MethodGet {
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+
+	args := structs.VariablesListRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var reply structs.VariablesListResponse
+	if err := s.agent.RPC(structs.VariablesListRPCMethod, &args, &reply); err != nil {
+		return nil, err
+	}
+	setMeta(resp, &reply.QueryMeta)
+
+	if reply.Data == nil {
+		reply.Data = make([]*structs.VariableMetadata, 0)
+	}
+	return reply.Data, nil
+}
+
+// VariableSpecificRequest is callable via the /v1/var/ HTTP API and handles
+// reads, upserts, and deletions of individual variables.
+func (s *HTTPServer) VariableSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	path := strings.TrimPrefix(req.URL.Path, "/v1/var/")
+	if path == "" {
+		return nil, CodedError(http.StatusBadRequest, "missing variable path")
+	}
+
+	switch req.Method {
+	case http.MethodGet:
+		return s.variableQuery(resp, req, path)
+	case http.MethodPut, http.MethodPost:
+		return s.variableUpsert(resp, req, path)
+	case http.MethodDelete:
+		return s.variableDelete(resp, req, path)
+	default:
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+}
+
+// variableQuery performs a read of a single variable using the
+// structs.VariablesReadRPCMethod RPC endpoint.
+func (s *HTTPServer) variableQuery(resp http.ResponseWriter, req *http.Request, path string) (interface{}, error) {
+	args := structs.VariablesReadRequest{Path: path}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var reply structs.VariablesReadResponse
+	if err := s.agent.RPC(structs.VariablesReadRPCMethod, &args, &reply); err != nil {
+		return nil, err
+	}
+	setMeta(resp, &reply.QueryMeta)
+
+	if reply.Data == nil {
+		return nil, CodedError(http.StatusNotFound, "variable not found")
+	}
+	return reply.Data, nil
+}
+
+// variableUpsert performs an upsert of a single variable using the
+// structs.VariablesApplyRPCMethod RPC endpoint.
+func (s *HTTPServer) variableUpsert(resp http.ResponseWriter, req *http.Request, path string) (interface{}, error) {
+	if req.ContentLength == 0 {
+		return nil, CodedError(http.StatusBadRequest, "missing request body")
+	}
+
+	var variable structs.VariableDecrypted
+	if err := decodeBody(req, &variable); err != nil {
+		return nil, CodedError(http.StatusBadRequest, err.Error())
+	}
+
+	// The path within the URL always takes precedence over any path within
+	// the body.
+	variable.Path = path
+
+	args := structs.VariablesApplyRequest{
+		Op:  structs.VarOpSet,
+		Var: &variable,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var reply structs.VariablesApplyResponse
+	if err := s.agent.RPC(structs.VariablesApplyRPCMethod, &args, &reply); err != nil {
+		return nil, err
+	}
+	setIndex(resp, reply.Index)
+
+	return reply.Output, nil
+}
+
+// variableDelete performs a deletion of a single variable using the
+// structs.VariablesApplyRPCMethod RPC endpoint.
+func (s *HTTPServer) variableDelete(resp http.ResponseWriter, req *http.Request, path string) (interface{}, error) {
+	args := structs.VariablesApplyRequest{
+		Op: structs.VarOpDelete,
+		Var: &structs.VariableDecrypted{
+			VariableMetadata: structs.VariableMetadata{Path: path},
+		},
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var reply structs.VariablesApplyResponse
+	if err := s.agent.RPC(structs.VariablesApplyRPCMethod, &args, &reply); err != nil {
+		return nil, err
+	}
+	setIndex(resp, reply.Index)
+
+	return nil, nil
+}
@@ -0,0 +1,100 @@
+package agent
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPServer_Variables(t *testing.T) {
+	ci.Parallel(t)
+
+	httpTest(t, nil, func(s *TestAgent) {
+
+		// The leader initializes the keyring asynchronously, so wait for it
+		// before writing any variables.
+		testutil.WaitForResult(func() (bool, error) {
+			keyMeta, err := s.Agent.server.State().GetActiveRootKeyMeta(memdb.NewWatchSet())
+			if err != nil {
+				return false, err
+			}
+			if keyMeta == nil {
+				return false, fmt.Errorf("keyring not initialized")
+			}
+			return true, nil
+		}, func(err error) {
+			require.NoError(t, err)
+		})
+
+		variable := mock.VariableDecrypted()
+		path := "/v1/var/" + variable.Path
+
+		// Reading a variable which does not exist should return a 404.
+		req, err := http.NewRequest(http.MethodGet, path, nil)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+		_, err = s.Server.VariableSpecificRequest(respW, req)
+		require.Error(t, err)
+		codedErr, ok := err.(HTTPCodedError)
+		require.True(t, ok)
+		require.Equal(t, http.StatusNotFound, codedErr.Code())
+
+		// Write the variable; the path within the URL takes precedence.
+		body := variable.Copy()
+		body.Path = "ignored"
+		req, err = http.NewRequest(http.MethodPut, path, encodeReq(body))
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		obj, err := s.Server.VariableSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.NotEmpty(t, respW.Header().Get("X-Nomad-Index"))
+
+		written := obj.(*structs.VariableDecrypted)
+		require.Equal(t, variable.Path, written.Path)
+		require.Equal(t, variable.Items, written.Items)
+
+		// Read the variable back.
+		req, err = http.NewRequest(http.MethodGet, path, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.VariableSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.Equal(t, variable.Items, obj.(*structs.VariableDecrypted).Items)
+
+		// List the variables.
+		req, err = http.NewRequest(http.MethodGet, "/v1/vars?prefix=nomad/jobs", nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.VariablesListRequest(respW, req)
+		require.NoError(t, err)
+		list := obj.([]*structs.VariableMetadata)
+		require.Len(t, list, 1)
+		require.Equal(t, variable.Path, list[0].Path)
+
+		// Only GET is supported by the list endpoint.
+		req, err = http.NewRequest(http.MethodPost, "/v1/vars", nil)
+		require.NoError(t, err)
+		_, err = s.Server.VariablesListRequest(httptest.NewRecorder(), req)
+		require.EqualError(t, err, ErrInvalidMethod)
+
+		// Delete the variable.
+		req, err = http.NewRequest(http.MethodDelete, path, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.VariableSpecificRequest(respW, req)
+		require.NoError(t, err)
+
+		req, err = http.NewRequest(http.MethodGet, path, nil)
+		require.NoError(t, err)
+		_, err = s.Server.VariableSpecificRequest(httptest.NewRecorder(), req)
+		require.Error(t, err)
+	})
+}
@@ -428,6 +428,14 @@ func (j *JobGetter) ApiJobWithArgs(jpath string, vars []string, varfiles []strin
 }
 
 func (j *JobGetter) Get(jpath string) (*api.Job, error) {
+	_, job, err := j.GetWithSubmission(jpath)
+	return job, err
+}
+
+// GetWithSubmission returns the Job struct from jobfile along with the
+// source it was parsed from, so the source can be stored with the job
+// version.
+func (j *JobGetter) GetWithSubmission(jpath string) (*api.JobSubmission, *api.Job, error) {
 	var jobfile io.Reader
 	pathName := filepath.Base(jpath)
 	switch jpath {
@@ -440,23 +448,23 @@ func (j *JobGetter) Get(jpath string) (*api.Job, error) {
 		pathName = "stdin"
 	default:
 		if len(jpath) == 0 {
-			return nil, fmt.Errorf("Error jobfile path has to be specified.")
+			return nil, nil, fmt.Errorf("Error jobfile path has to be specified.")
 		}
 
 		jobFile, err := os.CreateTemp("", "jobfile")
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		defer os.Remove(jobFile.Name())
 
 		if err := jobFile.Close(); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		// Get the pwd
 		pwd, err := os.Getwd()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		client := &gg.Client{
@@ -469,24 +477,35 @@ func (j *JobGetter) Get(jpath string) (*api.Job, error) {
 		}
 
 		if err := client.Get(); err != nil {
-			return nil, fmt.Errorf("Error getting jobfile from %q: %v", jpath, err)
+			return nil, nil, fmt.Errorf("Error getting jobfile from %q: %v", jpath, err)
 		} else {
 			file, err := os.Open(jobFile.Name())
 			if err != nil {
-				return nil, fmt.Errorf("Error opening file %q: %v", jpath, err)
+				return nil, nil, fmt.Errorf("Error opening file %q: %v", jpath, err)
 			}
 			defer file.Close()
 			jobfile = file
 		}
 	}
 
+	// Read the source so it can be submitted along with the job
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, jobfile); err != nil {
+		return nil, nil, fmt.Errorf("Error reading job file from %s: %v", jpath, err)
+	}
+	source := buf.Bytes()
+	jobfile = bytes.NewReader(source)
+
 	// Parse the JobFile
 	var jobStruct *api.Job
 	var err error
+	format := api.JobSubmissionFormatHCL2
 	switch {
 	case j.HCL1:
+		format = api.JobSubmissionFormatHCL1
 		jobStruct, err = jobspec.Parse(jobfile)
 	case j.JSON:
+		format = api.JobSubmissionFormatJSON
 		// Support JSON files with both a top-level Job key as well as
 		// ones without.
 		eitherJob := struct {
@@ -495,7 +514,7 @@ func (j *JobGetter) Get(jpath string) (*api.Job, error) {
 		}{}
 
 		if err := json.NewDecoder(jobfile).Decode(&eitherJob); err != nil {
-			return nil, fmt.Errorf("Failed to parse JSON job: %w", err)
+			return nil, nil, fmt.Errorf("Failed to parse JSON job: %w", err)
 		}
 
 		if eitherJob.NestedJob != nil {
@@ -504,14 +523,9 @@ func (j *JobGetter) Get(jpath string) (*api.Job, error) {
 			jobStruct = &eitherJob.Job
 		}
 	default:
-		var buf bytes.Buffer
-		_, err = io.Copy(&buf, jobfile)
-		if err != nil {
-			return nil, fmt.Errorf("Error reading job file from %s: %v", jpath, err)
-		}
 		jobStruct, err = jobspec2.ParseWithConfig(&jobspec2.ParseConfig{
 			Path:     pathName,
-			Body:     buf.Bytes(),
+			Body:     source,
 			ArgVars:  j.Vars,
 			AllowFS:  true,
 			VarFiles: j.VarFiles,
@@ -520,17 +534,65 @@ func (j *JobGetter) Get(jpath string) (*api.Job, error) {
 		})
 
 		if err != nil {
-			if _, merr := jobspec.Parse(&buf); merr == nil {
-				return nil, fmt.Errorf("Failed to parse using HCL 2. Use the HCL 1 parser with `nomad run -hcl1`, or address the following issues:\n%v", err)
+			if _, merr := jobspec.Parse(bytes.NewReader(source)); merr == nil {
+				return nil, nil, fmt.Errorf("Failed to parse using HCL 2. Use the HCL 1 parser with `nomad run -hcl1`, or address the following issues:\n%v", err)
 			}
 		}
 	}
 
 	if err != nil {
-		return nil, fmt.Errorf("Error parsing job file from %s:\n%v", jpath, err)
+		return nil, nil, fmt.Errorf("Error parsing job file from %s:\n%v", jpath, err)
 	}
 
-	return jobStruct, nil
+	submission := &api.JobSubmission{
+		Source: string(source),
+		Format: format,
+	}
+	if format == api.JobSubmissionFormatHCL2 {
+		if submission.VariableFlags, err = parseVariableFlags(j.Vars); err != nil {
+			return nil, nil, err
+		}
+		if submission.Variables, err = readVariableFiles(j.VarFiles); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return submission, jobStruct, nil
+}
+
+// parseVariableFlags returns the HCL2 variables set with the -var flag as a
+// map of variable name to value.
+func parseVariableFlags(vars []string) (map[string]string, error) {
+	if len(vars) == 0 {
+		return nil, nil
+	}
+
+	out := make(map[string]string, len(vars))
+	for _, v := range vars {
+		name, value, ok := strings.Cut(v, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid variable %q: must be of the form name=value", v)
+		}
+		out[name] = value
+	}
+	return out, nil
+}
+
+// readVariableFiles returns the concatenated content of the HCL2 variable
+// files.
+func readVariableFiles(paths []string) (string, error) {
+	var buf strings.Builder
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("Error reading variable file %q: %v", path, err)
+		}
+		buf.Write(content)
+		if len(content) > 0 && content[len(content)-1] != '\n' {
+			buf.WriteByte('\n')
+		}
+	}
+	return buf.String(), nil
 }
 
 // mergeAutocompleteFlags is used to join multiple flag completion sets.
@@ -376,6 +376,64 @@ job "example" {
 	require.Equal(t, expected, j.Datacenters)
 }
 
+func TestJobGetter_GetWithSubmission(t *testing.T) {
+	ci.Parallel(t)
+
+	hcl := `
+variable "var1" {}
+
+job "example" {
+  datacenters = ["${var.var1}", "${var.var2}"]
+}
+
+variable "var2" {}
+`
+
+	hclf, err := ioutil.TempFile("", "hcl")
+	require.NoError(t, err)
+	defer os.Remove(hclf.Name())
+	defer hclf.Close()
+
+	_, err = hclf.WriteString(hcl)
+	require.NoError(t, err)
+
+	vf, err := ioutil.TempFile("", "var.hcl")
+	require.NoError(t, err)
+	defer os.Remove(vf.Name())
+	defer vf.Close()
+
+	_, err = vf.WriteString(`var2 = "from-varfile"`)
+	require.NoError(t, err)
+
+	getter := &JobGetter{
+		Vars:     []string{"var1=from-cli"},
+		VarFiles: []string{vf.Name()},
+		Strict:   true,
+	}
+	sub, j, err := getter.GetWithSubmission(hclf.Name())
+	require.NoError(t, err)
+	require.NotNil(t, j)
+	require.Equal(t, []string{"from-cli", "from-varfile"}, j.Datacenters)
+
+	require.Equal(t, hcl, sub.Source)
+	require.Equal(t, api.JobSubmissionFormatHCL2, sub.Format)
+	require.Equal(t, map[string]string{"var1": "from-cli"}, sub.VariableFlags)
+	require.Equal(t, "var2 = \"from-varfile\"\n", sub.Variables)
+
+	// HCL1 jobs are submitted without variables
+	getter = &JobGetter{HCL1: true}
+	hclf.Truncate(0)
+	hclf.Seek(0, 0)
+	_, err = hclf.WriteString(`job "example" { datacenters = ["dc1"] }`)
+	require.NoError(t, err)
+
+	sub, _, err = getter.GetWithSubmission(hclf.Name())
+	require.NoError(t, err)
+	require.Equal(t, api.JobSubmissionFormatHCL1, sub.Format)
+	require.Empty(t, sub.VariableFlags)
+	require.Empty(t, sub.Variables)
+}
+
 func TestJobGetter_HCL2_Variables_StrictFalse(t *testing.T) {
 	ci.Parallel(t)
 
@@ -2,6 +2,7 @@ package command
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/nomad/api"
@@ -32,6 +33,11 @@ Inspect Options:
   -version <job version>
     Display the job at the given job version.
 
+  -hcl
+    Output the original jobspec source the job version was submitted with.
+    The HCL2 variable values submitted with the job are written to stderr so
+    the source can be redirected to a file.
+
   -json
     Output the job in its JSON format.
 
@@ -49,6 +55,7 @@ func (c *JobInspectCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
 			"-version": complete.PredictAnything,
+			"-hcl":     complete.PredictNothing,
 			"-json":    complete.PredictNothing,
 			"-t":       complete.PredictAnything,
 		})
@@ -72,11 +79,12 @@ func (c *JobInspectCommand) AutocompleteArgs() complete.Predictor {
 func (c *JobInspectCommand) Name() string { return "job inspect" }
 
 func (c *JobInspectCommand) Run(args []string) int {
-	var json bool
+	var json, hcl bool
 	var tmpl, versionStr string
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&hcl, "hcl", false, "")
 	flags.BoolVar(&json, "json", false, "")
 	flags.StringVar(&tmpl, "t", "", "")
 	flags.StringVar(&versionStr, "version", "", "")
@@ -86,6 +94,12 @@ func (c *JobInspectCommand) Run(args []string) int {
 	}
 	args = flags.Args()
 
+	if hcl && (json || len(tmpl) > 0) {
+		c.Ui.Error("The -hcl flag cannot be combined with -json or -t")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
 	// Get the HTTP client
 	client, err := c.Meta.Client()
 	if err != nil {
@@ -154,6 +168,11 @@ func (c *JobInspectCommand) Run(args []string) int {
 		return 1
 	}
 
+	// Print the source the job was submitted with
+	if hcl {
+		return c.outputSubmission(client, job)
+	}
+
 	// If output format is specified, format and output the data
 	if json || len(tmpl) > 0 {
 		out, err := Format(json, tmpl, job)
@@ -187,6 +206,41 @@ func (c *JobInspectCommand) Run(args []string) int {
 	return 0
 }
 
+// outputSubmission prints the jobspec source of the job version, followed by
+// the variable values it was submitted with.
+func (c *JobInspectCommand) outputSubmission(client *api.Client, job *api.Job) int {
+	q := &api.QueryOptions{Namespace: *job.Namespace}
+	sub, _, err := client.Jobs().Submission(*job.ID, int(*job.Version), q)
+	if err != nil {
+		if strings.Contains(err.Error(), "job source not found") {
+			c.Ui.Error(fmt.Sprintf("No source was stored for version %d of job %q", *job.Version, *job.ID))
+			return 1
+		}
+		c.Ui.Error(fmt.Sprintf("Error querying job source: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(strings.TrimSuffix(sub.Source, "\n"))
+
+	if len(sub.VariableFlags) > 0 {
+		names := make([]string, 0, len(sub.VariableFlags))
+		for name := range sub.VariableFlags {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		lines := make([]string, 0, len(names))
+		for _, name := range names {
+			lines = append(lines, fmt.Sprintf("%s=%s", name, sub.VariableFlags[name]))
+		}
+		c.Ui.Warn(fmt.Sprintf("\nVariable Flags\n%s", strings.Join(lines, "\n")))
+	}
+	if sub.Variables != "" {
+		c.Ui.Warn(fmt.Sprintf("\nVariable Files\n%s", strings.TrimSuffix(sub.Variables, "\n")))
+	}
+	return 0
+}
+
 // getJob retrieves the job optionally at a particular version.
 func getJob(client *api.Client, namespace, jobID string, version *uint64) (*api.Job, error) {
 	var q *api.QueryOptions
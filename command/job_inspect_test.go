@@ -10,6 +10,7 @@ import (
 	"github.com/mitchellh/cli"
 	"github.com/posener/complete"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestInspectCommand_Implements(t *testing.T) {
@@ -61,6 +62,47 @@ func TestInspectCommand_Fails(t *testing.T) {
 	}
 }
 
+func TestInspectCommand_HCL(t *testing.T) {
+	ci.Parallel(t)
+	srv, _, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	cmd := &JobInspectCommand{Meta: Meta{Ui: ui}}
+
+	state := srv.Agent.Server().State()
+	j := mock.Job()
+	sub := &structs.JobSubmission{
+		Source:        `job "example" {}`,
+		Format:        structs.JobSubmissionFormatHCL2,
+		VariableFlags: map[string]string{"count": "3"},
+	}
+	require.NoError(t, state.UpsertJobWithSubmission(structs.MsgTypeTestSetup, 1000, sub, j))
+
+	// Fails when combined with -json
+	code := cmd.Run([]string{"-address=" + url, "-hcl", "-json", j.ID})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "cannot be combined")
+	ui.ErrorWriter.Reset()
+
+	// Outputs the source and the variables
+	code = cmd.Run([]string{"-address=" + url, "-hcl", j.ID})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Equal(t, sub.Source+"\n", ui.OutputWriter.String())
+	require.Contains(t, ui.ErrorWriter.String(), "count=3")
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Fails when the version was registered without its source
+	j2 := j.Copy()
+	j2.Meta["updated"] = "true"
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1001, j2))
+
+	code = cmd.Run([]string{"-address=" + url, "-hcl", j.ID})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "No source was stored")
+}
+
 func TestInspectCommand_AutocompleteArgs(t *testing.T) {
 	ci.Parallel(t)
 	assert := assert.New(t)
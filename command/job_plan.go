@@ -57,6 +57,10 @@ Alias: nomad plan
   If the job has specified the region, the -region flag and NOMAD_REGION
   environment variable are overridden and the job's region is used.
 
+  The -json-output flag emits the plan results as a JSON document instead of
+  the human-readable diff, which is suitable to be consumed by other tools.
+  The exit codes are the same in both modes.
+
   Plan will return one of the following exit codes:
     * 0: No allocations created or destroyed.
     * 1: Allocations created or destroyed.
@@ -80,6 +84,11 @@ Plan Options:
     from "nomad job inspect" or "nomad run -output", the value of the field is
     used as the job.
 
+  -json-output
+    Output the plan results in JSON format, including the structured diff,
+    the desired updates of each task group, the placement failures and the job
+    modify index. Multiregion jobs output an object keyed by region name.
+
   -hcl1
     Parses the job file as HCLv1.
 
@@ -114,6 +123,7 @@ func (c *JobPlanCommand) AutocompleteFlags() complete.Flags {
 			"-policy-override": complete.PredictNothing,
 			"-verbose":         complete.PredictNothing,
 			"-json":            complete.PredictNothing,
+			"-json-output":     complete.PredictNothing,
 			"-hcl1":            complete.PredictNothing,
 			"-hcl2-strict":     complete.PredictNothing,
 			"-var":             complete.PredictAnything,
@@ -131,7 +141,7 @@ func (c *JobPlanCommand) AutocompleteArgs() complete.Predictor {
 
 func (c *JobPlanCommand) Name() string { return "job plan" }
 func (c *JobPlanCommand) Run(args []string) int {
-	var diff, policyOverride, verbose bool
+	var diff, policyOverride, verbose, jsonOutput bool
 
 	flagSet := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flagSet.Usage = func() { c.Ui.Output(c.Help()) }
@@ -139,6 +149,7 @@ func (c *JobPlanCommand) Run(args []string) int {
 	flagSet.BoolVar(&policyOverride, "policy-override", false, "")
 	flagSet.BoolVar(&verbose, "verbose", false, "")
 	flagSet.BoolVar(&c.JobGetter.JSON, "json", false, "")
+	flagSet.BoolVar(&jsonOutput, "json-output", false, "")
 	flagSet.BoolVar(&c.JobGetter.HCL1, "hcl1", false, "")
 	flagSet.BoolVar(&c.JobGetter.Strict, "hcl2-strict", true, "")
 	flagSet.Var(&c.JobGetter.Vars, "var", "")
@@ -196,7 +207,7 @@ func (c *JobPlanCommand) Run(args []string) int {
 	}
 
 	if job.IsMultiregion() {
-		return c.multiregionPlan(client, job, opts, diff, verbose)
+		return c.multiregionPlan(client, job, opts, diff, verbose, jsonOutput)
 	}
 
 	// Submit the job
@@ -206,6 +217,10 @@ func (c *JobPlanCommand) Run(args []string) int {
 		return 255
 	}
 
+	if jsonOutput {
+		return c.outputJSON(newJobPlanOutput(resp, diff), getExitCode(resp))
+	}
+
 	runArgs := strings.Builder{}
 	for _, varArg := range c.JobGetter.Vars {
 		runArgs.WriteString(fmt.Sprintf("-var=%q ", varArg))
@@ -220,7 +235,7 @@ func (c *JobPlanCommand) Run(args []string) int {
 	return exitCode
 }
 
-func (c *JobPlanCommand) multiregionPlan(client *api.Client, job *api.Job, opts *api.PlanOptions, diff, verbose bool) int {
+func (c *JobPlanCommand) multiregionPlan(client *api.Client, job *api.Job, opts *api.PlanOptions, diff, verbose, jsonOutput bool) int {
 
 	var exitCode int
 	plans := map[string]*api.JobPlanResponse{}
@@ -243,6 +258,17 @@ func (c *JobPlanCommand) multiregionPlan(client *api.Client, job *api.Job, opts
 		return exitCode
 	}
 
+	if jsonOutput {
+		out := make(map[string]*jobPlanOutput, len(plans))
+		for regionName, resp := range plans {
+			out[regionName] = newJobPlanOutput(resp, diff)
+			if regionExitCode := getExitCode(resp); regionExitCode > exitCode {
+				exitCode = regionExitCode
+			}
+		}
+		return c.outputJSON(out, exitCode)
+	}
+
 	for regionName, resp := range plans {
 		c.Ui.Output(c.Colorize().Color(fmt.Sprintf("[bold]Region: %q[reset]", regionName)))
 		regionExitCode := c.outputPlannedJob(job, resp, diff, verbose)
@@ -280,6 +306,18 @@ func (c *JobPlanCommand) outputPlannedJob(job *api.Job, resp *api.JobPlanRespons
 	return getExitCode(resp)
 }
 
+// outputJSON prints the JSON plan output and returns the exit code, unless the
+// output could not be formatted.
+func (c *JobPlanCommand) outputJSON(out interface{}, exitCode int) int {
+	formatted, err := Format(true, "", out)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 255
+	}
+	c.Ui.Output(formatted)
+	return exitCode
+}
+
 // addPreemptions shows details about preempted allocations
 func (c *JobPlanCommand) addPreemptions(resp *api.JobPlanResponse) {
 	c.Ui.Output(c.Colorize().Color("[bold][yellow]Preemptions:\n[reset]"))
@@ -335,6 +373,83 @@ func (c *JobPlanCommand) addPreemptions(resp *api.JobPlanResponse) {
 
 }
 
+// jobPlanOutput is the JSON output of a plan. Its fields are part of the
+// documented output of the -json-output flag, so fields must only be added.
+type jobPlanOutput struct {
+	// JobModifyIndex is the index to pass to "nomad job run -check-index".
+	JobModifyIndex uint64
+
+	// Changes is the sum of the desired updates of all the task groups.
+	Changes *api.DesiredUpdates
+
+	// Diff is the structured diff between the remote and planned job. It is
+	// nil if the diff was disabled.
+	Diff *api.JobDiff
+
+	// DesiredTGUpdates are the desired updates of each task group.
+	DesiredTGUpdates map[string]*api.DesiredUpdates
+
+	// PreemptedAllocs are the allocations preempted by the plan.
+	PreemptedAllocs []*api.AllocationListStub
+
+	// FailedTGAllocs are the allocation metrics of the task groups which
+	// failed to place all their allocations.
+	FailedTGAllocs map[string]*api.AllocationMetric
+
+	// NextPeriodicLaunch is the next launch time of periodic jobs.
+	NextPeriodicLaunch *time.Time
+
+	// Warnings are the warnings about the job.
+	Warnings string
+
+	// SpreadViolations are the spreads whose max skew would be exceeded once
+	// the plan is applied.
+	SpreadViolations []*api.SpreadViolation
+}
+
+// newJobPlanOutput returns the JSON output of the plan response.
+func newJobPlanOutput(resp *api.JobPlanResponse, diff bool) *jobPlanOutput {
+	out := &jobPlanOutput{
+		JobModifyIndex:   resp.JobModifyIndex,
+		Changes:          &api.DesiredUpdates{},
+		DesiredTGUpdates: map[string]*api.DesiredUpdates{},
+		PreemptedAllocs:  []*api.AllocationListStub{},
+		FailedTGAllocs:   map[string]*api.AllocationMetric{},
+		Warnings:         resp.Warnings,
+		SpreadViolations: []*api.SpreadViolation{},
+	}
+	if diff {
+		out.Diff = resp.Diff
+	}
+	if resp.Annotations != nil {
+		for tg, d := range resp.Annotations.DesiredTGUpdates {
+			out.DesiredTGUpdates[tg] = d
+			out.Changes.Ignore += d.Ignore
+			out.Changes.Place += d.Place
+			out.Changes.Migrate += d.Migrate
+			out.Changes.Stop += d.Stop
+			out.Changes.InPlaceUpdate += d.InPlaceUpdate
+			out.Changes.DestructiveUpdate += d.DestructiveUpdate
+			out.Changes.Canary += d.Canary
+			out.Changes.Preemptions += d.Preemptions
+		}
+		if len(resp.Annotations.PreemptedAllocs) > 0 {
+			out.PreemptedAllocs = resp.Annotations.PreemptedAllocs
+		}
+		if len(resp.Annotations.SpreadViolations) > 0 {
+			out.SpreadViolations = resp.Annotations.SpreadViolations
+		}
+	}
+	for tg, metrics := range resp.FailedTGAllocs {
+		out.FailedTGAllocs[tg] = metrics
+	}
+	if !resp.NextPeriodicLaunch.IsZero() {
+		next := resp.NextPeriodicLaunch
+		out.NextPeriodicLaunch = &next
+	}
+	return out
+}
+
 type namespaceIdPair struct {
 	id        string
 	namespace string
@@ -397,6 +512,13 @@ func formatDryRun(resp *api.JobPlanResponse, job *api.Job) string {
 		}
 	}
 
+	if resp.Annotations != nil {
+		for _, v := range resp.Annotations.SpreadViolations {
+			out += fmt.Sprintf("[yellow]- WARNING: Task Group %q spread over %q would have a skew of %d, greater than its max skew of %d.[reset]\n",
+				v.TaskGroup, v.Attribute, v.Skew, v.MaxSkew)
+		}
+	}
+
 	if rolling != nil {
 		out += fmt.Sprintf("[green]- Rolling update, next evaluation will be in %s.\n", rolling.Wait)
 	}
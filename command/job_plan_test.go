@@ -1,6 +1,7 @@
 package command
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"os"
 	"strconv"
@@ -271,3 +272,56 @@ func TestPlanCommad_JSON(t *testing.T) {
 	require.Equal(t, 255, code)
 	require.Contains(t, ui.ErrorWriter.String(), "Error during plan: Put")
 }
+
+func TestPlanCommand_JSONOutput(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create a server without clients so the placements fail
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	cmd := &JobPlanCommand{Meta: Meta{Ui: ui}}
+
+	fh, err := ioutil.TempFile("", "nomad")
+	require.NoError(t, err)
+	defer os.Remove(fh.Name())
+	_, err = fh.WriteString(`
+job "job1" {
+  type = "service"
+  datacenters = [ "dc1" ]
+  group "group1" {
+    count = 2
+    task "task1" {
+      driver = "exec"
+      resources {
+        cpu = 1000
+        memory = 512
+      }
+    }
+  }
+}`)
+	require.NoError(t, err)
+
+	code := cmd.Run([]string{"-address=" + url, "-json-output", fh.Name()})
+	require.Equal(t, 1, code, ui.ErrorWriter.String())
+
+	var out jobPlanOutput
+	require.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &out))
+	require.Zero(t, out.JobModifyIndex)
+	require.NotNil(t, out.Diff)
+	require.Equal(t, "Added", out.Diff.Type)
+	require.EqualValues(t, 2, out.Changes.Place)
+	require.Contains(t, out.DesiredTGUpdates, "group1")
+	require.EqualValues(t, 2, out.DesiredTGUpdates["group1"].Place)
+	require.Contains(t, out.FailedTGAllocs, "group1")
+	require.Empty(t, out.PreemptedAllocs)
+
+	// The diff is omitted when disabled
+	ui.OutputWriter.Reset()
+	code = cmd.Run([]string{"-address=" + url, "-json-output", "-diff=false", fh.Name()})
+	require.Equal(t, 1, code, ui.ErrorWriter.String())
+	out = jobPlanOutput{}
+	require.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &out))
+	require.Nil(t, out.Diff)
+}
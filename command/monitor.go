@@ -348,6 +348,12 @@ func formatAllocMetrics(metrics *api.AllocationMetric, scores bool, prefix strin
 	for dim, num := range metrics.DimensionExhausted {
 		out += fmt.Sprintf("%s* Dimension %q exhausted on %d nodes\n", prefix, dim, num)
 	}
+	for reason, num := range metrics.PreemptionRejected {
+		out += fmt.Sprintf("%s* Preemption rejected on %d nodes: %s\n", prefix, num, reason)
+	}
+	for attribute, num := range metrics.SpreadSkewExceeded {
+		out += fmt.Sprintf("%s* Spread max skew over %q exceeded on %d nodes\n", prefix, attribute, num)
+	}
 
 	// Print quota info
 	for _, dim := range metrics.QuotaExhausted {
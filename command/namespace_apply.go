@@ -220,6 +220,8 @@ func parseNamespaceSpecImpl(result *api.Namespace, list *ast.ObjectList) error {
 	}
 
 	delete(m, "capabilities")
+	delete(m, "node_pool_config")
+	delete(m, "preemption_config")
 	delete(m, "meta")
 
 	// Decode the rest
@@ -243,6 +245,38 @@ func parseNamespaceSpecImpl(result *api.Namespace, list *ast.ObjectList) error {
 		}
 	}
 
+	npObj := list.Filter("node_pool_config")
+	if len(npObj.Items) > 0 {
+		for _, o := range npObj.Elem().Items {
+			ot, ok := o.Val.(*ast.ObjectType)
+			if !ok {
+				break
+			}
+			var npConf *api.NamespaceNodePoolConfiguration
+			if err := hcl.DecodeObject(&npConf, ot.List); err != nil {
+				return err
+			}
+			result.NodePoolConfiguration = npConf
+			break
+		}
+	}
+
+	pcObj := list.Filter("preemption_config")
+	if len(pcObj.Items) > 0 {
+		for _, o := range pcObj.Elem().Items {
+			ot, ok := o.Val.(*ast.ObjectType)
+			if !ok {
+				break
+			}
+			var pConf *api.NamespacePreemptionConfiguration
+			if err := hcl.DecodeObject(&pConf, ot.List); err != nil {
+				return err
+			}
+			result.PreemptionConfiguration = pConf
+			break
+		}
+	}
+
 	if metaO := list.Filter("meta"); len(metaO.Items) > 0 {
 		for _, o := range metaO.Elem().Items {
 			var m map[string]interface{}
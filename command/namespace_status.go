@@ -3,6 +3,7 @@ package command
 import (
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/nomad/api"
@@ -132,12 +133,34 @@ func formatNamespaceBasics(ns *api.Namespace) string {
 			disabled_drivers = strings.Join(ns.Capabilities.DisabledTaskDrivers, ",")
 		}
 	}
+	default_pool := "default"
+	allowed_pools := "*"
+	denied_pools := ""
+	if ns.NodePoolConfiguration != nil {
+		if ns.NodePoolConfiguration.Default != "" {
+			default_pool = ns.NodePoolConfiguration.Default
+		}
+		if len(ns.NodePoolConfiguration.Allowed) != 0 {
+			allowed_pools = strings.Join(ns.NodePoolConfiguration.Allowed, ",")
+		}
+		if len(ns.NodePoolConfiguration.Denied) != 0 {
+			denied_pools = strings.Join(ns.NodePoolConfiguration.Denied, ",")
+		}
+	}
+	preemption_max_priority := ""
+	if ns.PreemptionConfiguration != nil && ns.PreemptionConfiguration.MaxPriority != 0 {
+		preemption_max_priority = strconv.Itoa(ns.PreemptionConfiguration.MaxPriority)
+	}
 	basic := []string{
 		fmt.Sprintf("Name|%s", ns.Name),
 		fmt.Sprintf("Description|%s", ns.Description),
 		fmt.Sprintf("Quota|%s", ns.Quota),
 		fmt.Sprintf("EnabledDrivers|%s", enabled_drivers),
 		fmt.Sprintf("DisabledDrivers|%s", disabled_drivers),
+		fmt.Sprintf("DefaultNodePool|%s", default_pool),
+		fmt.Sprintf("AllowedNodePools|%s", allowed_pools),
+		fmt.Sprintf("DeniedNodePools|%s", denied_pools),
+		fmt.Sprintf("PreemptionMaxPriority|%s", preemption_max_priority),
 	}
 
 	return formatKV(basic)
@@ -68,6 +68,12 @@ Node Drain Options:
     Ignore system allows the drain to complete without stopping system job
     allocations. By default system jobs are stopped last.
 
+  -checkpoint
+    Checkpoint the tasks of migrated allocations and restore them on their new
+    node instead of restarting them. Only tasks of drivers supporting
+    checkpoints in groups with a migrating ephemeral disk are checkpointed,
+    other tasks are stopped as usual.
+
   -keep-ineligible
     Keep ineligible will maintain the node's scheduling ineligibility even if
     the drain is being disabled. This is useful when an existing drain is being
@@ -103,6 +109,7 @@ func (c *NodeDrainCommand) AutocompleteFlags() complete.Flags {
 			"-force":           complete.PredictNothing,
 			"-no-deadline":     complete.PredictNothing,
 			"-ignore-system":   complete.PredictNothing,
+			"-checkpoint":      complete.PredictNothing,
 			"-keep-ineligible": complete.PredictNothing,
 			"-m":               complete.PredictNothing,
 			"-meta":            complete.PredictNothing,
@@ -131,7 +138,7 @@ func (c *NodeDrainCommand) Name() string { return "node-drain" }
 func (c *NodeDrainCommand) Run(args []string) int {
 	var enable, disable, detach, force,
 		noDeadline, ignoreSystem, keepIneligible,
-		self, autoYes, monitor bool
+		checkpoint, self, autoYes, monitor bool
 	var deadline, message string
 	var metaVars flaghelper.StringFlag
 
@@ -145,6 +152,7 @@ func (c *NodeDrainCommand) Run(args []string) int {
 	flags.BoolVar(&noDeadline, "no-deadline", false, "Drain node with no deadline")
 	flags.BoolVar(&ignoreSystem, "ignore-system", false, "Do not drain system job allocations from the node")
 	flags.BoolVar(&keepIneligible, "keep-ineligible", false, "Do not update the nodes scheduling eligibility")
+	flags.BoolVar(&checkpoint, "checkpoint", false, "Checkpoint and restore the tasks of migrated allocations")
 	flags.BoolVar(&self, "self", false, "")
 	flags.BoolVar(&autoYes, "yes", false, "Automatic yes to prompts.")
 	flags.BoolVar(&monitor, "monitor", false, "Monitor drain status.")
@@ -178,7 +186,7 @@ func (c *NodeDrainCommand) Run(args []string) int {
 	}
 
 	// Validate a compatible set of flags were set
-	if disable && (deadline != "" || force || noDeadline || ignoreSystem) {
+	if disable && (deadline != "" || force || noDeadline || ignoreSystem || checkpoint) {
 		c.Ui.Error("-disable can't be combined with flags configuring drain strategy")
 		c.Ui.Error(commandErrorText(c))
 		return 1
@@ -308,6 +316,7 @@ func (c *NodeDrainCommand) Run(args []string) int {
 		spec = &api.DrainSpec{
 			Deadline:         d,
 			IgnoreSystemJobs: ignoreSystem,
+			Checkpoint:       checkpoint,
 		}
 	}
 
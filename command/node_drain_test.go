@@ -370,7 +370,7 @@ func TestNodeDrainCommand_Fails(t *testing.T) {
 	ui.ErrorWriter.Reset()
 
 	// Fail on disable being used with drain strategy flags
-	for _, flag := range []string{"-force", "-no-deadline", "-ignore-system"} {
+	for _, flag := range []string{"-force", "-no-deadline", "-ignore-system", "-checkpoint"} {
 		if code := cmd.Run([]string{"-address=" + url, "-disable", flag, "12345678-abcd-efab-cdef-123456789abc"}); code != 1 {
 			t.Fatalf("expected exit 1, got: %d", code)
 		}
@@ -0,0 +1,77 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+type NodePoolCommand struct {
+	Meta
+}
+
+func (c *NodePoolCommand) Help() string {
+	helpText := `
+Usage: nomad node pool <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with node pools. Node pools
+  partition the client nodes of a cluster so that jobs only run on the nodes
+  of the node pool they target. Nodes join a node pool with the client
+  "node_pool" configuration.
+
+  Create or update a node pool:
+
+      $ nomad node pool apply -description "Nodes of the ML team" <name>
+
+  List node pools:
+
+      $ nomad node pool list
+
+  View the details of a node pool:
+
+      $ nomad node pool info <name>
+
+  List the nodes of a node pool:
+
+      $ nomad node pool nodes <name>
+
+  Please see the individual subcommand help for detailed usage information.
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodePoolCommand) Synopsis() string {
+	return "Interact with node pools"
+}
+
+func (c *NodePoolCommand) Name() string { return "node pool" }
+
+func (c *NodePoolCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+// NodePoolPredictor returns a node pool predictor that can optionally filter
+// specific node pools.
+func NodePoolPredictor(factory ApiClientFactory, filter map[string]struct{}) complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := factory()
+		if err != nil {
+			return nil
+		}
+
+		pools, _, err := client.NodePools().PrefixList(a.Last, nil)
+		if err != nil {
+			return []string{}
+		}
+
+		names := make([]string, 0, len(pools))
+		for _, pool := range pools {
+			if _, ok := filter[pool.Name]; !ok {
+				names = append(names, pool.Name)
+			}
+		}
+		return names
+	})
+}
@@ -0,0 +1,87 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+var _ cli.Command = (*NodePoolApplyCommand)(nil)
+
+func TestNodePoolApplyCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	ui := cli.NewMockUi()
+	cmd := &NodePoolApplyCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	if code := cmd.Run([]string{"some", "bad", "args"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, commandErrorText(cmd)) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	if code := cmd.Run([]string{"-address=nope"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, commandErrorText(cmd)) {
+		t.Fatalf("name required error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+}
+
+func TestNodePoolApplyCommand_Good(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create a server
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	cmd := &NodePoolApplyCommand{Meta: Meta{Ui: ui}}
+
+	// Create a node pool
+	name, desc := "gpu", "nodes with gpus"
+	if code := cmd.Run([]string{"-address=" + url, "-description=" + desc, name}); code != 0 {
+		t.Fatalf("expected exit 0, got: %d; %v", code, ui.ErrorWriter.String())
+	}
+
+	pool, _, err := client.NodePools().Info(name, nil)
+	require.NoError(t, err)
+	require.Equal(t, desc, pool.Description)
+}
+
+func TestNodePoolApplyCommand_ParseSpec(t *testing.T) {
+	ci.Parallel(t)
+
+	input := `
+name        = "gpu"
+description = "nodes with gpus"
+
+meta {
+  team = "ml"
+}
+
+scheduler_config {
+  scheduler_algorithm             = "spread"
+  memory_oversubscription_enabled = true
+}
+`
+	pool, err := parseNodePoolSpec([]byte(input))
+	require.NoError(t, err)
+	require.Equal(t, &api.NodePool{
+		Name:        "gpu",
+		Description: "nodes with gpus",
+		Meta:        map[string]string{"team": "ml"},
+		SchedulerConfiguration: &api.NodePoolSchedulerConfiguration{
+			SchedulerAlgorithm:            api.SchedulerAlgorithmSpread,
+			MemoryOversubscriptionEnabled: helper.BoolToPtr(true),
+		},
+	}, pool)
+}
@@ -0,0 +1,95 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+	"github.com/stretchr/testify/require"
+)
+
+var _ cli.Command = (*NodePoolDeleteCommand)(nil)
+
+func TestNodePoolDeleteCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	ui := cli.NewMockUi()
+	cmd := &NodePoolDeleteCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	if code := cmd.Run([]string{"some", "bad", "args"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, commandErrorText(cmd)) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	if code := cmd.Run([]string{"-address=nope", "foo"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "deleting node pool") {
+		t.Fatalf("connection error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+}
+
+func TestNodePoolDeleteCommand_Good(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create a server
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	cmd := &NodePoolDeleteCommand{Meta: Meta{Ui: ui}}
+
+	// Create a node pool to delete
+	pool := &api.NodePool{
+		Name: "gpu",
+	}
+	_, err := client.NodePools().Register(pool, nil)
+	require.NoError(t, err)
+
+	// Delete the node pool
+	if code := cmd.Run([]string{"-address=" + url, pool.Name}); code != 0 {
+		t.Fatalf("expected exit 0, got: %d; %v", code, ui.ErrorWriter.String())
+	}
+
+	pools, _, err := client.NodePools().List(nil)
+	require.NoError(t, err)
+	require.Len(t, pools, 2)
+
+	// Built-in node pools cannot be deleted
+	if code := cmd.Run([]string{"-address=" + url, api.NodePoolDefault}); code != 1 {
+		t.Fatalf("expected exit 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "cannot be deleted") {
+		t.Fatalf("expected built-in error, got: %s", out)
+	}
+}
+
+func TestNodePoolDeleteCommand_AutocompleteArgs(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	cmd := &NodePoolDeleteCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	// Create a node pool whose name shares a prefix with the built-in pools
+	pool := &api.NodePool{
+		Name: "dev",
+	}
+	_, err := client.NodePools().Register(pool, nil)
+	require.NoError(t, err)
+
+	args := complete.Args{Last: "d"}
+	predictor := cmd.AutocompleteArgs()
+
+	res := predictor.Predict(args)
+	require.Equal(t, []string{pool.Name}, res)
+}
@@ -0,0 +1,182 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type NodePoolInfoCommand struct {
+	Meta
+}
+
+func (c *NodePoolInfoCommand) Help() string {
+	helpText := `
+Usage: nomad node pool info [options] <node-pool>
+
+  Info is used to view the details of a node pool, including its scheduler
+  configuration.
+
+  If ACLs are enabled, this command requires a token with the 'node:read'
+  capability.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Info Options:
+
+  -json
+    Output the node pool in a JSON format.
+
+  -t
+    Format and display the node pool using a Go template.
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodePoolInfoCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json": complete.PredictNothing,
+			"-t":    complete.PredictAnything,
+		})
+}
+
+func (c *NodePoolInfoCommand) AutocompleteArgs() complete.Predictor {
+	return NodePoolPredictor(c.Meta.Client, nil)
+}
+
+func (c *NodePoolInfoCommand) Synopsis() string {
+	return "Display the details of a node pool"
+}
+
+func (c *NodePoolInfoCommand) Name() string { return "node pool info" }
+
+func (c *NodePoolInfoCommand) Run(args []string) int {
+	var json bool
+	var tmpl string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <node-pool>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	name := args[0]
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Do a prefix lookup
+	pool, possible, err := getNodePool(client.NodePools(), name)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving node pool: %s", err))
+		return 1
+	}
+
+	if len(possible) != 0 {
+		c.Ui.Error(fmt.Sprintf("Prefix matched multiple node pools\n\n%s", formatNodePools(possible)))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, pool)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(formatNodePoolBasics(pool))
+
+	if len(pool.Meta) != 0 {
+		c.Ui.Output(c.Colorize().Color("\n[bold]Metadata[reset]"))
+		keys := make([]string, 0, len(pool.Meta))
+		for k := range pool.Meta {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		meta := make([]string, 0, len(keys))
+		for _, k := range keys {
+			meta = append(meta, fmt.Sprintf("%s|%s", k, pool.Meta[k]))
+		}
+		c.Ui.Output(formatKV(meta))
+	}
+
+	if sc := pool.SchedulerConfiguration; sc != nil {
+		c.Ui.Output(c.Colorize().Color("\n[bold]Scheduler Configuration[reset]"))
+		algorithm := "<cluster default>"
+		if sc.SchedulerAlgorithm != "" {
+			algorithm = string(sc.SchedulerAlgorithm)
+		}
+		oversubscription := "<cluster default>"
+		if sc.MemoryOversubscriptionEnabled != nil {
+			oversubscription = fmt.Sprintf("%v", *sc.MemoryOversubscriptionEnabled)
+		}
+		c.Ui.Output(formatKV([]string{
+			fmt.Sprintf("Scheduler Algorithm|%s", algorithm),
+			fmt.Sprintf("Memory Oversubscription Enabled|%s", oversubscription),
+		}))
+	}
+
+	return 0
+}
+
+// formatNodePoolBasics formats the basic information of the node pool
+func formatNodePoolBasics(pool *api.NodePool) string {
+	basic := []string{
+		fmt.Sprintf("Name|%s", pool.Name),
+		fmt.Sprintf("Description|%s", pool.Description),
+	}
+
+	return formatKV(basic)
+}
+
+func getNodePool(client *api.NodePools, name string) (match *api.NodePool, possible []*api.NodePool, err error) {
+	// Do a prefix lookup
+	pools, _, err := client.PrefixList(name, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	l := len(pools)
+	switch {
+	case l == 0:
+		return nil, nil, fmt.Errorf("Node pool %q matched no node pools", name)
+	case l == 1:
+		return pools[0], nil, nil
+	default:
+		// search for an exact match in the returned node pools
+		for _, pool := range pools {
+			if pool.Name == name {
+				return pool, nil, nil
+			}
+		}
+		// if not found, return the fuzzy matches.
+		return nil, pools, nil
+	}
+}
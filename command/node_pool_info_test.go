@@ -0,0 +1,67 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+var _ cli.Command = (*NodePoolInfoCommand)(nil)
+
+func TestNodePoolInfoCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	ui := cli.NewMockUi()
+	cmd := &NodePoolInfoCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	if code := cmd.Run([]string{"some", "bad", "args"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, commandErrorText(cmd)) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	if code := cmd.Run([]string{"-address=nope", "foo"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "retrieving node pool") {
+		t.Fatalf("connection error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+}
+
+func TestNodePoolInfoCommand_Good(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create a server
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	cmd := &NodePoolInfoCommand{Meta: Meta{Ui: ui}}
+
+	// Create a node pool
+	pool := &api.NodePool{
+		Name:        "gpu",
+		Description: "nodes with gpus",
+		SchedulerConfiguration: &api.NodePoolSchedulerConfiguration{
+			SchedulerAlgorithm: api.SchedulerAlgorithmSpread,
+		},
+	}
+	_, err := client.NodePools().Register(pool, nil)
+	require.NoError(t, err)
+
+	// Query the node pool by prefix
+	if code := cmd.Run([]string{"-address=" + url, "gp"}); code != 0 {
+		t.Fatalf("expected exit 0, got: %d; %v", code, ui.ErrorWriter.String())
+	}
+
+	out := ui.OutputWriter.String()
+	require.Contains(t, out, pool.Description)
+	require.Contains(t, out, "spread")
+}
@@ -0,0 +1,117 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type NodePoolNodesCommand struct {
+	Meta
+}
+
+func (c *NodePoolNodesCommand) Help() string {
+	helpText := `
+Usage: nomad node pool nodes [options] <node-pool>
+
+  Nodes is used to list the nodes of a node pool. Listing the nodes of the
+  built-in "all" node pool returns every node of the cluster.
+
+  If ACLs are enabled, this command requires a token with the 'node:read'
+  capability.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Nodes Options:
+
+  -verbose
+    Display full information.
+
+  -json
+    Output the nodes in a JSON format.
+
+  -t
+    Format and display the nodes using a Go template.
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodePoolNodesCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-verbose": complete.PredictNothing,
+			"-json":    complete.PredictNothing,
+			"-t":       complete.PredictAnything,
+		})
+}
+
+func (c *NodePoolNodesCommand) AutocompleteArgs() complete.Predictor {
+	return NodePoolPredictor(c.Meta.Client, nil)
+}
+
+func (c *NodePoolNodesCommand) Synopsis() string {
+	return "List the nodes of a node pool"
+}
+
+func (c *NodePoolNodesCommand) Name() string { return "node pool nodes" }
+
+func (c *NodePoolNodesCommand) Run(args []string) int {
+	var json, verbose bool
+	var tmpl string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&verbose, "verbose", false, "")
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <node-pool>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	name := args[0]
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	nodes, _, err := client.NodePools().ListNodes(name, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving node pool nodes: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, nodes)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	if len(nodes) == 0 {
+		c.Ui.Output(fmt.Sprintf("No nodes in node pool %q", name))
+		return 0
+	}
+
+	c.Ui.Output(formatNodeStubList(nodes, verbose))
+	return 0
+}
@@ -453,6 +453,9 @@ func formatDrain(n *api.Node) string {
 		if n.DrainStrategy.IgnoreSystemJobs {
 			b.WriteString("; ignoring system jobs")
 		}
+		if n.DrainStrategy.Checkpoint {
+			b.WriteString("; checkpointing tasks")
+		}
 		return b.String()
 	}
 
@@ -480,6 +483,7 @@ func (c *NodeStatusCommand) formatNode(client *api.Client, node *api.Node) int {
 		fmt.Sprintf("Name|%s", node.Name),
 		fmt.Sprintf("Class|%s", node.NodeClass),
 		fmt.Sprintf("DC|%s", node.Datacenter),
+		fmt.Sprintf("Node Pool|%s", node.NodePool),
 		fmt.Sprintf("Drain|%v", formatDrain(node)),
 		fmt.Sprintf("Eligibility|%s", node.SchedulingEligibility),
 		fmt.Sprintf("Status|%s", node.Status),
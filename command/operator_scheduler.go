@@ -0,0 +1,44 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type OperatorSchedulerCommand struct {
+	Meta
+}
+
+func (c *OperatorSchedulerCommand) Name() string { return "operator scheduler" }
+
+func (c *OperatorSchedulerCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+func (c *OperatorSchedulerCommand) Synopsis() string {
+	return "Provides access to the scheduler of the cluster"
+}
+
+func (c *OperatorSchedulerCommand) Help() string {
+	helpText := `
+Usage: nomad operator scheduler <subcommand> [options]
+
+  This command groups subcommands for interacting with Nomad's scheduler.
+
+  Report the allocations which would be migrated to rebalance the cluster:
+
+      $ nomad operator scheduler rebalance -dry-run
+
+  Migrate the allocations to rebalance the cluster:
+
+      $ nomad operator scheduler rebalance
+
+  Simulate the scheduling of a job against the state saved in a snapshot:
+
+      $ nomad operator scheduler simulate -snapshot=backup.snap example.nomad
+
+  Please see the individual subcommand help for detailed usage information.
+  `
+	return strings.TrimSpace(helpText)
+}
@@ -0,0 +1,135 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type OperatorSchedulerRebalanceCommand struct {
+	Meta
+}
+
+func (c *OperatorSchedulerRebalanceCommand) Help() string {
+	helpText := `
+Usage: nomad operator scheduler rebalance [options]
+
+  Migrates the allocations of service jobs placed on skewed spreads, overly
+  dense nodes or nodes violating their affinities, following the rebalance
+  configuration of the scheduler. No more allocations of a task group are
+  migrated at once than its migrate block allows.
+
+  If ACLs are enabled, this command requires a token with the 'operator:write'
+  capability, or the 'operator:read' capability with the -dry-run flag.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Rebalance Options:
+
+  -dry-run
+    Only report the allocations which would be migrated, without migrating
+    them. Reports are available even when rebalancing is disabled in the
+    scheduler configuration.
+
+  -verbose
+    Display full information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorSchedulerRebalanceCommand) Synopsis() string {
+	return "Migrate allocations to rebalance the cluster"
+}
+
+func (c *OperatorSchedulerRebalanceCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-dry-run": complete.PredictNothing,
+			"-verbose": complete.PredictNothing,
+		})
+}
+
+func (c *OperatorSchedulerRebalanceCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OperatorSchedulerRebalanceCommand) Name() string { return "operator scheduler rebalance" }
+
+func (c *OperatorSchedulerRebalanceCommand) Run(args []string) int {
+	var dryRun, verbose bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&dryRun, "dry-run", false, "")
+	flags.BoolVar(&verbose, "verbose", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	if args = flags.Args(); len(args) != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	resp, _, err := client.Operator().SchedulerRebalance(&api.SchedulerRebalanceRequest{DryRun: dryRun}, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error rebalancing the cluster: %s", err))
+		return 1
+	}
+
+	if len(resp.Migrations) == 0 {
+		c.Ui.Output("No allocations to migrate")
+		return 0
+	}
+
+	c.Ui.Output(formatRebalanceMigrations(resp.Migrations, length))
+	if dryRun {
+		return 0
+	}
+
+	c.Ui.Output("")
+	c.Ui.Output(c.Colorize().Color(fmt.Sprintf(
+		"[bold]Migrating %d allocations with %d evaluations[reset]", len(resp.Migrations), len(resp.EvalIDs))))
+	for _, evalID := range resp.EvalIDs {
+		c.Ui.Output(fmt.Sprintf("  Evaluation ID: %s", limit(evalID, length)))
+	}
+	return 0
+}
+
+// formatRebalanceMigrations returns a table of the allocations selected for
+// migration.
+func formatRebalanceMigrations(migrations []*api.RebalanceMigration, length int) string {
+	out := make([]string, len(migrations)+1)
+	out[0] = "Alloc ID|Namespace|Job ID|Task Group|Node ID|Reason"
+	for i, m := range migrations {
+		out[i+1] = fmt.Sprintf("%s|%s|%s|%s|%s|%s",
+			limit(m.AllocID, length),
+			m.Namespace,
+			m.JobID,
+			m.TaskGroup,
+			limit(m.NodeID, length),
+			m.Reason,
+		)
+	}
+	return formatList(out)
+}
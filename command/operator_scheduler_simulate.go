@@ -0,0 +1,372 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/helper/raftutil"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/scheduler"
+	"github.com/posener/complete"
+)
+
+type OperatorSchedulerSimulateCommand struct {
+	Meta
+	JobGetter
+}
+
+func (c *OperatorSchedulerSimulateCommand) Help() string {
+	helpText := `
+Usage: nomad operator scheduler simulate [options] -snapshot=<file> <path>
+
+  Simulates the registration of a job against the state of a cluster restored
+  from a snapshot file, as saved by "nomad operator snapshot save". The
+  scheduler of the job's type is run locally against the restored state and
+  the resulting placements, placement failures, stopped allocations and
+  preemptions are displayed. The cluster the snapshot was taken from is not
+  contacted or modified.
+
+  If the supplied path is "-", the jobfile is read from stdin. Otherwise
+  it is read from the file at the supplied path or downloaded and
+  read from URL specified.
+
+  The job is registered as submitted, without the admission controllers of
+  the servers. Periodic and parameterized jobs, as well as jobs using a
+  scheduler plugin, cannot be simulated.
+
+  Plans are checked against namespace quotas and preemption disruption
+  budgets like the servers do, but as the simulation runs a single
+  scheduler against the snapshot, the rejections caused by concurrent
+  plans are not simulated. Quotas are enforced with the limits of the
+  job's region, so the snapshot should be taken from the servers of that
+  region.
+
+Simulate Options:
+
+  -snapshot=<file>
+    Path to the snapshot file to restore the state from. Required.
+
+  -json
+    Parses the job file as JSON. If the outer object has a Job field, such as
+    from "nomad job inspect" or "nomad run -output", the value of the field is
+    used as the job.
+
+  -hcl1
+    Parses the job file as HCLv1.
+
+  -hcl2-strict
+    Whether an error should be produced from the HCL2 parser where a variable
+    has been supplied which is not defined within the root variables. Defaults
+    to true.
+
+  -var 'key=value'
+    Variable for template, can be used multiple times.
+
+  -var-file=path
+    Path to HCL2 file containing user variables.
+
+  -verbose
+    Display full information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorSchedulerSimulateCommand) Synopsis() string {
+	return "Simulate the scheduling of a job against a snapshot"
+}
+
+func (c *OperatorSchedulerSimulateCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-snapshot":    complete.PredictFiles("*"),
+		"-json":        complete.PredictNothing,
+		"-hcl1":        complete.PredictNothing,
+		"-hcl2-strict": complete.PredictNothing,
+		"-var":         complete.PredictAnything,
+		"-var-file":    complete.PredictFiles("*.var"),
+		"-verbose":     complete.PredictNothing,
+	}
+}
+
+func (c *OperatorSchedulerSimulateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictOr(
+		complete.PredictFiles("*.nomad"),
+		complete.PredictFiles("*.hcl"),
+		complete.PredictFiles("*.json"),
+	)
+}
+
+func (c *OperatorSchedulerSimulateCommand) Name() string { return "operator scheduler simulate" }
+
+func (c *OperatorSchedulerSimulateCommand) Run(args []string) int {
+	var snapshotPath string
+	var verbose bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetNone)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&snapshotPath, "snapshot", "", "")
+	flags.BoolVar(&verbose, "verbose", false, "")
+	flags.BoolVar(&c.JobGetter.JSON, "json", false, "")
+	flags.BoolVar(&c.JobGetter.HCL1, "hcl1", false, "")
+	flags.BoolVar(&c.JobGetter.Strict, "hcl2-strict", true, "")
+	flags.Var(&c.JobGetter.Vars, "var", "")
+	flags.Var(&c.JobGetter.VarFiles, "var-file", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one job
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <path>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	if snapshotPath == "" {
+		c.Ui.Error("The -snapshot flag is required")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	if err := c.JobGetter.Validate(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Invalid job options: %s", err))
+		return 1
+	}
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	apiJob, err := c.JobGetter.Get(args[0])
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error getting job struct: %s", err))
+		return 1
+	}
+
+	job := agent.ApiJobToStructJob(apiJob)
+	job.Canonicalize()
+	if err := job.Validate(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error validating job: %s", err))
+		return 1
+	}
+
+	f, err := os.Open(snapshotPath)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error opening snapshot file: %s", err))
+		return 1
+	}
+	defer f.Close()
+
+	// Restore the state in the region of the job so the quota limits of the
+	// region the job would be scheduled in are enforced
+	store, meta, err := raftutil.RestoreFromArchiveInRegion(f, job.Region)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to read archive file: %s", err))
+		return 1
+	}
+
+	// Keep a snapshot of the restored state to tell placements apart from
+	// in-place updates of existing allocations
+	before, err := store.Snapshot()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error snapshotting state: %s", err))
+		return 1
+	}
+
+	sim, err := scheduler.NewSimulation(hclog.NewNullLogger(), store)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error creating simulation: %s", err))
+		return 1
+	}
+	eval, err := sim.Register(job)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error registering job: %s", err))
+		return 1
+	}
+	if err := sim.Process(eval); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error processing evaluation: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(c.Colorize().Color(fmt.Sprintf(
+		"[bold]Simulated job %q against snapshot at index %d[reset]", job.ID, meta.Index)))
+
+	placements, stops, preemptions, err := simulatedAllocs(before, sim.Plans)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error looking up allocations: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(c.Colorize().Color("\n[bold]Placements[reset]"))
+	if len(placements) == 0 {
+		c.Ui.Output("No allocations placed")
+	} else {
+		c.Ui.Output(formatSimulatedPlacements(before, placements, length))
+	}
+
+	if len(stops) > 0 {
+		c.Ui.Output(c.Colorize().Color("\n[bold]Stopped Allocations[reset]"))
+		c.Ui.Output(formatSimulatedStops(stops, length))
+	}
+
+	if len(preemptions) > 0 {
+		c.Ui.Output(c.Colorize().Color("\n[bold]Preemptions[reset]"))
+		c.Ui.Output(formatSimulatedPreemptions(preemptions, length))
+	}
+
+	// The failures are reported on the last update of the evaluation
+	var failed map[string]*structs.AllocMetric
+	if n := len(sim.Evals); n > 0 {
+		failed = sim.Evals[n-1].FailedTGAllocs
+	}
+	if len(failed) == 0 {
+		c.Ui.Output(c.Colorize().Color("\n[bold][green]All tasks successfully allocated[reset]"))
+		return 0
+	}
+
+	c.Ui.Output(c.Colorize().Color("\n[bold]Placement Failures[reset]"))
+	metrics := make(map[string]*api.AllocationMetric, len(failed))
+	for tg, m := range failed {
+		metric, err := apiAllocMetric(m)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error converting allocation metrics: %s", err))
+			return 1
+		}
+		metrics[tg] = metric
+	}
+	for _, tg := range sortedTaskGroupFromMetrics(metrics) {
+		metric := metrics[tg]
+
+		noun := "allocation"
+		if metric.CoalescedFailures > 0 {
+			noun += "s"
+		}
+		c.Ui.Output(fmt.Sprintf("Task Group %q (failed to place %d %s):", tg, metric.CoalescedFailures+1, noun))
+		c.Ui.Output(strings.TrimSuffix(formatAllocMetrics(metric, false, "  "), "\n"))
+	}
+	return 0
+}
+
+// simulatedAllocs returns the allocations placed, stopped and preempted by
+// the plans, sorted by ID.
+func simulatedAllocs(before *state.StateSnapshot, plans []*structs.Plan) (placements, stops, preemptions []*structs.Allocation, err error) {
+	for _, plan := range plans {
+		for _, allocs := range plan.NodeAllocation {
+			placements = append(placements, allocs...)
+		}
+		for _, allocs := range plan.NodeUpdate {
+			stops = append(stops, allocs...)
+		}
+		for _, allocs := range plan.NodePreemptions {
+			preemptions = append(preemptions, allocs...)
+		}
+	}
+
+	// Stopped and preempted allocations only carry the fields which changed
+	lookup := func(allocs []*structs.Allocation) error {
+		for i, alloc := range allocs {
+			existing, err := before.AllocByID(nil, alloc.ID)
+			if err != nil {
+				return err
+			}
+			if existing != nil {
+				allocs[i] = existing
+			}
+		}
+		return nil
+	}
+	if err := lookup(stops); err != nil {
+		return nil, nil, nil, err
+	}
+	if err := lookup(preemptions); err != nil {
+		return nil, nil, nil, err
+	}
+
+	for _, allocs := range [][]*structs.Allocation{placements, stops, preemptions} {
+		sort.Slice(allocs, func(i, j int) bool { return allocs[i].ID < allocs[j].ID })
+	}
+	return placements, stops, preemptions, nil
+}
+
+// formatSimulatedPlacements returns a table of the allocations placed by the
+// simulation, marking the in-place updates of existing allocations.
+func formatSimulatedPlacements(before *state.StateSnapshot, allocs []*structs.Allocation, length int) string {
+	out := make([]string, len(allocs)+1)
+	out[0] = "Alloc ID|Name|Node ID|Node Name|Type"
+	for i, alloc := range allocs {
+		placement := "create"
+		if existing, _ := before.AllocByID(nil, alloc.ID); existing != nil {
+			placement = "in-place update"
+		}
+		out[i+1] = fmt.Sprintf("%s|%s|%s|%s|%s",
+			limit(alloc.ID, length),
+			alloc.Name,
+			limit(alloc.NodeID, length),
+			alloc.NodeName,
+			placement,
+		)
+	}
+	return formatList(out)
+}
+
+// formatSimulatedStops returns a table of the allocations stopped by the
+// simulation.
+func formatSimulatedStops(allocs []*structs.Allocation, length int) string {
+	out := make([]string, len(allocs)+1)
+	out[0] = "Alloc ID|Name|Node ID|Node Name"
+	for i, alloc := range allocs {
+		out[i+1] = fmt.Sprintf("%s|%s|%s|%s",
+			limit(alloc.ID, length),
+			alloc.Name,
+			limit(alloc.NodeID, length),
+			alloc.NodeName,
+		)
+	}
+	return formatList(out)
+}
+
+// formatSimulatedPreemptions returns a table of the allocations of other jobs
+// preempted by the simulation.
+func formatSimulatedPreemptions(allocs []*structs.Allocation, length int) string {
+	out := make([]string, len(allocs)+1)
+	out[0] = "Alloc ID|Namespace|Job ID|Task Group|Node ID|Priority"
+	for i, alloc := range allocs {
+		var priority int
+		if alloc.Job != nil {
+			priority = alloc.Job.Priority
+		}
+		out[i+1] = fmt.Sprintf("%s|%s|%s|%s|%s|%d",
+			limit(alloc.ID, length),
+			alloc.Namespace,
+			alloc.JobID,
+			alloc.TaskGroup,
+			limit(alloc.NodeID, length),
+			priority,
+		)
+	}
+	return formatList(out)
+}
+
+// apiAllocMetric converts the metrics computed by the scheduler into their
+// API representation, as the HTTP API would encode them.
+func apiAllocMetric(metric *structs.AllocMetric) (*api.AllocationMetric, error) {
+	buf, err := json.Marshal(metric)
+	if err != nil {
+		return nil, err
+	}
+
+	var out api.AllocationMetric
+	if err := json.Unmarshal(buf, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,129 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperatorSchedulerSimulateCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &OperatorSchedulerSimulateCommand{}
+}
+
+func TestOperatorSchedulerSimulateCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+
+	ui := cli.NewMockUi()
+	cmd := &OperatorSchedulerSimulateCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	code := cmd.Run([]string{"-snapshot=backup.snap"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "This command takes one argument")
+	ui.ErrorWriter.Reset()
+
+	// Fails without a snapshot
+	code = cmd.Run([]string{"testdata/example-basic.nomad"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "The -snapshot flag is required")
+	ui.ErrorWriter.Reset()
+
+	// Fails on a missing snapshot
+	code = cmd.Run([]string{"-snapshot=/unicorns/leprechauns", "testdata/example-basic.nomad"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "Error opening snapshot file")
+}
+
+func TestOperatorSchedulerSimulateCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	snapPath := generateSnapshotFile(t, nil)
+
+	ui := cli.NewMockUi()
+	cmd := &OperatorSchedulerSimulateCommand{Meta: Meta{Ui: ui}}
+
+	// The snapshot has no client nodes so the job cannot be placed
+	code := cmd.Run([]string{"-snapshot=" + snapPath, "testdata/example-basic.nomad"})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+	out := ui.OutputWriter.String()
+	require.Contains(t, out, `Simulated job "job1"`)
+	require.Contains(t, out, "No allocations placed")
+	require.Contains(t, out, `Task Group "group1" (failed to place 1 allocation)`)
+	require.Contains(t, out, "No nodes were eligible for evaluation")
+}
+
+func TestOperatorSchedulerSimulateCommand_Run_Quota(t *testing.T) {
+	ci.Parallel(t)
+
+	// The snapshot is taken from the "global" region, with a quota limiting
+	// the region to less than the job asks for
+	spec := mock.QuotaSpec()
+	spec.Limits[0].RegionLimit = &structs.Resources{CPU: 500}
+	spec.SetHash()
+	ns := mock.Namespace()
+	ns.Quota = spec.Name
+
+	snapPath := generateSnapshotFile(t, func(srv *agent.TestAgent, _ *api.Client, _ string) {
+		state := srv.Agent.Server().State()
+		require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1000, mock.Node()))
+		require.NoError(t, state.UpsertQuotaSpecs(structs.MsgTypeTestSetup, 1001, []*structs.QuotaSpec{spec}))
+		require.NoError(t, state.UpsertNamespaces(1002, []*structs.Namespace{ns}))
+	})
+
+	writeJob := func(region string) string {
+		path := filepath.Join(t.TempDir(), "job.nomad")
+		job := fmt.Sprintf(`
+job "job1" {
+  region      = %q
+  namespace   = %q
+  datacenters = ["dc1"]
+  group "group1" {
+    task "task1" {
+      driver = "exec"
+      config {
+        command = "/bin/sleep"
+      }
+      resources {
+        cpu    = 1000
+        memory = 256
+      }
+    }
+  }
+}
+`, region, ns.Name)
+		require.NoError(t, os.WriteFile(path, []byte(job), 0644))
+		return path
+	}
+
+	// The quota of the job's region is enforced
+	ui := cli.NewMockUi()
+	cmd := &OperatorSchedulerSimulateCommand{Meta: Meta{Ui: ui}}
+	code := cmd.Run([]string{"-snapshot=" + snapPath, writeJob("global")})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+	out := ui.OutputWriter.String()
+	require.Contains(t, out, "No allocations placed")
+	require.Contains(t, out, `Task Group "group1" (failed to place 1 allocation)`)
+	require.Contains(t, out, "Quota limit hit")
+
+	// The quota does not limit other regions
+	ui = cli.NewMockUi()
+	cmd = &OperatorSchedulerSimulateCommand{Meta: Meta{Ui: ui}}
+	code = cmd.Run([]string{"-snapshot=" + snapPath, writeJob("east")})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+	out = ui.OutputWriter.String()
+	require.NotContains(t, out, "Quota limit hit")
+	require.Contains(t, out, "All tasks successfully allocated")
+}
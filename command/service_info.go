@@ -26,7 +26,8 @@ func (s *ServiceInfoCommand) Help() string {
 	helpText := `
 Usage: nomad service info [options] <service_name>
 
-  Info is used to read the services registered to a single service name.
+  Info is used to read the services registered to a single service name,
+  including the registrations with critical checks.
 
   When ACLs are enabled, this command requires a token with the 'read-job'
   capability for the service namespace.
@@ -111,11 +112,13 @@ func (s *ServiceInfoCommand) Run(args []string) int {
 		return 1
 	}
 
-	// Set up the options to capture any filter passed.
+	// Set up the options to capture any filter passed. Operators see every
+	// registration, including the ones with critical checks.
 	opts := api.QueryOptions{
 		Filter:    filter,
 		PerPage:   int32(perPage),
 		NextToken: pageToken,
+		Params:    map[string]string{"healthy": "false"},
 	}
 
 	serviceInfo, qm, err := client.Services().Get(args[0], &opts)
@@ -219,6 +222,14 @@ func (s *ServiceInfoCommand) formatVerboseOutput(jobIDs []string, jobServices ma
 				fmt.Sprintf("Tags|[%s]\n", strings.Join(service.Tags, ",")),
 			}
 			s.Ui.Output(formatKV(out))
+
+			if len(service.Checks) > 0 {
+				checks := []string{"Check Name|Type|Status"}
+				for _, check := range service.Checks {
+					checks = append(checks, fmt.Sprintf("%s|%s|%s", check.Name, check.Type, check.Status))
+				}
+				s.Ui.Output(formatList(checks))
+			}
 			s.Ui.Output("")
 		}
 	}
@@ -0,0 +1,81 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+)
+
+type VarCommand struct {
+	Meta
+}
+
+func (f *VarCommand) Help() string {
+	helpText := `
+Usage: nomad var <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with variables. Variables
+  allow operators to provide credentials and otherwise sensitive material to
+  Nomad jobs at runtime via the template block. Variables are encrypted at rest
+  using a keyring held by the Nomad servers.
+
+  Create or update the variable stored at the path "secret/creds":
+
+      $ nomad var put secret/creds username=admin password=hunter2
+
+  Read the variable stored at the path "secret/creds":
+
+      $ nomad var get secret/creds
+
+  List variables whose path begins with "secret":
+
+      $ nomad var list secret
+
+  Delete the variable stored at the path "secret/creds":
+
+      $ nomad var purge secret/creds
+
+  Please see the individual subcommand help for detailed usage information.
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+func (f *VarCommand) Synopsis() string {
+	return "Interact with variables"
+}
+
+func (f *VarCommand) Name() string { return "var" }
+
+func (f *VarCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+// formatVariable returns a human readable representation of the variable,
+// including its items.
+func formatVariable(v *api.Variable) string {
+	out := []string{
+		fmt.Sprintf("Namespace|%s", v.Namespace),
+		fmt.Sprintf("Path|%s", v.Path),
+		fmt.Sprintf("Create Time|%s", formatUnixNanoTime(v.CreateTime)),
+		fmt.Sprintf("Modify Time|%s", formatUnixNanoTime(v.ModifyTime)),
+		fmt.Sprintf("Create Index|%d", v.CreateIndex),
+		fmt.Sprintf("Modify Index|%d", v.ModifyIndex),
+	}
+
+	keys := make([]string, 0, len(v.Items))
+	for k := range v.Items {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	items := make([]string, 0, len(keys))
+	for _, k := range keys {
+		items = append(items, fmt.Sprintf("%s|%s", k, v.Items[k]))
+	}
+
+	return fmt.Sprintf("%s\n\n[bold]Items[reset]\n%s", formatKV(out), formatKV(items))
+}
@@ -0,0 +1,135 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type VarListCommand struct {
+	Meta
+}
+
+func (c *VarListCommand) Help() string {
+	helpText := `
+Usage: nomad var list [options] [<prefix>]
+
+  List is used to list the variables stored within a namespace. If a prefix is
+  given, only variables whose path begins with the prefix are listed. The items
+  of the variables are never displayed.
+
+  If ACLs are enabled, this command requires a token with the 'list' variables
+  capability. Any variables the token does not have access to are filtered
+  from the results.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+List Options:
+
+  -json
+    Output the variables in JSON format.
+
+  -t
+    Format and display the variables using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VarListCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json": complete.PredictNothing,
+			"-t":    complete.PredictAnything,
+		})
+}
+
+func (c *VarListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *VarListCommand) Synopsis() string {
+	return "List variables"
+}
+
+func (c *VarListCommand) Name() string { return "var list" }
+
+func (c *VarListCommand) Run(args []string) int {
+	var (
+		json bool
+		tmpl string
+	)
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no more than one argument
+	args = flags.Args()
+	if len(args) > 1 {
+		c.Ui.Error("This command takes at most one argument: [<prefix>]")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	var prefix string
+	if len(args) == 1 {
+		prefix = args[0]
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	vars, _, err := client.Variables().PrefixList(prefix, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving variables: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, vars)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		c.Ui.Output(out)
+		return 0
+	}
+
+	if len(vars) == 0 {
+		c.Ui.Output("No variables found")
+		return 0
+	}
+
+	c.Ui.Output(formatVariableList(vars, c.Meta.namespace == api.AllNamespacesNamespace))
+	return 0
+}
+
+func formatVariableList(vars []*api.VariableMetadata, showNamespace bool) string {
+	rows := make([]string, len(vars)+1)
+	if showNamespace {
+		rows[0] = "Namespace|Path|Last Updated"
+	} else {
+		rows[0] = "Path|Last Updated"
+	}
+	for i, v := range vars {
+		row := fmt.Sprintf("%s|%s", v.Path, formatUnixNanoTime(v.ModifyTime))
+		if showNamespace {
+			row = v.Namespace + "|" + row
+		}
+		rows[i+1] = row
+	}
+	return formatList(rows)
+}
@@ -0,0 +1,138 @@
+package command
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type VarPutCommand struct {
+	Meta
+}
+
+func (c *VarPutCommand) Help() string {
+	helpText := `
+Usage: nomad var put [options] <path> <key>=<value> [<key>=<value>...]
+
+  Put is used to create or update the variable stored at the given path. The
+  items of the variable are given as key=value pairs and replace any items the
+  variable already holds. If a value begins with "@", the remainder of the
+  value is treated as the path to a file whose contents are used as the value.
+
+  If ACLs are enabled, this command requires a token with the 'write' variables
+  capability for the path of the variable.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Put Options:
+
+  -json
+    Output the written variable in JSON format.
+
+  -t
+    Format and display the written variable using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VarPutCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json": complete.PredictNothing,
+			"-t":    complete.PredictAnything,
+		})
+}
+
+func (c *VarPutCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *VarPutCommand) Synopsis() string {
+	return "Create or update a variable"
+}
+
+func (c *VarPutCommand) Name() string { return "var put" }
+
+func (c *VarPutCommand) Run(args []string) int {
+	var (
+		json bool
+		tmpl string
+	)
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got a path and at least one item
+	args = flags.Args()
+	if len(args) < 2 {
+		c.Ui.Error("This command takes at least two arguments: <path> and <key>=<value>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	variable := api.NewVariable(args[0])
+	for _, arg := range args[1:] {
+		key, value, err := parseVariableItem(arg)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		variable.Items[key] = value
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	out, _, err := client.Variables().Put(variable, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error writing variable: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		formatted, err := Format(json, tmpl, out)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		c.Ui.Output(formatted)
+		return 0
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully wrote variable %q", out.Path))
+	return 0
+}
+
+// parseVariableItem parses a single key=value argument. A value beginning
+// with "@" is read from the file it names.
+func parseVariableItem(arg string) (string, string, error) {
+	parts := strings.SplitN(arg, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return "", "", fmt.Errorf("Invalid item %q: items must be in the form <key>=<value>", arg)
+	}
+
+	key, value := parts[0], parts[1]
+	if strings.HasPrefix(value, "@") {
+		contents, err := ioutil.ReadFile(value[1:])
+		if err != nil {
+			return "", "", fmt.Errorf("Error reading value of item %q: %v", key, err)
+		}
+		value = string(contents)
+	}
+	return key, value, nil
+}
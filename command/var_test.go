@@ -0,0 +1,117 @@
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVarCommands_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &VarCommand{}
+	var _ cli.Command = &VarGetCommand{}
+	var _ cli.Command = &VarListCommand{}
+	var _ cli.Command = &VarPurgeCommand{}
+	var _ cli.Command = &VarPutCommand{}
+}
+
+func TestVarCommands_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, client, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+	testutil.WaitForLeader(t, srv.Agent.RPC)
+
+	ui := cli.NewMockUi()
+	meta := Meta{Ui: ui}
+
+	// Writing a variable requires a path and at least one item.
+	put := &VarPutCommand{Meta: meta}
+	require.Equal(t, 1, put.Run([]string{"-address=" + url, "secret/creds"}))
+	require.Contains(t, ui.ErrorWriter.String(), "at least two arguments")
+	ui.ErrorWriter.Reset()
+
+	// The keyring is initialized asynchronously by the leader, so retry the
+	// write until it succeeds.
+	testutil.WaitForResult(func() (bool, error) {
+		_, _, err := client.Variables().Put(&api.Variable{
+			Path:  "secret/other",
+			Items: api.VariableItems{"a": "b"},
+		}, nil)
+		return err == nil, err
+	}, func(err error) {
+		require.NoError(t, err)
+	})
+
+	code := put.Run([]string{"-address=" + url, "secret/creds", "username=admin", "password=hunter2"})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), `Successfully wrote variable "secret/creds"`)
+	ui.OutputWriter.Reset()
+
+	// Read the variable and a single item.
+	get := &VarGetCommand{Meta: meta}
+	require.Equal(t, 0, get.Run([]string{"-address=" + url, "secret/creds"}))
+	out := ui.OutputWriter.String()
+	require.Contains(t, out, "secret/creds")
+	require.Contains(t, out, "hunter2")
+	ui.OutputWriter.Reset()
+
+	require.Equal(t, 0, get.Run([]string{"-address=" + url, "-item=username", "secret/creds"}))
+	require.Equal(t, "admin", strings.TrimSpace(ui.OutputWriter.String()))
+	ui.OutputWriter.Reset()
+
+	require.Equal(t, 1, get.Run([]string{"-address=" + url, "-item=missing", "secret/creds"}))
+	ui.ErrorWriter.Reset()
+
+	// List the variables by prefix.
+	list := &VarListCommand{Meta: meta}
+	require.Equal(t, 0, list.Run([]string{"-address=" + url, "secret/c"}))
+	out = ui.OutputWriter.String()
+	require.Contains(t, out, "secret/creds")
+	require.NotContains(t, out, "secret/other")
+	require.NotContains(t, out, "hunter2")
+	ui.OutputWriter.Reset()
+
+	// Purge the variable and ensure it can no longer be read.
+	purge := &VarPurgeCommand{Meta: meta}
+	require.Equal(t, 0, purge.Run([]string{"-address=" + url, "secret/creds"}))
+	require.Contains(t, ui.OutputWriter.String(), `Successfully purged variable "secret/creds"`)
+	ui.OutputWriter.Reset()
+
+	require.Equal(t, 1, get.Run([]string{"-address=" + url, "secret/creds"}))
+	require.Contains(t, ui.ErrorWriter.String(), "Error reading variable")
+}
+
+func TestVarPutCommand_parseVariableItem(t *testing.T) {
+	ci.Parallel(t)
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "value.txt")
+	require.NoError(t, os.WriteFile(file, []byte("from-file"), 0600))
+
+	key, value, err := parseVariableItem("foo=bar=baz")
+	require.NoError(t, err)
+	require.Equal(t, "foo", key)
+	require.Equal(t, "bar=baz", value)
+
+	key, value, err = parseVariableItem("foo=@" + file)
+	require.NoError(t, err)
+	require.Equal(t, "foo", key)
+	require.Equal(t, "from-file", value)
+
+	_, _, err = parseVariableItem("foo")
+	require.Error(t, err)
+
+	_, _, err = parseVariableItem("=bar")
+	require.Error(t, err)
+
+	_, _, err = parseVariableItem("foo=@" + filepath.Join(dir, "missing"))
+	require.Error(t, err)
+}
@@ -85,6 +85,8 @@ const (
 )
 
 type Driver struct {
+	drivers.DriverCheckpointNotSupported
+
 	// eventer is used to handle multiplexing of TaskEvents calls such that an
 	// event can be broadcast to all callers
 	eventer *eventer.Eventer
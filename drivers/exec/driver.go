@@ -2,10 +2,14 @@ package exec
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
+	osexec "os/exec"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"sync"
 	"time"
 
@@ -16,7 +20,9 @@ import (
 	"github.com/hashicorp/nomad/drivers/shared/eventer"
 	"github.com/hashicorp/nomad/drivers/shared/executor"
 	"github.com/hashicorp/nomad/drivers/shared/resolvconf"
+	"github.com/hashicorp/nomad/drivers/shared/seccomp"
 	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/pluginutils/hclutils"
 	"github.com/hashicorp/nomad/helper/pluginutils/loader"
 	"github.com/hashicorp/nomad/plugins/base"
 	"github.com/hashicorp/nomad/plugins/drivers"
@@ -78,6 +84,22 @@ var (
 			hclspec.NewAttr("allow_caps", "list(string)", false),
 			hclspec.NewLiteral(capabilities.HCLSpecLiteral),
 		),
+		"default_seccomp_profile": hclspec.NewDefault(
+			hclspec.NewAttr("default_seccomp_profile", "string", false),
+			hclspec.NewLiteral(`"unconfined"`),
+		),
+		"allow_seccomp_profiles": hclspec.NewDefault(
+			hclspec.NewAttr("allow_seccomp_profiles", "list(string)", false),
+			hclspec.NewLiteral(seccomp.HCLSpecLiteral),
+		),
+		"checkpoint": hclspec.NewDefault(
+			hclspec.NewAttr("checkpoint", "bool", false),
+			hclspec.NewLiteral("false"),
+		),
+		"criu_path": hclspec.NewDefault(
+			hclspec.NewAttr("criu_path", "string", false),
+			hclspec.NewLiteral(`"criu"`),
+		),
 	})
 
 	// taskConfigSpec is the hcl specification for the driver config section of
@@ -89,6 +111,10 @@ var (
 		"ipc_mode": hclspec.NewAttr("ipc_mode", "string", false),
 		"cap_add":  hclspec.NewAttr("cap_add", "list(string)", false),
 		"cap_drop": hclspec.NewAttr("cap_drop", "list(string)", false),
+
+		"seccomp_profile": hclspec.NewAttr("seccomp_profile", "string", false),
+		"rlimits":         hclspec.NewAttr("rlimits", "list(map(string))", false),
+		"readonly_rootfs": hclspec.NewAttr("readonly_rootfs", "bool", false),
 	})
 
 	// driverCapabilities represents the RPC response for what features are
@@ -151,6 +177,24 @@ type Config struct {
 	// AllowCaps configures which Linux Capabilities are enabled for tasks
 	// running on this node.
 	AllowCaps []string `codec:"allow_caps"`
+
+	// DefaultSeccompProfile is the seccomp profile applied to tasks which do
+	// not set one. It is either "default", "unconfined" or the path to a
+	// custom profile.
+	DefaultSeccompProfile string `codec:"default_seccomp_profile"`
+
+	// AllowSeccompProfiles configures which seccomp profiles tasks running on
+	// this node may set.
+	AllowSeccompProfiles []string `codec:"allow_seccomp_profiles"`
+
+	// Checkpoint enables checkpointing tasks with CRIU when their allocation
+	// is migrated off a draining node, and restoring them from the migrated
+	// checkpoint instead of restarting them.
+	Checkpoint bool `codec:"checkpoint"`
+
+	// CriuPath is the path of the criu binary used to checkpoint and restore
+	// tasks.
+	CriuPath string `codec:"criu_path"`
 }
 
 func (c *Config) validate() error {
@@ -171,6 +215,20 @@ func (c *Config) validate() error {
 		return fmt.Errorf("allow_caps configured with capabilities not supported by system: %s", badCaps)
 	}
 
+	if c.DefaultSeccompProfile != "" {
+		if err := seccomp.ValidateProfile(c.DefaultSeccompProfile); err != nil {
+			return fmt.Errorf("default_seccomp_profile: %v", err)
+		}
+		if err := seccomp.ValidateSupported(c.DefaultSeccompProfile); err != nil {
+			return fmt.Errorf("default_seccomp_profile: %v", err)
+		}
+	}
+	for _, profile := range c.AllowSeccompProfiles {
+		if err := seccomp.ValidateProfile(profile); err != nil {
+			return fmt.Errorf("allow_seccomp_profiles: %v", err)
+		}
+	}
+
 	return nil
 }
 
@@ -195,6 +253,17 @@ type TaskConfig struct {
 
 	// CapDrop is a set of linux capabilities to disable.
 	CapDrop []string `codec:"cap_drop"`
+
+	// SeccompProfile is the seccomp profile applied to the task, which must
+	// be allowed by the plugin configuration.
+	SeccompProfile string `codec:"seccomp_profile"`
+
+	// Rlimits are the resource limits of the task, mapping the resource to
+	// either "soft:hard" or a single limit.
+	Rlimits hclutils.MapStrStr `codec:"rlimits"`
+
+	// ReadonlyRootfs mounts the root filesystem of the task as read-only.
+	ReadonlyRootfs bool `codec:"readonly_rootfs"`
 }
 
 func (tc *TaskConfig) validate() error {
@@ -220,6 +289,19 @@ func (tc *TaskConfig) validate() error {
 		return fmt.Errorf("cap_drop configured with capabilities not supported by system: %s", badDrops)
 	}
 
+	if tc.SeccompProfile != "" {
+		if err := seccomp.ValidateProfile(tc.SeccompProfile); err != nil {
+			return err
+		}
+		if err := seccomp.ValidateSupported(tc.SeccompProfile); err != nil {
+			return err
+		}
+	}
+
+	if _, err := executor.ParseRlimits(tc.Rlimits); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -300,7 +382,19 @@ func (d *Driver) TaskConfigSchema() (*hclspec.Spec, error) {
 // Capabilities is returned by the Capabilities RPC and indicates what
 // optional features this driver supports
 func (d *Driver) Capabilities() (*drivers.Capabilities, error) {
-	return driverCapabilities, nil
+	if !d.checkpoint() {
+		return driverCapabilities, nil
+	}
+
+	caps := *driverCapabilities
+	caps.Checkpoint = true
+	return &caps, nil
+}
+
+// checkpoint returns whether tasks may be checkpointed and restored. CRIU
+// requires root, so checkpoints are not supported by rootless clients.
+func (d *Driver) checkpoint() bool {
+	return d.config.Checkpoint && !d.rootless()
 }
 
 func (d *Driver) Fingerprint(ctx context.Context) (<-chan *drivers.Fingerprint, error) {
@@ -341,10 +435,25 @@ func (d *Driver) buildFingerprint() *drivers.Fingerprint {
 	}
 
 	if !utils.IsUnixRoot() {
-		fp.Health = drivers.HealthStateUndetected
-		fp.HealthDescription = drivers.DriverRequiresRootMessage
-		d.setFingerprintFailure()
-		return fp
+		if !d.rootless() {
+			fp.Health = drivers.HealthStateUndetected
+			fp.HealthDescription = drivers.DriverRequiresRootMessage
+			d.setFingerprintFailure()
+			return fp
+		}
+
+		// rootless clients isolate tasks in user namespaces and the cgroups
+		// delegated to them
+		if err := rootlessSupported(); err != nil {
+			fp.Health = drivers.HealthStateUndetected
+			fp.HealthDescription = fmt.Sprintf("Rootless isolation unavailable: %v", err)
+			if d.fingerprintSuccessful() {
+				d.logger.Warn(fp.HealthDescription)
+			}
+			d.setFingerprintFailure()
+			return fp
+		}
+		fp.Attributes["driver.exec.rootless"] = pstructs.NewBoolAttribute(true)
 	}
 
 	mount, err := cgutil.FindCgroupMountpointDir()
@@ -365,11 +474,47 @@ func (d *Driver) buildFingerprint() *drivers.Fingerprint {
 		return fp
 	}
 
+	if d.checkpoint() {
+		// tasks can still run without criu, but can't be checkpointed
+		if _, err := osexec.LookPath(d.config.CriuPath); err == nil {
+			fp.Attributes["driver.exec.checkpoint"] = pstructs.NewBoolAttribute(true)
+		} else if d.fingerprintSuccessful() {
+			d.logger.Warn("checkpoint enabled but criu not found", "criu_path", d.config.CriuPath, "error", err)
+		}
+	}
+
+	if seccomp.Supported() {
+		fp.Attributes["driver.exec.seccomp"] = pstructs.NewBoolAttribute(true)
+	}
+
 	fp.Attributes["driver.exec"] = pstructs.NewBoolAttribute(true)
 	d.setFingerprintSuccess()
 	return fp
 }
 
+// rootless returns whether the client runs as an unprivileged user.
+func (d *Driver) rootless() bool {
+	return d.nomadConfig != nil && d.nomadConfig.Rootless
+}
+
+// rootlessSupported returns an error if tasks of a rootless client cannot be
+// isolated, which requires unprivileged user namespaces and a cgroups v2
+// subtree delegated to the user running the client.
+func rootlessSupported() error {
+	if _, err := cgutil.DelegatedCgroup(); err != nil {
+		return err
+	}
+
+	max, err := ioutil.ReadFile("/proc/sys/user/max_user_namespaces")
+	if err != nil {
+		return fmt.Errorf("failed to detect user namespaces: %v", err)
+	}
+	if strings.TrimSpace(string(max)) == "0" {
+		return errors.New("user namespaces are disabled")
+	}
+	return nil
+}
+
 func (d *Driver) RecoverTask(handle *drivers.TaskHandle) error {
 	if handle == nil {
 		return fmt.Errorf("handle cannot be nil")
@@ -423,6 +568,34 @@ func (d *Driver) RecoverTask(handle *drivers.TaskHandle) error {
 }
 
 func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drivers.DriverNetwork, error) {
+	return d.startTask(cfg, func(exec executor.Executor, execCmd *executor.ExecCommand) (*executor.ProcessState, error) {
+		ps, err := exec.Launch(execCmd)
+		if err != nil {
+			return nil, fmt.Errorf("failed to launch command with executor: %v", err)
+		}
+		return ps, nil
+	})
+}
+
+// RestoreTask starts a task from the checkpoint written into dir by
+// CheckpointTask, possibly on another client.
+func (d *Driver) RestoreTask(cfg *drivers.TaskConfig, dir string) (*drivers.TaskHandle, *drivers.DriverNetwork, error) {
+	if !d.checkpoint() {
+		return nil, nil, errors.New("checkpoint is not enabled for the exec driver")
+	}
+
+	return d.startTask(cfg, func(exec executor.Executor, execCmd *executor.ExecCommand) (*executor.ProcessState, error) {
+		ps, err := exec.Restore(execCmd, dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore command with executor: %v", err)
+		}
+		return ps, nil
+	})
+}
+
+// startTask starts the task with start, which either launches the command of
+// the task with the executor or restores it from a checkpoint.
+func (d *Driver) startTask(cfg *drivers.TaskConfig, start func(executor.Executor, *executor.ExecCommand) (*executor.ProcessState, error)) (*drivers.TaskHandle, *drivers.DriverNetwork, error) {
 	if _, ok := d.tasks.Get(cfg.ID); ok {
 		return nil, nil, fmt.Errorf("task with ID %q already started", cfg.ID)
 	}
@@ -436,6 +609,32 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 		return nil, nil, fmt.Errorf("failed driver config validation: %v", err)
 	}
 
+	user := cfg.User
+	if d.rootless() {
+		// tasks of rootless clients run as root in their user namespace,
+		// which is mapped to the user running the client
+		if user != "" && user != "root" {
+			return nil, nil, fmt.Errorf("rootless clients cannot run tasks as user %q", user)
+		}
+	} else if user == "" {
+		user = "nobody"
+	}
+
+	profile, err := seccomp.Select(d.config.DefaultSeccompProfile, d.config.AllowSeccompProfiles, driverConfig.SeccompProfile)
+	if err != nil {
+		return nil, nil, err
+	}
+	seccompProfile, err := seccomp.Load(profile)
+	if err != nil {
+		return nil, nil, err
+	}
+	d.logger.Debug("task seccomp profile", "seccomp_profile", profile)
+
+	rlimits, err := executor.ParseRlimits(driverConfig.Rlimits)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	d.logger.Info("starting task", "driver_cfg", hclog.Fmt("%+v", driverConfig))
 	handle := drivers.NewTaskHandle(taskHandleVersion)
 	handle.Config = cfg
@@ -454,11 +653,6 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 		return nil, nil, fmt.Errorf("failed to create executor: %v", err)
 	}
 
-	user := cfg.User
-	if user == "" {
-		user = "nobody"
-	}
-
 	if cfg.DNS != nil {
 		dnsMount, err := resolvconf.GenerateDNSMount(cfg.TaskDir().Dir, cfg.DNS)
 		if err != nil {
@@ -492,12 +686,16 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 		ModePID:          executor.IsolationMode(d.config.DefaultModePID, driverConfig.ModePID),
 		ModeIPC:          executor.IsolationMode(d.config.DefaultModeIPC, driverConfig.ModeIPC),
 		Capabilities:     caps,
+		SeccompProfile:   seccompProfile,
+		Rlimits:          rlimits,
+		ReadonlyRootfs:   driverConfig.ReadonlyRootfs,
+		CriuPath:         d.config.CriuPath,
 	}
 
-	ps, err := exec.Launch(execCmd)
+	ps, err := start(exec, execCmd)
 	if err != nil {
 		pluginClient.Kill()
-		return nil, nil, fmt.Errorf("failed to launch command with executor: %v", err)
+		return nil, nil, err
 	}
 
 	h := &taskHandle{
@@ -529,6 +727,24 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 	return handle, nil, nil
 }
 
+// CheckpointTask dumps the state of the task into dir with CRIU, which stops
+// the task. The checkpoint can be restored with RestoreTask.
+func (d *Driver) CheckpointTask(taskID string, dir string) error {
+	if !d.checkpoint() {
+		return errors.New("checkpoint is not enabled for the exec driver")
+	}
+
+	handle, ok := d.tasks.Get(taskID)
+	if !ok {
+		return drivers.ErrTaskNotFound
+	}
+
+	if err := handle.exec.Checkpoint(dir); err != nil {
+		return fmt.Errorf("executor Checkpoint failed: %v", err)
+	}
+	return nil
+}
+
 func (d *Driver) WaitTask(ctx context.Context, taskID string) (<-chan *drivers.ExitResult, error) {
 	handle, ok := d.tasks.Get(taskID)
 	if !ok {
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strconv"
@@ -20,6 +21,7 @@ import (
 	"github.com/hashicorp/nomad/client/lib/cgutil"
 	ctestutils "github.com/hashicorp/nomad/client/testutil"
 	"github.com/hashicorp/nomad/drivers/shared/executor"
+	"github.com/hashicorp/nomad/drivers/shared/seccomp"
 	"github.com/hashicorp/nomad/helper/pluginutils/hclutils"
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/helper/testtask"
@@ -115,6 +117,179 @@ func TestExecDriver_Fingerprint(t *testing.T) {
 	}
 }
 
+func TestExecDriver_Fingerprint_Rootless(t *testing.T) {
+	ci.Parallel(t)
+	if runtime.GOOS != "linux" || syscall.Geteuid() == 0 {
+		t.Skip("Test requires a non-root user on Linux")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d := NewExecDriver(ctx, testlog.HCLogger(t))
+	harness := dtestutil.NewDriverHarness(t, d)
+
+	var data []byte
+	require.NoError(t, basePlug.MsgPackEncode(&data, &Config{
+		DefaultModePID: executor.IsolationModePrivate,
+		DefaultModeIPC: executor.IsolationModePrivate,
+	}))
+	require.NoError(t, harness.SetConfig(&basePlug.Config{
+		PluginConfig: data,
+		AgentConfig: &basePlug.AgentConfig{
+			Driver: &basePlug.ClientDriverConfig{Rootless: true},
+		},
+	}))
+
+	fingerCh, err := harness.Fingerprint(context.Background())
+	require.NoError(t, err)
+	select {
+	case finger := <-fingerCh:
+		// The driver is only available if the cgroups of the user running the
+		// test are delegated
+		if err := rootlessSupported(); err != nil {
+			require.Equal(t, drivers.HealthStateUndetected, finger.Health)
+			require.Contains(t, finger.HealthDescription, err.Error())
+		} else {
+			require.Equal(t, drivers.HealthStateHealthy, finger.Health)
+			rootless, ok := finger.Attributes["driver.exec.rootless"].GetBool()
+			require.True(t, ok)
+			require.True(t, rootless)
+		}
+	case <-time.After(time.Duration(testutil.TestMultiplier()*5) * time.Second):
+		require.Fail(t, "timeout receiving fingerprint")
+	}
+}
+
+func TestExecDriver_Checkpoint_Disabled(t *testing.T) {
+	ci.Parallel(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d := NewExecDriver(ctx, testlog.HCLogger(t))
+	harness := dtestutil.NewDriverHarness(t, d)
+
+	caps, err := harness.Capabilities()
+	require.NoError(t, err)
+	require.False(t, caps.Checkpoint)
+
+	err = harness.CheckpointTask(uuid.Generate(), t.TempDir())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "checkpoint is not enabled")
+
+	_, _, err = harness.RestoreTask(&drivers.TaskConfig{ID: uuid.Generate()}, t.TempDir())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "checkpoint is not enabled")
+
+	// Rootless clients cannot checkpoint tasks even if enabled
+	var data []byte
+	require.NoError(t, basePlug.MsgPackEncode(&data, &Config{
+		DefaultModePID: executor.IsolationModePrivate,
+		DefaultModeIPC: executor.IsolationModePrivate,
+		Checkpoint:     true,
+		CriuPath:       "criu",
+	}))
+	require.NoError(t, harness.SetConfig(&basePlug.Config{
+		PluginConfig: data,
+		AgentConfig: &basePlug.AgentConfig{
+			Driver: &basePlug.ClientDriverConfig{Rootless: true},
+		},
+	}))
+	caps, err = harness.Capabilities()
+	require.NoError(t, err)
+	require.False(t, caps.Checkpoint)
+}
+
+func TestExecDriver_CheckpointRestore(t *testing.T) {
+	ci.Parallel(t)
+	ctestutils.ExecCompatible(t)
+	if _, err := exec.LookPath("criu"); err != nil {
+		t.Skip("Test requires criu")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d := NewExecDriver(ctx, testlog.HCLogger(t))
+	harness := dtestutil.NewDriverHarness(t, d)
+
+	var data []byte
+	require.NoError(t, basePlug.MsgPackEncode(&data, &Config{
+		DefaultModePID: executor.IsolationModePrivate,
+		DefaultModeIPC: executor.IsolationModePrivate,
+		Checkpoint:     true,
+		CriuPath:       "criu",
+	}))
+	require.NoError(t, harness.SetConfig(&basePlug.Config{PluginConfig: data}))
+
+	caps, err := harness.Capabilities()
+	require.NoError(t, err)
+	require.True(t, caps.Checkpoint)
+
+	allocID := uuid.Generate()
+	task := &drivers.TaskConfig{
+		AllocID:   allocID,
+		ID:        uuid.Generate(),
+		Name:      "test",
+		Resources: testResources(allocID, "test"),
+	}
+
+	// The counter is kept in memory and written to the alloc dir
+	tc := &TaskConfig{
+		Command: "/bin/bash",
+		Args:    []string{"-c", "i=0; while true; do i=$((i+1)); echo $i > /alloc/counter; sleep 0.1; done"},
+	}
+	require.NoError(t, task.EncodeConcreteDriverConfig(&tc))
+
+	cleanup := harness.MkAllocDir(task, false)
+	defer cleanup()
+
+	counter := func() int {
+		b, err := ioutil.ReadFile(filepath.Join(task.TaskDir().SharedAllocDir, "counter"))
+		if err != nil {
+			return 0
+		}
+		i, _ := strconv.Atoi(strings.TrimSpace(string(b)))
+		return i
+	}
+
+	_, _, err = harness.StartTask(task)
+	require.NoError(t, err)
+
+	ch, err := harness.WaitTask(context.Background(), task.ID)
+	require.NoError(t, err)
+
+	testutil.WaitForResult(func() (bool, error) {
+		return counter() >= 10, fmt.Errorf("counter is %d", counter())
+	}, func(err error) {
+		require.NoError(t, err)
+	})
+
+	// The task stops once checkpointed
+	dir := filepath.Join(t.TempDir(), "checkpoint")
+	require.NoError(t, harness.CheckpointTask(task.ID, dir))
+	select {
+	case <-ch:
+	case <-time.After(10 * time.Second):
+		require.Fail(t, "timeout waiting for task to stop")
+	}
+	require.NoError(t, harness.DestroyTask(task.ID, true))
+	checkpointed := counter()
+
+	// The restored task resumes counting from the checkpointed value
+	task.ID = uuid.Generate()
+	_, _, err = harness.RestoreTask(task, dir)
+	require.NoError(t, err)
+	defer harness.DestroyTask(task.ID, true)
+
+	testutil.WaitForResult(func() (bool, error) {
+		return counter() > checkpointed, fmt.Errorf("counter is %d, checkpointed %d", counter(), checkpointed)
+	}, func(err error) {
+		require.NoError(t, err)
+	})
+}
+
 func TestExecDriver_StartWait(t *testing.T) {
 	ci.Parallel(t)
 	ctestutils.ExecCompatible(t)
@@ -719,11 +894,23 @@ func TestConfig_ParseAllHCL(t *testing.T) {
 config {
   command = "/bin/bash"
   args = ["-c", "echo hello"]
+  seccomp_profile = "default"
+  rlimits {
+    nofile = "1024:4096"
+    core   = "0"
+  }
+  readonly_rootfs = true
 }`
 
 	expected := &TaskConfig{
-		Command: "/bin/bash",
-		Args:    []string{"-c", "echo hello"},
+		Command:        "/bin/bash",
+		Args:           []string{"-c", "echo hello"},
+		SeccompProfile: "default",
+		Rlimits: hclutils.MapStrStr{
+			"nofile": "1024:4096",
+			"core":   "0",
+		},
+		ReadonlyRootfs: true,
 	}
 
 	var tc *TaskConfig
@@ -774,6 +961,54 @@ func TestExecDriver_NoPivotRoot(t *testing.T) {
 	require.NoError(t, harness.DestroyTask(task.ID, true))
 }
 
+func TestExecDriver_SeccompProfileNotAllowed(t *testing.T) {
+	ci.Parallel(t)
+	ctestutils.ExecCompatible(t)
+	if !seccomp.Supported() {
+		t.Skip("Nomad was built without seccomp support")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d := NewExecDriver(ctx, testlog.HCLogger(t))
+	harness := dtestutil.NewDriverHarness(t, d)
+
+	config := &Config{
+		DefaultModePID:        executor.IsolationModePrivate,
+		DefaultModeIPC:        executor.IsolationModePrivate,
+		DefaultSeccompProfile: "default",
+		AllowSeccompProfiles:  []string{"default"},
+	}
+
+	var data []byte
+	require.NoError(t, basePlug.MsgPackEncode(&data, config))
+	bconfig := &basePlug.Config{PluginConfig: data}
+	require.NoError(t, harness.SetConfig(bconfig))
+
+	allocID := uuid.Generate()
+	task := &drivers.TaskConfig{
+		AllocID:   allocID,
+		ID:        uuid.Generate(),
+		Name:      "sleep",
+		Resources: testResources(allocID, "sleep"),
+	}
+	cleanup := harness.MkAllocDir(task, false)
+	defer cleanup()
+
+	tc := &TaskConfig{
+		Command:        "/bin/sleep",
+		Args:           []string{"100"},
+		SeccompProfile: "unconfined",
+	}
+	require.NoError(t, task.EncodeConcreteDriverConfig(&tc))
+
+	handle, _, err := harness.StartTask(task)
+	require.Error(t, err)
+	require.Nil(t, handle)
+	require.Contains(t, err.Error(), `seccomp profile "unconfined" is not allowed`)
+}
+
 func TestDriver_Config_validate(t *testing.T) {
 	ci.Parallel(t)
 	t.Run("pid/ipc", func(t *testing.T) {
@@ -813,6 +1048,41 @@ func TestDriver_Config_validate(t *testing.T) {
 			}).validate())
 		}
 	})
+
+	t.Run("seccomp", func(t *testing.T) {
+		if !seccomp.Supported() {
+			t.Skip("Nomad was built without seccomp support")
+		}
+		for _, tc := range []struct {
+			def     string
+			allowed []string
+			exp     error
+		}{
+			{def: "", allowed: nil, exp: nil},
+			{def: "unconfined", allowed: []string{"default", "unconfined"}, exp: nil},
+			{def: "/etc/nomad/seccomp.json", allowed: []string{"default", "/etc/nomad/seccomp.json"}, exp: nil},
+			{def: "seccomp.json", allowed: nil, exp: errors.New(`default_seccomp_profile: seccomp profile must be "default", "unconfined" or an absolute path, got "seccomp.json"`)},
+			{def: "default", allowed: []string{"other"}, exp: errors.New(`allow_seccomp_profiles: seccomp profile must be "default", "unconfined" or an absolute path, got "other"`)},
+		} {
+			require.Equal(t, tc.exp, (&Config{
+				DefaultModePID:        "private",
+				DefaultModeIPC:        "private",
+				DefaultSeccompProfile: tc.def,
+				AllowSeccompProfiles:  tc.allowed,
+			}).validate())
+		}
+	})
+
+	t.Run("seccomp unsupported", func(t *testing.T) {
+		if seccomp.Supported() {
+			t.Skip("Nomad was built with seccomp support")
+		}
+		require.EqualError(t, (&Config{
+			DefaultModePID:        "private",
+			DefaultModeIPC:        "private",
+			DefaultSeccompProfile: "default",
+		}).validate(), `default_seccomp_profile: seccomp profile "default" configured but Nomad was built without seccomp support`)
+	})
 }
 
 func TestDriver_TaskConfig_validate(t *testing.T) {
@@ -872,4 +1142,48 @@ func TestDriver_TaskConfig_validate(t *testing.T) {
 			}).validate())
 		}
 	})
+
+	t.Run("seccomp_profile", func(t *testing.T) {
+		if !seccomp.Supported() {
+			t.Skip("Nomad was built without seccomp support")
+		}
+		for _, tc := range []struct {
+			profile string
+			exp     error
+		}{
+			{profile: "", exp: nil},
+			{profile: "default", exp: nil},
+			{profile: "/etc/nomad/seccomp.json", exp: nil},
+			{profile: "seccomp.json", exp: errors.New(`seccomp profile must be "default", "unconfined" or an absolute path, got "seccomp.json"`)},
+		} {
+			require.Equal(t, tc.exp, (&TaskConfig{
+				SeccompProfile: tc.profile,
+			}).validate())
+		}
+	})
+
+	t.Run("seccomp_profile unsupported", func(t *testing.T) {
+		if seccomp.Supported() {
+			t.Skip("Nomad was built with seccomp support")
+		}
+		require.NoError(t, (&TaskConfig{SeccompProfile: "unconfined"}).validate())
+		require.EqualError(t, (&TaskConfig{SeccompProfile: "default"}).validate(),
+			`seccomp profile "default" configured but Nomad was built without seccomp support`)
+	})
+
+	t.Run("rlimits", func(t *testing.T) {
+		for _, tc := range []struct {
+			rlimits hclutils.MapStrStr
+			exp     error
+		}{
+			{rlimits: nil, exp: nil},
+			{rlimits: hclutils.MapStrStr{"nofile": "1024:4096", "nproc": "64", "core": "0"}, exp: nil},
+			{rlimits: hclutils.MapStrStr{"stack": "1024"}, exp: errors.New(`unsupported rlimit "stack", must be one of "core", "nofile" or "nproc"`)},
+			{rlimits: hclutils.MapStrStr{"nofile": "4096:1024"}, exp: errors.New(`soft rlimit nofile must not exceed the hard limit: "4096:1024"`)},
+		} {
+			require.Equal(t, tc.exp, (&TaskConfig{
+				Rlimits: tc.rlimits,
+			}).validate())
+		}
+	})
 }
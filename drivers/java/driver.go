@@ -17,6 +17,8 @@ import (
 	"github.com/hashicorp/nomad/drivers/shared/eventer"
 	"github.com/hashicorp/nomad/drivers/shared/executor"
 	"github.com/hashicorp/nomad/drivers/shared/resolvconf"
+	"github.com/hashicorp/nomad/drivers/shared/seccomp"
+	"github.com/hashicorp/nomad/helper/pluginutils/hclutils"
 	"github.com/hashicorp/nomad/helper/pluginutils/loader"
 	"github.com/hashicorp/nomad/plugins/base"
 	"github.com/hashicorp/nomad/plugins/drivers"
@@ -78,6 +80,14 @@ var (
 			hclspec.NewAttr("allow_caps", "list(string)", false),
 			hclspec.NewLiteral(capabilities.HCLSpecLiteral),
 		),
+		"default_seccomp_profile": hclspec.NewDefault(
+			hclspec.NewAttr("default_seccomp_profile", "string", false),
+			hclspec.NewLiteral(`"unconfined"`),
+		),
+		"allow_seccomp_profiles": hclspec.NewDefault(
+			hclspec.NewAttr("allow_seccomp_profiles", "list(string)", false),
+			hclspec.NewLiteral(seccomp.HCLSpecLiteral),
+		),
 	})
 
 	// taskConfigSpec is the hcl specification for the driver config section of
@@ -95,6 +105,10 @@ var (
 		"ipc_mode":    hclspec.NewAttr("ipc_mode", "string", false),
 		"cap_add":     hclspec.NewAttr("cap_add", "list(string)", false),
 		"cap_drop":    hclspec.NewAttr("cap_drop", "list(string)", false),
+
+		"seccomp_profile": hclspec.NewAttr("seccomp_profile", "string", false),
+		"rlimits":         hclspec.NewAttr("rlimits", "list(map(string))", false),
+		"readonly_rootfs": hclspec.NewAttr("readonly_rootfs", "bool", false),
 	})
 
 	// driverCapabilities is returned by the Capabilities RPC and indicates what
@@ -133,6 +147,15 @@ type Config struct {
 	// AllowCaps configures which Linux Capabilities are enabled for tasks
 	// running on this node.
 	AllowCaps []string `codec:"allow_caps"`
+
+	// DefaultSeccompProfile is the seccomp profile applied to tasks which do
+	// not set one. It is either "default", "unconfined" or the path to a
+	// custom profile.
+	DefaultSeccompProfile string `codec:"default_seccomp_profile"`
+
+	// AllowSeccompProfiles configures which seccomp profiles tasks running on
+	// this node may set.
+	AllowSeccompProfiles []string `codec:"allow_seccomp_profiles"`
 }
 
 func (c *Config) validate() error {
@@ -153,6 +176,20 @@ func (c *Config) validate() error {
 		return fmt.Errorf("allow_caps configured with capabilities not supported by system: %s", badCaps)
 	}
 
+	if c.DefaultSeccompProfile != "" {
+		if err := seccomp.ValidateProfile(c.DefaultSeccompProfile); err != nil {
+			return fmt.Errorf("default_seccomp_profile: %v", err)
+		}
+		if err := seccomp.ValidateSupported(c.DefaultSeccompProfile); err != nil {
+			return fmt.Errorf("default_seccomp_profile: %v", err)
+		}
+	}
+	for _, profile := range c.AllowSeccompProfiles {
+		if err := seccomp.ValidateProfile(profile); err != nil {
+			return fmt.Errorf("allow_seccomp_profiles: %v", err)
+		}
+	}
+
 	return nil
 }
 
@@ -186,6 +223,17 @@ type TaskConfig struct {
 
 	// CapDrop is a set of linux capabilities to disable.
 	CapDrop []string `codec:"cap_drop"`
+
+	// SeccompProfile is the seccomp profile applied to the task, which must
+	// be allowed by the plugin configuration.
+	SeccompProfile string `codec:"seccomp_profile"`
+
+	// Rlimits are the resource limits of the task, mapping the resource to
+	// either "soft:hard" or a single limit.
+	Rlimits hclutils.MapStrStr `codec:"rlimits"`
+
+	// ReadonlyRootfs mounts the root filesystem of the task as read-only.
+	ReadonlyRootfs bool `codec:"readonly_rootfs"`
 }
 
 func (tc *TaskConfig) validate() error {
@@ -212,6 +260,19 @@ func (tc *TaskConfig) validate() error {
 		return fmt.Errorf("cap_drop configured with capabilities not supported by system: %s", badDrops)
 	}
 
+	if tc.SeccompProfile != "" {
+		if err := seccomp.ValidateProfile(tc.SeccompProfile); err != nil {
+			return err
+		}
+		if err := seccomp.ValidateSupported(tc.SeccompProfile); err != nil {
+			return err
+		}
+	}
+
+	if _, err := executor.ParseRlimits(tc.Rlimits); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -227,6 +288,8 @@ type TaskState struct {
 
 // Driver is a driver for running images via Java
 type Driver struct {
+	drivers.DriverCheckpointNotSupported
+
 	// eventer is used to handle multiplexing of TaskEvents calls such that an
 	// event can be broadcast to all callers
 	eventer *eventer.Eventer
@@ -369,6 +432,9 @@ func (d *Driver) buildFingerprint() *drivers.Fingerprint {
 	fp.Attributes[driverVersionAttr] = pstructs.NewStringAttribute(version)
 	fp.Attributes["driver.java.runtime"] = pstructs.NewStringAttribute(jdkJRE)
 	fp.Attributes["driver.java.vm"] = pstructs.NewStringAttribute(vm)
+	if seccomp.Supported() {
+		fp.Attributes["driver.java.seccomp"] = pstructs.NewBoolAttribute(true)
+	}
 
 	return fp
 }
@@ -448,6 +514,21 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 
 	args := javaCmdArgs(driverConfig)
 
+	profile, err := seccomp.Select(d.config.DefaultSeccompProfile, d.config.AllowSeccompProfiles, driverConfig.SeccompProfile)
+	if err != nil {
+		return nil, nil, err
+	}
+	seccompProfile, err := seccomp.Load(profile)
+	if err != nil {
+		return nil, nil, err
+	}
+	d.logger.Debug("task seccomp profile", "seccomp_profile", profile)
+
+	rlimits, err := executor.ParseRlimits(driverConfig.Rlimits)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	d.logger.Info("starting java task", "driver_cfg", hclog.Fmt("%+v", driverConfig), "args", args)
 
 	handle := drivers.NewTaskHandle(taskHandleVersion)
@@ -504,6 +585,9 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 		ModePID:          executor.IsolationMode(d.config.DefaultModePID, driverConfig.ModePID),
 		ModeIPC:          executor.IsolationMode(d.config.DefaultModeIPC, driverConfig.ModeIPC),
 		Capabilities:     caps,
+		SeccompProfile:   seccompProfile,
+		Rlimits:          rlimits,
+		ReadonlyRootfs:   driverConfig.ReadonlyRootfs,
 	}
 
 	ps, err := exec.Launch(execCmd)
@@ -14,6 +14,7 @@ import (
 
 	"github.com/hashicorp/nomad/ci"
 	ctestutil "github.com/hashicorp/nomad/client/testutil"
+	"github.com/hashicorp/nomad/drivers/shared/seccomp"
 	"github.com/hashicorp/nomad/helper/pluginutils/hclutils"
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/helper/uuid"
@@ -340,14 +341,22 @@ config {
   jar_path = "/tmp/jar.jar"
   jvm_options = ["-Xmx600"]
   args = ["arg1", "arg2"]
+  seccomp_profile = "/etc/nomad/seccomp.json"
+  rlimits {
+    nproc = "512"
+  }
+  readonly_rootfs = true
 }`
 
 	expected := &TaskConfig{
-		Class:     "java.main",
-		ClassPath: "/tmp/cp",
-		JarPath:   "/tmp/jar.jar",
-		JvmOpts:   []string{"-Xmx600"},
-		Args:      []string{"arg1", "arg2"},
+		Class:          "java.main",
+		ClassPath:      "/tmp/cp",
+		JarPath:        "/tmp/jar.jar",
+		JvmOpts:        []string{"-Xmx600"},
+		Args:           []string{"arg1", "arg2"},
+		SeccompProfile: "/etc/nomad/seccomp.json",
+		Rlimits:        hclutils.MapStrStr{"nproc": "512"},
+		ReadonlyRootfs: true,
 	}
 
 	var tc *TaskConfig
@@ -452,6 +461,40 @@ func TestDriver_Config_validate(t *testing.T) {
 			}).validate())
 		}
 	})
+
+	t.Run("seccomp", func(t *testing.T) {
+		if !seccomp.Supported() {
+			t.Skip("Nomad was built without seccomp support")
+		}
+		for _, tc := range []struct {
+			def     string
+			allowed []string
+			exp     error
+		}{
+			{def: "", allowed: nil, exp: nil},
+			{def: "default", allowed: []string{"default", "/etc/nomad/seccomp.json"}, exp: nil},
+			{def: "strict", allowed: nil, exp: errors.New(`default_seccomp_profile: seccomp profile must be "default", "unconfined" or an absolute path, got "strict"`)},
+			{def: "default", allowed: []string{"seccomp.json"}, exp: errors.New(`allow_seccomp_profiles: seccomp profile must be "default", "unconfined" or an absolute path, got "seccomp.json"`)},
+		} {
+			require.Equal(t, tc.exp, (&Config{
+				DefaultModePID:        "private",
+				DefaultModeIPC:        "private",
+				DefaultSeccompProfile: tc.def,
+				AllowSeccompProfiles:  tc.allowed,
+			}).validate())
+		}
+	})
+
+	t.Run("seccomp unsupported", func(t *testing.T) {
+		if seccomp.Supported() {
+			t.Skip("Nomad was built with seccomp support")
+		}
+		require.EqualError(t, (&Config{
+			DefaultModePID:        "private",
+			DefaultModeIPC:        "private",
+			DefaultSeccompProfile: "default",
+		}).validate(), `default_seccomp_profile: seccomp profile "default" configured but Nomad was built without seccomp support`)
+	})
 }
 
 func TestDriver_TaskConfig_validate(t *testing.T) {
@@ -512,4 +555,16 @@ func TestDriver_TaskConfig_validate(t *testing.T) {
 			}).validate())
 		}
 	})
+
+	t.Run("seccomp_profile", func(t *testing.T) {
+		require.NoError(t, (&TaskConfig{SeccompProfile: "unconfined"}).validate())
+		require.EqualError(t, (&TaskConfig{SeccompProfile: "strict"}).validate(),
+			`seccomp profile must be "default", "unconfined" or an absolute path, got "strict"`)
+	})
+
+	t.Run("rlimits", func(t *testing.T) {
+		require.NoError(t, (&TaskConfig{Rlimits: hclutils.MapStrStr{"core": "0"}}).validate())
+		require.EqualError(t, (&TaskConfig{Rlimits: hclutils.MapStrStr{"core": "none"}}).validate(),
+			`malformed soft rlimit core: "none": strconv.ParseUint: parsing "none": invalid syntax`)
+	})
 }
@@ -111,6 +111,8 @@ var (
 
 // Driver is a mock DriverPlugin implementation
 type Driver struct {
+	drivers.DriverCheckpointNotSupported
+
 	// eventer is used to handle multiplexing of TaskEvents calls such that an
 	// event can be broadcast to all callers
 	eventer *eventer.Eventer
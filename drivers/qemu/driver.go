@@ -153,6 +153,8 @@ type Config struct {
 
 // Driver is a driver for running images via Qemu
 type Driver struct {
+	drivers.DriverCheckpointNotSupported
+
 	// eventer is used to handle multiplexing of TaskEvents calls such that an
 	// event can be broadcast to all callers
 	eventer *eventer.Eventer
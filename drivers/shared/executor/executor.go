@@ -41,6 +41,19 @@ const (
 
 	// IsolationModeHost represents the host isolation mode for a namespace
 	IsolationModeHost = "host"
+
+	// RlimitCore is the resource limit of the size of core dumps
+	RlimitCore = "core"
+
+	// RlimitNoFile is the resource limit of the number of open files
+	RlimitNoFile = "nofile"
+
+	// RlimitNProc is the resource limit of the number of processes of the
+	// task user
+	RlimitNProc = "nproc"
+
+	// RlimitUnlimited is the value of resource limits which are not limited
+	RlimitUnlimited = "unlimited"
 )
 
 var (
@@ -88,14 +101,22 @@ type Executor interface {
 
 	ExecStreaming(ctx context.Context, cmd []string, tty bool,
 		stream drivers.ExecTaskStream) error
+
+	// Checkpoint dumps the state of the user process into dir with CRIU,
+	// which stops the process.
+	Checkpoint(dir string) error
+
+	// Restore restores the user process configured by the given ExecCommand
+	// from the checkpoint in dir, instead of launching it from scratch.
+	Restore(launchCmd *ExecCommand, dir string) (*ProcessState, error)
 }
 
 // ExecCommand holds the user command, args, and other isolation related
 // settings.
 //
-// Important (!): when adding fields, make sure to update the RPC methods in
-// grpcExecutorClient.Launch and grpcExecutorServer.Launch. Number of hours
-// spent tracking this down: too many.
+// Important (!): when adding fields, make sure to update the RPC conversions in
+// launchRequestToProto and execCommandFromProto. Number of hours spent
+// tracking this down: too many.
 type ExecCommand struct {
 	// Cmd is the command that the user wants to run.
 	Cmd string
@@ -154,6 +175,34 @@ type ExecCommand struct {
 
 	// Capabilities are the linux capabilities to be enabled by the task driver.
 	Capabilities []string
+
+	// SeccompProfile is the JSON encoded OCI seccomp configuration applied
+	// to the task. Seccomp filtering is disabled if empty.
+	SeccompProfile []byte
+
+	// Rlimits are the resource limits of the task process.
+	Rlimits []*Rlimit
+
+	// ReadonlyRootfs mounts the root filesystem of the task as read-only,
+	// except for the task directories.
+	ReadonlyRootfs bool
+
+	// CriuPath is the path of the criu binary used to checkpoint and restore
+	// the task. Defaults to criu in the PATH if empty.
+	CriuPath string
+}
+
+// Rlimit is a resource limit of the task process, such as the maximum number
+// of open files.
+type Rlimit struct {
+	// Type is the name of the resource (nofile, nproc or core).
+	Type string
+
+	// Soft is the limit enforced for the process.
+	Soft uint64
+
+	// Hard is the ceiling up to which the process can raise the soft limit.
+	Hard uint64
 }
 
 // SetWriters sets the writer for the process stdout and stderr. This should
@@ -449,6 +498,17 @@ func (e *UniversalExecutor) ExecStreaming(ctx context.Context, command []string,
 	return execHelper.run(ctx, tty, stream)
 }
 
+// Checkpoint is not supported without isolation, as CRIU needs the task to
+// run in its own namespaces to restore it.
+func (e *UniversalExecutor) Checkpoint(dir string) error {
+	return fmt.Errorf("checkpoint is not supported by executors without isolation")
+}
+
+// Restore is not supported without isolation, see Checkpoint.
+func (e *UniversalExecutor) Restore(command *ExecCommand, dir string) (*ProcessState, error) {
+	return nil, fmt.Errorf("restore is not supported by executors without isolation")
+}
+
 // Wait waits until a process has exited and returns it's exitcode and errors
 func (e *UniversalExecutor) Wait(ctx context.Context) (*ProcessState, error) {
 	select {
@@ -4,6 +4,7 @@ package executor
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -33,6 +34,7 @@ import (
 	lconfigs "github.com/opencontainers/runc/libcontainer/configs"
 	"github.com/opencontainers/runc/libcontainer/devices"
 	ldevices "github.com/opencontainers/runc/libcontainer/devices"
+	lseccomp "github.com/opencontainers/runc/libcontainer/seccomp"
 	"github.com/opencontainers/runc/libcontainer/specconv"
 	lutils "github.com/opencontainers/runc/libcontainer/utils"
 	"github.com/opencontainers/runtime-spec/specs-go"
@@ -87,6 +89,59 @@ func NewExecutorWithIsolation(logger hclog.Logger) Executor {
 func (l *LibcontainerExecutor) Launch(command *ExecCommand) (*ProcessState, error) {
 	l.logger.Trace("preparing to launch command", "command", command.Cmd, "args", strings.Join(command.Args, " "))
 
+	return l.launch(command, func(process *libcontainer.Process) error {
+		l.logger.Debug("launching", "command", command.Cmd, "args", strings.Join(command.Args, " "))
+		return l.container.Run(process)
+	})
+}
+
+// Restore creates a new container in libcontainer and restores the process
+// checkpointed into dir in it, instead of starting a new process.
+func (l *LibcontainerExecutor) Restore(command *ExecCommand, dir string) (*ProcessState, error) {
+	l.logger.Trace("preparing to restore command", "command", command.Cmd, "args", strings.Join(command.Args, " "), "checkpoint", dir)
+
+	return l.launch(command, func(process *libcontainer.Process) error {
+		l.logger.Debug("restoring", "command", command.Cmd, "args", strings.Join(command.Args, " "), "checkpoint", dir)
+		if err := l.container.Restore(process, criuOpts(dir)); err != nil {
+			return fmt.Errorf("failed to restore container(%s): %v", l.id, err)
+		}
+		return nil
+	})
+}
+
+// Checkpoint dumps the state of the container into dir with CRIU. The
+// processes of the container are stopped once the checkpoint is complete.
+func (l *LibcontainerExecutor) Checkpoint(dir string) error {
+	if l.container == nil {
+		return fmt.Errorf("container not launched")
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create checkpoint directory: %v", err)
+	}
+
+	l.logger.Debug("checkpointing", "checkpoint", dir)
+	if err := l.container.Checkpoint(criuOpts(dir)); err != nil {
+		return fmt.Errorf("failed to checkpoint container(%s): %v", l.id, err)
+	}
+	return nil
+}
+
+// criuOpts returns the CRIU options to checkpoint a container into dir or to
+// restore it from there. The task runs in its own mount namespace, so the
+// checkpoint doesn't depend on the host path of the task directory and can be
+// restored on another client.
+func criuOpts(dir string) *libcontainer.CriuOpts {
+	return &libcontainer.CriuOpts{
+		ImagesDirectory: dir,
+		FileLocks:       true,
+	}
+}
+
+// launch creates a new container in libcontainer and starts the process of
+// the command in it with start, which either runs or restores the process.
+func (l *LibcontainerExecutor) launch(command *ExecCommand, start func(*libcontainer.Process) error) (*ProcessState, error) {
+
 	if command.Resources == nil {
 		command.Resources = &drivers.Resources{
 			NomadResources: &structs.AllocatedTaskResources{},
@@ -95,15 +150,19 @@ func (l *LibcontainerExecutor) Launch(command *ExecCommand) (*ProcessState, erro
 
 	l.command = command
 
-	// create a new factory which will store the container state in the allocDir
-	factory, err := libcontainer.New(
-		path.Join(command.TaskDir, "../alloc/container"),
+	factoryOpts := []func(*libcontainer.LinuxFactory) error{
 		libcontainer.Cgroupfs,
 		// note that os.Args[0] refers to the executor shim typically
 		// and first args arguments is ignored now due
 		// until https://github.com/opencontainers/runc/pull/1888 is merged
 		libcontainer.InitArgs(os.Args[0], "libcontainer-shim"),
-	)
+	}
+	if command.CriuPath != "" {
+		factoryOpts = append(factoryOpts, libcontainer.CriuPath(command.CriuPath))
+	}
+
+	// create a new factory which will store the container state in the allocDir
+	factory, err := libcontainer.New(path.Join(command.TaskDir, "../alloc/container"), factoryOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create factory: %v", err)
 	}
@@ -156,8 +215,6 @@ func (l *LibcontainerExecutor) Launch(command *ExecCommand) (*ProcessState, erro
 		return nil, err
 	}
 
-	l.logger.Debug("launching", "command", command.Cmd, "args", strings.Join(command.Args, " "))
-
 	// the task process will be started by the container
 	process := &libcontainer.Process{
 		Args:   combined,
@@ -177,7 +234,7 @@ func (l *LibcontainerExecutor) Launch(command *ExecCommand) (*ProcessState, erro
 	l.systemCpuStats = stats.NewCpuStats()
 
 	// Starts the task
-	if err := container.Run(process); err != nil {
+	if err := start(process); err != nil {
 		container.Destroy()
 		return nil, err
 	}
@@ -649,13 +706,81 @@ func configureIsolation(cfg *lconfigs.Config, command *ExecCommand) error {
 		},
 	}
 
+	if cgutil.Rootless {
+		if err := configureRootless(cfg, command); err != nil {
+			return err
+		}
+	}
+
 	if len(command.Mounts) > 0 {
 		cfg.Mounts = append(cfg.Mounts, cmdMounts(command.Mounts)...)
 	}
 
+	if command.ReadonlyRootfs {
+		configureReadonlyRootfs(cfg, command)
+	}
+
 	return nil
 }
 
+// configureRootless isolates the task of a rootless client in a user namespace
+// mapping root to the unprivileged user running the client. Filesystems which
+// can only be mounted by the owner of the host namespaces are bind mounted
+// instead, and the shared alloc dir, which a rootless client cannot mount into
+// the task dir, is bind mounted in the task's namespace.
+func configureRootless(cfg *lconfigs.Config, command *ExecCommand) error {
+	// mounting proc and mqueue requires owning the pid and ipc namespaces
+	if command.ModePID == IsolationModeHost || command.ModeIPC == IsolationModeHost {
+		return errors.New("rootless tasks require private pid and ipc modes")
+	}
+
+	cfg.RootlessEUID = true
+	cfg.RootlessCgroups = true
+	cfg.Namespaces = append(cfg.Namespaces, lconfigs.Namespace{Type: lconfigs.NEWUSER})
+	cfg.UidMappings = []lconfigs.IDMap{{ContainerID: 0, HostID: os.Geteuid(), Size: 1}}
+	cfg.GidMappings = []lconfigs.IDMap{{ContainerID: 0, HostID: os.Getegid(), Size: 1}}
+
+	for i, mount := range cfg.Mounts {
+		switch mount.Device {
+		case "sysfs":
+			cfg.Mounts[i] = &lconfigs.Mount{
+				Source:      "/sys",
+				Destination: "/sys",
+				Device:      "bind",
+				Flags:       mount.Flags | unix.MS_BIND | unix.MS_REC,
+			}
+		case "devpts":
+			// the tty group is not mapped in the user namespace
+			mount.Data = "newinstance,ptmxmode=0666,mode=0620"
+		}
+	}
+
+	cfg.Mounts = append(cfg.Mounts, &lconfigs.Mount{
+		Source:      filepath.Join(filepath.Dir(command.TaskDir), allocdir.SharedAllocName),
+		Destination: "/" + allocdir.SharedAllocName,
+		Device:      "bind",
+		Flags:       unix.MS_BIND | unix.MS_REC,
+	})
+	return nil
+}
+
+// configureReadonlyRootfs remounts the root filesystem of the task as
+// read-only. The alloc and secrets directories are separate mounts and remain
+// writable, while the local and tmp directories are bind mounted onto
+// themselves so that they do too.
+func configureReadonlyRootfs(cfg *lconfigs.Config, command *ExecCommand) {
+	cfg.Readonlyfs = true
+
+	for _, dir := range []string{allocdir.TaskLocal, allocdir.TmpDirName} {
+		cfg.Mounts = append(cfg.Mounts, &lconfigs.Mount{
+			Source:      filepath.Join(command.TaskDir, dir),
+			Destination: "/" + dir,
+			Device:      "bind",
+			Flags:       unix.MS_BIND | unix.MS_REC,
+		})
+	}
+}
+
 func configureCgroups(cfg *lconfigs.Config, command *ExecCommand) error {
 	// If resources are not limited then manually create cgroups needed
 	if !command.ResourceLimits {
@@ -748,9 +873,66 @@ func newLibcontainerConfig(command *ExecCommand) (*lconfigs.Config, error) {
 		return nil, err
 	}
 
+	if err := configureSeccomp(cfg, command); err != nil {
+		return nil, err
+	}
+
+	if err := configureRlimits(cfg, command); err != nil {
+		return nil, err
+	}
+
 	return cfg, nil
 }
 
+// configureSeccomp installs the seccomp profile of the task, if any. Seccomp
+// filtering requires Nomad to be built with the seccomp build tag.
+func configureSeccomp(cfg *lconfigs.Config, command *ExecCommand) error {
+	if len(command.SeccompProfile) == 0 {
+		return nil
+	}
+
+	if major, _, _ := lseccomp.Version(); major == 0 {
+		return fmt.Errorf("seccomp profile configured but Nomad was built without seccomp support")
+	}
+
+	var profile specs.LinuxSeccomp
+	if err := json.Unmarshal(command.SeccompProfile, &profile); err != nil {
+		return fmt.Errorf("failed to decode seccomp profile: %v", err)
+	}
+
+	seccomp, err := specconv.SetupSeccomp(&profile)
+	if err != nil {
+		return fmt.Errorf("failed to configure seccomp profile: %v", err)
+	}
+	cfg.Seccomp = seccomp
+	return nil
+}
+
+// rlimitResources maps the names of the supported resource limits to their
+// resource.
+var rlimitResources = map[string]int{
+	RlimitCore:   unix.RLIMIT_CORE,
+	RlimitNoFile: unix.RLIMIT_NOFILE,
+	RlimitNProc:  unix.RLIMIT_NPROC,
+}
+
+// configureRlimits sets the resource limits of the task process. Resources
+// without limits are inherited from the executor.
+func configureRlimits(cfg *lconfigs.Config, command *ExecCommand) error {
+	for _, rlimit := range command.Rlimits {
+		resource, ok := rlimitResources[rlimit.Type]
+		if !ok {
+			return fmt.Errorf("unsupported rlimit %q", rlimit.Type)
+		}
+		cfg.Rlimits = append(cfg.Rlimits, lconfigs.Rlimit{
+			Type: resource,
+			Soft: rlimit.Soft,
+			Hard: rlimit.Hard,
+		})
+	}
+	return nil
+}
+
 // cmdDevices converts a list of driver.DeviceConfigs into excutor.Devices.
 func cmdDevices(driverDevices []*drivers.DeviceConfig) ([]*devices.Device, error) {
 	if len(driverDevices) == 0 {
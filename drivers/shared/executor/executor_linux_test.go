@@ -18,6 +18,7 @@ import (
 	"github.com/hashicorp/nomad/client/taskenv"
 	"github.com/hashicorp/nomad/client/testutil"
 	"github.com/hashicorp/nomad/drivers/shared/capabilities"
+	"github.com/hashicorp/nomad/drivers/shared/seccomp"
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/plugins/drivers"
@@ -25,6 +26,7 @@ import (
 	"github.com/opencontainers/runc/libcontainer/cgroups"
 	lconfigs "github.com/opencontainers/runc/libcontainer/configs"
 	"github.com/opencontainers/runc/libcontainer/devices"
+	lseccomp "github.com/opencontainers/runc/libcontainer/seccomp"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/sys/unix"
 )
@@ -626,6 +628,191 @@ CapAmb: 0000000000000000`,
 
 }
 
+func TestExecutor_configureRlimits(t *testing.T) {
+	ci.Parallel(t)
+
+	cfg := &lconfigs.Config{}
+	err := configureRlimits(cfg, &ExecCommand{
+		Rlimits: []*Rlimit{
+			{Type: RlimitCore, Soft: 0, Hard: 0},
+			{Type: RlimitNoFile, Soft: 1024, Hard: 4096},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []lconfigs.Rlimit{
+		{Type: unix.RLIMIT_CORE, Soft: 0, Hard: 0},
+		{Type: unix.RLIMIT_NOFILE, Soft: 1024, Hard: 4096},
+	}, cfg.Rlimits)
+
+	err = configureRlimits(&lconfigs.Config{}, &ExecCommand{
+		Rlimits: []*Rlimit{{Type: "stack"}},
+	})
+	require.EqualError(t, err, `unsupported rlimit "stack"`)
+}
+
+func TestExecutor_configureReadonlyRootfs(t *testing.T) {
+	ci.Parallel(t)
+
+	cfg := &lconfigs.Config{}
+	configureReadonlyRootfs(cfg, &ExecCommand{TaskDir: "/nomad/alloc/task"})
+	require.True(t, cfg.Readonlyfs)
+	require.Equal(t, []*lconfigs.Mount{
+		{
+			Source:      "/nomad/alloc/task/local",
+			Destination: "/local",
+			Device:      "bind",
+			Flags:       unix.MS_BIND | unix.MS_REC,
+		},
+		{
+			Source:      "/nomad/alloc/task/tmp",
+			Destination: "/tmp",
+			Device:      "bind",
+			Flags:       unix.MS_BIND | unix.MS_REC,
+		},
+	}, cfg.Mounts)
+}
+
+func TestExecutor_configureRootless(t *testing.T) {
+	ci.Parallel(t)
+
+	command := &ExecCommand{
+		TaskDir: "/nomad/alloc/task",
+		ModePID: IsolationModePrivate,
+		ModeIPC: IsolationModePrivate,
+	}
+	cfg := &lconfigs.Config{
+		Namespaces: configureNamespaces(command.ModePID, command.ModeIPC),
+		Mounts: []*lconfigs.Mount{
+			{
+				Source:      "devpts",
+				Destination: "/dev/pts",
+				Device:      "devpts",
+				Flags:       unix.MS_NOSUID | unix.MS_NOEXEC,
+				Data:        "newinstance,ptmxmode=0666,mode=0620,gid=5",
+			},
+			{
+				Source:      "sysfs",
+				Destination: "/sys",
+				Device:      "sysfs",
+				Flags:       unix.MS_RDONLY,
+			},
+		},
+	}
+	require.NoError(t, configureRootless(cfg, command))
+
+	require.True(t, cfg.RootlessEUID)
+	require.True(t, cfg.RootlessCgroups)
+	require.True(t, cfg.Namespaces.Contains(lconfigs.NEWUSER))
+	require.Equal(t, []lconfigs.IDMap{{ContainerID: 0, HostID: os.Geteuid(), Size: 1}}, cfg.UidMappings)
+	require.Equal(t, []lconfigs.IDMap{{ContainerID: 0, HostID: os.Getegid(), Size: 1}}, cfg.GidMappings)
+	require.Equal(t, []*lconfigs.Mount{
+		{
+			Source:      "devpts",
+			Destination: "/dev/pts",
+			Device:      "devpts",
+			Flags:       unix.MS_NOSUID | unix.MS_NOEXEC,
+			Data:        "newinstance,ptmxmode=0666,mode=0620",
+		},
+		{
+			Source:      "/sys",
+			Destination: "/sys",
+			Device:      "bind",
+			Flags:       unix.MS_RDONLY | unix.MS_BIND | unix.MS_REC,
+		},
+		{
+			Source:      "/nomad/alloc/alloc",
+			Destination: "/alloc",
+			Device:      "bind",
+			Flags:       unix.MS_BIND | unix.MS_REC,
+		},
+	}, cfg.Mounts)
+
+	// Rootless tasks cannot mount proc in the host pid namespace
+	command.ModePID = IsolationModeHost
+	require.EqualError(t, configureRootless(&lconfigs.Config{}, command), "rootless tasks require private pid and ipc modes")
+}
+
+func TestExecutor_ReadonlyRootfsAndRlimits(t *testing.T) {
+	ci.Parallel(t)
+	testutil.ExecCompatible(t)
+
+	testExecCmd := testExecutorCommandWithChroot(t)
+	execCmd, allocDir := testExecCmd.command, testExecCmd.allocDir
+	defer allocDir.Destroy()
+
+	execCmd.ResourceLimits = true
+	execCmd.ReadonlyRootfs = true
+	execCmd.Rlimits = []*Rlimit{{Type: RlimitNoFile, Soft: 1024, Hard: 4096}}
+	execCmd.Cmd = "/bin/bash"
+	execCmd.Args = []string{"-c", strings.Join([]string{
+		"ulimit -Sn",
+		"ulimit -Hn",
+		"(echo > /rootfs) 2>/dev/null || echo rootfs-readonly",
+		"echo > /local/file && echo local-writable",
+		"echo > /tmp/file && echo tmp-writable",
+		"echo > /alloc/file && echo alloc-writable",
+	}, "; ")}
+
+	executor := NewExecutorWithIsolation(testlog.HCLogger(t))
+	defer executor.Shutdown("SIGKILL", 0)
+
+	_, err := executor.Launch(execCmd)
+	require.NoError(t, err)
+
+	ps, err := executor.Wait(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, ps.ExitCode, testExecCmd.stderr.String())
+
+	expected := "1024\n4096\nrootfs-readonly\nlocal-writable\ntmp-writable\nalloc-writable"
+	tu.WaitForResult(func() (bool, error) {
+		output := strings.TrimSpace(testExecCmd.stdout.String())
+		if output != expected {
+			return false, fmt.Errorf("output didn't match: want\n%v\n; got:\n%v\n", expected, output)
+		}
+		return true, nil
+	}, func(err error) { require.NoError(t, err) })
+}
+
+func TestExecutor_Seccomp(t *testing.T) {
+	ci.Parallel(t)
+	testutil.ExecCompatible(t)
+
+	if major, _, _ := lseccomp.Version(); major == 0 {
+		t.Skip("Nomad was built without seccomp support")
+	}
+
+	profile, err := seccomp.Load(seccomp.ProfileDefault)
+	require.NoError(t, err)
+
+	testExecCmd := testExecutorCommandWithChroot(t)
+	execCmd, allocDir := testExecCmd.command, testExecCmd.allocDir
+	defer allocDir.Destroy()
+
+	execCmd.ResourceLimits = true
+	execCmd.SeccompProfile = profile
+	execCmd.Cmd = "/bin/bash"
+	execCmd.Args = []string{"-c", "cat /proc/self/status"}
+
+	executor := NewExecutorWithIsolation(testlog.HCLogger(t))
+	defer executor.Shutdown("SIGKILL", 0)
+
+	_, err = executor.Launch(execCmd)
+	require.NoError(t, err)
+
+	ps, err := executor.Wait(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, ps.ExitCode, testExecCmd.stderr.String())
+
+	// The task runs in seccomp filter mode
+	tu.WaitForResult(func() (bool, error) {
+		output := testExecCmd.stdout.String()
+		if !regexp.MustCompile(`Seccomp:\s+2`).MatchString(output) {
+			return false, fmt.Errorf("task is not filtered by seccomp:\n%v", output)
+		}
+		return true, nil
+	}, func(err error) { require.NoError(t, err) })
+}
+
 func TestExecutor_ClientCleanup(t *testing.T) {
 	ci.Parallel(t)
 	testutil.ExecCompatible(t)
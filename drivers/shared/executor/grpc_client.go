@@ -32,7 +32,48 @@ type grpcExecutorClient struct {
 
 func (c *grpcExecutorClient) Launch(cmd *ExecCommand) (*ProcessState, error) {
 	ctx := context.Background()
-	req := &proto.LaunchRequest{
+	resp, err := c.client.Launch(ctx, launchRequestToProto(cmd))
+	if err != nil {
+		return nil, err
+	}
+
+	ps, err := processStateFromProto(resp.Process)
+	if err != nil {
+		return nil, err
+	}
+	return ps, nil
+}
+
+func (c *grpcExecutorClient) Restore(cmd *ExecCommand, dir string) (*ProcessState, error) {
+	ctx := context.Background()
+	req := &proto.RestoreRequest{
+		Launch: launchRequestToProto(cmd),
+		Dir:    dir,
+	}
+	resp, err := c.client.Restore(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	ps, err := processStateFromProto(resp.Process)
+	if err != nil {
+		return nil, err
+	}
+	return ps, nil
+}
+
+func (c *grpcExecutorClient) Checkpoint(dir string) error {
+	ctx := context.Background()
+	req := &proto.CheckpointRequest{Dir: dir}
+	if _, err := c.client.Checkpoint(ctx, req); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func launchRequestToProto(cmd *ExecCommand) *proto.LaunchRequest {
+	return &proto.LaunchRequest{
 		Cmd:                cmd.Cmd,
 		Args:               cmd.Args,
 		Resources:          drivers.ResourcesToProto(cmd.Resources),
@@ -50,17 +91,11 @@ func (c *grpcExecutorClient) Launch(cmd *ExecCommand) (*ProcessState, error) {
 		DefaultPidMode:     cmd.ModePID,
 		DefaultIpcMode:     cmd.ModeIPC,
 		Capabilities:       cmd.Capabilities,
+		SeccompProfile:     cmd.SeccompProfile,
+		Rlimits:            rlimitsToProto(cmd.Rlimits),
+		ReadonlyRootfs:     cmd.ReadonlyRootfs,
+		CriuPath:           cmd.CriuPath,
 	}
-	resp, err := c.client.Launch(ctx, req)
-	if err != nil {
-		return nil, err
-	}
-
-	ps, err := processStateFromProto(resp.Process)
-	if err != nil {
-		return nil, err
-	}
-	return ps, nil
 }
 
 func (c *grpcExecutorClient) Wait(ctx context.Context) (*ProcessState, error) {
@@ -46,6 +46,10 @@ type LaunchRequest struct {
 	CpusetCgroup         string                       `protobuf:"bytes,17,opt,name=cpuset_cgroup,json=cpusetCgroup,proto3" json:"cpuset_cgroup,omitempty"`
 	AllowCaps            []string                     `protobuf:"bytes,18,rep,name=allow_caps,json=allowCaps,proto3" json:"allow_caps,omitempty"`
 	Capabilities         []string                     `protobuf:"bytes,19,rep,name=capabilities,proto3" json:"capabilities,omitempty"`
+	SeccompProfile       []byte                       `protobuf:"bytes,20,opt,name=seccomp_profile,json=seccompProfile,proto3" json:"seccomp_profile,omitempty"`
+	Rlimits              []*Rlimit                    `protobuf:"bytes,21,rep,name=rlimits,proto3" json:"rlimits,omitempty"`
+	ReadonlyRootfs       bool                         `protobuf:"varint,22,opt,name=readonly_rootfs,json=readonlyRootfs,proto3" json:"readonly_rootfs,omitempty"`
+	CriuPath             string                       `protobuf:"bytes,23,opt,name=criu_path,json=criuPath,proto3" json:"criu_path,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}                     `json:"-"`
 	XXX_unrecognized     []byte                       `json:"-"`
 	XXX_sizecache        int32                        `json:"-"`
@@ -209,6 +213,34 @@ func (m *LaunchRequest) GetCapabilities() []string {
 	return nil
 }
 
+func (m *LaunchRequest) GetSeccompProfile() []byte {
+	if m != nil {
+		return m.SeccompProfile
+	}
+	return nil
+}
+
+func (m *LaunchRequest) GetRlimits() []*Rlimit {
+	if m != nil {
+		return m.Rlimits
+	}
+	return nil
+}
+
+func (m *LaunchRequest) GetReadonlyRootfs() bool {
+	if m != nil {
+		return m.ReadonlyRootfs
+	}
+	return false
+}
+
+func (m *LaunchRequest) GetCriuPath() string {
+	if m != nil {
+		return m.CriuPath
+	}
+	return ""
+}
+
 type LaunchResponse struct {
 	Process              *ProcessState `protobuf:"bytes,1,opt,name=process,proto3" json:"process,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
@@ -786,6 +818,162 @@ func (m *ExecResponse) GetExitCode() int32 {
 	return 0
 }
 
+type CheckpointRequest struct {
+	Dir                  string   `protobuf:"bytes,1,opt,name=dir,proto3" json:"dir,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckpointRequest) Reset()         { *m = CheckpointRequest{} }
+func (m *CheckpointRequest) String() string { return proto.CompactTextString(m) }
+func (*CheckpointRequest) ProtoMessage()    {}
+func (*CheckpointRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_66b85426380683f3, []int{16}
+}
+
+func (m *CheckpointRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckpointRequest.Unmarshal(m, b)
+}
+func (m *CheckpointRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckpointRequest.Marshal(b, m, deterministic)
+}
+func (m *CheckpointRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckpointRequest.Merge(m, src)
+}
+func (m *CheckpointRequest) XXX_Size() int {
+	return xxx_messageInfo_CheckpointRequest.Size(m)
+}
+func (m *CheckpointRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckpointRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckpointRequest proto.InternalMessageInfo
+
+func (m *CheckpointRequest) GetDir() string {
+	if m != nil {
+		return m.Dir
+	}
+	return ""
+}
+
+type CheckpointResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckpointResponse) Reset()         { *m = CheckpointResponse{} }
+func (m *CheckpointResponse) String() string { return proto.CompactTextString(m) }
+func (*CheckpointResponse) ProtoMessage()    {}
+func (*CheckpointResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_66b85426380683f3, []int{17}
+}
+
+func (m *CheckpointResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckpointResponse.Unmarshal(m, b)
+}
+func (m *CheckpointResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckpointResponse.Marshal(b, m, deterministic)
+}
+func (m *CheckpointResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckpointResponse.Merge(m, src)
+}
+func (m *CheckpointResponse) XXX_Size() int {
+	return xxx_messageInfo_CheckpointResponse.Size(m)
+}
+func (m *CheckpointResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckpointResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckpointResponse proto.InternalMessageInfo
+
+type RestoreRequest struct {
+	Launch               *LaunchRequest `protobuf:"bytes,1,opt,name=launch,proto3" json:"launch,omitempty"`
+	Dir                  string         `protobuf:"bytes,2,opt,name=dir,proto3" json:"dir,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *RestoreRequest) Reset()         { *m = RestoreRequest{} }
+func (m *RestoreRequest) String() string { return proto.CompactTextString(m) }
+func (*RestoreRequest) ProtoMessage()    {}
+func (*RestoreRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_66b85426380683f3, []int{18}
+}
+
+func (m *RestoreRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestoreRequest.Unmarshal(m, b)
+}
+func (m *RestoreRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestoreRequest.Marshal(b, m, deterministic)
+}
+func (m *RestoreRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestoreRequest.Merge(m, src)
+}
+func (m *RestoreRequest) XXX_Size() int {
+	return xxx_messageInfo_RestoreRequest.Size(m)
+}
+func (m *RestoreRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestoreRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestoreRequest proto.InternalMessageInfo
+
+func (m *RestoreRequest) GetLaunch() *LaunchRequest {
+	if m != nil {
+		return m.Launch
+	}
+	return nil
+}
+
+func (m *RestoreRequest) GetDir() string {
+	if m != nil {
+		return m.Dir
+	}
+	return ""
+}
+
+type RestoreResponse struct {
+	Process              *ProcessState `protobuf:"bytes,1,opt,name=process,proto3" json:"process,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *RestoreResponse) Reset()         { *m = RestoreResponse{} }
+func (m *RestoreResponse) String() string { return proto.CompactTextString(m) }
+func (*RestoreResponse) ProtoMessage()    {}
+func (*RestoreResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_66b85426380683f3, []int{19}
+}
+
+func (m *RestoreResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestoreResponse.Unmarshal(m, b)
+}
+func (m *RestoreResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestoreResponse.Marshal(b, m, deterministic)
+}
+func (m *RestoreResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestoreResponse.Merge(m, src)
+}
+func (m *RestoreResponse) XXX_Size() int {
+	return xxx_messageInfo_RestoreResponse.Size(m)
+}
+func (m *RestoreResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestoreResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestoreResponse proto.InternalMessageInfo
+
+func (m *RestoreResponse) GetProcess() *ProcessState {
+	if m != nil {
+		return m.Process
+	}
+	return nil
+}
+
 type ProcessState struct {
 	Pid                  int32                `protobuf:"varint,1,opt,name=pid,proto3" json:"pid,omitempty"`
 	ExitCode             int32                `protobuf:"varint,2,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
@@ -800,7 +988,7 @@ func (m *ProcessState) Reset()         { *m = ProcessState{} }
 func (m *ProcessState) String() string { return proto.CompactTextString(m) }
 func (*ProcessState) ProtoMessage()    {}
 func (*ProcessState) Descriptor() ([]byte, []int) {
-	return fileDescriptor_66b85426380683f3, []int{16}
+	return fileDescriptor_66b85426380683f3, []int{20}
 }
 
 func (m *ProcessState) XXX_Unmarshal(b []byte) error {
@@ -849,6 +1037,61 @@ func (m *ProcessState) GetTime() *timestamp.Timestamp {
 	return nil
 }
 
+type Rlimit struct {
+	Type                 string   `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Soft                 uint64   `protobuf:"varint,2,opt,name=soft,proto3" json:"soft,omitempty"`
+	Hard                 uint64   `protobuf:"varint,3,opt,name=hard,proto3" json:"hard,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Rlimit) Reset()         { *m = Rlimit{} }
+func (m *Rlimit) String() string { return proto.CompactTextString(m) }
+func (*Rlimit) ProtoMessage()    {}
+func (*Rlimit) Descriptor() ([]byte, []int) {
+	return fileDescriptor_66b85426380683f3, []int{21}
+}
+
+func (m *Rlimit) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Rlimit.Unmarshal(m, b)
+}
+func (m *Rlimit) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Rlimit.Marshal(b, m, deterministic)
+}
+func (m *Rlimit) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Rlimit.Merge(m, src)
+}
+func (m *Rlimit) XXX_Size() int {
+	return xxx_messageInfo_Rlimit.Size(m)
+}
+func (m *Rlimit) XXX_DiscardUnknown() {
+	xxx_messageInfo_Rlimit.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Rlimit proto.InternalMessageInfo
+
+func (m *Rlimit) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *Rlimit) GetSoft() uint64 {
+	if m != nil {
+		return m.Soft
+	}
+	return 0
+}
+
+func (m *Rlimit) GetHard() uint64 {
+	if m != nil {
+		return m.Hard
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*LaunchRequest)(nil), "hashicorp.nomad.plugins.executor.proto.LaunchRequest")
 	proto.RegisterType((*LaunchResponse)(nil), "hashicorp.nomad.plugins.executor.proto.LaunchResponse")
@@ -866,7 +1109,12 @@ func init() {
 	proto.RegisterType((*SignalResponse)(nil), "hashicorp.nomad.plugins.executor.proto.SignalResponse")
 	proto.RegisterType((*ExecRequest)(nil), "hashicorp.nomad.plugins.executor.proto.ExecRequest")
 	proto.RegisterType((*ExecResponse)(nil), "hashicorp.nomad.plugins.executor.proto.ExecResponse")
+	proto.RegisterType((*CheckpointRequest)(nil), "hashicorp.nomad.plugins.executor.proto.CheckpointRequest")
+	proto.RegisterType((*CheckpointResponse)(nil), "hashicorp.nomad.plugins.executor.proto.CheckpointResponse")
+	proto.RegisterType((*RestoreRequest)(nil), "hashicorp.nomad.plugins.executor.proto.RestoreRequest")
+	proto.RegisterType((*RestoreResponse)(nil), "hashicorp.nomad.plugins.executor.proto.RestoreResponse")
 	proto.RegisterType((*ProcessState)(nil), "hashicorp.nomad.plugins.executor.proto.ProcessState")
+	proto.RegisterType((*Rlimit)(nil), "hashicorp.nomad.plugins.executor.proto.Rlimit")
 }
 
 func init() {
@@ -874,74 +1122,86 @@ func init() {
 }
 
 var fileDescriptor_66b85426380683f3 = []byte{
-	// 1058 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x55, 0x6d, 0x6f, 0x1b, 0x45,
-	0x10, 0xee, 0xc5, 0x89, 0x5f, 0xc6, 0x76, 0xe2, 0x2e, 0xa8, 0x5c, 0x8d, 0x50, 0xcd, 0x21, 0x51,
-	0x0b, 0xca, 0x25, 0x4a, 0xdf, 0x90, 0x90, 0x28, 0x22, 0x2d, 0xa8, 0x52, 0x1a, 0x45, 0x97, 0x42,
-	0x25, 0x3e, 0x70, 0x6c, 0xee, 0xb6, 0xf6, 0x2a, 0xf6, 0xed, 0xb2, 0xbb, 0xe7, 0x04, 0x09, 0x89,
-	0x4f, 0xfc, 0x03, 0x90, 0xf8, 0x31, 0xfc, 0x38, 0xb4, 0x6f, 0x17, 0x3b, 0x2d, 0xd5, 0xb9, 0x88,
-	0x4f, 0xbe, 0x1d, 0x3f, 0xcf, 0xcc, 0xec, 0xce, 0xcc, 0x33, 0x70, 0x27, 0x17, 0x74, 0x41, 0x84,
-	0xdc, 0x95, 0x53, 0x2c, 0x48, 0xbe, 0x4b, 0x2e, 0x48, 0x56, 0x2a, 0x26, 0x76, 0xb9, 0x60, 0x8a,
-	0x55, 0xc7, 0xd8, 0x1c, 0xd1, 0xc7, 0x53, 0x2c, 0xa7, 0x34, 0x63, 0x82, 0xc7, 0x05, 0x9b, 0xe3,
-	0x3c, 0xe6, 0xb3, 0x72, 0x42, 0x0b, 0x19, 0xaf, 0xe2, 0x86, 0xb7, 0x26, 0x8c, 0x4d, 0x66, 0xc4,
-	0x3a, 0x39, 0x2d, 0x5f, 0xee, 0x2a, 0x3a, 0x27, 0x52, 0xe1, 0x39, 0x77, 0x80, 0xc8, 0x11, 0x77,
-	0x7d, 0x78, 0x1b, 0xce, 0x9e, 0x2c, 0x26, 0xfa, 0xbb, 0x09, 0xfd, 0x43, 0x5c, 0x16, 0xd9, 0x34,
-	0x21, 0x3f, 0x97, 0x44, 0x2a, 0x34, 0x80, 0x46, 0x36, 0xcf, 0xc3, 0x60, 0x14, 0x8c, 0x3b, 0x89,
-	0xfe, 0x44, 0x08, 0x36, 0xb1, 0x98, 0xc8, 0x70, 0x63, 0xd4, 0x18, 0x77, 0x12, 0xf3, 0x8d, 0x8e,
-	0xa0, 0x23, 0x88, 0x64, 0xa5, 0xc8, 0x88, 0x0c, 0x1b, 0xa3, 0x60, 0xdc, 0xdd, 0xdf, 0x8b, 0xff,
-	0x2d, 0x71, 0x17, 0xdf, 0x86, 0x8c, 0x13, 0xcf, 0x4b, 0x2e, 0x5d, 0xa0, 0x5b, 0xd0, 0x95, 0x2a,
-	0x67, 0xa5, 0x4a, 0x39, 0x56, 0xd3, 0x70, 0xd3, 0x44, 0x07, 0x6b, 0x3a, 0xc6, 0x6a, 0xea, 0x00,
-	0x44, 0x08, 0x0b, 0xd8, 0xaa, 0x00, 0x44, 0x08, 0x03, 0x18, 0x40, 0x83, 0x14, 0x8b, 0xb0, 0x69,
-	0x92, 0xd4, 0x9f, 0x3a, 0xef, 0x52, 0x12, 0x11, 0xb6, 0x0c, 0xd6, 0x7c, 0xa3, 0x9b, 0xd0, 0x56,
-	0x58, 0x9e, 0xa5, 0x39, 0x15, 0x61, 0xdb, 0xd8, 0x5b, 0xfa, 0xfc, 0x98, 0x0a, 0x74, 0x1b, 0x76,
-	0x7c, 0x3e, 0xe9, 0x8c, 0xce, 0xa9, 0x92, 0x61, 0x67, 0x14, 0x8c, 0xdb, 0xc9, 0xb6, 0x37, 0x1f,
-	0x1a, 0x2b, 0xda, 0x83, 0x77, 0x4f, 0xb1, 0xa4, 0x59, 0xca, 0x05, 0xcb, 0x88, 0x94, 0x69, 0x36,
-	0x11, 0xac, 0xe4, 0x21, 0x18, 0x34, 0x32, 0xff, 0x1d, 0xdb, 0xbf, 0x0e, 0xcc, 0x3f, 0xe8, 0x31,
-	0x34, 0xe7, 0xac, 0x2c, 0x94, 0x0c, 0xbb, 0xa3, 0xc6, 0xb8, 0xbb, 0x7f, 0xa7, 0xe6, 0x53, 0x3d,
-	0xd3, 0xa4, 0xc4, 0x71, 0xd1, 0xb7, 0xd0, 0xca, 0xc9, 0x82, 0xea, 0x17, 0xef, 0x19, 0x37, 0x9f,
-	0xd5, 0x74, 0xf3, 0xd8, 0xb0, 0x12, 0xcf, 0x46, 0x53, 0xb8, 0x5e, 0x10, 0x75, 0xce, 0xc4, 0x59,
-	0x4a, 0x25, 0x9b, 0x61, 0x45, 0x59, 0x11, 0xf6, 0x4d, 0x11, 0xbf, 0xa8, 0xe9, 0xf2, 0xc8, 0xf2,
-	0x9f, 0x7a, 0xfa, 0x09, 0x27, 0x59, 0x32, 0x28, 0xae, 0x58, 0x51, 0x04, 0xfd, 0x82, 0xa5, 0x9c,
-	0x2e, 0x98, 0x4a, 0x05, 0x63, 0x2a, 0xdc, 0x36, 0x6f, 0xd4, 0x2d, 0xd8, 0xb1, 0xb6, 0x25, 0x8c,
-	0x29, 0x34, 0x86, 0x41, 0x4e, 0x5e, 0xe2, 0x72, 0xa6, 0x52, 0x4e, 0xf3, 0x74, 0xce, 0x72, 0x12,
-	0xee, 0x98, 0xd2, 0x6c, 0x3b, 0xfb, 0x31, 0xcd, 0x9f, 0xb1, 0x9c, 0x2c, 0x23, 0x29, 0xcf, 0x2c,
-	0x72, 0xb0, 0x82, 0x7c, 0xca, 0x33, 0x83, 0xfc, 0x08, 0xfa, 0x19, 0x2f, 0x25, 0x51, 0xbe, 0x36,
-	0xd7, 0x0d, 0xac, 0x67, 0x8d, 0xae, 0x2a, 0x1f, 0x00, 0xe0, 0xd9, 0x8c, 0x9d, 0xa7, 0x19, 0xe6,
-	0x32, 0x44, 0xa6, 0x71, 0x3a, 0xc6, 0x72, 0x80, 0xb9, 0x44, 0x11, 0xf4, 0x32, 0xcc, 0xf1, 0x29,
-	0x9d, 0x51, 0x45, 0x89, 0x0c, 0xdf, 0x31, 0x80, 0x15, 0x5b, 0xf4, 0x13, 0x6c, 0xfb, 0xe9, 0x91,
-	0x9c, 0x15, 0x92, 0xa0, 0x23, 0x68, 0xb9, 0xb6, 0x30, 0x23, 0xd4, 0xdd, 0xbf, 0x17, 0xd7, 0x9b,
-	0xe7, 0xd8, 0xb5, 0xcc, 0x89, 0xc2, 0x8a, 0x24, 0xde, 0x49, 0xd4, 0x87, 0xee, 0x0b, 0x4c, 0x95,
-	0x9b, 0xce, 0xe8, 0x47, 0xe8, 0xd9, 0xe3, 0xff, 0x14, 0xee, 0x10, 0x76, 0x4e, 0xa6, 0xa5, 0xca,
-	0xd9, 0x79, 0xe1, 0x05, 0xe1, 0x06, 0x34, 0x25, 0x9d, 0x14, 0x78, 0xe6, 0x34, 0xc1, 0x9d, 0xd0,
-	0x87, 0xd0, 0x9b, 0x08, 0x9c, 0x91, 0x94, 0x13, 0x41, 0x59, 0x1e, 0x6e, 0x8c, 0x82, 0x71, 0x23,
-	0xe9, 0x1a, 0xdb, 0xb1, 0x31, 0x45, 0x08, 0x06, 0x97, 0xde, 0x6c, 0xc6, 0xd1, 0x14, 0x6e, 0x7c,
-	0xc7, 0x73, 0x1d, 0xb4, 0xd2, 0x01, 0x17, 0x68, 0x45, 0x53, 0x82, 0xff, 0xac, 0x29, 0xd1, 0x4d,
-	0x78, 0xef, 0x95, 0x48, 0x2e, 0x89, 0x01, 0x6c, 0x7f, 0x4f, 0x84, 0xa4, 0xcc, 0xdf, 0x32, 0xfa,
-	0x14, 0x76, 0x2a, 0x8b, 0x7b, 0xdb, 0x10, 0x5a, 0x0b, 0x6b, 0x72, 0x37, 0xf7, 0xc7, 0xe8, 0x13,
-	0xe8, 0xe9, 0x77, 0xab, 0x32, 0x1f, 0x42, 0x9b, 0x16, 0x8a, 0x88, 0x85, 0x7b, 0xa4, 0x46, 0x52,
-	0x9d, 0xa3, 0x17, 0xd0, 0x77, 0x58, 0xe7, 0xf6, 0x1b, 0xd8, 0x92, 0xda, 0xb0, 0xe6, 0x15, 0x9f,
-	0x63, 0x79, 0x66, 0x1d, 0x59, 0x7a, 0x74, 0x1b, 0xfa, 0x27, 0xa6, 0x12, 0xaf, 0x2f, 0xd4, 0x96,
-	0x2f, 0x94, 0xbe, 0xac, 0x07, 0xba, 0xeb, 0x9f, 0x41, 0xf7, 0xc9, 0x05, 0xc9, 0x3c, 0xf1, 0x01,
-	0xb4, 0x73, 0x82, 0xf3, 0x19, 0x2d, 0x88, 0x4b, 0x6a, 0x18, 0xdb, 0xe5, 0x12, 0xfb, 0xe5, 0x12,
-	0x3f, 0xf7, 0xcb, 0x25, 0xa9, 0xb0, 0x7e, 0x55, 0x6c, 0xbc, 0xba, 0x2a, 0x1a, 0x97, 0xab, 0x22,
-	0x3a, 0x80, 0x9e, 0x0d, 0xe6, 0xee, 0x7f, 0x03, 0x9a, 0xac, 0x54, 0xbc, 0x54, 0x26, 0x56, 0x2f,
-	0x71, 0x27, 0xf4, 0x3e, 0x74, 0xc8, 0x05, 0x55, 0x69, 0xa6, 0xc7, 0x7a, 0xc3, 0xdc, 0xa0, 0xad,
-	0x0d, 0x07, 0x2c, 0x27, 0xd1, 0xef, 0x01, 0xf4, 0x96, 0x3b, 0x56, 0xc7, 0xe6, 0x34, 0x77, 0x37,
-	0xd5, 0x9f, 0x6f, 0xe4, 0x2f, 0xbd, 0x4d, 0x63, 0xf9, 0x6d, 0x50, 0x0c, 0x9b, 0x7a, 0x6d, 0x9a,
-	0x85, 0xf3, 0xe6, 0x6b, 0x1b, 0xdc, 0xfe, 0x9f, 0x1d, 0x68, 0x3f, 0x71, 0x83, 0x84, 0x7e, 0x81,
-	0xa6, 0x9d, 0x7e, 0x74, 0xbf, 0xee, 0xd4, 0xad, 0xec, 0xda, 0xe1, 0x83, 0x75, 0x69, 0xae, 0x7e,
-	0xd7, 0x90, 0x84, 0x4d, 0xad, 0x03, 0xe8, 0x6e, 0x5d, 0x0f, 0x4b, 0x22, 0x32, 0xbc, 0xb7, 0x1e,
-	0xa9, 0x0a, 0xfa, 0x1b, 0xb4, 0xfd, 0x38, 0xa3, 0x87, 0x75, 0x7d, 0x5c, 0x91, 0x93, 0xe1, 0xe7,
-	0xeb, 0x13, 0xab, 0x04, 0xfe, 0x08, 0x60, 0xe7, 0xca, 0x48, 0xa3, 0x2f, 0xeb, 0xfa, 0x7b, 0xbd,
-	0xea, 0x0c, 0x1f, 0xbd, 0x35, 0xbf, 0x4a, 0xeb, 0x57, 0x68, 0x39, 0xed, 0x40, 0xb5, 0x2b, 0xba,
-	0x2a, 0x3f, 0xc3, 0x87, 0x6b, 0xf3, 0xaa, 0xe8, 0x17, 0xb0, 0x65, 0x74, 0x01, 0xd5, 0x2e, 0xeb,
-	0xb2, 0x76, 0x0d, 0xef, 0xaf, 0xc9, 0xf2, 0x71, 0xf7, 0x02, 0xdd, 0xff, 0x56, 0x58, 0xea, 0xf7,
-	0xff, 0x8a, 0x62, 0xd5, 0xef, 0xff, 0x2b, 0xfa, 0x65, 0xfa, 0x5f, 0x8f, 0x61, 0xfd, 0xfe, 0x5f,
-	0xd2, 0xbb, 0xfa, 0xfd, 0xbf, 0xac, 0x5b, 0xd1, 0x35, 0xf4, 0x57, 0x00, 0x7d, 0x6d, 0x3a, 0x51,
-	0x82, 0xe0, 0x39, 0x2d, 0x26, 0xe8, 0x51, 0x4d, 0xf1, 0xd6, 0x2c, 0x2b, 0xe0, 0x8e, 0xe9, 0x53,
-	0xf9, 0xea, 0xed, 0x1d, 0xf8, 0xb4, 0xc6, 0xc1, 0x5e, 0xf0, 0x75, 0xeb, 0x87, 0x2d, 0xab, 0x59,
-	0x4d, 0xf3, 0x73, 0xf7, 0x9f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x2e, 0x63, 0xc4, 0xd3, 0x74, 0x0c,
-	0x00, 0x00,
+	// 1252 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x56, 0x5b, 0x6f, 0x1b, 0x45,
+	0x14, 0x66, 0x63, 0xc7, 0x76, 0x8e, 0x2f, 0x71, 0x87, 0x92, 0x6e, 0x8d, 0x50, 0xcd, 0x22, 0xa8,
+	0x05, 0x65, 0x13, 0xa5, 0x37, 0x2e, 0x12, 0x45, 0x24, 0x05, 0x2a, 0xb5, 0x55, 0xb4, 0x29, 0x54,
+	0xe2, 0x81, 0x65, 0xba, 0x3b, 0xb1, 0x47, 0x59, 0xef, 0x6c, 0x67, 0x66, 0xd3, 0x54, 0x42, 0xe2,
+	0xa9, 0xff, 0x80, 0x07, 0x24, 0x7e, 0x27, 0xef, 0x68, 0x6e, 0x1b, 0xbb, 0x2d, 0xb0, 0x0e, 0xea,
+	0x93, 0x67, 0xbe, 0x3d, 0xdf, 0xb9, 0xcc, 0xb9, 0x19, 0xae, 0xa5, 0x9c, 0x9e, 0x10, 0x2e, 0xb6,
+	0xc5, 0x0c, 0x73, 0x92, 0x6e, 0x93, 0x53, 0x92, 0x94, 0x92, 0xf1, 0xed, 0x82, 0x33, 0xc9, 0xaa,
+	0x6b, 0xa8, 0xaf, 0xe8, 0xa3, 0x19, 0x16, 0x33, 0x9a, 0x30, 0x5e, 0x84, 0x39, 0x9b, 0xe3, 0x34,
+	0x2c, 0xb2, 0x72, 0x4a, 0x73, 0x11, 0x2e, 0xcb, 0x8d, 0xae, 0x4c, 0x19, 0x9b, 0x66, 0xc4, 0x28,
+	0x79, 0x52, 0x1e, 0x6d, 0x4b, 0x3a, 0x27, 0x42, 0xe2, 0x79, 0x61, 0x05, 0x02, 0x4b, 0xdc, 0x76,
+	0xe6, 0x8d, 0x39, 0x73, 0x33, 0x32, 0xc1, 0x5f, 0x6d, 0xe8, 0xdf, 0xc7, 0x65, 0x9e, 0xcc, 0x22,
+	0xf2, 0xb4, 0x24, 0x42, 0xa2, 0x21, 0x34, 0x92, 0x79, 0xea, 0x7b, 0x63, 0x6f, 0xb2, 0x11, 0xa9,
+	0x23, 0x42, 0xd0, 0xc4, 0x7c, 0x2a, 0xfc, 0xb5, 0x71, 0x63, 0xb2, 0x11, 0xe9, 0x33, 0x7a, 0x08,
+	0x1b, 0x9c, 0x08, 0x56, 0xf2, 0x84, 0x08, 0xbf, 0x31, 0xf6, 0x26, 0xdd, 0xdd, 0x9d, 0xf0, 0x9f,
+	0x1c, 0xb7, 0xf6, 0x8d, 0xc9, 0x30, 0x72, 0xbc, 0xe8, 0x4c, 0x05, 0xba, 0x02, 0x5d, 0x21, 0x53,
+	0x56, 0xca, 0xb8, 0xc0, 0x72, 0xe6, 0x37, 0xb5, 0x75, 0x30, 0xd0, 0x01, 0x96, 0x33, 0x2b, 0x40,
+	0x38, 0x37, 0x02, 0xeb, 0x95, 0x00, 0xe1, 0x5c, 0x0b, 0x0c, 0xa1, 0x41, 0xf2, 0x13, 0xbf, 0xa5,
+	0x9d, 0x54, 0x47, 0xe5, 0x77, 0x29, 0x08, 0xf7, 0xdb, 0x5a, 0x56, 0x9f, 0xd1, 0x65, 0xe8, 0x48,
+	0x2c, 0x8e, 0xe3, 0x94, 0x72, 0xbf, 0xa3, 0xf1, 0xb6, 0xba, 0xef, 0x53, 0x8e, 0xae, 0xc2, 0xa6,
+	0xf3, 0x27, 0xce, 0xe8, 0x9c, 0x4a, 0xe1, 0x6f, 0x8c, 0xbd, 0x49, 0x27, 0x1a, 0x38, 0xf8, 0xbe,
+	0x46, 0xd1, 0x0e, 0x5c, 0x7c, 0x82, 0x05, 0x4d, 0xe2, 0x82, 0xb3, 0x84, 0x08, 0x11, 0x27, 0x53,
+	0xce, 0xca, 0xc2, 0x07, 0x2d, 0x8d, 0xf4, 0xb7, 0x03, 0xf3, 0x69, 0x4f, 0x7f, 0x41, 0xfb, 0xd0,
+	0x9a, 0xb3, 0x32, 0x97, 0xc2, 0xef, 0x8e, 0x1b, 0x93, 0xee, 0xee, 0xb5, 0x9a, 0x4f, 0xf5, 0x40,
+	0x91, 0x22, 0xcb, 0x45, 0xdf, 0x41, 0x3b, 0x25, 0x27, 0x54, 0xbd, 0x78, 0x4f, 0xab, 0xf9, 0xb4,
+	0xa6, 0x9a, 0x7d, 0xcd, 0x8a, 0x1c, 0x1b, 0xcd, 0xe0, 0x42, 0x4e, 0xe4, 0x33, 0xc6, 0x8f, 0x63,
+	0x2a, 0x58, 0x86, 0x25, 0x65, 0xb9, 0xdf, 0xd7, 0x49, 0xfc, 0xb2, 0xa6, 0xca, 0x87, 0x86, 0x7f,
+	0xcf, 0xd1, 0x0f, 0x0b, 0x92, 0x44, 0xc3, 0xfc, 0x25, 0x14, 0x05, 0xd0, 0xcf, 0x59, 0x5c, 0xd0,
+	0x13, 0x26, 0x63, 0xce, 0x98, 0xf4, 0x07, 0xfa, 0x8d, 0xba, 0x39, 0x3b, 0x50, 0x58, 0xc4, 0x98,
+	0x44, 0x13, 0x18, 0xa6, 0xe4, 0x08, 0x97, 0x99, 0x8c, 0x0b, 0x9a, 0xc6, 0x73, 0x96, 0x12, 0x7f,
+	0x53, 0xa7, 0x66, 0x60, 0xf1, 0x03, 0x9a, 0x3e, 0x60, 0x29, 0x59, 0x94, 0xa4, 0x45, 0x62, 0x24,
+	0x87, 0x4b, 0x92, 0xf7, 0x8a, 0x44, 0x4b, 0x7e, 0x00, 0xfd, 0xa4, 0x28, 0x05, 0x91, 0x2e, 0x37,
+	0x17, 0xb4, 0x58, 0xcf, 0x80, 0x36, 0x2b, 0xef, 0x01, 0xe0, 0x2c, 0x63, 0xcf, 0xe2, 0x04, 0x17,
+	0xc2, 0x47, 0xba, 0x70, 0x36, 0x34, 0xb2, 0x87, 0x0b, 0x81, 0x02, 0xe8, 0x25, 0xb8, 0xc0, 0x4f,
+	0x68, 0x46, 0x25, 0x25, 0xc2, 0x7f, 0x5b, 0x0b, 0x2c, 0x61, 0xaa, 0x66, 0x04, 0x49, 0x12, 0x36,
+	0x2f, 0x54, 0x31, 0x1c, 0xd1, 0x8c, 0xf8, 0x17, 0xc7, 0xde, 0xa4, 0x17, 0x0d, 0x2c, 0x7c, 0x60,
+	0x50, 0xf4, 0x3d, 0xb4, 0xb9, 0x2d, 0xaa, 0x77, 0x74, 0xee, 0xc2, 0xb0, 0x5e, 0x9b, 0x87, 0x91,
+	0xa6, 0x45, 0x8e, 0x6e, 0xca, 0x14, 0xa7, 0x2c, 0xcf, 0x9e, 0xeb, 0x27, 0x3d, 0x12, 0xfe, 0x96,
+	0x2b, 0x53, 0x03, 0x47, 0x1a, 0x45, 0xef, 0xc2, 0x46, 0xc2, 0x69, 0x69, 0xfa, 0xe5, 0x92, 0x8e,
+	0xbf, 0xa3, 0x00, 0xd5, 0x2d, 0xc1, 0x2f, 0x30, 0x70, 0x6d, 0x2f, 0x0a, 0x96, 0x0b, 0x82, 0x1e,
+	0x42, 0xdb, 0xd6, 0xb3, 0xee, 0xfd, 0xee, 0xee, 0x8d, 0xba, 0x1e, 0xda, 0x5a, 0x3f, 0x94, 0x58,
+	0x92, 0xc8, 0x29, 0x09, 0xfa, 0xd0, 0x7d, 0x8c, 0xa9, 0xb4, 0x63, 0x25, 0xf8, 0x19, 0x7a, 0xe6,
+	0xfa, 0x86, 0xcc, 0xdd, 0x87, 0xcd, 0xc3, 0x59, 0x29, 0x53, 0xf6, 0x2c, 0x77, 0x93, 0x6c, 0x0b,
+	0x5a, 0x82, 0x4e, 0x73, 0x9c, 0xd9, 0x61, 0x66, 0x6f, 0xe8, 0x7d, 0xe8, 0x4d, 0x39, 0x4e, 0x48,
+	0x5c, 0x10, 0x4e, 0x59, 0xea, 0xaf, 0x8d, 0xbd, 0x49, 0x23, 0xea, 0x6a, 0xec, 0x40, 0x43, 0x01,
+	0x82, 0xe1, 0x99, 0x36, 0xe3, 0x71, 0x30, 0x83, 0xad, 0x1f, 0x8a, 0x54, 0x19, 0xad, 0x06, 0x98,
+	0x35, 0xb4, 0x34, 0x0c, 0xbd, 0xff, 0x3d, 0x0c, 0x83, 0xcb, 0x70, 0xe9, 0x15, 0x4b, 0xd6, 0x89,
+	0x21, 0x0c, 0x7e, 0x24, 0x5c, 0x50, 0xe6, 0xa2, 0x0c, 0x3e, 0x81, 0xcd, 0x0a, 0xb1, 0x6f, 0xeb,
+	0x43, 0xfb, 0xc4, 0x40, 0x36, 0x72, 0x77, 0x0d, 0x3e, 0x86, 0x9e, 0x7a, 0xb7, 0xca, 0xf3, 0x11,
+	0x74, 0x68, 0x2e, 0x09, 0x3f, 0xb1, 0x8f, 0xd4, 0x88, 0xaa, 0x7b, 0xf0, 0x18, 0xfa, 0x56, 0xd6,
+	0xaa, 0xfd, 0x16, 0xd6, 0x85, 0x02, 0x56, 0x0c, 0xf1, 0x11, 0x16, 0xc7, 0x46, 0x91, 0xa1, 0x07,
+	0x57, 0xa1, 0x7f, 0xa8, 0x33, 0xf1, 0xfa, 0x44, 0xad, 0xbb, 0x44, 0xa9, 0x60, 0x9d, 0xa0, 0x0d,
+	0xff, 0x18, 0xba, 0x77, 0x4f, 0x49, 0xe2, 0x88, 0xb7, 0xa0, 0x93, 0x12, 0x9c, 0x66, 0x34, 0x27,
+	0xd6, 0xa9, 0x51, 0x68, 0xb6, 0x62, 0xe8, 0xb6, 0x62, 0xf8, 0xc8, 0x6d, 0xc5, 0xa8, 0x92, 0x75,
+	0x3b, 0x6e, 0xed, 0xd5, 0x1d, 0xd7, 0x38, 0xdb, 0x71, 0xc1, 0x1e, 0xf4, 0x8c, 0x31, 0x1b, 0xff,
+	0x16, 0xb4, 0x58, 0x29, 0x8b, 0x52, 0x6a, 0x5b, 0xbd, 0xc8, 0xde, 0x54, 0xa3, 0x91, 0x53, 0x2a,
+	0xe3, 0x44, 0xcd, 0xa3, 0x35, 0x1d, 0x41, 0x47, 0x01, 0x7b, 0x2c, 0x25, 0xc1, 0x87, 0x70, 0x61,
+	0x6f, 0x46, 0x92, 0xe3, 0x82, 0xd1, 0x5c, 0x2e, 0xec, 0x58, 0xb5, 0x80, 0xec, 0x8e, 0x4d, 0x29,
+	0x0f, 0x2e, 0x02, 0x5a, 0x14, 0xb3, 0xe1, 0x3e, 0x85, 0x41, 0x44, 0x84, 0x64, 0x9c, 0x38, 0xe6,
+	0x03, 0x68, 0x65, 0xba, 0x6f, 0x6d, 0xbc, 0x37, 0xeb, 0x76, 0xcd, 0xd2, 0x92, 0x8f, 0xac, 0x12,
+	0xe7, 0xc8, 0xda, 0x99, 0x23, 0x18, 0x36, 0x2b, 0x93, 0x6f, 0xa8, 0x55, 0x5f, 0x78, 0xd0, 0x5b,
+	0xfc, 0xa2, 0xbc, 0x28, 0x68, 0x6a, 0x93, 0xaf, 0x8e, 0xff, 0xfa, 0xa4, 0x0b, 0xe5, 0xd2, 0x58,
+	0x2c, 0x17, 0x14, 0x42, 0x53, 0xfd, 0x05, 0xf2, 0x9b, 0xff, 0x59, 0x09, 0x5a, 0x2e, 0xd8, 0x87,
+	0x96, 0x19, 0xae, 0x2a, 0xfb, 0xf2, 0x79, 0x41, 0x6c, 0x42, 0xf4, 0x59, 0x61, 0x82, 0x1d, 0x49,
+	0x6d, 0xbd, 0x19, 0xe9, 0xb3, 0xc2, 0x66, 0x98, 0xa7, 0xda, 0x6e, 0x33, 0xd2, 0xe7, 0xdd, 0x3f,
+	0xbb, 0xd0, 0xb9, 0x6b, 0xc3, 0x46, 0xcf, 0xa1, 0x65, 0x1e, 0x1a, 0x9d, 0x2f, 0x31, 0xa3, 0x5b,
+	0xab, 0xd2, 0x6c, 0xa5, 0xbc, 0x85, 0x04, 0x34, 0xd5, 0x80, 0x45, 0xd7, 0xeb, 0x6a, 0x58, 0x98,
+	0xce, 0xa3, 0x1b, 0xab, 0x91, 0x2a, 0xa3, 0xbf, 0x41, 0xc7, 0xcd, 0x49, 0x74, 0xbb, 0xae, 0x8e,
+	0x97, 0xe6, 0xf4, 0xe8, 0xb3, 0xd5, 0x89, 0x95, 0x03, 0xbf, 0x7b, 0xb0, 0xf9, 0xd2, 0xac, 0x44,
+	0x5f, 0xd5, 0xd5, 0xf7, 0xfa, 0x71, 0x3e, 0xba, 0x73, 0x6e, 0x7e, 0xe5, 0xd6, 0xaf, 0xd0, 0xb6,
+	0x43, 0x19, 0xd5, 0xce, 0xe8, 0xf2, 0x5c, 0x1f, 0xdd, 0x5e, 0x99, 0x57, 0x59, 0x3f, 0x85, 0x75,
+	0x3d, 0x70, 0x51, 0xed, 0xb4, 0x2e, 0x2e, 0x85, 0xd1, 0xcd, 0x15, 0x59, 0xce, 0xee, 0x8e, 0xa7,
+	0xea, 0xdf, 0x4c, 0xec, 0xfa, 0xf5, 0xbf, 0xb4, 0x0a, 0x46, 0xb7, 0x56, 0xa5, 0x2d, 0xd6, 0xbf,
+	0x6a, 0xc3, 0xfa, 0xf5, 0xbf, 0xb0, 0x48, 0x46, 0x37, 0x56, 0x23, 0x55, 0x46, 0xff, 0xf0, 0xa0,
+	0xaf, 0xa0, 0x43, 0xc9, 0x09, 0x9e, 0xd3, 0x7c, 0x8a, 0xee, 0xd4, 0xdc, 0x8a, 0x8a, 0x65, 0x36,
+	0xa3, 0x65, 0x3a, 0x57, 0xbe, 0x3e, 0xbf, 0x02, 0xe7, 0xd6, 0xc4, 0xdb, 0xf1, 0xd0, 0x0b, 0x0f,
+	0xe0, 0x6c, 0xa5, 0xa0, 0xcf, 0xeb, 0x46, 0xf8, 0xca, 0xb6, 0x1a, 0x7d, 0x71, 0x1e, 0xea, 0x62,
+	0x2b, 0xd8, 0x85, 0x52, 0xbf, 0x15, 0x96, 0x97, 0xde, 0xe8, 0xf6, 0xca, 0x3c, 0x67, 0xfd, 0x9b,
+	0xf6, 0x4f, 0xeb, 0x66, 0xfe, 0xb7, 0xf4, 0xcf, 0xf5, 0xbf, 0x07, 0x00, 0x7d, 0xb2, 0xe2, 0xba,
+	0x8c, 0x0f, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -966,6 +1226,8 @@ type ExecutorClient interface {
 	Exec(ctx context.Context, in *ExecRequest, opts ...grpc.CallOption) (*ExecResponse, error)
 	// buf:lint:ignore RPC_REQUEST_RESPONSE_UNIQUE
 	ExecStreaming(ctx context.Context, opts ...grpc.CallOption) (Executor_ExecStreamingClient, error)
+	Checkpoint(ctx context.Context, in *CheckpointRequest, opts ...grpc.CallOption) (*CheckpointResponse, error)
+	Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*RestoreResponse, error)
 }
 
 type executorClient struct {
@@ -1102,6 +1364,24 @@ func (x *executorExecStreamingClient) Recv() (*proto1.ExecTaskStreamingResponse,
 	return m, nil
 }
 
+func (c *executorClient) Checkpoint(ctx context.Context, in *CheckpointRequest, opts ...grpc.CallOption) (*CheckpointResponse, error) {
+	out := new(CheckpointResponse)
+	err := c.cc.Invoke(ctx, "/hashicorp.nomad.plugins.executor.proto.Executor/Checkpoint", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *executorClient) Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*RestoreResponse, error) {
+	out := new(RestoreResponse)
+	err := c.cc.Invoke(ctx, "/hashicorp.nomad.plugins.executor.proto.Executor/Restore", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ExecutorServer is the server API for Executor service.
 type ExecutorServer interface {
 	Launch(context.Context, *LaunchRequest) (*LaunchResponse, error)
@@ -1114,6 +1394,8 @@ type ExecutorServer interface {
 	Exec(context.Context, *ExecRequest) (*ExecResponse, error)
 	// buf:lint:ignore RPC_REQUEST_RESPONSE_UNIQUE
 	ExecStreaming(Executor_ExecStreamingServer) error
+	Checkpoint(context.Context, *CheckpointRequest) (*CheckpointResponse, error)
+	Restore(context.Context, *RestoreRequest) (*RestoreResponse, error)
 }
 
 // UnimplementedExecutorServer can be embedded to have forward compatible implementations.
@@ -1147,6 +1429,12 @@ func (*UnimplementedExecutorServer) Exec(ctx context.Context, req *ExecRequest)
 func (*UnimplementedExecutorServer) ExecStreaming(srv Executor_ExecStreamingServer) error {
 	return status.Errorf(codes.Unimplemented, "method ExecStreaming not implemented")
 }
+func (*UnimplementedExecutorServer) Checkpoint(ctx context.Context, req *CheckpointRequest) (*CheckpointResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Checkpoint not implemented")
+}
+func (*UnimplementedExecutorServer) Restore(ctx context.Context, req *RestoreRequest) (*RestoreResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Restore not implemented")
+}
 
 func RegisterExecutorServer(s *grpc.Server, srv ExecutorServer) {
 	s.RegisterService(&_Executor_serviceDesc, srv)
@@ -1325,6 +1613,42 @@ func (x *executorExecStreamingServer) Recv() (*proto1.ExecTaskStreamingRequest,
 	return m, nil
 }
 
+func _Executor_Checkpoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckpointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExecutorServer).Checkpoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hashicorp.nomad.plugins.executor.proto.Executor/Checkpoint",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExecutorServer).Checkpoint(ctx, req.(*CheckpointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Executor_Restore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExecutorServer).Restore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hashicorp.nomad.plugins.executor.proto.Executor/Restore",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExecutorServer).Restore(ctx, req.(*RestoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Executor_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "hashicorp.nomad.plugins.executor.proto.Executor",
 	HandlerType: (*ExecutorServer)(nil),
@@ -1357,6 +1681,14 @@ var _Executor_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Exec",
 			Handler:    _Executor_Exec_Handler,
 		},
+		{
+			MethodName: "Checkpoint",
+			Handler:    _Executor_Checkpoint_Handler,
+		},
+		{
+			MethodName: "Restore",
+			Handler:    _Executor_Restore_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
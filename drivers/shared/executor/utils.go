@@ -3,8 +3,12 @@ package executor
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
 	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/golang/protobuf/ptypes"
 	hclog "github.com/hashicorp/go-hclog"
@@ -136,3 +140,81 @@ func IsolationMode(plugin, task string) string {
 	}
 	return plugin
 }
+
+// ParseRlimits parses the resource limits of the task driver configuration,
+// mapping the name of each resource to either "soft:hard" or a single value
+// used as both the soft and hard limits. Limits may be "unlimited".
+func ParseRlimits(raw map[string]string) ([]*Rlimit, error) {
+	rlimits := make([]*Rlimit, 0, len(raw))
+	for name, value := range raw {
+		switch name {
+		case RlimitCore, RlimitNoFile, RlimitNProc:
+		default:
+			return nil, fmt.Errorf("unsupported rlimit %q, must be one of %q, %q or %q", name, RlimitCore, RlimitNoFile, RlimitNProc)
+		}
+
+		softRaw, hardRaw := value, value
+		if idx := strings.Index(value, ":"); idx >= 0 {
+			softRaw, hardRaw = value[:idx], value[idx+1:]
+		}
+		soft, err := parseRlimitValue(softRaw)
+		if err != nil {
+			return nil, fmt.Errorf("malformed soft rlimit %s: %q: %v", name, value, err)
+		}
+		hard, err := parseRlimitValue(hardRaw)
+		if err != nil {
+			return nil, fmt.Errorf("malformed hard rlimit %s: %q: %v", name, value, err)
+		}
+		if soft > hard {
+			return nil, fmt.Errorf("soft rlimit %s must not exceed the hard limit: %q", name, value)
+		}
+
+		rlimits = append(rlimits, &Rlimit{
+			Type: name,
+			Soft: soft,
+			Hard: hard,
+		})
+	}
+
+	sort.Slice(rlimits, func(i, j int) bool {
+		return rlimits[i].Type < rlimits[j].Type
+	})
+	return rlimits, nil
+}
+
+func parseRlimitValue(value string) (uint64, error) {
+	if value == RlimitUnlimited {
+		return math.MaxUint64, nil
+	}
+	return strconv.ParseUint(value, 10, 64)
+}
+
+func rlimitsToProto(rlimits []*Rlimit) []*proto.Rlimit {
+	if len(rlimits) == 0 {
+		return nil
+	}
+	pb := make([]*proto.Rlimit, len(rlimits))
+	for i, r := range rlimits {
+		pb[i] = &proto.Rlimit{
+			Type: r.Type,
+			Soft: r.Soft,
+			Hard: r.Hard,
+		}
+	}
+	return pb
+}
+
+func rlimitsFromProto(pb []*proto.Rlimit) []*Rlimit {
+	if len(pb) == 0 {
+		return nil
+	}
+	rlimits := make([]*Rlimit, len(pb))
+	for i, r := range pb {
+		rlimits[i] = &Rlimit{
+			Type: r.Type,
+			Soft: r.Soft,
+			Hard: r.Hard,
+		}
+	}
+	return rlimits
+}
@@ -1,6 +1,7 @@
 package executor
 
 import (
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -26,3 +27,32 @@ func TestUtils_IsolationMode(t *testing.T) {
 		require.Equal(t, tc.exp, result)
 	}
 }
+
+func TestUtils_ParseRlimits(t *testing.T) {
+	rlimits, err := ParseRlimits(map[string]string{
+		"nproc":  "256",
+		"nofile": "1024:4096",
+		"core":   "0:unlimited",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []*Rlimit{
+		{Type: RlimitCore, Soft: 0, Hard: math.MaxUint64},
+		{Type: RlimitNoFile, Soft: 1024, Hard: 4096},
+		{Type: RlimitNProc, Soft: 256, Hard: 256},
+	}, rlimits)
+
+	// The rlimits survive the round trip through the executor RPCs
+	require.Equal(t, rlimits, rlimitsFromProto(rlimitsToProto(rlimits)))
+
+	for _, raw := range []map[string]string{
+		{"stack": "1024"},
+		{"nofile": ""},
+		{"nofile": "1024:"},
+		{"nofile": "-1"},
+		{"nofile": "4096:1024"},
+		{"nproc": "unlimited:256"},
+	} {
+		_, err := ParseRlimits(raw)
+		require.Error(t, err, "expected error for %v", raw)
+	}
+}
@@ -0,0 +1,122 @@
+package seccomp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+const (
+	// ProfileDefault is the name of the default-deny profile shipped with
+	// Nomad.
+	ProfileDefault = "default"
+
+	// ProfileUnconfined is the name of the profile disabling seccomp
+	// filtering.
+	ProfileUnconfined = "unconfined"
+
+	// HCLSpecLiteral is the default list of profiles tasks are allowed to
+	// use, expressed as a literal HCL string for use in HCL config parsing.
+	HCLSpecLiteral = `["default","unconfined"]`
+)
+
+// ValidateProfile returns an error if the profile is neither one of the
+// profiles shipped with Nomad nor the absolute path of a custom profile.
+func ValidateProfile(profile string) error {
+	switch profile {
+	case ProfileDefault, ProfileUnconfined:
+		return nil
+	}
+	if !filepath.IsAbs(profile) {
+		return fmt.Errorf("seccomp profile must be %q, %q or an absolute path, got %q", ProfileDefault, ProfileUnconfined, profile)
+	}
+	return nil
+}
+
+// ValidateSupported returns an error if the profile filters syscalls but
+// Nomad was built without seccomp support.
+func ValidateSupported(profile string) error {
+	switch profile {
+	case "", ProfileUnconfined:
+		return nil
+	}
+	if !Supported() {
+		return fmt.Errorf("seccomp profile %q configured but Nomad was built without seccomp support", profile)
+	}
+	return nil
+}
+
+// Select returns the profile of a task, as determined from the plugin
+// configuration and the task driver configuration. The profile set by the
+// task takes precedence but must be one of the allowed profiles.
+func Select(plugin string, allowed []string, task string) (string, error) {
+	if task == "" {
+		return plugin, nil
+	}
+	for _, profile := range allowed {
+		if profile == task {
+			return task, nil
+		}
+	}
+	return "", fmt.Errorf("seccomp profile %q is not allowed, allowed profiles: %s", task, strings.Join(allowed, ", "))
+}
+
+// Load returns the JSON encoded OCI seccomp configuration of the profile,
+// which is either one of the profiles shipped with Nomad or the path to a
+// custom profile in the OCI format. Unconfined profiles return nil.
+func Load(profile string) ([]byte, error) {
+	switch profile {
+	case "", ProfileUnconfined:
+		return nil, nil
+	case ProfileDefault:
+		return json.Marshal(DefaultProfile())
+	}
+
+	data, err := ioutil.ReadFile(profile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read seccomp profile %q: %v", profile, err)
+	}
+	return Parse(data)
+}
+
+// Parse validates a JSON encoded OCI seccomp configuration and returns it in
+// its compact form.
+func Parse(data []byte) ([]byte, error) {
+	var config specs.LinuxSeccomp
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&config); err != nil {
+		return nil, fmt.Errorf("failed to parse seccomp profile: %v", err)
+	}
+
+	if config.DefaultAction == "" {
+		return nil, fmt.Errorf("seccomp profile must set a default action")
+	}
+	if !validAction(config.DefaultAction) {
+		return nil, fmt.Errorf("seccomp profile has unknown default action %q", config.DefaultAction)
+	}
+	for _, syscall := range config.Syscalls {
+		if len(syscall.Names) == 0 {
+			return nil, fmt.Errorf("seccomp profile has a syscall rule without names")
+		}
+		if !validAction(syscall.Action) {
+			return nil, fmt.Errorf("seccomp profile has unknown action %q for syscalls %s", syscall.Action, strings.Join(syscall.Names, ", "))
+		}
+	}
+
+	return json.Marshal(&config)
+}
+
+// validAction returns whether the action is supported by libcontainer.
+func validAction(action specs.LinuxSeccompAction) bool {
+	switch action {
+	case specs.ActKill, specs.ActTrap, specs.ActErrno, specs.ActTrace, specs.ActAllow, specs.ActLog:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package seccomp
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeccomp_ValidateProfile(t *testing.T) {
+	ci.Parallel(t)
+
+	require.NoError(t, ValidateProfile(ProfileDefault))
+	require.NoError(t, ValidateProfile(ProfileUnconfined))
+	require.NoError(t, ValidateProfile("/etc/nomad/seccomp.json"))
+	require.Error(t, ValidateProfile(""))
+	require.Error(t, ValidateProfile("seccomp.json"))
+}
+
+func TestSeccomp_ValidateSupported(t *testing.T) {
+	ci.Parallel(t)
+
+	require.NoError(t, ValidateSupported(""))
+	require.NoError(t, ValidateSupported(ProfileUnconfined))
+
+	err := ValidateSupported(ProfileDefault)
+	if Supported() {
+		require.NoError(t, err)
+	} else {
+		require.EqualError(t, err, `seccomp profile "default" configured but Nomad was built without seccomp support`)
+	}
+}
+
+func TestSeccomp_Select(t *testing.T) {
+	ci.Parallel(t)
+
+	allowed := []string{ProfileDefault, "/etc/nomad/seccomp.json"}
+
+	// The plugin profile applies to tasks which do not set one
+	profile, err := Select(ProfileUnconfined, allowed, "")
+	require.NoError(t, err)
+	require.Equal(t, ProfileUnconfined, profile)
+
+	profile, err = Select(ProfileUnconfined, allowed, "/etc/nomad/seccomp.json")
+	require.NoError(t, err)
+	require.Equal(t, "/etc/nomad/seccomp.json", profile)
+
+	// Tasks cannot use profiles which are not allowed
+	_, err = Select(ProfileDefault, allowed, ProfileUnconfined)
+	require.EqualError(t, err, `seccomp profile "unconfined" is not allowed, allowed profiles: default, /etc/nomad/seccomp.json`)
+}
+
+func TestSeccomp_Load(t *testing.T) {
+	ci.Parallel(t)
+
+	data, err := Load(ProfileUnconfined)
+	require.NoError(t, err)
+	require.Nil(t, data)
+
+	data, err = Load(ProfileDefault)
+	require.NoError(t, err)
+	var profile specs.LinuxSeccomp
+	require.NoError(t, json.Unmarshal(data, &profile))
+	require.Equal(t, specs.ActErrno, profile.DefaultAction)
+	require.Contains(t, profile.Syscalls[0].Names, "read")
+	require.NotContains(t, profile.Syscalls[0].Names, "mount")
+	require.NotContains(t, profile.Syscalls[0].Names, "ptrace")
+
+	path := filepath.Join(t.TempDir(), "seccomp.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`{
+  "defaultAction": "SCMP_ACT_ALLOW",
+  "syscalls": [
+    {"names": ["mkdir"], "action": "SCMP_ACT_ERRNO"}
+  ]
+}`), 0644))
+	data, err = Load(path)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"defaultAction":"SCMP_ACT_ALLOW","syscalls":[{"names":["mkdir"],"action":"SCMP_ACT_ERRNO"}]}`, string(data))
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing.json"))
+	require.Error(t, err)
+}
+
+func TestSeccomp_Parse_Invalid(t *testing.T) {
+	ci.Parallel(t)
+
+	for _, tc := range []struct {
+		name    string
+		profile string
+		err     string
+	}{
+		{
+			name:    "malformed",
+			profile: `{"defaultAction": `,
+			err:     "failed to parse seccomp profile",
+		},
+		{
+			name:    "docker format",
+			profile: `{"defaultAction": "SCMP_ACT_ERRNO", "archMap": []}`,
+			err:     "unknown field",
+		},
+		{
+			name:    "no default action",
+			profile: `{"syscalls": [{"names": ["read"], "action": "SCMP_ACT_ALLOW"}]}`,
+			err:     "must set a default action",
+		},
+		{
+			name:    "unknown default action",
+			profile: `{"defaultAction": "SCMP_ACT_NOTIFY"}`,
+			err:     "unknown default action",
+		},
+		{
+			name:    "unknown action",
+			profile: `{"defaultAction": "SCMP_ACT_ERRNO", "syscalls": [{"names": ["read"], "action": "ALLOW"}]}`,
+			err:     "unknown action",
+		},
+		{
+			name:    "no names",
+			profile: `{"defaultAction": "SCMP_ACT_ERRNO", "syscalls": [{"action": "SCMP_ACT_ALLOW"}]}`,
+			err:     "without names",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse([]byte(tc.profile))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
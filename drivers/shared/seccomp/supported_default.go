@@ -0,0 +1,9 @@
+//go:build !linux
+
+package seccomp
+
+// Supported returns whether Nomad was built with seccomp support, which is
+// only available on Linux.
+func Supported() bool {
+	return false
+}
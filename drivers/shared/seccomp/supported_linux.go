@@ -0,0 +1,14 @@
+//go:build linux
+
+package seccomp
+
+import (
+	lseccomp "github.com/opencontainers/runc/libcontainer/seccomp"
+)
+
+// Supported returns whether Nomad was built with seccomp support, which
+// requires the seccomp build tag and cgo.
+func Supported() bool {
+	major, _, _ := lseccomp.Version()
+	return major != 0
+}
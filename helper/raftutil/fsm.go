@@ -52,7 +52,7 @@ func NewFSM(p string) (*FSMHelper, error) {
 		return nil, fmt.Errorf("failed to open snapshot dir: %v", err)
 	}
 
-	fsm, err := dummyFSM(logger)
+	fsm, err := dummyFSM(logger, "default")
 	if err != nil {
 		store.Close()
 		return nil, err
@@ -71,7 +71,7 @@ func NewFSM(p string) (*FSMHelper, error) {
 	}, nil
 }
 
-func dummyFSM(logger hclog.Logger) (nomadFSM, error) {
+func dummyFSM(logger hclog.Logger, region string) (nomadFSM, error) {
 	// use dummy non-enabled FSM dependencies
 	periodicDispatch := nomad.NewPeriodicDispatch(logger, nil)
 	blockedEvals := nomad.NewBlockedEvals(nil, logger)
@@ -85,7 +85,7 @@ func dummyFSM(logger hclog.Logger) (nomadFSM, error) {
 		Periodic:   periodicDispatch,
 		Blocked:    blockedEvals,
 		Logger:     logger,
-		Region:     "default",
+		Region:     region,
 	}
 
 	return nomad.NewFSM(fsmConfig)
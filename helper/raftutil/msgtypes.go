@@ -54,6 +54,19 @@ var msgTypeNames = map[structs.MessageType]string{
 	structs.ServiceRegistrationUpsertRequestType:         "ServiceRegistrationUpsertRequestType",
 	structs.ServiceRegistrationDeleteByIDRequestType:     "ServiceRegistrationDeleteByIDRequestType",
 	structs.ServiceRegistrationDeleteByNodeIDRequestType: "ServiceRegistrationDeleteByNodeIDRequestType",
+	structs.VarApplyStateRequestType:                     "VarApplyStateRequestType",
+	structs.RootKeyMetaUpsertRequestType:                 "RootKeyMetaUpsertRequestType",
+	structs.ACLRolesUpsertRequestType:                    "ACLRolesUpsertRequestType",
+	structs.ACLRolesDeleteByIDRequestType:                "ACLRolesDeleteByIDRequestType",
+	structs.ACLAuthMethodsUpsertRequestType:              "ACLAuthMethodsUpsertRequestType",
+	structs.ACLAuthMethodsDeleteRequestType:              "ACLAuthMethodsDeleteRequestType",
+	structs.ACLBindingRulesUpsertRequestType:             "ACLBindingRulesUpsertRequestType",
+	structs.ACLBindingRulesDeleteRequestType:             "ACLBindingRulesDeleteRequestType",
+	structs.NodePoolUpsertRequestType:                    "NodePoolUpsertRequestType",
+	structs.NodePoolDeleteRequestType:                    "NodePoolDeleteRequestType",
+	structs.NodeUpdateUtilizationRequestType:             "NodeUpdateUtilizationRequestType",
 	structs.NamespaceUpsertRequestType:                   "NamespaceUpsertRequestType",
 	structs.NamespaceDeleteRequestType:                   "NamespaceDeleteRequestType",
+	structs.QuotaSpecUpsertRequestType:                   "QuotaSpecUpsertRequestType",
+	structs.QuotaSpecDeleteRequestType:                   "QuotaSpecDeleteRequestType",
 }
@@ -13,9 +13,16 @@ import (
 )
 
 func RestoreFromArchive(archive io.Reader) (*state.StateStore, *raft.SnapshotMeta, error) {
+	return RestoreFromArchiveInRegion(archive, "default")
+}
+
+// RestoreFromArchiveInRegion restores the snapshot archive into a state
+// store configured for the region, as the region scoped objects such as
+// quota limits are looked up by the region of the state store.
+func RestoreFromArchiveInRegion(archive io.Reader, region string) (*state.StateStore, *raft.SnapshotMeta, error) {
 	logger := hclog.L()
 
-	fsm, err := dummyFSM(logger)
+	fsm, err := dummyFSM(logger, region)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create FSM: %w", err)
 	}
@@ -305,6 +305,7 @@ func parseSpread(result *[]*api.Spread, list *ast.ObjectList) error {
 			"attribute",
 			"weight",
 			"target",
+			"max_skew",
 		}
 		if err := checkHCLKeys(o.Val, valid); err != nil {
 			return err
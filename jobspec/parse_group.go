@@ -58,6 +58,10 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 			"scaling",
 			"stop_after_client_disconnect",
 			"max_client_disconnect",
+			"placement",
+			"preemption",
+			"depends_on",
+			"depends_on_condition",
 		}
 		if err := checkHCLKeys(listVal, valid); err != nil {
 			return multierror.Prefix(err, fmt.Sprintf("'%s' ->", n))
@@ -83,6 +87,7 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 		delete(m, "service")
 		delete(m, "volume")
 		delete(m, "scaling")
+		delete(m, "preemption")
 
 		// Build the group with the basic decode
 		var g api.TaskGroup
@@ -172,6 +177,13 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 			}
 		}
 
+		// If we have a preemption configuration, then parse that
+		if o := listVal.Filter("preemption"); len(o.Items) > 0 {
+			if err := parsePreemption(&g.Preemption, o); err != nil {
+				return multierror.Prefix(err, "preemption ->")
+			}
+		}
+
 		// Parse out meta fields. These are in HCL as a list so we need
 		// to iterate over them and merge them.
 		if metaO := listVal.Filter("meta"); len(metaO.Items) > 0 {
@@ -302,6 +314,38 @@ func parseEphemeralDisk(result **api.EphemeralDisk, list *ast.ObjectList) error
 	return nil
 }
 
+func parsePreemption(result **api.PreemptionPolicy, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'preemption' block allowed")
+	}
+
+	// Get our preemption object
+	obj := list.Items[0]
+
+	// Check for invalid keys
+	valid := []string{
+		"preemptible",
+		"max_disruption",
+	}
+	if err := checkHCLKeys(obj.Val, valid); err != nil {
+		return err
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, obj.Val); err != nil {
+		return err
+	}
+
+	var preemption api.PreemptionPolicy
+	if err := mapstructure.WeakDecode(m, &preemption); err != nil {
+		return err
+	}
+	*result = &preemption
+
+	return nil
+}
+
 func parseRestartPolicy(final **api.RestartPolicy, list *ast.ObjectList) error {
 	list = list.Elem()
 	if len(list.Items) > 1 {
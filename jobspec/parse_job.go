@@ -69,10 +69,12 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 		"migrate",
 		"name",
 		"namespace",
+		"node_pool",
 		"parameterized",
 		"periodic",
 		"priority",
 		"region",
+		"scheduler",
 		"reschedule",
 		"task",
 		"type",
@@ -41,6 +41,7 @@ var (
 		"kind",
 		"volume_mount",
 		"csi_plugin",
+		"identity",
 	)
 
 	sidecarTaskKeys = append(commonTaskKeys,
@@ -112,6 +113,7 @@ func parseTask(item *ast.ObjectItem, keys []string) (*api.Task, error) {
 	delete(m, "vault")
 	delete(m, "volume_mount")
 	delete(m, "csi_plugin")
+	delete(m, "identity")
 	delete(m, "scaling")
 
 	// Build the task
@@ -178,6 +180,29 @@ func parseTask(item *ast.ObjectItem, keys []string) (*api.Task, error) {
 		t.CSIPluginConfig = &cfg
 	}
 
+	if o := listVal.Filter("identity"); len(o.Items) > 0 {
+		if len(o.Items) != 1 {
+			return nil, fmt.Errorf("identity -> Expected single stanza, got %d", len(o.Items))
+		}
+		i := o.Elem().Items[0]
+
+		if err := checkHCLKeys(i.Val, []string{"env", "file"}); err != nil {
+			return nil, multierror.Prefix(err, "identity ->")
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, i.Val); err != nil {
+			return nil, err
+		}
+
+		var identity api.WorkloadIdentity
+		if err := mapstructure.WeakDecode(m, &identity); err != nil {
+			return nil, err
+		}
+
+		t.Identity = &identity
+	}
+
 	// If we have config, then parse that
 	if o := listVal.Filter("config"); len(o.Items) > 0 {
 		for _, o := range o.Elem().Items {
@@ -256,6 +281,10 @@ func parseTask(item *ast.ObjectItem, keys []string) (*api.Task, error) {
 		valid := []string{
 			"max_files",
 			"max_file_size",
+			"max_file_age",
+			"compress",
+			"max_alloc_size",
+			"sink",
 		}
 		if err := checkHCLKeys(logsBlock.Val, valid); err != nil {
 			return nil, multierror.Prefix(err, "logs ->")
@@ -264,12 +293,30 @@ func parseTask(item *ast.ObjectItem, keys []string) (*api.Task, error) {
 		if err := hcl.DecodeObject(&m, logsBlock.Val); err != nil {
 			return nil, err
 		}
+		delete(m, "sink")
 
 		var log api.LogConfig
-		if err := mapstructure.WeakDecode(m, &log); err != nil {
+		dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+			WeaklyTypedInput: true,
+			Result:           &log,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := dec.Decode(m); err != nil {
 			return nil, err
 		}
 
+		// Parse the sinks
+		if ot, ok := logsBlock.Val.(*ast.ObjectType); ok {
+			if o := ot.List.Filter("sink"); len(o.Items) > 0 {
+				if err := parseLogSinks(&log.Sinks, o); err != nil {
+					return nil, multierror.Prefix(err, "logs ->")
+				}
+			}
+		}
+
 		t.LogConfig = &log
 	}
 
@@ -560,6 +607,7 @@ func parseResources(result *api.Resources, list *ast.ObjectList) error {
 		"network",
 		"device",
 		"cores",
+		"numa",
 	}
 	if err := checkHCLKeys(listVal, valid); err != nil {
 		return multierror.Prefix(err, "resources ->")
@@ -571,6 +619,7 @@ func parseResources(result *api.Resources, list *ast.ObjectList) error {
 	}
 	delete(m, "network")
 	delete(m, "device")
+	delete(m, "numa")
 
 	if err := mapstructure.WeakDecode(m, result); err != nil {
 		return err
@@ -649,6 +698,31 @@ func parseResources(result *api.Resources, list *ast.ObjectList) error {
 		}
 	}
 
+	// Parse the NUMA placement
+	if o := listVal.Filter("numa"); len(o.Items) > 0 {
+		if len(o.Items) > 1 {
+			return fmt.Errorf("only one 'numa' block allowed per resources block")
+		}
+		no := o.Items[0]
+		valid := []string{
+			"affinity",
+		}
+		if err := checkHCLKeys(no.Val, valid); err != nil {
+			return multierror.Prefix(err, "resources, numa ->")
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, no.Val); err != nil {
+			return err
+		}
+
+		var numa api.NUMAResource
+		if err := mapstructure.WeakDecode(m, &numa); err != nil {
+			return err
+		}
+		result.NUMA = &numa
+	}
+
 	return nil
 }
 
@@ -689,3 +763,36 @@ func parseVolumeMounts(out *[]*api.VolumeMount, list *ast.ObjectList) error {
 	*out = mounts
 	return nil
 }
+
+func parseLogSinks(result *[]*api.LogSink, list *ast.ObjectList) error {
+	for _, item := range list.Items {
+		if len(item.Keys) != 1 {
+			return fmt.Errorf("sink must have a type")
+		}
+		n := item.Keys[0].Token.Value().(string)
+
+		// Check for invalid keys
+		valid := []string{
+			"address",
+			"tag",
+			"facility",
+			"buffer_size",
+			"overflow",
+		}
+		if err := checkHCLKeys(item.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("sink '%s' ->", n))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+
+		sink := api.LogSink{Type: n}
+		if err := mapstructure.WeakDecode(m, &sink); err != nil {
+			return err
+		}
+		*result = append(*result, &sink)
+	}
+	return nil
+}
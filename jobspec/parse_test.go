@@ -199,9 +199,20 @@ func TestParse(t *testing.T) {
 									},
 								},
 							},
+							{
+								Attribute: "${meta.rack}",
+								MaxSkew:   intToPtr(1),
+							},
 						},
 						StopAfterClientDisconnect: timeToPtr(120 * time.Second),
 						MaxClientDisconnect:       timeToPtr(120 * time.Hour),
+						Placement:                 stringToPtr("all_or_nothing"),
+						Preemption: &api.PreemptionPolicy{
+							Preemptible:   boolToPtr(true),
+							MaxDisruption: intToPtr(1),
+						},
+						DependsOn:          []string{"db"},
+						DependsOnCondition: stringToPtr("healthy"),
 						ReschedulePolicy: &api.ReschedulePolicy{
 							Interval: timeToPtr(12 * time.Hour),
 							Attempts: intToPtr(5),
@@ -330,8 +341,24 @@ func TestParse(t *testing.T) {
 								KillTimeout:   timeToPtr(22 * time.Second),
 								ShutdownDelay: 11 * time.Second,
 								LogConfig: &api.LogConfig{
-									MaxFiles:      intToPtr(14),
-									MaxFileSizeMB: intToPtr(101),
+									MaxFiles:       intToPtr(14),
+									MaxFileSizeMB:  intToPtr(101),
+									MaxFileAge:     timeToPtr(6 * time.Hour),
+									Compress:       boolToPtr(true),
+									MaxAllocSizeMB: intToPtr(1000),
+									Sinks: []*api.LogSink{
+										{
+											Type: "file",
+										},
+										{
+											Type:       "syslog",
+											Address:    "udp://127.0.0.1:514",
+											Tag:        "binstore",
+											Facility:   "local3",
+											BufferSize: intToPtr(500),
+											Overflow:   "block",
+										},
+									},
 								},
 								Artifacts: []*api.TaskArtifact{
 									{
@@ -407,6 +434,10 @@ func TestParse(t *testing.T) {
 									ChangeMode:   stringToPtr(vaultChangeModeSignal),
 									ChangeSignal: stringToPtr("SIGUSR1"),
 								},
+								Identity: &api.WorkloadIdentity{
+									Env:  true,
+									File: false,
+								},
 							},
 						},
 					},
@@ -1740,6 +1771,22 @@ func TestParse(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"job-scheduler.hcl",
+			&api.Job{
+				ID:        stringToPtr("job-scheduler"),
+				Name:      stringToPtr("job-scheduler"),
+				Type:      stringToPtr("batch"),
+				Scheduler: stringToPtr("gang"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name:  stringToPtr("group"),
+						Count: intToPtr(4),
+					},
+				},
+			},
+			false,
+		},
 		{
 			"multiregion.hcl",
 			&api.Job{
@@ -1792,6 +1839,32 @@ func TestParse(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"resources-numa.hcl",
+			&api.Job{
+				ID:   stringToPtr("numa-test"),
+				Name: stringToPtr("numa-test"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: stringToPtr("group"),
+						Tasks: []*api.Task{
+							{
+								Name:   "task",
+								Driver: "docker",
+								Resources: &api.Resources{
+									Cores:    intToPtr(4),
+									MemoryMB: intToPtr(128),
+									NUMA: &api.NUMAResource{
+										Affinity: "require",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
 		{
 			"service-provider.hcl",
 			&api.Job{
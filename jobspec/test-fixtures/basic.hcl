@@ -157,8 +157,21 @@ job "binstore-storagelocker" {
       }
     }
 
+    spread {
+      attribute = "${meta.rack}"
+      max_skew  = 1
+    }
+
     stop_after_client_disconnect = "120s"
     max_client_disconnect        = "120h"
+    placement                    = "all_or_nothing"
+    depends_on                   = ["db"]
+    depends_on_condition         = "healthy"
+
+    preemption {
+      preemptible    = true
+      max_disruption = 1
+    }
 
     task "binstore" {
       driver = "docker"
@@ -191,8 +204,21 @@ job "binstore-storagelocker" {
       }
 
       logs {
-        max_files     = 14
-        max_file_size = 101
+        max_files      = 14
+        max_file_size  = 101
+        max_file_age   = "6h"
+        compress       = true
+        max_alloc_size = 1000
+
+        sink "file" {}
+
+        sink "syslog" {
+          address     = "udp://127.0.0.1:514"
+          tag         = "binstore"
+          facility    = "local3"
+          buffer_size = 500
+          overflow    = "block"
+        }
       }
 
       env {
@@ -351,6 +377,11 @@ job "binstore-storagelocker" {
         change_mode   = "signal"
         change_signal = "SIGUSR1"
       }
+
+      identity {
+        env  = true
+        file = false
+      }
     }
 
     constraint {
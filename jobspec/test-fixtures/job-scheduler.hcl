@@ -0,0 +1,8 @@
+job "job-scheduler" {
+  type      = "batch"
+  scheduler = "gang"
+
+  group "group" {
+    count = 4
+  }
+}
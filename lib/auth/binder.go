@@ -0,0 +1,177 @@
+package auth
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/go-bexpr"
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// bindNameVariable matches the ${value.<key>} variables which can be used
+// within binding rule bind names.
+var bindNameVariable = regexp.MustCompile(`\$\{value\.([^}]+)\}`)
+
+// BinderStateStore is the subset of state store methods used by the Binder.
+type BinderStateStore interface {
+	GetACLBindingRulesByAuthMethod(ws memdb.WatchSet, authMethod string) (memdb.ResultIterator, error)
+	GetACLRoleByName(ws memdb.WatchSet, roleName string) (*structs.ACLRole, error)
+	ACLPolicyByName(ws memdb.WatchSet, name string) (*structs.ACLPolicy, error)
+}
+
+// Binder is responsible for collecting the ACL roles and policies to be
+// assigned to a token generated as a result of "logging in" via an auth
+// method.
+type Binder struct {
+	store BinderStateStore
+}
+
+// NewBinder creates a Binder with the given state store.
+func NewBinder(store BinderStateStore) *Binder {
+	return &Binder{store: store}
+}
+
+// Bindings contains the ACL roles and policies to be assigned to the created
+// token.
+type Bindings struct {
+	Management bool
+	Roles      []*structs.ACLTokenRoleLink
+	Policies   []string
+}
+
+// None indicates that the resulting bindings would not give the created token
+// access to any resources.
+func (b *Bindings) None() bool {
+	if b == nil {
+		return true
+	}
+	return !b.Management && len(b.Policies) == 0 && len(b.Roles) == 0
+}
+
+// Bind collects the ACL roles and policies to be assigned to the created
+// token, by evaluating the binding rules of the auth method against the
+// identity. Binding rules whose target role or policy does not exist are
+// skipped.
+func (b *Binder) Bind(authMethod *structs.ACLAuthMethod, identity *Identity) (*Bindings, error) {
+
+	var bindings Bindings
+
+	iter, err := b.store.GetACLBindingRulesByAuthMethod(nil, authMethod.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	var matchingRules []*structs.ACLBindingRule
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		rule := raw.(*structs.ACLBindingRule)
+		if doesSelectorMatch(rule.Selector, identity.Claims) {
+			matchingRules = append(matchingRules, rule)
+		}
+	}
+	if len(matchingRules) == 0 {
+		return &bindings, nil
+	}
+
+	seenRoles := make(map[string]struct{})
+	seenPolicies := make(map[string]struct{})
+
+	for _, rule := range matchingRules {
+		switch rule.BindType {
+		case structs.ACLBindingRuleBindTypeManagement:
+			bindings.Management = true
+
+		case structs.ACLBindingRuleBindTypeRole:
+			bindName, valid, err := computeBindName(rule.BindName, identity.ClaimMappings)
+			if err != nil || !valid {
+				continue
+			}
+			role, err := b.store.GetACLRoleByName(nil, bindName)
+			if err != nil {
+				return nil, err
+			}
+			if role == nil {
+				continue
+			}
+			if _, ok := seenRoles[role.ID]; !ok {
+				seenRoles[role.ID] = struct{}{}
+				bindings.Roles = append(bindings.Roles, &structs.ACLTokenRoleLink{ID: role.ID})
+			}
+
+		case structs.ACLBindingRuleBindTypePolicy:
+			bindName, valid, err := computeBindName(rule.BindName, identity.ClaimMappings)
+			if err != nil || !valid {
+				continue
+			}
+			policy, err := b.store.ACLPolicyByName(nil, bindName)
+			if err != nil {
+				return nil, err
+			}
+			if policy == nil {
+				continue
+			}
+			if _, ok := seenPolicies[policy.Name]; !ok {
+				seenPolicies[policy.Name] = struct{}{}
+				bindings.Policies = append(bindings.Policies, policy.Name)
+			}
+		}
+	}
+
+	// Management tokens cannot be linked to roles or policies, and have
+	// access to everything regardless.
+	if bindings.Management {
+		bindings.Roles = nil
+		bindings.Policies = nil
+	}
+
+	return &bindings, nil
+}
+
+// computeBindName interpolates the ${value.<key>} variables within the bind
+// name using the identity claim mappings. The returned boolean indicates
+// whether every variable could be interpolated, and the result is non-empty.
+func computeBindName(bindName string, claimMappings map[string]string) (string, bool, error) {
+	valid := true
+	result := bindNameVariable.ReplaceAllStringFunc(bindName, func(match string) string {
+		key := bindNameVariable.FindStringSubmatch(match)[1]
+		val, ok := claimMappings[key]
+		if !ok {
+			valid = false
+		}
+		return val
+	})
+	if !valid {
+		return "", false, fmt.Errorf("bind name %q references unknown claim mappings", bindName)
+	}
+	return result, result != "", nil
+}
+
+// doesSelectorMatch checks that a single selector matches the provided
+// claims. An empty selector matches all identities, while errors evaluating
+// the selector are treated as a non-match.
+func doesSelectorMatch(selector string, claims *SelectorData) bool {
+	if selector == "" {
+		return true
+	}
+	eval, err := bexpr.CreateEvaluator(selector)
+	if err != nil {
+		return false
+	}
+	result, err := eval.Evaluate(claims)
+	if err != nil {
+		return false
+	}
+	return result
+}
+
+// ValidateSelector ensures the binding rule selector is a valid boolean
+// expression.
+func ValidateSelector(selector string) error {
+	if selector == "" {
+		return nil
+	}
+	if _, err := bexpr.CreateEvaluator(selector); err != nil {
+		return fmt.Errorf("invalid selector: %v", err)
+	}
+	return nil
+}
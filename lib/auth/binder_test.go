@@ -0,0 +1,133 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBinder_Bind(t *testing.T) {
+	ci.Parallel(t)
+
+	testStore := state.TestStateStore(t)
+	testBind := NewBinder(testStore)
+
+	// Create an auth method, a policy and a role for the binding rules to
+	// link to.
+	authMethod := mock.ACLAuthMethod()
+	require.NoError(t, testStore.UpsertACLAuthMethods(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLAuthMethod{authMethod}))
+
+	policy := mock.ACLPolicy()
+	policy.Name = "eng-ro"
+	require.NoError(t, testStore.UpsertACLPolicies(
+		structs.MsgTypeTestSetup, 20, []*structs.ACLPolicy{policy}))
+
+	role := mock.ACLRole()
+	role.Name = "engineering"
+	role.Policies = []*structs.ACLRolePolicyLink{{Name: policy.Name}}
+	role.SetHash()
+	require.NoError(t, testStore.UpsertACLRoles(
+		structs.MsgTypeTestSetup, 30, []*structs.ACLRole{role}, false))
+
+	identity := &Identity{
+		Claims: &SelectorData{
+			Value: map[string]string{"team": "engineering"},
+			List:  map[string][]string{"groups": {"eng", "admins"}},
+		},
+		ClaimMappings: map[string]string{"team": "engineering"},
+	}
+
+	// No binding rules exist, so the bindings should be empty.
+	bindings, err := testBind.Bind(authMethod, identity)
+	require.NoError(t, err)
+	require.True(t, bindings.None())
+
+	newRule := func(selector, bindType, bindName string) *structs.ACLBindingRule {
+		rule := mock.ACLBindingRule()
+		rule.AuthMethod = authMethod.Name
+		rule.Selector = selector
+		rule.BindType = bindType
+		rule.BindName = bindName
+		rule.SetHash()
+		return rule
+	}
+
+	rules := []*structs.ACLBindingRule{
+		newRule("eng in list.groups", structs.ACLBindingRuleBindTypeRole, "${value.team}"),
+		newRule("", structs.ACLBindingRuleBindTypePolicy, "eng-ro"),
+		newRule("", structs.ACLBindingRuleBindTypePolicy, "eng-ro"),
+		newRule("ops in list.groups", structs.ACLBindingRuleBindTypePolicy, "ops"),
+		newRule("", structs.ACLBindingRuleBindTypePolicy, "not-a-policy"),
+		newRule("", structs.ACLBindingRuleBindTypeRole, "${value.unknown}"),
+	}
+	require.NoError(t, testStore.UpsertACLBindingRules(structs.MsgTypeTestSetup, 40, rules, false))
+
+	// The matching rules should bind the role and the policy once, while
+	// rules targeting missing objects are skipped.
+	bindings, err = testBind.Bind(authMethod, identity)
+	require.NoError(t, err)
+	require.False(t, bindings.Management)
+	require.Equal(t, []*structs.ACLTokenRoleLink{{ID: role.ID}}, bindings.Roles)
+	require.Equal(t, []string{"eng-ro"}, bindings.Policies)
+
+	// A matching management rule should result in a management binding
+	// without any roles or policies.
+	managementRule := newRule("admins in list.groups", structs.ACLBindingRuleBindTypeManagement, "")
+	require.NoError(t, testStore.UpsertACLBindingRules(
+		structs.MsgTypeTestSetup, 50, []*structs.ACLBindingRule{managementRule}, false))
+
+	bindings, err = testBind.Bind(authMethod, identity)
+	require.NoError(t, err)
+	require.True(t, bindings.Management)
+	require.Empty(t, bindings.Roles)
+	require.Empty(t, bindings.Policies)
+	require.False(t, bindings.None())
+}
+
+func Test_computeBindName(t *testing.T) {
+	ci.Parallel(t)
+
+	claimMappings := map[string]string{"team": "engineering", "env": "prod"}
+
+	out, valid, err := computeBindName("${value.team}-${value.env}", claimMappings)
+	require.NoError(t, err)
+	require.True(t, valid)
+	require.Equal(t, "engineering-prod", out)
+
+	out, valid, err = computeBindName("static", claimMappings)
+	require.NoError(t, err)
+	require.True(t, valid)
+	require.Equal(t, "static", out)
+
+	_, valid, err = computeBindName("${value.missing}", claimMappings)
+	require.Error(t, err)
+	require.False(t, valid)
+}
+
+func Test_doesSelectorMatch(t *testing.T) {
+	ci.Parallel(t)
+
+	claims := &SelectorData{
+		Value: map[string]string{"team": "engineering"},
+		List:  map[string][]string{"groups": {"eng"}},
+	}
+
+	require.True(t, doesSelectorMatch("", claims))
+	require.True(t, doesSelectorMatch(`value.team == "engineering"`, claims))
+	require.True(t, doesSelectorMatch("eng in list.groups", claims))
+	require.False(t, doesSelectorMatch("ops in list.groups", claims))
+	require.False(t, doesSelectorMatch("not a valid selector ==", claims))
+}
+
+func TestValidateSelector(t *testing.T) {
+	ci.Parallel(t)
+
+	require.NoError(t, ValidateSelector(""))
+	require.NoError(t, ValidateSelector("eng in list.groups"))
+	require.Error(t, ValidateSelector("not a valid selector =="))
+}
@@ -0,0 +1,125 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Identity is the verified identity of a user logging in via an auth method.
+// It is built from the claims returned by the auth method using the claim
+// mappings of the auth method configuration.
+type Identity struct {
+
+	// Claims is the data binding rule selectors are evaluated against.
+	Claims *SelectorData
+
+	// ClaimMappings contains the mapped single value claims, which can be
+	// interpolated into binding rule bind names.
+	ClaimMappings map[string]string
+}
+
+// SelectorData is the data binding rule selectors are evaluated against.
+// Single value claims are available as "value.<key>" and list claims as
+// "list.<key>", where the key is the one configured in the auth method claim
+// mappings.
+type SelectorData struct {
+	Value map[string]string   `bexpr:"value"`
+	List  map[string][]string `bexpr:"list"`
+}
+
+// NewIdentity builds the Identity of a user from the claims returned by the
+// auth method. Claims which are not configured within the auth method claim
+// mappings are ignored, as are mapped claims which are missing.
+func NewIdentity(config *structs.ACLAuthMethodConfig, claims map[string]interface{}) (*Identity, error) {
+	data := &SelectorData{
+		Value: make(map[string]string),
+		List:  make(map[string][]string),
+	}
+
+	if config == nil {
+		return &Identity{Claims: data, ClaimMappings: data.Value}, nil
+	}
+
+	for claim, key := range config.ClaimMappings {
+		raw, ok := lookupClaim(claims, claim)
+		if !ok {
+			continue
+		}
+		val, err := claimString(raw)
+		if err != nil {
+			return nil, fmt.Errorf("error converting claim %q to string: %v", claim, err)
+		}
+		data.Value[key] = val
+	}
+
+	for claim, key := range config.ListClaimMappings {
+		raw, ok := lookupClaim(claims, claim)
+		if !ok {
+			continue
+		}
+
+		// Single values are treated as a list containing one item, since
+		// providers often omit the list when there is a single entry.
+		items, isList := raw.([]interface{})
+		if !isList {
+			items = []interface{}{raw}
+		}
+
+		list := make([]string, 0, len(items))
+		for _, item := range items {
+			val, err := claimString(item)
+			if err != nil {
+				return nil, fmt.Errorf("error converting list claim %q to string: %v", claim, err)
+			}
+			list = append(list, val)
+		}
+		data.List[key] = list
+	}
+
+	return &Identity{
+		Claims:        data,
+		ClaimMappings: data.Value,
+	}, nil
+}
+
+// lookupClaim returns the named claim. Nested claims can be referenced using
+// a JSON pointer such as "/groups/engineering".
+func lookupClaim(claims map[string]interface{}, name string) (interface{}, bool) {
+	if !strings.HasPrefix(name, "/") {
+		val, ok := claims[name]
+		return val, ok && val != nil
+	}
+
+	var current interface{} = claims
+	for _, part := range strings.Split(strings.TrimPrefix(name, "/"), "/") {
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	return current, current != nil
+}
+
+// claimString converts a scalar claim value into its string form.
+func claimString(raw interface{}) (string, error) {
+	switch v := raw.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case json.Number:
+		return v.String(), nil
+	default:
+		return "", fmt.Errorf("unsupported type %T", raw)
+	}
+}
@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewIdentity(t *testing.T) {
+	ci.Parallel(t)
+
+	config := &structs.ACLAuthMethodConfig{
+		ClaimMappings: map[string]string{
+			"email":          "email",
+			"email_verified": "verified",
+			"/org/team":      "team",
+			"missing":        "missing",
+		},
+		ListClaimMappings: map[string]string{
+			"groups": "groups",
+			"role":   "roles",
+		},
+	}
+	claims := map[string]interface{}{
+		"email":          "alice@example.com",
+		"email_verified": true,
+		"org":            map[string]interface{}{"team": "engineering"},
+		"groups":         []interface{}{"eng", "ops"},
+		"role":           "admin",
+	}
+
+	identity, err := NewIdentity(config, claims)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"email":    "alice@example.com",
+		"verified": "true",
+		"team":     "engineering",
+	}, identity.Claims.Value)
+	require.Equal(t, map[string][]string{
+		"groups": {"eng", "ops"},
+		"roles":  {"admin"},
+	}, identity.Claims.List)
+	require.Equal(t, identity.Claims.Value, identity.ClaimMappings)
+
+	// Claims which cannot be converted to a string should error.
+	claims["email"] = map[string]interface{}{"foo": "bar"}
+	_, err = NewIdentity(config, claims)
+	require.ErrorContains(t, err, "error converting claim")
+}
@@ -0,0 +1,454 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+const (
+	// discoveryPath is the path, relative to the OIDC discovery URL, of the
+	// OIDC provider configuration document.
+	discoveryPath = "/.well-known/openid-configuration"
+
+	// defaultSigningAlg is the signing algorithm ID tokens are expected to
+	// use when the auth method does not configure any.
+	defaultSigningAlg = string(jose.RS256)
+
+	// clockSkewLeeway is the leeway used when validating the time based
+	// claims of an ID token, to account for clock skew between the provider
+	// and Nomad servers.
+	clockSkewLeeway = time.Minute
+
+	// maxResponseSize limits the size of responses read from the OIDC
+	// provider.
+	maxResponseSize = 1 << 20
+
+	// requestTimeout is the timeout used for all requests made to the OIDC
+	// provider.
+	requestTimeout = 10 * time.Second
+)
+
+// NewID generates a random string which can be used as the state or nonce
+// parameter of the OIDC authorization code flow.
+func NewID() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, buf); err != nil {
+		return "", fmt.Errorf("failed to generate random ID: %v", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// discoveryDocument is the subset of the OIDC provider configuration document
+// used by Nomad.
+type discoveryDocument struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+}
+
+// tokenResponse is the response from the OIDC provider token endpoint.
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	IDToken     string `json:"id_token"`
+	TokenType   string `json:"token_type"`
+}
+
+// Provider performs the OIDC authorization code flow for a single auth method.
+// It is safe for concurrent use.
+type Provider struct {
+	config *structs.ACLAuthMethodConfig
+	client *http.Client
+	doc    *discoveryDocument
+
+	// keys is the cached JSON web key set of the provider, used to verify ID
+	// token signatures. It is refreshed when a token is signed by an unknown
+	// key.
+	keys     *jose.JSONWebKeySet
+	keysLock sync.Mutex
+}
+
+// NewProvider creates a Provider for the auth method, performing OIDC
+// discovery to find the endpoints of the OIDC provider.
+func NewProvider(ctx context.Context, config *structs.ACLAuthMethodConfig) (*Provider, error) {
+	if config == nil {
+		return nil, errors.New("missing auth method config")
+	}
+
+	client, err := newHTTPClient(config.DiscoveryCaPem)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Provider{
+		config: config.Copy(),
+		client: client,
+	}
+
+	discoveryURL := strings.TrimSuffix(config.OIDCDiscoveryURL, "/") + discoveryPath
+
+	var doc discoveryDocument
+	if err := p.getJSON(ctx, discoveryURL, &doc); err != nil {
+		return nil, fmt.Errorf("failed to perform OIDC discovery: %v", err)
+	}
+
+	// The issuer returned by the provider must match the discovery URL, as
+	// required by the OIDC discovery specification.
+	if strings.TrimSuffix(doc.Issuer, "/") != strings.TrimSuffix(config.OIDCDiscoveryURL, "/") {
+		return nil, fmt.Errorf("OIDC discovery issuer %q does not match discovery URL %q",
+			doc.Issuer, config.OIDCDiscoveryURL)
+	}
+	if doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" || doc.JWKSURI == "" {
+		return nil, errors.New("OIDC discovery document is missing required endpoints")
+	}
+	p.doc = &doc
+
+	return p, nil
+}
+
+// newHTTPClient returns the client used to talk to the OIDC provider. If any
+// CA certificates are supplied, they are used instead of the system pool.
+func newHTTPClient(caPEMs []string) (*http.Client, error) {
+	client := cleanhttp.DefaultClient()
+	client.Timeout = requestTimeout
+
+	if len(caPEMs) == 0 {
+		return client, nil
+	}
+
+	pool := x509.NewCertPool()
+	for _, caPEM := range caPEMs {
+		if !pool.AppendCertsFromPEM([]byte(caPEM)) {
+			return nil, errors.New("could not parse discovery CA PEM")
+		}
+	}
+
+	transport := cleanhttp.DefaultTransport()
+	transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	client.Transport = transport
+
+	return client, nil
+}
+
+// AuthURL returns the URL the user should visit to authenticate with the OIDC
+// provider. The redirect URI must be one of the auth method's allowed redirect
+// URIs.
+func (p *Provider) AuthURL(state, nonce, redirectURI string) (string, error) {
+	if !p.redirectURIAllowed(redirectURI) {
+		return "", fmt.Errorf("redirect URI %q is not allowed", redirectURI)
+	}
+
+	scopes := append([]string{"openid"}, p.config.OIDCScopes...)
+
+	authURL, err := url.Parse(p.doc.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid authorization endpoint: %v", err)
+	}
+
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.OIDCClientID)
+	query.Set("redirect_uri", redirectURI)
+	query.Set("scope", strings.Join(scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	authURL.RawQuery = query.Encode()
+
+	return authURL.String(), nil
+}
+
+// Exchange exchanges the authorization code for an ID token, verifies the ID
+// token and returns its claims. The nonce must match the nonce used to create
+// the auth URL.
+func (p *Provider) Exchange(ctx context.Context, code, nonce, redirectURI string) (map[string]interface{}, error) {
+	if !p.redirectURIAllowed(redirectURI) {
+		return nil, fmt.Errorf("redirect URI %q is not allowed", redirectURI)
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", redirectURI)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.doc.TokenEndpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	// Authenticate using HTTP basic auth, unless the provider only supports
+	// sending the client credentials within the request body.
+	if p.useClientSecretPost() {
+		form.Set("client_id", p.config.OIDCClientID)
+		form.Set("client_secret", p.config.OIDCClientSecret)
+	} else {
+		req.SetBasicAuth(url.QueryEscape(p.config.OIDCClientID), url.QueryEscape(p.config.OIDCClientSecret))
+	}
+	req.Body = ioutil.NopCloser(strings.NewReader(form.Encode()))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange authorization code: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to exchange authorization code: unexpected status %d: %s",
+			resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var tokens tokenResponse
+	if err := json.Unmarshal(body, &tokens); err != nil {
+		return nil, fmt.Errorf("failed to decode token response: %v", err)
+	}
+	if tokens.IDToken == "" {
+		return nil, errors.New("token response did not contain an ID token")
+	}
+
+	return p.VerifyIDToken(ctx, tokens.IDToken, nonce)
+}
+
+// VerifyIDToken verifies the signature and standard claims of the raw ID
+// token, and returns all of its claims.
+func (p *Provider) VerifyIDToken(ctx context.Context, raw, nonce string) (map[string]interface{}, error) {
+	token, err := jwt.ParseSigned(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ID token: %v", err)
+	}
+	if len(token.Headers) != 1 {
+		return nil, errors.New("ID token must have a single signature")
+	}
+	header := token.Headers[0]
+
+	if !p.signingAlgAllowed(header.Algorithm) {
+		return nil, fmt.Errorf("ID token signed with unsupported algorithm %q", header.Algorithm)
+	}
+
+	keys, err := p.signingKeys(ctx, header.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		claims    jwt.Claims
+		allClaims map[string]interface{}
+		verified  bool
+	)
+	for _, key := range keys {
+		if err := token.Claims(key.Key, &claims, &allClaims); err == nil {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, errors.New("failed to verify ID token signature")
+	}
+
+	expected := jwt.Expected{
+		Issuer: p.doc.Issuer,
+		Time:   time.Now(),
+	}
+	if err := claims.ValidateWithLeeway(expected, clockSkewLeeway); err != nil {
+		return nil, fmt.Errorf("invalid ID token: %v", err)
+	}
+	if claims.Expiry == nil {
+		return nil, errors.New("invalid ID token: missing expiry")
+	}
+
+	if err := p.validateAudience(claims.Audience); err != nil {
+		return nil, err
+	}
+
+	if tokenNonce, _ := allClaims["nonce"].(string); tokenNonce != nonce {
+		return nil, errors.New("invalid ID token: nonce does not match")
+	}
+
+	return allClaims, nil
+}
+
+// validateAudience ensures the ID token was issued for Nomad. If the auth
+// method has bound audiences, the token must contain at least one of them,
+// otherwise it must contain the OIDC client ID.
+func (p *Provider) validateAudience(audience jwt.Audience) error {
+	bound := p.config.BoundAudiences
+	if len(bound) == 0 {
+		bound = []string{p.config.OIDCClientID}
+	}
+	for _, aud := range bound {
+		if audience.Contains(aud) {
+			return nil
+		}
+	}
+	return errors.New("invalid ID token: audience does not match")
+}
+
+// signingKeys returns the keys which may have signed a token with the given
+// key ID. The key set is refreshed if no key with the ID is known, to handle
+// key rotation by the provider.
+func (p *Provider) signingKeys(ctx context.Context, keyID string) ([]jose.JSONWebKey, error) {
+	p.keysLock.Lock()
+	defer p.keysLock.Unlock()
+
+	lookup := func() []jose.JSONWebKey {
+		if p.keys == nil {
+			return nil
+		}
+		if keyID == "" {
+			return p.keys.Keys
+		}
+		return p.keys.Key(keyID)
+	}
+
+	if keys := lookup(); len(keys) > 0 {
+		return keys, nil
+	}
+
+	var keySet jose.JSONWebKeySet
+	if err := p.getJSON(ctx, p.doc.JWKSURI, &keySet); err != nil {
+		return nil, fmt.Errorf("failed to fetch OIDC provider keys: %v", err)
+	}
+	p.keys = &keySet
+
+	if keys := lookup(); len(keys) > 0 {
+		return keys, nil
+	}
+	return nil, fmt.Errorf("failed to find ID token signing key %q", keyID)
+}
+
+// redirectURIAllowed returns whether the redirect URI is one of the auth
+// method's allowed redirect URIs.
+func (p *Provider) redirectURIAllowed(redirectURI string) bool {
+	for _, allowed := range p.config.AllowedRedirectURIs {
+		if allowed == redirectURI {
+			return true
+		}
+	}
+	return false
+}
+
+// signingAlgAllowed returns whether ID tokens signed with the algorithm are
+// accepted by the auth method.
+func (p *Provider) signingAlgAllowed(alg string) bool {
+	algs := p.config.SigningAlgs
+	if len(algs) == 0 {
+		algs = []string{defaultSigningAlg}
+	}
+	for _, allowed := range algs {
+		if allowed == alg {
+			return true
+		}
+	}
+	return false
+}
+
+// useClientSecretPost returns whether the client credentials should be sent
+// within the token request body rather than using HTTP basic auth.
+func (p *Provider) useClientSecretPost() bool {
+	var basic, post bool
+	for _, method := range p.doc.TokenEndpointAuthMethodsSupported {
+		switch method {
+		case "client_secret_basic":
+			basic = true
+		case "client_secret_post":
+			post = true
+		}
+	}
+	return post && !basic
+}
+
+// getJSON performs a GET request against the OIDC provider and decodes the
+// JSON response into out.
+func (p *Provider) getJSON(ctx context.Context, u string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, u)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(out)
+}
+
+// ProviderCache caches the Provider of each auth method, so that OIDC
+// discovery is not performed on every login request. A cached provider is
+// replaced when the auth method is modified.
+type ProviderCache struct {
+	providers map[string]*cachedProvider
+	lock      sync.Mutex
+}
+
+type cachedProvider struct {
+	hash     string
+	provider *Provider
+}
+
+// NewProviderCache returns an empty ProviderCache.
+func NewProviderCache() *ProviderCache {
+	return &ProviderCache{
+		providers: make(map[string]*cachedProvider),
+	}
+}
+
+// Get returns the Provider for the auth method, creating it if it is not
+// cached or the auth method has been modified since it was cached.
+func (c *ProviderCache) Get(ctx context.Context, authMethod *structs.ACLAuthMethod) (*Provider, error) {
+	hash := hex.EncodeToString(authMethod.Hash)
+
+	c.lock.Lock()
+	cached, ok := c.providers[authMethod.Name]
+	c.lock.Unlock()
+
+	if ok && cached.hash == hash {
+		return cached.provider, nil
+	}
+
+	provider, err := NewProvider(ctx, authMethod.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	c.providers[authMethod.Name] = &cachedProvider{hash: hash, provider: provider}
+	c.lock.Unlock()
+
+	return provider, nil
+}
+
+// Delete removes the cached Provider of the named auth method.
+func (c *ProviderCache) Delete(name string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.providers, name)
+}
@@ -0,0 +1,183 @@
+package oidc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+const testRedirectURI = "http://localhost:4649/oidc/callback"
+
+func testProviderConfig(tp *TestProvider) *structs.ACLAuthMethodConfig {
+	return &structs.ACLAuthMethodConfig{
+		OIDCDiscoveryURL:    tp.URL(),
+		OIDCClientID:        tp.ClientID,
+		OIDCClientSecret:    tp.ClientSecret,
+		AllowedRedirectURIs: []string{testRedirectURI},
+	}
+}
+
+func TestProvider_Login(t *testing.T) {
+	ci.Parallel(t)
+
+	tp := NewTestProvider(t)
+	tp.SetClaims(map[string]interface{}{
+		"email":  "alice@example.com",
+		"groups": []string{"eng"},
+	})
+
+	ctx := context.Background()
+	provider, err := NewProvider(ctx, testProviderConfig(tp))
+	require.NoError(t, err)
+
+	// Redirect URIs which are not allowed should be rejected.
+	_, err = provider.AuthURL("state", "nonce", "http://evil.example.com/callback")
+	require.ErrorContains(t, err, "is not allowed")
+
+	authURL, err := provider.AuthURL("test-state", "test-nonce", testRedirectURI)
+	require.NoError(t, err)
+
+	u, err := url.Parse(authURL)
+	require.NoError(t, err)
+	require.Equal(t, "openid", u.Query().Get("scope"))
+	require.Equal(t, "test-nonce", u.Query().Get("nonce"))
+
+	code, state := tp.Authorize(authURL)
+	require.Equal(t, "test-state", state)
+
+	// Exchanging the code with a different nonce should fail.
+	_, err = provider.Exchange(ctx, code, "other-nonce", testRedirectURI)
+	require.ErrorContains(t, err, "nonce does not match")
+
+	// The code is single use, so perform a new authorization.
+	code, _ = tp.Authorize(authURL)
+	claims, err := provider.Exchange(ctx, code, "test-nonce", testRedirectURI)
+	require.NoError(t, err)
+	require.Equal(t, "test-subject", claims["sub"])
+	require.Equal(t, "alice@example.com", claims["email"])
+	require.Equal(t, []interface{}{"eng"}, claims["groups"])
+}
+
+func TestProvider_VerifyIDToken(t *testing.T) {
+	ci.Parallel(t)
+
+	tp := NewTestProvider(t)
+	ctx := context.Background()
+
+	config := testProviderConfig(tp)
+	config.BoundAudiences = []string{"nomad"}
+
+	provider, err := NewProvider(ctx, config)
+	require.NoError(t, err)
+
+	now := time.Now()
+	validClaims := func() jwt.Claims {
+		return jwt.Claims{
+			Issuer:   tp.URL(),
+			Subject:  "test-subject",
+			Audience: jwt.Audience{"nomad"},
+			Expiry:   jwt.NewNumericDate(now.Add(time.Minute)),
+			IssuedAt: jwt.NewNumericDate(now),
+		}
+	}
+
+	_, err = provider.VerifyIDToken(ctx, tp.SignIDToken(validClaims(), "nonce"), "nonce")
+	require.NoError(t, err)
+
+	wrongAudience := validClaims()
+	wrongAudience.Audience = jwt.Audience{tp.ClientID}
+	_, err = provider.VerifyIDToken(ctx, tp.SignIDToken(wrongAudience, "nonce"), "nonce")
+	require.Error(t, err)
+
+	wrongIssuer := validClaims()
+	wrongIssuer.Issuer = "http://example.com"
+	_, err = provider.VerifyIDToken(ctx, tp.SignIDToken(wrongIssuer, "nonce"), "nonce")
+	require.ErrorContains(t, err, "invalid ID token")
+
+	expired := validClaims()
+	expired.Expiry = jwt.NewNumericDate(now.Add(-time.Hour))
+	_, err = provider.VerifyIDToken(ctx, tp.SignIDToken(expired, "nonce"), "nonce")
+	require.ErrorContains(t, err, "invalid ID token")
+
+	_, err = provider.VerifyIDToken(ctx, "not-a-token", "nonce")
+	require.ErrorContains(t, err, "failed to parse ID token")
+}
+
+func TestProviderCache(t *testing.T) {
+	ci.Parallel(t)
+
+	tp := NewTestProvider(t)
+	ctx := context.Background()
+
+	authMethod := &structs.ACLAuthMethod{
+		Name:          "test",
+		Type:          structs.ACLAuthMethodTypeOIDC,
+		TokenLocality: structs.ACLAuthMethodTokenLocalityLocal,
+		MaxTokenTTL:   time.Hour,
+		Config:        testProviderConfig(tp),
+	}
+	authMethod.SetHash()
+
+	cache := NewProviderCache()
+
+	provider1, err := cache.Get(ctx, authMethod)
+	require.NoError(t, err)
+	provider2, err := cache.Get(ctx, authMethod)
+	require.NoError(t, err)
+	require.Same(t, provider1, provider2)
+
+	// Modifying the auth method should result in a new provider.
+	authMethod.Config.OIDCScopes = []string{"groups"}
+	authMethod.SetHash()
+	provider3, err := cache.Get(ctx, authMethod)
+	require.NoError(t, err)
+	require.NotSame(t, provider2, provider3)
+
+	// Deleting the auth method from the cache should also result in a new
+	// provider.
+	cache.Delete(authMethod.Name)
+	provider4, err := cache.Get(ctx, authMethod)
+	require.NoError(t, err)
+	require.NotSame(t, provider3, provider4)
+}
+
+func TestCallbackServer(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, err := NewCallbackServer("127.0.0.1:0")
+	require.NoError(t, err)
+	defer srv.Close()
+	require.NotEmpty(t, srv.Nonce())
+
+	// A callback with an error should be sent to the error channel.
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, CallbackPath+"?error=access_denied", nil))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	select {
+	case err := <-srv.ErrorCh():
+		require.ErrorContains(t, err, "access_denied")
+	default:
+		t.Fatal("expected callback error")
+	}
+
+	// A successful callback should be sent to the success channel.
+	rec = httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, CallbackPath+"?code=foo&state=bar", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	select {
+	case res := <-srv.SuccessCh():
+		require.Equal(t, &CallbackResult{Code: "foo", State: "bar"}, res)
+	default:
+		t.Fatal("expected callback result")
+	}
+}
@@ -0,0 +1,154 @@
+package oidc
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"time"
+)
+
+const (
+	// CallbackPath is the path the OIDC provider redirects to after the user
+	// has authenticated.
+	CallbackPath = "/oidc/callback"
+
+	// callbackShutdownTimeout is the maximum time to wait for in-flight
+	// requests when closing the callback server.
+	callbackShutdownTimeout = 5 * time.Second
+)
+
+// CallbackResult contains the parameters the OIDC provider passed to the
+// callback server.
+type CallbackResult struct {
+	Code  string
+	State string
+}
+
+// CallbackServer is started by the CLI during login to receive the redirect
+// from the OIDC provider once the user has authenticated. It listens on a
+// local address, which must be configured as an allowed redirect URI of the
+// auth method.
+type CallbackServer struct {
+	httpServer *http.Server
+	listener   net.Listener
+	addr       string
+	nonce      string
+
+	errCh     chan error
+	successCh chan *CallbackResult
+}
+
+// NewCallbackServer creates and starts a callback server listening on the
+// address, such as "localhost:4649".
+func NewCallbackServer(addr string) (*CallbackServer, error) {
+	nonce, err := NewID()
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start OIDC callback server: %v", err)
+	}
+
+	srv := &CallbackServer{
+		listener:  listener,
+		addr:      addr,
+		nonce:     nonce,
+		errCh:     make(chan error, 5),
+		successCh: make(chan *CallbackResult, 5),
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(CallbackPath, srv)
+	srv.httpServer = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() { _ = srv.httpServer.Serve(listener) }()
+
+	return srv, nil
+}
+
+// Close stops the callback server.
+func (s *CallbackServer) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), callbackShutdownTimeout)
+	defer cancel()
+	return s.httpServer.Shutdown(ctx)
+}
+
+// RedirectURI returns the redirect URI which should be passed to the OIDC
+// provider.
+func (s *CallbackServer) RedirectURI() string {
+	return fmt.Sprintf("http://%s%s", s.addr, CallbackPath)
+}
+
+// Nonce returns the randomly generated nonce for this login attempt.
+func (s *CallbackServer) Nonce() string { return s.nonce }
+
+// ErrorCh returns a channel where any errors are sent. Errors may be sent
+// after a successful callback, so callers should stop listening once a
+// result has been received.
+func (s *CallbackServer) ErrorCh() <-chan error { return s.errCh }
+
+// SuccessCh returns a channel which receives the result of a successful
+// callback from the OIDC provider.
+func (s *CallbackServer) SuccessCh() <-chan *CallbackResult { return s.successCh }
+
+// ServeHTTP implements http.Handler and handles the callback request from the
+// OIDC provider.
+func (s *CallbackServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+
+	// The provider returns an error parameter if the user failed to
+	// authenticate or declined the request.
+	if errCode := query.Get("error"); errCode != "" {
+		err := fmt.Errorf("OIDC provider returned an error: %s %s", errCode, query.Get("error_description"))
+		s.writeResponse(w, http.StatusBadRequest, err.Error())
+		s.sendError(err)
+		return
+	}
+
+	code, state := query.Get("code"), query.Get("state")
+	if code == "" || state == "" {
+		err := fmt.Errorf("OIDC provider callback is missing the code or state parameter")
+		s.writeResponse(w, http.StatusBadRequest, err.Error())
+		s.sendError(err)
+		return
+	}
+
+	s.writeResponse(w, http.StatusOK, "Signed in via your OIDC provider. You can now close this window and return to the terminal.")
+
+	select {
+	case s.successCh <- &CallbackResult{Code: code, State: state}:
+	default:
+	}
+}
+
+func (s *CallbackServer) sendError(err error) {
+	select {
+	case s.errCh <- err:
+	default:
+	}
+}
+
+func (s *CallbackServer) writeResponse(w http.ResponseWriter, code int, msg string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(code)
+	_, _ = fmt.Fprintf(w, callbackResponseHTML, html.EscapeString(msg))
+}
+
+const callbackResponseHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Nomad OIDC Login</title>
+</head>
+<body>
+  <p>%s</p>
+</body>
+</html>
+`
@@ -0,0 +1,228 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+// TestProvider is a minimal OIDC provider which can be used within tests. It
+// supports discovery, the authorization code flow and serves the key set used
+// to sign its ID tokens.
+type TestProvider struct {
+	t      testing.TB
+	server *httptest.Server
+	signer jose.Signer
+	key    *rsa.PrivateKey
+
+	// ClientID and ClientSecret are the client credentials the provider
+	// accepts.
+	ClientID     string
+	ClientSecret string
+
+	lock   sync.Mutex
+	claims map[string]interface{}
+	codes  map[string]testAuthCode
+}
+
+// testAuthCode is an issued authorization code, along with the parameters of
+// the authorization request it was issued for.
+type testAuthCode struct {
+	nonce       string
+	redirectURI string
+}
+
+// testKeyID is the key ID of the test provider signing key.
+const testKeyID = "test-key"
+
+// NewTestProvider starts a TestProvider, which is stopped when the test
+// completes.
+func NewTestProvider(t testing.TB) *TestProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: key},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", testKeyID),
+	)
+	if err != nil {
+		t.Fatalf("failed to create signer: %v", err)
+	}
+
+	p := &TestProvider{
+		t:            t,
+		signer:       signer,
+		key:          key,
+		ClientID:     "nomad-test-client",
+		ClientSecret: "nomad-test-secret",
+		claims:       make(map[string]interface{}),
+		codes:        make(map[string]testAuthCode),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(discoveryPath, p.handleDiscovery)
+	mux.HandleFunc("/keys", p.handleKeys)
+	mux.HandleFunc("/authorize", p.handleAuthorize)
+	mux.HandleFunc("/token", p.handleToken)
+
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+
+	return p
+}
+
+// URL returns the issuer URL of the provider, which is also its discovery URL.
+func (p *TestProvider) URL() string { return p.server.URL }
+
+// SetClaims sets the additional claims included within the ID tokens issued
+// by the provider.
+func (p *TestProvider) SetClaims(claims map[string]interface{}) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.claims = claims
+}
+
+// Authorize simulates a user visiting the auth URL and authenticating. It
+// returns the code and state which the provider would pass to the redirect
+// URI.
+func (p *TestProvider) Authorize(authURL string) (string, string) {
+	u, err := url.Parse(authURL)
+	if err != nil {
+		p.t.Fatalf("failed to parse auth URL: %v", err)
+	}
+	query := u.Query()
+	if query.Get("client_id") != p.ClientID {
+		p.t.Fatalf("unexpected client ID %q", query.Get("client_id"))
+	}
+	code := p.issueCode(query.Get("nonce"), query.Get("redirect_uri"))
+	return code, query.Get("state")
+}
+
+// SignIDToken returns an ID token signed by the provider, containing the
+// passed claims in addition to the provider's configured claims.
+func (p *TestProvider) SignIDToken(claims jwt.Claims, nonce string) string {
+	p.lock.Lock()
+	extra := make(map[string]interface{}, len(p.claims)+1)
+	for k, v := range p.claims {
+		extra[k] = v
+	}
+	p.lock.Unlock()
+
+	if nonce != "" {
+		extra["nonce"] = nonce
+	}
+
+	raw, err := jwt.Signed(p.signer).Claims(claims).Claims(extra).CompactSerialize()
+	if err != nil {
+		p.t.Fatalf("failed to sign ID token: %v", err)
+	}
+	return raw
+}
+
+func (p *TestProvider) issueCode(nonce, redirectURI string) string {
+	code, err := NewID()
+	if err != nil {
+		p.t.Fatalf("failed to generate code: %v", err)
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.codes[code] = testAuthCode{nonce: nonce, redirectURI: redirectURI}
+	return code
+}
+
+func (p *TestProvider) handleDiscovery(w http.ResponseWriter, _ *http.Request) {
+	writeTestJSON(w, &discoveryDocument{
+		Issuer:                            p.URL(),
+		AuthorizationEndpoint:             p.URL() + "/authorize",
+		TokenEndpoint:                     p.URL() + "/token",
+		JWKSURI:                           p.URL() + "/keys",
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic"},
+	})
+}
+
+func (p *TestProvider) handleKeys(w http.ResponseWriter, _ *http.Request) {
+	writeTestJSON(w, &jose.JSONWebKeySet{
+		Keys: []jose.JSONWebKey{{
+			Key:       &p.key.PublicKey,
+			KeyID:     testKeyID,
+			Algorithm: string(jose.RS256),
+			Use:       "sig",
+		}},
+	})
+}
+
+// handleAuthorize immediately authenticates the user and redirects back to
+// the redirect URI.
+func (p *TestProvider) handleAuthorize(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	redirectURI := query.Get("redirect_uri")
+
+	redirect, err := url.Parse(redirectURI)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	params := redirect.Query()
+	params.Set("code", p.issueCode(query.Get("nonce"), redirectURI))
+	params.Set("state", query.Get("state"))
+	redirect.RawQuery = params.Encode()
+
+	http.Redirect(w, req, redirect.String(), http.StatusFound)
+}
+
+func (p *TestProvider) handleToken(w http.ResponseWriter, req *http.Request) {
+	clientID, clientSecret, ok := req.BasicAuth()
+	if !ok || clientID != url.QueryEscape(p.ClientID) || clientSecret != url.QueryEscape(p.ClientSecret) {
+		http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+		return
+	}
+	if err := req.ParseForm(); err != nil {
+		http.Error(w, `{"error":"invalid_request"}`, http.StatusBadRequest)
+		return
+	}
+
+	code := req.PostForm.Get("code")
+
+	p.lock.Lock()
+	authCode, ok := p.codes[code]
+	delete(p.codes, code)
+	p.lock.Unlock()
+
+	if !ok || authCode.redirectURI != req.PostForm.Get("redirect_uri") {
+		http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	idToken := p.SignIDToken(jwt.Claims{
+		Issuer:   p.URL(),
+		Subject:  "test-subject",
+		Audience: jwt.Audience{p.ClientID},
+		Expiry:   jwt.NewNumericDate(now.Add(5 * time.Minute)),
+		IssuedAt: jwt.NewNumericDate(now),
+	}, authCode.nonce)
+
+	writeTestJSON(w, &tokenResponse{
+		AccessToken: "test-access-token",
+		IDToken:     idToken,
+		TokenType:   "Bearer",
+	})
+}
+
+func writeTestJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -1,11 +1,13 @@
 package nomad
 
 import (
+	"strings"
 	"time"
 
 	metrics "github.com/armon/go-metrics"
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
@@ -31,10 +33,71 @@ func (s *Server) ResolveToken(secretID string) (*acl.ACL, error) {
 		return nil, err
 	}
 
+	// Workload identities are signed JWTs rather than UUIDs, and are
+	// resolved to the implicit policy of the workload.
+	if isWorkloadIdentity(secretID) {
+		return s.resolveWorkloadIdentity(snap, secretID)
+	}
+
 	// Resolve the ACL
 	return resolveTokenFromSnapshotCache(snap, s.aclCache, secretID)
 }
 
+// isWorkloadIdentity returns whether the secret looks like a JWT, and
+// therefore may be a workload identity rather than an ACL token secret ID.
+func isWorkloadIdentity(secretID string) bool {
+	return secretID != "" && !helper.IsUUID(secretID) && strings.Count(secretID, ".") == 2
+}
+
+// resolveWorkloadIdentity verifies the workload identity and returns an ACL
+// object which grants read access to the variables of the workload. The
+// identity is only valid while its allocation is running.
+func (s *Server) resolveWorkloadIdentity(snap *state.StateSnapshot, token string) (*acl.ACL, error) {
+	claims, err := s.encrypter.VerifyClaim(token)
+	if err != nil {
+		s.logger.Debug("failed to verify workload identity", "error", err)
+		return nil, structs.ErrTokenNotFound
+	}
+
+	alloc, err := snap.AllocByID(nil, claims.AllocationID)
+	if err != nil {
+		return nil, err
+	}
+	if alloc == nil || alloc.TerminalStatus() {
+		return nil, structs.ErrTokenExpired
+	}
+
+	return acl.NewACL(false, []*acl.Policy{workloadIdentityPolicy(claims)})
+}
+
+// workloadIdentityPolicy returns the implicit policy granted to a workload
+// identity. Workloads may read the variables of their job, task group and
+// task.
+func workloadIdentityPolicy(claims *structs.IdentityClaims) *acl.Policy {
+	capabilities := []string{acl.VariablesCapabilityRead, acl.VariablesCapabilityList}
+	paths := []string{
+		"nomad/jobs",
+		"nomad/jobs/" + claims.JobID,
+		"nomad/jobs/" + claims.JobID + "/" + claims.TaskGroup,
+		"nomad/jobs/" + claims.JobID + "/" + claims.TaskGroup + "/" + claims.TaskName,
+	}
+
+	pathPolicies := make([]*acl.VariablesPathPolicy, 0, len(paths))
+	for _, path := range paths {
+		pathPolicies = append(pathPolicies, &acl.VariablesPathPolicy{
+			PathSpec:     path,
+			Capabilities: capabilities,
+		})
+	}
+
+	return &acl.Policy{
+		Namespaces: []*acl.NamespacePolicy{{
+			Name:      claims.Namespace,
+			Variables: &acl.VariablesPolicy{Paths: pathPolicies},
+		}},
+	}
+}
+
 // resolveTokenFromSnapshotCache is used to resolve an ACL object from a snapshot of state,
 // using a cache to avoid parsing and ACL construction when possible. It is split from resolveToken
 // to simplify testing.
@@ -54,6 +117,9 @@ func resolveTokenFromSnapshotCache(snap *state.StateSnapshot, cache *lru.TwoQueu
 		if token == nil {
 			return nil, structs.ErrTokenNotFound
 		}
+		if token.IsExpired(time.Now().UTC()) {
+			return nil, structs.ErrTokenExpired
+		}
 	}
 
 	// Check if this is a management token
@@ -61,9 +127,15 @@ func resolveTokenFromSnapshotCache(snap *state.StateSnapshot, cache *lru.TwoQueu
 		return acl.ManagementACL, nil
 	}
 
-	// Get all associated policies
-	policies := make([]*structs.ACLPolicy, 0, len(token.Policies))
-	for _, policyName := range token.Policies {
+	// Get all associated policies, including those granted via the roles
+	// linked to the token.
+	policyNames, err := resolveTokenPolicyNames(snap, token)
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make([]*structs.ACLPolicy, 0, len(policyNames))
+	for _, policyName := range policyNames {
 		policy, err := snap.ACLPolicyByName(nil, policyName)
 		if err != nil {
 			return nil, err
@@ -85,6 +157,42 @@ func resolveTokenFromSnapshotCache(snap *state.StateSnapshot, cache *lru.TwoQueu
 	return aclObj, nil
 }
 
+// resolveTokenPolicyNames returns the names of all policies granted to the
+// token, either directly or via the ACL roles linked to the token. Roles which
+// no longer exist are ignored, since they don't grant any more privilege.
+func resolveTokenPolicyNames(snap *state.StateSnapshot, token *structs.ACLToken) ([]string, error) {
+	if len(token.Roles) == 0 {
+		return token.Policies, nil
+	}
+
+	names := make([]string, 0, len(token.Policies))
+	seen := make(map[string]struct{}, len(token.Policies))
+	add := func(name string) {
+		if _, ok := seen[name]; ok {
+			return
+		}
+		seen[name] = struct{}{}
+		names = append(names, name)
+	}
+
+	for _, policyName := range token.Policies {
+		add(policyName)
+	}
+	for _, roleLink := range token.Roles {
+		role, err := snap.GetACLRoleByID(nil, roleLink.ID)
+		if err != nil {
+			return nil, err
+		}
+		if role == nil {
+			continue
+		}
+		for _, policyLink := range role.Policies {
+			add(policyLink.Name)
+		}
+	}
+	return names, nil
+}
+
 // ResolveSecretToken is used to translate an ACL Token Secret ID into
 // an ACLToken object, nil if ACLs are disabled, or an error.
 func (s *Server) ResolveSecretToken(secretID string) (*structs.ACLToken, error) {
@@ -114,6 +222,9 @@ func (s *Server) ResolveSecretToken(secretID string) (*structs.ACLToken, error)
 		if token == nil {
 			return nil, structs.ErrTokenNotFound
 		}
+		if token.IsExpired(time.Now().UTC()) {
+			return nil, structs.ErrTokenExpired
+		}
 	}
 
 	return token, nil
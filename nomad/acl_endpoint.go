@@ -1,6 +1,7 @@
 package nomad
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -15,6 +16,8 @@ import (
 	policy "github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/lib/auth"
+	"github.com/hashicorp/nomad/lib/auth/oidc"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/state/paginator"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -29,6 +32,10 @@ const (
 	// aclBootstrapReset is the file name to create in the data dir. It's only contents
 	// should be the reset index
 	aclBootstrapReset = "acl-bootstrap-reset"
+
+	// aclOIDCRequestTimeout is the timeout used for requests made to OIDC
+	// providers while performing a login.
+	aclOIDCRequestTimeout = 30 * time.Second
 )
 
 // ACL endpoint is used for manipulating ACL tokens and policies
@@ -232,14 +239,10 @@ func (a *ACL) GetPolicy(args *structs.ACLPolicySpecificRequest, reply *structs.S
 			return structs.ErrTokenNotFound
 		}
 
-		found := false
-		for _, p := range token.Policies {
-			if p == args.Name {
-				found = true
-				break
-			}
+		found, err := a.tokenPolicySubset(token, []string{args.Name})
+		if err != nil {
+			return err
 		}
-
 		if !found {
 			return structs.ErrPermissionDenied
 		}
@@ -292,6 +295,34 @@ func (a *ACL) requestACLToken(secretID string) (*structs.ACLToken, error) {
 	return snap.ACLTokenBySecretID(nil, secretID)
 }
 
+// tokenPolicySubset checks if the given set of policies is a subset of those
+// granted to the token, either directly or via its linked ACL roles.
+func (a *ACL) tokenPolicySubset(token *structs.ACLToken, policies []string) (bool, error) {
+	if token.Type == structs.ACLManagementToken {
+		return true, nil
+	}
+
+	snap, err := a.srv.fsm.State().Snapshot()
+	if err != nil {
+		return false, err
+	}
+	policyNames, err := resolveTokenPolicyNames(snap, token)
+	if err != nil {
+		return false, err
+	}
+
+	granted := make(map[string]struct{}, len(policyNames))
+	for _, policyName := range policyNames {
+		granted[policyName] = struct{}{}
+	}
+	for _, policyName := range policies {
+		if _, ok := granted[policyName]; !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
 // GetPolicies is used to get a set of policies
 func (a *ACL) GetPolicies(args *structs.ACLPolicySetRequest, reply *structs.ACLPolicySetResponse) error {
 	if !a.srv.config.ACLEnabled {
@@ -313,7 +344,9 @@ func (a *ACL) GetPolicies(args *structs.ACLPolicySetRequest, reply *structs.ACLP
 	if token == nil {
 		return structs.ErrTokenNotFound
 	}
-	if token.Type != structs.ACLManagementToken && !token.PolicySubset(args.Names) {
+	if subset, err := a.tokenPolicySubset(token, args.Names); err != nil {
+		return err
+	} else if !subset {
 		return structs.ErrPermissionDenied
 	}
 
@@ -517,12 +550,26 @@ func (a *ACL) UpsertTokens(args *structs.ACLTokenUpsertRequest, reply *structs.A
 			return structs.NewErrRPCCodedf(400, "token %d invalid: %v", idx, err)
 		}
 
+		// Ensure the linked ACL roles exist, converting any links made using
+		// the role name to the role ID.
+		if err := normalizeTokenRoleLinks(state, token); err != nil {
+			return structs.NewErrRPCCodedf(400, "token %d invalid: %v", idx, err)
+		}
+
 		// Generate an accessor and secret ID if new
 		if token.AccessorID == "" {
 			token.AccessorID = uuid.Generate()
 			token.SecretID = uuid.Generate()
 			token.CreateTime = time.Now().UTC()
 
+			// Compute the expiration time from any TTL and ensure it
+			// falls within the bounds set by the server configuration.
+			token.Canonicalize()
+			if err := token.ValidateExpiration(
+				a.srv.config.ACLTokenMinExpirationTTL, a.srv.config.ACLTokenMaxExpirationTTL); err != nil {
+				return structs.NewErrRPCCodedf(400, "token %d invalid: %v", idx, err)
+			}
+
 		} else {
 			// Verify the token exists
 			out, err := state.ACLTokenByAccessorID(nil, token.AccessorID)
@@ -537,6 +584,15 @@ func (a *ACL) UpsertTokens(args *structs.ACLTokenUpsertRequest, reply *structs.A
 			if token.Global != out.Global {
 				return structs.NewErrRPCCodedf(400, "cannot toggle global mode of %s", token.AccessorID)
 			}
+
+			// Cannot change the expiration of an existing token
+			if token.ExpirationTTL != 0 && token.ExpirationTTL != out.ExpirationTTL {
+				return structs.NewErrRPCCodedf(400, "cannot update expiration of %s", token.AccessorID)
+			}
+			if token.HasExpirationTime() &&
+				(!out.HasExpirationTime() || !token.ExpirationTime.Equal(*out.ExpirationTime)) {
+				return structs.NewErrRPCCodedf(400, "cannot update expiration of %s", token.AccessorID)
+			}
 		}
 
 		// Compute the token hash
@@ -560,6 +616,9 @@ func (a *ACL) UpsertTokens(args *structs.ACLTokenUpsertRequest, reply *structs.A
 		if err != nil {
 			return structs.NewErrRPCCodedf(400, "token lookup failed: %v", err)
 		}
+		if out, err = populateTokenRoleLinks(nil, &state.StateStore, out); err != nil {
+			return err
+		}
 		reply.Tokens = append(reply.Tokens, out)
 	}
 
@@ -568,6 +627,89 @@ func (a *ACL) UpsertTokens(args *structs.ACLTokenUpsertRequest, reply *structs.A
 	return nil
 }
 
+// normalizeTokenRoleLinks ensures each ACL role linked to the token exists.
+// Links may be made using either the role ID or name, but only the ID is
+// stored, so that renaming a role does not affect the tokens linked to it.
+func normalizeTokenRoleLinks(snap *state.StateSnapshot, token *structs.ACLToken) error {
+	if len(token.Roles) == 0 {
+		return nil
+	}
+
+	roleLinks := make([]*structs.ACLTokenRoleLink, 0, len(token.Roles))
+	seen := make(map[string]struct{}, len(token.Roles))
+
+	for _, roleLink := range token.Roles {
+		var (
+			role *structs.ACLRole
+			err  error
+		)
+		switch {
+		case roleLink.ID != "":
+			role, err = snap.GetACLRoleByID(nil, roleLink.ID)
+		case roleLink.Name != "":
+			role, err = snap.GetACLRoleByName(nil, roleLink.Name)
+		default:
+			return fmt.Errorf("role link must specify an ID or name")
+		}
+		if err != nil {
+			return err
+		}
+		if role == nil {
+			return fmt.Errorf("cannot find role %s%s", roleLink.ID, roleLink.Name)
+		}
+
+		if _, ok := seen[role.ID]; ok {
+			continue
+		}
+		seen[role.ID] = struct{}{}
+		roleLinks = append(roleLinks, &structs.ACLTokenRoleLink{ID: role.ID})
+	}
+
+	token.Roles = roleLinks
+	return nil
+}
+
+// populateTokenRoleLinks returns the token with the names of its linked ACL
+// roles set from state. Links to roles which have since been deleted are
+// removed. The token is copied if modified, so objects held within state are
+// never altered.
+func populateTokenRoleLinks(ws memdb.WatchSet, store *state.StateStore, token *structs.ACLToken) (*structs.ACLToken, error) {
+	if token == nil || len(token.Roles) == 0 {
+		return token, nil
+	}
+
+	roleLinks, err := aclTokenRoleLinks(ws, store, token.Roles)
+	if err != nil {
+		return nil, err
+	}
+
+	token = token.Copy()
+	token.Roles = roleLinks
+	return token, nil
+}
+
+// aclTokenRoleLinks returns a new set of role links with the names set to the
+// current name of each linked ACL role. Links to roles which no longer exist
+// are dropped.
+func aclTokenRoleLinks(ws memdb.WatchSet, store *state.StateStore, links []*structs.ACLTokenRoleLink) ([]*structs.ACLTokenRoleLink, error) {
+	if len(links) == 0 {
+		return links, nil
+	}
+
+	out := make([]*structs.ACLTokenRoleLink, 0, len(links))
+	for _, link := range links {
+		role, err := store.GetACLRoleByID(ws, link.ID)
+		if err != nil {
+			return nil, err
+		}
+		if role == nil {
+			continue
+		}
+		out = append(out, &structs.ACLTokenRoleLink{ID: role.ID, Name: role.Name})
+	}
+	return out, nil
+}
+
 // DeleteTokens is used to delete tokens
 func (a *ACL) DeleteTokens(args *structs.ACLTokenDeleteRequest, reply *structs.GenericResponse) error {
 	// Ensure ACLs are enabled, and always flow modification requests to the authoritative region
@@ -703,7 +845,13 @@ func (a *ACL) ListTokens(args *structs.ACLTokenListRequest, reply *structs.ACLTo
 			paginator, err := paginator.NewPaginator(iter, tokenizer, nil, args.QueryOptions,
 				func(raw interface{}) error {
 					token := raw.(*structs.ACLToken)
-					tokens = append(tokens, token.Stub())
+					stub := token.Stub()
+					roles, err := aclTokenRoleLinks(ws, state, token.Roles)
+					if err != nil {
+						return err
+					}
+					stub.Roles = roles
+					tokens = append(tokens, stub)
 					return nil
 				})
 			if err != nil {
@@ -777,6 +925,9 @@ func (a *ACL) GetToken(args *structs.ACLTokenSpecificRequest, reply *structs.Sin
 			}
 
 			// Setup the output
+			if out, err = populateTokenRoleLinks(ws, state, out); err != nil {
+				return err
+			}
 			reply.Token = out
 			if out != nil {
 				reply.Index = out.ModifyIndex
@@ -825,6 +976,9 @@ func (a *ACL) GetTokens(args *structs.ACLTokenSetRequest, reply *structs.ACLToke
 					return err
 				}
 				if out != nil {
+					if out, err = populateTokenRoleLinks(ws, state, out); err != nil {
+						return err
+					}
 					reply.Tokens[out.AccessorID] = out
 				}
 			}
@@ -1022,3 +1176,1275 @@ func (a *ACL) ExpireOneTimeTokens(args *structs.OneTimeTokenExpireRequest, reply
 	reply.Index = index
 	return nil
 }
+
+// UpsertRoles is used to create or update a set of ACL roles. Roles are
+// global and therefore all writes are forwarded to the authoritative region.
+func (a *ACL) UpsertRoles(
+	args *structs.ACLRolesUpsertRequest,
+	reply *structs.ACLRolesUpsertResponse) error {
+
+	// Only allow operators to upsert ACL roles when ACLs are enabled.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	// This endpoint always forwards to the authoritative region as ACL roles
+	// are global.
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward(structs.ACLUpsertRolesRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "upsert_roles"}, time.Now())
+
+	// Only tokens with management level permissions can create ACL roles.
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of roles.
+	if len(args.ACLRoles) == 0 {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, "must specify as least one role")
+	}
+
+	// Snapshot the state so we can perform lookups against the ID and policy
+	// links if needed.
+	stateSnapshot, err := a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	// Validate each role.
+	for idx, role := range args.ACLRoles {
+
+		// An ACL role must have an ID when being updated, which must already
+		// exist. New roles have their ID generated during canonicalization.
+		if role.ID != "" {
+			existing, err := stateSnapshot.GetACLRoleByID(nil, role.ID)
+			if err != nil {
+				return structs.NewErrRPCCodedf(http.StatusInternalServerError, "role lookup failed: %v", err)
+			}
+			if existing == nil {
+				return structs.NewErrRPCCodedf(http.StatusNotFound, "cannot find role %s", role.ID)
+			}
+		}
+
+		// Canonicalize and validate the role, generating the ID if it is
+		// being created.
+		role.Canonicalize()
+		if err := role.Validate(); err != nil {
+			return structs.NewErrRPCCodedf(http.StatusBadRequest, "role %d invalid: %v", idx, err)
+		}
+
+		// Ensure the role name is not in use by a different role.
+		existingName, err := stateSnapshot.GetACLRoleByName(nil, role.Name)
+		if err != nil {
+			return structs.NewErrRPCCodedf(http.StatusInternalServerError, "role lookup failed: %v", err)
+		}
+		if existingName != nil && existingName.ID != role.ID {
+			return structs.NewErrRPCCodedf(http.StatusBadRequest, "role with name %s already exists", role.Name)
+		}
+
+		// Ensure the policies linked to the role exist.
+		if err := stateSnapshot.ValidateACLRolePolicyLinks(role); err != nil {
+			return structs.NewErrRPCCodedf(http.StatusBadRequest, "role %d invalid: %v", idx, err)
+		}
+
+		// Compute the role hash.
+		role.SetHash()
+	}
+
+	// Update via Raft.
+	out, index, err := a.srv.raftApply(structs.ACLRolesUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Check if the FSM response, which is an interface, contains an error.
+	if err, ok := out.(error); ok && err != nil {
+		return err
+	}
+
+	// Populate the response. We do a lookup against the state to pick up the
+	// proper create / modify indexes.
+	stateSnapshot, err = a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	for _, role := range args.ACLRoles {
+		lookupACLRole, err := stateSnapshot.GetACLRoleByID(nil, role.ID)
+		if err != nil {
+			return structs.NewErrRPCCodedf(http.StatusInternalServerError, "role lookup failed: %v", err)
+		}
+		reply.ACLRoles = append(reply.ACLRoles, lookupACLRole)
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// DeleteRolesByID is used to batch delete ACL roles using the ID as the
+// deletion key.
+func (a *ACL) DeleteRolesByID(
+	args *structs.ACLRolesDeleteByIDRequest,
+	reply *structs.ACLRolesDeleteByIDResponse) error {
+
+	// Only allow operators to delete ACL roles when ACLs are enabled.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	// This endpoint always forwards to the authoritative region as ACL roles
+	// are global.
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward(structs.ACLDeleteRolesByIDRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "delete_roles"}, time.Now())
+
+	// Only tokens with management level permissions can delete ACL roles.
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of roles.
+	if len(args.ACLRoleIDs) == 0 {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, "must specify as least one role")
+	}
+
+	// Update via Raft.
+	out, index, err := a.srv.raftApply(structs.ACLRolesDeleteByIDRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Check if the FSM response, which is an interface, contains an error.
+	if err, ok := out.(error); ok && err != nil {
+		if err.Error() == "ACL role not found" {
+			return structs.NewErrRPCCoded(http.StatusNotFound, err.Error())
+		}
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// ListRoles is used to list ACL roles within state. If not prefix is supplied,
+// all ACL roles are listed, otherwise a prefix search is performed on the ACL
+// role ID. Tokens without management privileges are only able to list the
+// roles linked to the token.
+func (a *ACL) ListRoles(
+	args *structs.ACLRolesListRequest,
+	reply *structs.ACLRolesListResponse) error {
+
+	// Only allow operators to list ACL roles when ACLs are enabled.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	if done, err := a.srv.forward(structs.ACLListRolesRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "list_roles"}, time.Now())
+
+	// Resolve the token and determine which roles it is able to read.
+	roleIDs, mgmt, err := a.tokenACLRoleIDs(args.AuthToken)
+	if err != nil {
+		return err
+	}
+
+	// Set up and return the blocking query.
+	return a.srv.blockingRPC(&blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
+
+			var (
+				err  error
+				iter memdb.ResultIterator
+			)
+
+			// If the operator supplied a prefix, perform a prefix search.
+			// Otherwise, list all ACL roles in state.
+			switch args.QueryOptions.Prefix {
+			case "":
+				iter, err = stateStore.GetACLRoles(ws)
+			default:
+				iter, err = stateStore.GetACLRoleByIDPrefix(ws, args.QueryOptions.Prefix)
+			}
+			if err != nil {
+				return err
+			}
+
+			// Iterate all the results returned from state and convert them
+			// to the stub format, only including those the token may read.
+			var stubs []*structs.ACLRoleListStub
+
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				role := raw.(*structs.ACLRole)
+				if _, ok := roleIDs[role.ID]; !mgmt && !ok {
+					continue
+				}
+				stubs = append(stubs, role.Stub())
+			}
+
+			// Populate the response, ensuring we return a non-nil list.
+			if stubs == nil {
+				stubs = []*structs.ACLRoleListStub{}
+			}
+			reply.ACLRoles = stubs
+
+			// Use the index table to populate the query meta as we have no
+			// way of tracking the max index on deletes.
+			return a.srv.setReplyQueryMeta(stateStore, state.TableACLRoles, &reply.QueryMeta)
+		},
+	})
+}
+
+// GetRolesByID is used to get a set of ACL Roles as defined by their ID. This
+// endpoint is used by the replication process and Nomad agent client token
+// resolution. Tokens without management privileges are only able to read the
+// roles linked to the token.
+func (a *ACL) GetRolesByID(args *structs.ACLRolesByIDRequest, reply *structs.ACLRolesByIDResponse) error {
+
+	// This endpoint is only used by the replication process which is only
+	// running on ACL enabled clusters, so this check should never be
+	// triggered.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	if done, err := a.srv.forward(structs.ACLGetRolesByIDRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_roles_id"}, time.Now())
+
+	// Resolve the token and determine which roles it is able to read.
+	roleIDs, mgmt, err := a.tokenACLRoleIDs(args.AuthToken)
+	if err != nil {
+		return err
+	}
+	if !mgmt {
+		for _, roleID := range args.ACLRoleIDs {
+			if _, ok := roleIDs[roleID]; !ok {
+				return structs.ErrPermissionDenied
+			}
+		}
+	}
+
+	// Set up and return the blocking query.
+	return a.srv.blockingRPC(&blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
+
+			// Instantiate the output map to the correct maximum length.
+			reply.ACLRoles = make(map[string]*structs.ACLRole, len(args.ACLRoleIDs))
+
+			// Look for the ACL role and add this to our mapping if we have
+			// found it.
+			for _, roleID := range args.ACLRoleIDs {
+				out, err := stateStore.GetACLRoleByID(ws, roleID)
+				if err != nil {
+					return err
+				}
+				if out != nil {
+					reply.ACLRoles[out.ID] = out
+				}
+			}
+
+			// Use the index table to populate the query meta as we have no
+			// way of tracking the max index on deletes.
+			return a.srv.setReplyQueryMeta(stateStore, state.TableACLRoles, &reply.QueryMeta)
+		},
+	})
+}
+
+// GetRoleByID is used to look up an individual ACL role using its ID.
+func (a *ACL) GetRoleByID(
+	args *structs.ACLRoleByIDRequest,
+	reply *structs.ACLRoleByIDResponse) error {
+
+	// Only allow operators to read an ACL role when ACLs are enabled.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	if done, err := a.srv.forward(structs.ACLGetRoleByIDRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_role_id"}, time.Now())
+
+	// Resolve the token and determine which roles it is able to read.
+	roleIDs, mgmt, err := a.tokenACLRoleIDs(args.AuthToken)
+	if err != nil {
+		return err
+	}
+	if _, ok := roleIDs[args.RoleID]; !mgmt && !ok {
+		return structs.ErrPermissionDenied
+	}
+
+	// Set up and return the blocking query.
+	return a.srv.blockingRPC(&blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
+
+			// Perform a lookup for the ACL role.
+			out, err := stateStore.GetACLRoleByID(ws, args.RoleID)
+			if err != nil {
+				return err
+			}
+
+			// Set the index correctly depending on whether the ACL role was
+			// found.
+			switch out {
+			case nil:
+				index, err := stateStore.Index(state.TableACLRoles)
+				if err != nil {
+					return err
+				}
+				reply.Index = index
+			default:
+				reply.Index = out.ModifyIndex
+			}
+
+			// We didn't encounter an error looking up the index; set the ACL
+			// role on the reply and exit successfully.
+			reply.ACLRole = out
+			return nil
+		},
+	})
+}
+
+// GetRoleByName is used to look up an individual ACL role using its name.
+func (a *ACL) GetRoleByName(
+	args *structs.ACLRoleByNameRequest,
+	reply *structs.ACLRoleByNameResponse) error {
+
+	// Only allow operators to read an ACL role when ACLs are enabled.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	if done, err := a.srv.forward(structs.ACLGetRoleByNameRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_role_name"}, time.Now())
+
+	// Resolve the token and determine which roles it is able to read.
+	roleIDs, mgmt, err := a.tokenACLRoleIDs(args.AuthToken)
+	if err != nil {
+		return err
+	}
+
+	// Set up and return the blocking query.
+	return a.srv.blockingRPC(&blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
+
+			// Perform a lookup for the ACL role.
+			out, err := stateStore.GetACLRoleByName(ws, args.RoleName)
+			if err != nil {
+				return err
+			}
+
+			// Set the index correctly depending on whether the ACL role was
+			// found.
+			switch out {
+			case nil:
+				// Only management tokens are able to block on roles which do
+				// not exist, otherwise the existence of a role would be
+				// leaked.
+				if !mgmt {
+					return structs.ErrPermissionDenied
+				}
+				index, err := stateStore.Index(state.TableACLRoles)
+				if err != nil {
+					return err
+				}
+				reply.Index = index
+			default:
+				if _, ok := roleIDs[out.ID]; !mgmt && !ok {
+					return structs.ErrPermissionDenied
+				}
+				reply.Index = out.ModifyIndex
+			}
+
+			// We didn't encounter an error looking up the index; set the ACL
+			// role on the reply and exit successfully.
+			reply.ACLRole = out
+			return nil
+		},
+	})
+}
+
+// tokenACLRoleIDs resolves the token identified by the secret ID and returns
+// the set of ACL role IDs linked to it. The returned boolean indicates the
+// token has management privileges, in which case it is able to read all ACL
+// roles.
+func (a *ACL) tokenACLRoleIDs(secretID string) (map[string]struct{}, bool, error) {
+
+	// The leader ACL is used by the replication process and is treated as a
+	// management token.
+	if leaderACL := a.srv.getLeaderAcl(); leaderACL != "" && secretID == leaderACL {
+		return nil, true, nil
+	}
+
+	token, err := a.requestACLToken(secretID)
+	if err != nil {
+		return nil, false, err
+	}
+	if token == nil {
+		return nil, false, structs.ErrTokenNotFound
+	}
+	if token.Type == structs.ACLManagementToken {
+		return nil, true, nil
+	}
+
+	roleIDs := make(map[string]struct{}, len(token.Roles))
+	for _, roleLink := range token.Roles {
+		roleIDs[roleLink.ID] = struct{}{}
+	}
+	return roleIDs, false, nil
+}
+
+// UpsertAuthMethods is used to create or update a set of auth methods. Auth
+// methods are global and therefore all writes are forwarded to the
+// authoritative region.
+func (a *ACL) UpsertAuthMethods(
+	args *structs.ACLAuthMethodUpsertRequest,
+	reply *structs.ACLAuthMethodUpsertResponse) error {
+
+	// Only allow operators to upsert auth methods when ACLs are enabled.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	// This endpoint always forwards to the authoritative region as auth
+	// methods are global.
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward(structs.ACLUpsertAuthMethodsRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "upsert_auth_methods"}, time.Now())
+
+	// Only tokens with management level permissions can create auth methods.
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of auth methods.
+	if len(args.AuthMethods) == 0 {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, "must specify as least one auth method")
+	}
+
+	// Snapshot the state so we can perform lookups against the default auth
+	// method.
+	stateSnapshot, err := a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	// Track the default auth method within the request, so that we can
+	// reject requests which attempt to set more than one.
+	var defaultMethod string
+
+	// Validate each auth method.
+	for idx, authMethod := range args.AuthMethods {
+
+		authMethod.Canonicalize()
+		if err := authMethod.Validate(); err != nil {
+			return structs.NewErrRPCCodedf(http.StatusBadRequest, "auth method %d invalid: %v", idx, err)
+		}
+
+		// Only a single auth method can be the default.
+		if authMethod.Default {
+			if defaultMethod != "" && defaultMethod != authMethod.Name {
+				return structs.NewErrRPCCoded(http.StatusBadRequest, "only one auth method can be the default")
+			}
+			defaultMethod = authMethod.Name
+
+			existingDefault, err := stateSnapshot.GetDefaultACLAuthMethod(nil)
+			if err != nil {
+				return structs.NewErrRPCCodedf(http.StatusInternalServerError, "auth method lookup failed: %v", err)
+			}
+			if existingDefault != nil && existingDefault.Name != authMethod.Name {
+				return structs.NewErrRPCCodedf(http.StatusBadRequest,
+					"default auth method already exists: %s", existingDefault.Name)
+			}
+		}
+
+		// Compute the auth method hash.
+		authMethod.SetHash()
+	}
+
+	// Update via Raft.
+	out, index, err := a.srv.raftApply(structs.ACLAuthMethodsUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Check if the FSM response, which is an interface, contains an error.
+	if err, ok := out.(error); ok && err != nil {
+		return err
+	}
+
+	// Populate the response. We do a lookup against the state to pick up the
+	// proper create / modify indexes.
+	stateSnapshot, err = a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	for _, method := range args.AuthMethods {
+		lookupAuthMethod, err := stateSnapshot.GetACLAuthMethodByName(nil, method.Name)
+		if err != nil {
+			return structs.NewErrRPCCodedf(http.StatusInternalServerError, "auth method lookup failed: %v", err)
+		}
+		reply.AuthMethods = append(reply.AuthMethods, lookupAuthMethod)
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// DeleteAuthMethods is used to batch delete auth methods using their names.
+// Any binding rules linked to the deleted auth methods are also deleted.
+func (a *ACL) DeleteAuthMethods(
+	args *structs.ACLAuthMethodDeleteRequest,
+	reply *structs.ACLAuthMethodDeleteResponse) error {
+
+	// Only allow operators to delete auth methods when ACLs are enabled.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	// This endpoint always forwards to the authoritative region as auth
+	// methods are global.
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward(structs.ACLDeleteAuthMethodsRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "delete_auth_methods"}, time.Now())
+
+	// Only tokens with management level permissions can delete auth methods.
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of auth methods.
+	if len(args.Names) == 0 {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, "must specify as least one auth method")
+	}
+
+	// Update via Raft.
+	out, index, err := a.srv.raftApply(structs.ACLAuthMethodsDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Check if the FSM response, which is an interface, contains an error.
+	if err, ok := out.(error); ok && err != nil {
+		if err.Error() == "ACL auth method not found" {
+			return structs.NewErrRPCCoded(http.StatusNotFound, err.Error())
+		}
+		return err
+	}
+
+	// Remove the cached OIDC providers of the deleted auth methods.
+	for _, name := range args.Names {
+		a.srv.oidcProviderCache.Delete(name)
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// ListAuthMethods is used to list the auth methods within state. Auth methods
+// are needed to log in, so they can be listed without an ACL token; the stubs
+// do not include the auth method configuration.
+func (a *ACL) ListAuthMethods(
+	args *structs.ACLAuthMethodListRequest,
+	reply *structs.ACLAuthMethodListResponse) error {
+
+	// Only allow operators to list auth methods when ACLs are enabled.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	if done, err := a.srv.forward(structs.ACLListAuthMethodsRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "list_auth_methods"}, time.Now())
+
+	// Set up and return the blocking query.
+	return a.srv.blockingRPC(&blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
+
+			iter, err := stateStore.GetACLAuthMethods(ws)
+			if err != nil {
+				return err
+			}
+
+			// Iterate all the results returned from state and convert them
+			// to the stub format.
+			stubs := []*structs.ACLAuthMethodStub{}
+
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				method := raw.(*structs.ACLAuthMethod)
+				stubs = append(stubs, method.Stub())
+			}
+			reply.AuthMethods = stubs
+
+			// Use the index table to populate the query meta as we have no
+			// way of tracking the max index on deletes.
+			return a.srv.setReplyQueryMeta(stateStore, state.TableACLAuthMethods, &reply.QueryMeta)
+		},
+	})
+}
+
+// GetAuthMethod is used to look up an individual auth method using its name.
+// The auth method contains the OIDC client secret, so management permissions
+// are required.
+func (a *ACL) GetAuthMethod(
+	args *structs.ACLAuthMethodGetRequest,
+	reply *structs.ACLAuthMethodGetResponse) error {
+
+	// Only allow operators to read an auth method when ACLs are enabled.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	if done, err := a.srv.forward(structs.ACLGetAuthMethodRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_auth_method_name"}, time.Now())
+
+	// Only tokens with management level permissions can read auth methods.
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Set up and return the blocking query.
+	return a.srv.blockingRPC(&blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
+
+			// Perform a lookup for the auth method.
+			out, err := stateStore.GetACLAuthMethodByName(ws, args.MethodName)
+			if err != nil {
+				return err
+			}
+
+			// Set the index correctly depending on whether the auth method
+			// was found.
+			switch out {
+			case nil:
+				index, err := stateStore.Index(state.TableACLAuthMethods)
+				if err != nil {
+					return err
+				}
+				reply.Index = index
+			default:
+				reply.Index = out.ModifyIndex
+			}
+
+			reply.AuthMethod = out
+			return nil
+		},
+	})
+}
+
+// GetAuthMethods is used to get a set of auth methods using their names. This
+// endpoint is used by the replication process.
+func (a *ACL) GetAuthMethods(
+	args *structs.ACLAuthMethodsGetRequest,
+	reply *structs.ACLAuthMethodsGetResponse) error {
+
+	// This endpoint is only used by the replication process which is only
+	// running on ACL enabled clusters, so this check should never be
+	// triggered.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	if done, err := a.srv.forward(structs.ACLGetAuthMethodsRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_auth_methods"}, time.Now())
+
+	// Only tokens with management level permissions can read auth methods.
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Set up and return the blocking query.
+	return a.srv.blockingRPC(&blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
+
+			// Instantiate the output map to the correct maximum length.
+			reply.AuthMethods = make(map[string]*structs.ACLAuthMethod, len(args.Names))
+
+			// Look for the auth method and add this to our mapping if we have
+			// found it.
+			for _, name := range args.Names {
+				out, err := stateStore.GetACLAuthMethodByName(ws, name)
+				if err != nil {
+					return err
+				}
+				if out != nil {
+					reply.AuthMethods[out.Name] = out
+				}
+			}
+
+			// Use the index table to populate the query meta as we have no
+			// way of tracking the max index on deletes.
+			return a.srv.setReplyQueryMeta(stateStore, state.TableACLAuthMethods, &reply.QueryMeta)
+		},
+	})
+}
+
+// UpsertBindingRules is used to create or update a set of ACL binding rules.
+// Binding rules are global and therefore all writes are forwarded to the
+// authoritative region.
+func (a *ACL) UpsertBindingRules(
+	args *structs.ACLBindingRulesUpsertRequest,
+	reply *structs.ACLBindingRulesUpsertResponse) error {
+
+	// Only allow operators to upsert binding rules when ACLs are enabled.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	// This endpoint always forwards to the authoritative region as binding
+	// rules are global.
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward(structs.ACLUpsertBindingRulesRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "upsert_binding_rules"}, time.Now())
+
+	// Only tokens with management level permissions can create binding
+	// rules.
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of binding rules.
+	if len(args.ACLBindingRules) == 0 {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, "must specify as least one binding rule")
+	}
+
+	// Snapshot the state so we can perform lookups against the ID and auth
+	// method links.
+	stateSnapshot, err := a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	// Validate each binding rule.
+	for idx, rule := range args.ACLBindingRules {
+
+		// A binding rule must have an ID when being updated, which must
+		// already exist. New rules have their ID generated during
+		// canonicalization.
+		if rule.ID != "" {
+			existing, err := stateSnapshot.GetACLBindingRule(nil, rule.ID)
+			if err != nil {
+				return structs.NewErrRPCCodedf(http.StatusInternalServerError, "binding rule lookup failed: %v", err)
+			}
+			if existing == nil {
+				return structs.NewErrRPCCodedf(http.StatusNotFound, "cannot find binding rule %s", rule.ID)
+			}
+		}
+
+		// Canonicalize and validate the binding rule, generating the ID if it
+		// is being created.
+		rule.Canonicalize()
+		if err := rule.Validate(); err != nil {
+			return structs.NewErrRPCCodedf(http.StatusBadRequest, "binding rule %d invalid: %v", idx, err)
+		}
+		if err := auth.ValidateSelector(rule.Selector); err != nil {
+			return structs.NewErrRPCCodedf(http.StatusBadRequest, "binding rule %d invalid: %v", idx, err)
+		}
+
+		// Ensure the auth method linked to the binding rule exists.
+		method, err := stateSnapshot.GetACLAuthMethodByName(nil, rule.AuthMethod)
+		if err != nil {
+			return structs.NewErrRPCCodedf(http.StatusInternalServerError, "auth method lookup failed: %v", err)
+		}
+		if method == nil {
+			return structs.NewErrRPCCodedf(http.StatusBadRequest,
+				"binding rule %d invalid: ACL auth method not found", idx)
+		}
+
+		// Compute the binding rule hash.
+		rule.SetHash()
+	}
+
+	// Update via Raft.
+	out, index, err := a.srv.raftApply(structs.ACLBindingRulesUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Check if the FSM response, which is an interface, contains an error.
+	if err, ok := out.(error); ok && err != nil {
+		return err
+	}
+
+	// Populate the response. We do a lookup against the state to pick up the
+	// proper create / modify indexes.
+	stateSnapshot, err = a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	for _, rule := range args.ACLBindingRules {
+		lookupRule, err := stateSnapshot.GetACLBindingRule(nil, rule.ID)
+		if err != nil {
+			return structs.NewErrRPCCodedf(http.StatusInternalServerError, "binding rule lookup failed: %v", err)
+		}
+		reply.ACLBindingRules = append(reply.ACLBindingRules, lookupRule)
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// DeleteBindingRules is used to batch delete ACL binding rules using their
+// IDs.
+func (a *ACL) DeleteBindingRules(
+	args *structs.ACLBindingRulesDeleteRequest,
+	reply *structs.ACLBindingRulesDeleteResponse) error {
+
+	// Only allow operators to delete binding rules when ACLs are enabled.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	// This endpoint always forwards to the authoritative region as binding
+	// rules are global.
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward(structs.ACLDeleteBindingRulesRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "delete_binding_rules"}, time.Now())
+
+	// Only tokens with management level permissions can delete binding
+	// rules.
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of binding rules.
+	if len(args.ACLBindingRuleIDs) == 0 {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, "must specify as least one binding rule")
+	}
+
+	// Update via Raft.
+	out, index, err := a.srv.raftApply(structs.ACLBindingRulesDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Check if the FSM response, which is an interface, contains an error.
+	if err, ok := out.(error); ok && err != nil {
+		if err.Error() == "ACL binding rule not found" {
+			return structs.NewErrRPCCoded(http.StatusNotFound, err.Error())
+		}
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// ListBindingRules is used to list the ACL binding rules within state.
+func (a *ACL) ListBindingRules(
+	args *structs.ACLBindingRulesListRequest,
+	reply *structs.ACLBindingRulesListResponse) error {
+
+	// Only allow operators to list binding rules when ACLs are enabled.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	if done, err := a.srv.forward(structs.ACLListBindingRulesRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "list_binding_rules"}, time.Now())
+
+	// Only tokens with management level permissions can list binding rules.
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Set up and return the blocking query.
+	return a.srv.blockingRPC(&blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
+
+			iter, err := stateStore.GetACLBindingRules(ws)
+			if err != nil {
+				return err
+			}
+
+			// Iterate all the results returned from state and convert them
+			// to the stub format.
+			stubs := []*structs.ACLBindingRuleListStub{}
+
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				rule := raw.(*structs.ACLBindingRule)
+				stubs = append(stubs, rule.Stub())
+			}
+			reply.ACLBindingRules = stubs
+
+			// Use the index table to populate the query meta as we have no
+			// way of tracking the max index on deletes.
+			return a.srv.setReplyQueryMeta(stateStore, state.TableACLBindingRules, &reply.QueryMeta)
+		},
+	})
+}
+
+// GetBindingRules is used to get a set of ACL binding rules using their IDs.
+// This endpoint is used by the replication process.
+func (a *ACL) GetBindingRules(
+	args *structs.ACLBindingRulesRequest,
+	reply *structs.ACLBindingRulesResponse) error {
+
+	// This endpoint is only used by the replication process which is only
+	// running on ACL enabled clusters, so this check should never be
+	// triggered.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	if done, err := a.srv.forward(structs.ACLGetBindingRulesRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_binding_rules"}, time.Now())
+
+	// Only tokens with management level permissions can read binding rules.
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Set up and return the blocking query.
+	return a.srv.blockingRPC(&blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
+
+			// Instantiate the output map to the correct maximum length.
+			reply.ACLBindingRules = make(map[string]*structs.ACLBindingRule, len(args.ACLBindingRuleIDs))
+
+			// Look for the binding rule and add this to our mapping if we
+			// have found it.
+			for _, ruleID := range args.ACLBindingRuleIDs {
+				out, err := stateStore.GetACLBindingRule(ws, ruleID)
+				if err != nil {
+					return err
+				}
+				if out != nil {
+					reply.ACLBindingRules[out.ID] = out
+				}
+			}
+
+			// Use the index table to populate the query meta as we have no
+			// way of tracking the max index on deletes.
+			return a.srv.setReplyQueryMeta(stateStore, state.TableACLBindingRules, &reply.QueryMeta)
+		},
+	})
+}
+
+// GetBindingRule is used to look up an individual ACL binding rule using its
+// ID.
+func (a *ACL) GetBindingRule(
+	args *structs.ACLBindingRuleRequest,
+	reply *structs.ACLBindingRuleResponse) error {
+
+	// Only allow operators to read a binding rule when ACLs are enabled.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	if done, err := a.srv.forward(structs.ACLGetBindingRuleRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_binding_rule"}, time.Now())
+
+	// Only tokens with management level permissions can read binding rules.
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Set up and return the blocking query.
+	return a.srv.blockingRPC(&blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
+
+			// Perform a lookup for the binding rule.
+			out, err := stateStore.GetACLBindingRule(ws, args.ACLBindingRuleID)
+			if err != nil {
+				return err
+			}
+
+			// Set the index correctly depending on whether the binding rule
+			// was found.
+			switch out {
+			case nil:
+				index, err := stateStore.Index(state.TableACLBindingRules)
+				if err != nil {
+					return err
+				}
+				reply.Index = index
+			default:
+				reply.Index = out.ModifyIndex
+			}
+
+			reply.ACLBindingRule = out
+			return nil
+		},
+	})
+}
+
+// OIDCAuthURL starts the OIDC login workflow. The response URL should be used
+// by the caller to authenticate the user. Once this has been completed,
+// OIDCCompleteAuth can be used for the remainder of the workflow.
+func (a *ACL) OIDCAuthURL(args *structs.ACLOIDCAuthURLRequest, reply *structs.ACLOIDCAuthURLResponse) error {
+
+	// The OIDC flow can only be used when the Nomad cluster has ACL enabled.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	// Perform the initial forwarding within the region. This ensures we
+	// respect stale queries.
+	if done, err := a.srv.forward(structs.ACLOIDCAuthURLRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "oidc_auth_url"}, time.Now())
+
+	// Validate the request arguments to ensure it contains all the data it
+	// needs.
+	if err := args.Validate(); err != nil {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "invalid OIDC auth-url request: %v", err)
+	}
+
+	authMethod, err := a.oidcAuthMethod(args.AuthMethodName)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(a.srv.shutdownCtx, aclOIDCRequestTimeout)
+	defer cancel()
+
+	provider, err := a.srv.oidcProviderCache.Get(ctx, authMethod)
+	if err != nil {
+		return structs.NewErrRPCCodedf(http.StatusInternalServerError, "failed to create OIDC provider: %v", err)
+	}
+
+	state, err := oidc.NewID()
+	if err != nil {
+		return err
+	}
+
+	authURL, err := provider.AuthURL(state, args.ClientNonce, args.RedirectURI)
+	if err != nil {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "failed to generate auth URL: %v", err)
+	}
+
+	reply.AuthURL = authURL
+	return nil
+}
+
+// OIDCCompleteAuth complete the OIDC login workflow. It will exchange the OIDC
+// provider token for a Nomad ACL token, using the configured ACL role and
+// policy claims to provide authorization.
+func (a *ACL) OIDCCompleteAuth(
+	args *structs.ACLOIDCCompleteAuthRequest, reply *structs.ACLOIDCCompleteAuthResponse) error {
+
+	// The OIDC flow can only be used when the Nomad cluster has ACL enabled.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	// Perform the initial forwarding within the region. This ensures we
+	// respect stale queries.
+	if done, err := a.srv.forward(structs.ACLOIDCCompleteAuthRPCMethod, args, args, reply); done {
+		return err
+	}
+
+	// Validate the request arguments to ensure it contains all the data it
+	// needs.
+	if err := args.Validate(); err != nil {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "invalid OIDC complete-auth request: %v", err)
+	}
+
+	authMethod, err := a.oidcAuthMethod(args.AuthMethodName)
+	if err != nil {
+		return err
+	}
+
+	// Global tokens can only be created within the authoritative region, so
+	// forward the request there if needed. Auth methods are replicated, so
+	// the authoritative region is able to complete the login.
+	if authMethod.TokenLocalityIsGlobal() && a.srv.Region() != a.srv.config.AuthoritativeRegion {
+		args.Region = a.srv.config.AuthoritativeRegion
+		if done, err := a.srv.forward(structs.ACLOIDCCompleteAuthRPCMethod, args, args, reply); done {
+			return err
+		}
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "oidc_complete_auth"}, time.Now())
+
+	ctx, cancel := context.WithTimeout(a.srv.shutdownCtx, aclOIDCRequestTimeout)
+	defer cancel()
+
+	provider, err := a.srv.oidcProviderCache.Get(ctx, authMethod)
+	if err != nil {
+		return structs.NewErrRPCCodedf(http.StatusInternalServerError, "failed to create OIDC provider: %v", err)
+	}
+
+	// Exchange the code for an ID token and verify it. The nonce ensures the
+	// code was generated for the login attempt of the caller.
+	claims, err := provider.Exchange(ctx, args.Code, args.ClientNonce, args.RedirectURI)
+	if err != nil {
+		a.logger.Debug("failed to complete OIDC login", "auth_method", authMethod.Name, "error", err)
+		return structs.NewErrRPCCodedf(http.StatusUnauthorized, "failed to exchange token with provider: %v", err)
+	}
+
+	identity, err := auth.NewIdentity(authMethod.Config, claims)
+	if err != nil {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "failed to map claims: %v", err)
+	}
+
+	stateSnapshot, err := a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	// Evaluate the binding rules of the auth method, to determine the roles
+	// and policies the token should be linked to.
+	bindings, err := auth.NewBinder(stateSnapshot).Bind(authMethod, identity)
+	if err != nil {
+		return err
+	}
+	if bindings.None() {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, "no role or policy bindings matched")
+	}
+
+	now := time.Now().UTC()
+	expirationTime := now.Add(authMethod.MaxTokenTTL)
+
+	token := &structs.ACLToken{
+		AccessorID:     uuid.Generate(),
+		SecretID:       uuid.Generate(),
+		Name:           "OIDC-" + authMethod.Name,
+		Type:           structs.ACLClientToken,
+		Policies:       bindings.Policies,
+		Roles:          bindings.Roles,
+		Global:         authMethod.TokenLocalityIsGlobal(),
+		CreateTime:     now,
+		ExpirationTime: &expirationTime,
+		ExpirationTTL:  authMethod.MaxTokenTTL,
+	}
+	if bindings.Management {
+		token.Type = structs.ACLManagementToken
+	}
+	if err := token.Validate(); err != nil {
+		return structs.NewErrRPCCodedf(http.StatusInternalServerError, "generated token invalid: %v", err)
+	}
+	token.SetHash()
+
+	// Write the token to state via Raft.
+	tokenUpsertRequest := structs.ACLTokenUpsertRequest{
+		Tokens:       []*structs.ACLToken{token},
+		WriteRequest: args.WriteRequest,
+	}
+	_, index, err := a.srv.raftApply(structs.ACLTokenUpsertRequestType, &tokenUpsertRequest)
+	if err != nil {
+		return err
+	}
+
+	// Populate the response. We do a lookup against the state to pick up the
+	// proper create / modify indexes.
+	stateSnapshot, err = a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	out, err := stateSnapshot.ACLTokenByAccessorID(nil, token.AccessorID)
+	if err != nil {
+		return structs.NewErrRPCCodedf(http.StatusInternalServerError, "token lookup failed: %v", err)
+	}
+	if out, err = populateTokenRoleLinks(nil, &stateSnapshot.StateStore, out); err != nil {
+		return err
+	}
+
+	reply.ACLToken = out
+	reply.Index = index
+	return nil
+}
+
+// oidcAuthMethod looks up the named auth method, ensuring it exists and is an
+// OIDC auth method.
+func (a *ACL) oidcAuthMethod(name string) (*structs.ACLAuthMethod, error) {
+	stateSnapshot, err := a.srv.State().Snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	authMethod, err := stateSnapshot.GetACLAuthMethodByName(nil, name)
+	if err != nil {
+		return nil, err
+	}
+	if authMethod == nil {
+		return nil, structs.NewErrRPCCodedf(http.StatusNotFound, "auth-method %q not found", name)
+	}
+	if authMethod.Type != structs.ACLAuthMethodTypeOIDC {
+		return nil, structs.NewErrRPCCodedf(http.StatusBadRequest,
+			"auth-method %q is not an OIDC auth-method", name)
+	}
+	return authMethod, nil
+}
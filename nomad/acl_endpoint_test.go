@@ -11,6 +11,7 @@ import (
 	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/lib/auth/oidc"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
@@ -1507,6 +1508,56 @@ func TestACLEndpoint_UpsertTokens(t *testing.T) {
 	assert.Equal(t, created, out)
 }
 
+func TestACLEndpoint_UpsertTokens_Expiration(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create a token with a TTL within the configured bounds.
+	tk := mock.ACLToken()
+	tk.AccessorID = ""
+	tk.ExpirationTTL = time.Hour
+
+	req := &structs.ACLTokenUpsertRequest{
+		Tokens: []*structs.ACLToken{tk},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.ACLTokenUpsertResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.UpsertTokens", req, &resp))
+	require.Len(t, resp.Tokens, 1)
+
+	created := resp.Tokens[0]
+	require.NotNil(t, created.ExpirationTime)
+	require.Equal(t, time.Hour, created.ExpirationTTL)
+	require.True(t, created.CreateTime.Add(time.Hour).Equal(*created.ExpirationTime))
+
+	// Attempting to change the expiration of the token must fail.
+	update := created.Copy()
+	update.ExpirationTTL = 2 * time.Hour
+	req.Tokens = []*structs.ACLToken{update}
+	err := msgpackrpc.CallWithCodec(codec, "ACL.UpsertTokens", req, &resp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "cannot update expiration")
+
+	// Tokens with a TTL outside the configured bounds are rejected.
+	for _, ttl := range []time.Duration{time.Second, 1000 * time.Hour} {
+		tk := mock.ACLToken()
+		tk.AccessorID = ""
+		tk.ExpirationTTL = ttl
+		req.Tokens = []*structs.ACLToken{tk}
+
+		err := msgpackrpc.CallWithCodec(codec, "ACL.UpsertTokens", req, &resp)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "expiration time cannot be")
+	}
+}
+
 func TestACLEndpoint_UpsertTokens_Invalid(t *testing.T) {
 	ci.Parallel(t)
 
@@ -1685,3 +1736,746 @@ func TestACLEndpoint_OneTimeToken(t *testing.T) {
 	require.NoError(t, err)
 	require.Nil(t, ott)
 }
+
+// upsertTestACLRolePolicies creates the ACL policies linked to by
+// mock.ACLRole within the server state.
+func upsertTestACLRolePolicies(t *testing.T, srv *Server, index uint64) {
+	policy1 := mock.ACLPolicy()
+	policy1.Name = "mocked-test-policy-1"
+	policy2 := mock.ACLPolicy()
+	policy2.Name = "mocked-test-policy-2"
+	require.NoError(t, srv.fsm.State().UpsertACLPolicies(
+		structs.MsgTypeTestSetup, index, []*structs.ACLPolicy{policy1, policy2}))
+}
+
+func TestACLEndpoint_UpsertTokens_Roles(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, rootACLToken, testServerCleanup := TestACLServer(t, nil)
+	defer testServerCleanup()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	// Create the ACL role the tokens will link to.
+	upsertTestACLRolePolicies(t, testServer, 10)
+	aclRole := mock.ACLRole()
+	require.NoError(t, testServer.fsm.State().UpsertACLRoles(
+		structs.MsgTypeTestSetup, 20, []*structs.ACLRole{aclRole}, false))
+
+	// Create a token linked to the role via its name, ensuring the link is
+	// stored using the role ID.
+	token := mock.ACLToken()
+	token.AccessorID = ""
+	token.Policies = nil
+	token.Roles = []*structs.ACLTokenRoleLink{{Name: aclRole.Name}}
+
+	req := &structs.ACLTokenUpsertRequest{
+		Tokens: []*structs.ACLToken{token},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: rootACLToken.SecretID,
+		},
+	}
+	var resp structs.ACLTokenUpsertResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.UpsertTokens", req, &resp))
+	require.Len(t, resp.Tokens, 1)
+	require.Equal(t, []*structs.ACLTokenRoleLink{{ID: aclRole.ID, Name: aclRole.Name}}, resp.Tokens[0].Roles)
+
+	out, err := testServer.fsm.State().ACLTokenByAccessorID(nil, resp.Tokens[0].AccessorID)
+	require.NoError(t, err)
+	require.Equal(t, []*structs.ACLTokenRoleLink{{ID: aclRole.ID}}, out.Roles)
+
+	// The token should be able to read the policies linked via the role.
+	getReq := &structs.ACLPolicySetRequest{
+		Names: []string{"mocked-test-policy-1", "mocked-test-policy-2"},
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: out.SecretID,
+		},
+	}
+	var getResp structs.ACLPolicySetResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.GetPolicies", getReq, &getResp))
+	require.Len(t, getResp.Policies, 2)
+
+	// Creating a token which links to a role that does not exist should fail.
+	invalidToken := mock.ACLToken()
+	invalidToken.AccessorID = ""
+	invalidToken.Roles = []*structs.ACLTokenRoleLink{{ID: uuid.Generate()}}
+	req.Tokens = []*structs.ACLToken{invalidToken}
+	err = msgpackrpc.CallWithCodec(codec, "ACL.UpsertTokens", req, &resp)
+	require.ErrorContains(t, err, "cannot find role")
+}
+
+func TestACLEndpoint_UpsertRoles(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, rootACLToken, testServerCleanup := TestACLServer(t, nil)
+	defer testServerCleanup()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	// Create the register request.
+	aclRole1 := mock.ACLRole()
+	aclRole1.ID = ""
+
+	aclRoleReq1 := &structs.ACLRolesUpsertRequest{
+		ACLRoles: []*structs.ACLRole{aclRole1},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: rootACLToken.SecretID,
+		},
+	}
+
+	// Try and upsert the role without the linked policies existing, which
+	// should fail.
+	var aclRoleResp1 structs.ACLRolesUpsertResponse
+	err := msgpackrpc.CallWithCodec(codec, structs.ACLUpsertRolesRPCMethod, aclRoleReq1, &aclRoleResp1)
+	require.ErrorContains(t, err, "policy not found")
+
+	// Create the policies and try again.
+	upsertTestACLRolePolicies(t, testServer, 10)
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLUpsertRolesRPCMethod, aclRoleReq1, &aclRoleResp1))
+	require.Len(t, aclRoleResp1.ACLRoles, 1)
+	require.NotEmpty(t, aclRoleResp1.ACLRoles[0].ID)
+	require.Equal(t, aclRole1.Name, aclRoleResp1.ACLRoles[0].Name)
+
+	// Update the role description using the generated ID.
+	aclRole2 := aclRoleResp1.ACLRoles[0].Copy()
+	aclRole2.Description = "updated-description"
+
+	aclRoleReq2 := &structs.ACLRolesUpsertRequest{
+		ACLRoles: []*structs.ACLRole{aclRole2},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: rootACLToken.SecretID,
+		},
+	}
+	var aclRoleResp2 structs.ACLRolesUpsertResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLUpsertRolesRPCMethod, aclRoleReq2, &aclRoleResp2))
+	require.Len(t, aclRoleResp2.ACLRoles, 1)
+	require.Equal(t, aclRoleResp1.ACLRoles[0].ID, aclRoleResp2.ACLRoles[0].ID)
+	require.Equal(t, "updated-description", aclRoleResp2.ACLRoles[0].Description)
+
+	// Try and create a role with the same name as the existing one.
+	aclRole3 := mock.ACLRole()
+	aclRole3.ID = ""
+	aclRole3.Name = aclRole1.Name
+
+	aclRoleReq3 := &structs.ACLRolesUpsertRequest{
+		ACLRoles: []*structs.ACLRole{aclRole3},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: rootACLToken.SecretID,
+		},
+	}
+	var aclRoleResp3 structs.ACLRolesUpsertResponse
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLUpsertRolesRPCMethod, aclRoleReq3, &aclRoleResp3)
+	require.ErrorContains(t, err, "already exists")
+
+	// Try and update a role which does not exist.
+	aclRole4 := mock.ACLRole()
+	aclRoleReq3.ACLRoles = []*structs.ACLRole{aclRole4}
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLUpsertRolesRPCMethod, aclRoleReq3, &aclRoleResp3)
+	require.ErrorContains(t, err, "cannot find role")
+
+	// Try and create a role using a token without management privileges.
+	aclRoleReq3.AuthToken = uuid.Generate()
+	aclRoleReq3.ACLRoles = []*structs.ACLRole{aclRole3}
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLUpsertRolesRPCMethod, aclRoleReq3, &aclRoleResp3)
+	require.Error(t, err)
+}
+
+func TestACLEndpoint_DeleteRolesByID(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, rootACLToken, testServerCleanup := TestACLServer(t, nil)
+	defer testServerCleanup()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	// Create the policies and roles within state.
+	upsertTestACLRolePolicies(t, testServer, 10)
+	aclRoles := []*structs.ACLRole{mock.ACLRole(), mock.ACLRole()}
+	require.NoError(t, testServer.fsm.State().UpsertACLRoles(
+		structs.MsgTypeTestSetup, 20, aclRoles, false))
+
+	// Delete one of the roles.
+	aclRoleReq := &structs.ACLRolesDeleteByIDRequest{
+		ACLRoleIDs: []string{aclRoles[0].ID},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: rootACLToken.SecretID,
+		},
+	}
+	var aclRoleResp structs.ACLRolesDeleteByIDResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLDeleteRolesByIDRPCMethod, aclRoleReq, &aclRoleResp))
+	require.NotZero(t, aclRoleResp.Index)
+
+	out, err := testServer.fsm.State().GetACLRoleByID(nil, aclRoles[0].ID)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	// Deleting the role again should return a not found error.
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLDeleteRolesByIDRPCMethod, aclRoleReq, &aclRoleResp)
+	require.ErrorContains(t, err, "ACL role not found")
+}
+
+func TestACLEndpoint_ListRoles(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, rootACLToken, testServerCleanup := TestACLServer(t, nil)
+	defer testServerCleanup()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	// Create the policies and roles within state.
+	upsertTestACLRolePolicies(t, testServer, 10)
+	aclRoles := []*structs.ACLRole{mock.ACLRole(), mock.ACLRole()}
+	aclRoles[0].ID = "aaaaaaaa-7bfb-395d-eb95-0685af2176b2"
+	require.NoError(t, testServer.fsm.State().UpsertACLRoles(
+		structs.MsgTypeTestSetup, 20, aclRoles, false))
+
+	// List all the roles using the management token.
+	aclRoleReq := &structs.ACLRolesListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: rootACLToken.SecretID,
+		},
+	}
+	var aclRoleResp structs.ACLRolesListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLListRolesRPCMethod, aclRoleReq, &aclRoleResp))
+	require.Len(t, aclRoleResp.ACLRoles, 2)
+	require.Equal(t, uint64(20), aclRoleResp.Index)
+
+	// List the roles using a prefix.
+	aclRoleReq.Prefix = "aaaaaaaa"
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLListRolesRPCMethod, aclRoleReq, &aclRoleResp))
+	require.Len(t, aclRoleResp.ACLRoles, 1)
+	require.Equal(t, aclRoles[0].ID, aclRoleResp.ACLRoles[0].ID)
+
+	// Create a token which is linked to one of the roles and ensure it is
+	// only able to list that role.
+	token := mock.ACLToken()
+	token.Policies = nil
+	token.Roles = []*structs.ACLTokenRoleLink{{ID: aclRoles[1].ID}}
+	require.NoError(t, testServer.fsm.State().UpsertACLTokens(
+		structs.MsgTypeTestSetup, 30, []*structs.ACLToken{token}))
+
+	aclRoleReq.Prefix = ""
+	aclRoleReq.AuthToken = token.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLListRolesRPCMethod, aclRoleReq, &aclRoleResp))
+	require.Len(t, aclRoleResp.ACLRoles, 1)
+	require.Equal(t, aclRoles[1].ID, aclRoleResp.ACLRoles[0].ID)
+}
+
+func TestACLEndpoint_GetRolesByID(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, rootACLToken, testServerCleanup := TestACLServer(t, nil)
+	defer testServerCleanup()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	// Create the policies and roles within state.
+	upsertTestACLRolePolicies(t, testServer, 10)
+	aclRoles := []*structs.ACLRole{mock.ACLRole(), mock.ACLRole()}
+	require.NoError(t, testServer.fsm.State().UpsertACLRoles(
+		structs.MsgTypeTestSetup, 20, aclRoles, false))
+
+	// Read both roles, including an ID which does not exist.
+	aclRoleReq := &structs.ACLRolesByIDRequest{
+		ACLRoleIDs: []string{aclRoles[0].ID, aclRoles[1].ID, uuid.Generate()},
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: rootACLToken.SecretID,
+		},
+	}
+	var aclRoleResp structs.ACLRolesByIDResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLGetRolesByIDRPCMethod, aclRoleReq, &aclRoleResp))
+	require.Len(t, aclRoleResp.ACLRoles, 2)
+	require.Equal(t, aclRoles[0], aclRoleResp.ACLRoles[aclRoles[0].ID])
+
+	// A token linked to a single role should only be able to read that role.
+	token := mock.ACLToken()
+	token.Policies = nil
+	token.Roles = []*structs.ACLTokenRoleLink{{ID: aclRoles[0].ID}}
+	require.NoError(t, testServer.fsm.State().UpsertACLTokens(
+		structs.MsgTypeTestSetup, 30, []*structs.ACLToken{token}))
+
+	aclRoleReq.AuthToken = token.SecretID
+	err := msgpackrpc.CallWithCodec(codec, structs.ACLGetRolesByIDRPCMethod, aclRoleReq, &aclRoleResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	aclRoleReq.ACLRoleIDs = []string{aclRoles[0].ID}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLGetRolesByIDRPCMethod, aclRoleReq, &aclRoleResp))
+	require.Len(t, aclRoleResp.ACLRoles, 1)
+}
+
+func TestACLEndpoint_GetRoleByID(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, rootACLToken, testServerCleanup := TestACLServer(t, nil)
+	defer testServerCleanup()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	// Create the policies and roles within state.
+	upsertTestACLRolePolicies(t, testServer, 10)
+	aclRoles := []*structs.ACLRole{mock.ACLRole(), mock.ACLRole()}
+	require.NoError(t, testServer.fsm.State().UpsertACLRoles(
+		structs.MsgTypeTestSetup, 20, aclRoles, false))
+
+	// Read a role using the management token.
+	aclRoleReq := &structs.ACLRoleByIDRequest{
+		RoleID: aclRoles[0].ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: rootACLToken.SecretID,
+		},
+	}
+	var aclRoleResp structs.ACLRoleByIDResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLGetRoleByIDRPCMethod, aclRoleReq, &aclRoleResp))
+	require.Equal(t, aclRoles[0], aclRoleResp.ACLRole)
+
+	// Read a role which does not exist.
+	aclRoleReq.RoleID = uuid.Generate()
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLGetRoleByIDRPCMethod, aclRoleReq, &aclRoleResp))
+	require.Nil(t, aclRoleResp.ACLRole)
+	require.Equal(t, uint64(20), aclRoleResp.Index)
+
+	// A token linked to a role can read it, but not other roles.
+	token := mock.ACLToken()
+	token.Policies = nil
+	token.Roles = []*structs.ACLTokenRoleLink{{ID: aclRoles[0].ID}}
+	require.NoError(t, testServer.fsm.State().UpsertACLTokens(
+		structs.MsgTypeTestSetup, 30, []*structs.ACLToken{token}))
+
+	aclRoleReq.AuthToken = token.SecretID
+	aclRoleReq.RoleID = aclRoles[0].ID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLGetRoleByIDRPCMethod, aclRoleReq, &aclRoleResp))
+	require.Equal(t, aclRoles[0], aclRoleResp.ACLRole)
+
+	aclRoleReq.RoleID = aclRoles[1].ID
+	err := msgpackrpc.CallWithCodec(codec, structs.ACLGetRoleByIDRPCMethod, aclRoleReq, &aclRoleResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+}
+
+func TestACLEndpoint_GetRoleByName(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, rootACLToken, testServerCleanup := TestACLServer(t, nil)
+	defer testServerCleanup()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	// Create the policies and roles within state.
+	upsertTestACLRolePolicies(t, testServer, 10)
+	aclRoles := []*structs.ACLRole{mock.ACLRole(), mock.ACLRole()}
+	require.NoError(t, testServer.fsm.State().UpsertACLRoles(
+		structs.MsgTypeTestSetup, 20, aclRoles, false))
+
+	// Read a role using the management token.
+	aclRoleReq := &structs.ACLRoleByNameRequest{
+		RoleName: aclRoles[0].Name,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: rootACLToken.SecretID,
+		},
+	}
+	var aclRoleResp structs.ACLRoleByNameResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLGetRoleByNameRPCMethod, aclRoleReq, &aclRoleResp))
+	require.Equal(t, aclRoles[0], aclRoleResp.ACLRole)
+
+	// Read a role which does not exist.
+	aclRoleReq.RoleName = "not-a-role"
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLGetRoleByNameRPCMethod, aclRoleReq, &aclRoleResp))
+	require.Nil(t, aclRoleResp.ACLRole)
+
+	// A token linked to a role can read it, but not other roles.
+	token := mock.ACLToken()
+	token.Policies = nil
+	token.Roles = []*structs.ACLTokenRoleLink{{ID: aclRoles[0].ID}}
+	require.NoError(t, testServer.fsm.State().UpsertACLTokens(
+		structs.MsgTypeTestSetup, 30, []*structs.ACLToken{token}))
+
+	aclRoleReq.AuthToken = token.SecretID
+	aclRoleReq.RoleName = aclRoles[0].Name
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLGetRoleByNameRPCMethod, aclRoleReq, &aclRoleResp))
+	require.Equal(t, aclRoles[0], aclRoleResp.ACLRole)
+
+	aclRoleReq.RoleName = aclRoles[1].Name
+	err := msgpackrpc.CallWithCodec(codec, structs.ACLGetRoleByNameRPCMethod, aclRoleReq, &aclRoleResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+}
+
+func TestACLEndpoint_UpsertAuthMethods(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, rootACLToken, testServerCleanup := TestACLServer(t, nil)
+	defer testServerCleanup()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	// Create the register request.
+	am1 := mock.ACLAuthMethod()
+	am1.Default = true
+
+	req := &structs.ACLAuthMethodUpsertRequest{
+		AuthMethods: []*structs.ACLAuthMethod{am1},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: rootACLToken.SecretID,
+		},
+	}
+	var resp structs.ACLAuthMethodUpsertResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLUpsertAuthMethodsRPCMethod, req, &resp))
+	require.NotEqual(t, uint64(0), resp.Index)
+	require.Len(t, resp.AuthMethods, 1)
+	require.Equal(t, am1.Name, resp.AuthMethods[0].Name)
+
+	// Check we created the auth method.
+	out, err := testServer.fsm.State().GetACLAuthMethodByName(nil, am1.Name)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.True(t, out.Default)
+
+	// Try to insert another default auth method.
+	am2 := mock.ACLAuthMethod()
+	am2.Default = true
+	req.AuthMethods = []*structs.ACLAuthMethod{am2}
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLUpsertAuthMethodsRPCMethod, req, &resp)
+	require.ErrorContains(t, err, "default auth method already exists")
+
+	// Try to insert an invalid auth method.
+	am3 := mock.ACLAuthMethod()
+	am3.MaxTokenTTL = 0
+	req.AuthMethods = []*structs.ACLAuthMethod{am3}
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLUpsertAuthMethodsRPCMethod, req, &resp)
+	require.ErrorContains(t, err, "max token TTL must be greater than zero")
+
+	// Try to insert an auth method without a management token.
+	req.AuthMethods = []*structs.ACLAuthMethod{mock.ACLAuthMethod()}
+	req.AuthToken = mock.ACLToken().SecretID
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLUpsertAuthMethodsRPCMethod, req, &resp)
+	require.Error(t, err)
+}
+
+func TestACLEndpoint_DeleteAuthMethods(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, rootACLToken, testServerCleanup := TestACLServer(t, nil)
+	defer testServerCleanup()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	// Create the auth method directly within state.
+	am1 := mock.ACLAuthMethod()
+	require.NoError(t, testServer.fsm.State().UpsertACLAuthMethods(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLAuthMethod{am1}))
+
+	req := &structs.ACLAuthMethodDeleteRequest{
+		Names: []string{am1.Name},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: rootACLToken.SecretID,
+		},
+	}
+	var resp structs.ACLAuthMethodDeleteResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLDeleteAuthMethodsRPCMethod, req, &resp))
+	require.NotEqual(t, uint64(0), resp.Index)
+
+	out, err := testServer.fsm.State().GetACLAuthMethodByName(nil, am1.Name)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	// Deleting the auth method again should result in a not found error.
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLDeleteAuthMethodsRPCMethod, req, &resp)
+	require.ErrorContains(t, err, "ACL auth method not found")
+}
+
+func TestACLEndpoint_ListAuthMethods(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, _, testServerCleanup := TestACLServer(t, nil)
+	defer testServerCleanup()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	am1 := mock.ACLAuthMethod()
+	am2 := mock.ACLAuthMethod()
+	require.NoError(t, testServer.fsm.State().UpsertACLAuthMethods(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLAuthMethod{am1, am2}))
+
+	// Listing auth methods does not require a token, so that users are able
+	// to find the auth method to log in with.
+	req := &structs.ACLAuthMethodListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region: "global",
+		},
+	}
+	var resp structs.ACLAuthMethodListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLListAuthMethodsRPCMethod, req, &resp))
+	require.Equal(t, uint64(10), resp.Index)
+	require.Len(t, resp.AuthMethods, 2)
+}
+
+func TestACLEndpoint_GetAuthMethod(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, rootACLToken, testServerCleanup := TestACLServer(t, nil)
+	defer testServerCleanup()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	am1 := mock.ACLAuthMethod()
+	require.NoError(t, testServer.fsm.State().UpsertACLAuthMethods(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLAuthMethod{am1}))
+
+	req := &structs.ACLAuthMethodGetRequest{
+		MethodName: am1.Name,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: rootACLToken.SecretID,
+		},
+	}
+	var resp structs.ACLAuthMethodGetResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLGetAuthMethodRPCMethod, req, &resp))
+	require.Equal(t, uint64(10), resp.Index)
+	require.Equal(t, am1.Name, resp.AuthMethod.Name)
+
+	// Look up an auth method which does not exist.
+	req.MethodName = "not-a-method"
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLGetAuthMethodRPCMethod, req, &resp))
+	require.Nil(t, resp.AuthMethod)
+
+	// Reading an auth method requires a management token.
+	req.AuthToken = ""
+	err := msgpackrpc.CallWithCodec(codec, structs.ACLGetAuthMethodRPCMethod, req, &resp)
+	require.ErrorContains(t, err, structs.ErrPermissionDenied.Error())
+}
+
+func TestACLEndpoint_UpsertBindingRules(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, rootACLToken, testServerCleanup := TestACLServer(t, nil)
+	defer testServerCleanup()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	// Create a binding rule, without the auth method existing.
+	bindingRule := mock.ACLBindingRule()
+	bindingRule.ID = ""
+
+	req := &structs.ACLBindingRulesUpsertRequest{
+		ACLBindingRules: []*structs.ACLBindingRule{bindingRule},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: rootACLToken.SecretID,
+		},
+	}
+	var resp structs.ACLBindingRulesUpsertResponse
+	err := msgpackrpc.CallWithCodec(codec, structs.ACLUpsertBindingRulesRPCMethod, req, &resp)
+	require.ErrorContains(t, err, "ACL auth method not found")
+
+	// Create the auth method and try again.
+	am := mock.ACLAuthMethod()
+	am.Name = bindingRule.AuthMethod
+	require.NoError(t, testServer.fsm.State().UpsertACLAuthMethods(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLAuthMethod{am}))
+
+	bindingRule.ID = ""
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLUpsertBindingRulesRPCMethod, req, &resp))
+	require.Len(t, resp.ACLBindingRules, 1)
+	require.NotEmpty(t, resp.ACLBindingRules[0].ID)
+
+	// Update the binding rule using the generated ID.
+	updatedRule := resp.ACLBindingRules[0].Copy()
+	updatedRule.Description = "updated-description"
+	req.ACLBindingRules = []*structs.ACLBindingRule{updatedRule}
+
+	var resp2 structs.ACLBindingRulesUpsertResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLUpsertBindingRulesRPCMethod, req, &resp2))
+	require.Len(t, resp2.ACLBindingRules, 1)
+	require.Equal(t, resp.ACLBindingRules[0].ID, resp2.ACLBindingRules[0].ID)
+	require.Equal(t, "updated-description", resp2.ACLBindingRules[0].Description)
+
+	// Try and update a binding rule which does not exist.
+	missingRule := mock.ACLBindingRule()
+	req.ACLBindingRules = []*structs.ACLBindingRule{missingRule}
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLUpsertBindingRulesRPCMethod, req, &resp2)
+	require.ErrorContains(t, err, "cannot find binding rule")
+
+	// Try and create a binding rule with an invalid selector.
+	invalidRule := mock.ACLBindingRule()
+	invalidRule.ID = ""
+	invalidRule.AuthMethod = am.Name
+	invalidRule.Selector = "not a valid selector =="
+	req.ACLBindingRules = []*structs.ACLBindingRule{invalidRule}
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLUpsertBindingRulesRPCMethod, req, &resp2)
+	require.ErrorContains(t, err, "invalid selector")
+}
+
+func TestACLEndpoint_DeleteBindingRules(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, rootACLToken, testServerCleanup := TestACLServer(t, nil)
+	defer testServerCleanup()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	bindingRule := mock.ACLBindingRule()
+	require.NoError(t, testServer.fsm.State().UpsertACLBindingRules(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLBindingRule{bindingRule}, true))
+
+	req := &structs.ACLBindingRulesDeleteRequest{
+		ACLBindingRuleIDs: []string{bindingRule.ID},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: rootACLToken.SecretID,
+		},
+	}
+	var resp structs.ACLBindingRulesDeleteResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLDeleteBindingRulesRPCMethod, req, &resp))
+	require.NotEqual(t, uint64(0), resp.Index)
+
+	// Deleting the binding rule again should result in a not found error.
+	err := msgpackrpc.CallWithCodec(codec, structs.ACLDeleteBindingRulesRPCMethod, req, &resp)
+	require.ErrorContains(t, err, "ACL binding rule not found")
+}
+
+func TestACLEndpoint_ListBindingRules(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, rootACLToken, testServerCleanup := TestACLServer(t, nil)
+	defer testServerCleanup()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	rules := []*structs.ACLBindingRule{mock.ACLBindingRule(), mock.ACLBindingRule()}
+	require.NoError(t, testServer.fsm.State().UpsertACLBindingRules(
+		structs.MsgTypeTestSetup, 10, rules, true))
+
+	req := &structs.ACLBindingRulesListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: rootACLToken.SecretID,
+		},
+	}
+	var resp structs.ACLBindingRulesListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLListBindingRulesRPCMethod, req, &resp))
+	require.Equal(t, uint64(10), resp.Index)
+	require.Len(t, resp.ACLBindingRules, 2)
+
+	// Fetch a single binding rule by its ID.
+	getReq := &structs.ACLBindingRuleRequest{
+		ACLBindingRuleID: rules[0].ID,
+		QueryOptions:     req.QueryOptions,
+	}
+	var getResp structs.ACLBindingRuleResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLGetBindingRuleRPCMethod, getReq, &getResp))
+	require.Equal(t, rules[0].ID, getResp.ACLBindingRule.ID)
+
+	// Listing binding rules requires a management token.
+	req.AuthToken = ""
+	err := msgpackrpc.CallWithCodec(codec, structs.ACLListBindingRulesRPCMethod, req, &resp)
+	require.ErrorContains(t, err, structs.ErrPermissionDenied.Error())
+}
+
+func TestACLEndpoint_OIDCLogin(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, _, testServerCleanup := TestACLServer(t, nil)
+	defer testServerCleanup()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	// Start the test OIDC provider and configure the auth method to use it.
+	oidcProvider := oidc.NewTestProvider(t)
+	oidcProvider.SetClaims(map[string]interface{}{
+		"groups": []string{"engineering"},
+	})
+
+	redirectURI := "http://localhost:4649/oidc/callback"
+
+	authMethod := mock.ACLAuthMethod()
+	authMethod.Config.OIDCDiscoveryURL = oidcProvider.URL()
+	authMethod.Config.OIDCClientID = oidcProvider.ClientID
+	authMethod.Config.OIDCClientSecret = oidcProvider.ClientSecret
+	authMethod.Config.BoundAudiences = nil
+	authMethod.Config.DiscoveryCaPem = nil
+	authMethod.Config.SigningAlgs = nil
+	authMethod.Config.AllowedRedirectURIs = []string{redirectURI}
+	authMethod.Config.ClaimMappings = nil
+	authMethod.Config.ListClaimMappings = map[string]string{"groups": "groups"}
+	authMethod.SetHash()
+
+	// Create the policy and binding rule which should be applied to the
+	// generated token.
+	policy := mock.ACLPolicy()
+	bindingRule := mock.ACLBindingRule()
+	bindingRule.AuthMethod = authMethod.Name
+	bindingRule.Selector = "engineering in list.groups"
+	bindingRule.BindType = structs.ACLBindingRuleBindTypePolicy
+	bindingRule.BindName = policy.Name
+	bindingRule.SetHash()
+
+	testState := testServer.fsm.State()
+	require.NoError(t, testState.UpsertACLAuthMethods(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLAuthMethod{authMethod}))
+	require.NoError(t, testState.UpsertACLPolicies(
+		structs.MsgTypeTestSetup, 20, []*structs.ACLPolicy{policy}))
+	require.NoError(t, testState.UpsertACLBindingRules(
+		structs.MsgTypeTestSetup, 30, []*structs.ACLBindingRule{bindingRule}, false))
+
+	// Generate the auth URL. The auth URL request does not need a token.
+	authURLReq := &structs.ACLOIDCAuthURLRequest{
+		AuthMethodName: authMethod.Name,
+		RedirectURI:    redirectURI,
+		ClientNonce:    "test-nonce",
+		WriteRequest:   structs.WriteRequest{Region: "global"},
+	}
+	var authURLResp structs.ACLOIDCAuthURLResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLOIDCAuthURLRPCMethod, authURLReq, &authURLResp))
+	require.True(t, strings.HasPrefix(authURLResp.AuthURL, oidcProvider.URL()))
+
+	// Unknown auth methods should return an error.
+	authURLReq.AuthMethodName = "not-a-method"
+	err := msgpackrpc.CallWithCodec(codec, structs.ACLOIDCAuthURLRPCMethod, authURLReq, &authURLResp)
+	require.ErrorContains(t, err, "not found")
+
+	// Complete the login flow using the code issued by the provider.
+	code, state := oidcProvider.Authorize(authURLResp.AuthURL)
+
+	completeReq := &structs.ACLOIDCCompleteAuthRequest{
+		AuthMethodName: authMethod.Name,
+		ClientNonce:    "test-nonce",
+		State:          state,
+		Code:           code,
+		RedirectURI:    redirectURI,
+		WriteRequest:   structs.WriteRequest{Region: "global"},
+	}
+	var completeResp structs.ACLOIDCCompleteAuthResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLOIDCCompleteAuthRPCMethod, completeReq, &completeResp))
+	require.NotNil(t, completeResp.ACLToken)
+	require.Equal(t, structs.ACLClientToken, completeResp.ACLToken.Type)
+	require.Equal(t, []string{policy.Name}, completeResp.ACLToken.Policies)
+	require.NotNil(t, completeResp.ACLToken.ExpirationTime)
+	require.False(t, completeResp.ACLToken.Global)
+
+	// The generated token should be usable to resolve an ACL object.
+	aclObj, err := testServer.ResolveToken(completeResp.ACLToken.SecretID)
+	require.NoError(t, err)
+	require.NotNil(t, aclObj)
+
+	// The code can only be used once.
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLOIDCCompleteAuthRPCMethod, completeReq, &completeResp)
+	require.Error(t, err)
+
+	// Logins which do not match any binding rules should fail.
+	oidcProvider.SetClaims(map[string]interface{}{"groups": []string{"marketing"}})
+	code, state = oidcProvider.Authorize(authURLResp.AuthURL)
+	completeReq.Code = code
+	completeReq.State = state
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLOIDCCompleteAuthRPCMethod, completeReq, &completeResp)
+	require.ErrorContains(t, err, "no role or policy bindings matched")
+}
@@ -2,6 +2,7 @@ package nomad
 
 import (
 	"testing"
+	"time"
 
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/hashicorp/nomad/acl"
@@ -12,6 +13,7 @@ import (
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestResolveACLToken(t *testing.T) {
@@ -132,3 +134,156 @@ func TestResolveSecretToken(t *testing.T) {
 	}
 
 }
+
+func TestResolveACLToken_Roles(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create mock state store and cache
+	testState := state.TestStateStore(t)
+	cache, err := lru.New2Q(16)
+	require.NoError(t, err)
+
+	// Create the policies which are linked via the ACL role, and a token which
+	// links to the role rather than the policies.
+	policy1 := mock.ACLPolicy()
+	policy1.Name = "mocked-test-policy-1"
+	policy2 := mock.ACLPolicy()
+	policy2.Name = "mocked-test-policy-2"
+	policy2.Rules = `namespace "other" { policy = "read" }`
+	policy2.SetHash()
+	require.NoError(t, testState.UpsertACLPolicies(
+		structs.MsgTypeTestSetup, 100, []*structs.ACLPolicy{policy1, policy2}))
+
+	aclRole := mock.ACLRole()
+	require.NoError(t, testState.UpsertACLRoles(
+		structs.MsgTypeTestSetup, 110, []*structs.ACLRole{aclRole}, false))
+
+	token := mock.ACLToken()
+	token.Policies = nil
+	token.Roles = []*structs.ACLTokenRoleLink{{ID: aclRole.ID}}
+	require.NoError(t, testState.UpsertACLTokens(
+		structs.MsgTypeTestSetup, 120, []*structs.ACLToken{token}))
+
+	snap, err := testState.Snapshot()
+	require.NoError(t, err)
+
+	// Resolve the token and ensure the policies linked via the role are
+	// applied.
+	aclObj, err := resolveTokenFromSnapshotCache(snap, cache, token.SecretID)
+	require.NoError(t, err)
+	require.NotNil(t, aclObj)
+	require.False(t, aclObj.IsManagement())
+	require.True(t, aclObj.AllowNamespaceOperation("default", acl.NamespaceCapabilityListJobs))
+	require.True(t, aclObj.AllowNamespaceOperation("other", acl.NamespaceCapabilityListJobs))
+
+	// Resolve the same token again, which should use the cached value.
+	aclObj2, err := resolveTokenFromSnapshotCache(snap, cache, token.SecretID)
+	require.NoError(t, err)
+	require.Same(t, aclObj, aclObj2)
+
+	// Remove a policy from the role, which should modify the resolved
+	// permissions.
+	updatedRole := aclRole.Copy()
+	updatedRole.Policies = []*structs.ACLRolePolicyLink{{Name: policy1.Name}}
+	updatedRole.SetHash()
+	require.NoError(t, testState.UpsertACLRoles(
+		structs.MsgTypeTestSetup, 130, []*structs.ACLRole{updatedRole}, false))
+
+	snap, err = testState.Snapshot()
+	require.NoError(t, err)
+
+	aclObj3, err := resolveTokenFromSnapshotCache(snap, cache, token.SecretID)
+	require.NoError(t, err)
+	require.True(t, aclObj3.AllowNamespaceOperation("default", acl.NamespaceCapabilityListJobs))
+	require.False(t, aclObj3.AllowNamespaceOperation("other", acl.NamespaceCapabilityListJobs))
+
+	// Delete the role, which should mean the token no longer has any
+	// privileges.
+	require.NoError(t, testState.DeleteACLRolesByID(
+		structs.MsgTypeTestSetup, 140, []string{aclRole.ID}))
+
+	snap, err = testState.Snapshot()
+	require.NoError(t, err)
+
+	aclObj4, err := resolveTokenFromSnapshotCache(snap, cache, token.SecretID)
+	require.NoError(t, err)
+	require.False(t, aclObj4.AllowNamespaceOperation("default", acl.NamespaceCapabilityListJobs))
+}
+
+func TestResolveACLToken_Expired(t *testing.T) {
+	ci.Parallel(t)
+
+	testState := state.TestStateStore(t)
+	cache, err := lru.New2Q(16)
+	require.NoError(t, err)
+
+	// Create a token which has already expired, and one which has not.
+	expiredTime := time.Now().UTC().Add(-time.Minute)
+	expiredToken := mock.ACLToken()
+	expiredToken.ExpirationTime = &expiredTime
+
+	validTime := time.Now().UTC().Add(time.Hour)
+	validToken := mock.ACLManagementToken()
+	validToken.ExpirationTime = &validTime
+
+	require.NoError(t, testState.UpsertACLTokens(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLToken{expiredToken, validToken}))
+
+	snap, err := testState.Snapshot()
+	require.NoError(t, err)
+
+	aclObj, err := resolveTokenFromSnapshotCache(snap, cache, expiredToken.SecretID)
+	require.Equal(t, structs.ErrTokenExpired, err)
+	require.Nil(t, aclObj)
+
+	aclObj, err = resolveTokenFromSnapshotCache(snap, cache, validToken.SecretID)
+	require.NoError(t, err)
+	require.True(t, aclObj.IsManagement())
+}
+
+func TestResolveACLToken_WorkloadIdentity(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, _, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	key, err := structs.NewRootKey(structs.EncryptionAlgorithmAES256GCM)
+	require.NoError(t, err)
+	require.NoError(t, s1.encrypter.AddKey(key))
+
+	alloc := mock.Alloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	require.NoError(t, s1.State().UpsertAllocs(structs.MsgTypeTestSetup, 1000, []*structs.Allocation{alloc}))
+
+	claims := structs.NewIdentityClaims(alloc.Job, alloc, task.Name, time.Now())
+	token, err := s1.encrypter.SignClaims(claims, key.Meta.KeyID)
+	require.NoError(t, err)
+
+	aclObj, err := s1.ResolveToken(token)
+	require.NoError(t, err)
+	require.NotNil(t, aclObj)
+	require.False(t, aclObj.IsManagement())
+
+	// The workload may read the variables of its own job, task group and
+	// task, but nothing else.
+	jobPath := "nomad/jobs/" + alloc.JobID
+	taskPath := jobPath + "/" + alloc.TaskGroup + "/" + task.Name
+	require.True(t, aclObj.AllowVariableOperation(alloc.Namespace, jobPath, acl.VariablesCapabilityRead))
+	require.True(t, aclObj.AllowVariableOperation(alloc.Namespace, taskPath, acl.VariablesCapabilityRead))
+	require.False(t, aclObj.AllowVariableOperation(alloc.Namespace, jobPath, acl.VariablesCapabilityWrite))
+	require.False(t, aclObj.AllowVariableOperation(alloc.Namespace, "nomad/jobs/other", acl.VariablesCapabilityRead))
+	require.False(t, aclObj.AllowVariableOperation("other", jobPath, acl.VariablesCapabilityRead))
+
+	// A tampered token must not resolve.
+	_, err = s1.ResolveToken(token + "x")
+	require.Equal(t, structs.ErrTokenNotFound, err)
+
+	// Once the allocation is terminal, the identity is no longer valid.
+	stopped := alloc.Copy()
+	stopped.DesiredStatus = structs.AllocDesiredStatusStop
+	require.NoError(t, s1.State().UpsertAllocs(structs.MsgTypeTestSetup, 1001, []*structs.Allocation{stopped}))
+
+	_, err = s1.ResolveToken(token)
+	require.Equal(t, structs.ErrTokenExpired, err)
+}
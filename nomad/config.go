@@ -162,6 +162,11 @@ type Config struct {
 	// the user time to inspect the job.
 	JobGCThreshold time.Duration
 
+	// JobMaxSourceSize is the maximum size in bytes of the source a job
+	// version was submitted from. Larger sources are discarded, and a value
+	// of 0 disables storing job sources.
+	JobMaxSourceSize int
+
 	// NodeGCInterval is how often we dispatch a job to GC failed nodes.
 	NodeGCInterval time.Duration
 
@@ -196,6 +201,19 @@ type Config struct {
 	// one-time tokens.
 	OneTimeTokenGCInterval time.Duration
 
+	// ACLTokenExpirationGCInterval is how often we dispatch a job to GC
+	// expired ACL tokens.
+	ACLTokenExpirationGCInterval time.Duration
+
+	// RebalanceInterval is how often we dispatch a job to rebalance the
+	// allocations of the cluster, when enabled in the scheduler
+	// configuration.
+	RebalanceInterval time.Duration
+
+	// ACLTokenExpirationGCThreshold controls how "old" an expired ACL token
+	// must be to be eligible for GC.
+	ACLTokenExpirationGCThreshold time.Duration
+
 	// EvalNackTimeout controls how long we allow a sub-scheduler to
 	// work on an evaluation before we consider it failed and Nack it.
 	// This allows that evaluation to be handed to another sub-scheduler
@@ -270,6 +288,14 @@ type Config struct {
 	// ACLEnabled controls if ACL enforcement and management is enabled.
 	ACLEnabled bool
 
+	// ACLTokenMinExpirationTTL is used to enforce the lowest acceptable value
+	// for ACL token expiration.
+	ACLTokenMinExpirationTTL time.Duration
+
+	// ACLTokenMaxExpirationTTL is used to enforce the highest acceptable
+	// value for ACL token expiration.
+	ACLTokenMaxExpirationTTL time.Duration
+
 	// ReplicationBackoff is how much we backoff when replication errors.
 	// This is a tunable knob for testing primarily.
 	ReplicationBackoff time.Duration
@@ -376,6 +402,7 @@ func DefaultConfig() *Config {
 		EvalGCThreshold:                  1 * time.Hour,
 		JobGCInterval:                    5 * time.Minute,
 		JobGCThreshold:                   4 * time.Hour,
+		JobMaxSourceSize:                 1e6,
 		NodeGCInterval:                   5 * time.Minute,
 		NodeGCThreshold:                  24 * time.Hour,
 		DeploymentGCInterval:             5 * time.Minute,
@@ -385,6 +412,9 @@ func DefaultConfig() *Config {
 		CSIVolumeClaimGCInterval:         5 * time.Minute,
 		CSIVolumeClaimGCThreshold:        5 * time.Minute,
 		OneTimeTokenGCInterval:           10 * time.Minute,
+		ACLTokenExpirationGCInterval:     5 * time.Minute,
+		ACLTokenExpirationGCThreshold:    1 * time.Hour,
+		RebalanceInterval:                5 * time.Minute,
 		EvalNackTimeout:                  60 * time.Second,
 		EvalDeliveryLimit:                3,
 		EvalNackInitialReenqueueDelay:    1 * time.Second,
@@ -401,6 +431,8 @@ func DefaultConfig() *Config {
 		StatsCollectionInterval:          1 * time.Minute,
 		TLSConfig:                        &config.TLSConfig{},
 		ReplicationBackoff:               30 * time.Second,
+		ACLTokenMinExpirationTTL:         1 * time.Minute,
+		ACLTokenMaxExpirationTTL:         24 * time.Hour,
 		SentinelGCInterval:               30 * time.Second,
 		LicenseConfig:                    &LicenseConfig{},
 		EnableEventBroker:                true,
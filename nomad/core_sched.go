@@ -9,6 +9,8 @@ import (
 	log "github.com/hashicorp/go-hclog"
 	memdb "github.com/hashicorp/go-memdb"
 	version "github.com/hashicorp/go-version"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/scheduler"
@@ -58,6 +60,12 @@ func (c *CoreScheduler) Process(eval *structs.Evaluation) error {
 		return c.csiPluginGC(eval)
 	case structs.CoreJobOneTimeTokenGC:
 		return c.expiredOneTimeTokenGC(eval)
+	case structs.CoreJobLocalTokenExpiredGC:
+		return c.expiredACLTokenGC(eval, false)
+	case structs.CoreJobGlobalTokenExpiredGC:
+		return c.expiredACLTokenGC(eval, true)
+	case structs.CoreJobRebalance:
+		return c.rebalance(eval)
 	case structs.CoreJobForceGC:
 		return c.forceGC(eval)
 	default:
@@ -85,6 +93,12 @@ func (c *CoreScheduler) forceGC(eval *structs.Evaluation) error {
 	if err := c.expiredOneTimeTokenGC(eval); err != nil {
 		return err
 	}
+	if err := c.expiredACLTokenGC(eval, false); err != nil {
+		return err
+	}
+	if err := c.expiredACLTokenGC(eval, true); err != nil {
+		return err
+	}
 	// Node GC must occur after the others to ensure the allocations are
 	// cleared.
 	return c.nodeGC(eval)
@@ -780,6 +794,164 @@ func (c *CoreScheduler) expiredOneTimeTokenGC(eval *structs.Evaluation) error {
 	return c.srv.RPC("ACL.ExpireOneTimeTokens", req, &structs.GenericResponse{})
 }
 
+// expiredACLTokenGC handles running the garbage collector for expired ACL
+// tokens. It can be used for both local and global tokens and includes
+// behaviour to account for periodic and user actioned garbage collection
+// invocations.
+func (c *CoreScheduler) expiredACLTokenGC(eval *structs.Evaluation, global bool) error {
+	// If ACLs are not enabled, we do not need to continue and should exit
+	// early. This is not an error condition as callers can blindly call this
+	// function without checking the configuration.
+	if !c.srv.config.ACLEnabled {
+		return nil
+	}
+
+	// If the function has been triggered for global tokens, but we are not
+	// the authoritative region, we should exit. Global tokens are replicated
+	// from the authoritative region and must be removed there.
+	if global && c.srv.config.AuthoritativeRegion != c.srv.Region() {
+		return nil
+	}
+
+	scope := "local"
+	if global {
+		scope = "global"
+	}
+
+	// Get the threshold to use for GC
+	oldThreshold := c.getThreshold(eval, scope+" expired ACL tokens",
+		"acl_token_expiration_gc_threshold", c.srv.config.ACLTokenExpirationGCThreshold)
+
+	iter, err := c.snap.ACLTokensByExpired(global)
+	if err != nil {
+		return err
+	}
+
+	var (
+		expiredAccessorIDs []string
+		num                int
+	)
+
+	// The memdb iterator contains all tokens which include an expiration
+	// time, sorted so that the earliest expiration comes first. Once we
+	// reach a token which has not yet expired we can stop.
+	now := time.Now().UTC()
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		token := raw.(*structs.ACLToken)
+
+		if !token.IsExpired(now) {
+			break
+		}
+
+		// Ignore tokens which are newer than the threshold
+		if token.CreateIndex > oldThreshold {
+			continue
+		}
+
+		expiredAccessorIDs = append(expiredAccessorIDs, token.AccessorID)
+
+		num++
+		if num >= structs.ACLMaxExpiredBatchSize {
+			break
+		}
+	}
+
+	// There is no need to call the RPC endpoint if we do not have any tokens
+	// to delete.
+	if len(expiredAccessorIDs) < 1 {
+		return nil
+	}
+
+	c.logger.Debug(fmt.Sprintf("%s expired ACL tokens GC found eligible tokens", scope),
+		"num", len(expiredAccessorIDs))
+
+	req := &structs.ACLTokenDeleteRequest{
+		AccessorIDs: expiredAccessorIDs,
+		WriteRequest: structs.WriteRequest{
+			Region:    c.srv.Region(),
+			AuthToken: eval.LeaderACL,
+		},
+	}
+	return c.srv.RPC("ACL.DeleteTokens", req, &structs.GenericResponse{})
+}
+
+// rebalance migrates the allocations placed on skewed spreads, overly dense
+// nodes and nodes violating their affinities, when enabled in the scheduler
+// configuration.
+func (c *CoreScheduler) rebalance(eval *structs.Evaluation) error {
+	_, schedConfig, err := c.snap.SchedulerConfig()
+	if err != nil {
+		return err
+	}
+	if schedConfig == nil || !schedConfig.RebalanceConfig.Enabled {
+		return nil
+	}
+
+	migrations, err := scheduler.FindRebalanceMigrations(c.logger, c.snap, schedConfig)
+	if err != nil {
+		return err
+	}
+	if len(migrations) == 0 {
+		return nil
+	}
+
+	c.logger.Debug("rebalance found allocations to migrate", "num", len(migrations))
+
+	req, err := rebalanceTransitionRequest(c.snap, migrations)
+	if err != nil {
+		return err
+	}
+	req.WriteRequest = structs.WriteRequest{
+		Region:    c.srv.Region(),
+		AuthToken: eval.LeaderACL,
+	}
+	return c.srv.RPC("Alloc.UpdateDesiredTransition", req, &structs.GenericResponse{})
+}
+
+// rebalanceTransitionRequest returns the request marking the allocations
+// for migration, along with an evaluation for each of their jobs.
+func rebalanceTransitionRequest(snap *state.StateSnapshot, migrations []*structs.RebalanceMigration) (*structs.AllocUpdateDesiredTransitionRequest, error) {
+	req := &structs.AllocUpdateDesiredTransitionRequest{
+		Allocs: make(map[string]*structs.DesiredTransition, len(migrations)),
+	}
+
+	now := time.Now().UTC().UnixNano()
+	jobs := make(map[structs.NamespacedID]struct{})
+	for _, migration := range migrations {
+		req.Allocs[migration.AllocID] = &structs.DesiredTransition{
+			Migrate: helper.BoolToPtr(true),
+		}
+
+		jobID := structs.NamespacedID{Namespace: migration.Namespace, ID: migration.JobID}
+		if _, ok := jobs[jobID]; ok {
+			continue
+		}
+		jobs[jobID] = struct{}{}
+
+		job, err := snap.JobByID(nil, migration.Namespace, migration.JobID)
+		if err != nil {
+			return nil, err
+		}
+		if job == nil {
+			return nil, fmt.Errorf("job %q not found", migration.JobID)
+		}
+		req.Evals = append(req.Evals, &structs.Evaluation{
+			ID:             uuid.Generate(),
+			Namespace:      job.Namespace,
+			Priority:       job.Priority,
+			Type:           job.Type,
+			TriggeredBy:    structs.EvalTriggerRebalance,
+			JobID:          job.ID,
+			JobModifyIndex: job.ModifyIndex,
+			Status:         structs.EvalStatusPending,
+			CreateTime:     now,
+			ModifyTime:     now,
+		})
+	}
+	return req, nil
+}
+
 // getThreshold returns the index threshold for determining whether an
 // object is old enough to GC
 func (c *CoreScheduler) getThreshold(eval *structs.Evaluation, objectName, configName string, configThreshold time.Duration) uint64 {
@@ -8,6 +8,7 @@ import (
 	memdb "github.com/hashicorp/go-memdb"
 	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
 	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/state"
@@ -2243,6 +2244,77 @@ func TestCoreScheduler_CSIPluginGC(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestCoreScheduler_ExpiredACLTokenGC(t *testing.T) {
+	ci.Parallel(t)
+
+	testFn := func(t *testing.T, global bool) {
+		srv, _, cleanupSRV := TestACLServer(t, func(c *Config) {
+			c.NumSchedulers = 0 // Prevent automatic dequeue
+		})
+		defer cleanupSRV()
+		testutil.WaitForLeader(t, srv.RPC)
+
+		srv.fsm.timetable.table = make([]TimeTableEntry, 1, 10)
+		store := srv.fsm.State()
+
+		now := time.Now().UTC()
+		past := now.Add(-time.Hour)
+		future := now.Add(time.Hour)
+
+		// Create an expired and an unexpired token of the locality under
+		// test, as well as an expired token of the other locality which must
+		// be ignored.
+		expiredToken := mock.ACLToken()
+		expiredToken.Global = global
+		expiredToken.ExpirationTime = &past
+
+		unexpiredToken := mock.ACLToken()
+		unexpiredToken.Global = global
+		unexpiredToken.ExpirationTime = &future
+
+		otherToken := mock.ACLToken()
+		otherToken.Global = !global
+		otherToken.ExpirationTime = &past
+
+		index := uint64(1000)
+		require.NoError(t, store.UpsertACLTokens(structs.MsgTypeTestSetup, index,
+			[]*structs.ACLToken{expiredToken, unexpiredToken, otherToken}))
+
+		// Update the time tables so the tokens are older than the threshold
+		tt := srv.fsm.TimeTable()
+		index = 2000
+		tt.Witness(index, now.Add(-1*srv.config.ACLTokenExpirationGCThreshold))
+
+		snap, err := store.Snapshot()
+		require.NoError(t, err)
+		core := NewCoreScheduler(srv, snap)
+
+		jobID := structs.CoreJobLocalTokenExpiredGC
+		if global {
+			jobID = structs.CoreJobGlobalTokenExpiredGC
+		}
+
+		index++
+		gc := srv.coreJobEval(jobID, index)
+		require.NoError(t, core.Process(gc))
+
+		out, err := store.ACLTokenByAccessorID(nil, expiredToken.AccessorID)
+		require.NoError(t, err)
+		require.Nil(t, out)
+
+		out, err = store.ACLTokenByAccessorID(nil, unexpiredToken.AccessorID)
+		require.NoError(t, err)
+		require.NotNil(t, out)
+
+		out, err = store.ACLTokenByAccessorID(nil, otherToken.AccessorID)
+		require.NoError(t, err)
+		require.NotNil(t, out)
+	}
+
+	t.Run("local", func(t *testing.T) { testFn(t, false) })
+	t.Run("global", func(t *testing.T) { testFn(t, true) })
+}
+
 func TestCoreScheduler_CSIVolumeClaimGC(t *testing.T) {
 	srv, shutdown := TestServer(t, func(c *Config) {
 		c.NumSchedulers = 0 // Prevent automatic dequeue
@@ -2510,3 +2582,69 @@ func TestCoreScheduler_FailLoop(t *testing.T) {
 			out.TriggeredBy)
 	}
 }
+
+func TestCoreScheduler_Rebalance(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, cleanupSRV := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupSRV()
+	testutil.WaitForLeader(t, srv.RPC)
+	store := srv.fsm.State()
+
+	hdd := mock.Node()
+	hdd.Meta["disk"] = "hdd"
+	ssd := mock.Node()
+	ssd.Meta["disk"] = "ssd"
+	require.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, 1000, hdd))
+	require.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, 1001, ssd))
+
+	// The allocation of the job violates its affinity
+	job := mock.Job()
+	job.TaskGroups[0].Count = 1
+	job.TaskGroups[0].Affinities = []*structs.Affinity{{
+		LTarget: "${meta.disk}",
+		RTarget: "ssd",
+		Operand: "=",
+		Weight:  100,
+	}}
+	require.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1002, job))
+
+	alloc := mock.Alloc()
+	alloc.Job = job
+	alloc.JobID = job.ID
+	alloc.NodeID = hdd.ID
+	alloc.ClientStatus = structs.AllocClientStatusRunning
+	alloc.DeploymentStatus = &structs.AllocDeploymentStatus{Healthy: helper.BoolToPtr(true)}
+	require.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 1003, []*structs.Allocation{alloc}))
+
+	process := func() {
+		snap, err := store.Snapshot()
+		require.NoError(t, err)
+		core := NewCoreScheduler(srv, snap)
+		require.NoError(t, core.Process(srv.coreJobEval(structs.CoreJobRebalance, 2000)))
+	}
+
+	// Nothing is migrated while rebalancing is disabled
+	process()
+	out, err := store.AllocByID(nil, alloc.ID)
+	require.NoError(t, err)
+	require.False(t, out.DesiredTransition.ShouldMigrate())
+
+	_, schedConfig, err := store.SchedulerConfig()
+	require.NoError(t, err)
+	config := *schedConfig
+	config.RebalanceConfig.Enabled = true
+	require.NoError(t, store.SchedulerSetConfig(1004, &config))
+
+	process()
+	out, err = store.AllocByID(nil, alloc.ID)
+	require.NoError(t, err)
+	require.True(t, out.DesiredTransition.ShouldMigrate())
+
+	evals, err := store.EvalsByJob(nil, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.Len(t, evals, 1)
+	require.Equal(t, structs.EvalTriggerRebalance, evals[0].TriggeredBy)
+}
@@ -624,6 +624,12 @@ func (w *deploymentWatcher) handleAllocUpdate(allocs []*structs.AllocListStub) (
 			res.createEval = true
 		}
 
+		// We need to create an eval so the placements of the task groups
+		// depending on this one are no longer held.
+		if alloc.ModifyIndex > latestEval && w.satisfiesDependents(alloc) {
+			res.createEval = true
+		}
+
 		// If the group is using a progress deadline, we don't have to do anything.
 		if progressBased {
 			continue
@@ -649,6 +655,17 @@ func (w *deploymentWatcher) handleAllocUpdate(allocs []*structs.AllocListStub) (
 	return res, nil
 }
 
+// satisfiesDependents returns whether the allocation satisfies the dependency
+// condition of a task group of the job depending on its task group.
+func (w *deploymentWatcher) satisfiesDependents(alloc *structs.AllocListStub) bool {
+	for _, tg := range w.j.DependentTaskGroups(alloc.TaskGroup) {
+		if alloc.SatisfiesDependency(tg.DependencyCondition()) {
+			return true
+		}
+	}
+	return false
+}
+
 // shouldFail returns whether the job should be failed and whether it should
 // rolled back to an earlier stable version by examining the allocations in the
 // deployment.
@@ -1851,6 +1851,66 @@ func TestWatcher_BatchAllocUpdates(t *testing.T) {
 		func(err error) { require.Equal(2, watchersCount(w), "Should have 2 deployment") })
 }
 
+// Tests that an evaluation is created when an allocation satisfies the
+// dependency condition of a task group depending on its task group
+func TestWatcher_DependsOn_CreateEval(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+	w, m := testDeploymentWatcher(t, 1000.0, 1*time.Millisecond)
+
+	m.On("UpdateDeploymentStatus", mocker.MatchedBy(func(args *structs.DeploymentStatusUpdateRequest) bool {
+		return true
+	})).Return(nil).Maybe()
+
+	// Create a job whose web group depends on its db group, and a deployment
+	j := mock.Job()
+	j.TaskGroups[0].Update = structs.DefaultUpdateStrategy.Copy()
+	j.TaskGroups[0].Update.ProgressDeadline = 0
+	db := j.TaskGroups[0].Copy()
+	db.Name = "db"
+	j.TaskGroups = append(j.TaskGroups, db)
+	j.TaskGroups[0].DependsOn = []string{"db"}
+	d := mock.Deployment()
+	d.JobID = j.ID
+	d.TaskGroups["db"] = d.TaskGroups["web"].Copy()
+	a := mock.Alloc()
+	a.Job = j
+	a.JobID = j.ID
+	a.TaskGroup = db.Name
+	a.DeploymentID = d.ID
+
+	require.Nil(m.state.UpsertJob(structs.MsgTypeTestSetup, m.nextIndex(), j), "UpsertJob")
+	require.Nil(m.state.UpsertDeployment(m.nextIndex(), d), "UpsertDeployment")
+	require.Nil(m.state.UpsertAllocs(structs.MsgTypeTestSetup, m.nextIndex(), []*structs.Allocation{a}), "UpsertAllocs")
+
+	m1 := matchUpdateAllocDesiredTransitions([]string{d.ID})
+	m.On("UpdateAllocDesiredTransition", mocker.MatchedBy(m1)).Return(nil).Once()
+
+	w.SetEnabled(true, m.state)
+	testutil.WaitForResult(func() (bool, error) { return 1 == watchersCount(w), nil },
+		func(err error) { require.Equal(1, watchersCount(w), "Should have 1 deployment") })
+
+	// Update the db alloc to running which should create an evaluation
+	a2 := a.Copy()
+	a2.ClientStatus = structs.AllocClientStatusRunning
+	require.Nil(m.state.UpdateAllocsFromClient(structs.MsgTypeTestSetup, m.nextIndex(), []*structs.Allocation{a2}))
+
+	testutil.WaitForResult(func() (bool, error) {
+		evals, err := m.state.EvalsByJob(nil, j.Namespace, j.ID)
+		if err != nil {
+			return false, err
+		}
+		if l := len(evals); l != 1 {
+			return false, fmt.Errorf("Got %d evals; want 1", l)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatal(err)
+	})
+
+	m.AssertCalled(t, "UpdateAllocDesiredTransition", mocker.MatchedBy(m1))
+}
+
 func watchersCount(w *Watcher) int {
 	w.l.Lock()
 	defer w.l.Unlock()
@@ -387,6 +387,19 @@ func (n *NodeDrainer) batchDrainAllocs(allocs []*structs.Allocation) (uint64, er
 	return future.Index(), nil
 }
 
+// checkpointNode returns whether the drain strategy of the node prefers
+// checkpointing the tasks of its allocations over restarting them.
+func (n *NodeDrainer) checkpointNode(nodeID string) bool {
+	n.l.RLock()
+	defer n.l.RUnlock()
+
+	draining, ok := n.nodes[nodeID]
+	if !ok {
+		return false
+	}
+	return draining.Checkpoint()
+}
+
 // drainAllocs is a non batch, marking of the desired transition to migrate for
 // the set of allocations. It will also create the necessary evaluations for the
 // affected jobs.
@@ -395,9 +408,13 @@ func (n *NodeDrainer) drainAllocs(future *structs.BatchFuture, allocs []*structs
 	jobs := make(map[structs.NamespacedID]*structs.Allocation, 4)
 	transitions := make(map[string]*structs.DesiredTransition, len(allocs))
 	for _, alloc := range allocs {
-		transitions[alloc.ID] = &structs.DesiredTransition{
+		transition := &structs.DesiredTransition{
 			Migrate: helper.BoolToPtr(true),
 		}
+		if n.checkpointNode(alloc.NodeID) {
+			transition.Checkpoint = helper.BoolToPtr(true)
+		}
+		transitions[alloc.ID] = transition
 		jobs[alloc.JobNamespacedID()] = alloc
 	}
 
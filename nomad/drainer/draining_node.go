@@ -47,6 +47,20 @@ func (n *drainingNode) DeadlineTime() (bool, time.Time) {
 	return n.node.DrainStrategy.DeadlineTime()
 }
 
+// Checkpoint returns if the drain strategy of the node prefers checkpointing
+// the tasks of its allocations
+func (n *drainingNode) Checkpoint() bool {
+	n.l.RLock()
+	defer n.l.RUnlock()
+
+	// Should never happen
+	if n.node == nil || n.node.DrainStrategy == nil {
+		return false
+	}
+
+	return n.node.DrainStrategy.Checkpoint
+}
+
 // IsDone returns if the node is done draining batch and service allocs. System
 // allocs must be stopped before marking drain complete unless they're being
 // ignored.
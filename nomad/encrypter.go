@@ -0,0 +1,531 @@
+package nomad
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/time/rate"
+	jose "gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// keystoreDir is the directory within the server data directory in which
+	// the root key material is stored.
+	keystoreDir = "keystore"
+
+	// keystoreFileExtension is the file extension used for root key files
+	// within the keystore.
+	keystoreFileExtension = ".nks.json"
+
+	// signingKeyInfo is the HKDF info used to derive the seed of the
+	// workload identity signing key from the root key material.
+	signingKeyInfo = "nomad-workload-identity-signing"
+)
+
+// Encrypter is the keyring used to encrypt and decrypt variables, and to sign
+// and verify workload identities. The key material of each root key is held
+// in memory and in the keystore on disk; only the metadata of the root keys
+// is written to Raft.
+type Encrypter struct {
+	// keystorePath is the directory in which the key material is persisted.
+	// It is empty when the server is running in dev mode, in which case keys
+	// are only ever held in memory.
+	keystorePath string
+
+	keyring map[string]*keyset
+	lock    sync.RWMutex
+}
+
+// keyset is a root key along with the cipher and signing key created from
+// its key material.
+type keyset struct {
+	rootKey    *structs.RootKey
+	cipher     cipher.AEAD
+	privateKey ed25519.PrivateKey
+}
+
+// NewEncrypter loads or creates a new local keystore and returns an encryption
+// keyring with the keys it finds.
+func NewEncrypter(keystorePath string) (*Encrypter, error) {
+	encrypter := &Encrypter{
+		keystorePath: keystorePath,
+		keyring:      make(map[string]*keyset),
+	}
+
+	if keystorePath == "" {
+		return encrypter, nil
+	}
+
+	if err := os.MkdirAll(keystorePath, 0700); err != nil {
+		return nil, err
+	}
+	if err := encrypter.loadKeystore(); err != nil {
+		return nil, err
+	}
+	return encrypter, nil
+}
+
+// loadKeystore reads all the root keys found within the keystore into the
+// keyring.
+func (e *Encrypter) loadKeystore() error {
+	return filepath.Walk(e.keystorePath, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return fmt.Errorf("could not read path %s from keystore: %v", path, err)
+		}
+
+		// Skip over subdirectories and non-key files; they shouldn't be here
+		// but there's no reason to fail startup for it if the administrator
+		// has left something there.
+		if path != e.keystorePath && info.IsDir() {
+			return filepath.SkipDir
+		}
+		if !strings.HasSuffix(path, keystoreFileExtension) {
+			return nil
+		}
+		id := strings.TrimSuffix(filepath.Base(path), keystoreFileExtension)
+		if !helper.IsUUID(id) {
+			return nil
+		}
+
+		key, err := e.loadKeyFromStore(path)
+		if err != nil {
+			return fmt.Errorf("could not load key file %s from keystore: %v", path, err)
+		}
+		if key.Meta.KeyID != id {
+			return fmt.Errorf("root key ID %s must match key file %s", key.Meta.KeyID, path)
+		}
+
+		return e.addCipher(key)
+	})
+}
+
+// Encrypt encrypts the cleartext using the root key with the given ID, which
+// is expected to be the active key.
+func (e *Encrypter) Encrypt(cleartext []byte, keyID string) ([]byte, error) {
+	keyset, err := e.keysetByID(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The nonce is prepended to the ciphertext, so it can be used during
+	// decryption.
+	nonce := make([]byte, keyset.cipher.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %v", err)
+	}
+	return keyset.cipher.Seal(nonce, nonce, cleartext, nil), nil
+}
+
+// Decrypt decrypts the ciphertext using the root key with the given ID.
+func (e *Encrypter) Decrypt(ciphertext []byte, keyID string) ([]byte, error) {
+	keyset, err := e.keysetByID(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	nonceSize := keyset.cipher.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	nonce, ciphertext := ciphertext[:nonceSize], ciphertext[nonceSize:]
+
+	return keyset.cipher.Open(nil, nonce, ciphertext, nil)
+}
+
+// SignClaims signs the identity claims using the root key with the given ID,
+// which is expected to be the active key, and returns the signed JWT.
+func (e *Encrypter) SignClaims(claims *structs.IdentityClaims, keyID string) (string, error) {
+	keyset, err := e.keysetByID(keyID)
+	if err != nil {
+		return "", err
+	}
+
+	opts := (&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", keyID)
+	signer, err := jose.NewSigner(jose.SigningKey{
+		Algorithm: jose.EdDSA,
+		Key:       keyset.privateKey,
+	}, opts)
+	if err != nil {
+		return "", fmt.Errorf("failed to create signer: %v", err)
+	}
+
+	return jwt.Signed(signer).Claims(claims).CompactSerialize()
+}
+
+// VerifyClaim verifies the signature of the JWT using the root key it was
+// signed with, and returns the identity claims it contains.
+func (e *Encrypter) VerifyClaim(token string) (*structs.IdentityClaims, error) {
+	parsed, err := jwt.ParseSigned(token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signed token: %v", err)
+	}
+	if len(parsed.Headers) != 1 || parsed.Headers[0].KeyID == "" {
+		return nil, fmt.Errorf("missing key ID header")
+	}
+
+	keyset, err := e.keysetByID(parsed.Headers[0].KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := &structs.IdentityClaims{}
+	if err := parsed.Claims(keyset.privateKey.Public(), claims); err != nil {
+		return nil, fmt.Errorf("invalid signature: %v", err)
+	}
+	if err := claims.Validate(jwt.Expected{Time: time.Now()}); err != nil {
+		return nil, fmt.Errorf("invalid claims: %v", err)
+	}
+	return claims, nil
+}
+
+// GetPublicKey returns the public key used to verify workload identities
+// signed with the root key with the given ID.
+func (e *Encrypter) GetPublicKey(keyID string) (ed25519.PublicKey, error) {
+	keyset, err := e.keysetByID(keyID)
+	if err != nil {
+		return nil, err
+	}
+	return keyset.privateKey.Public().(ed25519.PublicKey), nil
+}
+
+// AddKey stores the key in the keystore and adds it to the keyring.
+func (e *Encrypter) AddKey(rootKey *structs.RootKey) error {
+	if err := e.addCipher(rootKey); err != nil {
+		return err
+	}
+	return e.saveKeyToStore(rootKey)
+}
+
+// GetKey retrieves the key material of the root key with the given ID from
+// the keyring.
+func (e *Encrypter) GetKey(keyID string) (*structs.RootKey, error) {
+	keyset, err := e.keysetByID(keyID)
+	if err != nil {
+		return nil, err
+	}
+	return keyset.rootKey, nil
+}
+
+// hasKey returns whether the keyring holds the key material of the root key
+// with the given ID.
+func (e *Encrypter) hasKey(keyID string) bool {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	_, ok := e.keyring[keyID]
+	return ok
+}
+
+func (e *Encrypter) keysetByID(keyID string) (*keyset, error) {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+
+	keyset, ok := e.keyring[keyID]
+	if !ok {
+		return nil, fmt.Errorf("no such key %q in keyring", keyID)
+	}
+	return keyset, nil
+}
+
+// addCipher creates a cipher from the root key and adds it to the keyring.
+func (e *Encrypter) addCipher(rootKey *structs.RootKey) error {
+	if rootKey == nil || rootKey.Meta == nil {
+		return fmt.Errorf("missing metadata")
+	}
+
+	var aead cipher.AEAD
+
+	switch rootKey.Meta.Algorithm {
+	case structs.EncryptionAlgorithmAES256GCM:
+		block, err := aes.NewCipher(rootKey.Key)
+		if err != nil {
+			return fmt.Errorf("could not create cipher: %v", err)
+		}
+		aead, err = cipher.NewGCM(block)
+		if err != nil {
+			return fmt.Errorf("could not create cipher: %v", err)
+		}
+	default:
+		return fmt.Errorf("invalid algorithm %q", rootKey.Meta.Algorithm)
+	}
+
+	// The signing key is derived from the key material, so that it does not
+	// need to be stored or replicated separately. Its seed goes through HKDF
+	// so the encryption key itself is never used as a signing key.
+	seed := make([]byte, ed25519.SeedSize)
+	kdf := hkdf.New(sha256.New, rootKey.Key, nil, []byte(signingKeyInfo))
+	if _, err := io.ReadFull(kdf, seed); err != nil {
+		return fmt.Errorf("could not create signing key: %v", err)
+	}
+	privateKey := ed25519.NewKeyFromSeed(seed)
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.keyring[rootKey.Meta.KeyID] = &keyset{
+		rootKey:    rootKey,
+		cipher:     aead,
+		privateKey: privateKey,
+	}
+	return nil
+}
+
+// saveKeyToStore persists the root key to the keystore. Only the server
+// process user is able to read the file.
+func (e *Encrypter) saveKeyToStore(rootKey *structs.RootKey) error {
+	if e.keystorePath == "" {
+		return nil
+	}
+
+	buf, err := json.Marshal(rootKey)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(e.keystorePath, rootKey.Meta.KeyID+keystoreFileExtension)
+	return ioutil.WriteFile(path, buf, 0600)
+}
+
+// loadKeyFromStore deserializes a root key from the keystore.
+func (e *Encrypter) loadKeyFromStore(path string) (*structs.RootKey, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var rootKey structs.RootKey
+	if err := json.Unmarshal(raw, &rootKey); err != nil {
+		return nil, err
+	}
+	if err := rootKey.Meta.Validate(); err != nil {
+		return nil, err
+	}
+	return &rootKey, nil
+}
+
+// KeyringReplicator fetches the key material of root keys, whose metadata
+// has been written to Raft, from peer servers. Every server runs a replicator
+// so that any server is able to decrypt variables and become leader.
+type KeyringReplicator struct {
+	srv       *Server
+	encrypter *Encrypter
+	logger    log.Logger
+}
+
+// NewKeyringReplicator returns a KeyringReplicator which is ready to be run.
+func NewKeyringReplicator(srv *Server, e *Encrypter) *KeyringReplicator {
+	return &KeyringReplicator{
+		srv:       srv,
+		encrypter: e,
+		logger:    srv.logger.Named("keyring.replicator"),
+	}
+}
+
+// run watches the root key metadata table and replicates any key material
+// missing from the local keyring until the context is cancelled.
+func (krr *KeyringReplicator) run(ctx context.Context) {
+	krr.logger.Debug("starting encryption key replication")
+	defer krr.logger.Debug("exiting key replication")
+
+	limiter := rate.NewLimiter(replicationRateLimit, int(replicationRateLimit))
+
+	for {
+		if err := limiter.Wait(ctx); err != nil {
+			return
+		}
+
+		store := krr.srv.fsm.State()
+		ws := memdb.NewWatchSet()
+		ws.Add(store.AbandonCh())
+
+		missing, err := krr.missingKeys(ws, store)
+		if err != nil {
+			krr.logger.Error("failed to fetch keyring", "error", err)
+			continue
+		}
+
+		var failed bool
+		for _, keyMeta := range missing {
+			if err := krr.replicateKey(ctx, keyMeta); err != nil {
+				// Don't break the loop on an error, as we want to make sure
+				// we've replicated any keys we can. The rate limiter will
+				// prevent this case from sending excessive RPCs.
+				krr.logger.Error(err.Error(), "key", keyMeta.KeyID)
+				failed = true
+			}
+		}
+
+		// If a key could not be replicated, try again rather than waiting for
+		// the table to change.
+		if failed {
+			continue
+		}
+
+		if err := ws.WatchCtx(ctx); err != nil {
+			return
+		}
+	}
+}
+
+// missingKeys returns the metadata of any root key whose key material is not
+// held within the local keyring.
+func (krr *KeyringReplicator) missingKeys(
+	ws memdb.WatchSet, store *state.StateStore) ([]*structs.RootKeyMeta, error) {
+
+	iter, err := store.RootKeyMetas(ws)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []*structs.RootKeyMeta
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		keyMeta := raw.(*structs.RootKeyMeta)
+		if !krr.encrypter.hasKey(keyMeta.KeyID) {
+			missing = append(missing, keyMeta)
+		}
+	}
+	return missing, nil
+}
+
+// replicateKey fetches the key material of the root key from the leader, or
+// from any peer which holds the key if the leader does not, and adds it to the
+// local keyring.
+func (krr *KeyringReplicator) replicateKey(ctx context.Context, keyMeta *structs.RootKeyMeta) error {
+	keyID := keyMeta.KeyID
+	krr.logger.Debug("replicating new key", "id", keyID)
+
+	getReq := &structs.KeyringGetRootKeyRequest{
+		KeyID: keyID,
+		QueryOptions: structs.QueryOptions{
+			Region: krr.srv.config.Region,
+		},
+	}
+	getResp := &structs.KeyringGetRootKeyResponse{}
+	err := krr.srv.RPC(structs.KeyringGetRootKeyRPCMethod, getReq, getResp)
+
+	if err != nil || getResp.Key == nil {
+		// The leader may not hold the key if it has only just been elected,
+		// so try each of our peers in turn.
+		krr.logger.Debug("failed to fetch key from leader, trying peers",
+			"key", keyID, "error", err)
+		getReq.AllowStale = true
+
+		for _, peer := range krr.localPeers() {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			err = krr.srv.forwardServer(peer, structs.KeyringGetRootKeyRPCMethod, getReq, getResp)
+			if err == nil && getResp.Key != nil {
+				break
+			}
+		}
+		if getResp.Key == nil {
+			return fmt.Errorf("failed to fetch key from any peer: %v", err)
+		}
+	}
+
+	if err := krr.encrypter.AddKey(getResp.Key); err != nil {
+		return fmt.Errorf("failed to add key to keyring: %v", err)
+	}
+
+	krr.logger.Info("added key", "key", keyID)
+	return nil
+}
+
+// localPeers returns a copy of the servers within the local region, excluding
+// this server.
+func (krr *KeyringReplicator) localPeers() []*serverParts {
+	selfAddr := krr.srv.LocalMember().Addr.String()
+
+	krr.srv.peerLock.RLock()
+	defer krr.srv.peerLock.RUnlock()
+
+	peers := make([]*serverParts, 0, len(krr.srv.localPeers))
+	for _, peer := range krr.srv.localPeers {
+		if peer.Addr.String() == selfAddr {
+			continue
+		}
+		peers = append(peers, peer.Copy())
+	}
+	return peers
+}
+
+// initializeKeyring creates the first root key of the cluster if there is no
+// active key. It is called by the leader when establishing leadership.
+func (s *Server) initializeKeyring(stopCh <-chan struct{}) {
+	logger := s.logger.Named("keyring")
+
+	// Wait for all servers to support the keyring before writing to Raft, as
+	// older servers would fail to apply the message.
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+
+	for !ServersMeetMinimumVersion(s.Members(), minVariablesVersion, false) {
+		logger.Trace("waiting for servers to meet the minimum version",
+			"min_version", minVariablesVersion)
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+		}
+	}
+
+	keyMeta, err := s.State().GetActiveRootKeyMeta(nil)
+	if err != nil {
+		logger.Error("failed to get active key", "error", err)
+		return
+	}
+	if keyMeta != nil {
+		return
+	}
+
+	logger.Trace("initializing keyring")
+
+	rootKey, err := structs.NewRootKey(structs.EncryptionAlgorithmAES256GCM)
+	if err != nil {
+		logger.Error("could not generate new root key", "error", err)
+		return
+	}
+	rootKey.Meta.Active = true
+
+	// Add the key to the local keyring before writing the metadata to Raft,
+	// so that followers are able to replicate it from us as soon as they see
+	// the metadata.
+	if err := s.encrypter.AddKey(rootKey); err != nil {
+		logger.Error("could not add initial key to keyring", "error", err)
+		return
+	}
+
+	req := &structs.KeyringUpdateRootKeyMetaRequest{
+		RootKeyMeta: rootKey.Meta,
+		WriteRequest: structs.WriteRequest{
+			Region: s.config.Region,
+		},
+	}
+	if _, _, err := s.raftApply(structs.RootKeyMetaUpsertRequestType, req); err != nil {
+		logger.Error("could not initialize keyring", "error", err)
+		return
+	}
+
+	logger.Info("initialized keyring", "id", rootKey.Meta.KeyID)
+}
@@ -0,0 +1,168 @@
+package nomad
+
+import (
+	"crypto/ed25519"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEncrypter_LoadSave exercises round-tripping keys to disk
+func TestEncrypter_LoadSave(t *testing.T) {
+	ci.Parallel(t)
+
+	tmpDir := t.TempDir()
+	encrypter, err := NewEncrypter(tmpDir)
+	require.NoError(t, err)
+
+	key, err := structs.NewRootKey(structs.EncryptionAlgorithmAES256GCM)
+	require.NoError(t, err)
+	require.NoError(t, encrypter.AddKey(key))
+
+	// A new encrypter using the same keystore should load the key.
+	reloaded, err := NewEncrypter(tmpDir)
+	require.NoError(t, err)
+
+	got, err := reloaded.GetKey(key.Meta.KeyID)
+	require.NoError(t, err)
+	require.Equal(t, key.Key, got.Key)
+	require.Equal(t, key.Meta.KeyID, got.Meta.KeyID)
+}
+
+// TestEncrypter_EncryptDecrypt exercises encrypting and decrypting data with
+// a root key
+func TestEncrypter_EncryptDecrypt(t *testing.T) {
+	ci.Parallel(t)
+
+	encrypter, err := NewEncrypter("")
+	require.NoError(t, err)
+
+	key, err := structs.NewRootKey(structs.EncryptionAlgorithmAES256GCM)
+	require.NoError(t, err)
+	require.NoError(t, encrypter.AddKey(key))
+
+	cleartext := []byte("the quick brown fox")
+	ciphertext, err := encrypter.Encrypt(cleartext, key.Meta.KeyID)
+	require.NoError(t, err)
+	require.NotEqual(t, cleartext, ciphertext)
+
+	// Encrypting the same cleartext twice should use a different nonce.
+	ciphertext2, err := encrypter.Encrypt(cleartext, key.Meta.KeyID)
+	require.NoError(t, err)
+	require.NotEqual(t, ciphertext, ciphertext2)
+
+	got, err := encrypter.Decrypt(ciphertext, key.Meta.KeyID)
+	require.NoError(t, err)
+	require.Equal(t, cleartext, got)
+
+	// Tampered ciphertext should fail to decrypt.
+	ciphertext[len(ciphertext)-1] ^= 0xff
+	_, err = encrypter.Decrypt(ciphertext, key.Meta.KeyID)
+	require.Error(t, err)
+
+	// Unknown keys should be reported.
+	_, err = encrypter.Encrypt(cleartext, structs.NewRootKeyMeta().KeyID)
+	require.Error(t, err)
+}
+
+// TestEncrypter_SignVerify exercises signing workload identities and verifying
+// them with the same key
+func TestEncrypter_SignVerify(t *testing.T) {
+	ci.Parallel(t)
+
+	encrypter, err := NewEncrypter("")
+	require.NoError(t, err)
+
+	key, err := structs.NewRootKey(structs.EncryptionAlgorithmAES256GCM)
+	require.NoError(t, err)
+	require.NoError(t, encrypter.AddKey(key))
+
+	alloc := mock.Alloc()
+	claims := structs.NewIdentityClaims(alloc.Job, alloc, "web", time.Now())
+
+	token, err := encrypter.SignClaims(claims, key.Meta.KeyID)
+	require.NoError(t, err)
+
+	got, err := encrypter.VerifyClaim(token)
+	require.NoError(t, err)
+	require.Equal(t, alloc.Namespace, got.Namespace)
+	require.Equal(t, alloc.JobID, got.JobID)
+	require.Equal(t, alloc.TaskGroup, got.TaskGroup)
+	require.Equal(t, alloc.ID, got.AllocationID)
+	require.Equal(t, "web", got.TaskName)
+
+	// A token signed by a key which is not in the keyring should fail to
+	// verify, even if its key ID has been forged.
+	other, err := NewEncrypter("")
+	require.NoError(t, err)
+	otherKey, err := structs.NewRootKey(structs.EncryptionAlgorithmAES256GCM)
+	require.NoError(t, err)
+	otherKey.Meta.KeyID = key.Meta.KeyID
+	require.NoError(t, other.AddKey(otherKey))
+
+	forged, err := other.SignClaims(claims, key.Meta.KeyID)
+	require.NoError(t, err)
+	_, err = encrypter.VerifyClaim(forged)
+	require.Error(t, err)
+
+	_, err = encrypter.VerifyClaim("not-a-jwt")
+	require.Error(t, err)
+
+	// The signing key is derived from the root key rather than using the
+	// encryption key as its seed.
+	pubKey, err := encrypter.GetPublicKey(key.Meta.KeyID)
+	require.NoError(t, err)
+	require.NotEqual(t, ed25519.NewKeyFromSeed(key.Key).Public().(ed25519.PublicKey), pubKey)
+}
+
+// TestEncrypter_Replication exercises the keyring replicator, by ensuring the
+// key created by the leader is replicated to all followers
+func TestEncrypter_Replication(t *testing.T) {
+	ci.Parallel(t)
+
+	srv1, cleanupSRV1 := TestServer(t, func(c *Config) {
+		c.BootstrapExpect = 3
+		c.NumSchedulers = 0
+	})
+	defer cleanupSRV1()
+	srv2, cleanupSRV2 := TestServer(t, func(c *Config) {
+		c.BootstrapExpect = 3
+		c.NumSchedulers = 0
+	})
+	defer cleanupSRV2()
+	srv3, cleanupSRV3 := TestServer(t, func(c *Config) {
+		c.BootstrapExpect = 3
+		c.NumSchedulers = 0
+	})
+	defer cleanupSRV3()
+
+	servers := []*Server{srv1, srv2, srv3}
+	TestJoin(t, servers...)
+	testutil.WaitForLeader(t, srv1.RPC)
+
+	// Wait for the leader to initialize the keyring and for every server to
+	// hold the key material of the active key.
+	testutil.WaitForResult(func() (bool, error) {
+		for _, srv := range servers {
+			keyMeta, err := srv.State().GetActiveRootKeyMeta(memdb.NewWatchSet())
+			if err != nil {
+				return false, err
+			}
+			if keyMeta == nil {
+				return false, nil
+			}
+			if _, err := srv.encrypter.GetKey(keyMeta.KeyID); err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	}, func(err error) {
+		require.NoError(t, err)
+	})
+}
@@ -2893,6 +2893,41 @@ func TestFSM_SnapshotRestore_ServiceRegistrations(t *testing.T) {
 	require.ElementsMatch(t, restoredRegs, serviceRegs)
 }
 
+func TestFSM_SnapshotRestore_Variables(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create our initial FSM which will be snapshotted.
+	fsm := testFSM(t)
+	testState := fsm.State()
+
+	// Generate and upsert a variable and the metadata of a root key.
+	variable := mock.VariableEncrypted()
+	require.NoError(t, testState.VarApply(structs.MsgTypeTestSetup, 10,
+		&structs.VarApplyStateRequest{Op: structs.VarOpSet, Var: variable}))
+
+	keyMeta := structs.NewRootKeyMeta()
+	keyMeta.Active = true
+	require.NoError(t, testState.UpsertRootKeyMeta(structs.MsgTypeTestSetup, 11, keyMeta))
+
+	// Perform a snapshot restore.
+	restoredFSM := testSnapshotRestore(t, fsm)
+	restoredState := restoredFSM.State()
+
+	// Read the objects from restored state and ensure everything is as
+	// expected.
+	ws := memdb.NewWatchSet()
+	expectedVar, err := testState.GetVariable(ws, variable.Namespace, variable.Path)
+	require.NoError(t, err)
+	restoredVar, err := restoredState.GetVariable(ws, variable.Namespace, variable.Path)
+	require.NoError(t, err)
+	require.Equal(t, expectedVar, restoredVar)
+
+	restoredKeyMeta, err := restoredState.GetActiveRootKeyMeta(ws)
+	require.NoError(t, err)
+	require.NotNil(t, restoredKeyMeta)
+	require.Equal(t, keyMeta.KeyID, restoredKeyMeta.KeyID)
+}
+
 func TestFSM_ReconcileSummaries(t *testing.T) {
 	ci.Parallel(t)
 	// Add some state
@@ -3287,6 +3322,162 @@ func TestFSM_SnapshotRestore_Namespaces(t *testing.T) {
 	}
 }
 
+func TestFSM_SnapshotRestore_NodePools(t *testing.T) {
+	ci.Parallel(t)
+
+	// Add some state
+	fsm := testFSM(t)
+	testState := fsm.State()
+	pool := mock.NodePool()
+	require.NoError(t, testState.UpsertNodePools(
+		structs.MsgTypeTestSetup, 1000, []*structs.NodePool{pool}))
+
+	// Verify the contents, including the built-in node pools
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	for _, name := range []string{pool.Name, structs.NodePoolAll, structs.NodePoolDefault} {
+		expected, err := testState.NodePoolByName(nil, name)
+		require.NoError(t, err)
+		out, err := state2.NodePoolByName(nil, name)
+		require.NoError(t, err)
+		require.Equal(t, expected, out)
+	}
+}
+
+func TestFSM_UpsertNodePools(t *testing.T) {
+	ci.Parallel(t)
+	fsm := testFSM(t)
+
+	pool := mock.NodePool()
+	req := structs.NodePoolUpsertRequest{NodePools: []*structs.NodePool{pool}}
+	buf, err := structs.Encode(structs.NodePoolUpsertRequestType, req)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err := fsm.State().NodePoolByName(nil, pool.Name)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+
+	// Delete the node pool
+	delReq := structs.NodePoolDeleteRequest{Names: []string{pool.Name}}
+	buf, err = structs.Encode(structs.NodePoolDeleteRequestType, delReq)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err = fsm.State().NodePoolByName(nil, pool.Name)
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
+
+func TestFSM_SnapshotRestore_QuotaSpecs(t *testing.T) {
+	ci.Parallel(t)
+
+	// Add some state
+	fsm := testFSM(t)
+	testState := fsm.State()
+	spec := mock.QuotaSpec()
+	require.NoError(t, testState.UpsertQuotaSpecs(
+		structs.MsgTypeTestSetup, 1000, []*structs.QuotaSpec{spec}))
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	out, err := fsm2.State().QuotaSpecByName(nil, spec.Name)
+	require.NoError(t, err)
+	require.Equal(t, spec, out)
+}
+
+func TestFSM_UpsertQuotaSpecs(t *testing.T) {
+	ci.Parallel(t)
+	fsm := testFSM(t)
+
+	spec := mock.QuotaSpec()
+	req := structs.QuotaSpecUpsertRequest{Quotas: []*structs.QuotaSpec{spec}}
+	buf, err := structs.Encode(structs.QuotaSpecUpsertRequestType, req)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err := fsm.State().QuotaSpecByName(nil, spec.Name)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+
+	// The quota of an allocation is the quota of its namespace
+	ns := mock.Namespace()
+	ns.Quota = spec.Name
+	require.NoError(t, fsm.State().UpsertNamespaces(1001, []*structs.Namespace{ns}))
+	alloc := mock.Alloc()
+	alloc.Namespace = ns.Name
+	require.NoError(t, fsm.State().UpsertAllocs(structs.MsgTypeTestSetup, 1002, []*structs.Allocation{alloc}))
+
+	quota, err := fsm.allocQuota(alloc.ID)
+	require.NoError(t, err)
+	require.Equal(t, spec.Name, quota)
+
+	// Delete the quota specification once it is detached from the namespace
+	ns = ns.Copy()
+	ns.Quota = ""
+	require.NoError(t, fsm.State().UpsertNamespaces(1003, []*structs.Namespace{ns}))
+
+	delReq := structs.QuotaSpecDeleteRequest{Names: []string{spec.Name}}
+	buf, err = structs.Encode(structs.QuotaSpecDeleteRequestType, delReq)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err = fsm.State().QuotaSpecByName(nil, spec.Name)
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
+
+func TestFSM_SnapshotRestore_JobSubmissions(t *testing.T) {
+	ci.Parallel(t)
+
+	// Add some state
+	fsm := testFSM(t)
+	testState := fsm.State()
+	job := mock.Job()
+	sub := &structs.JobSubmission{
+		Source:    `job "example" {}`,
+		Format:    structs.JobSubmissionFormatHCL2,
+		Variables: `count = 3`,
+	}
+	require.NoError(t, testState.UpsertJobWithSubmission(structs.MsgTypeTestSetup, 1000, sub, job))
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	expected, err := testState.JobSubmission(nil, job.Namespace, job.ID, 0)
+	require.NoError(t, err)
+	out, err := state2.JobSubmission(nil, job.Namespace, job.ID, 0)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, expected, out)
+}
+
+func TestFSM_UpsertJob_Submission(t *testing.T) {
+	ci.Parallel(t)
+	fsm := testFSM(t)
+
+	job := mock.Job()
+	req := structs.JobRegisterRequest{
+		Job: job,
+		Submission: &structs.JobSubmission{
+			Source: `job "example" {}`,
+			Format: structs.JobSubmissionFormatHCL2,
+		},
+		WriteRequest: structs.WriteRequest{
+			Namespace: job.Namespace,
+		},
+	}
+	buf, err := structs.Encode(structs.JobRegisterRequestType, req)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err := fsm.State().JobSubmission(nil, job.Namespace, job.ID, 0)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, req.Submission.Source, out.Source)
+	require.EqualValues(t, 1, out.JobModifyIndex)
+}
+
 func TestFSM_UpsertServiceRegistrations(t *testing.T) {
 	ci.Parallel(t)
 	fsm := testFSM(t)
@@ -3570,3 +3761,216 @@ func TestFSM_EventBroker_JobRegisterFSMEvents(t *testing.T) {
 	require.Len(t, events, 1)
 	require.Equal(t, structs.TypeJobRegistered, events[0].Type)
 }
+
+func TestFSM_VarApply(t *testing.T) {
+	ci.Parallel(t)
+	fsm := testFSM(t)
+
+	variable := mock.VariableEncrypted()
+
+	// Build and apply our message to set the variable.
+	req := structs.VarApplyStateRequest{Op: structs.VarOpSet, Var: variable}
+	buf, err := structs.Encode(structs.VarApplyStateRequestType, req)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	ws := memdb.NewWatchSet()
+	out, err := fsm.State().GetVariable(ws, variable.Namespace, variable.Path)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, variable.Data, out.Data)
+
+	// Build and apply our message to delete the variable.
+	req = structs.VarApplyStateRequest{Op: structs.VarOpDelete, Var: variable}
+	buf, err = structs.Encode(structs.VarApplyStateRequestType, req)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err = fsm.State().GetVariable(ws, variable.Namespace, variable.Path)
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
+
+func TestFSM_UpsertRootKeyMeta(t *testing.T) {
+	ci.Parallel(t)
+	fsm := testFSM(t)
+
+	keyMeta := structs.NewRootKeyMeta()
+	keyMeta.Active = true
+
+	// Build and apply our message.
+	req := structs.KeyringUpdateRootKeyMetaRequest{RootKeyMeta: keyMeta}
+	buf, err := structs.Encode(structs.RootKeyMetaUpsertRequestType, req)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err := fsm.State().RootKeyMetaByID(memdb.NewWatchSet(), keyMeta.KeyID)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.True(t, out.Active)
+}
+
+func TestFSM_SnapshotRestore_ACLRoles(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create our initial FSM which will be snapshotted.
+	fsm := testFSM(t)
+	testState := fsm.State()
+
+	// Generate and upsert some ACL roles.
+	aclRoles := []*structs.ACLRole{mock.ACLRole(), mock.ACLRole()}
+	require.NoError(t, testState.UpsertACLRoles(structs.MsgTypeTestSetup, 10, aclRoles, true))
+
+	// Perform a snapshot restore.
+	restoredFSM := testSnapshotRestore(t, fsm)
+	restoredState := restoredFSM.State()
+
+	// List the ACL roles from restored state and ensure everything is as
+	// expected.
+	iter, err := restoredState.GetACLRoles(memdb.NewWatchSet())
+	require.NoError(t, err)
+
+	var restoredACLRoles []*structs.ACLRole
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		restoredACLRoles = append(restoredACLRoles, raw.(*structs.ACLRole))
+	}
+	require.ElementsMatch(t, restoredACLRoles, aclRoles)
+}
+
+func TestFSM_ACLRolesUpsertAndDeleteByID(t *testing.T) {
+	ci.Parallel(t)
+	fsm := testFSM(t)
+
+	// Create the policies our ACL roles wants to link to.
+	policy1 := mock.ACLPolicy()
+	policy1.Name = "mocked-test-policy-1"
+	policy2 := mock.ACLPolicy()
+	policy2.Name = "mocked-test-policy-2"
+	require.NoError(t, fsm.State().UpsertACLPolicies(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLPolicy{policy1, policy2}))
+
+	// Build and apply our message.
+	aclRole := mock.ACLRole()
+	req := structs.ACLRolesUpsertRequest{ACLRoles: []*structs.ACLRole{aclRole}}
+	buf, err := structs.Encode(structs.ACLRolesUpsertRequestType, req)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err := fsm.State().GetACLRoleByID(memdb.NewWatchSet(), aclRole.ID)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, aclRole.Name, out.Name)
+
+	// Delete the role via a message and ensure it is gone.
+	deleteReq := structs.ACLRolesDeleteByIDRequest{ACLRoleIDs: []string{aclRole.ID}}
+	buf, err = structs.Encode(structs.ACLRolesDeleteByIDRequestType, deleteReq)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err = fsm.State().GetACLRoleByID(memdb.NewWatchSet(), aclRole.ID)
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
+
+func TestFSM_SnapshotRestore_ACLAuthMethods(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create our initial FSM which will be snapshotted.
+	fsm := testFSM(t)
+	testState := fsm.State()
+
+	// Generate and upsert some ACL auth methods.
+	authMethods := []*structs.ACLAuthMethod{mock.ACLAuthMethod(), mock.ACLAuthMethod()}
+	require.NoError(t, testState.UpsertACLAuthMethods(structs.MsgTypeTestSetup, 10, authMethods))
+
+	// Perform a snapshot restore.
+	restoredFSM := testSnapshotRestore(t, fsm)
+	restoredState := restoredFSM.State()
+
+	// List the ACL auth methods from restored state and ensure everything is
+	// as expected.
+	iter, err := restoredState.GetACLAuthMethods(memdb.NewWatchSet())
+	require.NoError(t, err)
+
+	var restoredACLAuthMethods []*structs.ACLAuthMethod
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		restoredACLAuthMethods = append(restoredACLAuthMethods, raw.(*structs.ACLAuthMethod))
+	}
+	require.ElementsMatch(t, restoredACLAuthMethods, authMethods)
+}
+
+func TestFSM_SnapshotRestore_ACLBindingRules(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create our initial FSM which will be snapshotted.
+	fsm := testFSM(t)
+	testState := fsm.State()
+
+	// Generate and upsert some ACL binding rules.
+	bindingRules := []*structs.ACLBindingRule{mock.ACLBindingRule(), mock.ACLBindingRule()}
+	require.NoError(t, testState.UpsertACLBindingRules(structs.MsgTypeTestSetup, 10, bindingRules, true))
+
+	// Perform a snapshot restore.
+	restoredFSM := testSnapshotRestore(t, fsm)
+	restoredState := restoredFSM.State()
+
+	// List the ACL binding rules from restored state and ensure everything is
+	// as expected.
+	iter, err := restoredState.GetACLBindingRules(memdb.NewWatchSet())
+	require.NoError(t, err)
+
+	var restoredACLBindingRules []*structs.ACLBindingRule
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		restoredACLBindingRules = append(restoredACLBindingRules, raw.(*structs.ACLBindingRule))
+	}
+	require.ElementsMatch(t, restoredACLBindingRules, bindingRules)
+}
+
+func TestFSM_ACLAuthMethodsAndBindingRulesUpsertAndDelete(t *testing.T) {
+	ci.Parallel(t)
+	fsm := testFSM(t)
+
+	// Build and apply our auth method message.
+	authMethod := mock.ACLAuthMethod()
+	req := structs.ACLAuthMethodUpsertRequest{AuthMethods: []*structs.ACLAuthMethod{authMethod}}
+	buf, err := structs.Encode(structs.ACLAuthMethodsUpsertRequestType, req)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	outMethod, err := fsm.State().GetACLAuthMethodByName(memdb.NewWatchSet(), authMethod.Name)
+	require.NoError(t, err)
+	require.NotNil(t, outMethod)
+
+	// Build and apply our binding rule message, linked to the auth method.
+	bindingRule := mock.ACLBindingRule()
+	bindingRule.AuthMethod = authMethod.Name
+	bindingRule.SetHash()
+	ruleReq := structs.ACLBindingRulesUpsertRequest{ACLBindingRules: []*structs.ACLBindingRule{bindingRule}}
+	buf, err = structs.Encode(structs.ACLBindingRulesUpsertRequestType, ruleReq)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	outRule, err := fsm.State().GetACLBindingRule(memdb.NewWatchSet(), bindingRule.ID)
+	require.NoError(t, err)
+	require.NotNil(t, outRule)
+
+	// Delete the binding rule via a message and ensure it is gone.
+	deleteRuleReq := structs.ACLBindingRulesDeleteRequest{ACLBindingRuleIDs: []string{bindingRule.ID}}
+	buf, err = structs.Encode(structs.ACLBindingRulesDeleteRequestType, deleteRuleReq)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	outRule, err = fsm.State().GetACLBindingRule(memdb.NewWatchSet(), bindingRule.ID)
+	require.NoError(t, err)
+	require.Nil(t, outRule)
+
+	// Delete the auth method via a message and ensure it is gone.
+	deleteMethodReq := structs.ACLAuthMethodDeleteRequest{Names: []string{authMethod.Name}}
+	buf, err = structs.Encode(structs.ACLAuthMethodsDeleteRequestType, deleteMethodReq)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	outMethod, err = fsm.State().GetACLAuthMethodByName(memdb.NewWatchSet(), authMethod.Name)
+	require.NoError(t, err)
+	require.Nil(t, outMethod)
+}
@@ -0,0 +1,59 @@
+package nomad
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// jobNodePoolHook sets and validates the node pool targeted by jobs.
+type jobNodePoolHook struct {
+	srv *Server
+}
+
+func (jobNodePoolHook) Name() string {
+	return "node-pool"
+}
+
+// Mutate sets the node pool of jobs which do not specify one to the default
+// node pool of their namespace.
+func (h jobNodePoolHook) Mutate(job *structs.Job) (*structs.Job, []error, error) {
+	if job.NodePool != "" {
+		return job, nil, nil
+	}
+
+	ns, err := h.srv.State().NamespaceByName(nil, job.Namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Nonexistent namespaces are reported by the namespace validator, so
+	// fall back to the default node pool here.
+	if ns == nil {
+		job.NodePool = structs.NodePoolDefault
+	} else {
+		job.NodePool = ns.DefaultNodePool()
+	}
+	return job, nil, nil
+}
+
+// Validate ensures the node pool targeted by the job exists and is allowed by
+// the namespace of the job.
+func (h jobNodePoolHook) Validate(job *structs.Job) ([]error, error) {
+	pool, err := h.srv.State().NodePoolByName(nil, job.NodePool)
+	if err != nil {
+		return nil, err
+	}
+	if pool == nil {
+		return nil, fmt.Errorf("job %q is in nonexistent node pool %q", job.ID, job.NodePool)
+	}
+
+	ns, err := h.srv.State().NamespaceByName(nil, job.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if ns != nil && !ns.AllowsNodePool(job.NodePool) {
+		return nil, fmt.Errorf("node pool %q is not allowed in namespace %q", job.NodePool, ns.Name)
+	}
+	return nil, nil
+}
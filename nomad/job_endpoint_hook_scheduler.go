@@ -0,0 +1,29 @@
+package nomad
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// jobSchedulerHook validates the scheduler plugin used by jobs.
+type jobSchedulerHook struct {
+	srv *Server
+}
+
+func (jobSchedulerHook) Name() string {
+	return "scheduler"
+}
+
+// Validate ensures the scheduler plugin used by the job is registered with
+// the server.
+func (h jobSchedulerHook) Validate(job *structs.Job) ([]error, error) {
+	if job.Scheduler == "" {
+		return nil, nil
+	}
+
+	if !h.srv.schedulerPluginExists(job.Scheduler) {
+		return nil, fmt.Errorf("job %q uses unknown scheduler plugin %q", job.ID, job.Scheduler)
+	}
+	return nil, nil
+}
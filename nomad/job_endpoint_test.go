@@ -16,6 +16,7 @@ import (
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
+	schedplugin "github.com/hashicorp/nomad/plugins/scheduler"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/hashicorp/raft"
 	"github.com/kr/pretty"
@@ -938,6 +939,40 @@ func TestJobEndpoint_Register_InvalidNamespace(t *testing.T) {
 	}
 }
 
+func TestJobEndpoint_Register_SchedulerPlugin(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.PluginSingletonLoader = testSchedulerPluginCatalog(&schedplugin.MockSchedulerPlugin{})
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Jobs using unknown scheduler plugins are rejected
+	job := mock.Job()
+	job.Scheduler = "unknown"
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `unknown scheduler plugin "unknown"`)
+
+	job.Scheduler = "mock"
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+
+	out, err := s1.fsm.State().JobByID(nil, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.Equal(t, "mock", out.Scheduler)
+}
+
 func TestJobEndpoint_Register_Payload(t *testing.T) {
 	ci.Parallel(t)
 
@@ -4418,6 +4453,148 @@ func TestJobEndpoint_GetJobVersions(t *testing.T) {
 	}
 }
 
+func TestJobEndpoint_GetJobSubmission(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the register request with the job source
+	job := mock.Job()
+	reg := &structs.JobRegisterRequest{
+		Job: job,
+		Submission: &structs.JobSubmission{
+			Source:        `job "example" {}`,
+			Format:        structs.JobSubmissionFormatHCL2,
+			VariableFlags: map[string]string{"count": "3"},
+		},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", reg, &resp))
+	require.Empty(t, resp.Warnings)
+
+	// Lookup the submission
+	get := &structs.JobSubmissionRequest{
+		JobID:   job.ID,
+		Version: 0,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var subResp structs.JobSubmissionResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &subResp))
+	require.NotNil(t, subResp.Submission)
+	require.Equal(t, reg.Submission.Source, subResp.Submission.Source)
+	require.Equal(t, reg.Submission.VariableFlags, subResp.Submission.VariableFlags)
+	require.Equal(t, resp.JobModifyIndex, subResp.Index)
+
+	// Lookup a version without a submission
+	get.Version = 1
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &subResp))
+	require.Nil(t, subResp.Submission)
+
+	// Registering an invalid submission must fail
+	job2 := mock.Job()
+	reg.Job = job2
+	reg.Submission = &structs.JobSubmission{Source: "{}", Format: "yaml"}
+	err := msgpackrpc.CallWithCodec(codec, "Job.Register", reg, &resp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "format")
+}
+
+func TestJobEndpoint_Register_SubmissionTooLarge(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.JobMaxSourceSize = 10
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	job := mock.Job()
+	reg := &structs.JobRegisterRequest{
+		Job: job,
+		Submission: &structs.JobSubmission{
+			Source: `job "example" { datacenters = ["dc1"] }`,
+			Format: structs.JobSubmissionFormatHCL2,
+		},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	// The job is registered without its source
+	var resp structs.JobRegisterResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", reg, &resp))
+	require.Contains(t, resp.Warnings, "was not stored")
+
+	out, err := s1.fsm.State().JobByID(nil, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+
+	sub, err := s1.fsm.State().JobSubmission(nil, job.Namespace, job.ID, 0)
+	require.NoError(t, err)
+	require.Nil(t, sub)
+}
+
+func TestJobEndpoint_GetJobSubmission_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	job := mock.Job()
+	sub := &structs.JobSubmission{
+		Source: `job "example" {}`,
+		Format: structs.JobSubmissionFormatHCL2,
+	}
+	require.NoError(t, state.UpsertJobWithSubmission(structs.MsgTypeTestSetup, 1000, sub, job))
+
+	get := &structs.JobSubmissionRequest{
+		JobID: job.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	// Lookup with no token should fail
+	var resp structs.JobSubmissionResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Lookup with an invalid token should fail
+	invalidToken := mock.CreatePolicyAndToken(t, state, 1003, "test-invalid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityListJobs}))
+	get.AuthToken = invalidToken.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Lookup with a valid token should succeed
+	validToken := mock.CreatePolicyAndToken(t, state, 1005, "test-valid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+	get.AuthToken = validToken.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &resp))
+	require.NotNil(t, resp.Submission)
+
+	// Lookup with the management token should succeed
+	get.AuthToken = root.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &resp))
+	require.NotNil(t, resp.Submission)
+}
+
 func TestJobEndpoint_GetJobVersions_ACL(t *testing.T) {
 	ci.Parallel(t)
 	require := require.New(t)
@@ -0,0 +1,129 @@
+package nomad
+
+import (
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Keyring encapsulates the root key RPC endpoint. It is only used by servers
+// to replicate the key material of root keys between themselves.
+type Keyring struct {
+	srv    *Server
+	logger log.Logger
+
+	// ctx provides context regarding the underlying connection, so we can
+	// perform TLS certificate validation on internal only endpoints.
+	ctx *RPCContext
+}
+
+// Get returns the key material of a root key. This RPC is only callable by
+// other servers, and is used by the keyring replicator.
+func (k *Keyring) Get(args *structs.KeyringGetRootKeyRequest, reply *structs.KeyringGetRootKeyResponse) error {
+
+	// Ensure the connection was initiated by another server if TLS is used.
+	if err := validateTLSCertificateLevel(k.srv, k.ctx, tlsCertificateLevelServer); err != nil {
+		return err
+	}
+
+	if done, err := k.srv.forward(structs.KeyringGetRootKeyRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "keyring", "get"}, time.Now())
+
+	if args.KeyID == "" {
+		return structs.NewErrRPCCoded(400, "root key ID is required")
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, s *state.StateStore) error {
+
+			// Only return the key material if the key is known to the
+			// cluster.
+			keyMeta, err := s.RootKeyMetaByID(ws, args.KeyID)
+			if err != nil {
+				return err
+			}
+			if keyMeta == nil {
+				reply.Key = nil
+				reply.Index, err = s.Index(state.TableRootKeyMeta)
+				return err
+			}
+
+			// The key material may not have been replicated to this server
+			// yet, in which case the caller will try another server.
+			rootKey, err := k.srv.encrypter.GetKey(args.KeyID)
+			if err != nil {
+				reply.Key = nil
+			} else {
+				reply.Key = &structs.RootKey{
+					Meta: keyMeta.Copy(),
+					Key:  rootKey.Key,
+				}
+			}
+			reply.Index = keyMeta.ModifyIndex
+			return nil
+		},
+	}
+	return k.srv.blockingRPC(&opts)
+}
+
+// ListPublic returns the public keys of the keyring, which third parties use
+// to verify workload identities. No ACL token is required, as the keys are
+// public.
+func (k *Keyring) ListPublic(args *structs.GenericRequest, reply *structs.KeyringListPublicResponse) error {
+	if done, err := k.srv.forward(structs.KeyringListPublicRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "keyring", "list_public"}, time.Now())
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, s *state.StateStore) error {
+
+			iter, err := s.RootKeyMetas(ws)
+			if err != nil {
+				return err
+			}
+
+			pubKeys := []*structs.KeyringPublicKey{}
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				keyMeta := raw.(*structs.RootKeyMeta)
+
+				// Keys which have not yet been replicated to this server
+				// cannot have been used to sign any identity it knows of.
+				pubKey, err := k.srv.encrypter.GetPublicKey(keyMeta.KeyID)
+				if err != nil {
+					continue
+				}
+
+				pubKeys = append(pubKeys, &structs.KeyringPublicKey{
+					KeyID:      keyMeta.KeyID,
+					PublicKey:  pubKey,
+					Algorithm:  structs.PubKeyAlgEdDSA,
+					Use:        structs.PubKeyUseSig,
+					CreateTime: keyMeta.CreateTime,
+				})
+			}
+			reply.PublicKeys = pubKeys
+
+			index, err := s.Index(state.TableRootKeyMeta)
+			if err != nil {
+				return err
+			}
+			reply.Index = index
+			return nil
+		},
+	}
+	return k.srv.blockingRPC(&opts)
+}
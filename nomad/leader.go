@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math/rand"
 	"net"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -48,6 +49,8 @@ var minJobRegisterAtomicEvalVersion = version.Must(version.NewVersion("0.12.1"))
 
 var minOneTimeAuthenticationTokenVersion = version.Must(version.NewVersion("1.1.0"))
 
+var minVariablesVersion = version.Must(version.NewVersion("1.3.2"))
+
 // monitorLeadership is used to monitor if we acquire or lose our role
 // as the leader in the Raft cluster. There is some work the leader is
 // expected to do, so we must react to changes
@@ -347,6 +350,9 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 	// Reap any failed evaluations
 	go s.reapFailedEvaluations(stopCh)
 
+	// Create the first root key used to encrypt variables, if required
+	go s.initializeKeyring(stopCh)
+
 	// Reap any duplicate blocked evaluations
 	go s.reapDupBlockedEvaluations(stopCh)
 
@@ -378,7 +384,11 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 	if s.config.ACLEnabled && s.config.Region != s.config.AuthoritativeRegion {
 		go s.replicateACLPolicies(stopCh)
 		go s.replicateACLTokens(stopCh)
+		go s.replicateACLRoles(stopCh)
+		go s.replicateACLAuthMethods(stopCh)
+		go s.replicateACLBindingRules(stopCh)
 		go s.replicateNamespaces(stopCh)
+		go s.replicateQuotaSpecs(stopCh)
 	}
 
 	// Setup any enterprise systems required.
@@ -493,6 +503,100 @@ ERR_WAIT:
 	}
 }
 
+// replicateQuotaSpecs is used to replicate quota specifications from the
+// authoritative region to this region.
+func (s *Server) replicateQuotaSpecs(stopCh chan struct{}) {
+	req := structs.QuotaSpecListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:     s.config.AuthoritativeRegion,
+			AllowStale: true,
+		},
+	}
+	limiter := rate.NewLimiter(replicationRateLimit, int(replicationRateLimit))
+	s.logger.Debug("starting quota specification replication from authoritative region", "region", req.Region)
+
+START:
+	for {
+		select {
+		case <-stopCh:
+			return
+		default:
+		}
+
+		// Rate limit how often we attempt replication
+		limiter.Wait(context.Background())
+
+		// Fetch the list of quota specifications
+		var resp structs.QuotaSpecListResponse
+		req.AuthToken = s.ReplicationToken()
+		err := s.forwardRegion(s.config.AuthoritativeRegion, "Quota.ListQuotaSpecs", &req, &resp)
+		if err != nil {
+			s.logger.Error("failed to fetch quota specifications from authoritative region", "error", err)
+			goto ERR_WAIT
+		}
+
+		// Perform a two-way diff
+		delete, update := diffQuotaSpecs(s.State(), req.MinQueryIndex, resp.Quotas)
+
+		// Delete quota specifications that should not exist
+		if len(delete) > 0 {
+			args := &structs.QuotaSpecDeleteRequest{
+				Names: delete,
+			}
+			_, _, err := s.raftApply(structs.QuotaSpecDeleteRequestType, args)
+			if err != nil {
+				s.logger.Error("failed to delete quota specifications", "error", err)
+				goto ERR_WAIT
+			}
+		}
+
+		// Fetch any outdated quota specifications
+		var fetched []*structs.QuotaSpec
+		if len(update) > 0 {
+			req := structs.QuotaSpecSetRequest{
+				Names: update,
+				QueryOptions: structs.QueryOptions{
+					Region:        s.config.AuthoritativeRegion,
+					AuthToken:     s.ReplicationToken(),
+					AllowStale:    true,
+					MinQueryIndex: resp.Index - 1,
+				},
+			}
+			var reply structs.QuotaSpecSetResponse
+			if err := s.forwardRegion(s.config.AuthoritativeRegion, "Quota.GetQuotaSpecs", &req, &reply); err != nil {
+				s.logger.Error("failed to fetch quota specifications from authoritative region", "error", err)
+				goto ERR_WAIT
+			}
+			for _, spec := range reply.Quotas {
+				fetched = append(fetched, spec)
+			}
+		}
+
+		// Update local quota specifications
+		if len(fetched) > 0 {
+			args := &structs.QuotaSpecUpsertRequest{
+				Quotas: fetched,
+			}
+			_, _, err := s.raftApply(structs.QuotaSpecUpsertRequestType, args)
+			if err != nil {
+				s.logger.Error("failed to update quota specifications", "error", err)
+				goto ERR_WAIT
+			}
+		}
+
+		// Update the minimum query index, blocks until there is a change.
+		req.MinQueryIndex = resp.Index
+	}
+
+ERR_WAIT:
+	select {
+	case <-time.After(s.config.ReplicationBackoff):
+		goto START
+	case <-stopCh:
+		return
+	}
+}
+
 func (s *Server) handlePausableWorkers(isLeader bool) {
 	for _, w := range s.pausableWorkers() {
 		if isLeader {
@@ -549,6 +653,48 @@ func diffNamespaces(state *state.StateStore, minIndex uint64, remoteList []*stru
 	return
 }
 
+// diffQuotaSpecs is used to perform a two-way diff between the local quota
+// specifications and the remote quota specifications to determine which
+// quota specifications need to be deleted or updated.
+func diffQuotaSpecs(state *state.StateStore, minIndex uint64, remoteList []*structs.QuotaSpec) (delete []string, update []string) {
+	// Construct a set of the local and remote quota specifications
+	local := make(map[string][]byte)
+	remote := make(map[string]struct{})
+
+	// Add all the local quota specifications
+	iter, err := state.QuotaSpecs(nil)
+	if err != nil {
+		panic("failed to iterate local quota specifications")
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		spec := raw.(*structs.QuotaSpec)
+		local[spec.Name] = spec.Hash
+	}
+
+	// Iterate over the remote quota specifications
+	for _, rspec := range remoteList {
+		remote[rspec.Name] = struct{}{}
+
+		// Check if the quota specification is missing locally
+		if localHash, ok := local[rspec.Name]; !ok {
+			update = append(update, rspec.Name)
+
+			// Check if the quota specification is newer remotely and there
+			// is a hash mis-match.
+		} else if rspec.ModifyIndex > minIndex && !bytes.Equal(localHash, rspec.Hash) {
+			update = append(update, rspec.Name)
+		}
+	}
+
+	// Check if quota specifications should be deleted
+	for lspec := range local {
+		if _, ok := remote[lspec]; !ok {
+			delete = append(delete, lspec)
+		}
+	}
+	return
+}
+
 // restoreEvals is used to restore pending evaluations into the eval broker and
 // blocked evaluations into the blocked eval tracker. The broker and blocked
 // eval tracker is maintained only by the leader, so it must be restored anytime
@@ -761,6 +907,10 @@ func (s *Server) schedulePeriodic(stopCh chan struct{}) {
 	defer csiVolumeClaimGC.Stop()
 	oneTimeTokenGC := time.NewTicker(s.config.OneTimeTokenGCInterval)
 	defer oneTimeTokenGC.Stop()
+	aclTokenExpirationGC := time.NewTicker(s.config.ACLTokenExpirationGCInterval)
+	defer aclTokenExpirationGC.Stop()
+	rebalance := time.NewTicker(s.config.RebalanceInterval)
+	defer rebalance.Stop()
 
 	// getLatest grabs the latest index from the state store. It returns true if
 	// the index was retrieved successfully.
@@ -809,6 +959,28 @@ func (s *Server) schedulePeriodic(stopCh chan struct{}) {
 			if index, ok := getLatest(); ok {
 				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobOneTimeTokenGC, index))
 			}
+		case <-aclTokenExpirationGC.C:
+			// Only enqueue the expired token GC jobs when ACLs are enabled,
+			// otherwise there is nothing to collect.
+			if !s.config.ACLEnabled {
+				continue
+			}
+
+			if index, ok := getLatest(); ok {
+				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobLocalTokenExpiredGC, index))
+				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobGlobalTokenExpiredGC, index))
+			}
+		case <-rebalance.C:
+			// Only enqueue the rebalance job when enabled in the scheduler
+			// configuration, otherwise allocations are never migrated.
+			_, schedConfig, err := s.fsm.State().SchedulerConfig()
+			if err != nil || schedConfig == nil || !schedConfig.RebalanceConfig.Enabled {
+				continue
+			}
+
+			if index, ok := getLatest(); ok {
+				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobRebalance, index))
+			}
 		case <-stopCh:
 			return
 		}
@@ -1483,6 +1655,436 @@ func diffACLPolicies(state *state.StateStore, minIndex uint64, remoteList []*str
 	return
 }
 
+// replicateACLRoles is used to replicate ACL roles from the authoritative
+// region to this region. The replication process uses a two-way diff in the
+// same way as ACL policies, deleting roles which no longer exist in the
+// authoritative region and updating those which are missing or outdated.
+func (s *Server) replicateACLRoles(stopCh chan struct{}) {
+	req := structs.ACLRolesListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:     s.config.AuthoritativeRegion,
+			AllowStale: true,
+		},
+	}
+	limiter := rate.NewLimiter(replicationRateLimit, int(replicationRateLimit))
+	s.logger.Debug("starting ACL role replication from authoritative region", "authoritative_region", req.Region)
+
+START:
+	for {
+		select {
+		case <-stopCh:
+			return
+		default:
+			// Rate limit how often we attempt replication
+			limiter.Wait(context.Background())
+
+			// Fetch the list of roles
+			var resp structs.ACLRolesListResponse
+			req.AuthToken = s.ReplicationToken()
+			err := s.forwardRegion(s.config.AuthoritativeRegion,
+				structs.ACLListRolesRPCMethod, &req, &resp)
+			if err != nil {
+				s.logger.Error("failed to fetch ACL roles from authoritative region", "error", err)
+				goto ERR_WAIT
+			}
+
+			// Perform a two-way diff
+			delete, update := diffACLRoles(s.State(), req.MinQueryIndex, resp.ACLRoles)
+
+			// Delete roles that should not exist
+			if len(delete) > 0 {
+				args := &structs.ACLRolesDeleteByIDRequest{
+					ACLRoleIDs: delete,
+				}
+				_, _, err := s.raftApply(structs.ACLRolesDeleteByIDRequestType, args)
+				if err != nil {
+					s.logger.Error("failed to delete ACL roles", "error", err)
+					goto ERR_WAIT
+				}
+			}
+
+			// Fetch any outdated roles
+			var fetched []*structs.ACLRole
+			if len(update) > 0 {
+				req := structs.ACLRolesByIDRequest{
+					ACLRoleIDs: update,
+					QueryOptions: structs.QueryOptions{
+						Region:        s.config.AuthoritativeRegion,
+						AuthToken:     s.ReplicationToken(),
+						AllowStale:    true,
+						MinQueryIndex: resp.Index - 1,
+					},
+				}
+				var reply structs.ACLRolesByIDResponse
+				if err := s.forwardRegion(s.config.AuthoritativeRegion,
+					structs.ACLGetRolesByIDRPCMethod, &req, &reply); err != nil {
+					s.logger.Error("failed to fetch ACL roles from authoritative region", "error", err)
+					goto ERR_WAIT
+				}
+				for _, role := range reply.ACLRoles {
+					fetched = append(fetched, role)
+				}
+			}
+
+			// Update local roles. The policies linked to the roles may not
+			// have been replicated yet, so do not enforce their existence.
+			if len(fetched) > 0 {
+				args := &structs.ACLRolesUpsertRequest{
+					ACLRoles:             fetched,
+					AllowMissingPolicies: true,
+				}
+				_, _, err := s.raftApply(structs.ACLRolesUpsertRequestType, args)
+				if err != nil {
+					s.logger.Error("failed to update ACL roles", "error", err)
+					goto ERR_WAIT
+				}
+			}
+
+			// Update the minimum query index, blocks until there
+			// is a change.
+			req.MinQueryIndex = resp.Index
+		}
+	}
+
+ERR_WAIT:
+	select {
+	case <-time.After(s.config.ReplicationBackoff):
+		goto START
+	case <-stopCh:
+		return
+	}
+}
+
+// diffACLRoles is used to perform a two-way diff between the local ACL roles
+// and the remote roles to determine which roles need to be deleted or
+// updated. The returned values are ACL role IDs.
+func diffACLRoles(state *state.StateStore, minIndex uint64, remoteList []*structs.ACLRoleListStub) (delete []string, update []string) {
+	// Construct a set of the local and remote roles
+	local := make(map[string][]byte)
+	remote := make(map[string]struct{})
+
+	// Add all the local roles
+	iter, err := state.GetACLRoles(nil)
+	if err != nil {
+		panic("failed to iterate local ACL roles")
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		role := raw.(*structs.ACLRole)
+		local[role.ID] = role.Hash
+	}
+
+	// Iterate over the remote roles
+	for _, rr := range remoteList {
+		remote[rr.ID] = struct{}{}
+
+		// Check if the role is missing locally
+		if localHash, ok := local[rr.ID]; !ok {
+			update = append(update, rr.ID)
+
+			// Check if role is newer remotely and there is a hash mis-match.
+		} else if rr.ModifyIndex > minIndex && !bytes.Equal(localHash, rr.Hash) {
+			update = append(update, rr.ID)
+		}
+	}
+
+	// Check if role should be deleted
+	for lr := range local {
+		if _, ok := remote[lr]; !ok {
+			delete = append(delete, lr)
+		}
+	}
+	return
+}
+
+// replicateACLAuthMethods is used to replicate ACL auth methods from the
+// authoritative region to this region. The replication process uses a
+// two-way diff in the same way as ACL roles.
+func (s *Server) replicateACLAuthMethods(stopCh chan struct{}) {
+	req := structs.ACLAuthMethodListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:     s.config.AuthoritativeRegion,
+			AllowStale: true,
+		},
+	}
+	limiter := rate.NewLimiter(replicationRateLimit, int(replicationRateLimit))
+	s.logger.Debug("starting ACL auth method replication from authoritative region", "authoritative_region", req.Region)
+
+START:
+	for {
+		select {
+		case <-stopCh:
+			return
+		default:
+			// Rate limit how often we attempt replication
+			limiter.Wait(context.Background())
+
+			// Fetch the list of auth methods
+			var resp structs.ACLAuthMethodListResponse
+			req.AuthToken = s.ReplicationToken()
+			err := s.forwardRegion(s.config.AuthoritativeRegion,
+				structs.ACLListAuthMethodsRPCMethod, &req, &resp)
+			if err != nil {
+				s.logger.Error("failed to fetch ACL auth methods from authoritative region", "error", err)
+				goto ERR_WAIT
+			}
+
+			// Perform a two-way diff
+			delete, update := diffACLAuthMethods(s.State(), req.MinQueryIndex, resp.AuthMethods)
+
+			// Delete auth methods that should not exist
+			if len(delete) > 0 {
+				args := &structs.ACLAuthMethodDeleteRequest{
+					Names: delete,
+				}
+				_, _, err := s.raftApply(structs.ACLAuthMethodsDeleteRequestType, args)
+				if err != nil {
+					s.logger.Error("failed to delete ACL auth methods", "error", err)
+					goto ERR_WAIT
+				}
+				for _, name := range delete {
+					s.oidcProviderCache.Delete(name)
+				}
+			}
+
+			// Fetch any outdated auth methods
+			var fetched []*structs.ACLAuthMethod
+			if len(update) > 0 {
+				req := structs.ACLAuthMethodsGetRequest{
+					Names: update,
+					QueryOptions: structs.QueryOptions{
+						Region:        s.config.AuthoritativeRegion,
+						AuthToken:     s.ReplicationToken(),
+						AllowStale:    true,
+						MinQueryIndex: resp.Index - 1,
+					},
+				}
+				var reply structs.ACLAuthMethodsGetResponse
+				if err := s.forwardRegion(s.config.AuthoritativeRegion,
+					structs.ACLGetAuthMethodsRPCMethod, &req, &reply); err != nil {
+					s.logger.Error("failed to fetch ACL auth methods from authoritative region", "error", err)
+					goto ERR_WAIT
+				}
+				for _, method := range reply.AuthMethods {
+					fetched = append(fetched, method)
+				}
+			}
+
+			// Update local auth methods. Non-default methods are written
+			// first, so a change of default auth method does not conflict
+			// with the previous default.
+			if len(fetched) > 0 {
+				sort.SliceStable(fetched, func(i, j int) bool {
+					return !fetched[i].Default && fetched[j].Default
+				})
+				args := &structs.ACLAuthMethodUpsertRequest{
+					AuthMethods: fetched,
+				}
+				_, _, err := s.raftApply(structs.ACLAuthMethodsUpsertRequestType, args)
+				if err != nil {
+					s.logger.Error("failed to update ACL auth methods", "error", err)
+					goto ERR_WAIT
+				}
+			}
+
+			// Update the minimum query index, blocks until there
+			// is a change.
+			req.MinQueryIndex = resp.Index
+		}
+	}
+
+ERR_WAIT:
+	select {
+	case <-time.After(s.config.ReplicationBackoff):
+		goto START
+	case <-stopCh:
+		return
+	}
+}
+
+// diffACLAuthMethods is used to perform a two-way diff between the local ACL
+// auth methods and the remote auth methods to determine which need to be
+// deleted or updated. The returned values are ACL auth method names.
+func diffACLAuthMethods(state *state.StateStore, minIndex uint64, remoteList []*structs.ACLAuthMethodStub) (delete []string, update []string) {
+	// Construct a set of the local and remote auth methods
+	local := make(map[string][]byte)
+	remote := make(map[string]struct{})
+
+	// Add all the local auth methods
+	iter, err := state.GetACLAuthMethods(nil)
+	if err != nil {
+		panic("failed to iterate local ACL auth methods")
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		method := raw.(*structs.ACLAuthMethod)
+		local[method.Name] = method.Hash
+	}
+
+	// Iterate over the remote auth methods
+	for _, rm := range remoteList {
+		remote[rm.Name] = struct{}{}
+
+		// Check if the auth method is missing locally
+		if localHash, ok := local[rm.Name]; !ok {
+			update = append(update, rm.Name)
+
+			// Check if auth method is newer remotely and there is a hash
+			// mis-match.
+		} else if rm.ModifyIndex > minIndex && !bytes.Equal(localHash, rm.Hash) {
+			update = append(update, rm.Name)
+		}
+	}
+
+	// Check if auth method should be deleted
+	for lm := range local {
+		if _, ok := remote[lm]; !ok {
+			delete = append(delete, lm)
+		}
+	}
+	return
+}
+
+// replicateACLBindingRules is used to replicate ACL binding rules from the
+// authoritative region to this region. The replication process uses a
+// two-way diff in the same way as ACL roles.
+func (s *Server) replicateACLBindingRules(stopCh chan struct{}) {
+	req := structs.ACLBindingRulesListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:     s.config.AuthoritativeRegion,
+			AllowStale: true,
+		},
+	}
+	limiter := rate.NewLimiter(replicationRateLimit, int(replicationRateLimit))
+	s.logger.Debug("starting ACL binding rule replication from authoritative region", "authoritative_region", req.Region)
+
+START:
+	for {
+		select {
+		case <-stopCh:
+			return
+		default:
+			// Rate limit how often we attempt replication
+			limiter.Wait(context.Background())
+
+			// Fetch the list of binding rules
+			var resp structs.ACLBindingRulesListResponse
+			req.AuthToken = s.ReplicationToken()
+			err := s.forwardRegion(s.config.AuthoritativeRegion,
+				structs.ACLListBindingRulesRPCMethod, &req, &resp)
+			if err != nil {
+				s.logger.Error("failed to fetch ACL binding rules from authoritative region", "error", err)
+				goto ERR_WAIT
+			}
+
+			// Perform a two-way diff
+			delete, update := diffACLBindingRules(s.State(), req.MinQueryIndex, resp.ACLBindingRules)
+
+			// Delete binding rules that should not exist
+			if len(delete) > 0 {
+				args := &structs.ACLBindingRulesDeleteRequest{
+					ACLBindingRuleIDs: delete,
+				}
+				_, _, err := s.raftApply(structs.ACLBindingRulesDeleteRequestType, args)
+				if err != nil {
+					s.logger.Error("failed to delete ACL binding rules", "error", err)
+					goto ERR_WAIT
+				}
+			}
+
+			// Fetch any outdated binding rules
+			var fetched []*structs.ACLBindingRule
+			if len(update) > 0 {
+				req := structs.ACLBindingRulesRequest{
+					ACLBindingRuleIDs: update,
+					QueryOptions: structs.QueryOptions{
+						Region:        s.config.AuthoritativeRegion,
+						AuthToken:     s.ReplicationToken(),
+						AllowStale:    true,
+						MinQueryIndex: resp.Index - 1,
+					},
+				}
+				var reply structs.ACLBindingRulesResponse
+				if err := s.forwardRegion(s.config.AuthoritativeRegion,
+					structs.ACLGetBindingRulesRPCMethod, &req, &reply); err != nil {
+					s.logger.Error("failed to fetch ACL binding rules from authoritative region", "error", err)
+					goto ERR_WAIT
+				}
+				for _, rule := range reply.ACLBindingRules {
+					fetched = append(fetched, rule)
+				}
+			}
+
+			// Update local binding rules. The auth methods linked to the
+			// rules may not have been replicated yet, so do not enforce
+			// their existence.
+			if len(fetched) > 0 {
+				args := &structs.ACLBindingRulesUpsertRequest{
+					ACLBindingRules:         fetched,
+					AllowMissingAuthMethods: true,
+				}
+				_, _, err := s.raftApply(structs.ACLBindingRulesUpsertRequestType, args)
+				if err != nil {
+					s.logger.Error("failed to update ACL binding rules", "error", err)
+					goto ERR_WAIT
+				}
+			}
+
+			// Update the minimum query index, blocks until there
+			// is a change.
+			req.MinQueryIndex = resp.Index
+		}
+	}
+
+ERR_WAIT:
+	select {
+	case <-time.After(s.config.ReplicationBackoff):
+		goto START
+	case <-stopCh:
+		return
+	}
+}
+
+// diffACLBindingRules is used to perform a two-way diff between the local ACL
+// binding rules and the remote binding rules to determine which need to be
+// deleted or updated. The returned values are ACL binding rule IDs.
+func diffACLBindingRules(state *state.StateStore, minIndex uint64, remoteList []*structs.ACLBindingRuleListStub) (delete []string, update []string) {
+	// Construct a set of the local and remote binding rules
+	local := make(map[string][]byte)
+	remote := make(map[string]struct{})
+
+	// Add all the local binding rules
+	iter, err := state.GetACLBindingRules(nil)
+	if err != nil {
+		panic("failed to iterate local ACL binding rules")
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		rule := raw.(*structs.ACLBindingRule)
+		local[rule.ID] = rule.Hash
+	}
+
+	// Iterate over the remote binding rules
+	for _, rr := range remoteList {
+		remote[rr.ID] = struct{}{}
+
+		// Check if the binding rule is missing locally
+		if localHash, ok := local[rr.ID]; !ok {
+			update = append(update, rr.ID)
+
+			// Check if binding rule is newer remotely and there is a hash
+			// mis-match.
+		} else if rr.ModifyIndex > minIndex && !bytes.Equal(localHash, rr.Hash) {
+			update = append(update, rr.ID)
+		}
+	}
+
+	// Check if binding rule should be deleted
+	for lr := range local {
+		if _, ok := remote[lr]; !ok {
+			delete = append(delete, lr)
+		}
+	}
+	return
+}
+
 // replicateACLTokens is used to replicate global ACL tokens from
 // the authoritative region to this region.
 func (s *Server) replicateACLTokens(stopCh chan struct{}) {
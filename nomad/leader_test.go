@@ -949,6 +949,217 @@ func TestLeader_DiffACLPolicies(t *testing.T) {
 	assert.Equal(t, []string{p3.Name, p4.Name}, update)
 }
 
+func TestLeader_ReplicateACLRoles(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, func(c *Config) {
+		c.Region = "region1"
+		c.AuthoritativeRegion = "region1"
+		c.ACLEnabled = true
+	})
+	defer cleanupS1()
+	s2, _, cleanupS2 := TestACLServer(t, func(c *Config) {
+		c.Region = "region2"
+		c.AuthoritativeRegion = "region1"
+		c.ACLEnabled = true
+		c.ReplicationBackoff = 20 * time.Millisecond
+		c.ReplicationToken = root.SecretID
+	})
+	defer cleanupS2()
+	TestJoin(t, s1, s2)
+	testutil.WaitForLeader(t, s1.RPC)
+	testutil.WaitForLeader(t, s2.RPC)
+
+	// Write a role to the authoritative region. The linked policies do not
+	// need to exist for replication to succeed.
+	aclRole := mock.ACLRole()
+	require.NoError(t, s1.State().UpsertACLRoles(
+		structs.MsgTypeTestSetup, 100, []*structs.ACLRole{aclRole}, true))
+
+	// Wait for the role to replicate
+	testutil.WaitForResult(func() (bool, error) {
+		out, err := s2.State().GetACLRoleByID(nil, aclRole.ID)
+		return out != nil, err
+	}, func(err error) {
+		t.Fatalf("should replicate ACL role")
+	})
+
+	// Delete the role from the authoritative region and wait for the
+	// deletion to replicate.
+	require.NoError(t, s1.State().DeleteACLRolesByID(
+		structs.MsgTypeTestSetup, 200, []string{aclRole.ID}))
+
+	testutil.WaitForResult(func() (bool, error) {
+		out, err := s2.State().GetACLRoleByID(nil, aclRole.ID)
+		return out == nil, err
+	}, func(err error) {
+		t.Fatalf("should replicate ACL role deletion")
+	})
+}
+
+func TestLeader_DiffACLRoles(t *testing.T) {
+	ci.Parallel(t)
+
+	state := state.TestStateStore(t)
+
+	// Populate the local state
+	r1 := mock.ACLRole()
+	r2 := mock.ACLRole()
+	r3 := mock.ACLRole()
+	require.NoError(t, state.UpsertACLRoles(
+		structs.MsgTypeTestSetup, 100, []*structs.ACLRole{r1, r2, r3}, true))
+
+	// Simulate a remote list
+	r2Stub := r2.Stub()
+	r2Stub.ModifyIndex = 50 // Ignored, same index
+	r3Stub := r3.Stub()
+	r3Stub.ModifyIndex = 100 // Updated, higher index
+	r3Stub.Hash = []byte{0, 1, 2, 3}
+	r4 := mock.ACLRole()
+	remoteList := []*structs.ACLRoleListStub{
+		r2Stub,
+		r3Stub,
+		r4.Stub(),
+	}
+	delete, update := diffACLRoles(state, 50, remoteList)
+
+	// R1 does not exist on the remote side, should delete
+	require.Equal(t, []string{r1.ID}, delete)
+
+	// R2 is un-modified - ignore. R3 modified, R4 new.
+	require.Equal(t, []string{r3.ID, r4.ID}, update)
+}
+
+func TestLeader_ReplicateACLAuthMethods(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, func(c *Config) {
+		c.Region = "region1"
+		c.AuthoritativeRegion = "region1"
+		c.ACLEnabled = true
+	})
+	defer cleanupS1()
+	s2, _, cleanupS2 := TestACLServer(t, func(c *Config) {
+		c.Region = "region2"
+		c.AuthoritativeRegion = "region1"
+		c.ACLEnabled = true
+		c.ReplicationBackoff = 20 * time.Millisecond
+		c.ReplicationToken = root.SecretID
+	})
+	defer cleanupS2()
+	TestJoin(t, s1, s2)
+	testutil.WaitForLeader(t, s1.RPC)
+	testutil.WaitForLeader(t, s2.RPC)
+
+	// Write an auth method and binding rule to the authoritative region.
+	authMethod := mock.ACLAuthMethod()
+	require.NoError(t, s1.State().UpsertACLAuthMethods(
+		structs.MsgTypeTestSetup, 100, []*structs.ACLAuthMethod{authMethod}))
+
+	bindingRule := mock.ACLBindingRule()
+	bindingRule.AuthMethod = authMethod.Name
+	require.NoError(t, s1.State().UpsertACLBindingRules(
+		structs.MsgTypeTestSetup, 110, []*structs.ACLBindingRule{bindingRule}, false))
+
+	// Wait for the auth method and binding rule to replicate
+	testutil.WaitForResult(func() (bool, error) {
+		out, err := s2.State().GetACLAuthMethodByName(nil, authMethod.Name)
+		return out != nil, err
+	}, func(err error) {
+		t.Fatalf("should replicate ACL auth method")
+	})
+	testutil.WaitForResult(func() (bool, error) {
+		out, err := s2.State().GetACLBindingRule(nil, bindingRule.ID)
+		return out != nil, err
+	}, func(err error) {
+		t.Fatalf("should replicate ACL binding rule")
+	})
+
+	// Delete the auth method from the authoritative region, which also
+	// deletes the binding rule, and wait for the deletion to replicate.
+	require.NoError(t, s1.State().DeleteACLAuthMethods(
+		structs.MsgTypeTestSetup, 200, []string{authMethod.Name}))
+
+	testutil.WaitForResult(func() (bool, error) {
+		out, err := s2.State().GetACLAuthMethodByName(nil, authMethod.Name)
+		return out == nil, err
+	}, func(err error) {
+		t.Fatalf("should replicate ACL auth method deletion")
+	})
+	testutil.WaitForResult(func() (bool, error) {
+		out, err := s2.State().GetACLBindingRule(nil, bindingRule.ID)
+		return out == nil, err
+	}, func(err error) {
+		t.Fatalf("should replicate ACL binding rule deletion")
+	})
+}
+
+func TestLeader_DiffACLAuthMethods(t *testing.T) {
+	ci.Parallel(t)
+
+	state := state.TestStateStore(t)
+
+	// Populate the local state
+	am1 := mock.ACLAuthMethod()
+	am2 := mock.ACLAuthMethod()
+	am3 := mock.ACLAuthMethod()
+	require.NoError(t, state.UpsertACLAuthMethods(
+		structs.MsgTypeTestSetup, 100, []*structs.ACLAuthMethod{am1, am2, am3}))
+
+	// Simulate a remote list
+	am2Stub := am2.Stub()
+	am2Stub.ModifyIndex = 50 // Ignored, same index
+	am3Stub := am3.Stub()
+	am3Stub.ModifyIndex = 100 // Updated, higher index
+	am3Stub.Hash = []byte{0, 1, 2, 3}
+	am4 := mock.ACLAuthMethod()
+	remoteList := []*structs.ACLAuthMethodStub{
+		am2Stub,
+		am3Stub,
+		am4.Stub(),
+	}
+	delete, update := diffACLAuthMethods(state, 50, remoteList)
+
+	// AM1 does not exist on the remote side, should delete
+	require.Equal(t, []string{am1.Name}, delete)
+
+	// AM2 is un-modified - ignore. AM3 modified, AM4 new.
+	require.Equal(t, []string{am3.Name, am4.Name}, update)
+}
+
+func TestLeader_DiffACLBindingRules(t *testing.T) {
+	ci.Parallel(t)
+
+	state := state.TestStateStore(t)
+
+	// Populate the local state
+	br1 := mock.ACLBindingRule()
+	br2 := mock.ACLBindingRule()
+	br3 := mock.ACLBindingRule()
+	require.NoError(t, state.UpsertACLBindingRules(
+		structs.MsgTypeTestSetup, 100, []*structs.ACLBindingRule{br1, br2, br3}, true))
+
+	// Simulate a remote list
+	br2Stub := br2.Stub()
+	br2Stub.ModifyIndex = 50 // Ignored, same index
+	br3Stub := br3.Stub()
+	br3Stub.ModifyIndex = 100 // Updated, higher index
+	br3Stub.Hash = []byte{0, 1, 2, 3}
+	br4 := mock.ACLBindingRule()
+	remoteList := []*structs.ACLBindingRuleListStub{
+		br2Stub,
+		br3Stub,
+		br4.Stub(),
+	}
+	delete, update := diffACLBindingRules(state, 50, remoteList)
+
+	// BR1 does not exist on the remote side, should delete
+	require.Equal(t, []string{br1.ID}, delete)
+
+	// BR2 is un-modified - ignore. BR3 modified, BR4 new.
+	require.Equal(t, []string{br3.ID, br4.ID}, update)
+}
+
 func TestLeader_ReplicateACLTokens(t *testing.T) {
 	ci.Parallel(t)
 
@@ -1606,6 +1817,39 @@ func TestLeader_DiffNamespaces(t *testing.T) {
 	assert.Equal(t, []string{ns3.Name, ns4.Name}, update)
 }
 
+func TestLeader_DiffQuotaSpecs(t *testing.T) {
+	ci.Parallel(t)
+
+	state := state.TestStateStore(t)
+
+	// Populate the local state
+	spec1 := mock.QuotaSpec()
+	spec2 := mock.QuotaSpec()
+	spec3 := mock.QuotaSpec()
+	require.NoError(t, state.UpsertQuotaSpecs(structs.MsgTypeTestSetup, 100,
+		[]*structs.QuotaSpec{spec1, spec2, spec3}))
+
+	// Simulate a remote list
+	rspec2 := spec2.Copy()
+	rspec2.ModifyIndex = 50 // Ignored, same index
+	rspec3 := spec3.Copy()
+	rspec3.ModifyIndex = 100 // Updated, higher index
+	rspec3.Hash = []byte{0, 1, 2, 3}
+	spec4 := mock.QuotaSpec()
+	remoteList := []*structs.QuotaSpec{
+		rspec2,
+		rspec3,
+		spec4,
+	}
+	delete, update := diffQuotaSpecs(state, 50, remoteList)
+
+	// spec1 does not exist on the remote side, should delete
+	require.Equal(t, []string{spec1.Name}, delete)
+
+	// spec2 is un-modified - ignore. spec3 modified, spec4 new.
+	require.Equal(t, []string{spec3.Name, spec4.Name}, update)
+}
+
 // waitForStableLeadership waits until a leader is elected and all servers
 // get promoted as voting members, returns the leader
 func waitForStableLeadership(t *testing.T, servers []*Server) *Server {
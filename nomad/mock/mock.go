@@ -17,6 +17,7 @@ func Node() *structs.Node {
 		ID:         uuid.Generate(),
 		SecretID:   uuid.Generate(),
 		Datacenter: "dc1",
+		NodePool:   structs.NodePoolDefault,
 		Name:       "foobar",
 		Drivers: map[string]*structs.DriverInfo{
 			"exec": {
@@ -2081,6 +2082,65 @@ func ACLManagementToken() *structs.ACLToken {
 	}
 }
 
+func ACLRole() *structs.ACLRole {
+	role := &structs.ACLRole{
+		ID:          uuid.Generate(),
+		Name:        fmt.Sprintf("acl-role-%s", uuid.Short()),
+		Description: "mocked-test-acl-role",
+		Policies: []*structs.ACLRolePolicyLink{
+			{Name: "mocked-test-policy-1"},
+			{Name: "mocked-test-policy-2"},
+		},
+		CreateIndex: 10,
+		ModifyIndex: 10,
+	}
+	role.SetHash()
+	return role
+}
+
+func ACLAuthMethod() *structs.ACLAuthMethod {
+	method := structs.ACLAuthMethod{
+		Name:          fmt.Sprintf("acl-auth-method-%s", uuid.Short()),
+		Type:          "OIDC",
+		TokenLocality: "local",
+		MaxTokenTTL:   time.Hour,
+		Default:       false,
+		Config: &structs.ACLAuthMethodConfig{
+			OIDCDiscoveryURL:    "http://example.com",
+			OIDCClientID:        "mock",
+			OIDCClientSecret:    "very secret secret",
+			BoundAudiences:      []string{"audience1", "audience2"},
+			AllowedRedirectURIs: []string{"foo", "bar"},
+			DiscoveryCaPem:      []string{"foo"},
+			SigningAlgs:         []string{"bar"},
+			ClaimMappings:       map[string]string{"foo": "bar"},
+			ListClaimMappings:   map[string]string{"foo": "bar"},
+		},
+		CreateTime:  time.Now().UTC(),
+		CreateIndex: 10,
+		ModifyIndex: 10,
+	}
+	method.SetHash()
+	return &method
+}
+
+func ACLBindingRule() *structs.ACLBindingRule {
+	rule := &structs.ACLBindingRule{
+		ID:          uuid.Generate(),
+		Description: "mocked-acl-binding-rule",
+		AuthMethod:  "auth0",
+		Selector:    "engineering in list.roles",
+		BindType:    "role",
+		BindName:    "eng-ro",
+		CreateTime:  time.Now().UTC(),
+		ModifyTime:  time.Now().UTC(),
+		CreateIndex: 10,
+		ModifyIndex: 10,
+	}
+	rule.SetHash()
+	return rule
+}
+
 func ScalingPolicy() *structs.ScalingPolicy {
 	return &structs.ScalingPolicy{
 		ID:   uuid.Generate(),
@@ -2266,6 +2326,32 @@ func Namespace() *structs.Namespace {
 	return ns
 }
 
+func NodePool() *structs.NodePool {
+	return &structs.NodePool{
+		Name:        fmt.Sprintf("pool-%s", uuid.Short()),
+		Description: "test node pool",
+		Meta:        map[string]string{"team": "test"},
+	}
+}
+
+func QuotaSpec() *structs.QuotaSpec {
+	spec := &structs.QuotaSpec{
+		Name:        fmt.Sprintf("quota-%s", uuid.Short()),
+		Description: "test quota",
+		Limits: []*structs.QuotaLimit{
+			{
+				Region: "global",
+				RegionLimit: &structs.Resources{
+					CPU:      2000,
+					MemoryMB: 2000,
+				},
+			},
+		},
+	}
+	spec.SetHash()
+	return spec
+}
+
 // ServiceRegistrations generates an array containing two unique service
 // registrations.
 func ServiceRegistrations() []*structs.ServiceRegistration {
@@ -2296,3 +2382,35 @@ func ServiceRegistrations() []*structs.ServiceRegistration {
 		},
 	}
 }
+
+// VariableEncrypted returns a variable within the default namespace. The data
+// of the variable is not a valid ciphertext, so it is only useful for tests
+// which do not need to decrypt the variable.
+func VariableEncrypted() *structs.VariableEncrypted {
+	return &structs.VariableEncrypted{
+		VariableMetadata: structs.VariableMetadata{
+			Namespace:  structs.DefaultNamespace,
+			Path:       "nomad/jobs/example/" + uuid.Short(),
+			ModifyTime: time.Now().UnixNano(),
+		},
+		VariableData: structs.VariableData{
+			Data:  []byte(uuid.Generate()),
+			KeyID: uuid.Generate(),
+		},
+	}
+}
+
+// VariableDecrypted returns a variable within the default namespace, with a
+// couple of items.
+func VariableDecrypted() *structs.VariableDecrypted {
+	return &structs.VariableDecrypted{
+		VariableMetadata: structs.VariableMetadata{
+			Namespace: structs.DefaultNamespace,
+			Path:      "nomad/jobs/example/" + uuid.Short(),
+		},
+		Items: structs.VariableItems{
+			"username": "admin",
+			"password": uuid.Generate(),
+		},
+	}
+}
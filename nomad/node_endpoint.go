@@ -126,6 +126,17 @@ func (n *Node) Register(args *structs.NodeRegisterRequest, reply *structs.NodeUp
 		args.Node.SchedulingEligibility = structs.NodeSchedulingEligible
 	}
 
+	// Default to the default node pool if unset
+	if args.Node.NodePool == "" {
+		args.Node.NodePool = structs.NodePoolDefault
+	}
+	if args.Node.NodePool == structs.NodePoolAll {
+		return fmt.Errorf("nodes cannot be registered in the %q node pool", structs.NodePoolAll)
+	}
+	if err := (&structs.NodePool{Name: args.Node.NodePool}).Validate(); err != nil {
+		return fmt.Errorf("invalid node pool for client registration: %v", err)
+	}
+
 	// Set the timestamp when the node is registered
 	args.Node.StatusUpdatedAt = time.Now().Unix()
 
@@ -234,6 +245,7 @@ func shouldCreateNodeEval(original, updated *structs.Node) bool {
 	// and not relevant in this check.
 	return !(original.ID == updated.ID &&
 		original.Datacenter == updated.Datacenter &&
+		original.NodePool == updated.NodePool &&
 		original.Name == updated.Name &&
 		original.NodeClass == updated.NodeClass &&
 		reflect.DeepEqual(original.Attributes, updated.Attributes) &&
@@ -415,6 +427,17 @@ func (n *Node) deregister(args *structs.NodeBatchDeregisterRequest,
 	return nil
 }
 
+// utilizationScoringEnabled returns whether the scheduler configuration opts
+// into scoring nodes by their reported utilization.
+func (n *Node) utilizationScoringEnabled(snap *state.StateSnapshot) bool {
+	_, schedConfig, err := snap.SchedulerConfig()
+	if err != nil {
+		n.logger.Warn("failed to read scheduler configuration", "error", err)
+		return false
+	}
+	return schedConfig != nil && schedConfig.UtilizationScoringEnabled
+}
+
 // UpdateStatus is used to update the status of a client node
 func (n *Node) UpdateStatus(args *structs.NodeUpdateStatusRequest, reply *structs.NodeUpdateResponse) error {
 	isForwarded := args.IsForwarded()
@@ -487,6 +510,23 @@ func (n *Node) UpdateStatus(args *structs.NodeUpdateStatusRequest, reply *struct
 		reply.NodeModifyIndex = index
 	}
 
+	// Persist the utilization reported with the heartbeat, at most once per
+	// update interval and only when the scheduler will use it. Failing to do
+	// so must not fail the heartbeat.
+	if now := time.Now(); args.Utilization != nil && node.Utilization.ShouldUpdate(now) &&
+		n.utilizationScoringEnabled(snap) {
+		utilization := args.Utilization.Copy()
+		utilization.UpdatedAt = now.Unix()
+		req := &structs.NodeUpdateUtilizationRequest{
+			NodeID:       args.NodeID,
+			Utilization:  utilization,
+			WriteRequest: structs.WriteRequest{Region: args.Region},
+		}
+		if _, _, err := n.srv.raftApply(structs.NodeUpdateUtilizationRequestType, req); err != nil {
+			n.logger.Warn("utilization update failed", "node_id", args.NodeID, "error", err)
+		}
+	}
+
 	// Check if we should trigger evaluations
 	if structs.ShouldDrainNode(args.Status) ||
 		nodeStatusTransitionRequiresEval(args.Status, node.Status) {
@@ -1149,6 +1189,7 @@ func (n *Node) UpdateAlloc(args *structs.AllocUpdateRequest, reply *structs.Gene
 	// Update modified timestamp for client initiated allocation updates
 	now := time.Now()
 	var evals []*structs.Evaluation
+	dependencyEvals := make(map[structs.NamespacedID]struct{})
 
 	for _, allocToUpdate := range args.Alloc {
 		evalTriggerBy := ""
@@ -1159,6 +1200,16 @@ func (n *Node) UpdateAlloc(args *structs.AllocUpdateRequest, reply *structs.Gene
 			continue
 		}
 
+		// Allocations becoming ready may unblock the task groups which
+		// depend on their task group
+		jobID := structs.NewNamespacedID(alloc.JobID, alloc.Namespace)
+		if _, ok := dependencyEvals[jobID]; !ok {
+			if eval := n.dependencyEval(alloc, allocToUpdate, now); eval != nil {
+				dependencyEvals[jobID] = struct{}{}
+				evals = append(evals, eval)
+			}
+		}
+
 		if !allocToUpdate.TerminalStatus() && alloc.ClientStatus != structs.AllocClientStatusUnknown {
 			continue
 		}
@@ -1275,6 +1326,52 @@ func (n *Node) UpdateAlloc(args *structs.AllocUpdateRequest, reply *structs.Gene
 	return nil
 }
 
+// dependencyEval returns an evaluation for the job of the allocation if the
+// update makes it satisfy the dependency condition of a task group depending
+// on its task group, or nil otherwise. Allocations of an active deployment
+// are left to the deployment watcher.
+func (n *Node) dependencyEval(alloc, allocToUpdate *structs.Allocation, now time.Time) *structs.Evaluation {
+	if alloc.ClientStatus == structs.AllocClientStatusUnknown {
+		return nil
+	}
+
+	job, err := n.srv.State().JobByID(nil, alloc.Namespace, alloc.JobID)
+	if err != nil || job == nil || job.Stop {
+		return nil
+	}
+
+	satisfied := false
+	for _, tg := range job.DependentTaskGroups(alloc.TaskGroup) {
+		condition := tg.DependencyCondition()
+		if allocToUpdate.SatisfiesDependency(condition) && !alloc.SatisfiesDependency(condition) {
+			satisfied = true
+			break
+		}
+	}
+	if !satisfied {
+		return nil
+	}
+
+	if alloc.DeploymentID != "" {
+		deployment, err := n.srv.State().DeploymentByID(nil, alloc.DeploymentID)
+		if err == nil && deployment != nil && deployment.Active() {
+			return nil
+		}
+	}
+
+	return &structs.Evaluation{
+		ID:          uuid.Generate(),
+		Namespace:   alloc.Namespace,
+		TriggeredBy: structs.EvalTriggerDependency,
+		JobID:       alloc.JobID,
+		Type:        job.Type,
+		Priority:    job.Priority,
+		Status:      structs.EvalStatusPending,
+		CreateTime:  now.UTC().UnixNano(),
+		ModifyTime:  now.UTC().UnixNano(),
+	}
+}
+
 // batchUpdate is used to update all the allocations
 func (n *Node) batchUpdate(future *structs.BatchFuture, updates []*structs.Allocation, evals []*structs.Evaluation) {
 	var mErr multierror.Error
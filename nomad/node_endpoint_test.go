@@ -831,6 +831,72 @@ func TestClientEndpoint_UpdateStatus_HeartbeatOnly(t *testing.T) {
 	}
 }
 
+func TestClientEndpoint_UpdateStatus_Utilization(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	node := mock.Node()
+	reg := &structs.NodeRegisterRequest{
+		Node:         node,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.NodeUpdateResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Node.Register", reg, &resp))
+
+	// Heartbeat with the utilization of the node
+	heartbeat := &structs.NodeUpdateStatusRequest{
+		NodeID: node.ID,
+		Status: node.Status,
+		Utilization: &structs.NodeUtilization{
+			CpuUsedMHz:   1000,
+			MemoryUsedMB: 512,
+		},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp2 structs.NodeUpdateResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Node.UpdateStatus", heartbeat, &resp2))
+
+	// Utilization is not persisted while utilization scoring is disabled
+	out, err := s1.fsm.State().NodeByID(nil, node.ID)
+	require.NoError(err)
+	require.Nil(out.Utilization)
+	modifyIndex := out.ModifyIndex
+
+	_, schedConfig, err := s1.fsm.State().SchedulerConfig()
+	require.NoError(err)
+	config := *schedConfig
+	config.UtilizationScoringEnabled = true
+	require.NoError(s1.fsm.State().SchedulerSetConfig(resp2.Index+1, &config))
+
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Node.UpdateStatus", heartbeat, &resp2))
+
+	// Persisting utilization does not modify the node
+	out, err = s1.fsm.State().NodeByID(nil, node.ID)
+	require.NoError(err)
+	require.NotNil(out.Utilization)
+	require.EqualValues(1000, out.Utilization.CpuUsedMHz)
+	require.EqualValues(512, out.Utilization.MemoryUsedMB)
+	require.NotZero(out.Utilization.UpdatedAt)
+	require.Equal(modifyIndex, out.ModifyIndex)
+
+	// Utilization reported again within the update interval is not persisted
+	heartbeat.Utilization = &structs.NodeUtilization{
+		CpuUsedMHz:   2000,
+		MemoryUsedMB: 1024,
+	}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Node.UpdateStatus", heartbeat, &resp2))
+
+	out, err = s1.fsm.State().NodeByID(nil, node.ID)
+	require.NoError(err)
+	require.EqualValues(1000, out.Utilization.CpuUsedMHz)
+	require.Equal(modifyIndex, out.ModifyIndex)
+}
+
 func TestClientEndpoint_UpdateStatus_HeartbeatOnly_Advertise(t *testing.T) {
 	ci.Parallel(t)
 	require := require.New(t)
@@ -2527,6 +2593,80 @@ func TestClientEndpoint_UpdateAlloc(t *testing.T) {
 
 }
 
+func TestClientEndpoint_UpdateAlloc_DependsOn(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	node := mock.Node()
+	state := s1.fsm.State()
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 98, node))
+
+	// Inject a job whose web group depends on its db group
+	job := mock.Job()
+	db := job.TaskGroups[0].Copy()
+	db.Name = "db"
+	job.TaskGroups = append(job.TaskGroups, db)
+	job.TaskGroups[0].DependsOn = []string{"db"}
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 99, job))
+
+	var allocs []*structs.Allocation
+	for i := 0; i < 2; i++ {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = node.ID
+		alloc.TaskGroup = db.Name
+		alloc.DeploymentID = ""
+		allocs = append(allocs, alloc)
+	}
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 100, allocs))
+
+	// updateAllocs updates the client status of the allocations and returns
+	// the dependency evaluations of the job
+	updateAllocs := func(status string) []*structs.Evaluation {
+		var update []*structs.Allocation
+		for _, alloc := range allocs {
+			clientAlloc := alloc.Copy()
+			clientAlloc.ClientStatus = status
+			update = append(update, clientAlloc)
+		}
+		req := &structs.AllocUpdateRequest{
+			Alloc:        update,
+			WriteRequest: structs.WriteRequest{Region: "global"},
+		}
+		var resp structs.NodeAllocsResponse
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.UpdateAlloc", req, &resp))
+
+		evals, err := state.EvalsByJob(nil, job.Namespace, job.ID)
+		require.NoError(t, err)
+		var out []*structs.Evaluation
+		for _, eval := range evals {
+			if eval.TriggeredBy == structs.EvalTriggerDependency {
+				out = append(out, eval)
+			}
+		}
+		return out
+	}
+
+	// Allocations which are not running do not unblock the web group
+	require.Empty(t, updateAllocs(structs.AllocClientStatusPending))
+
+	// A single evaluation is created for the running allocations
+	evals := updateAllocs(structs.AllocClientStatusRunning)
+	require.Len(t, evals, 1)
+	require.Equal(t, job.Type, evals[0].Type)
+	require.Equal(t, job.Priority, evals[0].Priority)
+
+	// Allocations already running do not create more evaluations
+	require.Len(t, updateAllocs(structs.AllocClientStatusRunning), 1)
+}
+
 func TestClientEndpoint_BatchUpdate(t *testing.T) {
 	ci.Parallel(t)
 
@@ -0,0 +1,217 @@
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodePoolEndpoint_UpsertNodePools(t *testing.T) {
+	ci.Parallel(t)
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	pool1 := mock.NodePool()
+	pool2 := mock.NodePool()
+
+	// Upsert the node pools
+	req := &structs.NodePoolUpsertRequest{
+		NodePools:    []*structs.NodePool{pool1, pool2},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "NodePool.UpsertNodePools", req, &resp))
+	require.NotZero(t, resp.Index)
+
+	// Lookup a node pool
+	get := &structs.NodePoolSpecificRequest{
+		Name:         pool1.Name,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var getResp structs.NodePoolSingleResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "NodePool.GetNodePool", get, &getResp))
+	require.NotNil(t, getResp.NodePool)
+	require.Equal(t, pool1.Description, getResp.NodePool.Description)
+	require.Equal(t, resp.Index, getResp.Index)
+
+	// List the node pools, including the built-in ones
+	list := &structs.NodePoolListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var listResp structs.NodePoolListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "NodePool.List", list, &listResp))
+	require.Len(t, listResp.NodePools, 4)
+
+	// Invalid node pools are rejected
+	req.NodePools = []*structs.NodePool{{Name: "invalid name"}}
+	err := msgpackrpc.CallWithCodec(codec, "NodePool.UpsertNodePools", req, &resp)
+	require.ErrorContains(t, err, "invalid node pool")
+}
+
+func TestNodePoolEndpoint_DeleteNodePools(t *testing.T) {
+	ci.Parallel(t)
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	pool := mock.NodePool()
+	require.NoError(t, s1.fsm.State().UpsertNodePools(
+		structs.MsgTypeTestSetup, 1000, []*structs.NodePool{pool}))
+
+	// Built-in node pools cannot be deleted
+	req := &structs.NodePoolDeleteRequest{
+		Names:        []string{structs.NodePoolDefault},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+	err := msgpackrpc.CallWithCodec(codec, "NodePool.DeleteNodePools", req, &resp)
+	require.ErrorContains(t, err, "cannot be deleted")
+
+	req.Names = []string{pool.Name}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "NodePool.DeleteNodePools", req, &resp))
+	require.NotZero(t, resp.Index)
+
+	out, err := s1.fsm.State().NodePoolByName(nil, pool.Name)
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
+
+func TestNodePoolEndpoint_ListNodes(t *testing.T) {
+	ci.Parallel(t)
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	node1 := mock.Node()
+	node2 := mock.Node()
+	node2.NodePool = "gpu"
+	state := s1.fsm.State()
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1000, node1))
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1001, node2))
+
+	req := &structs.NodePoolNodesRequest{
+		Name:         "gpu",
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp structs.NodeListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "NodePool.ListNodes", req, &resp))
+	require.Len(t, resp.Nodes, 1)
+	require.Equal(t, node2.ID, resp.Nodes[0].ID)
+	require.Equal(t, "gpu", resp.Nodes[0].NodePool)
+
+	req.Name = structs.NodePoolAll
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "NodePool.ListNodes", req, &resp))
+	require.Len(t, resp.Nodes, 2)
+
+	req.Name = "unknown"
+	err := msgpackrpc.CallWithCodec(codec, "NodePool.ListNodes", req, &resp)
+	require.ErrorContains(t, err, "not found")
+}
+
+func TestNodePoolEndpoint_ACL(t *testing.T) {
+	ci.Parallel(t)
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	state := s1.fsm.State()
+	readToken := mock.CreatePolicyAndToken(t, state, 1001, "node-read",
+		mock.NodePolicy(acl.PolicyRead))
+	invalidToken := mock.CreatePolicyAndToken(t, state, 1002, "invalid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+
+	list := &structs.NodePoolListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var listResp structs.NodePoolListResponse
+
+	// Listing requires node read permissions
+	err := msgpackrpc.CallWithCodec(codec, "NodePool.List", list, &listResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+	list.AuthToken = invalidToken.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "NodePool.List", list, &listResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+	list.AuthToken = readToken.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "NodePool.List", list, &listResp))
+	require.Len(t, listResp.NodePools, 2)
+
+	// Writing requires a management token
+	req := &structs.NodePoolUpsertRequest{
+		NodePools: []*structs.NodePool{mock.NodePool()},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: readToken.SecretID,
+		},
+	}
+	var resp structs.GenericResponse
+	err = msgpackrpc.CallWithCodec(codec, "NodePool.UpsertNodePools", req, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+	req.AuthToken = root.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "NodePool.UpsertNodePools", req, &resp))
+}
+
+func TestJobEndpoint_Register_NodePool(t *testing.T) {
+	ci.Parallel(t)
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	state := s1.fsm.State()
+	require.NoError(t, state.UpsertNodePools(structs.MsgTypeTestSetup, 1000,
+		[]*structs.NodePool{{Name: "gpu"}, {Name: "web"}}))
+
+	ns := mock.Namespace()
+	ns.NodePoolConfiguration = &structs.NamespaceNodePoolConfiguration{
+		Default: "web",
+		Denied:  []string{"gpu"},
+	}
+	require.NoError(t, state.UpsertNamespaces(1001, []*structs.Namespace{ns}))
+
+	register := func(job *structs.Job) error {
+		req := &structs.JobRegisterRequest{
+			Job: job,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: job.Namespace,
+			},
+		}
+		var resp structs.JobRegisterResponse
+		return msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	}
+
+	// Jobs without a node pool use the default node pool of their namespace
+	job := mock.Job()
+	require.NoError(t, register(job))
+	out, err := state.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.Equal(t, structs.NodePoolDefault, out.NodePool)
+
+	job = mock.Job()
+	job.Namespace = ns.Name
+	require.NoError(t, register(job))
+	out, err = state.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.Equal(t, "web", out.NodePool)
+
+	// Jobs cannot target denied or nonexistent node pools
+	job = mock.Job()
+	job.Namespace = ns.Name
+	job.NodePool = "gpu"
+	require.ErrorContains(t, register(job), "not allowed")
+
+	job = mock.Job()
+	job.NodePool = "unknown"
+	require.ErrorContains(t, register(job), "nonexistent node pool")
+}
@@ -14,6 +14,7 @@ import (
 	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/helper/snapshot"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/scheduler"
 	"github.com/hashicorp/raft"
 	"github.com/hashicorp/serf/serf"
 )
@@ -368,6 +369,66 @@ func (op *Operator) SchedulerGetConfiguration(args *structs.GenericRequest, repl
 	return nil
 }
 
+// SchedulerRebalance is used to migrate the allocations placed on skewed
+// spreads, overly dense nodes and nodes violating their affinities. Dry runs
+// only report the allocations which would be migrated.
+func (op *Operator) SchedulerRebalance(args *structs.SchedulerRebalanceRequest, reply *structs.SchedulerRebalanceResponse) error {
+	if done, err := op.srv.forward("Operator.SchedulerRebalance", args, args, reply); done {
+		return err
+	}
+
+	// Dry runs require operator read access, migrations operator write.
+	rule, err := op.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if rule != nil {
+		if args.DryRun && !rule.AllowOperatorRead() {
+			return structs.ErrPermissionDenied
+		} else if !args.DryRun && !rule.AllowOperatorWrite() {
+			return structs.ErrPermissionDenied
+		}
+	}
+
+	snap, err := op.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	index, config, err := snap.SchedulerConfig()
+	if err != nil {
+		return err
+	} else if config == nil {
+		return fmt.Errorf("scheduler config not initialized yet")
+	}
+
+	migrations, err := scheduler.FindRebalanceMigrations(op.logger, snap, config)
+	if err != nil {
+		return err
+	}
+	reply.Migrations = migrations
+	reply.Index = index
+	if args.DryRun || len(migrations) == 0 {
+		return nil
+	}
+
+	req, err := rebalanceTransitionRequest(snap, migrations)
+	if err != nil {
+		return err
+	}
+	req.WriteRequest = args.WriteRequest
+
+	_, index, err = op.srv.raftApply(structs.AllocUpdateDesiredTransitionRequestType, req)
+	if err != nil {
+		op.logger.Error("AllocUpdateDesiredTransitionRequest failed", "error", err)
+		return err
+	}
+
+	for _, eval := range req.Evals {
+		reply.EvalIDs = append(reply.EvalIDs, eval.ID)
+	}
+	reply.Index = index
+	return nil
+}
+
 func (op *Operator) forwardStreamingRPC(region string, method string, args interface{}, in io.ReadWriteCloser) error {
 	server, err := op.srv.findRegionServer(region)
 	if err != nil {
@@ -19,6 +19,7 @@ import (
 	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/ci"
 	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/freeport"
 	"github.com/hashicorp/nomad/helper/snapshot"
 	"github.com/hashicorp/nomad/helper/uuid"
@@ -535,6 +536,88 @@ func TestOperator_SchedulerSetConfiguration_ACL(t *testing.T) {
 
 }
 
+func TestOperator_SchedulerRebalance(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	// Create a job whose allocations are all placed on the same rack
+	var nodes []*structs.Node
+	for i, rack := range []string{"r1", "r2"} {
+		node := mock.Node()
+		node.Meta["rack"] = rack
+		nodes = append(nodes, node)
+		require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, uint64(1000+i), node))
+	}
+
+	job := mock.Job()
+	job.TaskGroups[0].Count = 2
+	job.TaskGroups[0].Spreads = []*structs.Spread{{
+		Attribute: "${meta.rack}",
+		Weight:    50,
+	}}
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1002, job))
+
+	var allocs []*structs.Allocation
+	for i := 0; i < 2; i++ {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = nodes[0].ID
+		alloc.ClientStatus = structs.AllocClientStatusRunning
+		alloc.DeploymentStatus = &structs.AllocDeploymentStatus{Healthy: helper.BoolToPtr(true)}
+		allocs = append(allocs, alloc)
+	}
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1003, allocs))
+
+	readToken := mock.CreatePolicyAndToken(t, state, 1004, "operator-read", `operator { policy = "read" }`)
+
+	arg := structs.SchedulerRebalanceRequest{DryRun: true}
+	arg.Region = s1.config.Region
+
+	// Dry runs require operator read
+	var reply structs.SchedulerRebalanceResponse
+	err := msgpackrpc.CallWithCodec(codec, "Operator.SchedulerRebalance", &arg, &reply)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	arg.AuthToken = readToken.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Operator.SchedulerRebalance", &arg, &reply))
+	require.Len(t, reply.Migrations, 1)
+	require.Equal(t, structs.RebalanceReasonSpread, reply.Migrations[0].Reason)
+	require.Empty(t, reply.EvalIDs)
+
+	// Nothing is migrated by dry runs
+	out, err := state.AllocByID(nil, reply.Migrations[0].AllocID)
+	require.NoError(t, err)
+	require.False(t, out.DesiredTransition.ShouldMigrate())
+
+	// Migrations require operator write
+	arg.DryRun = false
+	reply = structs.SchedulerRebalanceResponse{}
+	err = msgpackrpc.CallWithCodec(codec, "Operator.SchedulerRebalance", &arg, &reply)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	arg.AuthToken = root.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Operator.SchedulerRebalance", &arg, &reply))
+	require.Len(t, reply.Migrations, 1)
+	require.Len(t, reply.EvalIDs, 1)
+
+	out, err = state.AllocByID(nil, reply.Migrations[0].AllocID)
+	require.NoError(t, err)
+	require.True(t, out.DesiredTransition.ShouldMigrate())
+
+	eval, err := state.EvalByID(nil, reply.EvalIDs[0])
+	require.NoError(t, err)
+	require.Equal(t, structs.EvalTriggerRebalance, eval.TriggeredBy)
+	require.Equal(t, job.ID, eval.JobID)
+}
+
 func TestOperator_SnapshotSave(t *testing.T) {
 	ci.Parallel(t)
 
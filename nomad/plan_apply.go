@@ -13,6 +13,7 @@ import (
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/scheduler"
 	"github.com/hashicorp/raft"
 )
 
@@ -242,6 +243,11 @@ func (p *planner) applyPlan(plan *structs.Plan, result *structs.PlanResult, snap
 		// to approximate the scheduling time.
 		updateAllocTimestamps(req.AllocsUpdated, now)
 
+		// Sign the workload identity of each task within the allocations.
+		if err := p.signAllocIdentities(plan.Job, req.AllocsUpdated, snap); err != nil {
+			return nil, err
+		}
+
 		for _, preemptions := range result.NodePreemptions {
 			for _, preemptedAlloc := range preemptions {
 				req.AllocsPreempted = append(req.AllocsPreempted, normalizePreemptedAlloc(preemptedAlloc, now))
@@ -367,6 +373,52 @@ func updateAllocTimestamps(allocations []*structs.Allocation, timestamp int64) {
 	}
 }
 
+// signAllocIdentities signs the workload identity of each task within the
+// allocations using the active root key. Allocations are skipped if the
+// keyring has not yet been initialized, as can happen while waiting for all
+// servers to be upgraded.
+func (p *planner) signAllocIdentities(job *structs.Job, allocations []*structs.Allocation, snap *state.StateSnapshot) error {
+	if len(allocations) == 0 {
+		return nil
+	}
+
+	keyMeta, err := snap.GetActiveRootKeyMeta(nil)
+	if err != nil {
+		return fmt.Errorf("failed to get active root key: %v", err)
+	}
+	if keyMeta == nil {
+		p.logger.Debug("keyring is not initialized, skipping workload identity signing")
+		return nil
+	}
+
+	now := time.Now().UTC()
+	for _, alloc := range allocations {
+		allocJob := job
+		if alloc.Job != nil {
+			allocJob = alloc.Job
+		}
+		if allocJob == nil {
+			continue
+		}
+		tg := allocJob.LookupTaskGroup(alloc.TaskGroup)
+		if tg == nil {
+			continue
+		}
+
+		alloc.SignedIdentities = make(map[string]string, len(tg.Tasks))
+		for _, task := range tg.Tasks {
+			claims := structs.NewIdentityClaims(allocJob, alloc, task.Name, now)
+			token, err := p.encrypter.SignClaims(claims, keyMeta.KeyID)
+			if err != nil {
+				return fmt.Errorf("failed to sign workload identity of task %q: %v", task.Name, err)
+			}
+			alloc.SignedIdentities[task.Name] = token
+		}
+		alloc.SigningKeyID = keyMeta.KeyID
+	}
+	return nil
+}
+
 // asyncPlanWait is used to apply and respond to a plan async. On successful
 // commit the plan's index will be sent on the chan. On error the chan will be
 // closed.
@@ -436,6 +488,36 @@ func evaluatePlan(pool *EvaluatePool, snap *state.StateSnapshot, plan *structs.P
 	return evaluatePlanPlacements(pool, snap, plan, logger)
 }
 
+// evaluatePlanQuota returns whether the plan would exceed the quota attached
+// to the namespace of its job.
+func evaluatePlanQuota(snap *state.StateSnapshot, plan *structs.Plan) (bool, error) {
+	quota, dims, err := scheduler.QuotaExhausted(snap, plan)
+	if err != nil {
+		return false, err
+	}
+	if len(dims) == 0 {
+		return false, nil
+	}
+
+	metrics.IncrCounterWithLabels([]string{"nomad", "plan", "quota_exhausted"}, 1,
+		[]metrics.Label{{Name: "quota", Value: quota}})
+	return true, nil
+}
+
+// refreshIndex returns the index the scheduler should refresh to as the
+// maximum of the allocation, node and quota specification tables.
+func refreshIndex(snap *state.StateSnapshot) (uint64, error) {
+	var index uint64
+	for _, table := range []string{"allocs", "nodes", state.TableQuotaSpecs} {
+		tableIndex, err := snap.Index(table)
+		if err != nil {
+			return 0, err
+		}
+		index = maxUint64(index, tableIndex)
+	}
+	return index, nil
+}
+
 // evaluatePlanPlacements is used to determine what portions of a plan can be
 // applied if any, looking for node over commitment. Returns if there should be
 // a plan application which may be partial or if there was an error
@@ -465,10 +547,19 @@ func evaluatePlanPlacements(pool *EvaluatePool, snap *state.StateSnapshot, plan
 		}
 	}
 
+	// Find the nodes whose preemptions would exceed the disruption budget of
+	// the preempted task groups, accounting for the preemptions committed
+	// since the scheduler took its snapshot
+	overBudget, err := scheduler.PlanPreemptionsOverBudget(snap, plan)
+	if err != nil {
+		return nil, err
+	}
+
 	// Setup a multierror to handle potentially getting many
 	// errors since we are processing in parallel.
 	var mErr multierror.Error
 	partialCommit := false
+	var rejectedNodes []string
 
 	// handleResult is used to process the result of evaluateNodePlan
 	handleResult := func(nodeID string, fit bool, reason string, err error) (cancel bool) {
@@ -494,6 +585,7 @@ func evaluatePlanPlacements(pool *EvaluatePool, snap *state.StateSnapshot, plan
 			}
 			// Set that this is a partial commit
 			partialCommit = true
+			rejectedNodes = append(rejectedNodes, nodeID)
 
 			// If we require all-at-once scheduling, there is no point
 			// to continue the evaluation, as we've already failed.
@@ -551,6 +643,17 @@ func evaluatePlanPlacements(pool *EvaluatePool, snap *state.StateSnapshot, plan
 OUTER:
 	for len(nodeIDList) > 0 {
 		nodeID := nodeIDList[0]
+
+		// Reject nodes exceeding the disruption budgets without evaluating them
+		if reason, ok := overBudget[nodeID]; ok {
+			nodeIDList = nodeIDList[1:]
+			if cancel := handleResult(nodeID, false, reason, nil); cancel {
+				didCancel = true
+				break OUTER
+			}
+			continue
+		}
+
 		select {
 		case req <- evaluateRequest{snap, plan, nodeID}:
 			outstanding++
@@ -593,52 +696,18 @@ OUTER:
 			mErr.Errors = append(mErr.Errors, err)
 		}
 
+		// Task groups which must be placed all at once cannot be partially
+		// committed, so remove the rest of their placements
+		scheduler.CorrectAllOrNothingPlacements(plan, result, rejectedNodes)
+
 		// If there was a partial commit and we are operating within a
 		// deployment correct for any canary that may have been desired to be
 		// placed but wasn't actually placed
-		correctDeploymentCanaries(result)
+		scheduler.CorrectDeploymentCanaries(result)
 	}
 	return result, mErr.ErrorOrNil()
 }
 
-// correctDeploymentCanaries ensures that the deployment object doesn't list any
-// canaries as placed if they didn't actually get placed. This could happen if
-// the plan had a partial commit.
-func correctDeploymentCanaries(result *structs.PlanResult) {
-	// Hot path
-	if result.Deployment == nil || !result.Deployment.HasPlacedCanaries() {
-		return
-	}
-
-	// Build a set of all the allocations IDs that were placed
-	placedAllocs := make(map[string]struct{}, len(result.NodeAllocation))
-	for _, placed := range result.NodeAllocation {
-		for _, alloc := range placed {
-			placedAllocs[alloc.ID] = struct{}{}
-		}
-	}
-
-	// Go through all the canaries and ensure that the result list only contains
-	// those that have been placed
-	for _, group := range result.Deployment.TaskGroups {
-		canaries := group.PlacedCanaries
-		if len(canaries) == 0 {
-			continue
-		}
-
-		// Prune the canaries in place to avoid allocating an extra slice
-		i := 0
-		for _, canaryID := range canaries {
-			if _, ok := placedAllocs[canaryID]; ok {
-				canaries[i] = canaryID
-				i++
-			}
-		}
-
-		group.PlacedCanaries = canaries[:i]
-	}
-}
-
 // evaluateNodePlan is used to evaluate the plan for a single node,
 // returning if the plan is valid or if an error is encountered
 func evaluateNodePlan(snap *state.StateSnapshot, plan *structs.Plan, nodeID string) (bool, string, error) {
@@ -65,6 +65,43 @@ func testRegisterJob(t *testing.T, s *Server, j *structs.Job) {
 }
 
 // COMPAT 0.11: Tests the older unoptimized code path for applyPlan
+func TestPlanApply_signAllocIdentities(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Wait for the leader to initialize the keyring.
+	var keyMeta *structs.RootKeyMeta
+	testutil.WaitForResult(func() (bool, error) {
+		var err error
+		keyMeta, err = s1.State().GetActiveRootKeyMeta(nil)
+		return keyMeta != nil, err
+	}, func(err error) {
+		require.NoError(t, err)
+	})
+
+	alloc := mock.Alloc()
+	job := alloc.Job
+	alloc.Job = nil
+
+	snap, err := s1.State().Snapshot()
+	require.NoError(t, err)
+	require.NoError(t, s1.signAllocIdentities(job, []*structs.Allocation{alloc}, snap))
+
+	require.Equal(t, keyMeta.KeyID, alloc.SigningKeyID)
+	require.Len(t, alloc.SignedIdentities, len(job.TaskGroups[0].Tasks))
+
+	for _, task := range job.TaskGroups[0].Tasks {
+		claims, err := s1.encrypter.VerifyClaim(alloc.SignedIdentities[task.Name])
+		require.NoError(t, err)
+		require.Equal(t, alloc.ID, claims.AllocationID)
+		require.Equal(t, job.ID, claims.JobID)
+		require.Equal(t, task.Name, claims.TaskName)
+	}
+}
+
 func TestPlanApply_applyPlan(t *testing.T) {
 	ci.Parallel(t)
 
@@ -434,6 +471,42 @@ func TestPlanApply_EvalPlan_Simple(t *testing.T) {
 	}
 }
 
+func TestPlanApply_EvalPlan_QuotaExhausted(t *testing.T) {
+	ci.Parallel(t)
+	state := testStateStore(t)
+	node := mock.Node()
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+
+	spec := mock.QuotaSpec()
+	spec.Limits[0].RegionLimit = &structs.Resources{CPU: 400}
+	spec.SetHash()
+	require.NoError(t, state.UpsertQuotaSpecs(structs.MsgTypeTestSetup, 1001, []*structs.QuotaSpec{spec}))
+	ns := mock.Namespace()
+	ns.Quota = spec.Name
+	require.NoError(t, state.UpsertNamespaces(1002, []*structs.Namespace{ns}))
+	snap, _ := state.Snapshot()
+
+	alloc := mock.Alloc()
+	alloc.Namespace = ns.Name
+	alloc.Job.Namespace = ns.Name
+	plan := &structs.Plan{
+		Job: alloc.Job,
+		NodeAllocation: map[string][]*structs.Allocation{
+			node.ID: {alloc},
+		},
+	}
+
+	pool := NewEvaluatePool(workerPoolSize, workerPoolBufferSize)
+	defer pool.Shutdown()
+
+	// The plan is rejected and the scheduler forced to refresh
+	result, err := evaluatePlan(pool, snap, plan, testlog.HCLogger(t))
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	require.Empty(t, result.NodeAllocation)
+	require.EqualValues(t, 1001, result.RefreshIndex)
+}
+
 func TestPlanApply_EvalPlan_Preemption(t *testing.T) {
 	ci.Parallel(t)
 	state := testStateStore(t)
@@ -548,6 +621,71 @@ func TestPlanApply_EvalPlan_Preemption(t *testing.T) {
 
 }
 
+func TestPlanApply_EvalPlan_Preemption_DisruptionBudget(t *testing.T) {
+	ci.Parallel(t)
+	state := testStateStore(t)
+	node := mock.Node()
+	state.UpsertNode(structs.MsgTypeTestSetup, 1000, node)
+	node2 := mock.Node()
+	state.UpsertNode(structs.MsgTypeTestSetup, 1001, node2)
+
+	// Only one allocation of the low priority job may be preempted at once
+	lowPrioJob := mock.Job()
+	lowPrioJob.TaskGroups[0].Count = 2
+	lowPrioJob.TaskGroups[0].Preemption = &structs.PreemptionPolicy{
+		Preemptible:   true,
+		MaxDisruption: 1,
+	}
+
+	var preempted []*structs.Allocation
+	for _, n := range []*structs.Node{node, node2} {
+		alloc := mock.Alloc()
+		alloc.Job = lowPrioJob
+		alloc.JobID = lowPrioJob.ID
+		alloc.NodeID = n.ID
+		preempted = append(preempted, alloc)
+	}
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1002, preempted))
+	snap, _ := state.Snapshot()
+
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	alloc2 := mock.Alloc()
+	alloc2.Job = alloc.Job
+	alloc2.JobID = alloc.JobID
+	alloc2.NodeID = node2.ID
+
+	plan := &structs.Plan{
+		Job: alloc.Job,
+		NodeAllocation: map[string][]*structs.Allocation{
+			node.ID:  {alloc},
+			node2.ID: {alloc2},
+		},
+		NodePreemptions: map[string][]*structs.Allocation{
+			node.ID:  {preempted[0]},
+			node2.ID: {preempted[1]},
+		},
+	}
+
+	pool := NewEvaluatePool(workerPoolSize, workerPoolBufferSize)
+	defer pool.Shutdown()
+
+	result, err := evaluatePlan(pool, snap, plan, testlog.HCLogger(t))
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	// Only the preemption of the first node in order fits in the budget
+	first, second := node.ID, node2.ID
+	if second < first {
+		first, second = second, first
+	}
+	require.Contains(t, result.NodeAllocation, first)
+	require.Len(t, result.NodePreemptions[first], 1)
+	require.NotContains(t, result.NodeAllocation, second)
+	require.NotContains(t, result.NodePreemptions, second)
+	require.EqualValues(t, 1002, result.RefreshIndex)
+}
+
 func TestPlanApply_EvalPlan_Partial(t *testing.T) {
 	ci.Parallel(t)
 	state := testStateStore(t)
@@ -657,6 +795,62 @@ func TestPlanApply_EvalPlan_Partial_AllAtOnce(t *testing.T) {
 	}
 }
 
+func TestPlanApply_EvalPlan_Partial_AllOrNothing(t *testing.T) {
+	ci.Parallel(t)
+	state := testStateStore(t)
+	node := mock.Node()
+	state.UpsertNode(structs.MsgTypeTestSetup, 1000, node)
+	node2 := mock.Node()
+	state.UpsertNode(structs.MsgTypeTestSetup, 1001, node2)
+	snap, _ := state.Snapshot()
+
+	job := mock.BatchJob()
+	job.TaskGroups[0].Placement = structs.TaskGroupPlacementAllOrNothing
+
+	// The previous allocations are stopped by the placements
+	prev := mock.Alloc()
+	prev.NodeID = node.ID
+	other := mock.Alloc()
+	other.NodeID = node.ID
+
+	alloc := mock.Alloc()
+	alloc.Job = job
+	alloc.TaskGroup = job.TaskGroups[0].Name
+	alloc.PreviousAllocation = prev.ID
+	alloc2 := mock.Alloc() // Ensure alloc2 does not fit
+	alloc2.Job = job
+	alloc2.TaskGroup = job.TaskGroups[0].Name
+	alloc2.AllocatedResources = structs.NodeResourcesToAllocatedResources(node2.NodeResources)
+
+	plan := &structs.Plan{
+		Job: job,
+		NodeUpdate: map[string][]*structs.Allocation{
+			node.ID: {prev, other},
+		},
+		NodeAllocation: map[string][]*structs.Allocation{
+			node.ID:  {alloc},
+			node2.ID: {alloc2},
+		},
+	}
+
+	pool := NewEvaluatePool(workerPoolSize, workerPoolBufferSize)
+	defer pool.Shutdown()
+
+	result, err := evaluatePlan(pool, snap, plan, testlog.HCLogger(t))
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	// None of the placements of the task group are committed
+	require.Empty(t, result.NodeAllocation)
+
+	// Only the stop of the replaced allocation is removed
+	require.Len(t, result.NodeUpdate[node.ID], 1)
+	require.Equal(t, other.ID, result.NodeUpdate[node.ID][0].ID)
+	require.Len(t, plan.NodeUpdate[node.ID], 2)
+
+	require.EqualValues(t, 1001, result.RefreshIndex)
+}
+
 func TestPlanApply_EvalNodePlan_Simple(t *testing.T) {
 	ci.Parallel(t)
 	state := testStateStore(t)
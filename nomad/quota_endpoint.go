@@ -0,0 +1,361 @@
+package nomad
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Quota endpoint is used for manipulating quota specifications
+type Quota struct {
+	srv *Server
+}
+
+// UpsertQuotaSpecs is used to create or update a set of quota
+// specifications. Quota specifications are written to the authoritative
+// region and replicated to the other regions.
+func (q *Quota) UpsertQuotaSpecs(args *structs.QuotaSpecUpsertRequest, reply *structs.GenericResponse) error {
+	args.Region = q.srv.config.AuthoritativeRegion
+	if done, err := q.srv.forward("Quota.UpsertQuotaSpecs", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "quota", "upsert_quota_specs"}, time.Now())
+
+	// Check quota write permissions
+	if aclObj, err := q.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowQuotaWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate there is at least one quota specification
+	if len(args.Quotas) == 0 {
+		return fmt.Errorf("must specify at least one quota specification")
+	}
+
+	// Validate the quota specifications and set the hash
+	for _, spec := range args.Quotas {
+		if err := spec.Validate(); err != nil {
+			return structs.NewErrRPCCodedf(http.StatusBadRequest, "invalid quota specification %q: %v", spec.Name, err)
+		}
+
+		spec.SetHash()
+	}
+
+	// Update via Raft
+	out, index, err := q.srv.raftApply(structs.QuotaSpecUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Check if there was an error when applying.
+	if err, ok := out.(error); ok && err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// DeleteQuotaSpecs is used to delete a set of quota specifications. Quota
+// specifications can only be deleted once no namespace is attached to them.
+func (q *Quota) DeleteQuotaSpecs(args *structs.QuotaSpecDeleteRequest, reply *structs.GenericResponse) error {
+	args.Region = q.srv.config.AuthoritativeRegion
+	if done, err := q.srv.forward("Quota.DeleteQuotaSpecs", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "quota", "delete_quota_specs"}, time.Now())
+
+	// Check quota write permissions
+	if aclObj, err := q.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowQuotaWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate at least one quota specification
+	if len(args.Names) == 0 {
+		return fmt.Errorf("must specify at least one quota specification to delete")
+	}
+
+	// Update via Raft
+	out, index, err := q.srv.raftApply(structs.QuotaSpecDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Check if there was an error when applying.
+	if err, ok := out.(error); ok && err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// ListQuotaSpecs is used to list the quota specifications.
+func (q *Quota) ListQuotaSpecs(args *structs.QuotaSpecListRequest, reply *structs.QuotaSpecListResponse) error {
+	if done, err := q.srv.forward("Quota.ListQuotaSpecs", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "quota", "list_quota_specs"}, time.Now())
+
+	// Check quota read permissions
+	if aclObj, err := q.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowQuotaRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, s *state.StateStore) error {
+			var err error
+			var iter memdb.ResultIterator
+			if prefix := args.QueryOptions.Prefix; prefix != "" {
+				iter, err = s.QuotaSpecsByNamePrefix(ws, prefix)
+			} else {
+				iter, err = s.QuotaSpecs(ws)
+			}
+			if err != nil {
+				return err
+			}
+
+			reply.Quotas = nil
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				reply.Quotas = append(reply.Quotas, raw.(*structs.QuotaSpec))
+			}
+
+			// Use the last index that affected the quota specifications table
+			index, err := s.Index(state.TableQuotaSpecs)
+			if err != nil {
+				return err
+			}
+
+			// Ensure we never set the index to zero, otherwise a blocking
+			// query cannot be used.
+			if index == 0 {
+				index = 1
+			}
+			reply.Index = index
+
+			// Set the query response
+			q.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return q.srv.blockingRPC(&opts)
+}
+
+// GetQuotaSpec is used to get a specific quota specification.
+func (q *Quota) GetQuotaSpec(args *structs.QuotaSpecSpecificRequest, reply *structs.SingleQuotaSpecResponse) error {
+	if done, err := q.srv.forward("Quota.GetQuotaSpec", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "quota", "get_quota_spec"}, time.Now())
+
+	// Check quota read permissions
+	if aclObj, err := q.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowQuotaRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, s *state.StateStore) error {
+			out, err := s.QuotaSpecByName(ws, args.Name)
+			if err != nil {
+				return err
+			}
+
+			// Setup the output
+			reply.Quota = out
+			if out != nil {
+				reply.Index = out.ModifyIndex
+			} else {
+				// Use the last index that affected the quota specifications
+				// table
+				index, err := s.Index(state.TableQuotaSpecs)
+				if err != nil {
+					return err
+				}
+				reply.Index = index
+			}
+
+			// Set the query response
+			q.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return q.srv.blockingRPC(&opts)
+}
+
+// GetQuotaSpecs is used to get a set of quota specifications. It is used to
+// replicate quota specifications from the authoritative region.
+func (q *Quota) GetQuotaSpecs(args *structs.QuotaSpecSetRequest, reply *structs.QuotaSpecSetResponse) error {
+	if done, err := q.srv.forward("Quota.GetQuotaSpecs", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "quota", "get_quota_specs"}, time.Now())
+
+	// Check management permissions
+	if aclObj, err := q.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, s *state.StateStore) error {
+			reply.Quotas = make(map[string]*structs.QuotaSpec, len(args.Names))
+			for _, name := range args.Names {
+				out, err := s.QuotaSpecByName(ws, name)
+				if err != nil {
+					return err
+				}
+				if out != nil {
+					reply.Quotas[name] = out
+				}
+			}
+
+			// Use the last index that affected the quota specifications table
+			index, err := s.Index(state.TableQuotaSpecs)
+			if err != nil {
+				return err
+			}
+
+			// Ensure we never set the index to zero, otherwise a blocking
+			// query cannot be used.
+			if index == 0 {
+				index = 1
+			}
+			reply.Index = index
+			return nil
+		}}
+	return q.srv.blockingRPC(&opts)
+}
+
+// ListQuotaUsages is used to list the usages of the quota specifications in
+// the region.
+func (q *Quota) ListQuotaUsages(args *structs.QuotaSpecListRequest, reply *structs.QuotaUsageListResponse) error {
+	if done, err := q.srv.forward("Quota.ListQuotaUsages", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "quota", "list_quota_usages"}, time.Now())
+
+	// Check quota read permissions
+	if aclObj, err := q.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowQuotaRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, s *state.StateStore) error {
+			var err error
+			var iter memdb.ResultIterator
+			if prefix := args.QueryOptions.Prefix; prefix != "" {
+				iter, err = s.QuotaSpecsByNamePrefix(ws, prefix)
+			} else {
+				iter, err = s.QuotaSpecs(ws)
+			}
+			if err != nil {
+				return err
+			}
+
+			reply.Usages = nil
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				usage, err := s.QuotaUsageByName(ws, raw.(*structs.QuotaSpec).Name)
+				if err != nil {
+					return err
+				}
+				reply.Usages = append(reply.Usages, usage)
+			}
+
+			index, err := quotaUsageIndex(s)
+			if err != nil {
+				return err
+			}
+			reply.Index = index
+
+			// Set the query response
+			q.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return q.srv.blockingRPC(&opts)
+}
+
+// GetQuotaUsage is used to get the usage of a specific quota specification
+// in the region.
+func (q *Quota) GetQuotaUsage(args *structs.QuotaSpecSpecificRequest, reply *structs.SingleQuotaUsageResponse) error {
+	if done, err := q.srv.forward("Quota.GetQuotaUsage", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "quota", "get_quota_usage"}, time.Now())
+
+	// Check quota read permissions
+	if aclObj, err := q.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowQuotaRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, s *state.StateStore) error {
+			out, err := s.QuotaUsageByName(ws, args.Name)
+			if err != nil {
+				return err
+			}
+			reply.Usage = out
+
+			index, err := quotaUsageIndex(s)
+			if err != nil {
+				return err
+			}
+			reply.Index = index
+
+			// Set the query response
+			q.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return q.srv.blockingRPC(&opts)
+}
+
+// quotaUsageIndex returns the last index that affected quota usages, which
+// are computed from the quota specifications, namespaces and allocations.
+func quotaUsageIndex(s *state.StateStore) (uint64, error) {
+	var index uint64
+	for _, table := range []string{state.TableQuotaSpecs, state.TableNamespaces, "allocs"} {
+		tableIndex, err := s.Index(table)
+		if err != nil {
+			return 0, err
+		}
+		index = maxUint64(index, tableIndex)
+	}
+
+	// Ensure we never set the index to zero, otherwise a blocking query
+	// cannot be used.
+	if index == 0 {
+		index = 1
+	}
+	return index, nil
+}
@@ -0,0 +1,178 @@
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuotaEndpoint_UpsertQuotaSpecs(t *testing.T) {
+	ci.Parallel(t)
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	spec1 := mock.QuotaSpec()
+	spec2 := mock.QuotaSpec()
+
+	// Upsert the quota specifications
+	req := &structs.QuotaSpecUpsertRequest{
+		Quotas:       []*structs.QuotaSpec{spec1, spec2},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Quota.UpsertQuotaSpecs", req, &resp))
+	require.NotZero(t, resp.Index)
+
+	// Lookup a quota specification
+	get := &structs.QuotaSpecSpecificRequest{
+		Name:         spec1.Name,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var getResp structs.SingleQuotaSpecResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Quota.GetQuotaSpec", get, &getResp))
+	require.NotNil(t, getResp.Quota)
+	require.Equal(t, spec1.Description, getResp.Quota.Description)
+	require.Equal(t, resp.Index, getResp.Index)
+
+	// List the quota specifications
+	list := &structs.QuotaSpecListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var listResp structs.QuotaSpecListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Quota.ListQuotaSpecs", list, &listResp))
+	require.Len(t, listResp.Quotas, 2)
+
+	// Invalid quota specifications are rejected
+	req.Quotas = []*structs.QuotaSpec{{Name: "invalid name"}}
+	err := msgpackrpc.CallWithCodec(codec, "Quota.UpsertQuotaSpecs", req, &resp)
+	require.ErrorContains(t, err, "invalid quota specification")
+}
+
+func TestQuotaEndpoint_DeleteQuotaSpecs(t *testing.T) {
+	ci.Parallel(t)
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	spec := mock.QuotaSpec()
+	state := s1.fsm.State()
+	require.NoError(t, state.UpsertQuotaSpecs(
+		structs.MsgTypeTestSetup, 1000, []*structs.QuotaSpec{spec}))
+	ns := mock.Namespace()
+	ns.Quota = spec.Name
+	require.NoError(t, state.UpsertNamespaces(1001, []*structs.Namespace{ns}))
+
+	// Quotas attached to a namespace cannot be deleted
+	req := &structs.QuotaSpecDeleteRequest{
+		Names:        []string{spec.Name},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+	err := msgpackrpc.CallWithCodec(codec, "Quota.DeleteQuotaSpecs", req, &resp)
+	require.ErrorContains(t, err, "is used by at least one namespace")
+
+	ns = ns.Copy()
+	ns.Quota = ""
+	require.NoError(t, state.UpsertNamespaces(1002, []*structs.Namespace{ns}))
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Quota.DeleteQuotaSpecs", req, &resp))
+	require.NotZero(t, resp.Index)
+
+	out, err := state.QuotaSpecByName(nil, spec.Name)
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
+
+func TestQuotaEndpoint_GetQuotaUsage(t *testing.T) {
+	ci.Parallel(t)
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	spec := mock.QuotaSpec()
+	state := s1.fsm.State()
+	require.NoError(t, state.UpsertQuotaSpecs(
+		structs.MsgTypeTestSetup, 1000, []*structs.QuotaSpec{spec}))
+	ns := mock.Namespace()
+	ns.Quota = spec.Name
+	require.NoError(t, state.UpsertNamespaces(1001, []*structs.Namespace{ns}))
+
+	alloc := mock.Alloc()
+	alloc.Namespace = ns.Name
+	alloc.Job.Namespace = ns.Name
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1002, alloc.Job))
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1003, []*structs.Allocation{alloc}))
+
+	get := &structs.QuotaSpecSpecificRequest{
+		Name:         spec.Name,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var getResp structs.SingleQuotaUsageResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Quota.GetQuotaUsage", get, &getResp))
+	require.NotNil(t, getResp.Usage)
+	require.EqualValues(t, 1003, getResp.Index)
+
+	used := getResp.Usage.Used[spec.Limits[0].HashKey()]
+	require.NotNil(t, used)
+	require.Equal(t, 500, used.RegionLimit.CPU)
+	require.Equal(t, 256, used.RegionLimit.MemoryMB)
+
+	list := &structs.QuotaSpecListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var listResp structs.QuotaUsageListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Quota.ListQuotaUsages", list, &listResp))
+	require.Len(t, listResp.Usages, 1)
+	require.Equal(t, spec.Name, listResp.Usages[0].Name)
+}
+
+func TestQuotaEndpoint_ACL(t *testing.T) {
+	ci.Parallel(t)
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	state := s1.fsm.State()
+	readToken := mock.CreatePolicyAndToken(t, state, 1001, "quota-read",
+		mock.QuotaPolicy(acl.PolicyRead))
+	invalidToken := mock.CreatePolicyAndToken(t, state, 1002, "invalid",
+		mock.NodePolicy(acl.PolicyRead))
+
+	list := &structs.QuotaSpecListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var listResp structs.QuotaSpecListResponse
+
+	// Listing requires quota read permissions
+	err := msgpackrpc.CallWithCodec(codec, "Quota.ListQuotaSpecs", list, &listResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+	list.AuthToken = invalidToken.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Quota.ListQuotaSpecs", list, &listResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+	list.AuthToken = readToken.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Quota.ListQuotaSpecs", list, &listResp))
+
+	// Writing requires quota write permissions
+	req := &structs.QuotaSpecUpsertRequest{
+		Quotas: []*structs.QuotaSpec{mock.QuotaSpec()},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: readToken.SecretID,
+		},
+	}
+	var resp structs.GenericResponse
+	err = msgpackrpc.CallWithCodec(codec, "Quota.UpsertQuotaSpecs", req, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+	req.AuthToken = root.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Quota.UpsertQuotaSpecs", req, &resp))
+}
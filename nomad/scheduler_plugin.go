@@ -0,0 +1,42 @@
+package nomad
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/nomad/plugins/base"
+	schedplugin "github.com/hashicorp/nomad/plugins/scheduler"
+)
+
+// schedulerPluginExists returns whether a scheduler plugin with the given name
+// is registered with the server.
+func (s *Server) schedulerPluginExists(name string) bool {
+	if s.config.PluginSingletonLoader == nil {
+		return false
+	}
+
+	for _, info := range s.config.PluginSingletonLoader.Catalog()[base.PluginTypeScheduler] {
+		if info.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// schedulerPlugin returns the scheduler plugin with the given name. External
+// plugins are launched on first use and relaunched if they exit.
+func (s *Server) schedulerPlugin(name string) (schedplugin.SchedulerPlugin, error) {
+	if !s.schedulerPluginExists(name) {
+		return nil, fmt.Errorf("unknown scheduler plugin %q", name)
+	}
+
+	instance, err := s.config.PluginSingletonLoader.Dispense(name, base.PluginTypeScheduler, nil, s.logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dispense scheduler plugin %q: %v", name, err)
+	}
+
+	plugin, ok := instance.Plugin().(schedplugin.SchedulerPlugin)
+	if !ok {
+		return nil, fmt.Errorf("plugin %q is not a scheduler plugin", name)
+	}
+	return plugin, nil
+}
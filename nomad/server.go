@@ -31,6 +31,7 @@ import (
 	"github.com/hashicorp/nomad/helper/pool"
 	"github.com/hashicorp/nomad/helper/stats"
 	"github.com/hashicorp/nomad/helper/tlsutil"
+	"github.com/hashicorp/nomad/lib/auth/oidc"
 	"github.com/hashicorp/nomad/nomad/deploymentwatcher"
 	"github.com/hashicorp/nomad/nomad/drainer"
 	"github.com/hashicorp/nomad/nomad/state"
@@ -124,6 +125,13 @@ type Server struct {
 	// fsm is the state machine used with Raft
 	fsm *nomadFSM
 
+	// encrypter is the keyring used to encrypt and decrypt variables
+	encrypter *Encrypter
+
+	// oidcProviderCache caches the OIDC providers of the ACL auth methods,
+	// so discovery is not performed on each login request.
+	oidcProviderCache *oidc.ProviderCache
+
 	// rpcListener is used to listen for incoming connections
 	rpcListener net.Listener
 	listenerCh  chan struct{}
@@ -279,6 +287,8 @@ type endpoints struct {
 	Enterprise          *EnterpriseEndpoints
 	Event               *Event
 	Namespace           *Namespace
+	NodePool            *NodePool
+	Quota               *Quota
 	ServiceRegistration *ServiceRegistration
 
 	// Client endpoints
@@ -350,6 +360,21 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI, consulConfigEntr
 	// Create the RPC handler
 	s.rpcHandler = newRpcHandler(s)
 
+	// Create the keyring used to encrypt variables. Keys are only held in
+	// memory when running in dev mode.
+	var keystorePath string
+	if !s.config.DevMode {
+		keystorePath = filepath.Join(s.config.DataDir, keystoreDir)
+	}
+	encrypter, err := NewEncrypter(keystorePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create keyring: %v", err)
+	}
+	s.encrypter = encrypter
+
+	// Create the OIDC provider cache used by ACL auth method logins.
+	s.oidcProviderCache = oidc.NewProviderCache()
+
 	// Create the planner
 	planner, err := newPlanner(s)
 	if err != nil {
@@ -426,6 +451,9 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI, consulConfigEntr
 	// Setup the node drainer.
 	s.setupNodeDrainer()
 
+	// Start replicating the key material of root keys from our peers
+	go NewKeyringReplicator(s, s.encrypter).run(s.shutdownCtx)
+
 	// Setup the enterprise state
 	if err := s.setupEnterprise(config); err != nil {
 		return nil, err
@@ -1159,6 +1187,8 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 		s.staticEndpoints.System = &System{srv: s, logger: s.logger.Named("system")}
 		s.staticEndpoints.Search = &Search{srv: s, logger: s.logger.Named("search")}
 		s.staticEndpoints.Namespace = &Namespace{srv: s}
+		s.staticEndpoints.NodePool = &NodePool{srv: s}
+		s.staticEndpoints.Quota = &Quota{srv: s}
 		s.staticEndpoints.Enterprise = NewEnterpriseEndpoints(s)
 
 		// These endpoints are dynamic because they need access to the
@@ -1206,6 +1236,8 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 	server.Register(s.staticEndpoints.FileSystem)
 	server.Register(s.staticEndpoints.Agent)
 	server.Register(s.staticEndpoints.Namespace)
+	server.Register(s.staticEndpoints.NodePool)
+	server.Register(s.staticEndpoints.Quota)
 
 	// Create new dynamic endpoints and add them to the RPC server.
 	alloc := &Alloc{srv: s, ctx: ctx, logger: s.logger.Named("alloc")}
@@ -1214,6 +1246,8 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 	node := &Node{srv: s, ctx: ctx, logger: s.logger.Named("client")}
 	plan := &Plan{srv: s, ctx: ctx, logger: s.logger.Named("plan")}
 	serviceReg := &ServiceRegistration{srv: s, ctx: ctx}
+	keyring := &Keyring{srv: s, ctx: ctx, logger: s.logger.Named("keyring")}
+	variables := &Variables{srv: s, ctx: ctx, logger: s.logger.Named("variables")}
 
 	// Register the dynamic endpoints
 	server.Register(alloc)
@@ -1222,6 +1256,8 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 	server.Register(node)
 	server.Register(plan)
 	_ = server.Register(serviceReg)
+	_ = server.Register(keyring)
+	_ = server.Register(variables)
 }
 
 // setupRaft is used to setup and initialize Raft
@@ -406,9 +406,21 @@ func (s *ServiceRegistration) GetService(
 			// Set up our output after we have checked the error.
 			var services []*structs.ServiceRegistration
 
+			// Registrations with critical checks are only returned if the
+			// caller did not ask for healthy instances, such as templates
+			// which only want routable instances.
+			var filters []paginator.Filter
+			if args.Healthy {
+				filters = append(filters, paginator.GenericFilter{
+					Allow: func(raw interface{}) (bool, error) {
+						return raw.(*structs.ServiceRegistration).Healthy(), nil
+					},
+				})
+			}
+
 			// Build the paginator. This includes the function that is
 			// responsible for appending a registration to the services array.
-			paginatorImpl, err := paginator.NewPaginator(iter, tokenizer, nil, args.QueryOptions,
+			paginatorImpl, err := paginator.NewPaginator(iter, tokenizer, filters, args.QueryOptions,
 				func(raw interface{}) error {
 					services = append(services, raw.(*structs.ServiceRegistration))
 					return nil
@@ -1317,6 +1317,89 @@ func TestServiceRegistration_GetService(t *testing.T) {
 				must.Eq(t, "10.0.0.1", result[1].Address)
 			},
 		},
+		{
+			name: "unhealthy registrations filtered when healthy requested",
+			serverFn: func(t *testing.T) (*Server, *structs.ACLToken, func()) {
+				server, cleanup := TestServer(t, nil)
+				return server, nil, cleanup
+			},
+			testFn: func(t *testing.T, s *Server, _ *structs.ACLToken) {
+				codec := rpcClient(t, s)
+				testutil.WaitForLeader(t, s.RPC)
+
+				// insert 3 instances of service s1, one without checks, one
+				// passing, and one critical
+				nodeID, jobID, allocID := "node_id", "job_id", "alloc_id"
+				services := []*structs.ServiceRegistration{
+					{
+						ID:          "id_1",
+						Namespace:   "default",
+						ServiceName: "s1",
+						NodeID:      nodeID,
+						Datacenter:  "dc1",
+						JobID:       jobID,
+						AllocID:     allocID,
+						Address:     "10.0.0.1",
+						Port:        9001,
+					},
+					{
+						ID:          "id_2",
+						Namespace:   "default",
+						ServiceName: "s1",
+						NodeID:      nodeID,
+						Datacenter:  "dc1",
+						JobID:       jobID,
+						AllocID:     allocID,
+						Address:     "10.0.0.2",
+						Port:        9002,
+						Checks: []*structs.ServiceRegistrationCheck{
+							{ID: "check_2", Name: "c", Type: "tcp", Status: structs.ServiceCheckStatusPassing},
+						},
+					},
+					{
+						ID:          "id_3",
+						Namespace:   "default",
+						ServiceName: "s1",
+						NodeID:      nodeID,
+						Datacenter:  "dc1",
+						JobID:       jobID,
+						AllocID:     allocID,
+						Address:     "10.0.0.3",
+						Port:        9003,
+						Checks: []*structs.ServiceRegistrationCheck{
+							{ID: "check_3", Name: "c", Type: "tcp", Status: structs.ServiceCheckStatusCritical},
+						},
+					},
+				}
+				must.NoError(t, s.fsm.State().UpsertServiceRegistrations(structs.MsgTypeTestSetup, 10, services))
+
+				serviceRegReq := &structs.ServiceRegistrationByNameRequest{
+					ServiceName: "s1",
+					QueryOptions: structs.QueryOptions{
+						Namespace: structs.DefaultNamespace,
+						Region:    DefaultRegion,
+					},
+				}
+				var serviceRegResp structs.ServiceRegistrationByNameResponse
+				err := msgpackrpc.CallWithCodec(
+					codec, structs.ServiceRegistrationGetServiceRPCMethod, serviceRegReq, &serviceRegResp)
+				must.NoError(t, err)
+				must.Len(t, 3, serviceRegResp.Services)
+
+				// only healthy registrations are returned when requested
+				serviceRegReq.Healthy = true
+				var healthyResp structs.ServiceRegistrationByNameResponse
+				err = msgpackrpc.CallWithCodec(
+					codec, structs.ServiceRegistrationGetServiceRPCMethod, serviceRegReq, &healthyResp)
+				must.NoError(t, err)
+
+				result := healthyResp.Services
+
+				must.Len(t, 2, result)
+				must.Eq(t, "id_1", result[0].ID)
+				must.Eq(t, "id_2", result[1].ID)
+			},
+		},
 	}
 
 	for _, tc := range testCases {
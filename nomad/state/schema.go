@@ -1,8 +1,10 @@
 package state
 
 import (
+	"encoding/binary"
 	"fmt"
 	"sync"
+	"time"
 
 	memdb "github.com/hashicorp/go-memdb"
 
@@ -14,6 +16,14 @@ const (
 
 	TableNamespaces           = "namespaces"
 	TableServiceRegistrations = "service_registrations"
+	TableVariables            = "variables"
+	TableRootKeyMeta          = "root_key_meta"
+	TableACLRoles             = "acl_roles"
+	TableACLAuthMethods       = "acl_auth_methods"
+	TableACLBindingRules      = "acl_binding_rules"
+	TableNodePools            = "node_pools"
+	TableJobSubmission        = "job_submission"
+	TableQuotaSpecs           = "quota_specs"
 )
 
 const (
@@ -22,6 +32,10 @@ const (
 	indexNodeID      = "node_id"
 	indexAllocID     = "alloc_id"
 	indexServiceName = "service_name"
+	indexKeyID       = "key_id"
+	indexName        = "name"
+	indexAuthMethod  = "auth_method"
+	indexNodePool    = "node_pool"
 )
 
 var (
@@ -70,6 +84,14 @@ func init() {
 		scalingEventTableSchema,
 		namespaceTableSchema,
 		serviceRegistrationsTableSchema,
+		variablesTableSchema,
+		rootKeyMetaTableSchema,
+		aclRolesTableSchema,
+		aclAuthMethodsTableSchema,
+		aclBindingRulesTableSchema,
+		nodePoolsTableSchema,
+		jobSubmissionTableSchema,
+		quotaSpecsTableSchema,
 	}...)
 }
 
@@ -134,6 +156,14 @@ func nodeTableSchema() *memdb.TableSchema {
 					Field: "SecretID",
 				},
 			},
+			indexNodePool: {
+				Name:         indexNodePool,
+				AllowMissing: true,
+				Unique:       false,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "NodePool",
+				},
+			},
 		},
 	}
 }
@@ -257,6 +287,37 @@ func jobVersionSchema() *memdb.TableSchema {
 	}
 }
 
+// jobSubmissionTableSchema returns the MemDB schema for the job submission
+// table. This table is used to store the source of job versions.
+func jobSubmissionTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableJobSubmission,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+
+				// Use a compound index so the tuple of (Namespace, JobID,
+				// Version) is uniquely identifying
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Namespace",
+						},
+						&memdb.StringFieldIndex{
+							Field: "JobID",
+						},
+						&memdb.UintFieldIndex{
+							Field: "Version",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 // jobIsGCable satisfies the ConditionalIndexFunc interface and creates an index
 // on whether a job is eligible for garbage collection.
 func jobIsGCable(obj interface{}) (bool, error) {
@@ -816,10 +877,68 @@ func aclTokenTableSchema() *memdb.TableSchema {
 					Field: "Global",
 				},
 			},
+			"expires-global": {
+				Name:         "expires-global",
+				AllowMissing: true,
+				Unique:       false,
+				Indexer: &ACLTokenExpirationIndex{
+					Global: true,
+				},
+			},
+			"expires-local": {
+				Name:         "expires-local",
+				AllowMissing: true,
+				Unique:       false,
+				Indexer: &ACLTokenExpirationIndex{
+					Global: false,
+				},
+			},
 		},
 	}
 }
 
+// ACLTokenExpirationIndex is a custom index on the ExpirationTime of ACL
+// tokens. Only tokens which have an expiration time and whose Global field
+// matches are indexed, allowing the table to be iterated in expiration order.
+type ACLTokenExpirationIndex struct {
+	Global bool
+}
+
+// FromObject is used to extract an index value from an
+// object or to indicate that the index value is missing.
+func (a *ACLTokenExpirationIndex) FromObject(obj interface{}) (bool, []byte, error) {
+	token, ok := obj.(*structs.ACLToken)
+	if !ok {
+		return false, nil, fmt.Errorf("object %#v is not an ACLToken", obj)
+	}
+
+	if token.Global != a.Global || !token.HasExpirationTime() {
+		return false, nil, nil
+	}
+
+	return true, encodeExpirationTime(*token.ExpirationTime), nil
+}
+
+// FromArgs is used to build an exact index lookup based on arguments
+func (a *ACLTokenExpirationIndex) FromArgs(args ...interface{}) ([]byte, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("must provide only a single argument")
+	}
+	arg, ok := args[0].(time.Time)
+	if !ok {
+		return nil, fmt.Errorf("argument must be a time.Time: %#v", args[0])
+	}
+	return encodeExpirationTime(arg), nil
+}
+
+// encodeExpirationTime encodes the time as big-endian nanoseconds so that
+// the index sorts in chronological order.
+func encodeExpirationTime(t time.Time) []byte {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, uint64(t.UnixNano()))
+	return buf
+}
+
 // oneTimeTokenTableSchema returns the MemDB schema for the tokens table.
 // This table is used to store one-time tokens for ACL tokens
 func oneTimeTokenTableSchema() *memdb.TableSchema {
@@ -1202,3 +1321,171 @@ func serviceRegistrationsTableSchema() *memdb.TableSchema {
 		},
 	}
 }
+
+// variablesTableSchema returns the MemDB schema for Nomad variables.
+func variablesTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableVariables,
+		Indexes: map[string]*memdb.IndexSchema{
+			// The path in combination with namespace forms a unique identifier
+			// for a variable. The prefix form of this index is used to perform
+			// listings of variables by path prefix.
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Namespace",
+						},
+						&memdb.StringFieldIndex{
+							Field: "Path",
+						},
+					},
+				},
+			},
+			// The keyID index allows finding all the variables encrypted with
+			// a given root key, which must be known before a key can be
+			// removed from the keyring.
+			indexKeyID: {
+				Name:         indexKeyID,
+				AllowMissing: false,
+				Unique:       false,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "KeyID",
+				},
+			},
+		},
+	}
+}
+
+// rootKeyMetaTableSchema returns the MemDB schema for the metadata of the
+// root keys used to encrypt variables.
+func rootKeyMetaTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableRootKeyMeta,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field:     "KeyID",
+					Lowercase: true,
+				},
+			},
+		},
+	}
+}
+
+// aclRolesTableSchema returns the MemDB schema for the ACL roles table. This
+// table is used to store ACL roles which group ACL policies, so they can be
+// linked to ACL tokens.
+func aclRolesTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableACLRoles,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "ID",
+				},
+			},
+			indexName: {
+				Name:         indexName,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "Name",
+				},
+			},
+		},
+	}
+}
+
+// aclAuthMethodsTableSchema returns the MemDB schema for the ACL auth methods
+// table. This table is used to store the configuration of the single sign-on
+// methods operators use to log in to Nomad.
+func aclAuthMethodsTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableACLAuthMethods,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "Name",
+				},
+			},
+		},
+	}
+}
+
+// aclBindingRulesTableSchema returns the MemDB schema for the ACL binding
+// rules table. This table is used to store the rules which map the claims of
+// an identity logging in via an auth method to ACL roles and policies.
+func aclBindingRulesTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableACLBindingRules,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "ID",
+				},
+			},
+			indexAuthMethod: {
+				Name:         indexAuthMethod,
+				AllowMissing: false,
+				Unique:       false,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "AuthMethod",
+				},
+			},
+		},
+	}
+}
+
+// nodePoolsTableSchema returns the MemDB schema for the node pools table.
+// This table is used to store the node pools which partition the nodes of
+// the cluster.
+func nodePoolsTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableNodePools,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "Name",
+				},
+			},
+		},
+	}
+}
+
+// quotaSpecsTableSchema returns the MemDB schema for the quota
+// specifications table. This table is used to store the quota specifications
+// which are attached to namespaces.
+func quotaSpecsTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableQuotaSpecs,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "Name",
+				},
+			},
+		},
+	}
+}
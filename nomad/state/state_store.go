@@ -141,6 +141,11 @@ func NewStateStore(config *StateStoreConfig) (*StateStore, error) {
 		return nil, fmt.Errorf("enterprise state store initialization failed: %v", err)
 	}
 
+	// Initialize the state store with the built-in node pools.
+	if err := s.nodePoolInit(); err != nil {
+		return nil, fmt.Errorf("node pool state store initialization failed: %v", err)
+	}
+
 	return s, nil
 }
 
@@ -917,6 +922,9 @@ func upsertNodeTxn(txn *txn, index uint64, node *structs.Node) error {
 	if err := upsertCSIPluginsForNode(txn, node, index); err != nil {
 		return fmt.Errorf("csi plugin update failed: %v", err)
 	}
+	if err := upsertNodePoolForNodeTxn(txn, index, node.NodePool); err != nil {
+		return fmt.Errorf("node pool update failed: %v", err)
+	}
 
 	return nil
 }
@@ -1012,6 +1020,35 @@ func (s *StateStore) updateNodeStatusTxn(txn *txn, nodeID, status string, update
 	return nil
 }
 
+// UpdateNodeUtilization is used to update the resource utilization of a node.
+// Utilization is refreshed periodically and only consumed by the scheduler,
+// so neither the node's ModifyIndex nor the nodes table index are bumped to
+// avoid waking blocking queries and watchers on every report.
+func (s *StateStore) UpdateNodeUtilization(msgType structs.MessageType, index uint64, nodeID string, utilization *structs.NodeUtilization) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	// Lookup the node
+	existing, err := txn.First("nodes", "id", nodeID)
+	if err != nil {
+		return fmt.Errorf("node lookup failed: %v", err)
+	}
+	if existing == nil {
+		return fmt.Errorf("node not found")
+	}
+
+	// Update the utilization in a copy of the node
+	copyNode := existing.(*structs.Node).Copy()
+	copyNode.Utilization = utilization.Copy()
+
+	// Insert the node
+	if err := txn.Insert("nodes", copyNode); err != nil {
+		return fmt.Errorf("node update failed: %v", err)
+	}
+
+	return txn.Commit()
+}
+
 // BatchUpdateNodeDrain is used to update the drain of a node set of nodes.
 // This is currently only called when node drain is completed by the drainer.
 func (s *StateStore) BatchUpdateNodeDrain(msgType structs.MessageType, index uint64, updatedAt int64,
@@ -1762,6 +1799,11 @@ func (s *StateStore) DeleteJobTxn(index uint64, namespace, jobID string, txn Txn
 		return err
 	}
 
+	// Delete the source of the job versions
+	if err := s.deleteJobSubmissions(index, job, txn); err != nil {
+		return err
+	}
+
 	// Cleanup plugins registered by this job, before we delete the summary
 	err = s.deleteJobFromPlugins(index, txn, job)
 	if err != nil {
@@ -1915,6 +1957,11 @@ func (s *StateStore) upsertJobVersion(index uint64, job *structs.Job, txn *txn)
 		return fmt.Errorf("failed to delete job %v (%d) from job_version", d.ID, d.Version)
 	}
 
+	// The source of the deleted version is no longer needed.
+	if err := s.deleteJobSubmissionTxn(index, d.Namespace, d.ID, d.Version, txn); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -5557,9 +5604,11 @@ func (s *StateStore) UpsertACLTokens(msgType structs.MessageType, index uint64,
 			token.CreateIndex = existTK.CreateIndex
 			token.ModifyIndex = index
 
-			// Do not allow SecretID or create time to change
+			// Do not allow SecretID, create time or expiration to change
 			token.SecretID = existTK.SecretID
 			token.CreateTime = existTK.CreateTime
+			token.ExpirationTime = existTK.ExpirationTime
+			token.ExpirationTTL = existTK.ExpirationTTL
 
 		} else {
 			token.CreateIndex = index
@@ -5700,6 +5749,24 @@ func (s *StateStore) ACLTokensByGlobal(ws memdb.WatchSet, globalVal bool, sort S
 	return iter, nil
 }
 
+// ACLTokensByExpired returns an iterator over all local or global ACL tokens
+// which have an expiration time, ordered by that expiration time with the
+// earliest first.
+func (s *StateStore) ACLTokensByExpired(global bool) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	index := "expires-local"
+	if global {
+		index = "expires-global"
+	}
+
+	iter, err := txn.Get("acl_token", index)
+	if err != nil {
+		return nil, fmt.Errorf("acl token lookup failed: %v", err)
+	}
+	return iter, nil
+}
+
 // CanBootstrapACLToken checks if bootstrapping is possible and returns the reset index
 func (s *StateStore) CanBootstrapACLToken() (bool, uint64, error) {
 	txn := s.db.ReadTxn()
@@ -6214,16 +6281,11 @@ func (s *StateStore) upsertNamespaceImpl(index uint64, txn *txn, namespace *stru
 		return fmt.Errorf("namespace lookup failed: %v", err)
 	}
 
-	// Setup the indexes correctly and determine which quotas need to be
-	// reconciled
-	var oldQuota string
+	// Setup the indexes correctly
 	if existing != nil {
 		exist := existing.(*structs.Namespace)
 		ns.CreateIndex = exist.CreateIndex
 		ns.ModifyIndex = index
-
-		// Grab the old quota on the namespace
-		oldQuota = exist.Quota
 	} else {
 		ns.CreateIndex = index
 		ns.ModifyIndex = index
@@ -6244,8 +6306,7 @@ func (s *StateStore) upsertNamespaceImpl(index uint64, txn *txn, namespace *stru
 		return fmt.Errorf("namespace insert failed: %v", err)
 	}
 
-	// Reconcile changed quotas
-	return s.quotaReconcile(index, txn, ns.Quota, oldQuota)
+	return nil
 }
 
 // DeleteNamespaces is used to remove a set of namespaces
@@ -6287,6 +6348,18 @@ func (s *StateStore) DeleteNamespaces(index uint64, names []string) error {
 			}
 		}
 
+		// Ensure that the namespace doesn't have any variables, as these
+		// would otherwise be orphaned and inaccessible.
+		varIter, err := txn.Get(TableVariables, indexID+"_prefix", name, "")
+		if err != nil {
+			return fmt.Errorf("variables lookup failed: %v", err)
+		}
+		if raw := varIter.Next(); raw != nil {
+			return fmt.Errorf("namespace %q contains at least one variable %q. "+
+				"All variables must be deleted in namespace before it can be deleted",
+				name, raw.(*structs.VariableEncrypted).Path)
+		}
+
 		// Delete the namespace
 		if err := txn.Delete(TableNamespaces, existing); err != nil {
 			return fmt.Errorf("namespace deletion failed: %v", err)
@@ -0,0 +1,220 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// UpsertACLAuthMethods is used to insert a number of ACL auth methods into the
+// state store. It uses a single write transaction for efficiency, however,
+// any error means no entries will be committed. An error is returned if the
+// request attempts to mark more than one auth method as the default.
+func (s *StateStore) UpsertACLAuthMethods(
+	msgType structs.MessageType, index uint64, methods []*structs.ACLAuthMethod) error {
+
+	// Grab a write transaction.
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	// updated tracks whether any inserts have been made. This allows us to
+	// skip updating the index table if we do not need to.
+	var updated bool
+
+	// Iterate the array of methods. In the event of a single error, all
+	// inserts fail via the txn.Abort() defer.
+	for _, method := range methods {
+
+		methodUpdated, err := s.upsertACLAuthMethodTxn(index, txn, method)
+		if err != nil {
+			return err
+		}
+
+		// Ensure we track whether any inserts have been made.
+		updated = updated || methodUpdated
+	}
+
+	// If we did not perform any inserts, exit early.
+	if !updated {
+		return nil
+	}
+
+	// Perform the index table update to mark the new insert.
+	if err := txn.Insert(tableIndex, &IndexEntry{TableACLAuthMethods, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	return txn.Commit()
+}
+
+// upsertACLAuthMethodTxn inserts a single ACL auth method into the state store
+// using the provided write transaction. It is the responsibility of the caller
+// to update the index table.
+func (s *StateStore) upsertACLAuthMethodTxn(
+	index uint64, txn *txn, method *structs.ACLAuthMethod) (bool, error) {
+
+	// Ensure the method hash is not zero to provide defense in depth. This
+	// should be done outside the state store, so we do not spend time here
+	// and thus Raft, when it, can be avoided.
+	if len(method.Hash) == 0 {
+		method.SetHash()
+	}
+
+	// Only a single auth method can be marked as the default. This is also
+	// checked within the RPC handler, but we check again while in our write
+	// txn to avoid races between requests.
+	if method.Default {
+		existingDefault, err := s.getDefaultACLAuthMethodTxn(txn, nil)
+		if err != nil {
+			return false, err
+		}
+		if existingDefault != nil && existingDefault.Name != method.Name {
+			return false, fmt.Errorf("default ACL auth method already exists: %s", existingDefault.Name)
+		}
+	}
+
+	existing, err := txn.First(TableACLAuthMethods, indexID, method.Name)
+	if err != nil {
+		return false, fmt.Errorf("ACL auth method lookup failed: %v", err)
+	}
+
+	// Set up the indexes correctly to ensure existing indexes are maintained.
+	if existing != nil {
+		exist := existing.(*structs.ACLAuthMethod)
+		if exist.Equals(method) {
+			return false, nil
+		}
+		method.CreateTime = exist.CreateTime
+		method.CreateIndex = exist.CreateIndex
+		method.ModifyIndex = index
+	} else {
+		method.CreateIndex = index
+		method.ModifyIndex = index
+	}
+
+	// Insert the auth method into the table.
+	if err := txn.Insert(TableACLAuthMethods, method); err != nil {
+		return false, fmt.Errorf("ACL auth method insert failed: %v", err)
+	}
+	return true, nil
+}
+
+// DeleteACLAuthMethods is responsible for batch deleting ACL auth methods. It
+// uses a single write transaction for efficiency, however, any error means no
+// entries will be committed. An error is produced if an auth method is not
+// found within state which has been passed within the array. Any ACL binding
+// rules which reference a deleted auth method are also deleted.
+func (s *StateStore) DeleteACLAuthMethods(
+	msgType structs.MessageType, index uint64, names []string) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	var deletedRules bool
+
+	for _, name := range names {
+		if err := s.deleteACLAuthMethodTxn(txn, name); err != nil {
+			return err
+		}
+
+		rulesDeleted, err := txn.DeleteAll(TableACLBindingRules, indexAuthMethod, name)
+		if err != nil {
+			return fmt.Errorf("ACL binding rule deletion failed: %v", err)
+		}
+		deletedRules = deletedRules || rulesDeleted > 0
+	}
+
+	// Update the index table to indicate an update has occurred.
+	if err := txn.Insert(tableIndex, &IndexEntry{TableACLAuthMethods, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	if deletedRules {
+		if err := txn.Insert(tableIndex, &IndexEntry{TableACLBindingRules, index}); err != nil {
+			return fmt.Errorf("index update failed: %v", err)
+		}
+	}
+
+	return txn.Commit()
+}
+
+// deleteACLAuthMethodTxn deletes a single ACL auth method from the state store
+// using the provided write transaction. It is the responsibility of the
+// caller to update the index table.
+func (s *StateStore) deleteACLAuthMethodTxn(txn *txn, name string) error {
+
+	existing, err := txn.First(TableACLAuthMethods, indexID, name)
+	if err != nil {
+		return fmt.Errorf("ACL auth method lookup failed: %v", err)
+	}
+	if existing == nil {
+		return errors.New("ACL auth method not found")
+	}
+
+	// Delete the existing entry from the table.
+	if err := txn.Delete(TableACLAuthMethods, existing); err != nil {
+		return fmt.Errorf("ACL auth method deletion failed: %v", err)
+	}
+	return nil
+}
+
+// GetACLAuthMethods returns an iterator that contains all ACL auth methods
+// stored within state.
+func (s *StateStore) GetACLAuthMethods(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	// Walk the entire table to get all ACL auth methods.
+	iter, err := txn.Get(TableACLAuthMethods, indexID)
+	if err != nil {
+		return nil, fmt.Errorf("ACL auth method lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// GetACLAuthMethodByName returns a single ACL auth method specified by the
+// input name. The auth method object will be nil, if no matching entry was
+// found; it is the responsibility of the caller to check for this.
+func (s *StateStore) GetACLAuthMethodByName(ws memdb.WatchSet, name string) (*structs.ACLAuthMethod, error) {
+	txn := s.db.ReadTxn()
+
+	// Perform the ACL auth method lookup using the "id" index.
+	watchCh, existing, err := txn.FirstWatch(TableACLAuthMethods, indexID, name)
+	if err != nil {
+		return nil, fmt.Errorf("ACL auth method lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.ACLAuthMethod), nil
+	}
+	return nil, nil
+}
+
+// GetDefaultACLAuthMethod returns the ACL auth method marked as the default.
+// The auth method object will be nil, if no default has been configured; it
+// is the responsibility of the caller to check for this.
+func (s *StateStore) GetDefaultACLAuthMethod(ws memdb.WatchSet) (*structs.ACLAuthMethod, error) {
+	txn := s.db.ReadTxn()
+	return s.getDefaultACLAuthMethodTxn(txn, ws)
+}
+
+// getDefaultACLAuthMethodTxn is the same as GetDefaultACLAuthMethod but allows
+// callers to pass their own transaction.
+func (s *StateStore) getDefaultACLAuthMethodTxn(txn ReadTxn, ws memdb.WatchSet) (*structs.ACLAuthMethod, error) {
+
+	iter, err := txn.Get(TableACLAuthMethods, indexID)
+	if err != nil {
+		return nil, fmt.Errorf("ACL auth method lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		method := raw.(*structs.ACLAuthMethod)
+		if method.Default {
+			return method, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,138 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateStore_UpsertACLAuthMethods(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	// Create mock auth methods ready for upserting.
+	mockedACLAuthMethods := []*structs.ACLAuthMethod{mock.ACLAuthMethod(), mock.ACLAuthMethod()}
+	require.NoError(t, testState.UpsertACLAuthMethods(structs.MsgTypeTestSetup, 10, mockedACLAuthMethods))
+
+	// Check that the index for the table was modified as expected.
+	tableIndex, err := testState.Index(TableACLAuthMethods)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), tableIndex)
+
+	// Upserting the same auth methods again should be a no-op.
+	require.NoError(t, testState.UpsertACLAuthMethods(structs.MsgTypeTestSetup, 20, mockedACLAuthMethods))
+
+	tableIndex, err = testState.Index(TableACLAuthMethods)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), tableIndex)
+
+	// Update an auth method and ensure the create index is kept while the
+	// modify index is updated.
+	updatedMethod := mockedACLAuthMethods[0].Copy()
+	updatedMethod.Default = true
+	updatedMethod.SetHash()
+	require.NoError(t, testState.UpsertACLAuthMethods(
+		structs.MsgTypeTestSetup, 30, []*structs.ACLAuthMethod{updatedMethod}))
+
+	ws := memdb.NewWatchSet()
+	out, err := testState.GetACLAuthMethodByName(ws, updatedMethod.Name)
+	require.NoError(t, err)
+	require.True(t, out.Default)
+	require.Equal(t, uint64(10), out.CreateIndex)
+	require.Equal(t, uint64(30), out.ModifyIndex)
+
+	// Marking a second auth method as the default should fail.
+	secondDefault := mockedACLAuthMethods[1].Copy()
+	secondDefault.Default = true
+	secondDefault.SetHash()
+	err = testState.UpsertACLAuthMethods(
+		structs.MsgTypeTestSetup, 40, []*structs.ACLAuthMethod{secondDefault})
+	require.ErrorContains(t, err, "default ACL auth method already exists")
+
+	// Switching the default within a single request should succeed when the
+	// previous default is unset first.
+	previousDefault := updatedMethod.Copy()
+	previousDefault.Default = false
+	previousDefault.SetHash()
+	require.NoError(t, testState.UpsertACLAuthMethods(
+		structs.MsgTypeTestSetup, 50, []*structs.ACLAuthMethod{previousDefault, secondDefault}))
+
+	defaultMethod, err := testState.GetDefaultACLAuthMethod(ws)
+	require.NoError(t, err)
+	require.Equal(t, secondDefault.Name, defaultMethod.Name)
+}
+
+func TestStateStore_DeleteACLAuthMethods(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	// Create mock auth methods and a binding rule linked to one of them.
+	mockedACLAuthMethods := []*structs.ACLAuthMethod{mock.ACLAuthMethod(), mock.ACLAuthMethod()}
+	require.NoError(t, testState.UpsertACLAuthMethods(structs.MsgTypeTestSetup, 10, mockedACLAuthMethods))
+
+	mockedBindingRule := mock.ACLBindingRule()
+	mockedBindingRule.AuthMethod = mockedACLAuthMethods[0].Name
+	require.NoError(t, testState.UpsertACLBindingRules(
+		structs.MsgTypeTestSetup, 20, []*structs.ACLBindingRule{mockedBindingRule}, false))
+
+	// Deleting an auth method which does not exist should fail.
+	err := testState.DeleteACLAuthMethods(structs.MsgTypeTestSetup, 30, []string{"not-a-method"})
+	require.EqualError(t, err, "ACL auth method not found")
+
+	// Delete the auth method which has a binding rule linked.
+	require.NoError(t, testState.DeleteACLAuthMethods(
+		structs.MsgTypeTestSetup, 40, []string{mockedACLAuthMethods[0].Name}))
+
+	ws := memdb.NewWatchSet()
+	out, err := testState.GetACLAuthMethodByName(ws, mockedACLAuthMethods[0].Name)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	// The linked binding rule should have been deleted.
+	rule, err := testState.GetACLBindingRule(ws, mockedBindingRule.ID)
+	require.NoError(t, err)
+	require.Nil(t, rule)
+
+	// Both table indexes should have been updated.
+	tableIndex, err := testState.Index(TableACLAuthMethods)
+	require.NoError(t, err)
+	require.Equal(t, uint64(40), tableIndex)
+	tableIndex, err = testState.Index(TableACLBindingRules)
+	require.NoError(t, err)
+	require.Equal(t, uint64(40), tableIndex)
+
+	// The remaining auth method should still exist.
+	iter, err := testState.GetACLAuthMethods(ws)
+	require.NoError(t, err)
+
+	var methods []*structs.ACLAuthMethod
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		methods = append(methods, raw.(*structs.ACLAuthMethod))
+	}
+	require.Len(t, methods, 1)
+	require.Equal(t, mockedACLAuthMethods[1].Name, methods[0].Name)
+}
+
+func TestStateStore_GetDefaultACLAuthMethod(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	// No default auth method should be found when none exist.
+	ws := memdb.NewWatchSet()
+	out, err := testState.GetDefaultACLAuthMethod(ws)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	mockedACLAuthMethods := []*structs.ACLAuthMethod{mock.ACLAuthMethod(), mock.ACLAuthMethod()}
+	mockedACLAuthMethods[1].Default = true
+	mockedACLAuthMethods[1].SetHash()
+	require.NoError(t, testState.UpsertACLAuthMethods(structs.MsgTypeTestSetup, 10, mockedACLAuthMethods))
+
+	out, err = testState.GetDefaultACLAuthMethod(ws)
+	require.NoError(t, err)
+	require.Equal(t, mockedACLAuthMethods[1].Name, out.Name)
+}
@@ -0,0 +1,109 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateStore_UpsertACLBindingRules(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	// Try and upsert binding rules without the auth method existing within
+	// state, which should fail.
+	mockedACLBindingRules := []*structs.ACLBindingRule{mock.ACLBindingRule(), mock.ACLBindingRule()}
+	err := testState.UpsertACLBindingRules(structs.MsgTypeTestSetup, 10, mockedACLBindingRules, false)
+	require.ErrorContains(t, err, "ACL auth method not found")
+
+	// The upsert should succeed when allowing missing auth methods, as used
+	// by the replication process.
+	require.NoError(t, testState.UpsertACLBindingRules(structs.MsgTypeTestSetup, 10, mockedACLBindingRules, true))
+
+	// Create the auth method the rules link to and upsert the rules again,
+	// which should be a no-op as nothing has changed.
+	authMethod := mock.ACLAuthMethod()
+	authMethod.Name = mockedACLBindingRules[0].AuthMethod
+	require.NoError(t, testState.UpsertACLAuthMethods(
+		structs.MsgTypeTestSetup, 20, []*structs.ACLAuthMethod{authMethod}))
+	require.NoError(t, testState.UpsertACLBindingRules(structs.MsgTypeTestSetup, 30, mockedACLBindingRules, false))
+
+	tableIndex, err := testState.Index(TableACLBindingRules)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), tableIndex)
+
+	// Update a binding rule and ensure the create index is kept while the
+	// modify index is updated.
+	updatedRule := mockedACLBindingRules[0].Copy()
+	updatedRule.Description = "updated description"
+	updatedRule.SetHash()
+	require.NoError(t, testState.UpsertACLBindingRules(
+		structs.MsgTypeTestSetup, 40, []*structs.ACLBindingRule{updatedRule}, false))
+
+	ws := memdb.NewWatchSet()
+	out, err := testState.GetACLBindingRule(ws, updatedRule.ID)
+	require.NoError(t, err)
+	require.Equal(t, "updated description", out.Description)
+	require.Equal(t, uint64(10), out.CreateIndex)
+	require.Equal(t, uint64(40), out.ModifyIndex)
+
+	tableIndex, err = testState.Index(TableACLBindingRules)
+	require.NoError(t, err)
+	require.Equal(t, uint64(40), tableIndex)
+}
+
+func TestStateStore_DeleteACLBindingRules(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	mockedACLBindingRules := []*structs.ACLBindingRule{mock.ACLBindingRule(), mock.ACLBindingRule()}
+	require.NoError(t, testState.UpsertACLBindingRules(structs.MsgTypeTestSetup, 10, mockedACLBindingRules, true))
+
+	// Deleting a binding rule which does not exist should fail and not
+	// delete any of the others.
+	err := testState.DeleteACLBindingRules(structs.MsgTypeTestSetup, 20,
+		[]string{mockedACLBindingRules[0].ID, "not-a-rule"})
+	require.EqualError(t, err, "ACL binding rule not found")
+
+	ws := memdb.NewWatchSet()
+	out, err := testState.GetACLBindingRule(ws, mockedACLBindingRules[0].ID)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+
+	// Delete both binding rules.
+	require.NoError(t, testState.DeleteACLBindingRules(structs.MsgTypeTestSetup, 30,
+		[]string{mockedACLBindingRules[0].ID, mockedACLBindingRules[1].ID}))
+
+	iter, err := testState.GetACLBindingRules(ws)
+	require.NoError(t, err)
+	require.Nil(t, iter.Next())
+
+	tableIndex, err := testState.Index(TableACLBindingRules)
+	require.NoError(t, err)
+	require.Equal(t, uint64(30), tableIndex)
+}
+
+func TestStateStore_GetACLBindingRulesByAuthMethod(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	mockedACLBindingRules := []*structs.ACLBindingRule{mock.ACLBindingRule(), mock.ACLBindingRule()}
+	mockedACLBindingRules[1].AuthMethod = "okta"
+	mockedACLBindingRules[1].SetHash()
+	require.NoError(t, testState.UpsertACLBindingRules(structs.MsgTypeTestSetup, 10, mockedACLBindingRules, true))
+
+	ws := memdb.NewWatchSet()
+	iter, err := testState.GetACLBindingRulesByAuthMethod(ws, "okta")
+	require.NoError(t, err)
+
+	var rules []*structs.ACLBindingRule
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		rules = append(rules, raw.(*structs.ACLBindingRule))
+	}
+	require.Len(t, rules, 1)
+	require.Equal(t, mockedACLBindingRules[1].ID, rules[0].ID)
+}
@@ -0,0 +1,155 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// upsertMockACLRolePolicies creates the policies linked to by mock.ACLRole, so
+// that roles can be inserted without skipping the policy link validation.
+func upsertMockACLRolePolicies(t *testing.T, testState *StateStore, index uint64) {
+	policy1 := mock.ACLPolicy()
+	policy1.Name = "mocked-test-policy-1"
+	policy2 := mock.ACLPolicy()
+	policy2.Name = "mocked-test-policy-2"
+	require.NoError(t, testState.UpsertACLPolicies(
+		structs.MsgTypeTestSetup, index, []*structs.ACLPolicy{policy1, policy2}))
+}
+
+func TestStateStore_UpsertACLRoles(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	// Try and upsert a role without the policies existing within state, which
+	// should fail.
+	mockedACLRoles := []*structs.ACLRole{mock.ACLRole(), mock.ACLRole()}
+	err := testState.UpsertACLRoles(structs.MsgTypeTestSetup, 10, mockedACLRoles, false)
+	require.ErrorContains(t, err, "policy not found")
+
+	// The upsert should succeed when allowing missing policies, as used by
+	// the replication process.
+	require.NoError(t, testState.UpsertACLRoles(structs.MsgTypeTestSetup, 10, mockedACLRoles, true))
+
+	// Create the policies our ACL roles wants to link to and upsert the roles
+	// again, which should be a no-op as nothing has changed.
+	upsertMockACLRolePolicies(t, testState, 20)
+	require.NoError(t, testState.UpsertACLRoles(structs.MsgTypeTestSetup, 30, mockedACLRoles, false))
+
+	// Check that the index for the table was not modified by the no-op
+	// upsert.
+	tableIndex, err := testState.Index(TableACLRoles)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), tableIndex)
+
+	// Update a role and ensure the create index is kept while the modify
+	// index is updated.
+	updatedRole := mockedACLRoles[0].Copy()
+	updatedRole.Description = "updated description"
+	updatedRole.SetHash()
+	require.NoError(t, testState.UpsertACLRoles(
+		structs.MsgTypeTestSetup, 40, []*structs.ACLRole{updatedRole}, false))
+
+	ws := memdb.NewWatchSet()
+	out, err := testState.GetACLRoleByID(ws, updatedRole.ID)
+	require.NoError(t, err)
+	require.Equal(t, "updated description", out.Description)
+	require.Equal(t, uint64(10), out.CreateIndex)
+	require.Equal(t, uint64(40), out.ModifyIndex)
+
+	tableIndex, err = testState.Index(TableACLRoles)
+	require.NoError(t, err)
+	require.Equal(t, uint64(40), tableIndex)
+
+	// Try to create a new role which uses the name of an existing role,
+	// which should fail.
+	duplicateRole := mock.ACLRole()
+	duplicateRole.Name = mockedACLRoles[1].Name
+	err = testState.UpsertACLRoles(
+		structs.MsgTypeTestSetup, 50, []*structs.ACLRole{duplicateRole}, false)
+	require.ErrorContains(t, err, "already exists")
+}
+
+func TestStateStore_DeleteACLRolesByID(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	upsertMockACLRolePolicies(t, testState, 10)
+
+	mockedACLRoles := []*structs.ACLRole{mock.ACLRole(), mock.ACLRole()}
+	require.NoError(t, testState.UpsertACLRoles(structs.MsgTypeTestSetup, 20, mockedACLRoles, false))
+
+	// Try and delete a role using an ID that doesn't exist. This should
+	// return an error and not perform any deletions.
+	err := testState.DeleteACLRolesByID(
+		structs.MsgTypeTestSetup, 30, []string{mockedACLRoles[0].ID, "not-a-role"})
+	require.EqualError(t, err, "ACL role not found")
+
+	iter, err := testState.GetACLRoles(memdb.NewWatchSet())
+	require.NoError(t, err)
+
+	var count int
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		count++
+	}
+	require.Equal(t, 2, count)
+
+	// Delete one of the roles and ensure the other remains.
+	require.NoError(t, testState.DeleteACLRolesByID(
+		structs.MsgTypeTestSetup, 40, []string{mockedACLRoles[0].ID}))
+
+	out, err := testState.GetACLRoleByID(nil, mockedACLRoles[0].ID)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	out, err = testState.GetACLRoleByID(nil, mockedACLRoles[1].ID)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+
+	tableIndex, err := testState.Index(TableACLRoles)
+	require.NoError(t, err)
+	require.Equal(t, uint64(40), tableIndex)
+}
+
+func TestStateStore_GetACLRoleByName(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	mockedACLRoles := []*structs.ACLRole{mock.ACLRole(), mock.ACLRole()}
+	require.NoError(t, testState.UpsertACLRoles(structs.MsgTypeTestSetup, 10, mockedACLRoles, true))
+
+	ws := memdb.NewWatchSet()
+
+	for _, role := range mockedACLRoles {
+		out, err := testState.GetACLRoleByName(ws, role.Name)
+		require.NoError(t, err)
+		require.Equal(t, role, out)
+	}
+
+	out, err := testState.GetACLRoleByName(ws, "not-a-role")
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
+
+func TestStateStore_GetACLRoleByIDPrefix(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	mockedACLRoles := []*structs.ACLRole{mock.ACLRole(), mock.ACLRole()}
+	mockedACLRoles[0].ID = "aaaaaaaa-7bfb-395d-eb95-0685af2176b2"
+	mockedACLRoles[1].ID = "bbbbbbbb-7bfb-395d-eb95-0685af2176b2"
+	require.NoError(t, testState.UpsertACLRoles(structs.MsgTypeTestSetup, 10, mockedACLRoles, true))
+
+	iter, err := testState.GetACLRoleByIDPrefix(memdb.NewWatchSet(), "aaaa")
+	require.NoError(t, err)
+
+	var ids []string
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		ids = append(ids, raw.(*structs.ACLRole).ID)
+	}
+	require.Equal(t, []string{mockedACLRoles[0].ID}, ids)
+}
@@ -0,0 +1,133 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateStore_UpsertJobWithSubmission(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	job := mock.Job()
+	sub := &structs.JobSubmission{
+		Source:        `job "example" {}`,
+		Format:        structs.JobSubmissionFormatHCL2,
+		VariableFlags: map[string]string{"count": "3"},
+	}
+
+	// Create a watchset so we can test that upsert fires the watch
+	ws := memdb.NewWatchSet()
+	_, err := testState.JobSubmission(ws, job.Namespace, job.ID, 0)
+	require.NoError(t, err)
+
+	require.NoError(t, testState.UpsertJobWithSubmission(structs.MsgTypeTestSetup, 1000, sub, job))
+	require.True(t, watchFired(ws))
+
+	out, err := testState.JobSubmission(nil, job.Namespace, job.ID, 0)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, sub.Source, out.Source)
+	require.Equal(t, sub.VariableFlags, out.VariableFlags)
+	require.Equal(t, job.Namespace, out.Namespace)
+	require.Equal(t, job.ID, out.JobID)
+	require.EqualValues(t, 0, out.Version)
+	require.EqualValues(t, 1000, out.JobModifyIndex)
+
+	// The submission passed in must not be modified
+	require.Empty(t, sub.JobID)
+
+	index, err := testState.Index(TableJobSubmission)
+	require.NoError(t, err)
+	require.EqualValues(t, 1000, index)
+
+	// Registering a new version without a submission must not store one
+	job2 := job.Copy()
+	job2.Meta["updated"] = "true"
+	require.NoError(t, testState.UpsertJobWithSubmission(structs.MsgTypeTestSetup, 1001, nil, job2))
+
+	out, err = testState.JobSubmission(nil, job.Namespace, job.ID, 1)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	out, err = testState.JobSubmission(nil, job.Namespace, job.ID, 0)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+}
+
+func TestStateStore_JobSubmission_PrunedVersions(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	job := mock.Job()
+	index := uint64(1000)
+	for i := 0; i < structs.JobTrackedVersions+1; i++ {
+		job = job.Copy()
+		job.Meta["version"] = string(rune('a' + i))
+		sub := &structs.JobSubmission{
+			Source: job.Meta["version"],
+			Format: structs.JobSubmissionFormatHCL2,
+		}
+		require.NoError(t, testState.UpsertJobWithSubmission(structs.MsgTypeTestSetup, index, sub, job))
+		index++
+	}
+
+	// The submission of the oldest version is pruned along with the version
+	out, err := testState.JobSubmission(nil, job.Namespace, job.ID, 0)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	out, err = testState.JobSubmission(nil, job.Namespace, job.ID, structs.JobTrackedVersions)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+
+	iter, err := testState.JobSubmissions(nil)
+	require.NoError(t, err)
+	count := 0
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		count++
+	}
+	require.Equal(t, structs.JobTrackedVersions, count)
+}
+
+func TestStateStore_JobSubmission_DeleteJob(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	// Use job IDs sharing a prefix to ensure only exact matches are deleted
+	job1 := mock.Job()
+	job1.ID = "example"
+	job2 := mock.Job()
+	job2.ID = "example-2"
+
+	sub := &structs.JobSubmission{
+		Source: `job "example" {}`,
+		Format: structs.JobSubmissionFormatHCL2,
+	}
+	require.NoError(t, testState.UpsertJobWithSubmission(structs.MsgTypeTestSetup, 1000, sub, job1))
+	require.NoError(t, testState.UpsertJobWithSubmission(structs.MsgTypeTestSetup, 1001, sub, job2))
+
+	ws := memdb.NewWatchSet()
+	_, err := testState.JobSubmission(ws, job1.Namespace, job1.ID, 0)
+	require.NoError(t, err)
+
+	require.NoError(t, testState.DeleteJob(1002, job1.Namespace, job1.ID))
+	require.True(t, watchFired(ws))
+
+	out, err := testState.JobSubmission(nil, job1.Namespace, job1.ID, 0)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	out, err = testState.JobSubmission(nil, job2.Namespace, job2.ID, 0)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+
+	index, err := testState.Index(TableJobSubmission)
+	require.NoError(t, err)
+	require.EqualValues(t, 1002, index)
+}
@@ -0,0 +1,118 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// UpsertRootKeyMeta saves the metadata of a root key to the state store. If
+// the key is marked as active, all other keys are marked as inactive, so
+// there is only ever a single active key.
+func (s *StateStore) UpsertRootKeyMeta(
+	msgType structs.MessageType, index uint64, rootKeyMeta *structs.RootKeyMeta) error {
+
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	// Copy the metadata, so we do not modify the object held within the Raft
+	// log entry.
+	rootKeyMeta = rootKeyMeta.Copy()
+
+	existing, err := txn.First(TableRootKeyMeta, indexID, rootKeyMeta.KeyID)
+	if err != nil {
+		return fmt.Errorf("root key metadata lookup failed: %v", err)
+	}
+
+	if existing != nil {
+		rootKeyMeta.CreateIndex = existing.(*structs.RootKeyMeta).CreateIndex
+	} else {
+		rootKeyMeta.CreateIndex = index
+	}
+	rootKeyMeta.ModifyIndex = index
+
+	if rootKeyMeta.Active {
+		iter, err := txn.Get(TableRootKeyMeta, indexID)
+		if err != nil {
+			return fmt.Errorf("root key metadata lookup failed: %v", err)
+		}
+
+		// Collect the keys needing an update before performing any writes, as
+		// the iterator is invalidated by modifications to the table.
+		var deactivate []*structs.RootKeyMeta
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			key := raw.(*structs.RootKeyMeta)
+			if key.Active && key.KeyID != rootKeyMeta.KeyID {
+				deactivate = append(deactivate, key)
+			}
+		}
+
+		for _, key := range deactivate {
+			key = key.Copy()
+			key.Active = false
+			key.ModifyIndex = index
+			if err := txn.Insert(TableRootKeyMeta, key); err != nil {
+				return fmt.Errorf("root key metadata insert failed: %v", err)
+			}
+		}
+	}
+
+	if err := txn.Insert(TableRootKeyMeta, rootKeyMeta); err != nil {
+		return fmt.Errorf("root key metadata insert failed: %v", err)
+	}
+	if err := txn.Insert(tableIndex, &IndexEntry{TableRootKeyMeta, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	return txn.Commit()
+}
+
+// RootKeyMetas returns an iterator over all root key metadata objects.
+func (s *StateStore) RootKeyMetas(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableRootKeyMeta, indexID)
+	if err != nil {
+		return nil, fmt.Errorf("root key metadata lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// RootKeyMetaByID returns a single root key metadata object. The object will
+// be nil if no matching entry was found; it is the responsibility of the
+// caller to check for this.
+func (s *StateStore) RootKeyMetaByID(ws memdb.WatchSet, id string) (*structs.RootKeyMeta, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, raw, err := txn.FirstWatch(TableRootKeyMeta, indexID, id)
+	if err != nil {
+		return nil, fmt.Errorf("root key metadata lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if raw != nil {
+		return raw.(*structs.RootKeyMeta), nil
+	}
+	return nil, nil
+}
+
+// GetActiveRootKeyMeta returns the metadata of the currently active root key.
+// The object will be nil if no key is active, which is the case until the
+// leader has initialized the keyring.
+func (s *StateStore) GetActiveRootKeyMeta(ws memdb.WatchSet) (*structs.RootKeyMeta, error) {
+	iter, err := s.RootKeyMetas(ws)
+	if err != nil {
+		return nil, err
+	}
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		key := raw.(*structs.RootKeyMeta)
+		if key.Active {
+			return key, nil
+		}
+	}
+	return nil, nil
+}
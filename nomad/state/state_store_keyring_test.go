@@ -0,0 +1,65 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateStore_UpsertRootKeyMeta(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+	ws := memdb.NewWatchSet()
+
+	// There is no active key until one has been written.
+	active, err := testState.GetActiveRootKeyMeta(ws)
+	require.NoError(t, err)
+	require.Nil(t, active)
+
+	key1 := structs.NewRootKeyMeta()
+	key1.Active = true
+	require.NoError(t, testState.UpsertRootKeyMeta(structs.MsgTypeTestSetup, 10, key1))
+
+	active, err = testState.GetActiveRootKeyMeta(ws)
+	require.NoError(t, err)
+	require.Equal(t, key1.KeyID, active.KeyID)
+	require.Equal(t, uint64(10), active.CreateIndex)
+	require.Equal(t, uint64(10), active.ModifyIndex)
+
+	// Writing a new active key should deactivate the previous key.
+	key2 := structs.NewRootKeyMeta()
+	key2.Active = true
+	require.NoError(t, testState.UpsertRootKeyMeta(structs.MsgTypeTestSetup, 20, key2))
+
+	active, err = testState.GetActiveRootKeyMeta(ws)
+	require.NoError(t, err)
+	require.Equal(t, key2.KeyID, active.KeyID)
+
+	out, err := testState.RootKeyMetaByID(ws, key1.KeyID)
+	require.NoError(t, err)
+	require.False(t, out.Active)
+	require.Equal(t, uint64(10), out.CreateIndex)
+	require.Equal(t, uint64(20), out.ModifyIndex)
+
+	// Check the index table and list all the keys.
+	index, err := testState.Index(TableRootKeyMeta)
+	require.NoError(t, err)
+	require.Equal(t, uint64(20), index)
+
+	iter, err := testState.RootKeyMetas(ws)
+	require.NoError(t, err)
+
+	var count int
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		count++
+	}
+	require.Equal(t, 2, count)
+
+	// Looking up a key which does not exist returns nil.
+	out, err = testState.RootKeyMetaByID(ws, structs.NewRootKeyMeta().KeyID)
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
@@ -0,0 +1,236 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// nodePoolInit ensures the built-in node pools exist. Like the default
+// namespace, this is safe to do every time we create the state store, as any
+// modification made to the built-in node pools is restored from snapshots.
+func (s *StateStore) nodePoolInit() error {
+	err := s.UpsertNodePools(structs.NodePoolUpsertRequestType, 1, structs.BuiltinNodePools())
+	if err != nil {
+		return fmt.Errorf("inserting built-in node pools failed: %v", err)
+	}
+	return nil
+}
+
+// UpsertNodePools is used to insert a number of node pools into the state
+// store. It uses a single write transaction for efficiency, however, any
+// error means no entries will be committed.
+func (s *StateStore) UpsertNodePools(
+	msgType structs.MessageType, index uint64, pools []*structs.NodePool) error {
+
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for _, pool := range pools {
+		if err := s.upsertNodePoolTxn(txn, index, pool); err != nil {
+			return err
+		}
+	}
+
+	// Perform the index table update to mark the new insert.
+	if err := txn.Insert(tableIndex, &IndexEntry{TableNodePools, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	return txn.Commit()
+}
+
+// upsertNodePoolTxn inserts a single node pool into the state store using
+// the provided write transaction. It is the responsibility of the caller to
+// update the index table.
+func (s *StateStore) upsertNodePoolTxn(txn *txn, index uint64, pool *structs.NodePool) error {
+	existing, err := txn.First(TableNodePools, indexID, pool.Name)
+	if err != nil {
+		return fmt.Errorf("node pool lookup failed: %v", err)
+	}
+
+	// Set up the indexes correctly to ensure existing indexes are maintained.
+	if existing != nil {
+		pool.CreateIndex = existing.(*structs.NodePool).CreateIndex
+		pool.ModifyIndex = index
+	} else {
+		pool.CreateIndex = index
+		pool.ModifyIndex = index
+	}
+
+	if err := txn.Insert(TableNodePools, pool); err != nil {
+		return fmt.Errorf("node pool insert failed: %v", err)
+	}
+	return nil
+}
+
+// upsertNodePoolForNodeTxn creates the node pool of a node being registered
+// if it does not exist yet, so operators do not have to create node pools
+// before clients can join them.
+func upsertNodePoolForNodeTxn(txn *txn, index uint64, name string) error {
+	if name == "" {
+		return nil
+	}
+
+	existing, err := txn.First(TableNodePools, indexID, name)
+	if err != nil {
+		return fmt.Errorf("node pool lookup failed: %v", err)
+	}
+	if existing != nil {
+		return nil
+	}
+
+	pool := &structs.NodePool{
+		Name:        name,
+		CreateIndex: index,
+		ModifyIndex: index,
+	}
+	if err := txn.Insert(TableNodePools, pool); err != nil {
+		return fmt.Errorf("node pool insert failed: %v", err)
+	}
+	if err := txn.Insert(tableIndex, &IndexEntry{TableNodePools, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return nil
+}
+
+// DeleteNodePools is responsible for batch deleting node pools by name. It
+// uses a single write transaction for efficiency, however, any error means
+// no entries will be committed. An error is returned if a node pool is not
+// found, is built-in, or is still in use by nodes or non-terminal jobs.
+func (s *StateStore) DeleteNodePools(
+	msgType structs.MessageType, index uint64, names []string) error {
+
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for _, name := range names {
+		if err := s.deleteNodePoolTxn(txn, name); err != nil {
+			return err
+		}
+	}
+
+	// Update the index table to indicate an update has occurred.
+	if err := txn.Insert(tableIndex, &IndexEntry{TableNodePools, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	return txn.Commit()
+}
+
+// deleteNodePoolTxn deletes a single node pool from the state store using the
+// provided write transaction. It is the responsibility of the caller to
+// update the index table.
+func (s *StateStore) deleteNodePoolTxn(txn *txn, name string) error {
+	existing, err := txn.First(TableNodePools, indexID, name)
+	if err != nil {
+		return fmt.Errorf("node pool lookup failed: %v", err)
+	}
+	if existing == nil {
+		return errors.New("node pool not found")
+	}
+
+	pool := existing.(*structs.NodePool)
+	if pool.IsBuiltIn() {
+		return fmt.Errorf("built-in node pool %q cannot be deleted", name)
+	}
+
+	// Ensure the node pool does not have any nodes, as these would otherwise
+	// belong to a node pool which cannot be targeted or configured.
+	nodeIter, err := txn.Get("nodes", indexNodePool, name)
+	if err != nil {
+		return fmt.Errorf("node lookup failed: %v", err)
+	}
+	if raw := nodeIter.Next(); raw != nil {
+		return fmt.Errorf("node pool %q contains at least one node %q. "+
+			"All nodes must be removed from the node pool before it can be deleted",
+			name, raw.(*structs.Node).ID)
+	}
+
+	// Ensure no non-terminal job targets the node pool.
+	jobIter, err := txn.Get("jobs", "id")
+	if err != nil {
+		return fmt.Errorf("job lookup failed: %v", err)
+	}
+	for raw := jobIter.Next(); raw != nil; raw = jobIter.Next() {
+		job := raw.(*structs.Job)
+		if job.NodePool == name && job.Status != structs.JobStatusDead {
+			return fmt.Errorf("node pool %q is used by at least one non-terminal job %q. "+
+				"All jobs using the node pool must be terminal before it can be deleted",
+				name, job.NamespacedID())
+		}
+	}
+
+	if err := txn.Delete(TableNodePools, existing); err != nil {
+		return fmt.Errorf("node pool deletion failed: %v", err)
+	}
+	return nil
+}
+
+// NodePools returns an iterator that contains all node pools stored within
+// state.
+func (s *StateStore) NodePools(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableNodePools, indexID)
+	if err != nil {
+		return nil, fmt.Errorf("node pool lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// NodePoolsByNamePrefix returns an iterator over the node pools whose name
+// starts with the prefix.
+func (s *StateStore) NodePoolsByNamePrefix(ws memdb.WatchSet, namePrefix string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableNodePools, indexID+"_prefix", namePrefix)
+	if err != nil {
+		return nil, fmt.Errorf("node pool lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// NodePoolByName returns a single node pool specified by the input name. The
+// node pool object will be nil, if no matching entry was found; it is the
+// responsibility of the caller to check for this.
+func (s *StateStore) NodePoolByName(ws memdb.WatchSet, name string) (*structs.NodePool, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch(TableNodePools, indexID, name)
+	if err != nil {
+		return nil, fmt.Errorf("node pool lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.NodePool), nil
+	}
+	return nil, nil
+}
+
+// NodesByNodePool returns an iterator over the nodes of the node pool. All
+// nodes are returned for the built-in "all" node pool.
+func (s *StateStore) NodesByNodePool(ws memdb.WatchSet, pool string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	var iter memdb.ResultIterator
+	var err error
+	if pool == structs.NodePoolAll {
+		iter, err = txn.Get("nodes", "id")
+	} else {
+		iter, err = txn.Get("nodes", indexNodePool, pool)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("node lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
@@ -0,0 +1,147 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateStore_NodePoolInit(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	for _, name := range []string{structs.NodePoolAll, structs.NodePoolDefault} {
+		pool, err := testState.NodePoolByName(nil, name)
+		require.NoError(t, err)
+		require.NotNil(t, pool)
+		require.True(t, pool.IsBuiltIn())
+	}
+}
+
+func TestStateStore_UpsertNodePools(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	pool1 := mock.NodePool()
+	pool2 := mock.NodePool()
+
+	// Create a watchset so we can test that upsert fires the watch
+	ws := memdb.NewWatchSet()
+	_, err := testState.NodePoolByName(ws, pool1.Name)
+	require.NoError(t, err)
+
+	require.NoError(t, testState.UpsertNodePools(
+		structs.MsgTypeTestSetup, 1000, []*structs.NodePool{pool1, pool2}))
+	require.True(t, watchFired(ws))
+
+	ws = memdb.NewWatchSet()
+	out, err := testState.NodePoolByName(ws, pool1.Name)
+	require.NoError(t, err)
+	require.Equal(t, pool1, out)
+	require.EqualValues(t, 1000, out.CreateIndex)
+
+	// Updating a node pool must maintain its create index
+	pool2Update := pool2.Copy()
+	pool2Update.Description = "updated"
+	require.NoError(t, testState.UpsertNodePools(
+		structs.MsgTypeTestSetup, 1001, []*structs.NodePool{pool2Update}))
+
+	out, err = testState.NodePoolByName(ws, pool2.Name)
+	require.NoError(t, err)
+	require.Equal(t, "updated", out.Description)
+	require.EqualValues(t, 1000, out.CreateIndex)
+	require.EqualValues(t, 1001, out.ModifyIndex)
+
+	index, err := testState.Index(TableNodePools)
+	require.NoError(t, err)
+	require.EqualValues(t, 1001, index)
+
+	iter, err := testState.NodePools(nil)
+	require.NoError(t, err)
+	var count int
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		count++
+	}
+	require.Equal(t, 4, count)
+}
+
+func TestStateStore_DeleteNodePools(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	pool := mock.NodePool()
+	require.NoError(t, testState.UpsertNodePools(
+		structs.MsgTypeTestSetup, 1000, []*structs.NodePool{pool}))
+
+	// Built-in and unknown node pools cannot be deleted
+	err := testState.DeleteNodePools(structs.MsgTypeTestSetup, 1001, []string{structs.NodePoolDefault})
+	require.ErrorContains(t, err, "cannot be deleted")
+	err = testState.DeleteNodePools(structs.MsgTypeTestSetup, 1001, []string{"unknown"})
+	require.ErrorContains(t, err, "not found")
+
+	// Node pools with nodes cannot be deleted
+	node := mock.Node()
+	node.NodePool = pool.Name
+	require.NoError(t, testState.UpsertNode(structs.MsgTypeTestSetup, 1001, node))
+	err = testState.DeleteNodePools(structs.MsgTypeTestSetup, 1002, []string{pool.Name})
+	require.ErrorContains(t, err, "contains at least one node")
+	require.NoError(t, testState.DeleteNode(structs.MsgTypeTestSetup, 1003, []string{node.ID}))
+
+	// Node pools with non-terminal jobs cannot be deleted
+	job := mock.Job()
+	job.NodePool = pool.Name
+	require.NoError(t, testState.UpsertJob(structs.MsgTypeTestSetup, 1004, job))
+	err = testState.DeleteNodePools(structs.MsgTypeTestSetup, 1005, []string{pool.Name})
+	require.ErrorContains(t, err, "non-terminal job")
+	require.NoError(t, testState.DeleteJob(1006, job.Namespace, job.ID))
+
+	// Create a watchset so we can test that delete fires the watch
+	ws := memdb.NewWatchSet()
+	_, err = testState.NodePoolByName(ws, pool.Name)
+	require.NoError(t, err)
+
+	require.NoError(t, testState.DeleteNodePools(structs.MsgTypeTestSetup, 1007, []string{pool.Name}))
+	require.True(t, watchFired(ws))
+
+	out, err := testState.NodePoolByName(nil, pool.Name)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	index, err := testState.Index(TableNodePools)
+	require.NoError(t, err)
+	require.EqualValues(t, 1007, index)
+}
+
+func TestStateStore_NodesByNodePool(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	node1 := mock.Node()
+	node2 := mock.Node()
+	node2.NodePool = "gpu"
+	require.NoError(t, testState.UpsertNode(structs.MsgTypeTestSetup, 1000, node1))
+	require.NoError(t, testState.UpsertNode(structs.MsgTypeTestSetup, 1001, node2))
+
+	// Registering a node creates its node pool
+	pool, err := testState.NodePoolByName(nil, "gpu")
+	require.NoError(t, err)
+	require.NotNil(t, pool)
+	require.EqualValues(t, 1001, pool.CreateIndex)
+
+	nodeIDs := func(pool string) []string {
+		iter, err := testState.NodesByNodePool(nil, pool)
+		require.NoError(t, err)
+		var ids []string
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			ids = append(ids, raw.(*structs.Node).ID)
+		}
+		return ids
+	}
+	require.Equal(t, []string{node1.ID}, nodeIDs(structs.NodePoolDefault))
+	require.Equal(t, []string{node2.ID}, nodeIDs("gpu"))
+	require.ElementsMatch(t, []string{node1.ID, node2.ID}, nodeIDs(structs.NodePoolAll))
+}
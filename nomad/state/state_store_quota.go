@@ -0,0 +1,220 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// UpsertQuotaSpecs is used to insert a number of quota specifications into
+// the state store. It uses a single write transaction for efficiency,
+// however, any error means no entries will be committed.
+func (s *StateStore) UpsertQuotaSpecs(
+	msgType structs.MessageType, index uint64, specs []*structs.QuotaSpec) error {
+
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for _, spec := range specs {
+		if err := s.upsertQuotaSpecTxn(txn, index, spec); err != nil {
+			return err
+		}
+	}
+
+	// Perform the index table update to mark the new insert.
+	if err := txn.Insert(tableIndex, &IndexEntry{TableQuotaSpecs, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	return txn.Commit()
+}
+
+// upsertQuotaSpecTxn inserts a single quota specification into the state
+// store using the provided write transaction. It is the responsibility of
+// the caller to update the index table.
+func (s *StateStore) upsertQuotaSpecTxn(txn *txn, index uint64, spec *structs.QuotaSpec) error {
+	// Ensure the hashes are set. This should be done outside the state store
+	// for performance reasons, but we check here for defense in depth.
+	if len(spec.Hash) == 0 {
+		spec.SetHash()
+	}
+
+	existing, err := txn.First(TableQuotaSpecs, indexID, spec.Name)
+	if err != nil {
+		return fmt.Errorf("quota specification lookup failed: %v", err)
+	}
+
+	// Set up the indexes correctly to ensure existing indexes are maintained.
+	if existing != nil {
+		spec.CreateIndex = existing.(*structs.QuotaSpec).CreateIndex
+		spec.ModifyIndex = index
+	} else {
+		spec.CreateIndex = index
+		spec.ModifyIndex = index
+	}
+
+	if err := txn.Insert(TableQuotaSpecs, spec); err != nil {
+		return fmt.Errorf("quota specification insert failed: %v", err)
+	}
+	return nil
+}
+
+// DeleteQuotaSpecs is responsible for batch deleting quota specifications by
+// name. It uses a single write transaction for efficiency, however, any
+// error means no entries will be committed. An error is returned if a quota
+// specification is not found or is still attached to a namespace.
+func (s *StateStore) DeleteQuotaSpecs(
+	msgType structs.MessageType, index uint64, names []string) error {
+
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for _, name := range names {
+		existing, err := txn.First(TableQuotaSpecs, indexID, name)
+		if err != nil {
+			return fmt.Errorf("quota specification lookup failed: %v", err)
+		}
+		if existing == nil {
+			return errors.New("quota specification not found")
+		}
+
+		// Ensure no namespace is attached to the quota, as these would
+		// otherwise reference a quota which does not exist.
+		nsIter, err := txn.Get(TableNamespaces, "quota", name)
+		if err != nil {
+			return fmt.Errorf("namespace lookup failed: %v", err)
+		}
+		if raw := nsIter.Next(); raw != nil {
+			return fmt.Errorf("quota specification %q is used by at least one namespace %q. "+
+				"All namespaces must be detached from the quota before it can be deleted",
+				name, raw.(*structs.Namespace).Name)
+		}
+
+		if err := txn.Delete(TableQuotaSpecs, existing); err != nil {
+			return fmt.Errorf("quota specification deletion failed: %v", err)
+		}
+	}
+
+	// Update the index table to indicate an update has occurred.
+	if err := txn.Insert(tableIndex, &IndexEntry{TableQuotaSpecs, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	return txn.Commit()
+}
+
+// QuotaSpecs returns an iterator that contains all quota specifications
+// stored within state.
+func (s *StateStore) QuotaSpecs(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableQuotaSpecs, indexID)
+	if err != nil {
+		return nil, fmt.Errorf("quota specification lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// QuotaSpecsByNamePrefix returns an iterator over the quota specifications
+// whose name starts with the prefix.
+func (s *StateStore) QuotaSpecsByNamePrefix(ws memdb.WatchSet, namePrefix string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableQuotaSpecs, indexID+"_prefix", namePrefix)
+	if err != nil {
+		return nil, fmt.Errorf("quota specification lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// QuotaSpecByName returns a single quota specification specified by the
+// input name. The quota specification object is returned nil if it is not
+// found.
+func (s *StateStore) QuotaSpecByName(ws memdb.WatchSet, name string) (*structs.QuotaSpec, error) {
+	txn := s.db.ReadTxn()
+	return s.quotaSpecByNameTxn(ws, txn, name)
+}
+
+func (s *StateStore) quotaSpecByNameTxn(ws memdb.WatchSet, txn ReadTxn, name string) (*structs.QuotaSpec, error) {
+	watchCh, existing, err := txn.FirstWatch(TableQuotaSpecs, indexID, name)
+	if err != nil {
+		return nil, fmt.Errorf("quota specification lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing == nil {
+		return nil, nil
+	}
+	return existing.(*structs.QuotaSpec), nil
+}
+
+// quotaSpecExists returns whether the quota exists
+func (s *StateStore) quotaSpecExists(txn *txn, name string) (bool, error) {
+	existing, err := txn.First(TableQuotaSpecs, indexID, name)
+	if err != nil {
+		return false, fmt.Errorf("quota specification lookup failed: %v", err)
+	}
+	return existing != nil, nil
+}
+
+// QuotaUsageByName returns the usage of the quota specification in the
+// region of the state store. The usage is computed from the non-terminal
+// allocations of the namespaces attached to the quota, and only contains the
+// limit of the region. The usage is returned nil if the quota specification
+// is not found.
+func (s *StateStore) QuotaUsageByName(ws memdb.WatchSet, name string) (*structs.QuotaUsage, error) {
+	txn := s.db.ReadTxn()
+
+	spec, err := s.quotaSpecByNameTxn(ws, txn, name)
+	if err != nil || spec == nil {
+		return nil, err
+	}
+
+	usage := &structs.QuotaUsage{
+		Name:        spec.Name,
+		Used:        make(map[string]*structs.QuotaLimit),
+		CreateIndex: spec.CreateIndex,
+		ModifyIndex: spec.ModifyIndex,
+	}
+
+	limit := spec.RegionLimit(s.config.Region)
+	if limit == nil {
+		return usage, nil
+	}
+
+	nsIter, err := txn.Get(TableNamespaces, "quota", name)
+	if err != nil {
+		return nil, fmt.Errorf("namespace lookup failed: %v", err)
+	}
+	ws.Add(nsIter.WatchCh())
+
+	used := new(structs.Resources)
+	for raw := nsIter.Next(); raw != nil; raw = nsIter.Next() {
+		ns := raw.(*structs.Namespace)
+
+		allocIter, err := s.allocsByNamespaceImpl(ws, txn, ns.Name)
+		if err != nil {
+			return nil, err
+		}
+		for rawAlloc := allocIter.Next(); rawAlloc != nil; rawAlloc = allocIter.Next() {
+			alloc := rawAlloc.(*structs.Allocation)
+			if alloc.TerminalStatus() {
+				continue
+			}
+			used.AddQuotaResources(alloc.QuotaResources())
+		}
+	}
+
+	usage.Used[limit.HashKey()] = &structs.QuotaLimit{
+		Region:      limit.Region,
+		RegionLimit: used,
+		Hash:        limit.Hash,
+	}
+	return usage, nil
+}
@@ -0,0 +1,147 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateStore_UpsertQuotaSpecs(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	spec1 := mock.QuotaSpec()
+	spec2 := mock.QuotaSpec()
+
+	// Create a watchset so we can test that upsert fires the watch
+	ws := memdb.NewWatchSet()
+	_, err := testState.QuotaSpecByName(ws, spec1.Name)
+	require.NoError(t, err)
+
+	require.NoError(t, testState.UpsertQuotaSpecs(
+		structs.MsgTypeTestSetup, 1000, []*structs.QuotaSpec{spec1, spec2}))
+	require.True(t, watchFired(ws))
+
+	out, err := testState.QuotaSpecByName(nil, spec1.Name)
+	require.NoError(t, err)
+	require.Equal(t, spec1, out)
+	require.EqualValues(t, 1000, out.CreateIndex)
+
+	// Updating a quota specification must maintain its create index
+	spec2Update := spec2.Copy()
+	spec2Update.Description = "updated"
+	spec2Update.SetHash()
+	require.NoError(t, testState.UpsertQuotaSpecs(
+		structs.MsgTypeTestSetup, 1001, []*structs.QuotaSpec{spec2Update}))
+
+	out, err = testState.QuotaSpecByName(nil, spec2.Name)
+	require.NoError(t, err)
+	require.Equal(t, "updated", out.Description)
+	require.EqualValues(t, 1000, out.CreateIndex)
+	require.EqualValues(t, 1001, out.ModifyIndex)
+
+	index, err := testState.Index(TableQuotaSpecs)
+	require.NoError(t, err)
+	require.EqualValues(t, 1001, index)
+
+	iter, err := testState.QuotaSpecsByNamePrefix(nil, spec1.Name[:len(spec1.Name)-1])
+	require.NoError(t, err)
+	var count int
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		count++
+	}
+	require.Equal(t, 1, count)
+}
+
+func TestStateStore_DeleteQuotaSpecs(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	spec := mock.QuotaSpec()
+	require.NoError(t, testState.UpsertQuotaSpecs(
+		structs.MsgTypeTestSetup, 1000, []*structs.QuotaSpec{spec}))
+
+	// Namespaces can only reference existing quotas
+	ns := mock.Namespace()
+	ns.Quota = "unknown"
+	require.ErrorContains(t, testState.UpsertNamespaces(1001, []*structs.Namespace{ns}),
+		`using non-existent quota "unknown"`)
+
+	ns.Quota = spec.Name
+	require.NoError(t, testState.UpsertNamespaces(1001, []*structs.Namespace{ns}))
+
+	// Quotas attached to a namespace cannot be deleted
+	err := testState.DeleteQuotaSpecs(structs.MsgTypeTestSetup, 1002, []string{spec.Name})
+	require.ErrorContains(t, err, "is used by at least one namespace")
+
+	ns = ns.Copy()
+	ns.Quota = ""
+	require.NoError(t, testState.UpsertNamespaces(1003, []*structs.Namespace{ns}))
+	require.NoError(t, testState.DeleteQuotaSpecs(structs.MsgTypeTestSetup, 1004, []string{spec.Name}))
+
+	out, err := testState.QuotaSpecByName(nil, spec.Name)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	err = testState.DeleteQuotaSpecs(structs.MsgTypeTestSetup, 1005, []string{spec.Name})
+	require.EqualError(t, err, "quota specification not found")
+}
+
+func TestStateStore_QuotaUsageByName(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	spec := mock.QuotaSpec()
+	spec.Limits = append(spec.Limits, &structs.QuotaLimit{
+		Region:      "europe",
+		RegionLimit: &structs.Resources{CPU: 100},
+	})
+	spec.SetHash()
+	require.NoError(t, testState.UpsertQuotaSpecs(
+		structs.MsgTypeTestSetup, 1000, []*structs.QuotaSpec{spec}))
+
+	ns1 := mock.Namespace()
+	ns1.Quota = spec.Name
+	ns2 := mock.Namespace()
+	ns2.Quota = spec.Name
+	ns3 := mock.Namespace()
+	require.NoError(t, testState.UpsertNamespaces(1001, []*structs.Namespace{ns1, ns2, ns3}))
+
+	// Allocations of every namespace attached to the quota are counted,
+	// unless they are terminal
+	var allocs []*structs.Allocation
+	for _, ns := range []string{ns1.Name, ns2.Name, ns2.Name, ns3.Name} {
+		alloc := mock.Alloc()
+		alloc.Namespace = ns
+		alloc.Job.Namespace = ns
+		allocs = append(allocs, alloc)
+	}
+	allocs[2].DesiredStatus = structs.AllocDesiredStatusStop
+	for _, alloc := range allocs {
+		require.NoError(t, testState.UpsertJob(structs.MsgTypeTestSetup, 1002, alloc.Job))
+	}
+	require.NoError(t, testState.UpsertAllocs(structs.MsgTypeTestSetup, 1003, allocs))
+
+	usage, err := testState.QuotaUsageByName(nil, spec.Name)
+	require.NoError(t, err)
+	require.Equal(t, spec.Name, usage.Name)
+	require.Len(t, usage.Used, 1)
+
+	// Only the limit of the region of the state store is used
+	limit := spec.RegionLimit("global")
+	used := usage.Used[limit.HashKey()]
+	require.NotNil(t, used)
+	require.Equal(t, "global", used.Region)
+	require.Equal(t, 1000, used.RegionLimit.CPU)
+	require.Equal(t, 512, used.RegionLimit.MemoryMB)
+	require.Equal(t, 512, used.RegionLimit.MemoryMaxMB)
+
+	// Unknown quotas have no usage
+	usage, err = testState.QuotaUsageByName(nil, "unknown")
+	require.NoError(t, err)
+	require.Nil(t, usage)
+}
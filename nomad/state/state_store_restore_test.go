@@ -573,3 +573,63 @@ func TestStateStore_ServiceRegistrationRestore(t *testing.T) {
 		require.Equal(t, serviceRegs[i], out)
 	}
 }
+
+func TestStateStore_VariablesRestore(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	variable := mock.VariableEncrypted()
+	variable.CreateIndex = 13
+	variable.ModifyIndex = 13
+
+	restore, err := testState.Restore()
+	require.NoError(t, err)
+	require.NoError(t, restore.VariablesRestore(variable))
+	require.NoError(t, restore.Commit())
+
+	out, err := testState.GetVariable(memdb.NewWatchSet(), variable.Namespace, variable.Path)
+	require.NoError(t, err)
+	require.Equal(t, variable, out)
+}
+
+func TestStateStore_RootKeyMetaRestore(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	keyMeta := structs.NewRootKeyMeta()
+	keyMeta.Active = true
+	keyMeta.CreateIndex = 13
+	keyMeta.ModifyIndex = 13
+
+	restore, err := testState.Restore()
+	require.NoError(t, err)
+	require.NoError(t, restore.RootKeyMetaRestore(keyMeta))
+	require.NoError(t, restore.Commit())
+
+	out, err := testState.RootKeyMetaByID(memdb.NewWatchSet(), keyMeta.KeyID)
+	require.NoError(t, err)
+	require.Equal(t, keyMeta, out)
+}
+
+func TestStateStore_ACLRoleRestore(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	// Set up our test registrations and index.
+	expectedIndex := uint64(13)
+	aclRole := mock.ACLRole()
+	aclRole.CreateIndex = expectedIndex
+	aclRole.ModifyIndex = expectedIndex
+
+	restore, err := testState.Restore()
+	require.NoError(t, err)
+	require.NoError(t, restore.ACLRoleRestore(aclRole))
+	require.NoError(t, restore.Commit())
+
+	// Check the state is now populated as we expect and that we can find the
+	// restored registrations.
+	ws := memdb.NewWatchSet()
+	out, err := testState.GetACLRoleByName(ws, aclRole.Name)
+	require.NoError(t, err)
+	require.Equal(t, aclRole, out)
+}
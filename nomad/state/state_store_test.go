@@ -1281,6 +1281,36 @@ func TestStateStore_UpdateNodeStatus_Node(t *testing.T) {
 	require.False(watchFired(ws))
 }
 
+func TestStateStore_UpdateNodeUtilization(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	state := testStateStore(t)
+	node := mock.Node()
+
+	require.NoError(state.UpsertNode(structs.MsgTypeTestSetup, 800, node))
+
+	utilization := &structs.NodeUtilization{
+		CpuUsedMHz:   1500,
+		MemoryUsedMB: 2048,
+		UpdatedAt:    70,
+	}
+	require.NoError(state.UpdateNodeUtilization(structs.MsgTypeTestSetup, 801, node.ID, utilization))
+
+	// Utilization updates must not advance the node or table indexes
+	out, err := state.NodeByID(nil, node.ID)
+	require.NoError(err)
+	require.Equal(utilization, out.Utilization)
+	require.EqualValues(800, out.ModifyIndex)
+
+	index, err := state.Index("nodes")
+	require.NoError(err)
+	require.EqualValues(800, index)
+
+	// Updating an unknown node fails
+	require.Error(state.UpdateNodeUtilization(structs.MsgTypeTestSetup, 802, "unknown", utilization))
+}
+
 func TestStateStore_BatchUpdateNodeDrain(t *testing.T) {
 	ci.Parallel(t)
 	require := require.New(t)
@@ -8788,6 +8818,66 @@ func TestStateStore_ACLTokensByGlobal(t *testing.T) {
 	})
 }
 
+func TestStateStore_ACLTokensByExpired(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+	now := time.Now().UTC()
+
+	// Generate a mix of local and global tokens, some of which do not expire.
+	expiry := func(d time.Duration) *time.Time {
+		ts := now.Add(d)
+		return &ts
+	}
+
+	tk1 := mock.ACLToken()
+	tk1.ExpirationTime = expiry(time.Hour)
+
+	tk2 := mock.ACLToken()
+	tk2.ExpirationTime = expiry(-time.Hour)
+
+	tk3 := mock.ACLToken()
+
+	tk4 := mock.ACLToken()
+	tk4.Global = true
+	tk4.ExpirationTime = expiry(time.Minute)
+
+	tk5 := mock.ACLToken()
+	tk5.Global = true
+
+	err := state.UpsertACLTokens(structs.MsgTypeTestSetup, 1000,
+		[]*structs.ACLToken{tk1, tk2, tk3, tk4, tk5})
+	require.NoError(t, err)
+
+	gatherTokens := func(iter memdb.ResultIterator) []string {
+		var ids []string
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			ids = append(ids, raw.(*structs.ACLToken).AccessorID)
+		}
+		return ids
+	}
+
+	// Local tokens are returned with the earliest expiration first.
+	iter, err := state.ACLTokensByExpired(false)
+	require.NoError(t, err)
+	require.Equal(t, []string{tk2.AccessorID, tk1.AccessorID}, gatherTokens(iter))
+
+	iter, err = state.ACLTokensByExpired(true)
+	require.NoError(t, err)
+	require.Equal(t, []string{tk4.AccessorID}, gatherTokens(iter))
+
+	// Updating a token must not change its expiration.
+	tk1Update := tk1.Copy()
+	tk1Update.ExpirationTime = nil
+	require.NoError(t, state.UpsertACLTokens(structs.MsgTypeTestSetup, 1010,
+		[]*structs.ACLToken{tk1Update}))
+
+	out, err := state.ACLTokenByAccessorID(nil, tk1.AccessorID)
+	require.NoError(t, err)
+	require.NotNil(t, out.ExpirationTime)
+	require.True(t, tk1.ExpirationTime.Equal(*out.ExpirationTime))
+}
+
 func TestStateStore_OneTimeTokens(t *testing.T) {
 	ci.Parallel(t)
 	index := uint64(100)
@@ -0,0 +1,188 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// VarApply is used to perform the variable operation described by the request
+// against the state store. It is the only write entrypoint for variables and
+// is called by the FSM.
+func (s *StateStore) VarApply(
+	msgType structs.MessageType, index uint64, req *structs.VarApplyStateRequest) error {
+
+	if req.Var == nil {
+		return fmt.Errorf("variable is required")
+	}
+
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	var (
+		updated bool
+		err     error
+	)
+
+	switch req.Op {
+	case structs.VarOpSet:
+		updated, err = s.varSetTxn(index, txn, req.Var)
+	case structs.VarOpDelete:
+		updated, err = s.varDeleteTxn(txn, req.Var.Namespace, req.Var.Path)
+	default:
+		err = fmt.Errorf("unsupported variable operation %q", req.Op)
+	}
+	if err != nil {
+		return err
+	}
+
+	// If nothing was modified, exit early without updating the index table.
+	if !updated {
+		return nil
+	}
+
+	if err := txn.Insert(tableIndex, &IndexEntry{TableVariables, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return txn.Commit()
+}
+
+// varSetTxn inserts a single variable into the state store using the provided
+// write transaction. It is the responsibility of the caller to update the
+// index table.
+func (s *StateStore) varSetTxn(
+	index uint64, txn *txn, variable *structs.VariableEncrypted) (bool, error) {
+
+	// The namespace must exist, otherwise the variable would be inaccessible
+	// and would prevent the namespace from being created and deleted
+	// correctly.
+	ns, err := txn.First(TableNamespaces, indexID, variable.Namespace)
+	if err != nil {
+		return false, fmt.Errorf("namespace lookup failed: %v", err)
+	}
+	if ns == nil {
+		return false, fmt.Errorf("namespace %q does not exist", variable.Namespace)
+	}
+
+	existing, err := txn.First(TableVariables, indexID, variable.Namespace, variable.Path)
+	if err != nil {
+		return false, fmt.Errorf("variable lookup failed: %v", err)
+	}
+
+	// Copy the variable, so we do not modify the object held within the Raft
+	// log entry, and set up the indexes.
+	variable = variable.Copy()
+
+	if existing != nil {
+		exist := existing.(*structs.VariableEncrypted)
+		if exist.Equals(variable) {
+			return false, nil
+		}
+		variable.CreateIndex = exist.CreateIndex
+		variable.CreateTime = exist.CreateTime
+	} else {
+		variable.CreateIndex = index
+		variable.CreateTime = variable.ModifyTime
+	}
+	variable.ModifyIndex = index
+
+	if err := txn.Insert(TableVariables, variable); err != nil {
+		return false, fmt.Errorf("variable insert failed: %v", err)
+	}
+	return true, nil
+}
+
+// varDeleteTxn deletes a single variable from the state store using the
+// provided write transaction. Deleting a variable which does not exist is not
+// an error. It is the responsibility of the caller to update the index table.
+func (s *StateStore) varDeleteTxn(txn *txn, namespace, path string) (bool, error) {
+	existing, err := txn.First(TableVariables, indexID, namespace, path)
+	if err != nil {
+		return false, fmt.Errorf("variable lookup failed: %v", err)
+	}
+	if existing == nil {
+		return false, nil
+	}
+
+	if err := txn.Delete(TableVariables, existing); err != nil {
+		return false, fmt.Errorf("variable deletion failed: %v", err)
+	}
+	return true, nil
+}
+
+// GetVariables returns an iterator that contains all variables stored within
+// state. This is primarily useful when performing listings which use the
+// namespace wildcard operator. The caller is responsible for ensuring ACL
+// access is confirmed, or filtering is performed before responding.
+func (s *StateStore) GetVariables(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableVariables, indexID)
+	if err != nil {
+		return nil, fmt.Errorf("variables lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// GetVariablesByNamespace returns an iterator that contains all variables
+// belonging to the provided namespace.
+func (s *StateStore) GetVariablesByNamespace(
+	ws memdb.WatchSet, namespace string) (memdb.ResultIterator, error) {
+	return s.GetVariablesByNamespaceAndPrefix(ws, namespace, "")
+}
+
+// GetVariablesByNamespaceAndPrefix returns an iterator that contains all
+// variables belonging to the provided namespace whose path begins with the
+// prefix.
+func (s *StateStore) GetVariablesByNamespaceAndPrefix(
+	ws memdb.WatchSet, namespace, prefix string) (memdb.ResultIterator, error) {
+
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableVariables, indexID+"_prefix", namespace, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("variables lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// GetVariablesByKeyID returns an iterator that contains all variables which
+// were encrypted using the root key with the provided ID.
+func (s *StateStore) GetVariablesByKeyID(
+	ws memdb.WatchSet, keyID string) (memdb.ResultIterator, error) {
+
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableVariables, indexKeyID, keyID)
+	if err != nil {
+		return nil, fmt.Errorf("variables lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// GetVariable returns a single variable. The variable will be nil if no
+// matching entry was found; it is the responsibility of the caller to check
+// for this.
+func (s *StateStore) GetVariable(
+	ws memdb.WatchSet, namespace, path string) (*structs.VariableEncrypted, error) {
+
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch(TableVariables, indexID, namespace, path)
+	if err != nil {
+		return nil, fmt.Errorf("variable lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.VariableEncrypted), nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,187 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateStore_VarApply(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	// SubTest Marker: This ensures a new variable is inserted as expected
+	// with its correct indexes and times, along with an update to the index
+	// table.
+	variable := mock.VariableEncrypted()
+	insertIndex := uint64(20)
+
+	require.NoError(t, testState.VarApply(structs.MsgTypeTestSetup, insertIndex,
+		&structs.VarApplyStateRequest{Op: structs.VarOpSet, Var: variable}))
+
+	initialIndex, err := testState.Index(TableVariables)
+	require.NoError(t, err)
+	require.Equal(t, insertIndex, initialIndex)
+
+	ws := memdb.NewWatchSet()
+	out, err := testState.GetVariable(ws, variable.Namespace, variable.Path)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, insertIndex, out.CreateIndex)
+	require.Equal(t, insertIndex, out.ModifyIndex)
+	require.Equal(t, variable.ModifyTime, out.CreateTime)
+	require.Equal(t, variable.Data, out.Data)
+
+	// The variable held by the request should not have been modified.
+	require.Zero(t, variable.CreateIndex)
+
+	// SubTest Marker: This section attempts to upsert the exact same variable
+	// without any modification. In this case, the index table should not be
+	// updated, indicating no write actually happened due to equality
+	// checking.
+	reInsertIndex := uint64(30)
+	require.NoError(t, testState.VarApply(structs.MsgTypeTestSetup, reInsertIndex,
+		&structs.VarApplyStateRequest{Op: structs.VarOpSet, Var: variable}))
+	reInsertActualIndex, err := testState.Index(TableVariables)
+	require.NoError(t, err)
+	require.Equal(t, insertIndex, reInsertActualIndex, "index should not have changed")
+
+	// SubTest Marker: This section modifies the variable and performs an
+	// upsert. The create index and time should be retained.
+	update := variable.Copy()
+	update.Data = []byte("modified")
+	update.ModifyTime++
+
+	updateIndex := uint64(40)
+	require.NoError(t, testState.VarApply(structs.MsgTypeTestSetup, updateIndex,
+		&structs.VarApplyStateRequest{Op: structs.VarOpSet, Var: update}))
+
+	out, err = testState.GetVariable(ws, variable.Namespace, variable.Path)
+	require.NoError(t, err)
+	require.Equal(t, insertIndex, out.CreateIndex)
+	require.Equal(t, updateIndex, out.ModifyIndex)
+	require.Equal(t, variable.ModifyTime, out.CreateTime)
+	require.Equal(t, update.ModifyTime, out.ModifyTime)
+	require.Equal(t, []byte("modified"), out.Data)
+
+	// SubTest Marker: Writing a variable to a namespace which does not exist
+	// should fail.
+	missingNS := mock.VariableEncrypted()
+	missingNS.Namespace = "platform"
+	err = testState.VarApply(structs.MsgTypeTestSetup, 50,
+		&structs.VarApplyStateRequest{Op: structs.VarOpSet, Var: missingNS})
+	require.ErrorContains(t, err, `namespace "platform" does not exist`)
+
+	// SubTest Marker: Deleting the variable should remove it and update the
+	// index table, while deleting it again should be a no-op.
+	deleteIndex := uint64(60)
+	require.NoError(t, testState.VarApply(structs.MsgTypeTestSetup, deleteIndex,
+		&structs.VarApplyStateRequest{Op: structs.VarOpDelete, Var: variable}))
+
+	out, err = testState.GetVariable(ws, variable.Namespace, variable.Path)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	deleteActualIndex, err := testState.Index(TableVariables)
+	require.NoError(t, err)
+	require.Equal(t, deleteIndex, deleteActualIndex)
+
+	require.NoError(t, testState.VarApply(structs.MsgTypeTestSetup, 70,
+		&structs.VarApplyStateRequest{Op: structs.VarOpDelete, Var: variable}))
+	deleteActualIndex, err = testState.Index(TableVariables)
+	require.NoError(t, err)
+	require.Equal(t, deleteIndex, deleteActualIndex, "index should not have changed")
+
+	// SubTest Marker: Unknown operations should be rejected.
+	err = testState.VarApply(structs.MsgTypeTestSetup, 80,
+		&structs.VarApplyStateRequest{Op: "cas", Var: variable})
+	require.ErrorContains(t, err, "unsupported variable operation")
+}
+
+func TestStateStore_GetVariablesByNamespaceAndPrefix(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	ns := mock.Namespace()
+	ns.Name = "platform"
+	require.NoError(t, testState.UpsertNamespaces(10, []*structs.Namespace{ns}))
+
+	paths := []struct {
+		namespace, path string
+	}{
+		{structs.DefaultNamespace, "nomad/jobs/example"},
+		{structs.DefaultNamespace, "nomad/jobs/example/web"},
+		{structs.DefaultNamespace, "secret/creds"},
+		{"platform", "nomad/jobs/example"},
+	}
+
+	for i, p := range paths {
+		variable := mock.VariableEncrypted()
+		variable.Namespace = p.namespace
+		variable.Path = p.path
+		require.NoError(t, testState.VarApply(structs.MsgTypeTestSetup, uint64(20+i),
+			&structs.VarApplyStateRequest{Op: structs.VarOpSet, Var: variable}))
+	}
+
+	collect := func(iter memdb.ResultIterator) []string {
+		var out []string
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			v := raw.(*structs.VariableEncrypted)
+			out = append(out, v.Namespace+"/"+v.Path)
+		}
+		return out
+	}
+
+	ws := memdb.NewWatchSet()
+
+	iter, err := testState.GetVariables(ws)
+	require.NoError(t, err)
+	require.Len(t, collect(iter), 4)
+
+	iter, err = testState.GetVariablesByNamespace(ws, structs.DefaultNamespace)
+	require.NoError(t, err)
+	require.Len(t, collect(iter), 3)
+
+	iter, err = testState.GetVariablesByNamespaceAndPrefix(ws, structs.DefaultNamespace, "nomad/jobs")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{
+		"default/nomad/jobs/example",
+		"default/nomad/jobs/example/web",
+	}, collect(iter))
+
+	iter, err = testState.GetVariablesByNamespaceAndPrefix(ws, "platform", "secret")
+	require.NoError(t, err)
+	require.Empty(t, collect(iter))
+
+	// Deleting a namespace which still contains variables should fail.
+	err = testState.DeleteNamespaces(30, []string{"platform"})
+	require.ErrorContains(t, err, "variables")
+}
+
+func TestStateStore_GetVariablesByKeyID(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	var1 := mock.VariableEncrypted()
+	var2 := mock.VariableEncrypted()
+	var2.KeyID = var1.KeyID
+	var3 := mock.VariableEncrypted()
+
+	for i, v := range []*structs.VariableEncrypted{var1, var2, var3} {
+		require.NoError(t, testState.VarApply(structs.MsgTypeTestSetup, uint64(10+i),
+			&structs.VarApplyStateRequest{Op: structs.VarOpSet, Var: v}))
+	}
+
+	iter, err := testState.GetVariablesByKeyID(memdb.NewWatchSet(), var1.KeyID)
+	require.NoError(t, err)
+
+	var found []string
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		found = append(found, raw.(*structs.VariableEncrypted).Path)
+	}
+	require.ElementsMatch(t, []string{var1.Path, var2.Path}, found)
+}
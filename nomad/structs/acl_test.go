@@ -0,0 +1,505 @@
+package structs
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLRole_SetHash(t *testing.T) {
+	ci.Parallel(t)
+
+	aclRole := &ACLRole{
+		Name:        "acl-role-" + uuid.Short(),
+		Description: "mocked-test-acl-role",
+		Policies: []*ACLRolePolicyLink{
+			{Name: "mocked-test-policy-1"},
+			{Name: "mocked-test-policy-2"},
+		},
+		CreateIndex: 10,
+		ModifyIndex: 10,
+	}
+	out1 := aclRole.SetHash()
+	require.NotEmpty(t, out1)
+	require.NotNil(t, aclRole.Hash)
+	require.Equal(t, out1, aclRole.Hash)
+
+	aclRole.Policies = append(aclRole.Policies, &ACLRolePolicyLink{Name: "mocked-test-policy-3"})
+	out2 := aclRole.SetHash()
+	require.NotEmpty(t, out2)
+	require.NotNil(t, aclRole.Hash)
+	require.Equal(t, out2, aclRole.Hash)
+	require.NotEqual(t, out1, out2)
+}
+
+func TestACLRole_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name             string
+		inputACLRole     *ACLRole
+		expectedErrorMsg string
+	}{
+		{
+			name:             "role name too long",
+			inputACLRole:     &ACLRole{Name: strings.Repeat("a", 129)},
+			expectedErrorMsg: "invalid name",
+		},
+		{
+			name:             "role name invalid characters",
+			inputACLRole:     &ACLRole{Name: "--#$%$^%_%%_?>"},
+			expectedErrorMsg: "invalid name",
+		},
+		{
+			name: "description too long",
+			inputACLRole: &ACLRole{
+				Name:        "acl-role",
+				Description: strings.Repeat("a", 1000),
+			},
+			expectedErrorMsg: "description longer than 256",
+		},
+		{
+			name:             "no policies",
+			inputACLRole:     &ACLRole{Name: "acl-role"},
+			expectedErrorMsg: "at least one policy should be specified",
+		},
+		{
+			name: "valid",
+			inputACLRole: &ACLRole{
+				Name:        "acl-role",
+				Description: "mocked-test-acl-role",
+				Policies: []*ACLRolePolicyLink{
+					{Name: "policy-1"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.inputACLRole.Validate()
+			if tc.expectedErrorMsg == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.expectedErrorMsg)
+			}
+		})
+	}
+}
+
+func TestACLRole_Canonicalize(t *testing.T) {
+	ci.Parallel(t)
+
+	aclRole := &ACLRole{
+		Name: "acl-role",
+		Policies: []*ACLRolePolicyLink{
+			{Name: "policy-1"},
+			{Name: "policy-2"},
+			{Name: "policy-1"},
+		},
+	}
+	aclRole.Canonicalize()
+	require.NotEmpty(t, aclRole.ID)
+	require.Equal(t, []string{"policy-1", "policy-2"}, aclRole.PolicyNames())
+
+	// Canonicalizing an existing role must not change the ID.
+	existingID := aclRole.ID
+	aclRole.Canonicalize()
+	require.Equal(t, existingID, aclRole.ID)
+}
+
+func TestACLRole_Equals(t *testing.T) {
+	ci.Parallel(t)
+
+	aclRole := &ACLRole{
+		ID:          uuid.Generate(),
+		Name:        "acl-role",
+		Description: "mocked-test-acl-role",
+		Policies: []*ACLRolePolicyLink{
+			{Name: "policy-1"},
+		},
+		CreateIndex: 10,
+		ModifyIndex: 10,
+	}
+	aclRole.SetHash()
+
+	// A copy with different indexes is considered equal.
+	copied := aclRole.Copy()
+	copied.ModifyIndex = 20
+	require.True(t, aclRole.Equals(copied))
+
+	// Modifying the policies results in a role which is no longer equal.
+	copied.Policies = append(copied.Policies, &ACLRolePolicyLink{Name: "policy-2"})
+	copied.SetHash()
+	require.False(t, aclRole.Equals(copied))
+
+	// The copy must not share policy links with the original.
+	require.Len(t, aclRole.Policies, 1)
+
+	require.True(t, (*ACLRole)(nil).Equals(nil))
+	require.False(t, aclRole.Equals(nil))
+}
+
+func TestACLToken_IsExpired(t *testing.T) {
+	ci.Parallel(t)
+
+	now := time.Now().UTC()
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Minute)
+
+	require.False(t, (&ACLToken{}).IsExpired(now))
+	require.True(t, (&ACLToken{ExpirationTime: &past}).IsExpired(now))
+	require.False(t, (&ACLToken{ExpirationTime: &future}).IsExpired(now))
+}
+
+func TestACLToken_Canonicalize(t *testing.T) {
+	ci.Parallel(t)
+
+	now := time.Now().UTC()
+
+	// A token without a TTL should not gain an expiration time.
+	token := &ACLToken{CreateTime: now}
+	token.Canonicalize()
+	require.Nil(t, token.ExpirationTime)
+
+	// A token with a TTL should have its expiration time computed.
+	token = &ACLToken{CreateTime: now, ExpirationTTL: time.Hour}
+	token.Canonicalize()
+	require.NotNil(t, token.ExpirationTime)
+	require.Equal(t, now.Add(time.Hour), *token.ExpirationTime)
+
+	// An explicit expiration time should not be overwritten.
+	expiry := now.Add(10 * time.Minute)
+	token = &ACLToken{CreateTime: now, ExpirationTTL: time.Hour, ExpirationTime: &expiry}
+	token.Canonicalize()
+	require.Equal(t, expiry, *token.ExpirationTime)
+}
+
+func TestACLToken_ValidateExpiration(t *testing.T) {
+	ci.Parallel(t)
+
+	now := time.Now().UTC()
+	minTTL, maxTTL := time.Minute, 24*time.Hour
+
+	testCases := []struct {
+		name        string
+		token       *ACLToken
+		expectedErr string
+	}{
+		{
+			name:  "no expiration",
+			token: &ACLToken{CreateTime: now},
+		},
+		{
+			name:  "within bounds",
+			token: &ACLToken{CreateTime: now, ExpirationTTL: time.Hour},
+		},
+		{
+			name:        "negative ttl",
+			token:       &ACLToken{CreateTime: now, ExpirationTTL: -time.Hour},
+			expectedErr: "cannot be negative",
+		},
+		{
+			name:        "below minimum",
+			token:       &ACLToken{CreateTime: now, ExpirationTTL: time.Second},
+			expectedErr: "cannot be less than 1m0s in the future",
+		},
+		{
+			name:        "above maximum",
+			token:       &ACLToken{CreateTime: now, ExpirationTTL: 48 * time.Hour},
+			expectedErr: "cannot be more than 24h0m0s in the future",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.token.Canonicalize()
+			err := tc.token.ValidateExpiration(minTTL, maxTTL)
+			if tc.expectedErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expectedErr)
+			}
+		})
+	}
+}
+
+func TestACLAuthMethod_SetHash(t *testing.T) {
+	ci.Parallel(t)
+
+	am := &ACLAuthMethod{
+		Name:          "foo",
+		Type:          ACLAuthMethodTypeOIDC,
+		TokenLocality: ACLAuthMethodTokenLocalityLocal,
+		MaxTokenTTL:   time.Hour,
+		Config: &ACLAuthMethodConfig{
+			OIDCDiscoveryURL:    "http://example.com",
+			OIDCClientID:        "mock",
+			AllowedRedirectURIs: []string{"http://localhost:4649/oidc/callback"},
+		},
+	}
+	out1 := am.SetHash()
+	require.NotEmpty(t, out1)
+	require.Equal(t, out1, am.Hash)
+
+	am.Config.BoundAudiences = []string{"audience"}
+	out2 := am.SetHash()
+	require.NotEmpty(t, out2)
+	require.Equal(t, out2, am.Hash)
+	require.NotEqual(t, out1, out2)
+
+	am.Default = true
+	out3 := am.SetHash()
+	require.NotEqual(t, out2, out3)
+}
+
+func TestACLAuthMethod_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	validConfig := &ACLAuthMethodConfig{
+		OIDCDiscoveryURL:    "http://example.com",
+		OIDCClientID:        "mock",
+		AllowedRedirectURIs: []string{"http://localhost:4649/oidc/callback"},
+	}
+
+	testCases := []struct {
+		name             string
+		inputAuthMethod  *ACLAuthMethod
+		expectedErrorMsg string
+	}{
+		{
+			name: "invalid name",
+			inputAuthMethod: &ACLAuthMethod{
+				Name:          "--#$%$^%_%%_?>",
+				Type:          ACLAuthMethodTypeOIDC,
+				TokenLocality: ACLAuthMethodTokenLocalityLocal,
+				MaxTokenTTL:   time.Hour,
+				Config:        validConfig,
+			},
+			expectedErrorMsg: "invalid name",
+		},
+		{
+			name: "invalid type",
+			inputAuthMethod: &ACLAuthMethod{
+				Name:          "foo",
+				Type:          "LDAP",
+				TokenLocality: ACLAuthMethodTokenLocalityLocal,
+				MaxTokenTTL:   time.Hour,
+				Config:        validConfig,
+			},
+			expectedErrorMsg: "invalid type",
+		},
+		{
+			name: "invalid token locality",
+			inputAuthMethod: &ACLAuthMethod{
+				Name:          "foo",
+				Type:          ACLAuthMethodTypeOIDC,
+				TokenLocality: "regional",
+				MaxTokenTTL:   time.Hour,
+				Config:        validConfig,
+			},
+			expectedErrorMsg: "invalid token locality",
+		},
+		{
+			name: "missing max token TTL",
+			inputAuthMethod: &ACLAuthMethod{
+				Name:          "foo",
+				Type:          ACLAuthMethodTypeOIDC,
+				TokenLocality: ACLAuthMethodTokenLocalityLocal,
+				Config:        validConfig,
+			},
+			expectedErrorMsg: "max token TTL must be greater than zero",
+		},
+		{
+			name: "missing config",
+			inputAuthMethod: &ACLAuthMethod{
+				Name:          "foo",
+				Type:          ACLAuthMethodTypeOIDC,
+				TokenLocality: ACLAuthMethodTokenLocalityLocal,
+				MaxTokenTTL:   time.Hour,
+			},
+			expectedErrorMsg: "missing config",
+		},
+		{
+			name: "missing redirect URIs",
+			inputAuthMethod: &ACLAuthMethod{
+				Name:          "foo",
+				Type:          ACLAuthMethodTypeOIDC,
+				TokenLocality: ACLAuthMethodTokenLocalityLocal,
+				MaxTokenTTL:   time.Hour,
+				Config: &ACLAuthMethodConfig{
+					OIDCDiscoveryURL: "http://example.com",
+					OIDCClientID:     "mock",
+				},
+			},
+			expectedErrorMsg: "at least one allowed redirect URI should be specified",
+		},
+		{
+			name: "valid",
+			inputAuthMethod: &ACLAuthMethod{
+				Name:          "foo",
+				Type:          ACLAuthMethodTypeOIDC,
+				TokenLocality: ACLAuthMethodTokenLocalityGlobal,
+				MaxTokenTTL:   time.Hour,
+				Config:        validConfig,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.inputAuthMethod.Validate()
+			if tc.expectedErrorMsg == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.expectedErrorMsg)
+			}
+		})
+	}
+}
+
+func TestACLAuthMethod_Canonicalize(t *testing.T) {
+	ci.Parallel(t)
+
+	am := &ACLAuthMethod{Name: "foo"}
+	am.Canonicalize()
+	require.Equal(t, ACLAuthMethodTokenLocalityLocal, am.TokenLocality)
+	require.False(t, am.CreateTime.IsZero())
+	require.Equal(t, am.CreateTime, am.ModifyTime)
+
+	// Canonicalizing an existing method must not change the create time.
+	createTime := am.CreateTime
+	am.Canonicalize()
+	require.Equal(t, createTime, am.CreateTime)
+}
+
+func TestACLBindingRule_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name             string
+		inputRule        *ACLBindingRule
+		expectedErrorMsg string
+	}{
+		{
+			name:             "missing auth method",
+			inputRule:        &ACLBindingRule{BindType: ACLBindingRuleBindTypeManagement},
+			expectedErrorMsg: "auth method is missing",
+		},
+		{
+			name: "description too long",
+			inputRule: &ACLBindingRule{
+				AuthMethod:  "foo",
+				Description: strings.Repeat("a", 1000),
+				BindType:    ACLBindingRuleBindTypeManagement,
+			},
+			expectedErrorMsg: "description longer than 256",
+		},
+		{
+			name:             "missing bind type",
+			inputRule:        &ACLBindingRule{AuthMethod: "foo"},
+			expectedErrorMsg: "bind type is missing",
+		},
+		{
+			name: "unsupported bind type",
+			inputRule: &ACLBindingRule{
+				AuthMethod: "foo",
+				BindType:   "service",
+				BindName:   "foo",
+			},
+			expectedErrorMsg: "unsupported bind type",
+		},
+		{
+			name: "role missing bind name",
+			inputRule: &ACLBindingRule{
+				AuthMethod: "foo",
+				BindType:   ACLBindingRuleBindTypeRole,
+			},
+			expectedErrorMsg: "bind name is missing",
+		},
+		{
+			name: "management with bind name",
+			inputRule: &ACLBindingRule{
+				AuthMethod: "foo",
+				BindType:   ACLBindingRuleBindTypeManagement,
+				BindName:   "foo",
+			},
+			expectedErrorMsg: "bind name should be empty",
+		},
+		{
+			name: "valid",
+			inputRule: &ACLBindingRule{
+				AuthMethod: "foo",
+				Selector:   "engineering in list.roles",
+				BindType:   ACLBindingRuleBindTypePolicy,
+				BindName:   "eng-ro",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.inputRule.Validate()
+			if tc.expectedErrorMsg == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.expectedErrorMsg)
+			}
+		})
+	}
+}
+
+func TestACLBindingRule_Canonicalize(t *testing.T) {
+	ci.Parallel(t)
+
+	rule := &ACLBindingRule{AuthMethod: "foo"}
+	rule.Canonicalize()
+	require.NotEmpty(t, rule.ID)
+	require.False(t, rule.CreateTime.IsZero())
+
+	// Canonicalizing an existing rule must not change the ID.
+	existingID := rule.ID
+	rule.Canonicalize()
+	require.Equal(t, existingID, rule.ID)
+}
+
+func TestACLBindingRule_SetHash(t *testing.T) {
+	ci.Parallel(t)
+
+	rule := &ACLBindingRule{
+		ID:         uuid.Generate(),
+		AuthMethod: "foo",
+		BindType:   ACLBindingRuleBindTypeRole,
+		BindName:   "eng-ro",
+	}
+	out1 := rule.SetHash()
+	require.NotEmpty(t, out1)
+	require.Equal(t, out1, rule.Hash)
+
+	rule.Selector = "engineering in list.roles"
+	out2 := rule.SetHash()
+	require.NotEqual(t, out1, out2)
+	require.Equal(t, out2, rule.Hash)
+}
+
+func TestACLOIDCAuthURLRequest_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	req := &ACLOIDCAuthURLRequest{}
+	err := req.Validate()
+	require.ErrorContains(t, err, "missing auth method name")
+	require.ErrorContains(t, err, "missing client nonce")
+	require.ErrorContains(t, err, "missing redirect URI")
+
+	req = &ACLOIDCAuthURLRequest{
+		AuthMethodName: "foo",
+		ClientNonce:    "nonce",
+		RedirectURI:    "http://localhost:4649/oidc/callback",
+	}
+	require.NoError(t, req.Validate())
+}
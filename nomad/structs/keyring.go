@@ -0,0 +1,130 @@
+package structs
+
+import (
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/uuid"
+)
+
+const (
+	// KeyringGetRootKeyRPCMethod is the RPC method used by servers to fetch
+	// the key material of a root key from a peer. It is only ever called by
+	// servers when replicating the keyring.
+	//
+	// Args: KeyringGetRootKeyRequest
+	// Reply: KeyringGetRootKeyResponse
+	KeyringGetRootKeyRPCMethod = "Keyring.Get"
+
+	// KeyringListPublicRPCMethod is the RPC method used to list the public
+	// keys used to verify workload identities. It does not require an ACL
+	// token, as the keys are public.
+	//
+	// Args: GenericRequest
+	// Reply: KeyringListPublicResponse
+	KeyringListPublicRPCMethod = "Keyring.ListPublic"
+)
+
+// EncryptionAlgorithm is the algorithm used to encrypt data using a root key.
+type EncryptionAlgorithm string
+
+const (
+	EncryptionAlgorithmAES256GCM EncryptionAlgorithm = "aes256-gcm"
+)
+
+// RootKey is used to encrypt and decrypt variables. The key material is never
+// stored in Raft; only the metadata is, and the key material is held within
+// the keystore of each server.
+type RootKey struct {
+	Meta *RootKeyMeta
+	Key  []byte
+}
+
+// NewRootKey returns a new root key and its metadata for the given algorithm.
+func NewRootKey(algorithm EncryptionAlgorithm) (*RootKey, error) {
+	meta := NewRootKeyMeta()
+	meta.Algorithm = algorithm
+
+	rootKey := &RootKey{Meta: meta}
+
+	switch algorithm {
+	case EncryptionAlgorithmAES256GCM:
+		rootKey.Key = make([]byte, 32)
+		if _, err := rand.Read(rootKey.Key); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported encryption algorithm %q", algorithm)
+	}
+
+	return rootKey, nil
+}
+
+// RootKeyMeta is the metadata of a root key which is stored in Raft.
+type RootKeyMeta struct {
+	KeyID      string
+	Algorithm  EncryptionAlgorithm
+	CreateTime int64
+
+	// Active indicates the key is the one used to encrypt new variables.
+	// There is only ever a single active key.
+	Active bool
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// NewRootKeyMeta returns a new RootKeyMeta with default values.
+func NewRootKeyMeta() *RootKeyMeta {
+	return &RootKeyMeta{
+		KeyID:      uuid.Generate(),
+		Algorithm:  EncryptionAlgorithmAES256GCM,
+		CreateTime: time.Now().UTC().UnixNano(),
+	}
+}
+
+// Copy returns a copy of the root key metadata. It handles nil objects.
+func (rkm *RootKeyMeta) Copy() *RootKeyMeta {
+	if rkm == nil {
+		return nil
+	}
+	out := *rkm
+	return &out
+}
+
+// Validate checks the root key metadata is well formed.
+func (rkm *RootKeyMeta) Validate() error {
+	if rkm == nil {
+		return fmt.Errorf("root key metadata is required")
+	}
+	if !helper.IsUUID(rkm.KeyID) {
+		return fmt.Errorf("root key UUID is required")
+	}
+	if rkm.Algorithm == "" {
+		return fmt.Errorf("root key algorithm is required")
+	}
+	return nil
+}
+
+// KeyringUpdateRootKeyMetaRequest is the request object written to the Raft
+// log when a root key is added to the keyring.
+type KeyringUpdateRootKeyMetaRequest struct {
+	RootKeyMeta *RootKeyMeta
+	WriteRequest
+}
+
+// KeyringGetRootKeyRequest is the request object used by a server to fetch the
+// key material of a root key from its peers.
+type KeyringGetRootKeyRequest struct {
+	KeyID string
+	QueryOptions
+}
+
+// KeyringGetRootKeyResponse is the response object when fetching a root key.
+// Key will be nil if the server does not hold the key material.
+type KeyringGetRootKeyResponse struct {
+	Key *RootKey
+	QueryMeta
+}
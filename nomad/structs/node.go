@@ -352,3 +352,57 @@ func (di *DriverInfo) HealthCheckEquals(other *DriverInfo) bool {
 
 	return true
 }
+
+const (
+	// NodeUtilizationUpdateInterval is the minimum interval at which the
+	// utilization reported by a client with its heartbeats is persisted.
+	// Heartbeats are frequent, so committing every report through raft would
+	// be wasteful.
+	NodeUtilizationUpdateInterval = 1 * time.Minute
+
+	// NodeUtilizationStaleAfter is the age after which the utilization of a
+	// node is no longer used for scoring.
+	NodeUtilizationStaleAfter = 5 * time.Minute
+)
+
+// NodeUtilization is the recent resource usage of a node, as measured by the
+// client. Unlike the resources reserved by allocations, it accounts for how
+// much of their reservations the tasks actually use as well as processes
+// running on the host outside of Nomad.
+type NodeUtilization struct {
+	// CpuUsedMHz is the CPU consumed on the node
+	CpuUsedMHz int64
+
+	// MemoryUsedMB is the memory used on the node
+	MemoryUsedMB int64
+
+	// UpdatedAt is the unix timestamp, in seconds, at which the server
+	// received the utilization
+	UpdatedAt int64
+}
+
+func (u *NodeUtilization) Copy() *NodeUtilization {
+	if u == nil {
+		return nil
+	}
+	nu := new(NodeUtilization)
+	*nu = *u
+	return nu
+}
+
+// ShouldUpdate returns whether new utilization received at now should
+// replace u.
+func (u *NodeUtilization) ShouldUpdate(now time.Time) bool {
+	if u == nil {
+		return true
+	}
+	return now.Sub(time.Unix(u.UpdatedAt, 0)) >= NodeUtilizationUpdateInterval
+}
+
+// IsStale returns whether u is too old to be used for scoring at now.
+func (u *NodeUtilization) IsStale(now time.Time) bool {
+	if u == nil {
+		return true
+	}
+	return now.Sub(time.Unix(u.UpdatedAt, 0)) > NodeUtilizationStaleAfter
+}
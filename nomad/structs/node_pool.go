@@ -0,0 +1,271 @@
+package structs
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper"
+)
+
+const (
+	// NodePoolAll is a built-in node pool which contains all the nodes of
+	// the cluster. Jobs targeting it may be placed on any node, but nodes
+	// cannot be registered in it.
+	NodePoolAll = "all"
+
+	// NodePoolDefault is a built-in node pool used by nodes and jobs which
+	// do not specify a node pool.
+	NodePoolDefault = "default"
+
+	// maxNodePoolDescriptionLength limits a node pool description length.
+	maxNodePoolDescriptionLength = 256
+)
+
+var (
+	// validNodePoolName is used to validate a node pool name.
+	validNodePoolName = regexp.MustCompile("^[a-zA-Z0-9-_]{1,128}$")
+)
+
+// NodePool partitions the nodes of a cluster. Nodes belong to exactly one
+// node pool, and jobs are only placed on the nodes of the node pool they
+// target.
+type NodePool struct {
+	// Name is the unique name of the node pool.
+	Name string
+
+	// Description is a human readable description of the node pool.
+	Description string
+
+	// Meta is the set of metadata key/value pairs attached to the node pool.
+	Meta map[string]string
+
+	// SchedulerConfiguration overrides the cluster wide scheduler
+	// configuration when placing allocations on the nodes of the pool.
+	SchedulerConfiguration *NodePoolSchedulerConfiguration
+
+	// Raft Indexes
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// NodePoolSchedulerConfiguration is the subset of the scheduler
+// configuration which can be overridden by a node pool. Unset fields inherit
+// the cluster wide value.
+type NodePoolSchedulerConfiguration struct {
+	// SchedulerAlgorithm overrides the scheduling algorithm used for jobs
+	// targeting the node pool.
+	SchedulerAlgorithm SchedulerAlgorithm
+
+	// MemoryOversubscriptionEnabled overrides whether memory
+	// oversubscription is enabled for jobs targeting the node pool.
+	MemoryOversubscriptionEnabled *bool
+}
+
+// BuiltinNodePools returns the node pools which always exist in the cluster.
+func BuiltinNodePools() []*NodePool {
+	return []*NodePool{
+		{
+			Name:        NodePoolAll,
+			Description: "Node pool with all nodes in the cluster.",
+		},
+		{
+			Name:        NodePoolDefault,
+			Description: "Default node pool.",
+		},
+	}
+}
+
+// IsBuiltIn returns whether the node pool is one of the built-in node pools,
+// which cannot be deleted.
+func (n *NodePool) IsBuiltIn() bool {
+	switch n.Name {
+	case NodePoolAll, NodePoolDefault:
+		return true
+	default:
+		return false
+	}
+}
+
+// Validate returns an error if the node pool is invalid.
+func (n *NodePool) Validate() error {
+	var mErr multierror.Error
+
+	if !validNodePoolName.MatchString(n.Name) {
+		mErr.Errors = append(mErr.Errors,
+			fmt.Errorf("invalid name %q. Must match regex %s", n.Name, validNodePoolName))
+	}
+	if len(n.Description) > maxNodePoolDescriptionLength {
+		mErr.Errors = append(mErr.Errors,
+			fmt.Errorf("description longer than %d", maxNodePoolDescriptionLength))
+	}
+	if n.SchedulerConfiguration != nil {
+		switch n.SchedulerConfiguration.SchedulerAlgorithm {
+		case "", SchedulerAlgorithmBinpack, SchedulerAlgorithmSpread:
+		default:
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid scheduler algorithm: %v",
+				n.SchedulerConfiguration.SchedulerAlgorithm))
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// Copy returns a deep copy of the node pool. It handles nil objects.
+func (n *NodePool) Copy() *NodePool {
+	if n == nil {
+		return nil
+	}
+
+	nc := new(NodePool)
+	*nc = *n
+	nc.Meta = helper.CopyMapStringString(n.Meta)
+	if n.SchedulerConfiguration != nil {
+		sc := new(NodePoolSchedulerConfiguration)
+		*sc = *n.SchedulerConfiguration
+		if n.SchedulerConfiguration.MemoryOversubscriptionEnabled != nil {
+			sc.MemoryOversubscriptionEnabled = helper.BoolToPtr(*n.SchedulerConfiguration.MemoryOversubscriptionEnabled)
+		}
+		nc.SchedulerConfiguration = sc
+	}
+	return nc
+}
+
+// WithNodePool returns a copy of the scheduler configuration with the
+// overrides of the node pool applied. The receiver is returned unmodified if
+// the node pool does not override the scheduler configuration.
+func (s *SchedulerConfiguration) WithNodePool(pool *NodePool) *SchedulerConfiguration {
+	if pool == nil || pool.SchedulerConfiguration == nil {
+		return s
+	}
+
+	var out SchedulerConfiguration
+	if s != nil {
+		out = *s
+	}
+
+	if alg := pool.SchedulerConfiguration.SchedulerAlgorithm; alg != "" {
+		out.SchedulerAlgorithm = alg
+	}
+	if enabled := pool.SchedulerConfiguration.MemoryOversubscriptionEnabled; enabled != nil {
+		out.MemoryOversubscriptionEnabled = *enabled
+	}
+	return &out
+}
+
+// NamespaceNodePoolConfiguration restricts the node pools the jobs of a
+// namespace may target.
+type NamespaceNodePoolConfiguration struct {
+	// Default is the node pool used by jobs of the namespace which do not
+	// specify a node pool. It is always allowed.
+	Default string
+
+	// Allowed lists the node pools the jobs of the namespace may target. All
+	// node pools are allowed if empty. It cannot be set along with Denied.
+	Allowed []string
+
+	// Denied lists the node pools the jobs of the namespace may not target.
+	// It cannot be set along with Allowed.
+	Denied []string
+}
+
+// Validate returns an error if the node pool configuration is invalid.
+func (c *NamespaceNodePoolConfiguration) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	var mErr multierror.Error
+	if c.Default != "" && !validNodePoolName.MatchString(c.Default) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid default node pool %q", c.Default))
+	}
+	if len(c.Allowed) > 0 && len(c.Denied) > 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("allowed and denied node pools are mutually exclusive"))
+	}
+	defaultPool := c.Default
+	if defaultPool == "" {
+		defaultPool = NodePoolDefault
+	}
+	if helper.SliceStringContains(c.Denied, defaultPool) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("default node pool %q cannot be denied", defaultPool))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// Copy returns a deep copy of the node pool configuration. It handles nil
+// objects.
+func (c *NamespaceNodePoolConfiguration) Copy() *NamespaceNodePoolConfiguration {
+	if c == nil {
+		return nil
+	}
+	nc := new(NamespaceNodePoolConfiguration)
+	*nc = *c
+	nc.Allowed = helper.CopySliceString(c.Allowed)
+	nc.Denied = helper.CopySliceString(c.Denied)
+	return nc
+}
+
+// DefaultNodePool returns the node pool used by jobs of the namespace which
+// do not specify a node pool.
+func (n *Namespace) DefaultNodePool() string {
+	if n.NodePoolConfiguration == nil || n.NodePoolConfiguration.Default == "" {
+		return NodePoolDefault
+	}
+	return n.NodePoolConfiguration.Default
+}
+
+// AllowsNodePool returns whether the jobs of the namespace may target the
+// node pool.
+func (n *Namespace) AllowsNodePool(pool string) bool {
+	if pool == n.DefaultNodePool() || n.NodePoolConfiguration == nil {
+		return true
+	}
+
+	c := n.NodePoolConfiguration
+	if len(c.Allowed) > 0 {
+		return helper.SliceStringContains(c.Allowed, pool)
+	}
+	return !helper.SliceStringContains(c.Denied, pool)
+}
+
+// NodePoolUpsertRequest is used to create or update a set of node pools.
+type NodePoolUpsertRequest struct {
+	NodePools []*NodePool
+	WriteRequest
+}
+
+// NodePoolDeleteRequest is used to delete a set of node pools.
+type NodePoolDeleteRequest struct {
+	Names []string
+	WriteRequest
+}
+
+// NodePoolListRequest is used to list the node pools.
+type NodePoolListRequest struct {
+	QueryOptions
+}
+
+// NodePoolListResponse is the response to a NodePoolListRequest.
+type NodePoolListResponse struct {
+	NodePools []*NodePool
+	QueryMeta
+}
+
+// NodePoolSpecificRequest is used to query a specific node pool.
+type NodePoolSpecificRequest struct {
+	Name string
+	QueryOptions
+}
+
+// NodePoolSingleResponse is the response to a NodePoolSpecificRequest.
+type NodePoolSingleResponse struct {
+	NodePool *NodePool
+	QueryMeta
+}
+
+// NodePoolNodesRequest is used to list the nodes of a node pool.
+type NodePoolNodesRequest struct {
+	Name   string
+	Fields *NodeStubFields
+	QueryOptions
+}
@@ -0,0 +1,193 @@
+package structs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodePool_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name   string
+		pool   *NodePool
+		expErr string
+	}{
+		{
+			name: "valid",
+			pool: &NodePool{
+				Name:        "gpu-nodes_1",
+				Description: "nodes with gpus",
+				SchedulerConfiguration: &NodePoolSchedulerConfiguration{
+					SchedulerAlgorithm: SchedulerAlgorithmSpread,
+				},
+			},
+		},
+		{
+			name:   "invalid name",
+			pool:   &NodePool{Name: "gpu nodes"},
+			expErr: "invalid name",
+		},
+		{
+			name:   "empty name",
+			pool:   &NodePool{},
+			expErr: "invalid name",
+		},
+		{
+			name: "description too long",
+			pool: &NodePool{
+				Name:        "gpu",
+				Description: strings.Repeat("a", maxNodePoolDescriptionLength+1),
+			},
+			expErr: "description longer than",
+		},
+		{
+			name: "invalid scheduler algorithm",
+			pool: &NodePool{
+				Name: "gpu",
+				SchedulerConfiguration: &NodePoolSchedulerConfiguration{
+					SchedulerAlgorithm: "round-robin",
+				},
+			},
+			expErr: "invalid scheduler algorithm",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.pool.Validate()
+			if tc.expErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.expErr)
+			}
+		})
+	}
+}
+
+func TestNodePool_Copy(t *testing.T) {
+	ci.Parallel(t)
+
+	pool := &NodePool{
+		Name: "gpu",
+		Meta: map[string]string{"team": "ml"},
+		SchedulerConfiguration: &NodePoolSchedulerConfiguration{
+			MemoryOversubscriptionEnabled: helper.BoolToPtr(true),
+		},
+	}
+	poolCopy := pool.Copy()
+	require.Equal(t, pool, poolCopy)
+
+	poolCopy.Meta["team"] = "web"
+	*poolCopy.SchedulerConfiguration.MemoryOversubscriptionEnabled = false
+	require.Equal(t, "ml", pool.Meta["team"])
+	require.True(t, *pool.SchedulerConfiguration.MemoryOversubscriptionEnabled)
+}
+
+func TestSchedulerConfiguration_WithNodePool(t *testing.T) {
+	ci.Parallel(t)
+
+	schedConfig := &SchedulerConfiguration{
+		SchedulerAlgorithm:            SchedulerAlgorithmBinpack,
+		MemoryOversubscriptionEnabled: false,
+	}
+
+	// Node pools without overrides use the cluster configuration.
+	require.Same(t, schedConfig, schedConfig.WithNodePool(&NodePool{Name: "gpu"}))
+	require.Same(t, schedConfig, schedConfig.WithNodePool(nil))
+
+	out := schedConfig.WithNodePool(&NodePool{
+		Name: "gpu",
+		SchedulerConfiguration: &NodePoolSchedulerConfiguration{
+			SchedulerAlgorithm:            SchedulerAlgorithmSpread,
+			MemoryOversubscriptionEnabled: helper.BoolToPtr(true),
+		},
+	})
+	require.Equal(t, SchedulerAlgorithmSpread, out.SchedulerAlgorithm)
+	require.True(t, out.MemoryOversubscriptionEnabled)
+
+	// The cluster configuration must not be modified.
+	require.Equal(t, SchedulerAlgorithmBinpack, schedConfig.SchedulerAlgorithm)
+	require.False(t, schedConfig.MemoryOversubscriptionEnabled)
+}
+
+func TestNamespaceNodePoolConfiguration_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name   string
+		config *NamespaceNodePoolConfiguration
+		expErr string
+	}{
+		{
+			name: "nil",
+		},
+		{
+			name: "allowed",
+			config: &NamespaceNodePoolConfiguration{
+				Default: "gpu",
+				Allowed: []string{"web"},
+			},
+		},
+		{
+			name: "allowed and denied",
+			config: &NamespaceNodePoolConfiguration{
+				Allowed: []string{"web"},
+				Denied:  []string{"gpu"},
+			},
+			expErr: "mutually exclusive",
+		},
+		{
+			name: "denied default",
+			config: &NamespaceNodePoolConfiguration{
+				Denied: []string{NodePoolDefault},
+			},
+			expErr: "cannot be denied",
+		},
+		{
+			name: "invalid default",
+			config: &NamespaceNodePoolConfiguration{
+				Default: "gpu nodes",
+			},
+			expErr: "invalid default node pool",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.config.Validate()
+			if tc.expErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.expErr)
+			}
+		})
+	}
+}
+
+func TestNamespace_AllowsNodePool(t *testing.T) {
+	ci.Parallel(t)
+
+	ns := &Namespace{Name: "default"}
+	require.Equal(t, NodePoolDefault, ns.DefaultNodePool())
+	require.True(t, ns.AllowsNodePool("gpu"))
+
+	ns.NodePoolConfiguration = &NamespaceNodePoolConfiguration{
+		Default: "web",
+		Allowed: []string{"gpu"},
+	}
+	require.Equal(t, "web", ns.DefaultNodePool())
+	require.True(t, ns.AllowsNodePool("web"))
+	require.True(t, ns.AllowsNodePool("gpu"))
+	require.False(t, ns.AllowsNodePool(NodePoolDefault))
+
+	ns.NodePoolConfiguration = &NamespaceNodePoolConfiguration{
+		Denied: []string{"gpu"},
+	}
+	require.True(t, ns.AllowsNodePool("web"))
+	require.False(t, ns.AllowsNodePool("gpu"))
+}
@@ -2,6 +2,7 @@ package structs
 
 import (
 	"testing"
+	"time"
 
 	"github.com/hashicorp/nomad/ci"
 	"github.com/stretchr/testify/require"
@@ -63,3 +64,25 @@ func TestDriverInfoEquals(t *testing.T) {
 		require.Equal(testCase.expected, first.HealthCheckEquals(second), testCase.errorMsg)
 	}
 }
+
+func TestNodeUtilization_ShouldUpdate(t *testing.T) {
+	ci.Parallel(t)
+
+	now := time.Now()
+
+	var missing *NodeUtilization
+	require.True(t, missing.ShouldUpdate(now))
+	require.True(t, missing.IsStale(now))
+
+	recent := &NodeUtilization{UpdatedAt: now.Add(-10 * time.Second).Unix()}
+	require.False(t, recent.ShouldUpdate(now))
+	require.False(t, recent.IsStale(now))
+
+	old := &NodeUtilization{UpdatedAt: now.Add(-2 * time.Minute).Unix()}
+	require.True(t, old.ShouldUpdate(now))
+	require.False(t, old.IsStale(now))
+
+	stale := &NodeUtilization{UpdatedAt: now.Add(-time.Hour).Unix()}
+	require.True(t, stale.ShouldUpdate(now))
+	require.True(t, stale.IsStale(now))
+}
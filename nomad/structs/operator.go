@@ -152,6 +152,15 @@ type SchedulerConfiguration struct {
 	// MemoryOversubscriptionEnabled specifies whether memory oversubscription is enabled
 	MemoryOversubscriptionEnabled bool `hcl:"memory_oversubscription_enabled"`
 
+	// UtilizationScoringEnabled specifies whether nodes are also scored on
+	// the CPU and memory utilization reported by their clients, in addition
+	// to the resources reserved by their allocations
+	UtilizationScoringEnabled bool `hcl:"utilization_scoring_enabled"`
+
+	// RebalanceConfig specifies whether and how the leader periodically
+	// migrates running allocations to rebalance the cluster.
+	RebalanceConfig RebalanceConfig `hcl:"rebalance_config"`
+
 	// RejectJobRegistration disables new job registrations except with a
 	// management ACL token
 	RejectJobRegistration bool `hcl:"reject_job_registration"`
@@ -186,6 +195,10 @@ func (s *SchedulerConfiguration) Validate() error {
 		return fmt.Errorf("invalid scheduler algorithm: %v", s.SchedulerAlgorithm)
 	}
 
+	if err := s.RebalanceConfig.Validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -222,6 +235,100 @@ type PreemptionConfig struct {
 	ServiceSchedulerEnabled bool `hcl:"service_scheduler_enabled"`
 }
 
+const (
+	// DefaultRebalanceMaxMigrations is the maximum number of allocations
+	// migrated by a single run of the rebalancer when not configured.
+	DefaultRebalanceMaxMigrations = 10
+
+	// DefaultRebalanceDensityThreshold is the percentage by which the
+	// allocated resources of a node must exceed the cluster average for the
+	// node to be considered overly dense when not configured.
+	DefaultRebalanceDensityThreshold = 25
+)
+
+// RebalanceConfig specifies how running allocations are migrated to correct
+// skewed spreads, overly dense nodes and violated affinities.
+type RebalanceConfig struct {
+	// Enabled specifies if the leader periodically rebalances the cluster.
+	// Rebalancing reports can be requested even when disabled.
+	Enabled bool `hcl:"enabled"`
+
+	// MaxMigrations is the maximum number of allocations migrated by a
+	// single rebalancing run.
+	MaxMigrations int `hcl:"max_migrations"`
+
+	// DensityThreshold is the percentage by which the allocated resources of
+	// a node must exceed the cluster average for its allocations to be
+	// migrated. Only used with the spread scheduler algorithm.
+	DensityThreshold int `hcl:"density_threshold"`
+}
+
+// EffectiveMaxMigrations returns the maximum number of allocations migrated by
+// a single rebalancing run, falling back to the default if not set.
+func (c *RebalanceConfig) EffectiveMaxMigrations() int {
+	if c == nil || c.MaxMigrations == 0 {
+		return DefaultRebalanceMaxMigrations
+	}
+	return c.MaxMigrations
+}
+
+// EffectiveDensityThreshold returns the density threshold as a fraction,
+// falling back to the default if not set.
+func (c *RebalanceConfig) EffectiveDensityThreshold() float64 {
+	if c == nil || c.DensityThreshold == 0 {
+		return DefaultRebalanceDensityThreshold / 100.0
+	}
+	return float64(c.DensityThreshold) / 100.0
+}
+
+func (c *RebalanceConfig) Validate() error {
+	if c.MaxMigrations < 0 {
+		return fmt.Errorf("rebalance max migrations must be positive: %d", c.MaxMigrations)
+	}
+	if c.DensityThreshold < 0 || c.DensityThreshold > 100 {
+		return fmt.Errorf("rebalance density threshold must be between 0 and 100: %d", c.DensityThreshold)
+	}
+	return nil
+}
+
+// SchedulerRebalanceRequest is used by the Operator endpoint to find the
+// allocations which should be migrated to rebalance the cluster.
+type SchedulerRebalanceRequest struct {
+	// DryRun only reports the migrations without applying them.
+	DryRun bool
+
+	WriteRequest
+}
+
+// SchedulerRebalanceResponse is the response of a rebalancing request.
+type SchedulerRebalanceResponse struct {
+	// Migrations are the allocations selected for migration.
+	Migrations []*RebalanceMigration
+
+	// EvalIDs are the evaluations created to migrate the allocations. It is
+	// empty for dry runs.
+	EvalIDs []string
+
+	WriteMeta
+}
+
+const (
+	RebalanceReasonSpread   = "spread"
+	RebalanceReasonDensity  = "density"
+	RebalanceReasonAffinity = "affinity"
+)
+
+// RebalanceMigration is an allocation selected for migration by the
+// rebalancer, along with the reason it was selected.
+type RebalanceMigration struct {
+	AllocID   string
+	Namespace string
+	JobID     string
+	TaskGroup string
+	NodeID    string
+	Reason    string
+}
+
 // SchedulerSetConfigRequest is used by the Operator endpoint to update the
 // current Scheduler configuration of the cluster.
 type SchedulerSetConfigRequest struct {
@@ -0,0 +1,346 @@
+package structs
+
+import (
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/hashicorp/go-multierror"
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	// maxQuotaSpecDescriptionLength limits a quota specification description
+	// length.
+	maxQuotaSpecDescriptionLength = 256
+
+	// QuotaDimensionCPU, QuotaDimensionMemory and QuotaDimensionMemoryMax
+	// are the resources which can be limited by a quota specification.
+	QuotaDimensionCPU       = "cpu"
+	QuotaDimensionMemory    = "memory"
+	QuotaDimensionMemoryMax = "memory_max"
+)
+
+var (
+	// validQuotaSpecName is used to validate a quota specification name.
+	validQuotaSpecName = regexp.MustCompile("^[a-zA-Z0-9-]{1,128}$")
+)
+
+// QuotaSpec specifies the amount of resources the allocations of the
+// namespaces attached to it may use in each region.
+type QuotaSpec struct {
+	// Name is the unique name of the quota specification.
+	Name string
+
+	// Description is a human readable description of the quota
+	// specification.
+	Description string
+
+	// Limits is the set of quota limits, at most one per region.
+	Limits []*QuotaLimit
+
+	// Hash is the hash of the quota specification, used to detect
+	// changes during replication.
+	Hash []byte
+
+	// Raft Indexes
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// QuotaLimit limits the resources which can be used in a region.
+type QuotaLimit struct {
+	// Region is the region the limit applies to.
+	Region string
+
+	// RegionLimit is the quota limit for the region. Only the CPU, MemoryMB
+	// and MemoryMaxMB fields are used. A value of zero means the resource is
+	// unlimited while a negative value disallows any use of it.
+	RegionLimit *Resources
+
+	// Hash is the hash of the limit and identifies it within quota usages.
+	Hash []byte
+}
+
+// QuotaUsage is the usage of a quota specification in a region.
+type QuotaUsage struct {
+	// Name is the name of the quota specification.
+	Name string
+
+	// Used is the resources used against each limit of the region, keyed by
+	// the base64 encoded hash of the limit.
+	Used map[string]*QuotaLimit
+
+	// Raft Indexes
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// Validate returns an error if the quota specification is invalid.
+func (q *QuotaSpec) Validate() error {
+	var mErr multierror.Error
+
+	if !validQuotaSpecName.MatchString(q.Name) {
+		mErr.Errors = append(mErr.Errors,
+			fmt.Errorf("invalid name %q. Must match regex %s", q.Name, validQuotaSpecName))
+	}
+	if len(q.Description) > maxQuotaSpecDescriptionLength {
+		mErr.Errors = append(mErr.Errors,
+			fmt.Errorf("description longer than %d", maxQuotaSpecDescriptionLength))
+	}
+
+	regions := make(map[string]struct{}, len(q.Limits))
+	for i, limit := range q.Limits {
+		if limit == nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("limit %d is nil", i+1))
+			continue
+		}
+		if _, ok := regions[limit.Region]; ok {
+			mErr.Errors = append(mErr.Errors,
+				fmt.Errorf("duplicate limit for region %q", limit.Region))
+		}
+		regions[limit.Region] = struct{}{}
+
+		if err := limit.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, multierror.Prefix(err, fmt.Sprintf("limit %d:", i+1)))
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// Validate returns an error if the quota limit is invalid.
+func (l *QuotaLimit) Validate() error {
+	var mErr multierror.Error
+
+	if l.Region == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("missing region"))
+	}
+
+	r := l.RegionLimit
+	if r == nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("missing region_limit"))
+		return mErr.ErrorOrNil()
+	}
+	if r.Cores != 0 || r.DiskMB != 0 || r.IOPS != 0 ||
+		len(r.Networks) != 0 || len(r.Devices) != 0 || r.NUMA != nil {
+		mErr.Errors = append(mErr.Errors,
+			fmt.Errorf("only the cpu, memory and memory_max resources can be limited"))
+	}
+	if r.MemoryMaxMB > 0 && r.MemoryMB > 0 && r.MemoryMaxMB < r.MemoryMB {
+		mErr.Errors = append(mErr.Errors,
+			fmt.Errorf("memory_max (%d) cannot be lower than memory (%d)", r.MemoryMaxMB, r.MemoryMB))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// SetHash computes and sets the hash of the quota specification and of each
+// of its limits.
+func (q *QuotaSpec) SetHash() []byte {
+	// Initialize a 256bit Blake2 hash (32 bytes)
+	hash, err := blake2b.New256(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	_, _ = hash.Write([]byte(q.Name))
+	_, _ = hash.Write([]byte(q.Description))
+	for _, limit := range q.Limits {
+		_, _ = hash.Write(limit.SetHash())
+	}
+
+	q.Hash = hash.Sum(nil)
+	return q.Hash
+}
+
+// SetHash computes and sets the hash of the quota limit.
+func (l *QuotaLimit) SetHash() []byte {
+	// Initialize a 256bit Blake2 hash (32 bytes)
+	hash, err := blake2b.New256(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	_, _ = hash.Write([]byte(l.Region))
+	if l.RegionLimit != nil {
+		_, _ = hash.Write([]byte(strconv.Itoa(l.RegionLimit.CPU)))
+		_, _ = hash.Write([]byte(strconv.Itoa(l.RegionLimit.MemoryMB)))
+		_, _ = hash.Write([]byte(strconv.Itoa(l.RegionLimit.MemoryMaxMB)))
+	}
+
+	l.Hash = hash.Sum(nil)
+	return l.Hash
+}
+
+// HashKey returns the key of the limit within quota usages.
+func (l *QuotaLimit) HashKey() string {
+	return base64.StdEncoding.EncodeToString(l.Hash)
+}
+
+// RegionLimit returns the limit of the quota specification for the region,
+// or nil if the region is not limited.
+func (q *QuotaSpec) RegionLimit(region string) *QuotaLimit {
+	for _, limit := range q.Limits {
+		if limit.Region == region {
+			return limit
+		}
+	}
+	return nil
+}
+
+// Copy returns a deep copy of the quota specification. It handles nil
+// objects.
+func (q *QuotaSpec) Copy() *QuotaSpec {
+	if q == nil {
+		return nil
+	}
+
+	nq := new(QuotaSpec)
+	*nq = *q
+	nq.Hash = make([]byte, len(q.Hash))
+	copy(nq.Hash, q.Hash)
+	if q.Limits != nil {
+		nq.Limits = make([]*QuotaLimit, len(q.Limits))
+		for i, limit := range q.Limits {
+			nq.Limits[i] = limit.Copy()
+		}
+	}
+	return nq
+}
+
+// Copy returns a deep copy of the quota limit. It handles nil objects.
+func (l *QuotaLimit) Copy() *QuotaLimit {
+	if l == nil {
+		return nil
+	}
+
+	nl := new(QuotaLimit)
+	*nl = *l
+	nl.RegionLimit = l.RegionLimit.Copy()
+	nl.Hash = make([]byte, len(l.Hash))
+	copy(nl.Hash, l.Hash)
+	return nl
+}
+
+// Exhausted returns the dimensions of the limit which the delta pushes
+// over the limit given the resources already used. Dimensions the delta
+// does not increase are never reported, so allocations may still be stopped
+// or shrunk while a quota is over its limit.
+func (l *QuotaLimit) Exhausted(used, delta *Resources) []string {
+	if l == nil || l.RegionLimit == nil {
+		return nil
+	}
+
+	var dims []string
+	check := func(dim string, limit, used, delta int) {
+		if limit == 0 || delta <= 0 {
+			return
+		}
+		if limit < 0 {
+			limit = 0
+		}
+		if total := used + delta; total > limit {
+			dims = append(dims, fmt.Sprintf("%s exhausted (%d > %d)", dim, total, limit))
+		}
+	}
+
+	check(QuotaDimensionCPU, l.RegionLimit.CPU, used.CPU, delta.CPU)
+	check(QuotaDimensionMemory, l.RegionLimit.MemoryMB, used.MemoryMB, delta.MemoryMB)
+	check(QuotaDimensionMemoryMax, l.RegionLimit.MemoryMaxMB, used.MemoryMaxMB, delta.MemoryMaxMB)
+	return dims
+}
+
+// QuotaResources returns the resources counted against the quota of the
+// namespace of the allocation. Allocations without a memory_max count their
+// memory towards the memory_max quota.
+func (a *Allocation) QuotaResources() *Resources {
+	flattened := a.ComparableResources().Flattened
+	r := &Resources{
+		CPU:         int(flattened.Cpu.CpuShares),
+		MemoryMB:    int(flattened.Memory.MemoryMB),
+		MemoryMaxMB: int(flattened.Memory.MemoryMaxMB),
+	}
+	if r.MemoryMaxMB < r.MemoryMB {
+		r.MemoryMaxMB = r.MemoryMB
+	}
+	return r
+}
+
+// AddQuotaResources adds the quota resources of delta to r.
+func (r *Resources) AddQuotaResources(delta *Resources) {
+	r.CPU += delta.CPU
+	r.MemoryMB += delta.MemoryMB
+	r.MemoryMaxMB += delta.MemoryMaxMB
+}
+
+// SubtractQuotaResources subtracts the quota resources of delta from r.
+func (r *Resources) SubtractQuotaResources(delta *Resources) {
+	r.CPU -= delta.CPU
+	r.MemoryMB -= delta.MemoryMB
+	r.MemoryMaxMB -= delta.MemoryMaxMB
+}
+
+// QuotaSpecUpsertRequest is used to upsert a set of quota specifications.
+type QuotaSpecUpsertRequest struct {
+	Quotas []*QuotaSpec
+	WriteRequest
+}
+
+// QuotaSpecDeleteRequest is used to delete a set of quota specifications.
+type QuotaSpecDeleteRequest struct {
+	Names []string
+	WriteRequest
+}
+
+// QuotaSpecListRequest is used to list the quota specifications or their
+// usages.
+type QuotaSpecListRequest struct {
+	QueryOptions
+}
+
+// QuotaSpecListResponse is used for a list request.
+type QuotaSpecListResponse struct {
+	Quotas []*QuotaSpec
+	QueryMeta
+}
+
+// QuotaSpecSpecificRequest is used to query a specific quota specification
+// or its usage.
+type QuotaSpecSpecificRequest struct {
+	Name string
+	QueryOptions
+}
+
+// SingleQuotaSpecResponse is used to return a single quota specification.
+type SingleQuotaSpecResponse struct {
+	Quota *QuotaSpec
+	QueryMeta
+}
+
+// QuotaSpecSetRequest is used to query a set of quota specifications.
+type QuotaSpecSetRequest struct {
+	Names []string
+	QueryOptions
+}
+
+// QuotaSpecSetResponse is used to return a set of quota specifications.
+type QuotaSpecSetResponse struct {
+	Quotas map[string]*QuotaSpec // Keyed by quota specification Name
+	QueryMeta
+}
+
+// QuotaUsageListResponse is used to return the usages of a list of quota
+// specifications.
+type QuotaUsageListResponse struct {
+	Usages []*QuotaUsage
+	QueryMeta
+}
+
+// SingleQuotaUsageResponse is used to return the usage of a single quota
+// specification.
+type SingleQuotaUsageResponse struct {
+	Usage *QuotaUsage
+	QueryMeta
+}
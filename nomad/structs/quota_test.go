@@ -0,0 +1,140 @@
+package structs
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuotaSpec_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name        string
+		spec        *QuotaSpec
+		expectedErr string
+	}{
+		{
+			name: "valid",
+			spec: &QuotaSpec{
+				Name:        "valid",
+				Description: "valid quota",
+				Limits: []*QuotaLimit{{
+					Region:      "global",
+					RegionLimit: &Resources{CPU: 2000, MemoryMB: 1000, MemoryMaxMB: 2000},
+				}},
+			},
+		},
+		{
+			name:        "invalid name",
+			spec:        &QuotaSpec{Name: "not@valid"},
+			expectedErr: "invalid name",
+		},
+		{
+			name: "duplicate region",
+			spec: &QuotaSpec{
+				Name: "duplicate",
+				Limits: []*QuotaLimit{
+					{Region: "global", RegionLimit: &Resources{CPU: 100}},
+					{Region: "global", RegionLimit: &Resources{CPU: 200}},
+				},
+			},
+			expectedErr: `duplicate limit for region "global"`,
+		},
+		{
+			name: "missing region limit",
+			spec: &QuotaSpec{
+				Name:   "missing",
+				Limits: []*QuotaLimit{{Region: "global"}},
+			},
+			expectedErr: "missing region_limit",
+		},
+		{
+			name: "unsupported resource",
+			spec: &QuotaSpec{
+				Name: "unsupported",
+				Limits: []*QuotaLimit{{
+					Region:      "global",
+					RegionLimit: &Resources{DiskMB: 100},
+				}},
+			},
+			expectedErr: "only the cpu, memory and memory_max resources can be limited",
+		},
+		{
+			name: "memory max lower than memory",
+			spec: &QuotaSpec{
+				Name: "memory",
+				Limits: []*QuotaLimit{{
+					Region:      "global",
+					RegionLimit: &Resources{MemoryMB: 1000, MemoryMaxMB: 500},
+				}},
+			},
+			expectedErr: "memory_max (500) cannot be lower than memory (1000)",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.spec.Validate()
+			if tc.expectedErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
+
+func TestQuotaSpec_SetHash(t *testing.T) {
+	ci.Parallel(t)
+
+	spec := &QuotaSpec{
+		Name: "hash",
+		Limits: []*QuotaLimit{{
+			Region:      "global",
+			RegionLimit: &Resources{CPU: 100},
+		}},
+	}
+	out1 := spec.SetHash()
+	require.NotEmpty(t, out1)
+	require.Equal(t, out1, spec.Hash)
+	require.NotEmpty(t, spec.Limits[0].Hash)
+
+	// Changing a limit changes the hash of the limit and of the quota
+	limitHash := spec.Limits[0].Hash
+	spec.Limits[0].RegionLimit.CPU = 200
+	out2 := spec.SetHash()
+	require.NotEqual(t, out1, out2)
+	require.NotEqual(t, limitHash, spec.Limits[0].Hash)
+
+	// Copies are deep
+	spec2 := spec.Copy()
+	spec2.Limits[0].RegionLimit.CPU = 300
+	require.Equal(t, 200, spec.Limits[0].RegionLimit.CPU)
+	require.Equal(t, spec.Hash, spec2.Hash)
+}
+
+func TestQuotaLimit_Exhausted(t *testing.T) {
+	ci.Parallel(t)
+
+	limit := &QuotaLimit{
+		Region:      "global",
+		RegionLimit: &Resources{CPU: 1000, MemoryMB: -1},
+	}
+
+	// Within the limit
+	require.Empty(t, limit.Exhausted(&Resources{CPU: 500}, &Resources{CPU: 500}))
+
+	// Over the limit
+	require.Equal(t, []string{"cpu exhausted (1500 > 1000)"},
+		limit.Exhausted(&Resources{CPU: 1000}, &Resources{CPU: 500}))
+
+	// Negative limits disallow any use while unset limits are unlimited
+	require.Equal(t, []string{"memory exhausted (10 > 0)"},
+		limit.Exhausted(&Resources{}, &Resources{MemoryMB: 10, MemoryMaxMB: 10}))
+
+	// Dimensions which are not increased are not exhausted, even when
+	// already over the limit
+	require.Empty(t, limit.Exhausted(&Resources{CPU: 2000}, &Resources{CPU: -500}))
+}
@@ -87,6 +87,11 @@ type ServiceRegistration struct {
 	// is determined by a combination of factors on the client.
 	Port int
 
+	// Checks contains the most recent status of the health checks defined
+	// within the service block. The checks are executed by the client running
+	// the allocation, which updates the registration when a status changes.
+	Checks []*ServiceRegistrationCheck
+
 	CreateIndex uint64
 	ModifyIndex uint64
 }
@@ -102,6 +107,13 @@ func (s *ServiceRegistration) Copy() *ServiceRegistration {
 	*ns = *s
 	ns.Tags = helper.CopySliceString(ns.Tags)
 
+	if s.Checks != nil {
+		ns.Checks = make([]*ServiceRegistrationCheck, len(s.Checks))
+		for i, check := range s.Checks {
+			ns.Checks[i] = check.Copy()
+		}
+	}
+
 	return ns
 }
 
@@ -141,6 +153,30 @@ func (s *ServiceRegistration) Equals(o *ServiceRegistration) bool {
 	if !helper.CompareSliceSetString(s.Tags, o.Tags) {
 		return false
 	}
+	if len(s.Checks) != len(o.Checks) {
+		return false
+	}
+	for i := range s.Checks {
+		if !s.Checks[i].Equals(o.Checks[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Healthy returns whether none of the checks associated with the service
+// registration are critical. Checks with a warning status still allow the
+// instance to receive traffic. A registration without checks is always
+// considered healthy.
+func (s *ServiceRegistration) Healthy() bool {
+	if s == nil {
+		return false
+	}
+	for _, check := range s.Checks {
+		if check.Status == ServiceCheckStatusCritical {
+			return false
+		}
+	}
 	return true
 }
 
@@ -192,6 +228,65 @@ func (s *ServiceRegistration) HashWith(key string) string {
 	return fmt.Sprintf("%x", sum.Sum(nil))
 }
 
+const (
+	// ServiceCheckStatusPassing, ServiceCheckStatusWarning, and
+	// ServiceCheckStatusCritical are the statuses a Nomad service check can
+	// report. They mirror the Consul health statuses, so the same
+	// initial_status values are valid for both providers.
+	ServiceCheckStatusPassing  = "passing"
+	ServiceCheckStatusWarning  = "warning"
+	ServiceCheckStatusCritical = "critical"
+)
+
+// ServiceRegistrationCheck is the status of an individual health check of a
+// service registration using the Nomad provider.
+type ServiceRegistrationCheck struct {
+
+	// ID is the unique identifier of the check and is generated from the
+	// check definition and the service registration ID.
+	ID string
+
+	// Name is ServiceCheck.Name.
+	Name string
+
+	// Type is ServiceCheck.Type and indicates whether this is a http, tcp, or
+	// script check.
+	Type string
+
+	// Status is the current status of the check. It is one of
+	// ServiceCheckStatusPassing, ServiceCheckStatusWarning, or
+	// ServiceCheckStatusCritical.
+	Status string
+
+	// Output is the output of the check execution that resulted in the
+	// current status, truncated by the client.
+	Output string
+}
+
+// Copy creates a copy of the service registration check. It handles nil
+// objects.
+func (c *ServiceRegistrationCheck) Copy() *ServiceRegistrationCheck {
+	if c == nil {
+		return nil
+	}
+	nc := new(ServiceRegistrationCheck)
+	*nc = *c
+	return nc
+}
+
+// Equals performs an equality check on the two service registration checks.
+// It handles nil objects.
+func (c *ServiceRegistrationCheck) Equals(o *ServiceRegistrationCheck) bool {
+	if c == nil || o == nil {
+		return c == o
+	}
+	return c.ID == o.ID &&
+		c.Name == o.Name &&
+		c.Type == o.Type &&
+		c.Status == o.Status &&
+		c.Output == o.Output
+}
+
 // ServiceRegistrationUpsertRequest is the request object used to upsert one or
 // more service registrations.
 type ServiceRegistrationUpsertRequest struct {
@@ -267,6 +362,7 @@ type ServiceRegistrationStub struct {
 type ServiceRegistrationByNameRequest struct {
 	ServiceName string
 	Choose      string // stable selection of n services
+	Healthy     bool   // omit registrations with critical checks
 	QueryOptions
 }
 
@@ -383,6 +383,63 @@ func TestServiceRegistration_Equal(t *testing.T) {
 	}
 }
 
+func TestServiceRegistration_Healthy(t *testing.T) {
+	testCases := []struct {
+		inputServiceRegistration *ServiceRegistration
+		expectedOutput           bool
+		name                     string
+	}{
+		{
+			inputServiceRegistration: nil,
+			expectedOutput:           false,
+			name:                     "nil input",
+		},
+		{
+			inputServiceRegistration: &ServiceRegistration{ID: "foo"},
+			expectedOutput:           true,
+			name:                     "no checks",
+		},
+		{
+			inputServiceRegistration: &ServiceRegistration{
+				ID: "foo",
+				Checks: []*ServiceRegistrationCheck{
+					{ID: "check1", Status: ServiceCheckStatusPassing},
+					{ID: "check2", Status: ServiceCheckStatusPassing},
+				},
+			},
+			expectedOutput: true,
+			name:           "all checks passing",
+		},
+		{
+			inputServiceRegistration: &ServiceRegistration{
+				ID: "foo",
+				Checks: []*ServiceRegistrationCheck{
+					{ID: "check1", Status: ServiceCheckStatusPassing},
+					{ID: "check2", Status: ServiceCheckStatusWarning},
+				},
+			},
+			expectedOutput: true,
+			name:           "check warning",
+		},
+		{
+			inputServiceRegistration: &ServiceRegistration{
+				ID: "foo",
+				Checks: []*ServiceRegistrationCheck{
+					{ID: "check1", Status: ServiceCheckStatusCritical},
+				},
+			},
+			expectedOutput: false,
+			name:           "check critical",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expectedOutput, tc.inputServiceRegistration.Healthy())
+		})
+	}
+}
+
 func TestServiceRegistration_GetID(t *testing.T) {
 	testCases := []struct {
 		inputServiceRegistration *ServiceRegistration
@@ -663,11 +663,33 @@ func (s *Service) validateConsulService(mErr *multierror.Error) {
 // nomad provider.
 func (s *Service) validateNomadService(mErr *multierror.Error) {
 
-	// Service blocks for the Nomad provider do not support checks. We perform
-	// a nil check, as an empty check list is nilled within the service
-	// canonicalize function.
-	if s.Checks != nil {
-		mErr.Errors = append(mErr.Errors, errors.New("Service with provider nomad cannot include Check blocks"))
+	// Service blocks for the Nomad provider support a subset of the check
+	// types and features, as the checks are executed by the Nomad client
+	// rather than a Consul agent.
+	for _, c := range s.Checks {
+		switch c.Type {
+		case ServiceCheckHTTP, ServiceCheckTCP, ServiceCheckScript:
+		default:
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Check %s invalid: provider nomad does not support %q checks", c.Name, c.Type))
+			continue
+		}
+
+		if s.PortLabel == "" && c.PortLabel == "" && c.RequiresPort() {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Check %s invalid: check requires a port but neither check nor service %+q have a port", c.Name, s.Name))
+			continue
+		}
+
+		if c.Expose {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Check %s invalid: provider nomad does not support expose", c.Name))
+		}
+
+		if c.CheckRestart != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Check %s invalid: provider nomad does not support check_restart", c.Name))
+		}
+
+		if err := c.validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Check %s invalid: %v", c.Name, err))
+		}
 	}
 
 	// Services using the Nomad provider do not support Consul connect.
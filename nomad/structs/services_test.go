@@ -1558,12 +1558,13 @@ func TestService_Validate(t *testing.T) {
 				Checks: []*ServiceCheck{
 					{
 						Name: "servicecheck",
+						Type: "grpc",
 					},
 				},
 			},
 			expErr:    true,
-			expErrStr: "Service with provider nomad cannot include Check blocks",
-			name:      "provider nomad with checks",
+			expErrStr: `provider nomad does not support "grpc" checks`,
+			name:      "provider nomad with grpc check",
 		},
 		{
 			input: &Service{
@@ -1712,12 +1713,52 @@ func TestService_validateNomadService(t *testing.T) {
 				Namespace: "default",
 				Provider:  "nomad",
 				Checks: []*ServiceCheck{
-					{Name: "some-check"},
+					{
+						Name:     "some-check",
+						Type:     "http",
+						Path:     "/health",
+						Interval: 10 * time.Second,
+						Timeout:  2 * time.Second,
+					},
+				},
+			},
+			inputErr:             &multierror.Error{},
+			expectedOutputErrors: []error{},
+			name:                 "valid service with http check",
+		},
+		{
+			inputService: &Service{
+				Name:      "webapp",
+				PortLabel: "http",
+				Namespace: "default",
+				Provider:  "nomad",
+				Checks: []*ServiceCheck{
+					{Name: "some-check", Type: "grpc"},
+				},
+			},
+			inputErr:             &multierror.Error{},
+			expectedOutputErrors: []error{errors.New(`Check some-check invalid: provider nomad does not support "grpc" checks`)},
+			name:                 "invalid service due to grpc check",
+		},
+		{
+			inputService: &Service{
+				Name:      "webapp",
+				PortLabel: "http",
+				Namespace: "default",
+				Provider:  "nomad",
+				Checks: []*ServiceCheck{
+					{
+						Name:         "some-check",
+						Type:         "tcp",
+						Interval:     10 * time.Second,
+						Timeout:      2 * time.Second,
+						CheckRestart: &CheckRestart{Limit: 3},
+					},
 				},
 			},
 			inputErr:             &multierror.Error{},
-			expectedOutputErrors: []error{errors.New("Service with provider nomad cannot include Check blocks")},
-			name:                 "invalid service due to checks",
+			expectedOutputErrors: []error{errors.New("Check some-check invalid: provider nomad does not support check_restart")},
+			name:                 "invalid service due to check restart",
 		},
 		{
 			inputService: &Service{
@@ -1743,12 +1784,12 @@ func TestService_validateNomadService(t *testing.T) {
 					Native: true,
 				},
 				Checks: []*ServiceCheck{
-					{Name: "some-check"},
+					{Name: "some-check", Type: "grpc"},
 				},
 			},
 			inputErr: &multierror.Error{},
 			expectedOutputErrors: []error{
-				errors.New("Service with provider nomad cannot include Check blocks"),
+				errors.New(`Check some-check invalid: provider nomad does not support "grpc" checks`),
 				errors.New("Service with provider nomad cannot include Connect blocks"),
 			},
 			name: "invalid service due to checks and connect",
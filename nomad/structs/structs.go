@@ -16,6 +16,7 @@ import (
 	"hash/crc32"
 	"math"
 	"net"
+	"net/url"
 	"os"
 	"reflect"
 	"regexp"
@@ -108,10 +109,26 @@ const (
 	ServiceRegistrationUpsertRequestType         MessageType = 47
 	ServiceRegistrationDeleteByIDRequestType     MessageType = 48
 	ServiceRegistrationDeleteByNodeIDRequestType MessageType = 49
+	VarApplyStateRequestType                     MessageType = 50
+	RootKeyMetaUpsertRequestType                 MessageType = 51
+	ACLRolesUpsertRequestType                    MessageType = 52
+	ACLRolesDeleteByIDRequestType                MessageType = 53
+	ACLAuthMethodsUpsertRequestType              MessageType = 54
+	ACLAuthMethodsDeleteRequestType              MessageType = 55
+	ACLBindingRulesUpsertRequestType             MessageType = 56
+	ACLBindingRulesDeleteRequestType             MessageType = 57
+	NodePoolUpsertRequestType                    MessageType = 58
+	NodePoolDeleteRequestType                    MessageType = 59
+	NodeUpdateUtilizationRequestType             MessageType = 60
 
 	// Namespace types were moved from enterprise and therefore start at 64
 	NamespaceUpsertRequestType MessageType = 64
 	NamespaceDeleteRequestType MessageType = 65
+
+	// Quota specification types were moved from enterprise and therefore
+	// follow the namespace types
+	QuotaSpecUpsertRequestType MessageType = 66
+	QuotaSpecDeleteRequestType MessageType = 67
 )
 
 const (
@@ -496,6 +513,18 @@ type NodeUpdateStatusRequest struct {
 	Status    string
 	NodeEvent *NodeEvent
 	UpdatedAt int64
+
+	// Utilization is the resource usage measured by the client, sent along
+	// with heartbeats.
+	Utilization *NodeUtilization
+	WriteRequest
+}
+
+// NodeUpdateUtilizationRequest is used for updating the resource utilization
+// of a node
+type NodeUpdateUtilizationRequest struct {
+	NodeID      string
+	Utilization *NodeUtilization
 	WriteRequest
 }
 
@@ -600,6 +629,10 @@ type JobRegisterRequest struct {
 	// Eval is the evaluation that is associated with the job registration
 	Eval *Evaluation
 
+	// Submission is the original jobspec source the job was parsed from. It
+	// is optional and is dropped if it exceeds the maximum source size.
+	Submission *JobSubmission
+
 	WriteRequest
 }
 
@@ -1444,6 +1477,20 @@ type JobVersionsResponse struct {
 	QueryMeta
 }
 
+// JobSubmissionRequest is used to get the source a version of a job was
+// submitted from.
+type JobSubmissionRequest struct {
+	JobID   string
+	Version uint64
+	QueryOptions
+}
+
+// JobSubmissionResponse is used for a job submission request
+type JobSubmissionResponse struct {
+	Submission *JobSubmission
+	QueryMeta
+}
+
 // JobPlanResponse is used to respond to a job plan request
 type JobPlanResponse struct {
 	// Annotations stores annotations explaining decisions the scheduler made.
@@ -1740,6 +1787,12 @@ type DrainSpec struct {
 	// IgnoreSystemJobs allows systems jobs to remain on the node even though it
 	// has been marked for draining.
 	IgnoreSystemJobs bool
+
+	// Checkpoint prefers checkpointing the tasks of migrated allocations and
+	// restoring them on their new node over restarting them. Only tasks of
+	// drivers supporting checkpoints in groups with a migrating ephemeral
+	// disk are checkpointed.
+	Checkpoint bool
 }
 
 // DrainStrategy describes a Node's drain behavior.
@@ -1803,6 +1856,8 @@ func (d *DrainStrategy) Equal(o *DrainStrategy) bool {
 		return false
 	} else if d.IgnoreSystemJobs != o.IgnoreSystemJobs {
 		return false
+	} else if d.Checkpoint != o.Checkpoint {
+		return false
 	}
 
 	return true
@@ -1862,6 +1917,9 @@ type Node struct {
 	// Datacenter for this node
 	Datacenter string
 
+	// NodePool is the node pool the node belongs to.
+	NodePool string
+
 	// Node name
 	Name string
 
@@ -1957,6 +2015,9 @@ type Node struct {
 	// LastDrain contains metadata about the most recent drain operation
 	LastDrain *DrainMetadata
 
+	// Utilization is the most recent resource usage reported by the client
+	Utilization *NodeUtilization
+
 	// Raft Indexes
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -2003,6 +2064,12 @@ func (n *Node) Canonicalize() {
 		n.SchedulingEligibility = NodeSchedulingEligible
 	}
 
+	// Nodes registered before node pools were introduced belong to the
+	// default node pool.
+	if n.NodePool == "" {
+		n.NodePool = NodePoolDefault
+	}
+
 	// COMPAT remove in 1.0
 	// In v0.12.0 we introduced a separate node specific network resource struct
 	// so we need to covert any pre 0.12 clients to the correct struct
@@ -2049,6 +2116,7 @@ func (n *Node) Copy() *Node {
 	nn.HostVolumes = copyNodeHostVolumes(n.HostVolumes)
 	nn.HostNetworks = copyNodeHostNetworks(n.HostNetworks)
 	nn.LastDrain = nn.LastDrain.Copy()
+	nn.Utilization = nn.Utilization.Copy()
 	return nn
 }
 
@@ -2205,6 +2273,7 @@ func (n *Node) Stub(fields *NodeStubFields) *NodeListStub {
 		Address:               addr,
 		ID:                    n.ID,
 		Datacenter:            n.Datacenter,
+		NodePool:              n.NodePool,
 		Name:                  n.Name,
 		NodeClass:             n.NodeClass,
 		Version:               n.Attributes["nomad.version"],
@@ -2243,6 +2312,7 @@ type NodeListStub struct {
 	ID                    string
 	Attributes            map[string]string `json:",omitempty"`
 	Datacenter            string
+	NodePool              string
 	Name                  string
 	NodeClass             string
 	Version               string
@@ -2276,6 +2346,7 @@ type Resources struct {
 	IOPS        int // COMPAT(0.10): Only being used to issue warnings
 	Networks    Networks
 	Devices     ResourceDevices
+	NUMA        *NUMA
 }
 
 const (
@@ -2338,6 +2409,12 @@ func (r *Resources) Validate() error {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("MemoryMaxMB value (%d) should be larger than MemoryMB value (%d)", r.MemoryMaxMB, r.MemoryMB))
 	}
 
+	if err := r.NUMA.Validate(); err != nil {
+		mErr.Errors = append(mErr.Errors, err)
+	} else if r.NUMA.IsRequested() && r.Cores == 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("NUMA affinity can only be set when reserving 'cores'"))
+	}
+
 	return mErr.ErrorOrNil()
 }
 
@@ -2365,6 +2442,9 @@ func (r *Resources) Merge(other *Resources) {
 	if len(other.Devices) != 0 {
 		r.Devices = other.Devices
 	}
+	if other.NUMA != nil {
+		r.NUMA = other.NUMA.Copy()
+	}
 }
 
 // Equals Resources.
@@ -2384,7 +2464,8 @@ func (r *Resources) Equals(o *Resources) bool {
 		r.DiskMB == o.DiskMB &&
 		r.IOPS == o.IOPS &&
 		r.Networks.Equals(&o.Networks) &&
-		r.Devices.Equals(&o.Devices)
+		r.Devices.Equals(&o.Devices) &&
+		r.NUMA.Equals(o.NUMA)
 }
 
 // ResourceDevices are part of Resources.
@@ -2434,6 +2515,8 @@ func (r *Resources) Canonicalize() {
 	for _, n := range r.Networks {
 		n.Canonicalize()
 	}
+
+	r.NUMA.Canonicalize()
 }
 
 // MeetsMinResources returns an error if the resources specified are less than
@@ -2472,6 +2555,8 @@ func (r *Resources) Copy() *Resources {
 		}
 	}
 
+	newR.NUMA = r.NUMA.Copy()
+
 	return newR
 }
 
@@ -2516,6 +2601,74 @@ func (r *Resources) GoString() string {
 	return fmt.Sprintf("*%#v", *r)
 }
 
+const (
+	// NUMAAffinityNone places the cores of a task without regard to the NUMA
+	// topology of the node.
+	NUMAAffinityNone = "none"
+
+	// NUMAAffinityPrefer places the cores of a task on a single NUMA node
+	// when possible, and falls back to spreading them across NUMA nodes.
+	NUMAAffinityPrefer = "prefer"
+
+	// NUMAAffinityRequire places the cores of a task on a single NUMA node,
+	// and marks nodes where that is not possible as exhausted.
+	NUMAAffinityRequire = "require"
+)
+
+// NUMA is used to request that the reserved cores, memory and devices of a
+// task are placed on the same NUMA node.
+type NUMA struct {
+	// Affinity is one of "none", "prefer" or "require".
+	Affinity string
+}
+
+// IsRequested returns whether the task asks for NUMA aware placement.
+func (n *NUMA) IsRequested() bool {
+	return n != nil && n.Affinity != "" && n.Affinity != NUMAAffinityNone
+}
+
+// IsRequired returns whether the task must be placed on a single NUMA node.
+func (n *NUMA) IsRequired() bool {
+	return n != nil && n.Affinity == NUMAAffinityRequire
+}
+
+func (n *NUMA) Canonicalize() {
+	if n == nil {
+		return
+	}
+	if n.Affinity == "" {
+		n.Affinity = NUMAAffinityNone
+	}
+}
+
+func (n *NUMA) Validate() error {
+	if n == nil {
+		return nil
+	}
+	switch n.Affinity {
+	case "", NUMAAffinityNone, NUMAAffinityPrefer, NUMAAffinityRequire:
+		return nil
+	default:
+		return fmt.Errorf("invalid NUMA affinity %q: must be one of %q, %q or %q",
+			n.Affinity, NUMAAffinityNone, NUMAAffinityPrefer, NUMAAffinityRequire)
+	}
+}
+
+func (n *NUMA) Equals(o *NUMA) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Affinity == o.Affinity
+}
+
+func (n *NUMA) Copy() *NUMA {
+	if n == nil {
+		return nil
+	}
+	nn := *n
+	return &nn
+}
+
 // NodeNetworkResource is used to describe a fingerprinted network of a node
 type NodeNetworkResource struct {
 	Mode string // host for physical networks, cni/<name> for cni networks
@@ -2899,6 +3052,10 @@ type NodeResources struct {
 	NodeNetworks []*NodeNetworkResource
 	Devices      []*NodeDeviceResource
 
+	// NUMA is the NUMA topology of the node. It is nil on nodes where the
+	// topology could not be fingerprinted.
+	NUMA *NodeNUMAResources
+
 	MinDynamicPort int
 	MaxDynamicPort int
 }
@@ -2929,6 +3086,8 @@ func (n *NodeResources) Copy() *NodeResources {
 		}
 	}
 
+	newN.NUMA = n.NUMA.Copy()
+
 	return newN
 }
 
@@ -2974,6 +3133,10 @@ func (n *NodeResources) Merge(o *NodeResources) {
 		n.Devices = o.Devices
 	}
 
+	if o.NUMA != nil {
+		n.NUMA = o.NUMA
+	}
+
 	if len(o.NodeNetworks) != 0 {
 		lookupNetwork := func(nets []*NodeNetworkResource, name string) (int, *NodeNetworkResource) {
 			for i, nw := range nets {
@@ -3025,6 +3188,10 @@ func (n *NodeResources) Equals(o *NodeResources) bool {
 		return false
 	}
 
+	if !n.NUMA.Equals(o.NUMA) {
+		return false
+	}
+
 	return true
 }
 
@@ -3164,6 +3331,131 @@ func (n *NodeCpuResources) SharesPerCore() int64 {
 	return n.CpuShares / int64(n.TotalCpuCores)
 }
 
+// NodeNUMAResources captures the NUMA topology of the node.
+type NodeNUMAResources struct {
+	// Nodes are the NUMA nodes of the node, sorted by ID.
+	Nodes []*NodeNUMANode
+}
+
+func (n *NodeNUMAResources) Copy() *NodeNUMAResources {
+	if n == nil {
+		return nil
+	}
+
+	newN := &NodeNUMAResources{}
+	if n.Nodes != nil {
+		newN.Nodes = make([]*NodeNUMANode, len(n.Nodes))
+		for i, node := range n.Nodes {
+			newN.Nodes[i] = node.Copy()
+		}
+	}
+	return newN
+}
+
+func (n *NodeNUMAResources) Equals(o *NodeNUMAResources) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+
+	if len(n.Nodes) != len(o.Nodes) {
+		return false
+	}
+	for i := range n.Nodes {
+		if !n.Nodes[i].Equals(o.Nodes[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// NodeByID returns the NUMA node with the given ID or nil if there is none.
+func (n *NodeNUMAResources) NodeByID(id uint16) *NodeNUMANode {
+	if n == nil {
+		return nil
+	}
+	for _, node := range n.Nodes {
+		if node.ID == id {
+			return node
+		}
+	}
+	return nil
+}
+
+// NodeOfCore returns the NUMA node which the core belongs to or nil if the
+// core is not part of any NUMA node.
+func (n *NodeNUMAResources) NodeOfCore(core uint16) *NodeNUMANode {
+	if n == nil {
+		return nil
+	}
+	for _, node := range n.Nodes {
+		for _, c := range node.Cores {
+			if c == core {
+				return node
+			}
+		}
+	}
+	return nil
+}
+
+// NodeNUMANode describes a NUMA node: a set of cores together with the
+// memory local to them.
+type NodeNUMANode struct {
+	// ID is the ID of the NUMA node, as used by cpuset.mems.
+	ID uint16
+
+	// Socket is the physical package the cores of the NUMA node belong to.
+	Socket uint16
+
+	// Cores is the set of cores local to the NUMA node.
+	Cores []uint16
+
+	// CoreSiblings groups the cores of the NUMA node which are hardware
+	// threads of the same physical core.
+	CoreSiblings [][]uint16
+
+	// MemoryMB is the memory local to the NUMA node.
+	MemoryMB int64
+}
+
+func (n *NodeNUMANode) Copy() *NodeNUMANode {
+	if n == nil {
+		return nil
+	}
+
+	newN := new(NodeNUMANode)
+	*newN = *n
+	newN.Cores = append([]uint16(nil), n.Cores...)
+	if n.CoreSiblings != nil {
+		newN.CoreSiblings = make([][]uint16, len(n.CoreSiblings))
+		for i, siblings := range n.CoreSiblings {
+			newN.CoreSiblings[i] = append([]uint16(nil), siblings...)
+		}
+	}
+	return newN
+}
+
+func (n *NodeNUMANode) Equals(o *NodeNUMANode) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+
+	if n.ID != o.ID || n.Socket != o.Socket || n.MemoryMB != o.MemoryMB {
+		return false
+	}
+	if !cpuset.New(n.Cores...).Equals(cpuset.New(o.Cores...)) {
+		return false
+	}
+	if len(n.CoreSiblings) != len(o.CoreSiblings) {
+		return false
+	}
+	for i := range n.CoreSiblings {
+		if !cpuset.New(n.CoreSiblings[i]...).Equals(cpuset.New(o.CoreSiblings[i]...)) {
+			return false
+		}
+	}
+	return true
+}
+
 // NodeMemoryResources captures the memory resources of the node
 type NodeMemoryResources struct {
 	// MemoryMB is the total available memory on the node
@@ -3420,6 +3712,10 @@ func (n *NodeDevice) Copy() *NodeDevice {
 type NodeDeviceLocality struct {
 	// PciBusID is the PCI Bus ID for the device.
 	PciBusID string
+
+	// NUMANode is the NUMA node the device is attached to. It is nil when
+	// the NUMA node of the device is unknown.
+	NUMANode *uint16
 }
 
 func (n *NodeDeviceLocality) Equals(o *NodeDeviceLocality) bool {
@@ -3435,6 +3731,11 @@ func (n *NodeDeviceLocality) Equals(o *NodeDeviceLocality) bool {
 		return false
 	}
 
+	if (n.NUMANode == nil) != (o.NUMANode == nil) ||
+		(n.NUMANode != nil && *n.NUMANode != *o.NUMANode) {
+		return false
+	}
+
 	return true
 }
 
@@ -3445,6 +3746,10 @@ func (n *NodeDeviceLocality) Copy() *NodeDeviceLocality {
 
 	// Copy the primitives
 	nn := *n
+	if n.NUMANode != nil {
+		numaNode := *n.NUMANode
+		nn.NUMANode = &numaNode
+	}
 	return &nn
 }
 
@@ -3822,6 +4127,10 @@ func (a *AllocatedSharedResources) Canonicalize() {
 type AllocatedCpuResources struct {
 	CpuShares     int64
 	ReservedCores []uint16
+
+	// NUMANodes is the set of NUMA nodes the memory of the task is bound to.
+	// It is empty when the task is not bound to any NUMA node.
+	NUMANodes []uint16
 }
 
 func (a *AllocatedCpuResources) Add(delta *AllocatedCpuResources) {
@@ -3832,6 +4141,9 @@ func (a *AllocatedCpuResources) Add(delta *AllocatedCpuResources) {
 	a.CpuShares += delta.CpuShares
 
 	a.ReservedCores = cpuset.New(a.ReservedCores...).Union(cpuset.New(delta.ReservedCores...)).ToSlice()
+	if len(delta.NUMANodes) > 0 {
+		a.NUMANodes = cpuset.New(a.NUMANodes...).Union(cpuset.New(delta.NUMANodes...)).ToSlice()
+	}
 }
 
 func (a *AllocatedCpuResources) Subtract(delta *AllocatedCpuResources) {
@@ -3854,6 +4166,7 @@ func (a *AllocatedCpuResources) Max(other *AllocatedCpuResources) {
 
 	if len(other.ReservedCores) > len(a.ReservedCores) {
 		a.ReservedCores = other.ReservedCores
+		a.NUMANodes = other.NUMANodes
 	}
 }
 
@@ -4098,6 +4411,11 @@ type Job struct {
 	// This can be extended in the future to support custom schedulers.
 	Type string
 
+	// Scheduler is the name of the scheduler plugin used to process the
+	// evaluations of the job. If empty, the built-in scheduler matching the
+	// type of the job is used.
+	Scheduler string
+
 	// Priority is used to control scheduling importance and if this job
 	// can preempt other jobs.
 	Priority int
@@ -4110,6 +4428,11 @@ type Job struct {
 	// Datacenters contains all the datacenters this job is allowed to span
 	Datacenters []string
 
+	// NodePool is the node pool the job is allowed to be placed on. Jobs
+	// which do not specify a node pool use the default node pool of their
+	// namespace.
+	NodePool string
+
 	// Constraints can be specified at a job level and apply to
 	// all the task groups and tasks.
 	Constraints []*Constraint
@@ -4415,6 +4738,10 @@ func (j *Job) Validate() error {
 		}
 	}
 
+	if cycle := j.dependencyCycle(); cycle != nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Task group dependencies form a cycle: %s", strings.Join(cycle, " -> ")))
+	}
+
 	// Validate periodic is only used with batch or sysbatch jobs.
 	if j.IsPeriodic() && j.Periodic.Enabled {
 		if j.Type != JobTypeBatch && j.Type != JobTypeSysBatch {
@@ -4490,6 +4817,68 @@ func (j *Job) LookupTaskGroup(name string) *TaskGroup {
 	return nil
 }
 
+// DependentTaskGroups returns the task groups of the job which depend on the
+// named task group.
+func (j *Job) DependentTaskGroups(name string) []*TaskGroup {
+	var dependents []*TaskGroup
+	for _, tg := range j.TaskGroups {
+		for _, dep := range tg.DependsOn {
+			if dep == name {
+				dependents = append(dependents, tg)
+				break
+			}
+		}
+	}
+	return dependents
+}
+
+// dependencyCycle returns the names of the task groups forming a cycle of
+// depends_on references, or nil if the dependencies are acyclic. References
+// to unknown task groups are ignored and reported by the task group
+// validation.
+func (j *Job) dependencyCycle() []string {
+	const (
+		visiting = iota + 1
+		visited
+	)
+	state := make(map[string]int, len(j.TaskGroups))
+	var path []string
+
+	var visit func(tg *TaskGroup) []string
+	visit = func(tg *TaskGroup) []string {
+		switch state[tg.Name] {
+		case visited:
+			return nil
+		case visiting:
+			for i, name := range path {
+				if name == tg.Name {
+					return append(helper.CopySliceString(path[i:]), tg.Name)
+				}
+			}
+		}
+
+		state[tg.Name] = visiting
+		path = append(path, tg.Name)
+		for _, name := range tg.DependsOn {
+			if dep := j.LookupTaskGroup(name); dep != nil && dep != tg {
+				if cycle := visit(dep); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[tg.Name] = visited
+		return nil
+	}
+
+	for _, tg := range j.TaskGroups {
+		if cycle := visit(tg); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
 // CombinedTaskMeta takes a TaskGroup and Task name and returns the combined
 // meta data for the task. When joining Job, Group and Task Meta, the precedence
 // is by deepest scope (Task > Group > Job).
@@ -4551,6 +4940,7 @@ func (j *Job) Stub(summary *JobSummary) *JobListStub {
 		ParentID:          j.ParentID,
 		Name:              j.Name,
 		Datacenters:       j.Datacenters,
+		NodePool:          j.NodePool,
 		Multiregion:       j.Multiregion,
 		Type:              j.Type,
 		Priority:          j.Priority,
@@ -4727,6 +5117,76 @@ func (j *Job) SetSubmitTime() {
 	j.SubmitTime = time.Now().UTC().UnixNano()
 }
 
+const (
+	// JobSubmissionFormatHCL1, JobSubmissionFormatHCL2 and
+	// JobSubmissionFormatJSON are the formats of the source a job was
+	// submitted from.
+	JobSubmissionFormatHCL1 = "hcl1"
+	JobSubmissionFormatHCL2 = "hcl2"
+	JobSubmissionFormatJSON = "json"
+)
+
+// JobSubmission is the original source a version of a job was parsed from,
+// along with the HCL2 variables used to render it. Submissions are stored in
+// their own table, keyed by the job version, so they do not bloat the jobs
+// table.
+type JobSubmission struct {
+	// Source is the jobspec as it was submitted.
+	Source string
+
+	// Format is the format of the source, one of hcl1, hcl2 or json.
+	Format string
+
+	// VariableFlags are the HCL2 variables set with the -var flag.
+	VariableFlags map[string]string
+
+	// Variables is the content of the HCL2 variable files.
+	Variables string
+
+	// Namespace, JobID and Version identify the job version the source was
+	// submitted for. They are set by the server.
+	Namespace string
+	JobID     string
+	Version   uint64
+
+	// JobModifyIndex is the index of the job registration.
+	JobModifyIndex uint64
+}
+
+// Size returns the number of bytes of source and variables stored by the
+// submission.
+func (js *JobSubmission) Size() int {
+	if js == nil {
+		return 0
+	}
+	size := len(js.Source) + len(js.Variables)
+	for k, v := range js.VariableFlags {
+		size += len(k) + len(v)
+	}
+	return size
+}
+
+// Validate returns an error if the submission format is unknown.
+func (js *JobSubmission) Validate() error {
+	switch js.Format {
+	case JobSubmissionFormatHCL1, JobSubmissionFormatHCL2, JobSubmissionFormatJSON:
+		return nil
+	default:
+		return fmt.Errorf("invalid job submission format %q", js.Format)
+	}
+}
+
+// Copy returns a deep copy of the submission. It handles nil objects.
+func (js *JobSubmission) Copy() *JobSubmission {
+	if js == nil {
+		return nil
+	}
+	c := new(JobSubmission)
+	*c = *js
+	c.VariableFlags = helper.CopyMapStringString(js.VariableFlags)
+	return c
+}
+
 // JobListStub is used to return a subset of job information
 // for the job list
 type JobListStub struct {
@@ -4735,6 +5195,7 @@ type JobListStub struct {
 	Name              string
 	Namespace         string `json:",omitempty"`
 	Datacenters       []string
+	NodePool          string
 	Multiregion       *Multiregion
 	Type              string
 	Priority          int
@@ -5029,6 +5490,14 @@ type Namespace struct {
 	// Meta is the set of metadata key/value pairs that attached to the namespace
 	Meta map[string]string
 
+	// NodePoolConfiguration restricts the node pools the jobs of the
+	// namespace may target.
+	NodePoolConfiguration *NamespaceNodePoolConfiguration
+
+	// PreemptionConfiguration limits the allocations the jobs of the
+	// namespace may preempt.
+	PreemptionConfiguration *NamespacePreemptionConfiguration
+
 	// Hash is the hash of the namespace which is used to efficiently replicate
 	// cross-regions.
 	Hash []byte
@@ -5045,6 +5514,49 @@ type NamespaceCapabilities struct {
 	DisabledTaskDrivers []string
 }
 
+// NamespacePreemptionConfiguration limits the allocations the jobs of a
+// namespace may preempt.
+type NamespacePreemptionConfiguration struct {
+	// MaxPriority is the priority ceiling used by the jobs of the namespace
+	// when preempting allocations: jobs with a higher priority preempt as if
+	// their priority was MaxPriority. Zero means there is no ceiling.
+	MaxPriority int
+}
+
+// Validate returns an error if the preemption configuration is invalid.
+func (c *NamespacePreemptionConfiguration) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.MaxPriority < 0 || c.MaxPriority > JobMaxPriority {
+		return fmt.Errorf("max priority must be between 0 and %d", JobMaxPriority)
+	}
+	return nil
+}
+
+// Copy returns a copy of the preemption configuration. It handles nil
+// objects.
+func (c *NamespacePreemptionConfiguration) Copy() *NamespacePreemptionConfiguration {
+	if c == nil {
+		return nil
+	}
+	nc := new(NamespacePreemptionConfiguration)
+	*nc = *c
+	return nc
+}
+
+// PreemptionPriority returns the priority a job of the namespace with the
+// given priority preempts allocations with.
+func (n *Namespace) PreemptionPriority(priority int) int {
+	if n == nil || n.PreemptionConfiguration == nil {
+		return priority
+	}
+	if ceiling := n.PreemptionConfiguration.MaxPriority; ceiling > 0 && priority > ceiling {
+		return ceiling
+	}
+	return priority
+}
+
 func (n *Namespace) Validate() error {
 	var mErr multierror.Error
 
@@ -5057,6 +5569,12 @@ func (n *Namespace) Validate() error {
 		err := fmt.Errorf("description longer than %d", maxNamespaceDescriptionLength)
 		mErr.Errors = append(mErr.Errors, err)
 	}
+	if err := n.NodePoolConfiguration.Validate(); err != nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid node pool configuration: %v", err))
+	}
+	if err := n.PreemptionConfiguration.Validate(); err != nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid preemption configuration: %v", err))
+	}
 
 	return mErr.ErrorOrNil()
 }
@@ -5081,6 +5599,18 @@ func (n *Namespace) SetHash() []byte {
 			_, _ = hash.Write([]byte(driver))
 		}
 	}
+	if n.NodePoolConfiguration != nil {
+		_, _ = hash.Write([]byte(n.NodePoolConfiguration.Default))
+		for _, pool := range n.NodePoolConfiguration.Allowed {
+			_, _ = hash.Write([]byte(pool))
+		}
+		for _, pool := range n.NodePoolConfiguration.Denied {
+			_, _ = hash.Write([]byte(pool))
+		}
+	}
+	if n.PreemptionConfiguration != nil {
+		_, _ = hash.Write([]byte(strconv.Itoa(n.PreemptionConfiguration.MaxPriority)))
+	}
 
 	// sort keys to ensure hash stability when meta is stored later
 	var keys []string
@@ -5113,6 +5643,8 @@ func (n *Namespace) Copy() *Namespace {
 		c.DisabledTaskDrivers = helper.CopySliceString(n.Capabilities.DisabledTaskDrivers)
 		nc.Capabilities = c
 	}
+	nc.NodePoolConfiguration = n.NodePoolConfiguration.Copy()
+	nc.PreemptionConfiguration = n.PreemptionConfiguration.Copy()
 	if n.Meta != nil {
 		nc.Meta = make(map[string]string, len(n.Meta))
 		for k, v := range n.Meta {
@@ -6116,6 +6648,34 @@ func (m *MigrateStrategy) Validate() error {
 	return mErr.ErrorOrNil()
 }
 
+// PreemptionPolicy limits the preemption of the allocations of a task group.
+type PreemptionPolicy struct {
+	// Preemptible is whether the allocations of the task group may be
+	// preempted at all.
+	Preemptible bool
+
+	// MaxDisruption is the maximum number of allocations of the task group
+	// which may be preempted and not yet replaced at any given time. Zero
+	// means there is no limit.
+	MaxDisruption int
+}
+
+func (p *PreemptionPolicy) Copy() *PreemptionPolicy {
+	if p == nil {
+		return nil
+	}
+	np := new(PreemptionPolicy)
+	*np = *p
+	return np
+}
+
+func (p *PreemptionPolicy) Validate() error {
+	if p.MaxDisruption < 0 {
+		return fmt.Errorf("MaxDisruption must be >= 0 but found %d", p.MaxDisruption)
+	}
+	return nil
+}
+
 // TaskGroup is an atomic unit of placement. Each task group belongs to
 // a job and may contain any number of tasks. A task group support running
 // in many replicas using the same configuration..
@@ -6189,6 +6749,92 @@ type TaskGroup struct {
 	// MaxClientDisconnect, if set, configures the client to allow placed
 	// allocations for tasks in this group to attempt to resume running without a restart.
 	MaxClientDisconnect *time.Duration
+
+	// Placement controls whether the scheduler may place a subset of the
+	// missing allocations of this task group. An empty value is treated as
+	// TaskGroupPlacementBestEffort.
+	Placement string
+
+	// Preemption limits how the allocations of this task group may be
+	// preempted by higher priority jobs. A nil value allows any number of
+	// allocations to be preempted.
+	Preemption *PreemptionPolicy
+
+	// DependsOn is the list of task groups of the job whose allocations must
+	// satisfy DependsOnCondition before this task group is placed.
+	DependsOn []string
+
+	// DependsOnCondition is the state the allocations of the task groups in
+	// DependsOn must reach. An empty value is treated as
+	// DependencyConditionRunning.
+	DependsOnCondition string
+}
+
+const (
+	// TaskGroupPlacementBestEffort places as many of the missing allocations
+	// of a task group as possible, and retries the rest when resources
+	// become available.
+	TaskGroupPlacementBestEffort = "best_effort"
+
+	// TaskGroupPlacementAllOrNothing only places the missing allocations of a
+	// task group if all of them can be placed at once, which is required for
+	// workloads such as MPI jobs whose allocations must start together.
+	TaskGroupPlacementAllOrNothing = "all_or_nothing"
+)
+
+const (
+	// DependencyConditionRunning is satisfied once the allocations of the
+	// task group are running.
+	DependencyConditionRunning = "running"
+
+	// DependencyConditionHealthy is satisfied once the allocations of the
+	// task group are running and have been marked healthy by a deployment.
+	DependencyConditionHealthy = "healthy"
+
+	// DependencyConditionComplete is satisfied once the allocations of the
+	// task group have completed successfully.
+	DependencyConditionComplete = "complete"
+)
+
+// IsAllOrNothing returns whether the missing allocations of the task group
+// must be placed all at once or not at all.
+func (tg *TaskGroup) IsAllOrNothing() bool {
+	return tg.Placement == TaskGroupPlacementAllOrNothing
+}
+
+// DependencyCondition returns the condition the allocations of the task
+// groups it depends on must satisfy.
+func (tg *TaskGroup) DependencyCondition() string {
+	if tg.DependsOnCondition == "" {
+		return DependencyConditionRunning
+	}
+	return tg.DependsOnCondition
+}
+
+// PreemptionBudget returns the number of allocations of the task group which
+// may still be preempted, given the number of its allocations which are not
+// terminal. Allocations missing from the task group count against the budget
+// since they may have been preempted and not replaced yet. It returns -1 if
+// the preemption of the task group is not limited.
+func (tg *TaskGroup) PreemptionBudget(live int) int {
+	if tg.Preemption == nil {
+		return -1
+	}
+	if !tg.Preemption.Preemptible {
+		return 0
+	}
+	if tg.Preemption.MaxDisruption == 0 {
+		return -1
+	}
+
+	disrupted := tg.Count - live
+	if disrupted < 0 {
+		disrupted = 0
+	}
+	if budget := tg.Preemption.MaxDisruption - disrupted; budget > 0 {
+		return budget
+	}
+	return 0
 }
 
 func (tg *TaskGroup) Copy() *TaskGroup {
@@ -6206,6 +6852,8 @@ func (tg *TaskGroup) Copy() *TaskGroup {
 	ntg.Volumes = CopyMapVolumeRequest(ntg.Volumes)
 	ntg.Scaling = ntg.Scaling.Copy()
 	ntg.Consul = ntg.Consul.Copy()
+	ntg.Preemption = ntg.Preemption.Copy()
+	ntg.DependsOn = helper.CopySliceString(ntg.DependsOn)
 
 	// Copy the network objects
 	if tg.Networks != nil {
@@ -6320,6 +6968,27 @@ func (tg *TaskGroup) Validate(j *Job) error {
 		mErr.Errors = append(mErr.Errors, errors.New("max_client_disconnect cannot be negative"))
 	}
 
+	if tg.Preemption != nil {
+		if err := tg.Preemption.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
+	}
+
+	switch tg.Placement {
+	case "", TaskGroupPlacementBestEffort:
+	case TaskGroupPlacementAllOrNothing:
+		if j.Type != JobTypeBatch {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Placement %q is only supported by batch jobs", tg.Placement))
+		}
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Invalid placement %q, must be one of %q or %q",
+			tg.Placement, TaskGroupPlacementBestEffort, TaskGroupPlacementAllOrNothing))
+	}
+
+	if err := tg.validateDependencies(j); err != nil {
+		mErr.Errors = append(mErr.Errors, err)
+	}
+
 	for idx, constr := range tg.Constraints {
 		if err := constr.Validate(); err != nil {
 			outer := fmt.Errorf("Constraint %d validation failed: %s", idx+1, err)
@@ -6488,6 +7157,51 @@ func (tg *TaskGroup) Validate(j *Job) error {
 	return mErr.ErrorOrNil()
 }
 
+// validateDependencies checks that the task groups the task group depends on
+// exist in the job and that the dependency condition is supported by the job.
+func (tg *TaskGroup) validateDependencies(j *Job) error {
+	if len(tg.DependsOn) == 0 && tg.DependsOnCondition == "" {
+		return nil
+	}
+
+	var mErr multierror.Error
+	if j.Type == JobTypeSystem || j.Type == JobTypeSysBatch {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("depends_on is not supported by %s jobs", j.Type))
+	}
+
+	seen := make(map[string]struct{}, len(tg.DependsOn))
+	for _, name := range tg.DependsOn {
+		if _, ok := seen[name]; ok {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("depends_on lists task group %q more than once", name))
+			continue
+		}
+		seen[name] = struct{}{}
+
+		if name == tg.Name {
+			mErr.Errors = append(mErr.Errors, errors.New("depends_on cannot reference its own task group"))
+		} else if j.LookupTaskGroup(name) == nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("depends_on references unknown task group %q", name))
+		}
+	}
+
+	switch tg.DependsOnCondition {
+	case "", DependencyConditionRunning:
+	case DependencyConditionHealthy:
+		if j.Type != JobTypeService {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("depends_on_condition %q is only supported by service jobs", tg.DependsOnCondition))
+		}
+	case DependencyConditionComplete:
+		if j.Type != JobTypeBatch {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("depends_on_condition %q is only supported by batch jobs", tg.DependsOnCondition))
+		}
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Invalid depends_on_condition %q, must be one of %q, %q or %q",
+			tg.DependsOnCondition, DependencyConditionRunning, DependencyConditionHealthy, DependencyConditionComplete))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
 func (tg *TaskGroup) validateNetworks() error {
 	var mErr multierror.Error
 	portLabels := make(map[string]string)
@@ -6847,10 +7561,27 @@ const (
 	DefaultKillTimeout = 5 * time.Second
 )
 
-// LogConfig provides configuration for log rotation
+// LogConfig provides configuration for log rotation and the sinks the
+// output of a task is shipped to
 type LogConfig struct {
 	MaxFiles      int
 	MaxFileSizeMB int
+
+	// MaxFileAge is the age after which a log file is rotated even if it
+	// hasn't reached MaxFileSizeMB. Zero disables time based rotation.
+	MaxFileAge time.Duration
+
+	// Compress enables the gzip compression of rotated log files
+	Compress bool
+
+	// MaxAllocSizeMB caps the total size of the log files of all the tasks of
+	// the allocation, removing the oldest rotated files first. Zero disables
+	// the cap.
+	MaxAllocSizeMB int
+
+	// Sinks are the destinations of the output of the task. If empty, the
+	// output is only written to rotated files in the log directory.
+	Sinks []*LogSink
 }
 
 func (l *LogConfig) Equals(o *LogConfig) bool {
@@ -6866,6 +7597,27 @@ func (l *LogConfig) Equals(o *LogConfig) bool {
 		return false
 	}
 
+	if l.MaxFileAge != o.MaxFileAge {
+		return false
+	}
+
+	if l.Compress != o.Compress {
+		return false
+	}
+
+	if l.MaxAllocSizeMB != o.MaxAllocSizeMB {
+		return false
+	}
+
+	if len(l.Sinks) != len(o.Sinks) {
+		return false
+	}
+	for i, sink := range l.Sinks {
+		if !sink.Equals(o.Sinks[i]) {
+			return false
+		}
+	}
+
 	return true
 }
 
@@ -6873,12 +7625,25 @@ func (l *LogConfig) Copy() *LogConfig {
 	if l == nil {
 		return nil
 	}
-	return &LogConfig{
-		MaxFiles:      l.MaxFiles,
-		MaxFileSizeMB: l.MaxFileSizeMB,
+	nl := &LogConfig{
+		MaxFiles:       l.MaxFiles,
+		MaxFileSizeMB:  l.MaxFileSizeMB,
+		MaxFileAge:     l.MaxFileAge,
+		Compress:       l.Compress,
+		MaxAllocSizeMB: l.MaxAllocSizeMB,
+	}
+	if l.Sinks != nil {
+		nl.Sinks = make([]*LogSink, len(l.Sinks))
+		for i, sink := range l.Sinks {
+			nl.Sinks[i] = sink.Copy()
+		}
 	}
+	return nl
 }
 
+// MinLogFileAge is the minimum age after which log files can be rotated.
+const MinLogFileAge = time.Minute
+
 // DefaultLogConfig returns the default LogConfig values.
 func DefaultLogConfig() *LogConfig {
 	return &LogConfig{
@@ -6887,6 +7652,21 @@ func DefaultLogConfig() *LogConfig {
 	}
 }
 
+// WritesFiles returns whether the output of the task is written to rotated
+// files in the log directory, which is the case when no sinks are configured
+// or one of them is a file sink.
+func (l *LogConfig) WritesFiles() bool {
+	if len(l.Sinks) == 0 {
+		return true
+	}
+	for _, sink := range l.Sinks {
+		if sink.Type == LogSinkTypeFile {
+			return true
+		}
+	}
+	return false
+}
+
 // Validate returns an error if the log config specified are less than
 // the minimum allowed.
 func (l *LogConfig) Validate() error {
@@ -6897,6 +7677,151 @@ func (l *LogConfig) Validate() error {
 	if l.MaxFileSizeMB < 1 {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("minimum file size is 1MB; got %d", l.MaxFileSizeMB))
 	}
+	if l.MaxFileAge != 0 && l.MaxFileAge < MinLogFileAge {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("minimum file age is %v; got %v", MinLogFileAge, l.MaxFileAge))
+	}
+	if l.MaxAllocSizeMB < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("max alloc size must be positive; got %d", l.MaxAllocSizeMB))
+	} else if l.MaxAllocSizeMB > 0 && l.MaxAllocSizeMB < l.MaxFileSizeMB {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("max alloc size (%d MB) must be at least the max file size (%d MB)",
+			l.MaxAllocSizeMB, l.MaxFileSizeMB))
+	}
+
+	var files int
+	for idx, sink := range l.Sinks {
+		if sink.Type == LogSinkTypeFile {
+			files++
+		}
+		if err := sink.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Sink %d validation failed: %v", idx+1, err))
+		}
+	}
+	if files > 1 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("only one file sink is allowed; got %d", files))
+	}
+	return mErr.ErrorOrNil()
+}
+
+const (
+	// LogSinkTypeFile writes the output of the task to rotated files in the
+	// log directory, from which it is served by the logs API.
+	LogSinkTypeFile = "file"
+
+	// LogSinkTypeSyslog ships the output of the task to a syslog server.
+	LogSinkTypeSyslog = "syslog"
+
+	// LogSinkTypeFluentForward ships the output of the task to a Fluentd or
+	// Fluent Bit forward input.
+	LogSinkTypeFluentForward = "fluent-forward"
+
+	// LogSinkOverflowDrop drops the lines written while the buffer of a sink
+	// is full.
+	LogSinkOverflowDrop = "drop"
+
+	// LogSinkOverflowBlock stops reading the output of the task while the
+	// buffer of a sink is full, which blocks the task once its pipes fill up.
+	LogSinkOverflowBlock = "block"
+)
+
+// validLogSinkFacilities are the syslog facilities a sink can log to.
+var validLogSinkFacilities = []string{
+	"kern", "user", "mail", "daemon", "auth", "syslog", "lpr", "news",
+	"uucp", "cron", "authpriv", "ftp", "local0", "local1", "local2",
+	"local3", "local4", "local5", "local6", "local7",
+}
+
+// LogSink is a destination the output of a task is shipped to. Each line is
+// shipped along with the allocation, job, task and stream it was written by.
+type LogSink struct {
+	// Type is the type of the sink.
+	Type string
+
+	// Address of the destination. Syslog addresses are URLs with the udp,
+	// tcp, unix or unixgram scheme and default to the local syslog socket.
+	// Fluent forward addresses are host:port pairs.
+	Address string
+
+	// Tag is the syslog application name or the Fluent tag of the lines.
+	Tag string
+
+	// Facility is the syslog facility of the lines.
+	Facility string
+
+	// BufferSize is the number of lines buffered while the destination is
+	// slow or unreachable.
+	BufferSize int
+
+	// Overflow is what happens to the lines written while the buffer is
+	// full, either "drop" or "block".
+	Overflow string
+}
+
+func (s *LogSink) Equals(o *LogSink) bool {
+	if s == nil || o == nil {
+		return s == o
+	}
+	return *s == *o
+}
+
+func (s *LogSink) Copy() *LogSink {
+	if s == nil {
+		return nil
+	}
+	ns := new(LogSink)
+	*ns = *s
+	return ns
+}
+
+// Validate returns an error if the sink is invalid.
+func (s *LogSink) Validate() error {
+	var mErr multierror.Error
+
+	switch s.Type {
+	case LogSinkTypeFile:
+		// The file sink is configured by the log config itself
+		if s.Address != "" || s.Tag != "" || s.Facility != "" || s.BufferSize != 0 || s.Overflow != "" {
+			mErr.Errors = append(mErr.Errors, errors.New("file sinks do not accept any configuration"))
+		}
+		return mErr.ErrorOrNil()
+	case LogSinkTypeSyslog:
+		if s.Address != "" {
+			u, err := url.Parse(s.Address)
+			if err != nil {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid syslog address %q: %v", s.Address, err))
+			} else {
+				switch u.Scheme {
+				case "udp", "tcp", "unix", "unixgram":
+				default:
+					mErr.Errors = append(mErr.Errors, fmt.Errorf("syslog address scheme must be one of udp, tcp, unix or unixgram; got %q", u.Scheme))
+				}
+			}
+		}
+		if s.Facility != "" && !helper.SliceStringContains(validLogSinkFacilities, s.Facility) {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid syslog facility %q", s.Facility))
+		}
+	case LogSinkTypeFluentForward:
+		if s.Address != "" {
+			if _, _, err := net.SplitHostPort(s.Address); err != nil {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid fluent forward address %q: %v", s.Address, err))
+			}
+		}
+		if s.Facility != "" {
+			mErr.Errors = append(mErr.Errors, errors.New("facility is only supported by syslog sinks"))
+		}
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("unknown sink type %q", s.Type))
+		return mErr.ErrorOrNil()
+	}
+
+	if s.BufferSize < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("buffer size must be positive; got %d", s.BufferSize))
+	}
+	switch s.Overflow {
+	case "", LogSinkOverflowDrop, LogSinkOverflowBlock:
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("overflow must be %q or %q; got %q",
+			LogSinkOverflowDrop, LogSinkOverflowBlock, s.Overflow))
+	}
 	return mErr.ErrorOrNil()
 }
 
@@ -6987,6 +7912,10 @@ type Task struct {
 
 	// CSIPluginConfig is used to configure the plugin supervisor for the task.
 	CSIPluginConfig *TaskCSIPluginConfig
+
+	// Identity controls if and how the workload identity is exposed to
+	// the task.
+	Identity *WorkloadIdentity
 }
 
 // UsesConnect is for conveniently detecting if the Task is able to make use
@@ -7021,6 +7950,7 @@ func (t *Task) Copy() *Task {
 	nt.Affinities = CopySliceAffinities(nt.Affinities)
 	nt.VolumeMounts = CopySliceVolumeMount(nt.VolumeMounts)
 	nt.CSIPluginConfig = nt.CSIPluginConfig.Copy()
+	nt.Identity = nt.Identity.Copy()
 
 	nt.Vault = nt.Vault.Copy()
 	nt.Resources = nt.Resources.Copy()
@@ -7169,8 +8099,11 @@ func (t *Task) Validate(ephemeralDisk *EphemeralDisk, jobType string, tgServices
 		mErr.Errors = append(mErr.Errors, err)
 	}
 
-	if t.LogConfig != nil && ephemeralDisk != nil {
+	if t.LogConfig != nil && t.LogConfig.WritesFiles() && ephemeralDisk != nil {
 		logUsage := (t.LogConfig.MaxFiles * t.LogConfig.MaxFileSizeMB)
+		if t.LogConfig.MaxAllocSizeMB > 0 && t.LogConfig.MaxAllocSizeMB < logUsage {
+			logUsage = t.LogConfig.MaxAllocSizeMB
+		}
 		if ephemeralDisk.SizeMB <= logUsage {
 			mErr.Errors = append(mErr.Errors,
 				fmt.Errorf("log storage (%d MB) must be less than requested disk capacity (%d MB)",
@@ -8012,6 +8945,19 @@ const (
 
 	// TaskClientReconnected indicates that the client running the task disconnected.
 	TaskClientReconnected = "Reconnected"
+
+	// TaskCheckpointed indicates that the task was checkpointed before being
+	// stopped so that it can be restored by the replacement allocation.
+	TaskCheckpointed = "Checkpointed"
+
+	// TaskCheckpointFailed indicates that the task could not be checkpointed,
+	// in which case it is killed, or restored from its checkpoint, in which
+	// case it is started.
+	TaskCheckpointFailed = "Checkpoint Failed"
+
+	// TaskRestoredFromCheckpoint indicates that the task was restored from
+	// the checkpoint of the previous allocation.
+	TaskRestoredFromCheckpoint = "Restored From Checkpoint"
 )
 
 // TaskEvent is an event that effects the state of a task and contains meta-data
@@ -8225,6 +9171,10 @@ func (e *TaskEvent) PopulateEventDisplayMessage() {
 		desc = "Main tasks in the group died"
 	case TaskClientReconnected:
 		desc = "Client reconnected"
+	case TaskCheckpointed:
+		desc = "Task checkpointed for migration"
+	case TaskRestoredFromCheckpoint:
+		desc = "Task restored from the checkpoint of the previous allocation"
 	default:
 		desc = e.Message
 	}
@@ -8795,6 +9745,12 @@ type Spread struct {
 	// SpreadTarget is used to describe desired percentages for each attribute value
 	SpreadTarget []*SpreadTarget
 
+	// MaxSkew is the maximum difference allowed between the number of
+	// allocations of the task group on any two values of the attribute. When
+	// set, nodes on which a placement would exceed it are infeasible rather
+	// than merely scored lower.
+	MaxSkew int
+
 	// Memoized string representation
 	str string
 }
@@ -8840,6 +9796,9 @@ func (s *Spread) String() string {
 		return s.str
 	}
 	s.str = fmt.Sprintf("%s %s %v", s.Attribute, s.SpreadTarget, s.Weight)
+	if s.MaxSkew > 0 {
+		s.str += fmt.Sprintf(" max_skew=%d", s.MaxSkew)
+	}
 	return s.str
 }
 
@@ -8851,6 +9810,11 @@ func (s *Spread) Validate() error {
 	if s.Weight <= 0 || s.Weight > 100 {
 		mErr.Errors = append(mErr.Errors, errors.New("Spread stanza must have a positive weight from 0 to 100"))
 	}
+	if s.MaxSkew < 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("Spread max_skew must not be negative"))
+	} else if s.MaxSkew > 0 && len(s.SpreadTarget) > 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("Spread max_skew cannot be used with targets"))
+	}
 	seen := make(map[string]struct{})
 	sumPercent := uint32(0)
 
@@ -9398,6 +10362,11 @@ type DesiredTransition struct {
 	// task shutdown_delay configuration and ignore the delay for any
 	// allocations stopped as a result of this Deregister call.
 	NoShutdownDelay *bool
+
+	// Checkpoint is used to indicate that the tasks of a migrated allocation
+	// should be checkpointed when stopped, so that they can be restored on
+	// the node of the replacement allocation.
+	Checkpoint *bool
 }
 
 // Merge merges the two desired transitions, preferring the values from the
@@ -9418,6 +10387,10 @@ func (d *DesiredTransition) Merge(o *DesiredTransition) {
 	if o.NoShutdownDelay != nil {
 		d.NoShutdownDelay = o.NoShutdownDelay
 	}
+
+	if o.Checkpoint != nil {
+		d.Checkpoint = o.Checkpoint
+	}
 }
 
 // ShouldMigrate returns whether the transition object dictates a migration.
@@ -9449,6 +10422,15 @@ func (d *DesiredTransition) ShouldIgnoreShutdownDelay() bool {
 	return d.NoShutdownDelay != nil && *d.NoShutdownDelay
 }
 
+// ShouldCheckpoint returns whether the transition object dictates that the
+// tasks of a migrated allocation are checkpointed.
+func (d *DesiredTransition) ShouldCheckpoint() bool {
+	if d == nil {
+		return false
+	}
+	return d.ShouldMigrate() && d.Checkpoint != nil && *d.Checkpoint
+}
+
 const (
 	AllocDesiredStatusRun   = "run"   // Allocation should run
 	AllocDesiredStatusStop  = "stop"  // Allocation should stop
@@ -9540,6 +10522,15 @@ type Allocation struct {
 	// TaskStates stores the state of each task,
 	TaskStates map[string]*TaskState
 
+	// SignedIdentities is a map of task names to the signed workload
+	// identity of each task. It is populated by the plan applier and must
+	// never be exposed via the HTTP API.
+	SignedIdentities map[string]string `json:"-"`
+
+	// SigningKeyID is the ID of the root key used to sign the
+	// SignedIdentities.
+	SigningKeyID string
+
 	// AllocStates track meta data associated with changes to the state of the whole allocation, like becoming lost
 	AllocStates []*AllocState
 
@@ -9718,6 +10709,7 @@ func (a *Allocation) copyImpl(job bool) *Allocation {
 
 	na.RescheduleTracker = a.RescheduleTracker.Copy()
 	na.PreemptedAllocations = helper.CopySliceString(a.PreemptedAllocations)
+	na.SignedIdentities = helper.CopyMapStringString(a.SignedIdentities)
 	return na
 }
 
@@ -10020,6 +11012,25 @@ func (a *Allocation) Terminated() bool {
 	return false
 }
 
+// SatisfiesDependency returns whether the allocation has reached the given
+// task group dependency condition.
+func (a *Allocation) SatisfiesDependency(condition string) bool {
+	return satisfiesDependency(a.ClientStatus, a.DeploymentStatus, condition)
+}
+
+// satisfiesDependency returns whether an allocation with the given client and
+// deployment status has reached the task group dependency condition.
+func satisfiesDependency(clientStatus string, deploymentStatus *AllocDeploymentStatus, condition string) bool {
+	switch condition {
+	case DependencyConditionHealthy:
+		return clientStatus == AllocClientStatusRunning && deploymentStatus.IsHealthy()
+	case DependencyConditionComplete:
+		return clientStatus == AllocClientStatusComplete
+	default:
+		return clientStatus == AllocClientStatusRunning
+	}
+}
+
 // SetStop updates the allocation in place to a DesiredStatus stop, with the ClientStatus
 func (a *Allocation) SetStop(clientStatus, clientDesc string) {
 	a.DesiredStatus = AllocDesiredStatusStop
@@ -10311,6 +11322,12 @@ func (a *AllocListStub) SetEventDisplayMessages() {
 	setDisplayMsg(a.TaskStates)
 }
 
+// SatisfiesDependency returns whether the allocation has reached the given
+// task group dependency condition.
+func (a *AllocListStub) SatisfiesDependency(condition string) bool {
+	return satisfiesDependency(a.ClientStatus, a.DeploymentStatus, condition)
+}
+
 func setDisplayMsg(taskStates map[string]*TaskState) {
 	for _, taskState := range taskStates {
 		for _, event := range taskState.Events {
@@ -10369,6 +11386,14 @@ type AllocMetric struct {
 	// QuotaExhausted provides the exhausted dimensions
 	QuotaExhausted []string
 
+	// PreemptionRejected provides the count of exhausted nodes on which
+	// preemption could not free enough resources, by reason
+	PreemptionRejected map[string]int
+
+	// SpreadSkewExceeded is the number of nodes filtered because a placement
+	// would exceed the max skew of a spread, by spread attribute
+	SpreadSkewExceeded map[string]int
+
 	// ResourcesExhausted provides the amount of resources exhausted by task
 	// during the allocation placement
 	ResourcesExhausted map[string]*Resources
@@ -10412,6 +11437,8 @@ func (a *AllocMetric) Copy() *AllocMetric {
 	na.ClassExhausted = helper.CopyMapStringInt(na.ClassExhausted)
 	na.DimensionExhausted = helper.CopyMapStringInt(na.DimensionExhausted)
 	na.QuotaExhausted = helper.CopySliceString(na.QuotaExhausted)
+	na.PreemptionRejected = helper.CopyMapStringInt(na.PreemptionRejected)
+	na.SpreadSkewExceeded = helper.CopyMapStringInt(na.SpreadSkewExceeded)
 	na.Scores = helper.CopyMapStringFloat64(na.Scores)
 	na.ScoreMetaData = CopySliceNodeScoreMeta(na.ScoreMetaData)
 	return na
@@ -10453,6 +11480,25 @@ func (a *AllocMetric) ExhaustedNode(node *Node, dimension string) {
 	}
 }
 
+// RejectPreemption records why no allocation could be preempted on a node
+// to make room for the placement.
+func (a *AllocMetric) RejectPreemption(reason string) {
+	if a.PreemptionRejected == nil {
+		a.PreemptionRejected = make(map[string]int)
+	}
+	a.PreemptionRejected[reason] += 1
+}
+
+// ExceedSpreadSkew records that the node was filtered because placing on it
+// would exceed the max skew of the spread over attribute.
+func (a *AllocMetric) ExceedSpreadSkew(node *Node, attribute string) {
+	a.FilterNode(node, "")
+	if a.SpreadSkewExceeded == nil {
+		a.SpreadSkewExceeded = make(map[string]int)
+	}
+	a.SpreadSkewExceeded[attribute] += 1
+}
+
 func (a *AllocMetric) ExhaustQuota(dimensions []string) {
 	if a.QuotaExhausted == nil {
 		a.QuotaExhausted = make([]string, 0, len(dimensions))
@@ -10688,6 +11734,8 @@ const (
 	EvalTriggerScaling              = "job-scaling"
 	EvalTriggerMaxDisconnectTimeout = "max-disconnect-timeout"
 	EvalTriggerReconnect            = "reconnect"
+	EvalTriggerRebalance            = "rebalance"
+	EvalTriggerDependency           = "task-group-dependency"
 )
 
 const (
@@ -10727,6 +11775,22 @@ const (
 	// tokens. We periodically scan for expired tokens and delete them.
 	CoreJobOneTimeTokenGC = "one-time-token-gc"
 
+	// CoreJobLocalTokenExpiredGC is used for the garbage collection of
+	// expired local ACL tokens. We periodically scan for expired tokens and
+	// delete them.
+	CoreJobLocalTokenExpiredGC = "local-token-expired-gc"
+
+	// CoreJobGlobalTokenExpiredGC is used for the garbage collection of
+	// expired global ACL tokens. We periodically scan for expired tokens and
+	// delete them. This only runs within the authoritative region.
+	CoreJobGlobalTokenExpiredGC = "global-token-expired-gc"
+
+	// CoreJobRebalance is used to rebalance the cluster. We periodically
+	// scan the running allocations of service jobs and migrate the ones
+	// placed on skewed spreads, overly dense nodes or nodes violating their
+	// affinities.
+	CoreJobRebalance = "rebalance"
+
 	// CoreJobForceGC is used to force garbage collection of all GCable objects.
 	CoreJobForceGC = "force-gc"
 )
@@ -11327,6 +12391,57 @@ func (p *Plan) PopUpdate(alloc *Allocation) {
 	}
 }
 
+// RemoveUpdate removes the stop or eviction of the allocation from the plan.
+func (p *Plan) RemoveUpdate(alloc *Allocation) {
+	existing := p.NodeUpdate[alloc.NodeID]
+	for i, update := range existing {
+		if update.ID != alloc.ID {
+			continue
+		}
+		existing = append(existing[:i:i], existing[i+1:]...)
+		if len(existing) > 0 {
+			p.NodeUpdate[alloc.NodeID] = existing
+		} else {
+			delete(p.NodeUpdate, alloc.NodeID)
+		}
+		return
+	}
+}
+
+// RemoveAlloc removes the placement of the allocation from the plan, along
+// with the preemptions it required.
+func (p *Plan) RemoveAlloc(alloc *Allocation) {
+	existing := p.NodeAllocation[alloc.NodeID]
+	for i, placed := range existing {
+		if placed.ID != alloc.ID {
+			continue
+		}
+		existing = append(existing[:i:i], existing[i+1:]...)
+		if len(existing) > 0 {
+			p.NodeAllocation[alloc.NodeID] = existing
+		} else {
+			delete(p.NodeAllocation, alloc.NodeID)
+		}
+		break
+	}
+
+	preempted := p.NodePreemptions[alloc.NodeID]
+	if len(preempted) == 0 {
+		return
+	}
+	remaining := make([]*Allocation, 0, len(preempted))
+	for _, preemptedAlloc := range preempted {
+		if preemptedAlloc.PreemptedByAllocation != alloc.ID {
+			remaining = append(remaining, preemptedAlloc)
+		}
+	}
+	if len(remaining) > 0 {
+		p.NodePreemptions[alloc.NodeID] = remaining
+	} else {
+		delete(p.NodePreemptions, alloc.NodeID)
+	}
+}
+
 // AppendAlloc appends the alloc to the plan allocations.
 // Uses the passed job if explicitly passed, otherwise
 // it is assumed the alloc will use the plan Job version.
@@ -11429,6 +12544,23 @@ type PlanAnnotations struct {
 
 	// PreemptedAllocs is the set of allocations to be preempted to make the placement successful.
 	PreemptedAllocs []*AllocListStub
+
+	// SpreadViolations is the set of spreads whose max skew would still be
+	// exceeded once the plan is applied.
+	SpreadViolations []*SpreadViolation
+}
+
+// SpreadViolation describes a spread of a task group whose allocations are
+// distributed over the values of the spread attribute with a larger skew than
+// allowed.
+type SpreadViolation struct {
+	TaskGroup string
+	Attribute string
+
+	// Skew is the difference between the number of allocations on the most
+	// and the least used values of the attribute
+	Skew    int
+	MaxSkew int
 }
 
 // DesiredUpdates is the set of changes the scheduler would like to make given
@@ -11707,14 +12839,27 @@ type ACLPolicyUpsertRequest struct {
 
 // ACLToken represents a client token which is used to Authenticate
 type ACLToken struct {
-	AccessorID  string   // Public Accessor ID (UUID)
-	SecretID    string   // Secret ID, private (UUID)
-	Name        string   // Human friendly name
-	Type        string   // Client or Management
-	Policies    []string // Policies this token ties to
-	Global      bool     // Global or Region local
-	Hash        []byte
-	CreateTime  time.Time // Time of creation
+	AccessorID string              // Public Accessor ID (UUID)
+	SecretID   string              // Secret ID, private (UUID)
+	Name       string              // Human friendly name
+	Type       string              // Client or Management
+	Policies   []string            // Policies this token ties to
+	Roles      []*ACLTokenRoleLink // Roles this token ties to
+	Global     bool                // Global or Region local
+	Hash       []byte
+	CreateTime time.Time // Time of creation
+
+	// ExpirationTime represents the point after which a token should be
+	// considered revoked and is eligible for destruction. This time should
+	// always use UTC to account for multi-region global tokens. A nil value
+	// indicates the token does not expire.
+	ExpirationTime *time.Time
+
+	// ExpirationTTL is a convenience field for helping set ExpirationTime to
+	// a value of CreateTime+ExpirationTTL. This can only be set during token
+	// creation.
+	ExpirationTTL time.Duration
+
 	CreateIndex uint64
 	ModifyIndex uint64
 }
@@ -11745,6 +12890,19 @@ func (a *ACLToken) Copy() *ACLToken {
 	c.Hash = make([]byte, len(a.Hash))
 	copy(c.Hash, a.Hash)
 
+	if a.Roles != nil {
+		c.Roles = make([]*ACLTokenRoleLink, len(a.Roles))
+		for i, roleLink := range a.Roles {
+			link := *roleLink
+			c.Roles[i] = &link
+		}
+	}
+
+	if a.ExpirationTime != nil {
+		expirationTime := *a.ExpirationTime
+		c.ExpirationTime = &expirationTime
+	}
+
 	return c
 }
 
@@ -11761,15 +12919,17 @@ var (
 )
 
 type ACLTokenListStub struct {
-	AccessorID  string
-	Name        string
-	Type        string
-	Policies    []string
-	Global      bool
-	Hash        []byte
-	CreateTime  time.Time
-	CreateIndex uint64
-	ModifyIndex uint64
+	AccessorID     string
+	Name           string
+	Type           string
+	Policies       []string
+	Roles          []*ACLTokenRoleLink
+	Global         bool
+	Hash           []byte
+	CreateTime     time.Time
+	ExpirationTime *time.Time
+	CreateIndex    uint64
+	ModifyIndex    uint64
 }
 
 // SetHash is used to compute and set the hash of the ACL token
@@ -11786,6 +12946,9 @@ func (a *ACLToken) SetHash() []byte {
 	for _, policyName := range a.Policies {
 		_, _ = hash.Write([]byte(policyName))
 	}
+	for _, roleLink := range a.Roles {
+		_, _ = hash.Write([]byte(roleLink.ID))
+	}
 	if a.Global {
 		_, _ = hash.Write([]byte("global"))
 	} else {
@@ -11802,18 +12965,64 @@ func (a *ACLToken) SetHash() []byte {
 
 func (a *ACLToken) Stub() *ACLTokenListStub {
 	return &ACLTokenListStub{
-		AccessorID:  a.AccessorID,
-		Name:        a.Name,
-		Type:        a.Type,
-		Policies:    a.Policies,
-		Global:      a.Global,
-		Hash:        a.Hash,
-		CreateTime:  a.CreateTime,
-		CreateIndex: a.CreateIndex,
-		ModifyIndex: a.ModifyIndex,
+		AccessorID:     a.AccessorID,
+		Name:           a.Name,
+		Type:           a.Type,
+		Policies:       a.Policies,
+		Roles:          a.Roles,
+		Global:         a.Global,
+		Hash:           a.Hash,
+		CreateTime:     a.CreateTime,
+		ExpirationTime: a.ExpirationTime,
+		CreateIndex:    a.CreateIndex,
+		ModifyIndex:    a.ModifyIndex,
+	}
+}
+
+// Canonicalize sets the expiration time of a new token which was created
+// with an expiration TTL. It must be called once the create time of the
+// token has been set.
+func (a *ACLToken) Canonicalize() {
+	if a.ExpirationTTL != 0 && a.ExpirationTime == nil {
+		expirationTime := a.CreateTime.Add(a.ExpirationTTL)
+		a.ExpirationTime = &expirationTime
 	}
 }
 
+// HasExpirationTime checks whether the token has an expiration time set.
+func (a *ACLToken) HasExpirationTime() bool {
+	return a != nil && a.ExpirationTime != nil && !a.ExpirationTime.IsZero()
+}
+
+// ValidateExpiration checks that the expiration of a new token falls within
+// the passed bounds, relative to the create time of the token. Tokens
+// without an expiration are always valid.
+func (a *ACLToken) ValidateExpiration(minTTL, maxTTL time.Duration) error {
+	if a.ExpirationTTL < 0 {
+		return fmt.Errorf("token expiration TTL cannot be negative")
+	}
+	if !a.HasExpirationTime() {
+		return nil
+	}
+	if a.ExpirationTime.Before(a.CreateTime.Add(minTTL)) {
+		return fmt.Errorf("expiration time cannot be less than %s in the future", minTTL)
+	}
+	if a.ExpirationTime.After(a.CreateTime.Add(maxTTL)) {
+		return fmt.Errorf("expiration time cannot be more than %s in the future", maxTTL)
+	}
+	return nil
+}
+
+// IsExpired compares the token expiration time to the passed time and
+// returns whether the token has expired. Tokens without an expiration time
+// never expire.
+func (a *ACLToken) IsExpired(t time.Time) bool {
+	if !a.HasExpirationTime() {
+		return false
+	}
+	return a.ExpirationTime.Before(t)
+}
+
 // Validate is used to check a token for reasonableness
 func (a *ACLToken) Validate() error {
 	var mErr multierror.Error
@@ -11822,13 +13031,16 @@ func (a *ACLToken) Validate() error {
 	}
 	switch a.Type {
 	case ACLClientToken:
-		if len(a.Policies) == 0 {
-			mErr.Errors = append(mErr.Errors, fmt.Errorf("client token missing policies"))
+		if len(a.Policies) == 0 && len(a.Roles) == 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("client token missing policies or roles"))
 		}
 	case ACLManagementToken:
 		if len(a.Policies) != 0 {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("management token cannot be associated with policies"))
 		}
+		if len(a.Roles) != 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("management token cannot be associated with roles"))
+		}
 	default:
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("token type must be client or management"))
 	}
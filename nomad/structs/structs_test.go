@@ -1295,6 +1295,197 @@ func TestTaskGroup_Validate(t *testing.T) {
 	require.Contains(t, err.Error(), expected)
 }
 
+func TestTaskGroup_Validate_Placement(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		jobType   string
+		placement string
+		err       string
+	}{
+		{JobTypeBatch, "", ""},
+		{JobTypeBatch, TaskGroupPlacementBestEffort, ""},
+		{JobTypeBatch, TaskGroupPlacementAllOrNothing, ""},
+		{JobTypeService, TaskGroupPlacementBestEffort, ""},
+		{JobTypeService, TaskGroupPlacementAllOrNothing, "only supported by batch jobs"},
+		{JobTypeBatch, "some", "Invalid placement"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.jobType+"/"+tc.placement, func(t *testing.T) {
+			j := testJob()
+			j.Type = tc.jobType
+			tg := j.TaskGroups[0]
+			tg.Placement = tc.placement
+
+			err := tg.Validate(j)
+			if tc.err == "" {
+				if err != nil {
+					require.NotContains(t, err.Error(), "lacement")
+				}
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+			}
+		})
+	}
+}
+
+func TestTaskGroup_Validate_DependsOn(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name      string
+		jobType   string
+		dependsOn []string
+		condition string
+		err       string
+	}{
+		{"none", JobTypeService, nil, "", ""},
+		{"running", JobTypeService, []string{"db"}, DependencyConditionRunning, ""},
+		{"default condition", JobTypeBatch, []string{"db"}, "", ""},
+		{"healthy service", JobTypeService, []string{"db"}, DependencyConditionHealthy, ""},
+		{"healthy batch", JobTypeBatch, []string{"db"}, DependencyConditionHealthy, "only supported by service jobs"},
+		{"complete batch", JobTypeBatch, []string{"db"}, DependencyConditionComplete, ""},
+		{"complete service", JobTypeService, []string{"db"}, DependencyConditionComplete, "only supported by batch jobs"},
+		{"invalid condition", JobTypeService, []string{"db"}, "ready", "Invalid depends_on_condition"},
+		{"system job", JobTypeSystem, []string{"db"}, "", "not supported by system jobs"},
+		{"unknown group", JobTypeService, []string{"cache"}, "", `unknown task group "cache"`},
+		{"own group", JobTypeService, []string{"web"}, "", "cannot reference its own task group"},
+		{"duplicate group", JobTypeService, []string{"db", "db"}, "", "more than once"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			j := testJob()
+			j.Type = tc.jobType
+			db := j.TaskGroups[0].Copy()
+			db.Name = "db"
+			j.TaskGroups = append(j.TaskGroups, db)
+			tg := j.TaskGroups[0]
+			tg.DependsOn = tc.dependsOn
+			tg.DependsOnCondition = tc.condition
+
+			err := tg.validateDependencies(j)
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+			}
+		})
+	}
+}
+
+func TestJob_Validate_DependencyCycle(t *testing.T) {
+	ci.Parallel(t)
+
+	j := testJob()
+	for _, name := range []string{"db", "cache"} {
+		tg := j.TaskGroups[0].Copy()
+		tg.Name = name
+		j.TaskGroups = append(j.TaskGroups, tg)
+	}
+
+	// web -> db -> cache
+	j.TaskGroups[0].DependsOn = []string{"db"}
+	j.TaskGroups[1].DependsOn = []string{"cache"}
+	require.Nil(t, j.dependencyCycle())
+	require.Equal(t, []string{"web"}, taskGroupNames(j.DependentTaskGroups("db")))
+
+	// web -> db -> cache -> web
+	j.TaskGroups[2].DependsOn = []string{"web"}
+	require.Equal(t, []string{"web", "db", "cache", "web"}, j.dependencyCycle())
+
+	err := j.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Task group dependencies form a cycle: web -> db -> cache -> web")
+}
+
+func taskGroupNames(tgs []*TaskGroup) []string {
+	names := make([]string, 0, len(tgs))
+	for _, tg := range tgs {
+		names = append(names, tg.Name)
+	}
+	return names
+}
+
+func TestAllocation_SatisfiesDependency(t *testing.T) {
+	ci.Parallel(t)
+
+	healthy := &AllocDeploymentStatus{Healthy: helper.BoolToPtr(true)}
+	cases := []struct {
+		clientStatus     string
+		deploymentStatus *AllocDeploymentStatus
+		condition        string
+		expected         bool
+	}{
+		{AllocClientStatusPending, nil, DependencyConditionRunning, false},
+		{AllocClientStatusRunning, nil, DependencyConditionRunning, true},
+		{AllocClientStatusRunning, nil, "", true},
+		{AllocClientStatusRunning, nil, DependencyConditionHealthy, false},
+		{AllocClientStatusRunning, healthy, DependencyConditionHealthy, true},
+		{AllocClientStatusRunning, nil, DependencyConditionComplete, false},
+		{AllocClientStatusFailed, nil, DependencyConditionComplete, false},
+		{AllocClientStatusComplete, nil, DependencyConditionComplete, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.clientStatus+"/"+tc.condition, func(t *testing.T) {
+			alloc := &Allocation{ClientStatus: tc.clientStatus, DeploymentStatus: tc.deploymentStatus}
+			require.Equal(t, tc.expected, alloc.SatisfiesDependency(tc.condition))
+
+			stub := &AllocListStub{ClientStatus: tc.clientStatus, DeploymentStatus: tc.deploymentStatus}
+			require.Equal(t, tc.expected, stub.SatisfiesDependency(tc.condition))
+		})
+	}
+}
+
+func TestTaskGroup_PreemptionBudget(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name       string
+		preemption *PreemptionPolicy
+		count      int
+		live       int
+		expected   int
+	}{
+		{"no policy", nil, 3, 3, -1},
+		{"not preemptible", &PreemptionPolicy{Preemptible: false}, 3, 3, 0},
+		{"no max disruption", &PreemptionPolicy{Preemptible: true}, 3, 1, -1},
+		{"all live", &PreemptionPolicy{Preemptible: true, MaxDisruption: 2}, 3, 3, 2},
+		{"some disrupted", &PreemptionPolicy{Preemptible: true, MaxDisruption: 2}, 3, 2, 1},
+		{"all disrupted", &PreemptionPolicy{Preemptible: true, MaxDisruption: 2}, 3, 0, 0},
+		{"more live than count", &PreemptionPolicy{Preemptible: true, MaxDisruption: 1}, 3, 4, 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tg := &TaskGroup{Count: tc.count, Preemption: tc.preemption}
+			require.Equal(t, tc.expected, tg.PreemptionBudget(tc.live))
+		})
+	}
+}
+
+func TestNamespace_PreemptionPriority(t *testing.T) {
+	ci.Parallel(t)
+
+	var ns *Namespace
+	require.Equal(t, 80, ns.PreemptionPriority(80))
+
+	ns = &Namespace{Name: "foo"}
+	require.Equal(t, 80, ns.PreemptionPriority(80))
+
+	ns.PreemptionConfiguration = &NamespacePreemptionConfiguration{MaxPriority: 50}
+	require.Equal(t, 50, ns.PreemptionPriority(80))
+	require.Equal(t, 20, ns.PreemptionPriority(20))
+	require.NoError(t, ns.Validate())
+
+	ns.PreemptionConfiguration.MaxPriority = JobMaxPriority + 1
+	require.Error(t, ns.Validate())
+}
+
 func TestTaskGroupNetwork_Validate(t *testing.T) {
 	ci.Parallel(t)
 
@@ -2486,6 +2677,135 @@ func TestLogConfig_Equals(t *testing.T) {
 	})
 }
 
+func TestLogConfig_Validate_Sinks(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name  string
+		sinks []*LogSink
+		err   string
+	}{
+		{
+			name: "valid",
+			sinks: []*LogSink{
+				{Type: LogSinkTypeFile},
+				{Type: LogSinkTypeSyslog, Address: "tcp://127.0.0.1:514", Facility: "local3", Overflow: LogSinkOverflowBlock},
+				{Type: LogSinkTypeFluentForward, Address: "127.0.0.1:24224", Tag: "web", BufferSize: 100},
+			},
+		},
+		{
+			name:  "unknown type",
+			sinks: []*LogSink{{Type: "kafka"}},
+			err:   `unknown sink type "kafka"`,
+		},
+		{
+			name:  "multiple file sinks",
+			sinks: []*LogSink{{Type: LogSinkTypeFile}, {Type: LogSinkTypeFile}},
+			err:   "only one file sink is allowed",
+		},
+		{
+			name:  "configured file sink",
+			sinks: []*LogSink{{Type: LogSinkTypeFile, Tag: "web"}},
+			err:   "file sinks do not accept any configuration",
+		},
+		{
+			name:  "syslog scheme",
+			sinks: []*LogSink{{Type: LogSinkTypeSyslog, Address: "http://127.0.0.1:514"}},
+			err:   "syslog address scheme must be one of",
+		},
+		{
+			name:  "syslog facility",
+			sinks: []*LogSink{{Type: LogSinkTypeSyslog, Facility: "local9"}},
+			err:   `invalid syslog facility "local9"`,
+		},
+		{
+			name:  "fluent address",
+			sinks: []*LogSink{{Type: LogSinkTypeFluentForward, Address: "127.0.0.1"}},
+			err:   "invalid fluent forward address",
+		},
+		{
+			name:  "fluent facility",
+			sinks: []*LogSink{{Type: LogSinkTypeFluentForward, Facility: "user"}},
+			err:   "facility is only supported by syslog sinks",
+		},
+		{
+			name:  "overflow",
+			sinks: []*LogSink{{Type: LogSinkTypeSyslog, Overflow: "wait"}},
+			err:   "overflow must be",
+		},
+		{
+			name:  "buffer size",
+			sinks: []*LogSink{{Type: LogSinkTypeSyslog, BufferSize: -1}},
+			err:   "buffer size must be positive",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			l := DefaultLogConfig()
+			l.Sinks = tc.sinks
+			err := l.Validate()
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
+
+func TestLogConfig_Validate_Rotation(t *testing.T) {
+	ci.Parallel(t)
+
+	l := DefaultLogConfig()
+	l.MaxFileAge = time.Hour
+	l.Compress = true
+	l.MaxAllocSizeMB = 100
+	require.NoError(t, l.Validate())
+
+	l.MaxFileAge = time.Second
+	require.ErrorContains(t, l.Validate(), "minimum file age is 1m0s")
+
+	l.MaxFileAge = 0
+	l.MaxAllocSizeMB = 5
+	require.ErrorContains(t, l.Validate(), "max alloc size (5 MB) must be at least the max file size (10 MB)")
+
+	l.MaxAllocSizeMB = -1
+	require.ErrorContains(t, l.Validate(), "max alloc size must be positive")
+
+	// The cap bounds the disk used by the logs of the task
+	task := &Task{
+		LogConfig: DefaultLogConfig(),
+	}
+	task.LogConfig.MaxAllocSizeMB = 50
+	ephemeralDisk := &EphemeralDisk{
+		SizeMB: 60,
+	}
+	err := task.Validate(ephemeralDisk, JobTypeService, nil, nil)
+	require.NotContains(t, err.Error(), "log storage")
+}
+
+func TestTask_Validate_LogConfig_Sinks(t *testing.T) {
+	ci.Parallel(t)
+
+	// Log files don't need to fit on the disk if they aren't written
+	task := &Task{
+		LogConfig: DefaultLogConfig(),
+	}
+	task.LogConfig.Sinks = []*LogSink{{Type: LogSinkTypeSyslog}}
+	ephemeralDisk := &EphemeralDisk{
+		SizeMB: 1,
+	}
+
+	err := task.Validate(ephemeralDisk, JobTypeService, nil, nil)
+	require.NotContains(t, err.Error(), "log storage")
+
+	task.LogConfig.Sinks = append(task.LogConfig.Sinks, &LogSink{Type: LogSinkTypeFile})
+	err = task.Validate(ephemeralDisk, JobTypeService, nil, nil)
+	require.Contains(t, err.Error(), "log storage")
+}
+
 func TestTask_Validate_CSIPluginConfig(t *testing.T) {
 	ci.Parallel(t)
 
@@ -2963,6 +3283,63 @@ func TestUpdateStrategy_Validate(t *testing.T) {
 	)
 }
 
+func TestResource_Validate_NUMA(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name   string
+		cores  int
+		numa   *NUMA
+		expErr string
+	}{
+		{
+			name:  "no numa",
+			cores: 2,
+		},
+		{
+			name:  "require with cores",
+			cores: 2,
+			numa:  &NUMA{Affinity: NUMAAffinityRequire},
+		},
+		{
+			name: "none without cores",
+			numa: &NUMA{Affinity: NUMAAffinityNone},
+		},
+		{
+			name:   "prefer without cores",
+			numa:   &NUMA{Affinity: NUMAAffinityPrefer},
+			expErr: "NUMA affinity can only be set when reserving 'cores'",
+		},
+		{
+			name:   "invalid affinity",
+			cores:  2,
+			numa:   &NUMA{Affinity: "always"},
+			expErr: `invalid NUMA affinity "always"`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &Resources{
+				MemoryMB: 256,
+				Cores:    tc.cores,
+				NUMA:     tc.numa,
+			}
+			if tc.cores == 0 {
+				r.CPU = 100
+			}
+
+			err := r.Validate()
+			if tc.expErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expErr)
+			}
+		})
+	}
+}
+
 func TestResource_NetIndex(t *testing.T) {
 	ci.Parallel(t)
 
@@ -4411,6 +4788,31 @@ func TestPlan_AppendPreemptedAllocAppendsAllocWithUpdatedAttrs(t *testing.T) {
 	assert.Equal(t, expectedAlloc, appendedAlloc)
 }
 
+func TestPlan_RemoveAlloc(t *testing.T) {
+	ci.Parallel(t)
+	plan := &Plan{
+		NodeAllocation:  make(map[string][]*Allocation),
+		NodePreemptions: make(map[string][]*Allocation),
+	}
+	alloc := MockAlloc()
+	alloc2 := MockAlloc()
+	alloc2.NodeID = alloc.NodeID
+	preempted := MockAlloc()
+	preempted.NodeID = alloc.NodeID
+
+	plan.AppendAlloc(alloc, nil)
+	plan.AppendAlloc(alloc2, nil)
+	plan.AppendPreemptedAlloc(preempted, alloc.ID)
+
+	plan.RemoveAlloc(alloc)
+	require.Len(t, plan.NodeAllocation[alloc.NodeID], 1)
+	require.Equal(t, alloc2.ID, plan.NodeAllocation[alloc.NodeID][0].ID)
+	require.NotContains(t, plan.NodePreemptions, alloc.NodeID)
+
+	plan.RemoveAlloc(alloc2)
+	require.Empty(t, plan.NodeAllocation)
+}
+
 func TestAllocation_MsgPackTags(t *testing.T) {
 	ci.Parallel(t)
 	planType := reflect.TypeOf(Allocation{})
@@ -6035,8 +6437,23 @@ func TestACLTokenValidate(t *testing.T) {
 		t.Fatalf("bad: %v", err)
 	}
 
-	// Make it valid
+	// Management tokens cannot be linked to roles
 	tk.Name = "foo"
+	tk.Roles = []*ACLTokenRoleLink{{ID: uuid.Generate()}}
+	err = tk.Validate()
+	assert.NotNil(t, err)
+	if !strings.Contains(err.Error(), "associated with roles") {
+		t.Fatalf("bad: %v", err)
+	}
+
+	// Client tokens may be linked to only roles
+	tk.Type = ACLClientToken
+	err = tk.Validate()
+	assert.Nil(t, err)
+
+	// Make it valid
+	tk.Type = ACLManagementToken
+	tk.Roles = nil
 	err = tk.Validate()
 	assert.Nil(t, err)
 }
@@ -6335,6 +6752,7 @@ func TestNode_Canonicalize(t *testing.T) {
 	node := &Node{}
 	node.Canonicalize()
 	require.Equal(NodeSchedulingEligible, node.SchedulingEligibility)
+	require.Equal(NodePoolDefault, node.NodePool)
 
 	node = &Node{
 		DrainStrategy: &DrainStrategy{
@@ -6594,6 +7012,39 @@ func TestSpread_Validate(t *testing.T) {
 			err:  fmt.Errorf("Spread target value \"dc1\" already defined"),
 			name: "No spread targets",
 		},
+		{
+			spread: &Spread{
+				Attribute: "${meta.rack}",
+				Weight:    50,
+				MaxSkew:   -1,
+			},
+			err:  fmt.Errorf("Spread max_skew must not be negative"),
+			name: "Negative max skew",
+		},
+		{
+			spread: &Spread{
+				Attribute: "${meta.rack}",
+				Weight:    50,
+				MaxSkew:   1,
+				SpreadTarget: []*SpreadTarget{
+					{
+						Value:   "r1",
+						Percent: 50,
+					},
+				},
+			},
+			err:  fmt.Errorf("Spread max_skew cannot be used with targets"),
+			name: "Max skew with targets",
+		},
+		{
+			spread: &Spread{
+				Attribute: "${meta.rack}",
+				Weight:    50,
+				MaxSkew:   1,
+			},
+			err:  nil,
+			name: "Valid max skew",
+		},
 		{
 			spread: &Spread{
 				Attribute: "${node.datacenter}",
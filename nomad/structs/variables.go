@@ -0,0 +1,296 @@
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+const (
+	// VariablesApplyRPCMethod is the RPC method for upserting or deleting a
+	// variable.
+	//
+	// Args: VariablesApplyRequest
+	// Reply: VariablesApplyResponse
+	VariablesApplyRPCMethod = "Variables.Apply"
+
+	// VariablesListRPCMethod is the RPC method for listing the metadata of
+	// variables within Nomad.
+	//
+	// Args: VariablesListRequest
+	// Reply: VariablesListResponse
+	VariablesListRPCMethod = "Variables.List"
+
+	// VariablesReadRPCMethod is the RPC method for reading and decrypting a
+	// single variable.
+	//
+	// Args: VariablesReadRequest
+	// Reply: VariablesReadResponse
+	VariablesReadRPCMethod = "Variables.Read"
+
+	// maxVariableSize is the maximum size of the unencrypted contents of a
+	// variable. This limits the impact a single variable can have on the size
+	// of Raft log entries and snapshots.
+	maxVariableSize = 16 * 1024
+
+	// VariablesJobPathPrefix is the path prefix under which variables are
+	// implicitly readable by the allocations of the job identified by the
+	// path segment following the prefix.
+	VariablesJobPathPrefix = "nomad/jobs"
+)
+
+var (
+	// validVariablePath is used to validate variable paths. Paths are not
+	// allowed to contain characters which would require escaping within URLs
+	// or within the ACL path globs.
+	validVariablePath = regexp.MustCompile("^[a-zA-Z0-9-_~/]{1,128}$")
+)
+
+// VariableMetadata is the metadata envelope for a variable. It is the
+// portion of a variable which is stored and returned in plaintext.
+type VariableMetadata struct {
+	Namespace string
+	Path      string
+
+	CreateIndex uint64
+	CreateTime  int64
+	ModifyIndex uint64
+	ModifyTime  int64
+}
+
+// VariableEncrypted is the form of a variable which is stored within the
+// state store and Raft log. The items are only ever held encrypted.
+type VariableEncrypted struct {
+	VariableMetadata
+	VariableData
+}
+
+// VariableData is the encrypted contents of a variable along with the ID of
+// the root key used to perform the encryption.
+type VariableData struct {
+	Data  []byte
+	KeyID string
+}
+
+// VariableDecrypted is the form of a variable which is passed between the
+// HTTP API and the RPC layer. It must never be written to the Raft log or
+// the state store.
+type VariableDecrypted struct {
+	VariableMetadata
+	Items VariableItems
+}
+
+// VariableItems is the set of key/value pairs held within a variable.
+type VariableItems map[string]string
+
+// Size returns the number of bytes used by the keys and values of the items.
+func (vi VariableItems) Size() uint64 {
+	var out uint64
+	for k, v := range vi {
+		out += uint64(len(k))
+		out += uint64(len(v))
+	}
+	return out
+}
+
+// Equals performs an equality check on the two variable item sets.
+func (vi VariableItems) Equals(o VariableItems) bool {
+	if len(vi) != len(o) {
+		return false
+	}
+	for k, v := range vi {
+		if ov, ok := o[k]; !ok || ov != v {
+			return false
+		}
+	}
+	return true
+}
+
+// Copy returns a copy of the variable item set.
+func (vi VariableItems) Copy() VariableItems {
+	if vi == nil {
+		return nil
+	}
+	out := make(VariableItems, len(vi))
+	for k, v := range vi {
+		out[k] = v
+	}
+	return out
+}
+
+// Copy returns a deep copy of the decrypted variable. It handles nil objects.
+func (v *VariableDecrypted) Copy() *VariableDecrypted {
+	if v == nil {
+		return nil
+	}
+	return &VariableDecrypted{
+		VariableMetadata: v.VariableMetadata,
+		Items:            v.Items.Copy(),
+	}
+}
+
+// Validate ensures the decrypted variable is well formed before it is
+// encrypted and written to state.
+func (v *VariableDecrypted) Validate() error {
+	var mErr multierror.Error
+
+	if v.Namespace == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("variable namespace is required"))
+	}
+	if err := ValidateVariablePath(v.Path); err != nil {
+		mErr.Errors = append(mErr.Errors, err)
+	}
+
+	if len(v.Items) == 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("variable missing Items"))
+	}
+	for k := range v.Items {
+		if k == "" {
+			mErr.Errors = append(mErr.Errors, errors.New("variable item keys cannot be empty"))
+			break
+		}
+	}
+	if size := v.Items.Size(); size > maxVariableSize {
+		mErr.Errors = append(mErr.Errors,
+			fmt.Errorf("variable items exceed maximum size of %d bytes: %d", maxVariableSize, size))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// ValidateVariablePath checks that the path is a valid variable path.
+func ValidateVariablePath(path string) error {
+	if !validVariablePath.MatchString(path) {
+		return fmt.Errorf("invalid path %q", path)
+	}
+	if strings.HasPrefix(path, "/") || strings.HasSuffix(path, "/") {
+		return fmt.Errorf("invalid path %q: cannot start or end with '/'", path)
+	}
+	if strings.Contains(path, "//") {
+		return fmt.Errorf("invalid path %q: cannot contain empty path segments", path)
+	}
+
+	// The job path prefix is reserved for variables which belong to a job,
+	// so must be followed by a job ID.
+	if path == VariablesJobPathPrefix {
+		return fmt.Errorf("invalid path %q: %q is reserved", path, VariablesJobPathPrefix)
+	}
+	return nil
+}
+
+// Copy returns a deep copy of the encrypted variable. It handles nil objects.
+func (v *VariableEncrypted) Copy() *VariableEncrypted {
+	if v == nil {
+		return nil
+	}
+	nv := new(VariableEncrypted)
+	*nv = *v
+	if v.Data != nil {
+		nv.Data = make([]byte, len(v.Data))
+		copy(nv.Data, v.Data)
+	}
+	return nv
+}
+
+// Equals performs an equality check on the two encrypted variables, ignoring
+// the Raft and time indexes. It handles nil objects.
+func (v *VariableEncrypted) Equals(o *VariableEncrypted) bool {
+	if v == nil || o == nil {
+		return v == o
+	}
+	return v.Namespace == o.Namespace &&
+		v.Path == o.Path &&
+		v.KeyID == o.KeyID &&
+		string(v.Data) == string(o.Data)
+}
+
+// GetNamespace is a helper for getting the namespace when the object may be
+// nil and is required for pagination.
+func (v *VariableEncrypted) GetNamespace() string {
+	if v == nil {
+		return ""
+	}
+	return v.Namespace
+}
+
+// GetID is a helper for getting the ID when the object may be nil and is
+// required for pagination. The path is unique within a namespace and therefore
+// acts as the ID.
+func (v *VariableEncrypted) GetID() string {
+	if v == nil {
+		return ""
+	}
+	return v.Path
+}
+
+// GetCreateIndex is a helper for getting the create index when the object may
+// be nil.
+func (v *VariableEncrypted) GetCreateIndex() uint64 {
+	if v == nil {
+		return 0
+	}
+	return v.CreateIndex
+}
+
+// VarOp is the operation performed on a variable by an apply request.
+type VarOp string
+
+const (
+	VarOpSet    VarOp = "set"
+	VarOpDelete VarOp = "delete"
+)
+
+// VariablesApplyRequest is the request object used to upsert or delete a
+// variable.
+type VariablesApplyRequest struct {
+	Op  VarOp
+	Var *VariableDecrypted
+	WriteRequest
+}
+
+// VariablesApplyResponse is the response object when a variable has been
+// upserted or deleted. The Output is the variable as written to state, and is
+// nil following a delete.
+type VariablesApplyResponse struct {
+	Op     VarOp
+	Output *VariableDecrypted
+	WriteMeta
+}
+
+// VarApplyStateRequest is the request object written to the Raft log when a
+// variable is upserted or deleted. The variable has already been encrypted by
+// the RPC handler.
+type VarApplyStateRequest struct {
+	Op  VarOp
+	Var *VariableEncrypted
+	WriteRequest
+}
+
+// VariablesListRequest is the request object used to list the metadata of
+// variables. The QueryOptions.Prefix field is used to filter by path prefix.
+type VariablesListRequest struct {
+	QueryOptions
+}
+
+// VariablesListResponse is the response object when listing variables. It
+// only ever contains metadata.
+type VariablesListResponse struct {
+	Data []*VariableMetadata
+	QueryMeta
+}
+
+// VariablesReadRequest is the request object used to read a single variable.
+type VariablesReadRequest struct {
+	Path string
+	QueryOptions
+}
+
+// VariablesReadResponse is the response object when reading a single
+// variable. Data will be nil if the variable does not exist.
+type VariablesReadResponse struct {
+	Data *VariableDecrypted
+	QueryMeta
+}
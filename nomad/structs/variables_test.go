@@ -0,0 +1,94 @@
+package structs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateVariablePath(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		path        string
+		expectedErr bool
+	}{
+		{path: "foo", expectedErr: false},
+		{path: "foo/bar_baz-1~", expectedErr: false},
+		{path: "nomad/jobs/example", expectedErr: false},
+		{path: "", expectedErr: true},
+		{path: "/foo", expectedErr: true},
+		{path: "foo/", expectedErr: true},
+		{path: "foo//bar", expectedErr: true},
+		{path: "foo bar", expectedErr: true},
+		{path: "foo*", expectedErr: true},
+		{path: "nomad/jobs", expectedErr: true},
+		{path: strings.Repeat("a", 129), expectedErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			err := ValidateVariablePath(tc.path)
+			if tc.expectedErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestVariableDecrypted_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	v := &VariableDecrypted{
+		VariableMetadata: VariableMetadata{
+			Namespace: DefaultNamespace,
+			Path:      "foo/bar",
+		},
+		Items: VariableItems{"key": "value"},
+	}
+	require.NoError(t, v.Validate())
+
+	noItems := v.Copy()
+	noItems.Items = nil
+	require.ErrorContains(t, noItems.Validate(), "missing Items")
+
+	emptyKey := v.Copy()
+	emptyKey.Items[""] = "value"
+	require.ErrorContains(t, emptyKey.Validate(), "keys cannot be empty")
+
+	tooLarge := v.Copy()
+	tooLarge.Items["large"] = strings.Repeat("a", maxVariableSize)
+	require.ErrorContains(t, tooLarge.Validate(), "exceed maximum size")
+}
+
+func TestVariableEncrypted_Equals(t *testing.T) {
+	ci.Parallel(t)
+
+	v := &VariableEncrypted{
+		VariableMetadata: VariableMetadata{
+			Namespace:   DefaultNamespace,
+			Path:        "foo/bar",
+			ModifyIndex: 10,
+		},
+		VariableData: VariableData{
+			Data:  []byte("ciphertext"),
+			KeyID: "key",
+		},
+	}
+
+	c := v.Copy()
+	require.True(t, v.Equals(c))
+
+	// Modifying the copy should not modify the original.
+	c.Data[0] = 'C'
+	require.False(t, v.Equals(c))
+	require.Equal(t, []byte("ciphertext"), v.Data)
+
+	c = v.Copy()
+	c.KeyID = "other"
+	require.False(t, v.Equals(c))
+}
@@ -0,0 +1,112 @@
+package structs
+
+import (
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/helper/uuid"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+const (
+	// WorkloadIdentityFile is the name of the file within the task's secrets
+	// directory which holds the signed workload identity of the task.
+	WorkloadIdentityFile = "nomad_token"
+)
+
+// WorkloadIdentity is the jobspec block which determines how the signed
+// workload identity of a task is exposed to it.
+type WorkloadIdentity struct {
+	// Env injects the workload identity into the task's environment if
+	// true.
+	Env bool
+
+	// File writes the workload identity into the task's secrets directory
+	// if true.
+	File bool
+}
+
+// DefaultWorkloadIdentity returns the workload identity configuration used
+// for tasks which do not include an identity block. The identity is written
+// into the secrets directory, but not injected into the environment.
+func DefaultWorkloadIdentity() *WorkloadIdentity {
+	return &WorkloadIdentity{
+		File: true,
+	}
+}
+
+// Copy returns a copy of the workload identity. It handles nil objects.
+func (wi *WorkloadIdentity) Copy() *WorkloadIdentity {
+	if wi == nil {
+		return nil
+	}
+	out := *wi
+	return &out
+}
+
+// Equals returns whether the two workload identities are equal.
+func (wi *WorkloadIdentity) Equals(other *WorkloadIdentity) bool {
+	if wi == nil || other == nil {
+		return wi == other
+	}
+	return *wi == *other
+}
+
+// IdentityClaims are the claims of the JWT identifying a workload. The JWT is
+// signed by the leader using the active root key when the allocation is
+// created. IdentityClaims should never be serialized to msgpack unsigned.
+type IdentityClaims struct {
+	Namespace    string `json:"nomad_namespace"`
+	JobID        string `json:"nomad_job_id"`
+	TaskGroup    string `json:"nomad_task_group"`
+	AllocationID string `json:"nomad_allocation_id"`
+	TaskName     string `json:"nomad_task"`
+
+	jwt.Claims
+}
+
+// NewIdentityClaims returns the identity claims of the task within the
+// allocation. The subject of the claims uniquely identifies the task within
+// the cluster, so third parties can make use of it without needing to parse
+// the Nomad specific claims.
+func NewIdentityClaims(job *Job, alloc *Allocation, taskName string, now time.Time) *IdentityClaims {
+	return &IdentityClaims{
+		Namespace:    alloc.Namespace,
+		JobID:        job.ID,
+		TaskGroup:    alloc.TaskGroup,
+		AllocationID: alloc.ID,
+		TaskName:     taskName,
+		Claims: jwt.Claims{
+			ID:        uuid.Generate(),
+			Subject:   strings.Join([]string{alloc.Namespace, job.ID, alloc.TaskGroup, taskName}, ":"),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+		},
+	}
+}
+
+// KeyringPublicKey is the public key of a root key, used by third parties to
+// verify the signature of workload identities.
+type KeyringPublicKey struct {
+	KeyID      string
+	PublicKey  []byte
+	Algorithm  string
+	Use        string
+	CreateTime int64
+}
+
+const (
+	// PubKeyAlgEdDSA is the algorithm of the public keys used to sign
+	// workload identities.
+	PubKeyAlgEdDSA = "EdDSA"
+
+	// PubKeyUseSig denotes that a public key is used to verify signatures.
+	PubKeyUseSig = "sig"
+)
+
+// KeyringListPublicResponse lists the public keys of the keyring, which are
+// used to verify workload identities.
+type KeyringListPublicResponse struct {
+	PublicKeys []*KeyringPublicKey
+	QueryMeta
+}
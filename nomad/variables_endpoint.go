@@ -0,0 +1,370 @@
+package nomad
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+	multierror "github.com/hashicorp/go-multierror"
+
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/state/paginator"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Variables encapsulates the variables RPC endpoint which is callable via the
+// Variables RPCs and externally via the "/v1/var{s}" HTTP API.
+type Variables struct {
+	srv    *Server
+	logger log.Logger
+
+	// ctx provides context regarding the underlying connection.
+	ctx *RPCContext
+}
+
+// Apply is used to upsert or delete a single variable. The items of the
+// variable are encrypted using the active root key before being written to
+// Raft.
+func (v *Variables) Apply(args *structs.VariablesApplyRequest, reply *structs.VariablesApplyResponse) error {
+	if done, err := v.srv.forward(structs.VariablesApplyRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variables", "apply"}, time.Now())
+
+	if args.Var == nil {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, "missing variable")
+	}
+
+	// The variable namespace is always the request namespace, so a caller
+	// cannot write to a namespace they do not have access to.
+	args.Var.Namespace = args.RequestNamespace()
+
+	var cap string
+	switch args.Op {
+	case structs.VarOpSet:
+		cap = acl.VariablesCapabilityWrite
+	case structs.VarOpDelete:
+		cap = acl.VariablesCapabilityDestroy
+	default:
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "unsupported variable operation %q", args.Op)
+	}
+
+	aclObj, err := v.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+	if aclObj != nil && !aclObj.AllowVariableOperation(args.Var.Namespace, args.Var.Path, cap) {
+		return structs.ErrPermissionDenied
+	}
+
+	stateReq := &structs.VarApplyStateRequest{
+		Op:           args.Op,
+		WriteRequest: args.WriteRequest,
+	}
+
+	switch args.Op {
+	case structs.VarOpSet:
+		if err := args.Var.Validate(); err != nil {
+			return structs.NewErrRPCCoded(http.StatusBadRequest, err.Error())
+		}
+		if stateReq.Var, err = v.encrypt(args.Var); err != nil {
+			return err
+		}
+	case structs.VarOpDelete:
+		if err := structs.ValidateVariablePath(args.Var.Path); err != nil {
+			return structs.NewErrRPCCoded(http.StatusBadRequest, err.Error())
+		}
+		stateReq.Var = &structs.VariableEncrypted{
+			VariableMetadata: structs.VariableMetadata{
+				Namespace: args.Var.Namespace,
+				Path:      args.Var.Path,
+			},
+		}
+	}
+
+	// Update via Raft.
+	out, index, err := v.srv.raftApply(structs.VarApplyStateRequestType, stateReq)
+	if err != nil {
+		return err
+	}
+
+	// Check if the FSM response, which is an interface, contains an error.
+	if err, ok := out.(error); ok && err != nil {
+		return err
+	}
+
+	reply.Op = args.Op
+	reply.Index = index
+
+	// Return the variable as stored, so the caller has the correct indexes
+	// and times. The items are already known, so there is no need to decrypt
+	// the stored variable.
+	if args.Op == structs.VarOpSet {
+		stored, err := v.srv.State().GetVariable(nil, args.Var.Namespace, args.Var.Path)
+		if err != nil {
+			return err
+		}
+		if stored != nil {
+			reply.Output = &structs.VariableDecrypted{
+				VariableMetadata: stored.VariableMetadata,
+				Items:            args.Var.Items.Copy(),
+			}
+		}
+	}
+	return nil
+}
+
+// encrypt encrypts the items of the decrypted variable using the active root
+// key.
+func (v *Variables) encrypt(variable *structs.VariableDecrypted) (*structs.VariableEncrypted, error) {
+	keyMeta, err := v.srv.State().GetActiveRootKeyMeta(nil)
+	if err != nil {
+		return nil, err
+	}
+	if keyMeta == nil {
+		return nil, fmt.Errorf("keyring has not been initialized yet")
+	}
+
+	buf, err := json.Marshal(variable.Items)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := v.srv.encrypter.Encrypt(buf, keyMeta.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &structs.VariableEncrypted{
+		VariableMetadata: structs.VariableMetadata{
+			Namespace:  variable.Namespace,
+			Path:       variable.Path,
+			ModifyTime: time.Now().UnixNano(),
+		},
+		VariableData: structs.VariableData{
+			Data:  ciphertext,
+			KeyID: keyMeta.KeyID,
+		},
+	}, nil
+}
+
+// decrypt decrypts the items of the encrypted variable using the root key
+// which was used to encrypt it.
+func (v *Variables) decrypt(variable *structs.VariableEncrypted) (*structs.VariableDecrypted, error) {
+	buf, err := v.srv.encrypter.Decrypt(variable.Data, variable.KeyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt variable %q: %v", variable.Path, err)
+	}
+
+	decrypted := &structs.VariableDecrypted{
+		VariableMetadata: variable.VariableMetadata,
+	}
+	if err := json.Unmarshal(buf, &decrypted.Items); err != nil {
+		return nil, fmt.Errorf("failed to decode variable %q: %v", variable.Path, err)
+	}
+	return decrypted, nil
+}
+
+// Read is used to read and decrypt a single variable.
+func (v *Variables) Read(args *structs.VariablesReadRequest, reply *structs.VariablesReadResponse) error {
+	if done, err := v.srv.forward(structs.VariablesReadRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variables", "read"}, time.Now())
+
+	if err := v.handleMixedAuthRead(args.QueryOptions, args.Path); err != nil {
+		return err
+	}
+
+	return v.srv.blockingRPC(&blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
+
+			out, err := stateStore.GetVariable(ws, args.RequestNamespace(), args.Path)
+			if err != nil {
+				return err
+			}
+
+			if out == nil {
+				reply.Data = nil
+				return v.srv.setReplyQueryMeta(stateStore, state.TableVariables, &reply.QueryMeta)
+			}
+
+			if reply.Data, err = v.decrypt(out); err != nil {
+				return err
+			}
+			reply.Index = out.ModifyIndex
+			return nil
+		},
+	})
+}
+
+// List is used to list the metadata of variables held within state. It
+// supports filtering by path prefix and the namespace wildcard operator. The
+// items of the variables are never returned.
+func (v *Variables) List(args *structs.VariablesListRequest, reply *structs.VariablesListResponse) error {
+	if done, err := v.srv.forward(structs.VariablesListRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variables", "list"}, time.Now())
+
+	aclObj, err := v.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+
+	namespace := args.RequestNamespace()
+
+	return v.srv.blockingRPC(&blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
+
+			var (
+				iter memdb.ResultIterator
+				err  error
+			)
+			if namespace == structs.AllNamespacesSentinel {
+				iter, err = stateStore.GetVariables(ws)
+			} else {
+				iter, err = stateStore.GetVariablesByNamespaceAndPrefix(ws, namespace, args.Prefix)
+			}
+			if err != nil {
+				return err
+			}
+
+			tokenizer := paginator.NewStructsTokenizer(iter,
+				paginator.StructsTokenizerOptions{
+					WithNamespace: true,
+					WithID:        true,
+				},
+			)
+
+			// Only include the variables the caller is permitted to list,
+			// and apply the prefix when listing across all namespaces.
+			filters := []paginator.Filter{
+				paginator.GenericFilter{
+					Allow: func(raw interface{}) (bool, error) {
+						variable := raw.(*structs.VariableEncrypted)
+						if !strings.HasPrefix(variable.Path, args.Prefix) {
+							return false, nil
+						}
+						if aclObj == nil {
+							return true, nil
+						}
+						return aclObj.AllowVariableOperation(
+							variable.Namespace, variable.Path, acl.VariablesCapabilityList), nil
+					},
+				},
+			}
+
+			var stubs []*structs.VariableMetadata
+			paginatorImpl, err := paginator.NewPaginator(iter, tokenizer, filters, args.QueryOptions,
+				func(raw interface{}) error {
+					variable := raw.(*structs.VariableEncrypted)
+					meta := variable.VariableMetadata
+					stubs = append(stubs, &meta)
+					return nil
+				})
+			if err != nil {
+				return structs.NewErrRPCCodedf(
+					http.StatusBadRequest, "failed to create result paginator: %v", err)
+			}
+
+			nextToken, err := paginatorImpl.Page()
+			if err != nil {
+				return structs.NewErrRPCCodedf(
+					http.StatusBadRequest, "failed to read result page: %v", err)
+			}
+
+			// Ensure we return an empty list, rather than nil, when there are
+			// no results.
+			if stubs == nil {
+				stubs = make([]*structs.VariableMetadata, 0)
+			}
+
+			reply.QueryMeta.NextToken = nextToken
+			reply.Data = stubs
+
+			// Use the index table to populate the query meta as we have no way
+			// of tracking the max index on deletes.
+			return v.srv.setReplyQueryMeta(stateStore, state.TableVariables, &reply.QueryMeta)
+		},
+	})
+}
+
+// handleMixedAuthRead performs the authorization of a variable read, which is
+// callable by both ACL tokens and Nomad nodes. Nodes are only permitted to
+// read variables which belong to a job they are running an allocation of; this
+// is used to render task templates.
+func (v *Variables) handleMixedAuthRead(args structs.QueryOptions, path string) error {
+
+	// Perform the initial token resolution.
+	aclObj, err := v.srv.ResolveToken(args.AuthToken)
+
+	switch err {
+	case nil:
+		// Perform our ACL validation. If the object is nil, this means ACLs
+		// are not enabled.
+		if aclObj != nil {
+			if !aclObj.AllowVariableOperation(args.RequestNamespace(), path, acl.VariablesCapabilityRead) {
+				return structs.ErrPermissionDenied
+			}
+		}
+		return nil
+	default:
+		// In the event we got any error other than ErrTokenNotFound, consider
+		// this terminal.
+		if err != structs.ErrTokenNotFound {
+			return err
+		}
+	}
+
+	// Attempt to lookup AuthToken as a Node.SecretID and return any error
+	// wrapped along with the original.
+	stateStore := v.srv.State()
+	node, stateErr := stateStore.NodeBySecretID(nil, args.AuthToken)
+	if stateErr != nil {
+		var mErr multierror.Error
+		mErr.Errors = append(mErr.Errors, err, stateErr)
+		return mErr.ErrorOrNil()
+	}
+
+	// At this point, we do not have a valid ACL token, nor are we being
+	// called, or able to confirm via the state store, by a node.
+	if node == nil {
+		return structs.ErrTokenNotFound
+	}
+
+	jobID, ok := variableJobID(path)
+	if !ok {
+		return structs.ErrPermissionDenied
+	}
+
+	allocs, err := stateStore.AllocsByNodeTerminal(nil, node.ID, false)
+	if err != nil {
+		return err
+	}
+	for _, alloc := range allocs {
+		if alloc.Namespace == args.RequestNamespace() && alloc.JobID == jobID {
+			return nil
+		}
+	}
+	return structs.ErrPermissionDenied
+}
+
+// variableJobID returns the job ID that a variable belongs to, when the
+// variable path is within the reserved job path prefix.
+func variableJobID(path string) (string, bool) {
+	trimmed := strings.TrimPrefix(path, structs.VariablesJobPathPrefix+"/")
+	if trimmed == path || trimmed == "" {
+		return "", false
+	}
+	return strings.SplitN(trimmed, "/", 2)[0], true
+}
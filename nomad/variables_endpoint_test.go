@@ -0,0 +1,236 @@
+package nomad
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// waitForKeyring waits until the leader has initialized the keyring, so that
+// variables can be encrypted.
+func waitForKeyring(t *testing.T, s *Server) {
+	testutil.WaitForResult(func() (bool, error) {
+		keyMeta, err := s.State().GetActiveRootKeyMeta(memdb.NewWatchSet())
+		if err != nil {
+			return false, err
+		}
+		if keyMeta == nil {
+			return false, fmt.Errorf("keyring not initialized")
+		}
+		return s.encrypter.hasKey(keyMeta.KeyID), nil
+	}, func(err error) {
+		require.NoError(t, err)
+	})
+}
+
+func TestVariablesEndpoint_CRUD(t *testing.T) {
+	ci.Parallel(t)
+
+	s, cleanup := TestServer(t, nil)
+	defer cleanup()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+	waitForKeyring(t, s)
+
+	variable := mock.VariableDecrypted()
+
+	// Write the variable.
+	applyReq := &structs.VariablesApplyRequest{
+		Op:  structs.VarOpSet,
+		Var: variable,
+		WriteRequest: structs.WriteRequest{
+			Region:    s.Region(),
+			Namespace: variable.Namespace,
+		},
+	}
+	var applyResp structs.VariablesApplyResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesApplyRPCMethod, applyReq, &applyResp))
+	require.NotZero(t, applyResp.Index)
+	require.NotNil(t, applyResp.Output)
+	require.Equal(t, variable.Items, applyResp.Output.Items)
+	require.Equal(t, applyResp.Index, applyResp.Output.CreateIndex)
+
+	// The variable should be encrypted within state.
+	stored, err := s.State().GetVariable(nil, variable.Namespace, variable.Path)
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	require.NotContains(t, string(stored.Data), variable.Items["password"])
+
+	// Read the variable back.
+	readReq := &structs.VariablesReadRequest{
+		Path: variable.Path,
+		QueryOptions: structs.QueryOptions{
+			Region:    s.Region(),
+			Namespace: variable.Namespace,
+		},
+	}
+	var readResp structs.VariablesReadResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesReadRPCMethod, readReq, &readResp))
+	require.NotNil(t, readResp.Data)
+	require.Equal(t, variable.Items, readResp.Data.Items)
+	require.Equal(t, applyResp.Index, readResp.Index)
+
+	// List the variables; the items should not be included.
+	listReq := &structs.VariablesListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    s.Region(),
+			Namespace: variable.Namespace,
+			Prefix:    "nomad/jobs",
+		},
+	}
+	var listResp structs.VariablesListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesListRPCMethod, listReq, &listResp))
+	require.Len(t, listResp.Data, 1)
+	require.Equal(t, variable.Path, listResp.Data[0].Path)
+
+	listReq.Prefix = "secret"
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesListRPCMethod, listReq, &listResp))
+	require.Len(t, listResp.Data, 0)
+
+	// Invalid variables should be rejected.
+	invalid := variable.Copy()
+	invalid.Path = "nomad//jobs"
+	applyReq.Var = invalid
+	err = msgpackrpc.CallWithCodec(codec, structs.VariablesApplyRPCMethod, applyReq, &applyResp)
+	require.Error(t, err)
+
+	// Delete the variable, after which reads should return no data.
+	applyReq.Op = structs.VarOpDelete
+	applyReq.Var = variable
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesApplyRPCMethod, applyReq, &applyResp))
+
+	readResp = structs.VariablesReadResponse{}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesReadRPCMethod, readReq, &readResp))
+	require.Nil(t, readResp.Data)
+}
+
+func TestVariablesEndpoint_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	s, rootToken, cleanup := TestACLServer(t, nil)
+	defer cleanup()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+	waitForKeyring(t, s)
+
+	// Create a token which may only read variables below "secret/".
+	policy := `
+namespace "default" {
+  variables {
+    path "secret/*" {
+      capabilities = ["read"]
+    }
+  }
+}`
+	readToken := mock.CreatePolicyAndToken(t, s.State(), 1001, "variables-read", policy)
+
+	variable := mock.VariableDecrypted()
+	variable.Path = "secret/creds"
+
+	applyReq := &structs.VariablesApplyRequest{
+		Op:  structs.VarOpSet,
+		Var: variable,
+		WriteRequest: structs.WriteRequest{
+			Region:    s.Region(),
+			Namespace: variable.Namespace,
+			AuthToken: readToken.SecretID,
+		},
+	}
+	var applyResp structs.VariablesApplyResponse
+
+	// The read token should not be able to write.
+	err := msgpackrpc.CallWithCodec(codec, structs.VariablesApplyRPCMethod, applyReq, &applyResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// The management token should be able to write.
+	applyReq.AuthToken = rootToken.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesApplyRPCMethod, applyReq, &applyResp))
+
+	other := mock.VariableDecrypted()
+	applyReq.Var = other
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesApplyRPCMethod, applyReq, &applyResp))
+
+	// The read token should be able to read the variable it has access to,
+	// but not the other.
+	readReq := &structs.VariablesReadRequest{
+		Path: variable.Path,
+		QueryOptions: structs.QueryOptions{
+			Region:    s.Region(),
+			Namespace: variable.Namespace,
+			AuthToken: readToken.SecretID,
+		},
+	}
+	var readResp structs.VariablesReadResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesReadRPCMethod, readReq, &readResp))
+	require.Equal(t, variable.Items, readResp.Data.Items)
+
+	readReq.Path = other.Path
+	err = msgpackrpc.CallWithCodec(codec, structs.VariablesReadRPCMethod, readReq, &readResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Listing with the read token should only return the permitted variable.
+	listReq := &structs.VariablesListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    s.Region(),
+			Namespace: variable.Namespace,
+			AuthToken: readToken.SecretID,
+		},
+	}
+	var listResp structs.VariablesListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesListRPCMethod, listReq, &listResp))
+	require.Len(t, listResp.Data, 1)
+	require.Equal(t, variable.Path, listResp.Data[0].Path)
+
+	// A node may only read the variables of jobs it is running.
+	node := mock.Node()
+	require.NoError(t, s.State().UpsertNode(structs.MsgTypeTestSetup, 1010, node))
+
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	alloc.JobID = "example"
+	alloc.Job.ID = "example"
+	require.NoError(t, s.State().UpsertJob(structs.MsgTypeTestSetup, 1011, alloc.Job))
+	require.NoError(t, s.State().UpsertAllocs(structs.MsgTypeTestSetup, 1012, []*structs.Allocation{alloc}))
+
+	readReq.AuthToken = node.SecretID
+	readReq.Path = other.Path
+	readResp = structs.VariablesReadResponse{}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesReadRPCMethod, readReq, &readResp))
+	require.Equal(t, other.Items, readResp.Data.Items)
+
+	readReq.Path = variable.Path
+	err = msgpackrpc.CallWithCodec(codec, structs.VariablesReadRPCMethod, readReq, &readResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+}
+
+func TestVariables_variableJobID(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		path       string
+		expectedID string
+		expectedOK bool
+	}{
+		{path: "nomad/jobs/example", expectedID: "example", expectedOK: true},
+		{path: "nomad/jobs/example/web/task", expectedID: "example", expectedOK: true},
+		{path: "nomad/jobs", expectedOK: false},
+		{path: "nomad/jobs/", expectedOK: false},
+		{path: "secret/creds", expectedOK: false},
+		{path: "nomad/jobsexample", expectedOK: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			id, ok := variableJobID(tc.path)
+			require.Equal(t, tc.expectedOK, ok)
+			require.Equal(t, tc.expectedID, id)
+		})
+	}
+}
@@ -567,9 +567,25 @@ func (w *Worker) invokeScheduler(snap *state.StateSnapshot, eval *structs.Evalua
 	if eval.Type == structs.JobTypeCore {
 		sched = NewCoreScheduler(w.srv, snap)
 	} else {
-		sched, err = scheduler.NewScheduler(eval.Type, w.logger, w.srv.workersEventCh, snap, w)
+		job, err := snap.JobByID(nil, eval.Namespace, eval.JobID)
 		if err != nil {
-			return fmt.Errorf("failed to instantiate scheduler: %v", err)
+			return fmt.Errorf("failed to get job %q: %v", eval.JobID, err)
+		}
+
+		// Jobs may opt into a scheduler plugin. The evaluations of purged
+		// jobs are left to the built-in schedulers, which stop their
+		// allocations.
+		if job != nil && job.Scheduler != "" {
+			plugin, err := w.srv.schedulerPlugin(job.Scheduler)
+			if err != nil {
+				return fmt.Errorf("failed to instantiate scheduler: %v", err)
+			}
+			sched = scheduler.NewPluginScheduler(job.Scheduler, plugin, w.logger, snap, w)
+		} else {
+			sched, err = scheduler.NewScheduler(eval.Type, w.logger, w.srv.workersEventCh, snap, w)
+			if err != nil {
+				return fmt.Errorf("failed to instantiate scheduler: %v", err)
+			}
 		}
 	}
 
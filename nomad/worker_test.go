@@ -13,10 +13,13 @@ import (
 	"github.com/hashicorp/nomad/ci"
 	"github.com/stretchr/testify/require"
 
+	"github.com/hashicorp/nomad/helper/pluginutils/loader"
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/base"
+	schedplugin "github.com/hashicorp/nomad/plugins/scheduler"
 	"github.com/hashicorp/nomad/scheduler"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/stretchr/testify/assert"
@@ -413,6 +416,85 @@ func TestWorker_invokeScheduler(t *testing.T) {
 	require.NoError(t, err)
 }
 
+// testSchedulerPluginCatalog returns a plugin catalog serving the given
+// scheduler plugin under the name "mock".
+func testSchedulerPluginCatalog(plugin schedplugin.SchedulerPlugin) loader.PluginCatalog {
+	return &loader.MockCatalog{
+		DispenseF: func(name, pluginType string, _ *base.AgentConfig, _ log.Logger) (loader.PluginInstance, error) {
+			return loader.MockBasicExternalPlugin(plugin, schedplugin.ApiVersion010), nil
+		},
+		CatalogF: func() map[string][]*base.PluginInfoResponse {
+			return map[string][]*base.PluginInfoResponse{
+				base.PluginTypeScheduler: {
+					{
+						Name:              "mock",
+						Type:              base.PluginTypeScheduler,
+						PluginApiVersions: []string{schedplugin.ApiVersion010},
+					},
+				},
+			}
+		},
+	}
+}
+
+func TestWorker_invokeScheduler_Plugin(t *testing.T) {
+	ci.Parallel(t)
+
+	var processed []string
+	plugin := &schedplugin.MockSchedulerPlugin{
+		MockPlugin: &base.MockPlugin{},
+		ProcessF: func(eval *structs.Evaluation, _ schedplugin.StateView) (*structs.Plan, error) {
+			processed = append(processed, eval.ID)
+			return &structs.Plan{}, nil
+		},
+	}
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+		c.EnabledSchedulers = []string{structs.JobTypeService}
+		c.PluginSingletonLoader = testSchedulerPluginCatalog(plugin)
+	})
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	job := mock.Job()
+	job.Scheduler = "mock"
+	eval := mock.Eval()
+	eval.JobID = job.ID
+	require.NoError(t, s1.fsm.State().UpsertJob(structs.MsgTypeTestSetup, 1000, job))
+	require.NoError(t, s1.fsm.State().UpsertEvals(structs.MsgTypeTestSetup, 1001, []*structs.Evaluation{eval}))
+
+	s1.evalBroker.Enqueue(eval)
+	_, token, err := s1.evalBroker.Dequeue([]string{eval.Type}, time.Second)
+	require.NoError(t, err)
+
+	snap, err := s1.fsm.State().Snapshot()
+	require.NoError(t, err)
+
+	poolArgs := getSchedulerWorkerPoolArgsFromConfigLocked(s1.config).Copy()
+	w := newWorker(s1.shutdownCtx, s1, poolArgs)
+	require.NoError(t, w.invokeScheduler(snap, eval, token))
+
+	// The evaluation is processed by the plugin instead of the built-in
+	// service scheduler
+	require.Equal(t, []string{eval.ID}, processed)
+
+	out, err := s1.fsm.State().EvalByID(nil, eval.ID)
+	require.NoError(t, err)
+	require.Equal(t, structs.EvalStatusComplete, out.Status)
+
+	// Unknown plugins fail the evaluation so it is retried
+	job2 := job.Copy()
+	job2.Scheduler = "unknown"
+	require.NoError(t, s1.fsm.State().UpsertJob(structs.MsgTypeTestSetup, 1002, job2))
+	snap, err = s1.fsm.State().Snapshot()
+	require.NoError(t, err)
+
+	err = w.invokeScheduler(snap, eval, token)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `unknown scheduler plugin "unknown"`)
+}
+
 func TestWorker_SubmitPlan(t *testing.T) {
 	ci.Parallel(t)
 
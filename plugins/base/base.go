@@ -62,6 +62,11 @@ type ClientDriverConfig struct {
 	// ClientMinPort is the lower range of the ports that the client uses for
 	// communicating with plugin subsystems over loopback
 	ClientMinPort uint
+
+	// Rootless indicates whether the client runs as an unprivileged user,
+	// in which case drivers must isolate tasks using user namespaces and the
+	// cgroups delegated to that user
+	Rootless bool
 }
 
 func (c *AgentConfig) toProto() *proto.NomadConfig {
@@ -75,6 +80,7 @@ func (c *AgentConfig) toProto() *proto.NomadConfig {
 		cfg.Driver = &proto.NomadDriverConfig{
 			ClientMaxPort: uint32(c.Driver.ClientMaxPort),
 			ClientMinPort: uint32(c.Driver.ClientMinPort),
+			Rootless:      c.Driver.Rootless,
 		}
 	}
 
@@ -91,6 +97,7 @@ func nomadConfigFromProto(pb *proto.NomadConfig) *AgentConfig {
 		cfg.Driver = &ClientDriverConfig{
 			ClientMaxPort: uint(pb.Driver.ClientMaxPort),
 			ClientMinPort: uint(pb.Driver.ClientMinPort),
+			Rootless:      pb.Driver.Rootless,
 		}
 	}
 
@@ -20,6 +20,9 @@ const (
 
 	// PluginTypeDevice implements the device plugin interface
 	PluginTypeDevice = "device"
+
+	// PluginTypeScheduler implements the scheduler plugin interface
+	PluginTypeScheduler = "scheduler"
 )
 
 var (
@@ -29,21 +29,24 @@ const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 type PluginType int32
 
 const (
-	PluginType_UNKNOWN PluginType = 0
-	PluginType_DRIVER  PluginType = 2
-	PluginType_DEVICE  PluginType = 3
+	PluginType_UNKNOWN   PluginType = 0
+	PluginType_DRIVER    PluginType = 2
+	PluginType_DEVICE    PluginType = 3
+	PluginType_SCHEDULER PluginType = 4
 )
 
 var PluginType_name = map[int32]string{
 	0: "UNKNOWN",
 	2: "DRIVER",
 	3: "DEVICE",
+	4: "SCHEDULER",
 }
 
 var PluginType_value = map[string]int32{
-	"UNKNOWN": 0,
-	"DRIVER":  2,
-	"DEVICE":  3,
+	"UNKNOWN":   0,
+	"DRIVER":    2,
+	"DEVICE":    3,
+	"SCHEDULER": 4,
 }
 
 func (x PluginType) String() string {
@@ -340,7 +343,11 @@ type NomadDriverConfig struct {
 	// ClientMinPort is the lower range of the ports that the client uses for
 	// communicating with plugin subsystems over loopback
 	// buf:lint:ignore FIELD_LOWER_SNAKE_CASE
-	ClientMinPort        uint32   `protobuf:"varint,2,opt,name=ClientMinPort,proto3" json:"ClientMinPort,omitempty"`
+	ClientMinPort uint32 `protobuf:"varint,2,opt,name=ClientMinPort,proto3" json:"ClientMinPort,omitempty"`
+	// Rootless indicates whether the client runs as an unprivileged user,
+	// relying on user namespaces and delegated cgroups to isolate tasks
+	// buf:lint:ignore FIELD_LOWER_SNAKE_CASE
+	Rootless             bool     `protobuf:"varint,3,opt,name=Rootless,proto3" json:"Rootless,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -385,6 +392,13 @@ func (m *NomadDriverConfig) GetClientMinPort() uint32 {
 	return 0
 }
 
+func (m *NomadDriverConfig) GetRootless() bool {
+	if m != nil {
+		return m.Rootless
+	}
+	return false
+}
+
 // SetConfigResponse is used to respond to setting the configuration
 type SetConfigResponse struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -434,40 +448,42 @@ func init() {
 }
 
 var fileDescriptor_19edef855873449e = []byte{
-	// 521 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x53, 0x51, 0x6f, 0x12, 0x41,
-	0x10, 0xee, 0x01, 0xd2, 0x30, 0x40, 0x73, 0x2c, 0x9a, 0x10, 0x12, 0x13, 0x72, 0xb1, 0x09, 0x31,
-	0xcd, 0x12, 0x51, 0xd4, 0x47, 0x85, 0xf2, 0x40, 0x4c, 0xb1, 0x59, 0x14, 0x8d, 0x31, 0xb9, 0x6c,
-	0x8f, 0x2d, 0x77, 0x11, 0xf6, 0xd6, 0xdb, 0x6b, 0x63, 0x4d, 0x7c, 0xf2, 0xd9, 0x5f, 0xe4, 0xa3,
-	0x7f, 0xcc, 0xdc, 0xee, 0x5e, 0x39, 0x5a, 0x4d, 0x8f, 0xa7, 0x1b, 0x66, 0xbe, 0xef, 0x9b, 0x99,
-	0x8f, 0x59, 0x78, 0x28, 0x56, 0x17, 0xcb, 0x80, 0xcb, 0xde, 0x19, 0x95, 0xac, 0x27, 0xa2, 0x30,
-	0x0e, 0x55, 0x88, 0x55, 0x88, 0x1c, 0x9f, 0x4a, 0x3f, 0xf0, 0xc2, 0x48, 0x60, 0x1e, 0xae, 0xe9,
-	0x02, 0x1b, 0x38, 0xde, 0x60, 0xda, 0x87, 0xa9, 0x84, 0xf4, 0x69, 0xc4, 0x16, 0x3d, 0xdf, 0x5b,
-	0x49, 0xc1, 0xbc, 0xe4, 0xeb, 0x26, 0x81, 0x86, 0x39, 0x4d, 0x68, 0x9c, 0x2a, 0xe0, 0x84, 0x9f,
-	0x87, 0x84, 0x7d, 0xbd, 0x60, 0x32, 0x76, 0xfe, 0x58, 0x80, 0xb2, 0x59, 0x29, 0x42, 0x2e, 0x19,
-	0x1a, 0x42, 0x29, 0xbe, 0x12, 0xac, 0x65, 0x75, 0xac, 0xee, 0x41, 0x1f, 0xe3, 0xbb, 0xa7, 0xc0,
-	0x5a, 0xe5, 0xdd, 0x95, 0x60, 0x44, 0x71, 0x11, 0x86, 0xa6, 0x86, 0xb9, 0x54, 0x04, 0xee, 0x25,
-	0x8b, 0x64, 0x10, 0x72, 0xd9, 0x2a, 0x74, 0x8a, 0xdd, 0x0a, 0x69, 0xe8, 0xd2, 0x6b, 0x11, 0xcc,
-	0x4d, 0x01, 0x1d, 0xc2, 0x81, 0xc1, 0x1b, 0x6c, 0xab, 0xd8, 0xb1, 0xba, 0x15, 0x52, 0xd7, 0x59,
-	0x83, 0x43, 0x08, 0x4a, 0x9c, 0xae, 0x59, 0xab, 0xa4, 0x8a, 0x2a, 0x76, 0x1e, 0x40, 0x73, 0x14,
-	0xf2, 0xf3, 0x60, 0x39, 0xf3, 0x7c, 0xb6, 0xa6, 0xe9, 0x72, 0x1f, 0xe1, 0xfe, 0x76, 0xda, 0x6c,
-	0xf7, 0x0a, 0x4a, 0x89, 0x2f, 0x6a, 0xbb, 0x6a, 0xff, 0xe8, 0xbf, 0xdb, 0x69, 0x3f, 0xb1, 0xf1,
-	0x13, 0xcf, 0x04, 0xf3, 0x88, 0x62, 0x3a, 0xbf, 0x2d, 0xb0, 0x67, 0x2c, 0xd6, 0xea, 0xa6, 0x5d,
-	0xb2, 0xc0, 0x5a, 0x2e, 0x05, 0xf5, 0xbe, 0xb8, 0x9e, 0x2a, 0xa8, 0x06, 0x35, 0x52, 0x37, 0x59,
-	0x8d, 0x46, 0x04, 0x6a, 0xaa, 0x4d, 0x0a, 0x2a, 0xa8, 0x29, 0x7a, 0x79, 0x3c, 0x9e, 0x26, 0x05,
-	0xd3, 0xb4, 0xca, 0x37, 0x3f, 0xd0, 0x11, 0xa0, 0xdb, 0x5e, 0x1b, 0xff, 0xec, 0x9b, 0x56, 0x3b,
-	0x9f, 0xa1, 0x9a, 0x51, 0x42, 0x27, 0x50, 0x5e, 0x44, 0xc1, 0x25, 0x8b, 0x8c, 0x21, 0x83, 0xdc,
-	0xa3, 0x1c, 0x2b, 0x9a, 0x19, 0xc8, 0x88, 0x38, 0x2e, 0x34, 0x6e, 0x15, 0xd1, 0x23, 0xa8, 0x8f,
-	0x56, 0x01, 0xe3, 0xf1, 0x09, 0xfd, 0x76, 0x1a, 0x46, 0xb1, 0x6a, 0x55, 0x27, 0xdb, 0xc9, 0x0c,
-	0x2a, 0xe0, 0x0a, 0x55, 0xd8, 0x42, 0xe9, 0x64, 0x72, 0xc8, 0x19, 0xef, 0xf5, 0x7f, 0xfa, 0xf8,
-	0x09, 0xc0, 0xe6, 0x02, 0x51, 0x15, 0xf6, 0xdf, 0x4f, 0xdf, 0x4c, 0xdf, 0x7e, 0x98, 0xda, 0x7b,
-	0x08, 0xa0, 0x7c, 0x4c, 0x26, 0xf3, 0x31, 0xb1, 0x0b, 0x2a, 0x1e, 0xcf, 0x27, 0xa3, 0xb1, 0x5d,
-	0xec, 0xff, 0x2a, 0x02, 0x0c, 0xa9, 0x64, 0x9a, 0x87, 0x7e, 0xa4, 0x0a, 0xc9, 0x4b, 0x40, 0x83,
-	0xfc, 0x37, 0x9f, 0x79, 0x4f, 0xed, 0xe7, 0xbb, 0xd2, 0xf4, 0xf8, 0xce, 0x1e, 0xfa, 0x69, 0x41,
-	0x2d, 0x7b, 0xad, 0xe8, 0x45, 0x1e, 0xa9, 0x7f, 0x9c, 0x7d, 0xfb, 0xe5, 0xee, 0xc4, 0xeb, 0x29,
-	0xbe, 0x43, 0xe5, 0xda, 0x5b, 0xf4, 0x2c, 0x8f, 0xd0, 0xcd, 0x67, 0xd0, 0x1e, 0xec, 0xc8, 0x4a,
-	0x7b, 0x0f, 0xf7, 0x3f, 0xdd, 0x53, 0xc5, 0xb3, 0xb2, 0xfa, 0x3c, 0xfd, 0x1b, 0x00, 0x00, 0xff,
-	0xff, 0xdc, 0x0f, 0xf5, 0x51, 0x1c, 0x05, 0x00, 0x00,
+	// 546 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x53, 0xdf, 0x6f, 0x12, 0x4f,
+	0x10, 0xef, 0x01, 0x5f, 0x5a, 0x06, 0x68, 0x8e, 0xe1, 0x6b, 0x42, 0x48, 0x4c, 0xc8, 0xc5, 0x26,
+	0xc4, 0x34, 0x47, 0x82, 0xa2, 0x3e, 0x56, 0x7e, 0x24, 0x12, 0x2d, 0x36, 0x8b, 0x45, 0x63, 0x4c,
+	0xc8, 0xf5, 0xd8, 0xc2, 0x45, 0xd8, 0x5d, 0x6f, 0xaf, 0x8d, 0xd5, 0xf8, 0xe4, 0xb3, 0x7f, 0x91,
+	0x8f, 0xfe, 0x63, 0xe6, 0x76, 0x17, 0x38, 0x5a, 0x8d, 0xf0, 0xb4, 0xb3, 0x33, 0x9f, 0xf9, 0xcc,
+	0xcc, 0x67, 0x77, 0xe0, 0xbe, 0x98, 0x5f, 0x4d, 0x03, 0x26, 0x1b, 0x17, 0x9e, 0xa4, 0x0d, 0x11,
+	0xf2, 0x88, 0x2b, 0xd3, 0x55, 0x26, 0x3a, 0x33, 0x4f, 0xce, 0x02, 0x9f, 0x87, 0xc2, 0x65, 0x7c,
+	0xe1, 0x4d, 0x5c, 0x03, 0x77, 0xd7, 0x98, 0xea, 0xd1, 0x92, 0x42, 0xce, 0xbc, 0x90, 0x4e, 0x1a,
+	0x33, 0x7f, 0x2e, 0x05, 0xf5, 0xe3, 0x73, 0x1c, 0x1b, 0x1a, 0xe6, 0x94, 0xa1, 0x74, 0xa6, 0x80,
+	0x7d, 0x76, 0xc9, 0x09, 0xfd, 0x74, 0x45, 0x65, 0xe4, 0xfc, 0xb2, 0x00, 0x93, 0x5e, 0x29, 0x38,
+	0x93, 0x14, 0xdb, 0x90, 0x89, 0x6e, 0x04, 0xad, 0x58, 0x35, 0xab, 0x7e, 0xd8, 0x74, 0xdd, 0x7f,
+	0x77, 0xe1, 0x6a, 0x96, 0x37, 0x37, 0x82, 0x12, 0x95, 0x8b, 0x2e, 0x94, 0x35, 0x6c, 0xec, 0x89,
+	0x60, 0x7c, 0x4d, 0x43, 0x19, 0x70, 0x26, 0x2b, 0xa9, 0x5a, 0xba, 0x9e, 0x23, 0x25, 0x1d, 0x7a,
+	0x2e, 0x82, 0x91, 0x09, 0xe0, 0x11, 0x1c, 0x1a, 0xbc, 0xc1, 0x56, 0xd2, 0x35, 0xab, 0x9e, 0x23,
+	0x45, 0xed, 0x35, 0x38, 0x44, 0xc8, 0x30, 0x6f, 0x41, 0x2b, 0x19, 0x15, 0x54, 0xb6, 0x73, 0x0f,
+	0xca, 0x1d, 0xce, 0x2e, 0x83, 0xe9, 0xd0, 0x9f, 0xd1, 0x85, 0xb7, 0x1c, 0xee, 0x1d, 0xfc, 0xbf,
+	0xe9, 0x36, 0xd3, 0x9d, 0x40, 0x26, 0xd6, 0x45, 0x4d, 0x97, 0x6f, 0x1e, 0xff, 0x75, 0x3a, 0xad,
+	0xa7, 0x6b, 0xf4, 0x74, 0x87, 0x82, 0xfa, 0x44, 0x65, 0x3a, 0x3f, 0x2d, 0xb0, 0x87, 0x34, 0xd2,
+	0xec, 0xa6, 0x5c, 0x3c, 0xc0, 0x42, 0x4e, 0x85, 0xe7, 0x7f, 0x1c, 0xfb, 0x2a, 0xa0, 0x0a, 0x14,
+	0x48, 0xd1, 0x78, 0x35, 0x1a, 0x09, 0x14, 0x54, 0x99, 0x25, 0x28, 0xa5, 0xba, 0x68, 0x6c, 0xa3,
+	0xf1, 0x20, 0x0e, 0x98, 0xa2, 0x79, 0xb6, 0xbe, 0xe0, 0x31, 0xe0, 0x5d, 0xad, 0x8d, 0x7e, 0xf6,
+	0x6d, 0xa9, 0x9d, 0x0f, 0x90, 0x4f, 0x30, 0xe1, 0x29, 0x64, 0x27, 0x61, 0x70, 0x4d, 0x43, 0x23,
+	0x48, 0x6b, 0xeb, 0x56, 0xba, 0x2a, 0xcd, 0x34, 0x64, 0x48, 0x9c, 0xaf, 0x50, 0xba, 0x13, 0xc4,
+	0x07, 0x50, 0xec, 0xcc, 0x03, 0xca, 0xa2, 0x53, 0xef, 0xf3, 0x19, 0x0f, 0x23, 0x55, 0xaa, 0x48,
+	0x36, 0x9d, 0x09, 0x54, 0xc0, 0x14, 0x2a, 0xb5, 0x81, 0xd2, 0x4e, 0xac, 0xc2, 0x01, 0xe1, 0x3c,
+	0x9a, 0x53, 0x29, 0xd5, 0x88, 0x07, 0x64, 0x75, 0x8f, 0x3f, 0x79, 0xe2, 0x5d, 0xf4, 0x7b, 0x3f,
+	0x3c, 0x01, 0x58, 0xff, 0x4e, 0xcc, 0xc3, 0xfe, 0xf9, 0xe0, 0xe5, 0xe0, 0xf5, 0xdb, 0x81, 0xbd,
+	0x87, 0x00, 0xd9, 0x2e, 0xe9, 0x8f, 0x7a, 0xc4, 0x4e, 0x29, 0xbb, 0x37, 0xea, 0x77, 0x7a, 0x76,
+	0x1a, 0x8b, 0x90, 0x1b, 0x76, 0x5e, 0xf4, 0xba, 0xe7, 0xaf, 0x7a, 0xc4, 0xce, 0x34, 0x7f, 0xa4,
+	0x01, 0xda, 0x9e, 0xa4, 0x9a, 0x06, 0xbf, 0x01, 0xac, 0x97, 0x06, 0x5b, 0xdb, 0xaf, 0x47, 0x62,
+	0xf5, 0xaa, 0x4f, 0x76, 0x4d, 0xd3, 0xd3, 0x38, 0x7b, 0xf8, 0xdd, 0x82, 0x42, 0xf2, 0x63, 0xe3,
+	0xd3, 0x6d, 0xa8, 0xfe, 0xb0, 0x21, 0xd5, 0x67, 0xbb, 0x27, 0xae, 0xba, 0xf8, 0x02, 0xb9, 0x95,
+	0xd4, 0xf8, 0x78, 0x1b, 0xa2, 0xdb, 0x1b, 0x53, 0x6d, 0xed, 0x98, 0xb5, 0xac, 0xdd, 0xde, 0x7f,
+	0xff, 0x9f, 0x0a, 0x5e, 0x64, 0xd5, 0xf1, 0xe8, 0xf7, 0x00, 0x92, 0x07, 0xd2, 0xa3, 0x47, 0x05,
+	0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -26,6 +26,8 @@ func (b *basePluginServer) PluginInfo(context.Context, *proto.PluginInfoRequest)
 		ptype = proto.PluginType_DRIVER
 	case PluginTypeDevice:
 		ptype = proto.PluginType_DEVICE
+	case PluginTypeScheduler:
+		ptype = proto.PluginType_SCHEDULER
 	default:
 		return nil, fmt.Errorf("plugin is of unknown type: %q", resp.Type)
 	}
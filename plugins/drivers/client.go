@@ -74,6 +74,7 @@ func (d *driverPluginClient) Capabilities() (*Capabilities, error) {
 
 		caps.MountConfigs = MountConfigSupport(resp.Capabilities.MountConfigs)
 		caps.RemoteTasks = resp.Capabilities.RemoteTasks
+		caps.Checkpoint = resp.Capabilities.Checkpoint
 	}
 
 	return caps, nil
@@ -145,28 +146,56 @@ func (d *driverPluginClient) StartTask(c *TaskConfig) (*TaskHandle, *DriverNetwo
 
 	resp, err := d.client.StartTask(d.doneCtx, req)
 	if err != nil {
-		st := status.Convert(err)
-		if len(st.Details()) > 0 {
-			if rec, ok := st.Details()[0].(*sproto.RecoverableError); ok {
-				return nil, nil, structs.NewRecoverableError(err, rec.Recoverable)
-			}
-		}
-		return nil, nil, grpcutils.HandleGrpcErr(err, d.doneCtx)
+		return nil, nil, d.startErr(err)
 	}
 
-	var net *DriverNetwork
-	if resp.NetworkOverride != nil {
-		net = &DriverNetwork{
-			PortMap:       map[string]int{},
-			IP:            resp.NetworkOverride.Addr,
-			AutoAdvertise: resp.NetworkOverride.AutoAdvertise,
-		}
-		for k, v := range resp.NetworkOverride.PortMap {
-			net.PortMap[k] = int(v)
+	return taskHandleFromProto(resp.Handle), driverNetworkFromProto(resp.NetworkOverride), nil
+}
+
+// RestoreTask starts a task from the checkpoint written to dir by
+// CheckpointTask, instead of starting it from scratch. Like StartTask, a
+// TaskHandle is returned to the caller to recover state of the task.
+func (d *driverPluginClient) RestoreTask(c *TaskConfig, dir string) (*TaskHandle, *DriverNetwork, error) {
+	req := &proto.RestoreTaskRequest{
+		Task: taskConfigToProto(c),
+		Dir:  dir,
+	}
+
+	resp, err := d.client.RestoreTask(d.doneCtx, req)
+	if err != nil {
+		return nil, nil, d.startErr(err)
+	}
+
+	return taskHandleFromProto(resp.Handle), driverNetworkFromProto(resp.NetworkOverride), nil
+}
+
+// startErr converts the error of a StartTask or RestoreTask RPC, preserving
+// whether it is recoverable.
+func (d *driverPluginClient) startErr(err error) error {
+	st := status.Convert(err)
+	if len(st.Details()) > 0 {
+		if rec, ok := st.Details()[0].(*sproto.RecoverableError); ok {
+			return structs.NewRecoverableError(err, rec.Recoverable)
 		}
 	}
+	return grpcutils.HandleGrpcErr(err, d.doneCtx)
+}
+
+// driverNetworkFromProto converts the network override of a started task.
+func driverNetworkFromProto(pb *proto.NetworkOverride) *DriverNetwork {
+	if pb == nil {
+		return nil
+	}
 
-	return taskHandleFromProto(resp.Handle), net, nil
+	net := &DriverNetwork{
+		PortMap:       map[string]int{},
+		IP:            pb.Addr,
+		AutoAdvertise: pb.AutoAdvertise,
+	}
+	for k, v := range pb.PortMap {
+		net.PortMap[k] = int(v)
+	}
+	return net
 }
 
 // WaitTask returns a channel that will have an ExitResult pushed to it once when the task
@@ -218,6 +247,18 @@ func (d *driverPluginClient) StopTask(taskID string, timeout time.Duration, sign
 	return grpcutils.HandleGrpcErr(err, d.doneCtx)
 }
 
+// CheckpointTask dumps the state of the task with the given taskID into dir
+// and stops the task, so that it can later be restored by RestoreTask.
+func (d *driverPluginClient) CheckpointTask(taskID string, dir string) error {
+	req := &proto.CheckpointTaskRequest{
+		TaskId: taskID,
+		Dir:    dir,
+	}
+
+	_, err := d.client.CheckpointTask(d.doneCtx, req)
+	return grpcutils.HandleGrpcErr(err, d.doneCtx)
+}
+
 // DestroyTask removes the task from the driver's in memory state. The task
 // cannot be running unless force is set to true. If force is set to true the
 // driver will forcefully terminate the task before removing it.
@@ -62,6 +62,9 @@ type DriverPlugin interface {
 
 	SignalTask(taskID string, signal string) error
 	ExecTask(taskID string, cmd []string, timeout time.Duration) (*ExecTaskResult, error)
+
+	CheckpointTask(taskID string, dir string) error
+	RestoreTask(cfg *TaskConfig, dir string) (*TaskHandle, *DriverNetwork, error)
 }
 
 // ExecTaskStreamingDriver marks that a driver supports streaming exec task.  This represents a user friendly
@@ -112,6 +115,19 @@ func (_ DriverExecTaskNotSupported) ExecTask(taskID string, cmd []string, timeou
 	return nil, fmt.Errorf("ExecTask is not supported by this driver")
 }
 
+// DriverCheckpointNotSupported can be embedded by drivers which don't support
+// the CheckpointTask and RestoreTask RPCs. This satisfies the CheckpointTask
+// and RestoreTask func requirements of the DriverPlugin interface.
+type DriverCheckpointNotSupported struct{}
+
+func (DriverCheckpointNotSupported) CheckpointTask(taskID, dir string) error {
+	return fmt.Errorf("CheckpointTask is not supported by this driver")
+}
+
+func (DriverCheckpointNotSupported) RestoreTask(cfg *TaskConfig, dir string) (*TaskHandle, *DriverNetwork, error) {
+	return nil, nil, fmt.Errorf("RestoreTask is not supported by this driver")
+}
+
 type HealthState string
 
 var (
@@ -171,6 +187,11 @@ type Capabilities struct {
 	// adjust behavior such as propogating task handles between allocations
 	// to avoid downtime when a client is lost.
 	RemoteTasks bool
+
+	// Checkpoint indicates this driver can checkpoint a running task into a
+	// directory and later restore it, possibly on another client, in place
+	// of starting it from scratch.
+	Checkpoint bool
 }
 
 func (c *Capabilities) HasNetIsolationMode(m NetIsolationMode) bool {
@@ -137,7 +137,7 @@ func (x DriverCapabilities_FSIsolation) String() string {
 }
 
 func (DriverCapabilities_FSIsolation) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_4a8f45747846a74d, []int{32, 0}
+	return fileDescriptor_4a8f45747846a74d, []int{36, 0}
 }
 
 type DriverCapabilities_MountConfigs int32
@@ -165,7 +165,7 @@ func (x DriverCapabilities_MountConfigs) String() string {
 }
 
 func (DriverCapabilities_MountConfigs) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_4a8f45747846a74d, []int{32, 1}
+	return fileDescriptor_4a8f45747846a74d, []int{36, 1}
 }
 
 type NetworkIsolationSpec_NetworkIsolationMode int32
@@ -196,7 +196,7 @@ func (x NetworkIsolationSpec_NetworkIsolationMode) String() string {
 }
 
 func (NetworkIsolationSpec_NetworkIsolationMode) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_4a8f45747846a74d, []int{33, 0}
+	return fileDescriptor_4a8f45747846a74d, []int{37, 0}
 }
 
 type CPUUsage_Fields int32
@@ -233,7 +233,7 @@ func (x CPUUsage_Fields) String() string {
 }
 
 func (CPUUsage_Fields) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_4a8f45747846a74d, []int{54, 0}
+	return fileDescriptor_4a8f45747846a74d, []int{58, 0}
 }
 
 type MemoryUsage_Fields int32
@@ -273,7 +273,7 @@ func (x MemoryUsage_Fields) String() string {
 }
 
 func (MemoryUsage_Fields) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_4a8f45747846a74d, []int{55, 0}
+	return fileDescriptor_4a8f45747846a74d, []int{59, 0}
 }
 
 type TaskConfigSchemaRequest struct {
@@ -1833,6 +1833,186 @@ func (m *DestroyNetworkResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_DestroyNetworkResponse proto.InternalMessageInfo
 
+type CheckpointTaskRequest struct {
+	// TaskId is the ID of the target task
+	TaskId string `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	// Dir is the directory the checkpoint of the task is written to
+	Dir                  string   `protobuf:"bytes,2,opt,name=dir,proto3" json:"dir,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckpointTaskRequest) Reset()         { *m = CheckpointTaskRequest{} }
+func (m *CheckpointTaskRequest) String() string { return proto.CompactTextString(m) }
+func (*CheckpointTaskRequest) ProtoMessage()    {}
+func (*CheckpointTaskRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4a8f45747846a74d, []int{32}
+}
+
+func (m *CheckpointTaskRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckpointTaskRequest.Unmarshal(m, b)
+}
+func (m *CheckpointTaskRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckpointTaskRequest.Marshal(b, m, deterministic)
+}
+func (m *CheckpointTaskRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckpointTaskRequest.Merge(m, src)
+}
+func (m *CheckpointTaskRequest) XXX_Size() int {
+	return xxx_messageInfo_CheckpointTaskRequest.Size(m)
+}
+func (m *CheckpointTaskRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckpointTaskRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckpointTaskRequest proto.InternalMessageInfo
+
+func (m *CheckpointTaskRequest) GetTaskId() string {
+	if m != nil {
+		return m.TaskId
+	}
+	return ""
+}
+
+func (m *CheckpointTaskRequest) GetDir() string {
+	if m != nil {
+		return m.Dir
+	}
+	return ""
+}
+
+type CheckpointTaskResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckpointTaskResponse) Reset()         { *m = CheckpointTaskResponse{} }
+func (m *CheckpointTaskResponse) String() string { return proto.CompactTextString(m) }
+func (*CheckpointTaskResponse) ProtoMessage()    {}
+func (*CheckpointTaskResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4a8f45747846a74d, []int{33}
+}
+
+func (m *CheckpointTaskResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckpointTaskResponse.Unmarshal(m, b)
+}
+func (m *CheckpointTaskResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckpointTaskResponse.Marshal(b, m, deterministic)
+}
+func (m *CheckpointTaskResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckpointTaskResponse.Merge(m, src)
+}
+func (m *CheckpointTaskResponse) XXX_Size() int {
+	return xxx_messageInfo_CheckpointTaskResponse.Size(m)
+}
+func (m *CheckpointTaskResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckpointTaskResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckpointTaskResponse proto.InternalMessageInfo
+
+type RestoreTaskRequest struct {
+	// Task is the configuration of the restored task
+	Task *TaskConfig `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	// Dir is the directory the checkpoint of the task is read from
+	Dir                  string   `protobuf:"bytes,2,opt,name=dir,proto3" json:"dir,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RestoreTaskRequest) Reset()         { *m = RestoreTaskRequest{} }
+func (m *RestoreTaskRequest) String() string { return proto.CompactTextString(m) }
+func (*RestoreTaskRequest) ProtoMessage()    {}
+func (*RestoreTaskRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4a8f45747846a74d, []int{34}
+}
+
+func (m *RestoreTaskRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestoreTaskRequest.Unmarshal(m, b)
+}
+func (m *RestoreTaskRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestoreTaskRequest.Marshal(b, m, deterministic)
+}
+func (m *RestoreTaskRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestoreTaskRequest.Merge(m, src)
+}
+func (m *RestoreTaskRequest) XXX_Size() int {
+	return xxx_messageInfo_RestoreTaskRequest.Size(m)
+}
+func (m *RestoreTaskRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestoreTaskRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestoreTaskRequest proto.InternalMessageInfo
+
+func (m *RestoreTaskRequest) GetTask() *TaskConfig {
+	if m != nil {
+		return m.Task
+	}
+	return nil
+}
+
+func (m *RestoreTaskRequest) GetDir() string {
+	if m != nil {
+		return m.Dir
+	}
+	return ""
+}
+
+type RestoreTaskResponse struct {
+	// Handle is opaque to the client, but must be stored in order to recover
+	// the task.
+	Handle *TaskHandle `protobuf:"bytes,1,opt,name=handle,proto3" json:"handle,omitempty"`
+	// NetworkOverride is set if the driver sets network settings and the service ip/port
+	// needs to be set differently.
+	NetworkOverride      *NetworkOverride `protobuf:"bytes,2,opt,name=network_override,json=networkOverride,proto3" json:"network_override,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *RestoreTaskResponse) Reset()         { *m = RestoreTaskResponse{} }
+func (m *RestoreTaskResponse) String() string { return proto.CompactTextString(m) }
+func (*RestoreTaskResponse) ProtoMessage()    {}
+func (*RestoreTaskResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4a8f45747846a74d, []int{35}
+}
+
+func (m *RestoreTaskResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestoreTaskResponse.Unmarshal(m, b)
+}
+func (m *RestoreTaskResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestoreTaskResponse.Marshal(b, m, deterministic)
+}
+func (m *RestoreTaskResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestoreTaskResponse.Merge(m, src)
+}
+func (m *RestoreTaskResponse) XXX_Size() int {
+	return xxx_messageInfo_RestoreTaskResponse.Size(m)
+}
+func (m *RestoreTaskResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestoreTaskResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestoreTaskResponse proto.InternalMessageInfo
+
+func (m *RestoreTaskResponse) GetHandle() *TaskHandle {
+	if m != nil {
+		return m.Handle
+	}
+	return nil
+}
+
+func (m *RestoreTaskResponse) GetNetworkOverride() *NetworkOverride {
+	if m != nil {
+		return m.NetworkOverride
+	}
+	return nil
+}
+
 type DriverCapabilities struct {
 	// SendSignals indicates that the driver can send process signals (ex. SIGUSR1)
 	// to the task.
@@ -1848,7 +2028,10 @@ type DriverCapabilities struct {
 	MountConfigs DriverCapabilities_MountConfigs `protobuf:"varint,6,opt,name=mount_configs,json=mountConfigs,proto3,enum=hashicorp.nomad.plugins.drivers.proto.DriverCapabilities_MountConfigs" json:"mount_configs,omitempty"`
 	// remote_tasks indicates whether the driver executes tasks remotely such
 	// on cloud runtimes like AWS ECS.
-	RemoteTasks          bool     `protobuf:"varint,7,opt,name=remote_tasks,json=remoteTasks,proto3" json:"remote_tasks,omitempty"`
+	RemoteTasks bool `protobuf:"varint,7,opt,name=remote_tasks,json=remoteTasks,proto3" json:"remote_tasks,omitempty"`
+	// checkpoint indicates whether the driver implements the CheckpointTask
+	// and RestoreTask RPCs.
+	Checkpoint           bool     `protobuf:"varint,8,opt,name=checkpoint,proto3" json:"checkpoint,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1858,7 +2041,7 @@ func (m *DriverCapabilities) Reset()         { *m = DriverCapabilities{} }
 func (m *DriverCapabilities) String() string { return proto.CompactTextString(m) }
 func (*DriverCapabilities) ProtoMessage()    {}
 func (*DriverCapabilities) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4a8f45747846a74d, []int{32}
+	return fileDescriptor_4a8f45747846a74d, []int{36}
 }
 
 func (m *DriverCapabilities) XXX_Unmarshal(b []byte) error {
@@ -1928,6 +2111,13 @@ func (m *DriverCapabilities) GetRemoteTasks() bool {
 	return false
 }
 
+func (m *DriverCapabilities) GetCheckpoint() bool {
+	if m != nil {
+		return m.Checkpoint
+	}
+	return false
+}
+
 type NetworkIsolationSpec struct {
 	Mode                 NetworkIsolationSpec_NetworkIsolationMode `protobuf:"varint,1,opt,name=mode,proto3,enum=hashicorp.nomad.plugins.drivers.proto.NetworkIsolationSpec_NetworkIsolationMode" json:"mode,omitempty"`
 	Path                 string                                    `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
@@ -1942,7 +2132,7 @@ func (m *NetworkIsolationSpec) Reset()         { *m = NetworkIsolationSpec{} }
 func (m *NetworkIsolationSpec) String() string { return proto.CompactTextString(m) }
 func (*NetworkIsolationSpec) ProtoMessage()    {}
 func (*NetworkIsolationSpec) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4a8f45747846a74d, []int{33}
+	return fileDescriptor_4a8f45747846a74d, []int{37}
 }
 
 func (m *NetworkIsolationSpec) XXX_Unmarshal(b []byte) error {
@@ -2003,7 +2193,7 @@ func (m *HostsConfig) Reset()         { *m = HostsConfig{} }
 func (m *HostsConfig) String() string { return proto.CompactTextString(m) }
 func (*HostsConfig) ProtoMessage()    {}
 func (*HostsConfig) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4a8f45747846a74d, []int{34}
+	return fileDescriptor_4a8f45747846a74d, []int{38}
 }
 
 func (m *HostsConfig) XXX_Unmarshal(b []byte) error {
@@ -2051,7 +2241,7 @@ func (m *DNSConfig) Reset()         { *m = DNSConfig{} }
 func (m *DNSConfig) String() string { return proto.CompactTextString(m) }
 func (*DNSConfig) ProtoMessage()    {}
 func (*DNSConfig) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4a8f45747846a74d, []int{35}
+	return fileDescriptor_4a8f45747846a74d, []int{39}
 }
 
 func (m *DNSConfig) XXX_Unmarshal(b []byte) error {
@@ -2143,7 +2333,7 @@ func (m *TaskConfig) Reset()         { *m = TaskConfig{} }
 func (m *TaskConfig) String() string { return proto.CompactTextString(m) }
 func (*TaskConfig) ProtoMessage()    {}
 func (*TaskConfig) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4a8f45747846a74d, []int{36}
+	return fileDescriptor_4a8f45747846a74d, []int{40}
 }
 
 func (m *TaskConfig) XXX_Unmarshal(b []byte) error {
@@ -2300,7 +2490,7 @@ func (m *Resources) Reset()         { *m = Resources{} }
 func (m *Resources) String() string { return proto.CompactTextString(m) }
 func (*Resources) ProtoMessage()    {}
 func (*Resources) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4a8f45747846a74d, []int{37}
+	return fileDescriptor_4a8f45747846a74d, []int{41}
 }
 
 func (m *Resources) XXX_Unmarshal(b []byte) error {
@@ -2355,7 +2545,7 @@ func (m *AllocatedTaskResources) Reset()         { *m = AllocatedTaskResources{}
 func (m *AllocatedTaskResources) String() string { return proto.CompactTextString(m) }
 func (*AllocatedTaskResources) ProtoMessage()    {}
 func (*AllocatedTaskResources) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4a8f45747846a74d, []int{38}
+	return fileDescriptor_4a8f45747846a74d, []int{42}
 }
 
 func (m *AllocatedTaskResources) XXX_Unmarshal(b []byte) error {
@@ -2408,7 +2598,7 @@ func (m *AllocatedCpuResources) Reset()         { *m = AllocatedCpuResources{} }
 func (m *AllocatedCpuResources) String() string { return proto.CompactTextString(m) }
 func (*AllocatedCpuResources) ProtoMessage()    {}
 func (*AllocatedCpuResources) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4a8f45747846a74d, []int{39}
+	return fileDescriptor_4a8f45747846a74d, []int{43}
 }
 
 func (m *AllocatedCpuResources) XXX_Unmarshal(b []byte) error {
@@ -2448,7 +2638,7 @@ func (m *AllocatedMemoryResources) Reset()         { *m = AllocatedMemoryResourc
 func (m *AllocatedMemoryResources) String() string { return proto.CompactTextString(m) }
 func (*AllocatedMemoryResources) ProtoMessage()    {}
 func (*AllocatedMemoryResources) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4a8f45747846a74d, []int{40}
+	return fileDescriptor_4a8f45747846a74d, []int{44}
 }
 
 func (m *AllocatedMemoryResources) XXX_Unmarshal(b []byte) error {
@@ -2499,7 +2689,7 @@ func (m *NetworkResource) Reset()         { *m = NetworkResource{} }
 func (m *NetworkResource) String() string { return proto.CompactTextString(m) }
 func (*NetworkResource) ProtoMessage()    {}
 func (*NetworkResource) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4a8f45747846a74d, []int{41}
+	return fileDescriptor_4a8f45747846a74d, []int{45}
 }
 
 func (m *NetworkResource) XXX_Unmarshal(b []byte) error {
@@ -2574,7 +2764,7 @@ func (m *NetworkPort) Reset()         { *m = NetworkPort{} }
 func (m *NetworkPort) String() string { return proto.CompactTextString(m) }
 func (*NetworkPort) ProtoMessage()    {}
 func (*NetworkPort) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4a8f45747846a74d, []int{42}
+	return fileDescriptor_4a8f45747846a74d, []int{46}
 }
 
 func (m *NetworkPort) XXX_Unmarshal(b []byte) error {
@@ -2623,7 +2813,7 @@ func (m *PortMapping) Reset()         { *m = PortMapping{} }
 func (m *PortMapping) String() string { return proto.CompactTextString(m) }
 func (*PortMapping) ProtoMessage()    {}
 func (*PortMapping) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4a8f45747846a74d, []int{43}
+	return fileDescriptor_4a8f45747846a74d, []int{47}
 }
 
 func (m *PortMapping) XXX_Unmarshal(b []byte) error {
@@ -2700,7 +2890,7 @@ func (m *LinuxResources) Reset()         { *m = LinuxResources{} }
 func (m *LinuxResources) String() string { return proto.CompactTextString(m) }
 func (*LinuxResources) ProtoMessage()    {}
 func (*LinuxResources) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4a8f45747846a74d, []int{44}
+	return fileDescriptor_4a8f45747846a74d, []int{48}
 }
 
 func (m *LinuxResources) XXX_Unmarshal(b []byte) error {
@@ -2793,7 +2983,7 @@ func (m *Mount) Reset()         { *m = Mount{} }
 func (m *Mount) String() string { return proto.CompactTextString(m) }
 func (*Mount) ProtoMessage()    {}
 func (*Mount) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4a8f45747846a74d, []int{45}
+	return fileDescriptor_4a8f45747846a74d, []int{49}
 }
 
 func (m *Mount) XXX_Unmarshal(b []byte) error {
@@ -2857,7 +3047,7 @@ func (m *Device) Reset()         { *m = Device{} }
 func (m *Device) String() string { return proto.CompactTextString(m) }
 func (*Device) ProtoMessage()    {}
 func (*Device) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4a8f45747846a74d, []int{46}
+	return fileDescriptor_4a8f45747846a74d, []int{50}
 }
 
 func (m *Device) XXX_Unmarshal(b []byte) error {
@@ -2919,7 +3109,7 @@ func (m *TaskHandle) Reset()         { *m = TaskHandle{} }
 func (m *TaskHandle) String() string { return proto.CompactTextString(m) }
 func (*TaskHandle) ProtoMessage()    {}
 func (*TaskHandle) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4a8f45747846a74d, []int{47}
+	return fileDescriptor_4a8f45747846a74d, []int{51}
 }
 
 func (m *TaskHandle) XXX_Unmarshal(b []byte) error {
@@ -2987,7 +3177,7 @@ func (m *NetworkOverride) Reset()         { *m = NetworkOverride{} }
 func (m *NetworkOverride) String() string { return proto.CompactTextString(m) }
 func (*NetworkOverride) ProtoMessage()    {}
 func (*NetworkOverride) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4a8f45747846a74d, []int{48}
+	return fileDescriptor_4a8f45747846a74d, []int{52}
 }
 
 func (m *NetworkOverride) XXX_Unmarshal(b []byte) error {
@@ -3046,7 +3236,7 @@ func (m *ExitResult) Reset()         { *m = ExitResult{} }
 func (m *ExitResult) String() string { return proto.CompactTextString(m) }
 func (*ExitResult) ProtoMessage()    {}
 func (*ExitResult) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4a8f45747846a74d, []int{49}
+	return fileDescriptor_4a8f45747846a74d, []int{53}
 }
 
 func (m *ExitResult) XXX_Unmarshal(b []byte) error {
@@ -3110,7 +3300,7 @@ func (m *TaskStatus) Reset()         { *m = TaskStatus{} }
 func (m *TaskStatus) String() string { return proto.CompactTextString(m) }
 func (*TaskStatus) ProtoMessage()    {}
 func (*TaskStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4a8f45747846a74d, []int{50}
+	return fileDescriptor_4a8f45747846a74d, []int{54}
 }
 
 func (m *TaskStatus) XXX_Unmarshal(b []byte) error {
@@ -3186,7 +3376,7 @@ func (m *TaskDriverStatus) Reset()         { *m = TaskDriverStatus{} }
 func (m *TaskDriverStatus) String() string { return proto.CompactTextString(m) }
 func (*TaskDriverStatus) ProtoMessage()    {}
 func (*TaskDriverStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4a8f45747846a74d, []int{51}
+	return fileDescriptor_4a8f45747846a74d, []int{55}
 }
 
 func (m *TaskDriverStatus) XXX_Unmarshal(b []byte) error {
@@ -3232,7 +3422,7 @@ func (m *TaskStats) Reset()         { *m = TaskStats{} }
 func (m *TaskStats) String() string { return proto.CompactTextString(m) }
 func (*TaskStats) ProtoMessage()    {}
 func (*TaskStats) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4a8f45747846a74d, []int{52}
+	return fileDescriptor_4a8f45747846a74d, []int{56}
 }
 
 func (m *TaskStats) XXX_Unmarshal(b []byte) error {
@@ -3295,7 +3485,7 @@ func (m *TaskResourceUsage) Reset()         { *m = TaskResourceUsage{} }
 func (m *TaskResourceUsage) String() string { return proto.CompactTextString(m) }
 func (*TaskResourceUsage) ProtoMessage()    {}
 func (*TaskResourceUsage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4a8f45747846a74d, []int{53}
+	return fileDescriptor_4a8f45747846a74d, []int{57}
 }
 
 func (m *TaskResourceUsage) XXX_Unmarshal(b []byte) error {
@@ -3348,7 +3538,7 @@ func (m *CPUUsage) Reset()         { *m = CPUUsage{} }
 func (m *CPUUsage) String() string { return proto.CompactTextString(m) }
 func (*CPUUsage) ProtoMessage()    {}
 func (*CPUUsage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4a8f45747846a74d, []int{54}
+	return fileDescriptor_4a8f45747846a74d, []int{58}
 }
 
 func (m *CPUUsage) XXX_Unmarshal(b []byte) error {
@@ -3437,7 +3627,7 @@ func (m *MemoryUsage) Reset()         { *m = MemoryUsage{} }
 func (m *MemoryUsage) String() string { return proto.CompactTextString(m) }
 func (*MemoryUsage) ProtoMessage()    {}
 func (*MemoryUsage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4a8f45747846a74d, []int{55}
+	return fileDescriptor_4a8f45747846a74d, []int{59}
 }
 
 func (m *MemoryUsage) XXX_Unmarshal(b []byte) error {
@@ -3536,7 +3726,7 @@ func (m *DriverTaskEvent) Reset()         { *m = DriverTaskEvent{} }
 func (m *DriverTaskEvent) String() string { return proto.CompactTextString(m) }
 func (*DriverTaskEvent) ProtoMessage()    {}
 func (*DriverTaskEvent) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4a8f45747846a74d, []int{56}
+	return fileDescriptor_4a8f45747846a74d, []int{60}
 }
 
 func (m *DriverTaskEvent) XXX_Unmarshal(b []byte) error {
@@ -3643,6 +3833,10 @@ func init() {
 	proto.RegisterType((*CreateNetworkResponse)(nil), "hashicorp.nomad.plugins.drivers.proto.CreateNetworkResponse")
 	proto.RegisterType((*DestroyNetworkRequest)(nil), "hashicorp.nomad.plugins.drivers.proto.DestroyNetworkRequest")
 	proto.RegisterType((*DestroyNetworkResponse)(nil), "hashicorp.nomad.plugins.drivers.proto.DestroyNetworkResponse")
+	proto.RegisterType((*CheckpointTaskRequest)(nil), "hashicorp.nomad.plugins.drivers.proto.CheckpointTaskRequest")
+	proto.RegisterType((*CheckpointTaskResponse)(nil), "hashicorp.nomad.plugins.drivers.proto.CheckpointTaskResponse")
+	proto.RegisterType((*RestoreTaskRequest)(nil), "hashicorp.nomad.plugins.drivers.proto.RestoreTaskRequest")
+	proto.RegisterType((*RestoreTaskResponse)(nil), "hashicorp.nomad.plugins.drivers.proto.RestoreTaskResponse")
 	proto.RegisterType((*DriverCapabilities)(nil), "hashicorp.nomad.plugins.drivers.proto.DriverCapabilities")
 	proto.RegisterType((*NetworkIsolationSpec)(nil), "hashicorp.nomad.plugins.drivers.proto.NetworkIsolationSpec")
 	proto.RegisterMapType((map[string]string)(nil), "hashicorp.nomad.plugins.drivers.proto.NetworkIsolationSpec.LabelsEntry")
@@ -3682,243 +3876,249 @@ func init() {
 }
 
 var fileDescriptor_4a8f45747846a74d = []byte{
-	// 3776 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x5a, 0xcd, 0x6f, 0x1b, 0x49,
-	0x76, 0x57, 0xb3, 0xf9, 0xf9, 0x48, 0x51, 0xad, 0xb2, 0x6c, 0xd3, 0x9c, 0x24, 0xe3, 0xed, 0x60,
-	0x02, 0x61, 0x77, 0x86, 0x9e, 0xd5, 0x22, 0xe3, 0xf1, 0xac, 0x67, 0x3d, 0x1c, 0x8a, 0xb6, 0x34,
-	0x96, 0x28, 0xa5, 0x48, 0xc1, 0xeb, 0x38, 0x3b, 0x9d, 0x16, 0xbb, 0x4c, 0xb5, 0xc5, 0xfe, 0x98,
-	0xae, 0xa6, 0x2c, 0x6d, 0x10, 0x24, 0xd8, 0x00, 0xc1, 0x06, 0x48, 0x90, 0x5c, 0x26, 0x7b, 0xc9,
-	0x69, 0x81, 0x9c, 0xf2, 0x0f, 0x04, 0x1b, 0xec, 0x29, 0x87, 0xfc, 0x13, 0xb9, 0xe4, 0x96, 0x63,
-	0x72, 0xca, 0x35, 0xa8, 0x8f, 0x6e, 0x76, 0x93, 0xf4, 0xb8, 0x49, 0xf9, 0xc4, 0x7e, 0xf5, 0xf1,
-	0xab, 0xc7, 0xf7, 0x5e, 0xbd, 0x7a, 0xf5, 0xea, 0x81, 0xee, 0x8f, 0x27, 0x23, 0xdb, 0xa5, 0xf7,
-	0xac, 0xc0, 0xbe, 0x20, 0x01, 0xbd, 0xe7, 0x07, 0x5e, 0xe8, 0x49, 0xaa, 0xc5, 0x09, 0xf4, 0xc1,
-	0x99, 0x49, 0xcf, 0xec, 0xa1, 0x17, 0xf8, 0x2d, 0xd7, 0x73, 0x4c, 0xab, 0x25, 0xe7, 0xb4, 0xe4,
-	0x1c, 0x31, 0xac, 0xf9, 0x7b, 0x23, 0xcf, 0x1b, 0x8d, 0x89, 0x40, 0x38, 0x9d, 0xbc, 0xbc, 0x67,
-	0x4d, 0x02, 0x33, 0xb4, 0x3d, 0x57, 0xf6, 0xbf, 0x3f, 0xdb, 0x1f, 0xda, 0x0e, 0xa1, 0xa1, 0xe9,
-	0xf8, 0x72, 0xc0, 0x07, 0x11, 0x2f, 0xf4, 0xcc, 0x0c, 0x88, 0x75, 0xef, 0x6c, 0x38, 0xa6, 0x3e,
-	0x19, 0xb2, 0x5f, 0x83, 0x7d, 0xc8, 0x61, 0x1f, 0xce, 0x0c, 0xa3, 0x61, 0x30, 0x19, 0x86, 0x11,
-	0xe7, 0x66, 0x18, 0x06, 0xf6, 0xe9, 0x24, 0x24, 0x62, 0xb4, 0x7e, 0x07, 0x6e, 0x0f, 0x4c, 0x7a,
-	0xde, 0xf1, 0xdc, 0x97, 0xf6, 0xa8, 0x3f, 0x3c, 0x23, 0x8e, 0x89, 0xc9, 0x37, 0x13, 0x42, 0x43,
-	0xfd, 0x4f, 0xa0, 0x31, 0xdf, 0x45, 0x7d, 0xcf, 0xa5, 0x04, 0x7d, 0x01, 0x79, 0xb6, 0x64, 0x43,
-	0xb9, 0xab, 0x6c, 0x57, 0x77, 0x3e, 0x6c, 0xbd, 0x49, 0x04, 0x82, 0x87, 0x96, 0x64, 0xb5, 0xd5,
-	0xf7, 0xc9, 0x10, 0xf3, 0x99, 0xfa, 0x4d, 0xb8, 0xd1, 0x31, 0x7d, 0xf3, 0xd4, 0x1e, 0xdb, 0xa1,
-	0x4d, 0x68, 0xb4, 0xe8, 0x04, 0xb6, 0xd2, 0xcd, 0x72, 0xc1, 0x9f, 0x41, 0x6d, 0x98, 0x68, 0x97,
-	0x0b, 0x3f, 0x68, 0x65, 0x92, 0x7d, 0x6b, 0x97, 0x53, 0x29, 0xe0, 0x14, 0x9c, 0xbe, 0x05, 0xe8,
-	0xb1, 0xed, 0x8e, 0x48, 0xe0, 0x07, 0xb6, 0x1b, 0x46, 0xcc, 0xfc, 0x56, 0x85, 0x1b, 0xa9, 0x66,
-	0xc9, 0xcc, 0x2b, 0x80, 0x58, 0x8e, 0x8c, 0x15, 0x75, 0xbb, 0xba, 0xf3, 0x55, 0x46, 0x56, 0x16,
-	0xe0, 0xb5, 0xda, 0x31, 0x58, 0xd7, 0x0d, 0x83, 0x2b, 0x9c, 0x40, 0x47, 0x5f, 0x43, 0xf1, 0x8c,
-	0x98, 0xe3, 0xf0, 0xac, 0x91, 0xbb, 0xab, 0x6c, 0xd7, 0x77, 0x1e, 0x5f, 0x63, 0x9d, 0x3d, 0x0e,
-	0xd4, 0x0f, 0xcd, 0x90, 0x60, 0x89, 0x8a, 0x3e, 0x02, 0x24, 0xbe, 0x0c, 0x8b, 0xd0, 0x61, 0x60,
-	0xfb, 0xcc, 0x24, 0x1b, 0xea, 0x5d, 0x65, 0xbb, 0x82, 0x37, 0x45, 0xcf, 0xee, 0xb4, 0xa3, 0xe9,
-	0xc3, 0xc6, 0x0c, 0xb7, 0x48, 0x03, 0xf5, 0x9c, 0x5c, 0x71, 0x8d, 0x54, 0x30, 0xfb, 0x44, 0x4f,
-	0xa0, 0x70, 0x61, 0x8e, 0x27, 0x84, 0xb3, 0x5c, 0xdd, 0xf9, 0xe1, 0xdb, 0xcc, 0x43, 0x9a, 0xe8,
-	0x54, 0x0e, 0x58, 0xcc, 0xff, 0x2c, 0xf7, 0xa9, 0xa2, 0x3f, 0x80, 0x6a, 0x82, 0x6f, 0x54, 0x07,
-	0x38, 0xe9, 0xed, 0x76, 0x07, 0xdd, 0xce, 0xa0, 0xbb, 0xab, 0xad, 0xa1, 0x75, 0xa8, 0x9c, 0xf4,
-	0xf6, 0xba, 0xed, 0x83, 0xc1, 0xde, 0x73, 0x4d, 0x41, 0x55, 0x28, 0x45, 0x44, 0x4e, 0xbf, 0x04,
-	0x84, 0xc9, 0xd0, 0xbb, 0x20, 0x01, 0x33, 0x64, 0xa9, 0x55, 0x74, 0x1b, 0x4a, 0xa1, 0x49, 0xcf,
-	0x0d, 0xdb, 0x92, 0x3c, 0x17, 0x19, 0xb9, 0x6f, 0xa1, 0x7d, 0x28, 0x9e, 0x99, 0xae, 0x35, 0x7e,
-	0x3b, 0xdf, 0x69, 0x51, 0x33, 0xf0, 0x3d, 0x3e, 0x11, 0x4b, 0x00, 0x66, 0xdd, 0xa9, 0x95, 0x85,
-	0x02, 0xf4, 0xe7, 0xa0, 0xf5, 0x43, 0x33, 0x08, 0x93, 0xec, 0x74, 0x21, 0xcf, 0xd6, 0x97, 0x16,
-	0xbd, 0xcc, 0x9a, 0x62, 0x67, 0x62, 0x3e, 0x5d, 0xff, 0xdf, 0x1c, 0x6c, 0x26, 0xb0, 0xa5, 0xa5,
-	0x3e, 0x83, 0x62, 0x40, 0xe8, 0x64, 0x1c, 0x72, 0xf8, 0xfa, 0xce, 0xa3, 0x8c, 0xf0, 0x73, 0x48,
-	0x2d, 0xcc, 0x61, 0xb0, 0x84, 0x43, 0xdb, 0xa0, 0x89, 0x19, 0x06, 0x09, 0x02, 0x2f, 0x30, 0x1c,
-	0x3a, 0xe2, 0x52, 0xab, 0xe0, 0xba, 0x68, 0xef, 0xb2, 0xe6, 0x43, 0x3a, 0x4a, 0x48, 0x55, 0xbd,
-	0xa6, 0x54, 0x91, 0x09, 0x9a, 0x4b, 0xc2, 0xd7, 0x5e, 0x70, 0x6e, 0x30, 0xd1, 0x06, 0xb6, 0x45,
-	0x1a, 0x79, 0x0e, 0xfa, 0x49, 0x46, 0xd0, 0x9e, 0x98, 0x7e, 0x24, 0x67, 0xe3, 0x0d, 0x37, 0xdd,
-	0xa0, 0xff, 0x00, 0x8a, 0xe2, 0x9f, 0x32, 0x4b, 0xea, 0x9f, 0x74, 0x3a, 0xdd, 0x7e, 0x5f, 0x5b,
-	0x43, 0x15, 0x28, 0xe0, 0xee, 0x00, 0x33, 0x0b, 0xab, 0x40, 0xe1, 0x71, 0x7b, 0xd0, 0x3e, 0xd0,
-	0x72, 0xfa, 0xf7, 0x61, 0xe3, 0x99, 0x69, 0x87, 0x59, 0x8c, 0x4b, 0xf7, 0x40, 0x9b, 0x8e, 0x95,
-	0xda, 0xd9, 0x4f, 0x69, 0x27, 0xbb, 0x68, 0xba, 0x97, 0x76, 0x38, 0xa3, 0x0f, 0x0d, 0x54, 0x12,
-	0x04, 0x52, 0x05, 0xec, 0x53, 0x7f, 0x0d, 0x1b, 0xfd, 0xd0, 0xf3, 0x33, 0x59, 0xfe, 0x8f, 0xa0,
-	0xc4, 0x4e, 0x1b, 0x6f, 0x12, 0x4a, 0xd3, 0xbf, 0xd3, 0x12, 0xa7, 0x51, 0x2b, 0x3a, 0x8d, 0x5a,
-	0xbb, 0xf2, 0xb4, 0xc2, 0xd1, 0x48, 0x74, 0x0b, 0x8a, 0xd4, 0x1e, 0xb9, 0xe6, 0x58, 0x7a, 0x0b,
-	0x49, 0xe9, 0x88, 0x19, 0x79, 0xb4, 0xb0, 0x34, 0xfc, 0x0e, 0xa0, 0x5d, 0x42, 0xc3, 0xc0, 0xbb,
-	0xca, 0xc4, 0xcf, 0x16, 0x14, 0x5e, 0x7a, 0xc1, 0x50, 0x6c, 0xc4, 0x32, 0x16, 0x04, 0xdb, 0x54,
-	0x29, 0x10, 0x89, 0xfd, 0x11, 0xa0, 0x7d, 0x97, 0x9d, 0x29, 0xd9, 0x14, 0xf1, 0x0f, 0x39, 0xb8,
-	0x91, 0x1a, 0x2f, 0x95, 0xb1, 0xfa, 0x3e, 0x64, 0x8e, 0x69, 0x42, 0xc5, 0x3e, 0x44, 0x47, 0x50,
-	0x14, 0x23, 0xa4, 0x24, 0xef, 0x2f, 0x01, 0x24, 0x8e, 0x29, 0x09, 0x27, 0x61, 0x16, 0x1a, 0xbd,
-	0xfa, 0x6e, 0x8d, 0xfe, 0x35, 0x68, 0xd1, 0xff, 0xa0, 0x6f, 0xd5, 0xcd, 0x57, 0x70, 0x63, 0xe8,
-	0x8d, 0xc7, 0x64, 0xc8, 0xac, 0xc1, 0xb0, 0xdd, 0x90, 0x04, 0x17, 0xe6, 0xf8, 0xed, 0x76, 0x83,
-	0xa6, 0xb3, 0xf6, 0xe5, 0x24, 0xfd, 0x05, 0x6c, 0x26, 0x16, 0x96, 0x8a, 0x78, 0x0c, 0x05, 0xca,
-	0x1a, 0xa4, 0x26, 0x3e, 0x5e, 0x52, 0x13, 0x14, 0x8b, 0xe9, 0xfa, 0x0d, 0x01, 0xde, 0xbd, 0x20,
-	0x6e, 0xfc, 0xb7, 0xf4, 0x5d, 0xd8, 0xec, 0x73, 0x33, 0xcd, 0x64, 0x87, 0x53, 0x13, 0xcf, 0xa5,
-	0x4c, 0x7c, 0x0b, 0x50, 0x12, 0x45, 0x1a, 0xe2, 0x15, 0x6c, 0x74, 0x2f, 0xc9, 0x30, 0x13, 0x72,
-	0x03, 0x4a, 0x43, 0xcf, 0x71, 0x4c, 0xd7, 0x6a, 0xe4, 0xee, 0xaa, 0xdb, 0x15, 0x1c, 0x91, 0xc9,
-	0xbd, 0xa8, 0x66, 0xdd, 0x8b, 0xfa, 0xdf, 0x29, 0xa0, 0x4d, 0xd7, 0x96, 0x82, 0x64, 0xdc, 0x87,
-	0x16, 0x03, 0x62, 0x6b, 0xd7, 0xb0, 0xa4, 0x64, 0x7b, 0xe4, 0x2e, 0x44, 0x3b, 0x09, 0x82, 0x84,
-	0x3b, 0x52, 0xaf, 0xe9, 0x8e, 0xf4, 0x3d, 0xf8, 0x9d, 0x88, 0x9d, 0x7e, 0x18, 0x10, 0xd3, 0xb1,
-	0xdd, 0xd1, 0xfe, 0xd1, 0x91, 0x4f, 0x04, 0xe3, 0x08, 0x41, 0xde, 0x32, 0x43, 0x53, 0x32, 0xc6,
-	0xbf, 0xd9, 0xa6, 0x1f, 0x8e, 0x3d, 0x1a, 0x6f, 0x7a, 0x4e, 0xe8, 0xff, 0xa1, 0x42, 0x63, 0x0e,
-	0x2a, 0x12, 0xef, 0x0b, 0x28, 0x50, 0x12, 0x4e, 0x7c, 0x69, 0x2a, 0xdd, 0xcc, 0x0c, 0x2f, 0xc6,
-	0x6b, 0xf5, 0x19, 0x18, 0x16, 0x98, 0x68, 0x04, 0xe5, 0x30, 0xbc, 0x32, 0xa8, 0xfd, 0xf3, 0x28,
-	0x20, 0x38, 0xb8, 0x2e, 0xfe, 0x80, 0x04, 0x8e, 0xed, 0x9a, 0xe3, 0xbe, 0xfd, 0x73, 0x82, 0x4b,
-	0x61, 0x78, 0xc5, 0x3e, 0xd0, 0x73, 0x66, 0xf0, 0x96, 0xed, 0x4a, 0xb1, 0x77, 0x56, 0x5d, 0x25,
-	0x21, 0x60, 0x2c, 0x10, 0x9b, 0x07, 0x50, 0xe0, 0xff, 0x69, 0x15, 0x43, 0xd4, 0x40, 0x0d, 0xc3,
-	0x2b, 0xce, 0x54, 0x19, 0xb3, 0xcf, 0xe6, 0x43, 0xa8, 0x25, 0xff, 0x01, 0x33, 0xa4, 0x33, 0x62,
-	0x8f, 0xce, 0x84, 0x81, 0x15, 0xb0, 0xa4, 0x98, 0x26, 0x5f, 0xdb, 0x96, 0x0c, 0x59, 0x0b, 0x58,
-	0x10, 0xfa, 0xbf, 0xe6, 0xe0, 0xce, 0x02, 0xc9, 0x48, 0x63, 0x7d, 0x91, 0x32, 0xd6, 0x77, 0x24,
-	0x85, 0xc8, 0xe2, 0x5f, 0xa4, 0x2c, 0xfe, 0x1d, 0x82, 0xb3, 0x6d, 0x73, 0x0b, 0x8a, 0xe4, 0xd2,
-	0x0e, 0x89, 0x25, 0x45, 0x25, 0xa9, 0xc4, 0x76, 0xca, 0x5f, 0x77, 0x3b, 0x1d, 0xc2, 0x56, 0x27,
-	0x20, 0x66, 0x48, 0xa4, 0x2b, 0x8f, 0xec, 0xff, 0x0e, 0x94, 0xcd, 0xf1, 0xd8, 0x1b, 0x4e, 0xd5,
-	0x5a, 0xe2, 0xf4, 0xbe, 0x85, 0x9a, 0x50, 0x3e, 0xf3, 0x68, 0xe8, 0x9a, 0x0e, 0x91, 0xce, 0x2b,
-	0xa6, 0xf5, 0x6f, 0x15, 0xb8, 0x39, 0x83, 0x27, 0xb5, 0x70, 0x0a, 0x75, 0x9b, 0x7a, 0x63, 0xfe,
-	0x07, 0x8d, 0xc4, 0x0d, 0xef, 0xc7, 0xcb, 0x1d, 0x35, 0xfb, 0x11, 0x06, 0xbf, 0xf0, 0xad, 0xdb,
-	0x49, 0x92, 0x5b, 0x1c, 0x5f, 0xdc, 0x92, 0x3b, 0x3d, 0x22, 0xf5, 0x7f, 0x54, 0xe0, 0xa6, 0x3c,
-	0xe1, 0xb3, 0xff, 0xd1, 0x79, 0x96, 0x73, 0xef, 0x9a, 0x65, 0xbd, 0x01, 0xb7, 0x66, 0xf9, 0x92,
-	0x3e, 0xff, 0xff, 0xf2, 0x80, 0xe6, 0x6f, 0x97, 0xe8, 0x7b, 0x50, 0xa3, 0xc4, 0xb5, 0x0c, 0x71,
-	0x5e, 0x88, 0xa3, 0xac, 0x8c, 0xab, 0xac, 0x4d, 0x1c, 0x1c, 0x94, 0xb9, 0x40, 0x72, 0x29, 0xb9,
-	0x2d, 0x63, 0xfe, 0x8d, 0xce, 0xa0, 0xf6, 0x92, 0x1a, 0xf1, 0xda, 0xdc, 0xa0, 0xea, 0x99, 0xdd,
-	0xda, 0x3c, 0x1f, 0xad, 0xc7, 0xfd, 0xf8, 0x7f, 0xe1, 0xea, 0x4b, 0x1a, 0x13, 0xe8, 0x97, 0x0a,
-	0xdc, 0x8e, 0xc2, 0x8a, 0xa9, 0xf8, 0x1c, 0xcf, 0x22, 0xb4, 0x91, 0xbf, 0xab, 0x6e, 0xd7, 0x77,
-	0x8e, 0xaf, 0x21, 0xbf, 0xb9, 0xc6, 0x43, 0xcf, 0x22, 0xf8, 0xa6, 0xbb, 0xa0, 0x95, 0xa2, 0x16,
-	0xdc, 0x70, 0x26, 0x34, 0x34, 0x84, 0x15, 0x18, 0x72, 0x50, 0xa3, 0xc0, 0xe5, 0xb2, 0xc9, 0xba,
-	0x52, 0xb6, 0x8a, 0xce, 0x61, 0xdd, 0xf1, 0x26, 0x6e, 0x68, 0x0c, 0xf9, 0xfd, 0x87, 0x36, 0x8a,
-	0x4b, 0x5d, 0x8c, 0x17, 0x48, 0xe9, 0x90, 0xc1, 0x89, 0xdb, 0x14, 0xc5, 0x35, 0x27, 0x41, 0x31,
-	0x45, 0x06, 0xc4, 0xf1, 0x42, 0x62, 0x30, 0x7f, 0x49, 0x1b, 0x25, 0xa1, 0x48, 0xd1, 0xc6, 0x5c,
-	0x03, 0xd5, 0x5b, 0x50, 0x4d, 0x88, 0x19, 0x95, 0x21, 0xdf, 0x3b, 0xea, 0x75, 0xb5, 0x35, 0x04,
-	0x50, 0xec, 0xec, 0xe1, 0xa3, 0xa3, 0x81, 0xb8, 0x35, 0xec, 0x1f, 0xb6, 0x9f, 0x74, 0xb5, 0x9c,
-	0xde, 0x85, 0x5a, 0x72, 0x41, 0x84, 0xa0, 0x7e, 0xd2, 0x7b, 0xda, 0x3b, 0x7a, 0xd6, 0x33, 0x0e,
-	0x8f, 0x4e, 0x7a, 0x03, 0x76, 0xdf, 0xa8, 0x03, 0xb4, 0x7b, 0xcf, 0xa7, 0xf4, 0x3a, 0x54, 0x7a,
-	0x47, 0x11, 0xa9, 0x34, 0x73, 0x9a, 0xa2, 0xff, 0xbb, 0x0a, 0x5b, 0x8b, 0x64, 0x8f, 0x2c, 0xc8,
-	0x33, 0x3d, 0xca, 0x1b, 0xdf, 0xbb, 0x57, 0x23, 0x47, 0x67, 0xe6, 0xeb, 0x9b, 0xd2, 0xc5, 0x57,
-	0x30, 0xff, 0x46, 0x06, 0x14, 0xc7, 0xe6, 0x29, 0x19, 0xd3, 0x86, 0xca, 0x73, 0x22, 0x4f, 0xae,
-	0xb3, 0xf6, 0x01, 0x47, 0x12, 0x09, 0x11, 0x09, 0x8b, 0x06, 0x50, 0x65, 0x4e, 0x8c, 0x0a, 0xd1,
-	0x49, 0xbf, 0xba, 0x93, 0x71, 0x95, 0xbd, 0xe9, 0x4c, 0x9c, 0x84, 0x69, 0x3e, 0x80, 0x6a, 0x62,
-	0xb1, 0x05, 0xf9, 0x8c, 0xad, 0x64, 0x3e, 0xa3, 0x92, 0x4c, 0x4e, 0x3c, 0x9a, 0xd7, 0x01, 0x93,
-	0x11, 0x33, 0x82, 0xbd, 0xa3, 0xfe, 0x40, 0xdc, 0x1c, 0x9f, 0xe0, 0xa3, 0x93, 0x63, 0x4d, 0x61,
-	0x8d, 0x83, 0x76, 0xff, 0xa9, 0x96, 0x8b, 0x6d, 0x44, 0xd5, 0x3b, 0x50, 0x4d, 0xf0, 0x95, 0xf2,
-	0xda, 0x4a, 0xda, 0x6b, 0x33, 0xbf, 0x69, 0x5a, 0x56, 0x40, 0x28, 0x95, 0x7c, 0x44, 0xa4, 0xfe,
-	0x02, 0x2a, 0xbb, 0xbd, 0xbe, 0x84, 0x68, 0x40, 0x89, 0x92, 0x80, 0xfd, 0x6f, 0x9e, 0x99, 0xaa,
-	0xe0, 0x88, 0x64, 0xe0, 0x94, 0x98, 0xc1, 0xf0, 0x8c, 0x50, 0x79, 0xd6, 0xc7, 0x34, 0x9b, 0xe5,
-	0xf1, 0x0c, 0x8f, 0xd0, 0x5d, 0x05, 0x47, 0xa4, 0xfe, 0x3f, 0x25, 0x80, 0x69, 0xb6, 0x01, 0xd5,
-	0x21, 0x17, 0xfb, 0xe0, 0x9c, 0x6d, 0x31, 0x3b, 0x48, 0x9c, 0x31, 0xfc, 0x1b, 0xed, 0xc0, 0x4d,
-	0x87, 0x8e, 0x7c, 0x73, 0x78, 0x6e, 0xc8, 0x24, 0x81, 0xd8, 0xaa, 0xdc, 0x9f, 0xd5, 0xf0, 0x0d,
-	0xd9, 0x29, 0x77, 0xa2, 0xc0, 0x3d, 0x00, 0x95, 0xb8, 0x17, 0xdc, 0xf7, 0x54, 0x77, 0x3e, 0x5b,
-	0x3a, 0x0b, 0xd2, 0xea, 0xba, 0x17, 0xc2, 0x56, 0x18, 0x0c, 0x32, 0x00, 0x2c, 0x72, 0x61, 0x0f,
-	0x89, 0xc1, 0x40, 0x0b, 0x1c, 0xf4, 0x8b, 0xe5, 0x41, 0x77, 0x39, 0x46, 0x0c, 0x5d, 0xb1, 0x22,
-	0x1a, 0xf5, 0xa0, 0x12, 0x10, 0xea, 0x4d, 0x82, 0x21, 0x11, 0x0e, 0x28, 0xfb, 0x45, 0x05, 0x47,
-	0xf3, 0xf0, 0x14, 0x02, 0xed, 0x42, 0x91, 0xfb, 0x1d, 0xe6, 0x61, 0xd4, 0xef, 0x4c, 0xa9, 0xa6,
-	0xc1, 0xb8, 0x27, 0xc1, 0x72, 0x2e, 0x7a, 0x02, 0x25, 0xc1, 0x22, 0x6d, 0x94, 0x39, 0xcc, 0x47,
-	0x59, 0x9d, 0x22, 0x9f, 0x85, 0xa3, 0xd9, 0x4c, 0xab, 0x13, 0x4a, 0x82, 0x46, 0x45, 0x68, 0x95,
-	0x7d, 0xa3, 0xf7, 0xa0, 0x22, 0xce, 0x60, 0xcb, 0x0e, 0x1a, 0x20, 0x8c, 0x93, 0x37, 0xec, 0xda,
-	0x01, 0x7a, 0x1f, 0xaa, 0x22, 0xd6, 0x32, 0xb8, 0x57, 0xa8, 0xf2, 0x6e, 0x10, 0x4d, 0xc7, 0xcc,
-	0x37, 0x88, 0x01, 0x24, 0x08, 0xc4, 0x80, 0x5a, 0x3c, 0x80, 0x04, 0x01, 0x1f, 0xf0, 0x07, 0xb0,
-	0xc1, 0x23, 0xd4, 0x51, 0xe0, 0x4d, 0x7c, 0x83, 0xdb, 0xd4, 0x3a, 0x1f, 0xb4, 0xce, 0x9a, 0x9f,
-	0xb0, 0xd6, 0x1e, 0x33, 0xae, 0x3b, 0x50, 0x7e, 0xe5, 0x9d, 0x8a, 0x01, 0x75, 0xb1, 0x0f, 0x5e,
-	0x79, 0xa7, 0x51, 0x57, 0x1c, 0x25, 0x6c, 0xa4, 0xa3, 0x84, 0x6f, 0xe0, 0xd6, 0xfc, 0x71, 0xc7,
-	0xa3, 0x05, 0xed, 0xfa, 0xd1, 0xc2, 0x96, 0xbb, 0xc8, 0x0f, 0x7f, 0x09, 0xaa, 0xe5, 0xd2, 0xc6,
-	0xe6, 0x52, 0xc6, 0x11, 0xef, 0x63, 0xcc, 0x26, 0x37, 0x3f, 0x81, 0x72, 0x64, 0x7d, 0xcb, 0xf8,
-	0xa5, 0xe6, 0x43, 0xa8, 0xa7, 0x6d, 0x77, 0x29, 0xaf, 0xf6, 0xcf, 0x39, 0xa8, 0xc4, 0x56, 0x8a,
-	0x5c, 0xb8, 0xc1, 0xa5, 0xc8, 0x42, 0x34, 0x63, 0x6a, 0xf4, 0x22, 0x30, 0xfc, 0x3c, 0xe3, 0xff,
-	0x6a, 0x47, 0x08, 0xf2, 0x86, 0x2a, 0x77, 0x00, 0x8a, 0x91, 0xa7, 0xeb, 0x7d, 0x0d, 0x1b, 0x63,
-	0xdb, 0x9d, 0x5c, 0x26, 0xd6, 0x12, 0x11, 0xdd, 0x1f, 0x66, 0x5c, 0xeb, 0x80, 0xcd, 0x9e, 0xae,
-	0x51, 0x1f, 0xa7, 0x68, 0xb4, 0x07, 0x05, 0xdf, 0x0b, 0xc2, 0xe8, 0x90, 0xca, 0x7a, 0x7c, 0x1c,
-	0x7b, 0x41, 0x78, 0x68, 0xfa, 0x3e, 0xbb, 0xb4, 0x08, 0x00, 0xfd, 0xdb, 0x1c, 0xdc, 0x5a, 0xfc,
-	0xc7, 0x50, 0x0f, 0xd4, 0xa1, 0x3f, 0x91, 0x42, 0x7a, 0xb8, 0xac, 0x90, 0x3a, 0xfe, 0x64, 0xca,
-	0x3f, 0x03, 0x42, 0xcf, 0xa0, 0xe8, 0x10, 0xc7, 0x0b, 0xae, 0xa4, 0x2c, 0x1e, 0x2d, 0x0b, 0x79,
-	0xc8, 0x67, 0x4f, 0x51, 0x25, 0x1c, 0xc2, 0x50, 0x96, 0xd6, 0x4b, 0xa5, 0x9f, 0x5c, 0x32, 0xad,
-	0x14, 0x41, 0xe2, 0x18, 0x47, 0xff, 0x04, 0x6e, 0x2e, 0xfc, 0x2b, 0xe8, 0x77, 0x01, 0x86, 0xfe,
-	0xc4, 0xe0, 0x69, 0x7f, 0x61, 0x41, 0x2a, 0xae, 0x0c, 0xfd, 0x49, 0x9f, 0x37, 0xe8, 0x2f, 0xa0,
-	0xf1, 0x26, 0x7e, 0x99, 0xf7, 0x11, 0x1c, 0x1b, 0xce, 0x29, 0x97, 0x81, 0x8a, 0xcb, 0xa2, 0xe1,
-	0xf0, 0x14, 0xe9, 0xb0, 0x1e, 0x75, 0x9a, 0x97, 0x6c, 0x80, 0xca, 0x07, 0x54, 0xe5, 0x00, 0xf3,
-	0xf2, 0xf0, 0x54, 0xff, 0x55, 0x0e, 0x36, 0x66, 0x58, 0x66, 0x57, 0x37, 0xe1, 0xf1, 0xa2, 0x4b,
-	0xb1, 0xa0, 0x98, 0xfb, 0x1b, 0xda, 0x56, 0x94, 0x4e, 0xe5, 0xdf, 0xfc, 0xe0, 0xf3, 0x65, 0xaa,
-	0x33, 0x67, 0xfb, 0x6c, 0xfb, 0x38, 0xa7, 0x76, 0x48, 0x79, 0x14, 0x52, 0xc0, 0x82, 0x40, 0xcf,
-	0xa1, 0x1e, 0x10, 0x7e, 0xe0, 0x5a, 0x86, 0xb0, 0xb2, 0xc2, 0x52, 0x56, 0x26, 0x39, 0x64, 0xc6,
-	0x86, 0xd7, 0x23, 0x24, 0x46, 0x51, 0xf4, 0x0c, 0xd6, 0xad, 0x2b, 0xd7, 0x74, 0xec, 0xa1, 0x44,
-	0x2e, 0xae, 0x8c, 0x5c, 0x93, 0x40, 0x1c, 0x58, 0x7f, 0x00, 0xd5, 0x44, 0x27, 0xfb, 0x63, 0x3c,
-	0xdc, 0x92, 0x32, 0x11, 0x44, 0xda, 0x5b, 0x14, 0xa4, 0xb7, 0xd0, 0x4f, 0xa1, 0x9a, 0xd8, 0x17,
-	0xcb, 0x4c, 0x65, 0xf2, 0x0c, 0x3d, 0x2e, 0xcf, 0x02, 0xce, 0x85, 0x1e, 0xba, 0x0d, 0x25, 0x16,
-	0xea, 0x18, 0xb6, 0xcf, 0x25, 0x5a, 0xc1, 0x45, 0x46, 0xee, 0xfb, 0xfa, 0x6f, 0x72, 0x50, 0x4f,
-	0x6f, 0xe9, 0xc8, 0x8e, 0x7c, 0x12, 0xd8, 0x9e, 0x95, 0xb0, 0xa3, 0x63, 0xde, 0xc0, 0x6c, 0x85,
-	0x75, 0x7f, 0x33, 0xf1, 0x42, 0x33, 0xb2, 0x95, 0xa1, 0x3f, 0xf9, 0x23, 0x46, 0xcf, 0xd8, 0xa0,
-	0x3a, 0x63, 0x83, 0xe8, 0x43, 0x40, 0xd2, 0x94, 0xc6, 0xb6, 0x63, 0x87, 0xc6, 0xe9, 0x55, 0x48,
-	0x84, 0x8e, 0x55, 0xac, 0x89, 0x9e, 0x03, 0xd6, 0xf1, 0x25, 0x6b, 0x67, 0x86, 0xe7, 0x79, 0x8e,
-	0x41, 0x87, 0x5e, 0x40, 0x0c, 0xd3, 0x7a, 0xc5, 0x6f, 0x2d, 0x2a, 0xae, 0x7a, 0x9e, 0xd3, 0x67,
-	0x6d, 0x6d, 0xeb, 0x15, 0x3b, 0xf9, 0x86, 0xfe, 0x84, 0x92, 0xd0, 0x60, 0x3f, 0x3c, 0x58, 0xa8,
-	0x60, 0x10, 0x4d, 0x1d, 0x7f, 0x42, 0xd1, 0xef, 0xc3, 0x7a, 0x34, 0x80, 0x1f, 0x7e, 0xf2, 0xd4,
-	0xad, 0xc9, 0x21, 0xbc, 0x0d, 0xe9, 0x50, 0x3b, 0x26, 0xc1, 0x90, 0xb8, 0xe1, 0xc0, 0x1e, 0x9e,
-	0xb3, 0xf3, 0x5d, 0xd9, 0x56, 0x70, 0xaa, 0xed, 0xab, 0x7c, 0xb9, 0xa4, 0x95, 0x71, 0xb4, 0x9a,
-	0x43, 0x1c, 0xaa, 0xff, 0x0c, 0x0a, 0x3c, 0x44, 0x60, 0x32, 0xe1, 0xc7, 0x2b, 0x3f, 0x7d, 0x65,
-	0x68, 0xc9, 0x1a, 0xf8, 0xd9, 0xfb, 0x1e, 0x54, 0xb8, 0xec, 0x13, 0x11, 0x3d, 0x8f, 0x3b, 0x79,
-	0x67, 0x13, 0xca, 0x01, 0x31, 0x2d, 0xcf, 0x1d, 0x47, 0xc9, 0xa0, 0x98, 0xd6, 0xbf, 0x81, 0xa2,
-	0x38, 0x67, 0xae, 0x81, 0xff, 0x11, 0x20, 0xf1, 0xbf, 0x99, 0x3e, 0x1d, 0x9b, 0x52, 0x19, 0x85,
-	0xf2, 0x17, 0x48, 0xd1, 0x73, 0x3c, 0xed, 0xd0, 0xff, 0x53, 0x11, 0xf1, 0xa8, 0x78, 0x1b, 0x62,
-	0x81, 0x2b, 0x33, 0x72, 0x76, 0x5b, 0x16, 0x49, 0xa8, 0x88, 0x44, 0xfb, 0x50, 0x94, 0x61, 0x67,
-	0x6e, 0xd5, 0xa7, 0x35, 0x09, 0x10, 0xa5, 0xa4, 0x89, 0xbc, 0x90, 0x2f, 0x9b, 0x92, 0x26, 0x22,
-	0x25, 0x4d, 0xd8, 0x6d, 0x52, 0x06, 0xc4, 0x02, 0x2e, 0xcf, 0xe3, 0xe1, 0xaa, 0x15, 0xe7, 0xfd,
-	0x89, 0xfe, 0xdf, 0x4a, 0xec, 0xa6, 0xa2, 0xfc, 0x3c, 0xfa, 0x1a, 0xca, 0x6c, 0xc7, 0x1b, 0x8e,
-	0xe9, 0xcb, 0xd7, 0xe6, 0xce, 0x6a, 0xa9, 0xff, 0xe8, 0x10, 0x13, 0xe1, 0x6c, 0xc9, 0x17, 0x14,
-	0x73, 0x77, 0xec, 0x2a, 0x11, 0xb9, 0x3b, 0xf6, 0x8d, 0x3e, 0x80, 0xba, 0x39, 0x09, 0x3d, 0xc3,
-	0xb4, 0x2e, 0x48, 0x10, 0xda, 0x94, 0x48, 0xdd, 0xaf, 0xb3, 0xd6, 0x76, 0xd4, 0xd8, 0xfc, 0x0c,
-	0x6a, 0x49, 0xcc, 0xb7, 0x85, 0x19, 0x85, 0x64, 0x98, 0xf1, 0xa7, 0x00, 0xd3, 0x5c, 0x17, 0xb3,
-	0x11, 0x72, 0x69, 0xb3, 0x5b, 0xbd, 0xbc, 0xbb, 0x16, 0x70, 0x99, 0x35, 0x74, 0xd8, 0x7d, 0x2a,
-	0x9d, 0x88, 0x2f, 0x44, 0x89, 0x78, 0xb6, 0x99, 0xd9, 0xfe, 0x3b, 0xb7, 0xc7, 0xe3, 0x38, 0xff,
-	0x56, 0xf1, 0x3c, 0xe7, 0x29, 0x6f, 0xd0, 0x7f, 0x9b, 0x13, 0xb6, 0x22, 0x9e, 0x54, 0x32, 0xdd,
-	0x5d, 0xde, 0x95, 0xaa, 0x1f, 0x00, 0xd0, 0xd0, 0x0c, 0x58, 0xcc, 0x64, 0x46, 0x19, 0xc0, 0xe6,
-	0x5c, 0x26, 0x7f, 0x10, 0xd5, 0x78, 0xe0, 0x8a, 0x1c, 0xdd, 0x0e, 0xd1, 0xe7, 0x50, 0x1b, 0x7a,
-	0x8e, 0x3f, 0x26, 0x72, 0x72, 0xe1, 0xad, 0x93, 0xab, 0xf1, 0xf8, 0x76, 0x98, 0xc8, 0x3b, 0x16,
-	0xaf, 0x9b, 0x77, 0xfc, 0x8d, 0x22, 0x5e, 0x86, 0x92, 0x0f, 0x53, 0x68, 0xb4, 0xa0, 0xfa, 0xe1,
-	0xc9, 0x8a, 0xaf, 0x5c, 0xdf, 0x55, 0xfa, 0xd0, 0xfc, 0x3c, 0x4b, 0xad, 0xc1, 0x9b, 0xa3, 0xd8,
-	0x7f, 0x53, 0xa1, 0x12, 0x3f, 0x0a, 0xcd, 0xe9, 0xfe, 0x53, 0xa8, 0xc4, 0x05, 0x36, 0xd2, 0x41,
-	0x7c, 0xa7, 0x7a, 0xe2, 0xc1, 0xe8, 0x25, 0x20, 0x73, 0x34, 0x8a, 0xa3, 0x53, 0x63, 0x42, 0xcd,
-	0x51, 0xf4, 0x24, 0xf7, 0xe9, 0x12, 0x72, 0x88, 0x8e, 0xb3, 0x13, 0x36, 0x1f, 0x6b, 0xe6, 0x68,
-	0x94, 0x6a, 0x41, 0x7f, 0x06, 0x37, 0xd3, 0x6b, 0x18, 0xa7, 0x57, 0x86, 0x6f, 0x5b, 0xf2, 0x8e,
-	0xbc, 0xb7, 0xec, 0xbb, 0x58, 0x2b, 0x05, 0xff, 0xe5, 0xd5, 0xb1, 0x6d, 0x09, 0x99, 0xa3, 0x60,
-	0xae, 0xa3, 0xf9, 0x17, 0x70, 0xfb, 0x0d, 0xc3, 0x17, 0xe8, 0xa0, 0x97, 0xae, 0xf7, 0x58, 0x5d,
-	0x08, 0x09, 0xed, 0xfd, 0x5a, 0x11, 0xcf, 0x77, 0x69, 0x99, 0xb4, 0x93, 0x61, 0xf5, 0xbd, 0x8c,
-	0xeb, 0x74, 0x8e, 0x4f, 0x04, 0x3c, 0x8f, 0xa4, 0xbf, 0x9a, 0x89, 0xa4, 0xb3, 0xc6, 0x4f, 0x22,
-	0x20, 0x15, 0x40, 0x12, 0x41, 0xff, 0x17, 0x15, 0xca, 0x11, 0x3a, 0xbf, 0xe1, 0x5e, 0xd1, 0x90,
-	0x38, 0x46, 0x9c, 0x7e, 0x53, 0x30, 0x88, 0x26, 0x9e, 0x14, 0x7a, 0x0f, 0x2a, 0xec, 0x22, 0x2d,
-	0xba, 0x73, 0xbc, 0xbb, 0xcc, 0x1a, 0x78, 0xe7, 0xfb, 0x50, 0x0d, 0xbd, 0xd0, 0x1c, 0x1b, 0x21,
-	0x3f, 0xde, 0x55, 0x31, 0x9b, 0x37, 0xf1, 0xc3, 0x1d, 0xfd, 0x00, 0x36, 0xc3, 0xb3, 0xc0, 0x0b,
-	0xc3, 0x31, 0x0b, 0x2d, 0x79, 0xa0, 0x23, 0xe2, 0x92, 0x3c, 0xd6, 0xe2, 0x0e, 0x11, 0x00, 0x51,
-	0xe6, 0xbd, 0xa7, 0x83, 0x99, 0xe9, 0x72, 0x27, 0x92, 0xc7, 0xeb, 0x71, 0x2b, 0x33, 0x6d, 0x76,
-	0x78, 0xfa, 0x22, 0x80, 0xe0, 0xbe, 0x42, 0xc1, 0x11, 0x89, 0x0c, 0xd8, 0x70, 0x88, 0x49, 0x27,
-	0x01, 0xb1, 0x8c, 0x97, 0x36, 0x19, 0x5b, 0x22, 0x31, 0x51, 0xcf, 0x7c, 0x3b, 0x88, 0xc4, 0xd2,
-	0x7a, 0xcc, 0x67, 0xe3, 0x7a, 0x04, 0x27, 0x68, 0x16, 0x39, 0x88, 0x2f, 0xb4, 0x01, 0xd5, 0xfe,
-	0xf3, 0xfe, 0xa0, 0x7b, 0x68, 0x1c, 0x1e, 0xed, 0x76, 0x65, 0x49, 0x4f, 0xbf, 0x8b, 0x05, 0xa9,
-	0xb0, 0xfe, 0xc1, 0xd1, 0xa0, 0x7d, 0x60, 0x0c, 0xf6, 0x3b, 0x4f, 0xfb, 0x5a, 0x0e, 0xdd, 0x84,
-	0xcd, 0xc1, 0x1e, 0x3e, 0x1a, 0x0c, 0x0e, 0xba, 0xbb, 0xc6, 0x71, 0x17, 0xef, 0x1f, 0xed, 0xf6,
-	0x35, 0x15, 0x21, 0xa8, 0x4f, 0x9b, 0x07, 0xfb, 0x87, 0x5d, 0x2d, 0x8f, 0xaa, 0x50, 0x3a, 0xee,
-	0xe2, 0x4e, 0xb7, 0x37, 0xd0, 0x0a, 0xfa, 0xaf, 0x54, 0xa8, 0x26, 0xb4, 0xc8, 0x0c, 0x39, 0xa0,
-	0xe2, 0x1a, 0x92, 0xc7, 0xec, 0x93, 0x3f, 0x41, 0x9a, 0xc3, 0x33, 0xa1, 0x9d, 0x3c, 0x16, 0x04,
-	0xbf, 0x7a, 0x98, 0x97, 0x89, 0x7d, 0x9e, 0xc7, 0x65, 0xc7, 0xbc, 0x14, 0x20, 0xdf, 0x83, 0xda,
-	0x39, 0x09, 0x5c, 0x32, 0x96, 0xfd, 0x42, 0x23, 0x55, 0xd1, 0x26, 0x86, 0x6c, 0x83, 0x26, 0x87,
-	0x4c, 0x61, 0x84, 0x3a, 0xea, 0xa2, 0xfd, 0x30, 0x02, 0xdb, 0x82, 0x82, 0xe8, 0x2e, 0x89, 0xf5,
-	0x39, 0xc1, 0x8e, 0x29, 0xfa, 0xda, 0xf4, 0x79, 0xc8, 0x97, 0xc7, 0xfc, 0x1b, 0x9d, 0xce, 0xeb,
-	0xa7, 0xc8, 0xf5, 0xf3, 0x60, 0x79, 0x73, 0x7e, 0x93, 0x8a, 0xce, 0x62, 0x15, 0x95, 0x40, 0xc5,
-	0x51, 0x1d, 0x4c, 0xa7, 0xdd, 0xd9, 0x63, 0x6a, 0x59, 0x87, 0xca, 0x61, 0xfb, 0xa7, 0xc6, 0x49,
-	0x9f, 0x67, 0xb5, 0x91, 0x06, 0xb5, 0xa7, 0x5d, 0xdc, 0xeb, 0x1e, 0xc8, 0x16, 0x15, 0x6d, 0x81,
-	0x26, 0x5b, 0xa6, 0xe3, 0xf2, 0x0c, 0x41, 0x7c, 0x16, 0x50, 0x19, 0xf2, 0xfd, 0x67, 0xed, 0x63,
-	0xad, 0xa8, 0xff, 0x57, 0x0e, 0x36, 0xc4, 0xb1, 0x10, 0xbf, 0xd8, 0xbf, 0xf9, 0xc5, 0x32, 0x99,
-	0xe5, 0xc9, 0xa5, 0xb3, 0x3c, 0x51, 0x10, 0xca, 0x4f, 0x75, 0x75, 0x1a, 0x84, 0xf2, 0xec, 0x50,
-	0xca, 0xe3, 0xe7, 0x97, 0xf1, 0xf8, 0x0d, 0x28, 0x39, 0x84, 0xc6, 0x7a, 0xab, 0xe0, 0x88, 0x44,
-	0x36, 0x54, 0x4d, 0xd7, 0xf5, 0x42, 0x53, 0xa4, 0x4e, 0x8b, 0x4b, 0x1d, 0x86, 0x33, 0xff, 0xb8,
-	0xd5, 0x9e, 0x22, 0x09, 0xc7, 0x9c, 0xc4, 0x6e, 0xfe, 0x04, 0xb4, 0xd9, 0x01, 0xcb, 0x1c, 0x87,
-	0xdf, 0xff, 0xe1, 0xf4, 0x34, 0x24, 0x6c, 0x5f, 0xc8, 0x37, 0x07, 0x6d, 0x8d, 0x11, 0xf8, 0xa4,
-	0xd7, 0xdb, 0xef, 0x3d, 0xd1, 0x14, 0x04, 0x50, 0xec, 0xfe, 0x74, 0x7f, 0xd0, 0xdd, 0xd5, 0x72,
-	0x3b, 0xbf, 0xde, 0x84, 0xa2, 0x60, 0x12, 0x7d, 0x2b, 0x23, 0x81, 0x64, 0x35, 0x28, 0xfa, 0xc9,
-	0xd2, 0x11, 0x75, 0xaa, 0xc2, 0xb4, 0xf9, 0x68, 0xe5, 0xf9, 0xf2, 0xf5, 0x6d, 0x0d, 0xfd, 0x8d,
-	0x02, 0xb5, 0xd4, 0xcb, 0x5b, 0xd6, 0xd4, 0xf1, 0x82, 0xe2, 0xd3, 0xe6, 0x8f, 0x57, 0x9a, 0x1b,
-	0xf3, 0xf2, 0x4b, 0x05, 0xaa, 0x89, 0xb2, 0x4b, 0xf4, 0x60, 0x95, 0x52, 0x4d, 0xc1, 0xc9, 0x67,
-	0xab, 0x57, 0x79, 0xea, 0x6b, 0x1f, 0x2b, 0xe8, 0xaf, 0x15, 0xa8, 0x26, 0x0a, 0x10, 0x33, 0xb3,
-	0x32, 0x5f, 0x2e, 0x99, 0x99, 0x95, 0x45, 0xf5, 0x8e, 0x6b, 0xe8, 0x2f, 0x15, 0xa8, 0xc4, 0xc5,
-	0x84, 0xe8, 0xfe, 0xf2, 0xe5, 0x87, 0x82, 0x89, 0x4f, 0x57, 0xad, 0x5b, 0xd4, 0xd7, 0xd0, 0x9f,
-	0x43, 0x39, 0xaa, 0xbc, 0x43, 0x59, 0x4f, 0xaf, 0x99, 0xb2, 0xbe, 0xe6, 0xfd, 0xa5, 0xe7, 0x25,
-	0x97, 0x8f, 0xca, 0xe1, 0x32, 0x2f, 0x3f, 0x53, 0xb8, 0xd7, 0xbc, 0xbf, 0xf4, 0xbc, 0x78, 0x79,
-	0x66, 0x09, 0x89, 0xaa, 0xb9, 0xcc, 0x96, 0x30, 0x5f, 0xae, 0x97, 0xd9, 0x12, 0x16, 0x15, 0xe9,
-	0x09, 0x46, 0x12, 0x75, 0x77, 0x99, 0x19, 0x99, 0xaf, 0xed, 0xcb, 0xcc, 0xc8, 0x82, 0x32, 0x3f,
-	0x7d, 0x0d, 0xfd, 0x42, 0x49, 0xde, 0x0b, 0xee, 0x2f, 0x5d, 0x5e, 0xb6, 0xa4, 0x49, 0xce, 0x15,
-	0xb8, 0xf1, 0x0d, 0xfa, 0x0b, 0x99, 0xc5, 0x10, 0xd5, 0x69, 0x68, 0x19, 0xb0, 0x54, 0x41, 0x5b,
-	0xf3, 0x93, 0xd5, 0x0e, 0x1b, 0xce, 0xc4, 0x5f, 0x29, 0x00, 0xd3, 0x3a, 0xb6, 0xcc, 0x4c, 0xcc,
-	0x15, 0xd0, 0x35, 0x1f, 0xac, 0x30, 0x33, 0xb9, 0x41, 0xa2, 0x3a, 0x9b, 0xcc, 0x1b, 0x64, 0xa6,
-	0xce, 0x2e, 0xf3, 0x06, 0x99, 0xad, 0x91, 0xd3, 0xd7, 0xd0, 0x3f, 0x29, 0xb0, 0x39, 0x57, 0xe7,
-	0x83, 0x1e, 0x5d, 0xb3, 0xd4, 0xab, 0xf9, 0xc5, 0xea, 0x00, 0x11, 0x6b, 0xdb, 0xca, 0xc7, 0x0a,
-	0xfa, 0x5b, 0x05, 0xd6, 0xd3, 0xf5, 0x0f, 0x99, 0x4f, 0xa9, 0x05, 0x15, 0x43, 0xcd, 0x87, 0xab,
-	0x4d, 0x8e, 0xa5, 0xf5, 0xf7, 0x0a, 0xd4, 0xd3, 0xa5, 0x30, 0xe8, 0xe1, 0x72, 0x6e, 0x61, 0x86,
-	0xa1, 0xcf, 0x57, 0x9c, 0x1d, 0x71, 0xf4, 0x65, 0xe9, 0x8f, 0x0b, 0x22, 0x7a, 0x2b, 0xf2, 0x9f,
-	0x1f, 0xfd, 0x7f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x76, 0x8b, 0xb6, 0x52, 0xb4, 0x33, 0x00, 0x00,
+	// 3858 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x5a, 0x4f, 0x6f, 0x1b, 0x49,
+	0x76, 0x77, 0xf3, 0x9f, 0xc8, 0x47, 0x89, 0x6a, 0x95, 0xa4, 0x19, 0x9a, 0x93, 0xec, 0x78, 0x3b,
+	0x98, 0x40, 0xd8, 0x9d, 0xa1, 0x67, 0xb5, 0xc8, 0x78, 0xec, 0xb5, 0xd7, 0x43, 0x53, 0xb4, 0xa5,
+	0xb1, 0x44, 0x29, 0x45, 0x0a, 0x5e, 0xc7, 0xd9, 0xe9, 0xb4, 0xba, 0xcb, 0x64, 0x5b, 0xec, 0x3f,
+	0xd3, 0xd5, 0x94, 0xa5, 0x0d, 0x82, 0x04, 0x1b, 0x20, 0xd8, 0x00, 0x09, 0x92, 0xcb, 0x64, 0x2e,
+	0x39, 0x05, 0xc8, 0x29, 0x5f, 0x20, 0xd8, 0x60, 0x4e, 0x7b, 0xc8, 0x97, 0xc8, 0x25, 0xb7, 0x1c,
+	0x93, 0x6f, 0x10, 0xd4, 0x9f, 0x6e, 0x76, 0x93, 0xf4, 0xba, 0x49, 0x79, 0x4f, 0xec, 0xf7, 0xaa,
+	0xea, 0x57, 0x8f, 0xef, 0xbd, 0xaa, 0xf7, 0xaa, 0xea, 0x81, 0xe6, 0x8f, 0xc6, 0x03, 0xdb, 0xa5,
+	0xb7, 0xad, 0xc0, 0xbe, 0x20, 0x01, 0xbd, 0xed, 0x07, 0x5e, 0xe8, 0x49, 0xaa, 0xc9, 0x09, 0xf4,
+	0xd1, 0xd0, 0xa0, 0x43, 0xdb, 0xf4, 0x02, 0xbf, 0xe9, 0x7a, 0x8e, 0x61, 0x35, 0xe5, 0x98, 0xa6,
+	0x1c, 0x23, 0xba, 0x35, 0xbe, 0x37, 0xf0, 0xbc, 0xc1, 0x88, 0x08, 0x84, 0xb3, 0xf1, 0xcb, 0xdb,
+	0xd6, 0x38, 0x30, 0x42, 0xdb, 0x73, 0x65, 0xfb, 0x87, 0xd3, 0xed, 0xa1, 0xed, 0x10, 0x1a, 0x1a,
+	0x8e, 0x2f, 0x3b, 0x7c, 0x14, 0xc9, 0x42, 0x87, 0x46, 0x40, 0xac, 0xdb, 0x43, 0x73, 0x44, 0x7d,
+	0x62, 0xb2, 0x5f, 0x9d, 0x7d, 0xc8, 0x6e, 0x1f, 0x4f, 0x75, 0xa3, 0x61, 0x30, 0x36, 0xc3, 0x48,
+	0x72, 0x23, 0x0c, 0x03, 0xfb, 0x6c, 0x1c, 0x12, 0xd1, 0x5b, 0xbb, 0x09, 0xef, 0xf7, 0x0d, 0x7a,
+	0xde, 0xf6, 0xdc, 0x97, 0xf6, 0xa0, 0x67, 0x0e, 0x89, 0x63, 0x60, 0xf2, 0xf5, 0x98, 0xd0, 0x50,
+	0xfb, 0x53, 0xa8, 0xcf, 0x36, 0x51, 0xdf, 0x73, 0x29, 0x41, 0x5f, 0x40, 0x81, 0x4d, 0x59, 0x57,
+	0x6e, 0x29, 0x3b, 0xd5, 0xdd, 0x8f, 0x9b, 0x6f, 0x52, 0x81, 0x90, 0xa1, 0x29, 0x45, 0x6d, 0xf6,
+	0x7c, 0x62, 0x62, 0x3e, 0x52, 0xdb, 0x86, 0xcd, 0xb6, 0xe1, 0x1b, 0x67, 0xf6, 0xc8, 0x0e, 0x6d,
+	0x42, 0xa3, 0x49, 0xc7, 0xb0, 0x95, 0x66, 0xcb, 0x09, 0x7f, 0x0e, 0xab, 0x66, 0x82, 0x2f, 0x27,
+	0xbe, 0xdb, 0xcc, 0xa4, 0xfb, 0xe6, 0x1e, 0xa7, 0x52, 0xc0, 0x29, 0x38, 0x6d, 0x0b, 0xd0, 0x63,
+	0xdb, 0x1d, 0x90, 0xc0, 0x0f, 0x6c, 0x37, 0x8c, 0x84, 0xf9, 0x2e, 0x0f, 0x9b, 0x29, 0xb6, 0x14,
+	0xe6, 0x15, 0x40, 0xac, 0x47, 0x26, 0x4a, 0x7e, 0xa7, 0xba, 0xfb, 0x65, 0x46, 0x51, 0xe6, 0xe0,
+	0x35, 0x5b, 0x31, 0x58, 0xc7, 0x0d, 0x83, 0x2b, 0x9c, 0x40, 0x47, 0x5f, 0x41, 0x69, 0x48, 0x8c,
+	0x51, 0x38, 0xac, 0xe7, 0x6e, 0x29, 0x3b, 0xb5, 0xdd, 0xc7, 0xd7, 0x98, 0x67, 0x9f, 0x03, 0xf5,
+	0x42, 0x23, 0x24, 0x58, 0xa2, 0xa2, 0x4f, 0x00, 0x89, 0x2f, 0xdd, 0x22, 0xd4, 0x0c, 0x6c, 0x9f,
+	0xb9, 0x64, 0x3d, 0x7f, 0x4b, 0xd9, 0xa9, 0xe0, 0x0d, 0xd1, 0xb2, 0x37, 0x69, 0x68, 0xf8, 0xb0,
+	0x3e, 0x25, 0x2d, 0x52, 0x21, 0x7f, 0x4e, 0xae, 0xb8, 0x45, 0x2a, 0x98, 0x7d, 0xa2, 0x27, 0x50,
+	0xbc, 0x30, 0x46, 0x63, 0xc2, 0x45, 0xae, 0xee, 0xfe, 0xe8, 0x6d, 0xee, 0x21, 0x5d, 0x74, 0xa2,
+	0x07, 0x2c, 0xc6, 0xdf, 0xcb, 0x7d, 0xae, 0x68, 0x77, 0xa1, 0x9a, 0x90, 0x1b, 0xd5, 0x00, 0x4e,
+	0xbb, 0x7b, 0x9d, 0x7e, 0xa7, 0xdd, 0xef, 0xec, 0xa9, 0x37, 0xd0, 0x1a, 0x54, 0x4e, 0xbb, 0xfb,
+	0x9d, 0xd6, 0x61, 0x7f, 0xff, 0xb9, 0xaa, 0xa0, 0x2a, 0xac, 0x44, 0x44, 0x4e, 0xbb, 0x04, 0x84,
+	0x89, 0xe9, 0x5d, 0x90, 0x80, 0x39, 0xb2, 0xb4, 0x2a, 0x7a, 0x1f, 0x56, 0x42, 0x83, 0x9e, 0xeb,
+	0xb6, 0x25, 0x65, 0x2e, 0x31, 0xf2, 0xc0, 0x42, 0x07, 0x50, 0x1a, 0x1a, 0xae, 0x35, 0x7a, 0xbb,
+	0xdc, 0x69, 0x55, 0x33, 0xf0, 0x7d, 0x3e, 0x10, 0x4b, 0x00, 0xe6, 0xdd, 0xa9, 0x99, 0x85, 0x01,
+	0xb4, 0xe7, 0xa0, 0xf6, 0x42, 0x23, 0x08, 0x93, 0xe2, 0x74, 0xa0, 0xc0, 0xe6, 0xaf, 0x2b, 0x0b,
+	0xcf, 0x29, 0x56, 0x26, 0xe6, 0xc3, 0xb5, 0xff, 0xcb, 0xc1, 0x46, 0x02, 0x5b, 0x7a, 0xea, 0x33,
+	0x28, 0x05, 0x84, 0x8e, 0x47, 0x21, 0x87, 0xaf, 0xed, 0x3e, 0xcc, 0x08, 0x3f, 0x83, 0xd4, 0xc4,
+	0x1c, 0x06, 0x4b, 0x38, 0xb4, 0x03, 0xaa, 0x18, 0xa1, 0x93, 0x20, 0xf0, 0x02, 0xdd, 0xa1, 0x03,
+	0xae, 0xb5, 0x0a, 0xae, 0x09, 0x7e, 0x87, 0xb1, 0x8f, 0xe8, 0x20, 0xa1, 0xd5, 0xfc, 0x35, 0xb5,
+	0x8a, 0x0c, 0x50, 0x5d, 0x12, 0xbe, 0xf6, 0x82, 0x73, 0x9d, 0xa9, 0x36, 0xb0, 0x2d, 0x52, 0x2f,
+	0x70, 0xd0, 0xcf, 0x32, 0x82, 0x76, 0xc5, 0xf0, 0x63, 0x39, 0x1a, 0xaf, 0xbb, 0x69, 0x86, 0xf6,
+	0x43, 0x28, 0x89, 0x7f, 0xca, 0x3c, 0xa9, 0x77, 0xda, 0x6e, 0x77, 0x7a, 0x3d, 0xf5, 0x06, 0xaa,
+	0x40, 0x11, 0x77, 0xfa, 0x98, 0x79, 0x58, 0x05, 0x8a, 0x8f, 0x5b, 0xfd, 0xd6, 0xa1, 0x9a, 0xd3,
+	0x7e, 0x00, 0xeb, 0xcf, 0x0c, 0x3b, 0xcc, 0xe2, 0x5c, 0x9a, 0x07, 0xea, 0xa4, 0xaf, 0xb4, 0xce,
+	0x41, 0xca, 0x3a, 0xd9, 0x55, 0xd3, 0xb9, 0xb4, 0xc3, 0x29, 0x7b, 0xa8, 0x90, 0x27, 0x41, 0x20,
+	0x4d, 0xc0, 0x3e, 0xb5, 0xd7, 0xb0, 0xde, 0x0b, 0x3d, 0x3f, 0x93, 0xe7, 0xff, 0x18, 0x56, 0x58,
+	0xb4, 0xf1, 0xc6, 0xa1, 0x74, 0xfd, 0x9b, 0x4d, 0x11, 0x8d, 0x9a, 0x51, 0x34, 0x6a, 0xee, 0xc9,
+	0x68, 0x85, 0xa3, 0x9e, 0xe8, 0x3d, 0x28, 0x51, 0x7b, 0xe0, 0x1a, 0x23, 0xb9, 0x5b, 0x48, 0x4a,
+	0x43, 0xa0, 0x4e, 0x26, 0x96, 0x8e, 0xdf, 0x06, 0xb4, 0x47, 0x68, 0x18, 0x78, 0x57, 0x99, 0xe4,
+	0xd9, 0x82, 0xe2, 0x4b, 0x2f, 0x30, 0xc5, 0x42, 0x2c, 0x63, 0x41, 0xb0, 0x45, 0x95, 0x02, 0x91,
+	0xd8, 0x9f, 0x00, 0x3a, 0x70, 0x59, 0x4c, 0xc9, 0x66, 0x88, 0x7f, 0xcc, 0xc1, 0x66, 0xaa, 0xbf,
+	0x34, 0xc6, 0xf2, 0xeb, 0x90, 0x6d, 0x4c, 0x63, 0x2a, 0xd6, 0x21, 0x3a, 0x86, 0x92, 0xe8, 0x21,
+	0x35, 0x79, 0x67, 0x01, 0x20, 0x11, 0xa6, 0x24, 0x9c, 0x84, 0x99, 0xeb, 0xf4, 0xf9, 0x77, 0xeb,
+	0xf4, 0xaf, 0x41, 0x8d, 0xfe, 0x07, 0x7d, 0xab, 0x6d, 0xbe, 0x84, 0x4d, 0xd3, 0x1b, 0x8d, 0x88,
+	0xc9, 0xbc, 0x41, 0xb7, 0xdd, 0x90, 0x04, 0x17, 0xc6, 0xe8, 0xed, 0x7e, 0x83, 0x26, 0xa3, 0x0e,
+	0xe4, 0x20, 0xed, 0x05, 0x6c, 0x24, 0x26, 0x96, 0x86, 0x78, 0x0c, 0x45, 0xca, 0x18, 0xd2, 0x12,
+	0x9f, 0x2e, 0x68, 0x09, 0x8a, 0xc5, 0x70, 0x6d, 0x53, 0x80, 0x77, 0x2e, 0x88, 0x1b, 0xff, 0x2d,
+	0x6d, 0x0f, 0x36, 0x7a, 0xdc, 0x4d, 0x33, 0xf9, 0xe1, 0xc4, 0xc5, 0x73, 0x29, 0x17, 0xdf, 0x02,
+	0x94, 0x44, 0x91, 0x8e, 0x78, 0x05, 0xeb, 0x9d, 0x4b, 0x62, 0x66, 0x42, 0xae, 0xc3, 0x8a, 0xe9,
+	0x39, 0x8e, 0xe1, 0x5a, 0xf5, 0xdc, 0xad, 0xfc, 0x4e, 0x05, 0x47, 0x64, 0x72, 0x2d, 0xe6, 0xb3,
+	0xae, 0x45, 0xed, 0xef, 0x15, 0x50, 0x27, 0x73, 0x4b, 0x45, 0x32, 0xe9, 0x43, 0x8b, 0x01, 0xb1,
+	0xb9, 0x57, 0xb1, 0xa4, 0x24, 0x3f, 0xda, 0x2e, 0x04, 0x9f, 0x04, 0x41, 0x62, 0x3b, 0xca, 0x5f,
+	0x73, 0x3b, 0xd2, 0xf6, 0xe1, 0xf7, 0x22, 0x71, 0x7a, 0x61, 0x40, 0x0c, 0xc7, 0x76, 0x07, 0x07,
+	0xc7, 0xc7, 0x3e, 0x11, 0x82, 0x23, 0x04, 0x05, 0xcb, 0x08, 0x0d, 0x29, 0x18, 0xff, 0x66, 0x8b,
+	0xde, 0x1c, 0x79, 0x34, 0x5e, 0xf4, 0x9c, 0xd0, 0xfe, 0x33, 0x0f, 0xf5, 0x19, 0xa8, 0x48, 0xbd,
+	0x2f, 0xa0, 0x48, 0x49, 0x38, 0xf6, 0xa5, 0xab, 0x74, 0x32, 0x0b, 0x3c, 0x1f, 0xaf, 0xd9, 0x63,
+	0x60, 0x58, 0x60, 0xa2, 0x01, 0x94, 0xc3, 0xf0, 0x4a, 0xa7, 0xf6, 0x2f, 0xa2, 0x84, 0xe0, 0xf0,
+	0xba, 0xf8, 0x7d, 0x12, 0x38, 0xb6, 0x6b, 0x8c, 0x7a, 0xf6, 0x2f, 0x08, 0x5e, 0x09, 0xc3, 0x2b,
+	0xf6, 0x81, 0x9e, 0x33, 0x87, 0xb7, 0x6c, 0x57, 0xaa, 0xbd, 0xbd, 0xec, 0x2c, 0x09, 0x05, 0x63,
+	0x81, 0xd8, 0x38, 0x84, 0x22, 0xff, 0x4f, 0xcb, 0x38, 0xa2, 0x0a, 0xf9, 0x30, 0xbc, 0xe2, 0x42,
+	0x95, 0x31, 0xfb, 0x6c, 0xdc, 0x87, 0xd5, 0xe4, 0x3f, 0x60, 0x8e, 0x34, 0x24, 0xf6, 0x60, 0x28,
+	0x1c, 0xac, 0x88, 0x25, 0xc5, 0x2c, 0xf9, 0xda, 0xb6, 0x64, 0xca, 0x5a, 0xc4, 0x82, 0xd0, 0xfe,
+	0x3d, 0x07, 0x37, 0xe7, 0x68, 0x46, 0x3a, 0xeb, 0x8b, 0x94, 0xb3, 0xbe, 0x23, 0x2d, 0x44, 0x1e,
+	0xff, 0x22, 0xe5, 0xf1, 0xef, 0x10, 0x9c, 0x2d, 0x9b, 0xf7, 0xa0, 0x44, 0x2e, 0xed, 0x90, 0x58,
+	0x52, 0x55, 0x92, 0x4a, 0x2c, 0xa7, 0xc2, 0x75, 0x97, 0xd3, 0x11, 0x6c, 0xb5, 0x03, 0x62, 0x84,
+	0x44, 0x6e, 0xe5, 0x91, 0xff, 0xdf, 0x84, 0xb2, 0x31, 0x1a, 0x79, 0xe6, 0xc4, 0xac, 0x2b, 0x9c,
+	0x3e, 0xb0, 0x50, 0x03, 0xca, 0x43, 0x8f, 0x86, 0xae, 0xe1, 0x10, 0xb9, 0x79, 0xc5, 0xb4, 0xf6,
+	0x8d, 0x02, 0xdb, 0x53, 0x78, 0xd2, 0x0a, 0x67, 0x50, 0xb3, 0xa9, 0x37, 0xe2, 0x7f, 0x50, 0x4f,
+	0x9c, 0xf0, 0x7e, 0xb2, 0x58, 0xa8, 0x39, 0x88, 0x30, 0xf8, 0x81, 0x6f, 0xcd, 0x4e, 0x92, 0xdc,
+	0xe3, 0xf8, 0xe4, 0x96, 0x5c, 0xe9, 0x11, 0xa9, 0xfd, 0x93, 0x02, 0xdb, 0x32, 0xc2, 0x67, 0xff,
+	0xa3, 0xb3, 0x22, 0xe7, 0xde, 0xb5, 0xc8, 0x5a, 0x1d, 0xde, 0x9b, 0x96, 0x4b, 0xee, 0xf9, 0x8f,
+	0x60, 0xbb, 0x3d, 0x24, 0xe6, 0xb9, 0xef, 0xd9, 0x6e, 0xa6, 0xfc, 0x83, 0x2d, 0x2b, 0xcb, 0x8e,
+	0x33, 0x35, 0xcb, 0x0e, 0x18, 0xfa, 0x34, 0x86, 0x44, 0x77, 0xd8, 0x01, 0x86, 0x86, 0x5e, 0x40,
+	0xde, 0xfd, 0x89, 0x61, 0x8e, 0x20, 0xbf, 0x51, 0x60, 0x33, 0x35, 0xdf, 0x24, 0x4f, 0x95, 0x29,
+	0xbc, 0xf2, 0xbb, 0x48, 0xe1, 0x73, 0xef, 0x36, 0x9b, 0xf9, 0xb6, 0x08, 0x68, 0xf6, 0xc0, 0x8f,
+	0xbe, 0x0f, 0xab, 0x94, 0xb8, 0x96, 0x2e, 0x42, 0xb8, 0xc8, 0x2e, 0xca, 0xb8, 0xca, 0x78, 0x22,
+	0x96, 0x53, 0x16, 0x95, 0xc8, 0xa5, 0x74, 0xa0, 0x32, 0xe6, 0xdf, 0x68, 0x08, 0xab, 0x2f, 0xa9,
+	0x1e, 0xbb, 0x03, 0x5f, 0xe3, 0xb5, 0xcc, 0x91, 0x66, 0x56, 0x8e, 0xe6, 0xe3, 0x5e, 0xec, 0x6a,
+	0xb8, 0xfa, 0x92, 0xc6, 0x04, 0xfa, 0x95, 0x02, 0xef, 0x47, 0xba, 0x99, 0x78, 0xb4, 0xe3, 0x59,
+	0x84, 0xd6, 0x0b, 0xb7, 0xf2, 0x3b, 0xb5, 0xdd, 0x93, 0x6b, 0xb8, 0xf4, 0x0c, 0xf3, 0xc8, 0xb3,
+	0x08, 0xde, 0x76, 0xe7, 0x70, 0x29, 0x6a, 0xc2, 0xa6, 0x33, 0xa6, 0xa1, 0x2e, 0x16, 0xa6, 0x2e,
+	0x3b, 0xd5, 0x8b, 0x5c, 0x2f, 0x1b, 0xac, 0x29, 0xb5, 0x7d, 0xa0, 0x73, 0x58, 0x73, 0xbc, 0xb1,
+	0x1b, 0xea, 0x26, 0x77, 0x30, 0x5a, 0x2f, 0x2d, 0x74, 0x57, 0x31, 0x47, 0x4b, 0x47, 0x0c, 0x4e,
+	0xb8, 0x2b, 0xc5, 0xab, 0x4e, 0x82, 0x62, 0x86, 0x0c, 0x88, 0xe3, 0x85, 0x44, 0x67, 0x6e, 0x4c,
+	0xeb, 0x2b, 0xc2, 0x90, 0x82, 0xc7, 0x5c, 0x8e, 0xa2, 0xef, 0x01, 0x98, 0xf1, 0x8a, 0xaa, 0x97,
+	0x79, 0x87, 0x04, 0x47, 0x6b, 0x42, 0x35, 0x61, 0x06, 0x54, 0x86, 0x42, 0xf7, 0xb8, 0xdb, 0x51,
+	0x6f, 0x20, 0x80, 0x52, 0x7b, 0x1f, 0x1f, 0x1f, 0xf7, 0xc5, 0x41, 0xef, 0xe0, 0xa8, 0xf5, 0xa4,
+	0xa3, 0xe6, 0xb4, 0x0e, 0xac, 0x26, 0x05, 0x42, 0x08, 0x6a, 0xa7, 0xdd, 0xa7, 0xdd, 0xe3, 0x67,
+	0x5d, 0xfd, 0xe8, 0xf8, 0xb4, 0xdb, 0x67, 0x47, 0xc4, 0x1a, 0x40, 0xab, 0xfb, 0x7c, 0x42, 0xaf,
+	0x41, 0xa5, 0x7b, 0x1c, 0x91, 0x4a, 0x23, 0xa7, 0x2a, 0xda, 0x6f, 0xf2, 0xb0, 0x35, 0xcf, 0x36,
+	0xc8, 0x82, 0x02, 0xb3, 0xb3, 0x3c, 0xa4, 0xbf, 0x7b, 0x33, 0x73, 0x74, 0xe6, 0xde, 0xbe, 0x21,
+	0xa3, 0x72, 0x05, 0xf3, 0x6f, 0xa4, 0x43, 0x69, 0x64, 0x9c, 0x91, 0x11, 0xad, 0xe7, 0xf9, 0x35,
+	0xd6, 0x93, 0xeb, 0xcc, 0x7d, 0xc8, 0x91, 0xc4, 0x1d, 0x96, 0x84, 0x45, 0x7d, 0xa8, 0xb2, 0xb8,
+	0x43, 0x85, 0xea, 0x64, 0x28, 0xdc, 0xcd, 0x38, 0xcb, 0xfe, 0x64, 0x24, 0x4e, 0xc2, 0x34, 0xee,
+	0x42, 0x35, 0x31, 0xd9, 0x9c, 0x2b, 0xa8, 0xad, 0xe4, 0x15, 0x54, 0x25, 0x79, 0x9f, 0xf4, 0x10,
+	0xb6, 0xe6, 0xe9, 0x88, 0x39, 0xc1, 0xfe, 0x71, 0xaf, 0x2f, 0x0e, 0xfb, 0x4f, 0xf0, 0xf1, 0xe9,
+	0x89, 0xaa, 0x30, 0x66, 0xbf, 0xd5, 0x7b, 0xaa, 0xe6, 0x62, 0x1f, 0xc9, 0x6b, 0x6d, 0xa8, 0x26,
+	0xe4, 0x4a, 0x05, 0x5a, 0x25, 0x1d, 0x68, 0x59, 0xa8, 0x33, 0x2c, 0x2b, 0x20, 0x94, 0x4a, 0x39,
+	0x22, 0x52, 0x7b, 0x01, 0x95, 0xbd, 0x6e, 0x4f, 0x42, 0xd4, 0x61, 0x85, 0x92, 0x80, 0xfd, 0x6f,
+	0x7e, 0x99, 0x58, 0xc1, 0x11, 0xc9, 0xc0, 0x29, 0x31, 0x02, 0x73, 0x48, 0xa8, 0x4c, 0xcf, 0x62,
+	0x9a, 0x8d, 0xf2, 0xf8, 0xa5, 0x9c, 0xb0, 0x5d, 0x05, 0x47, 0xa4, 0xf6, 0xbf, 0x2b, 0x00, 0x93,
+	0xed, 0x1e, 0xd5, 0x20, 0x17, 0x47, 0xa1, 0x9c, 0x6d, 0x31, 0x3f, 0x48, 0xa4, 0x05, 0xfc, 0x1b,
+	0xed, 0xc2, 0xb6, 0x43, 0x07, 0xbe, 0x61, 0x9e, 0xeb, 0xf2, 0x5e, 0x47, 0x2c, 0x65, 0xbe, 0xdf,
+	0xad, 0xe2, 0x4d, 0xd9, 0x28, 0x57, 0xaa, 0xc0, 0x3d, 0x84, 0x3c, 0x71, 0x2f, 0xf8, 0xde, 0x54,
+	0xdd, 0xbd, 0xb7, 0x70, 0x18, 0x6a, 0x76, 0xdc, 0x0b, 0xe1, 0x2b, 0x0c, 0x06, 0xe9, 0x00, 0x16,
+	0xb9, 0xb0, 0x4d, 0xa2, 0x33, 0xd0, 0x22, 0x07, 0xfd, 0x62, 0x71, 0xd0, 0x3d, 0x8e, 0x11, 0x43,
+	0x57, 0xac, 0x88, 0x46, 0x5d, 0xa8, 0x04, 0x84, 0x7a, 0xe3, 0xc0, 0x24, 0x62, 0x83, 0xca, 0x7e,
+	0xb6, 0xc4, 0xd1, 0x38, 0x3c, 0x81, 0x40, 0x7b, 0x50, 0xe2, 0xfb, 0x12, 0xdb, 0x81, 0xf2, 0xbf,
+	0xf5, 0x16, 0x3c, 0x0d, 0xc6, 0x77, 0x12, 0x2c, 0xc7, 0xa2, 0x27, 0xb0, 0x22, 0x44, 0xa4, 0xf5,
+	0x32, 0x87, 0xf9, 0x24, 0xeb, 0xa6, 0xc9, 0x47, 0xe1, 0x68, 0x34, 0xb3, 0xea, 0x98, 0x92, 0xa0,
+	0x5e, 0x11, 0x56, 0x65, 0xdf, 0xe8, 0x03, 0xa8, 0x88, 0xb4, 0x89, 0x05, 0x7a, 0x10, 0xce, 0xc9,
+	0x19, 0x7b, 0x76, 0x80, 0x3e, 0x84, 0xaa, 0x48, 0x8f, 0x75, 0xbe, 0x2b, 0x54, 0x79, 0x33, 0x08,
+	0xd6, 0x09, 0xdb, 0x1b, 0x44, 0x07, 0x12, 0x04, 0xa2, 0xc3, 0x6a, 0xdc, 0x81, 0x04, 0x01, 0xef,
+	0xf0, 0x87, 0xb0, 0xce, 0x73, 0x9c, 0x41, 0xe0, 0x8d, 0x7d, 0x9d, 0xfb, 0xd4, 0x1a, 0xef, 0xb4,
+	0xc6, 0xd8, 0x4f, 0x18, 0xb7, 0xcb, 0x9c, 0xeb, 0x26, 0x94, 0x5f, 0x79, 0x67, 0xa2, 0x43, 0x4d,
+	0xac, 0x83, 0x57, 0xde, 0x59, 0xd4, 0x14, 0x27, 0x76, 0xeb, 0xe9, 0xc4, 0xee, 0x6b, 0x78, 0x6f,
+	0x36, 0x1c, 0xf2, 0x04, 0x4f, 0xbd, 0x7e, 0x82, 0xb7, 0xe5, 0xce, 0xe1, 0xa2, 0x47, 0x90, 0xb7,
+	0x5c, 0x5a, 0xdf, 0x58, 0xc8, 0x39, 0xe2, 0x75, 0x8c, 0xd9, 0xe0, 0xc6, 0x67, 0x50, 0x8e, 0xbc,
+	0x6f, 0x91, 0x7d, 0xa9, 0x71, 0x1f, 0x6a, 0x69, 0xdf, 0x5d, 0x68, 0x57, 0xfb, 0xd7, 0x1c, 0x54,
+	0x62, 0x2f, 0x45, 0x2e, 0x6c, 0x72, 0x2d, 0x1a, 0x21, 0xb1, 0xf4, 0x89, 0xd3, 0x8b, 0xec, 0xed,
+	0x41, 0xc6, 0xff, 0xd5, 0x8a, 0x10, 0x64, 0x2e, 0x28, 0x57, 0x00, 0x8a, 0x91, 0x27, 0xf3, 0x7d,
+	0x05, 0xeb, 0x23, 0xdb, 0x1d, 0x5f, 0x26, 0xe6, 0x12, 0x49, 0xdd, 0x1f, 0x65, 0x9c, 0xeb, 0x90,
+	0x8d, 0x9e, 0xcc, 0x51, 0x1b, 0xa5, 0x68, 0xb4, 0x0f, 0x45, 0xdf, 0x0b, 0xc2, 0x28, 0x48, 0x65,
+	0x0d, 0x1f, 0x27, 0x5e, 0x10, 0x1e, 0x19, 0xbe, 0xcf, 0xce, 0x99, 0x02, 0x40, 0xfb, 0x26, 0x07,
+	0xef, 0xcd, 0xff, 0x63, 0xa8, 0x0b, 0x79, 0xd3, 0x1f, 0x4b, 0x25, 0xdd, 0x5f, 0x54, 0x49, 0x6d,
+	0x7f, 0x3c, 0x91, 0x9f, 0x01, 0xb1, 0xbb, 0x77, 0x87, 0x38, 0x5e, 0x70, 0x25, 0x75, 0xf1, 0x70,
+	0x51, 0xc8, 0x23, 0x3e, 0x7a, 0x82, 0x2a, 0xe1, 0x10, 0x86, 0xb2, 0xf4, 0x5e, 0x2a, 0xf7, 0xc9,
+	0x05, 0x73, 0xe7, 0x08, 0x12, 0xc7, 0x38, 0xda, 0x67, 0xb0, 0x3d, 0xf7, 0xaf, 0xa0, 0xdf, 0x07,
+	0x30, 0xfd, 0xb1, 0xce, 0x5f, 0x6a, 0x84, 0x07, 0xe5, 0x71, 0xc5, 0xf4, 0xc7, 0x3d, 0xce, 0xd0,
+	0x5e, 0x40, 0xfd, 0x4d, 0xf2, 0xb2, 0xdd, 0x47, 0x48, 0xac, 0x3b, 0x67, 0x5c, 0x07, 0x79, 0x5c,
+	0x16, 0x8c, 0xa3, 0x33, 0xa4, 0xc1, 0x5a, 0xd4, 0x68, 0x5c, 0xb2, 0x0e, 0x79, 0xde, 0xa1, 0x2a,
+	0x3b, 0x18, 0x97, 0x47, 0x67, 0xda, 0xb7, 0x39, 0x58, 0x9f, 0x12, 0x99, 0x9d, 0xb6, 0xc5, 0x8e,
+	0x17, 0x1d, 0xab, 0x04, 0xc5, 0xb6, 0x3f, 0xd3, 0xb6, 0xa2, 0xe3, 0x0c, 0xff, 0xe6, 0x81, 0xcf,
+	0x97, 0xb7, 0xd3, 0x39, 0xdb, 0x67, 0xcb, 0xc7, 0x39, 0xb3, 0x43, 0xca, 0xb3, 0x90, 0x22, 0x16,
+	0x04, 0x7a, 0x0e, 0xb5, 0x80, 0xf0, 0x80, 0x6b, 0xe9, 0xc2, 0xcb, 0x8a, 0x0b, 0x79, 0x99, 0x94,
+	0x90, 0x39, 0x1b, 0x5e, 0x8b, 0x90, 0x18, 0x45, 0xd1, 0x33, 0x58, 0xb3, 0xae, 0x5c, 0xc3, 0xb1,
+	0x4d, 0x89, 0x5c, 0x5a, 0x1a, 0x79, 0x55, 0x02, 0x71, 0x60, 0xf6, 0x28, 0x96, 0x68, 0x64, 0x7f,
+	0x8c, 0xa7, 0x5b, 0x52, 0x27, 0x82, 0x48, 0xef, 0x16, 0x45, 0xb9, 0x5b, 0x68, 0x67, 0x50, 0x4d,
+	0xac, 0x8b, 0x45, 0x86, 0x32, 0x7d, 0x86, 0x1e, 0xd7, 0x67, 0x11, 0xe7, 0x42, 0x8f, 0x9d, 0x71,
+	0x59, 0xaa, 0xa3, 0xdb, 0x3e, 0xd7, 0x68, 0x05, 0x97, 0x18, 0x79, 0xe0, 0x6b, 0xbf, 0xce, 0x41,
+	0x2d, 0xbd, 0xa4, 0x23, 0x3f, 0xf2, 0x49, 0x60, 0x7b, 0x56, 0xc2, 0x8f, 0x4e, 0x38, 0x83, 0xf9,
+	0x0a, 0x6b, 0xfe, 0x7a, 0xec, 0x85, 0x46, 0xe4, 0x2b, 0xa6, 0x3f, 0xfe, 0x63, 0x46, 0x4f, 0xf9,
+	0x60, 0x7e, 0xca, 0x07, 0xd1, 0xc7, 0x80, 0xa4, 0x2b, 0x8d, 0x6c, 0xc7, 0x0e, 0xf5, 0xb3, 0xab,
+	0x90, 0x08, 0x1b, 0xe7, 0xb1, 0x2a, 0x5a, 0x0e, 0x59, 0xc3, 0x23, 0xc6, 0x67, 0x8e, 0xe7, 0x79,
+	0x8e, 0x4e, 0x4d, 0x2f, 0x20, 0xba, 0x61, 0xbd, 0xe2, 0xa7, 0x9a, 0x3c, 0xae, 0x7a, 0x9e, 0xd3,
+	0x63, 0xbc, 0x96, 0xf5, 0x8a, 0x45, 0x3e, 0xd3, 0x1f, 0x53, 0x12, 0xea, 0xec, 0x87, 0x27, 0x0b,
+	0x15, 0x0c, 0x82, 0xd5, 0xf6, 0xc7, 0x14, 0xfd, 0x01, 0xac, 0x45, 0x1d, 0x78, 0xf0, 0x93, 0x51,
+	0x77, 0x55, 0x76, 0xe1, 0x3c, 0xa4, 0xc1, 0xea, 0x09, 0x09, 0x4c, 0xe2, 0x86, 0x7d, 0xdb, 0x3c,
+	0xa7, 0xfc, 0x1c, 0xa2, 0xe0, 0x14, 0xef, 0xcb, 0x42, 0x79, 0x45, 0x2d, 0xe3, 0x68, 0x36, 0x87,
+	0x38, 0x54, 0xfb, 0x39, 0x14, 0x79, 0x8a, 0xc0, 0x74, 0xc2, 0xc3, 0x2b, 0x8f, 0xbe, 0x32, 0xb5,
+	0x64, 0x0c, 0x1e, 0x7b, 0x3f, 0x80, 0x0a, 0xd7, 0x7d, 0x22, 0xa3, 0xe7, 0x79, 0x27, 0x6f, 0x6c,
+	0x40, 0x39, 0x20, 0x86, 0xe5, 0xb9, 0xa3, 0xe8, 0xfe, 0x2e, 0xa6, 0xb5, 0xaf, 0xa1, 0x24, 0xe2,
+	0xcc, 0x35, 0xf0, 0x3f, 0x01, 0x24, 0xfe, 0x37, 0xb3, 0xa7, 0x63, 0x53, 0x2a, 0xb3, 0x50, 0xfe,
+	0x68, 0x2c, 0x5a, 0x4e, 0x26, 0x0d, 0xda, 0x7f, 0x29, 0x00, 0x93, 0xbb, 0x00, 0x96, 0xb8, 0x32,
+	0x27, 0x67, 0xa7, 0x69, 0x71, 0x6f, 0x18, 0x91, 0xec, 0xa2, 0x41, 0xa6, 0x9d, 0xb9, 0x65, 0xef,
+	0x36, 0x24, 0x40, 0xf4, 0x8a, 0x40, 0xe4, 0x81, 0x7d, 0xd1, 0x57, 0x04, 0x22, 0x5e, 0x11, 0x08,
+	0x3b, 0x6d, 0xca, 0x84, 0x58, 0xc0, 0x15, 0x78, 0x3e, 0x5c, 0xb5, 0xe2, 0xa7, 0x1a, 0xa2, 0xfd,
+	0x8f, 0x12, 0x6f, 0x53, 0xd1, 0x25, 0x04, 0xfa, 0x0a, 0xca, 0x6c, 0xc5, 0xeb, 0x8e, 0xe1, 0xcb,
+	0x02, 0x81, 0xf6, 0x72, 0xf7, 0x1b, 0x51, 0x10, 0x13, 0xe9, 0xec, 0x8a, 0x2f, 0x28, 0xb6, 0xdd,
+	0xb1, 0xa3, 0x44, 0xb4, 0xdd, 0xb1, 0x6f, 0xf4, 0x11, 0xd4, 0x8c, 0x71, 0xe8, 0xe9, 0x86, 0x75,
+	0x41, 0x82, 0xd0, 0xa6, 0x44, 0xda, 0x7e, 0x8d, 0x71, 0x5b, 0x11, 0xb3, 0x71, 0x0f, 0x56, 0x93,
+	0x98, 0x6f, 0x4b, 0x33, 0x8a, 0xc9, 0x34, 0xe3, 0xcf, 0x00, 0x26, 0xd7, 0x93, 0xcc, 0x47, 0xd8,
+	0x5d, 0xa7, 0x6e, 0x46, 0x67, 0xd7, 0x22, 0x2e, 0x33, 0x46, 0x9b, 0x9d, 0xa7, 0xd2, 0x6f, 0x27,
+	0xc5, 0xe8, 0xed, 0x84, 0x2d, 0x66, 0xb6, 0xfe, 0xce, 0xed, 0xd1, 0x28, 0xbe, 0x32, 0xad, 0x78,
+	0x9e, 0xf3, 0x94, 0x33, 0xb4, 0xef, 0x72, 0xc2, 0x57, 0xc4, 0x2b, 0x58, 0xa6, 0xb3, 0xcb, 0xbb,
+	0x32, 0xf5, 0x5d, 0x00, 0x1a, 0x1a, 0x01, 0xcb, 0x99, 0x8c, 0xe8, 0xd2, 0xb6, 0x31, 0xf3, 0xf8,
+	0xd2, 0x8f, 0xca, 0x72, 0x70, 0x45, 0xf6, 0x6e, 0x85, 0xe8, 0x01, 0xac, 0x9a, 0x9e, 0xe3, 0x8f,
+	0x88, 0x1c, 0x5c, 0x7c, 0xeb, 0xe0, 0x6a, 0xdc, 0xbf, 0x15, 0x26, 0xae, 0x8a, 0x4b, 0xd7, 0xbd,
+	0x2a, 0xfe, 0xb5, 0x22, 0x1e, 0xf3, 0x92, 0x6f, 0x89, 0x68, 0x30, 0xa7, 0x60, 0xe5, 0xc9, 0x92,
+	0x0f, 0x93, 0xbf, 0xad, 0x5a, 0xa5, 0xf1, 0x20, 0x4b, 0x79, 0xc8, 0x9b, 0xb3, 0xd8, 0xff, 0xc8,
+	0x43, 0x25, 0x32, 0xcb, 0xac, 0xed, 0x3f, 0x87, 0x4a, 0x5c, 0x13, 0x55, 0xcf, 0xbd, 0x55, 0xc3,
+	0x93, 0xce, 0xe8, 0x25, 0x20, 0x63, 0x30, 0x88, 0xb3, 0x53, 0x7d, 0x4c, 0x8d, 0x41, 0xf4, 0x8a,
+	0xfa, 0xf9, 0x02, 0x7a, 0x88, 0xc2, 0xd9, 0x29, 0x1b, 0x8f, 0x55, 0x63, 0x30, 0x48, 0x71, 0xd0,
+	0x9f, 0xc3, 0x76, 0x7a, 0x0e, 0xfd, 0xec, 0x4a, 0xf7, 0x6d, 0x4b, 0x9e, 0x91, 0xf7, 0x17, 0x7d,
+	0xca, 0x6c, 0xa6, 0xe0, 0x1f, 0x5d, 0x9d, 0xd8, 0x96, 0xd0, 0x39, 0x0a, 0x66, 0x1a, 0x1a, 0x7f,
+	0x09, 0xef, 0xbf, 0xa1, 0xfb, 0x1c, 0x1b, 0x74, 0xd3, 0x25, 0x3a, 0xcb, 0x2b, 0x21, 0x61, 0xbd,
+	0x7f, 0x51, 0x60, 0x63, 0xa6, 0x03, 0x6a, 0x25, 0xd3, 0xea, 0xdb, 0x19, 0xe7, 0x69, 0x9f, 0x9c,
+	0x0a, 0x78, 0x36, 0x16, 0x7d, 0x39, 0x95, 0x49, 0x67, 0xcd, 0x9f, 0x44, 0x42, 0x2a, 0x80, 0x24,
+	0x82, 0xf6, 0x6f, 0x79, 0x28, 0x47, 0xe8, 0xfc, 0x84, 0x7b, 0x45, 0x43, 0xe2, 0xe8, 0xf1, 0xf5,
+	0x9b, 0x82, 0x41, 0xb0, 0xf8, 0xa5, 0xd0, 0x07, 0x50, 0x61, 0x07, 0x69, 0xd1, 0x9c, 0xe3, 0xcd,
+	0x65, 0xc6, 0xe0, 0x8d, 0x1f, 0x42, 0x35, 0xf4, 0x42, 0x63, 0xa4, 0x87, 0x3c, 0xbc, 0xe7, 0xc5,
+	0x68, 0xce, 0xe2, 0xc1, 0x1d, 0xfd, 0x10, 0x36, 0xc2, 0x61, 0xe0, 0x85, 0xe1, 0x88, 0xa5, 0x96,
+	0x3c, 0xd1, 0x11, 0x79, 0x49, 0x01, 0xab, 0x71, 0x83, 0x48, 0x80, 0x28, 0xdb, 0xbd, 0x27, 0x9d,
+	0x99, 0xeb, 0xf2, 0x4d, 0xa4, 0x80, 0xd7, 0x62, 0x2e, 0x73, 0x6d, 0x16, 0x3c, 0x7d, 0x91, 0x40,
+	0xf0, 0xbd, 0x42, 0xc1, 0x11, 0x89, 0x74, 0x58, 0x77, 0x88, 0x41, 0xc7, 0x01, 0xb1, 0xf4, 0x97,
+	0x36, 0x19, 0x59, 0xe2, 0x62, 0xa2, 0x96, 0xf9, 0x74, 0x10, 0xa9, 0xa5, 0xf9, 0x98, 0x8f, 0xc6,
+	0xb5, 0x08, 0x4e, 0xd0, 0x2c, 0x73, 0x10, 0x5f, 0x68, 0x1d, 0xaa, 0xbd, 0xe7, 0xbd, 0x7e, 0xe7,
+	0x48, 0x3f, 0x3a, 0xde, 0xeb, 0xc8, 0x2a, 0xac, 0x5e, 0x07, 0x0b, 0x52, 0x61, 0xed, 0xfd, 0xe3,
+	0x7e, 0xeb, 0x50, 0xef, 0x1f, 0xb4, 0x9f, 0xf6, 0xd4, 0x1c, 0xda, 0x86, 0x8d, 0xfe, 0x3e, 0x3e,
+	0xee, 0xf7, 0x0f, 0x3b, 0x7b, 0xfa, 0x49, 0x07, 0x1f, 0x1c, 0xef, 0xf5, 0xd4, 0x3c, 0xbb, 0x47,
+	0x9d, 0xb0, 0xfb, 0x07, 0x47, 0x1d, 0xb5, 0xc0, 0xea, 0x6e, 0x4e, 0x3a, 0xb8, 0xdd, 0xe9, 0xf6,
+	0xd5, 0xa2, 0xf6, 0x6d, 0x1e, 0xaa, 0x09, 0x2b, 0x32, 0x47, 0x0e, 0xa8, 0x38, 0x86, 0x14, 0x30,
+	0xfb, 0xe4, 0xaf, 0xc6, 0x86, 0x39, 0x14, 0xd6, 0x29, 0x60, 0x41, 0xf0, 0xa3, 0x87, 0x71, 0x99,
+	0x58, 0xe7, 0x05, 0x5c, 0x76, 0x8c, 0x4b, 0x01, 0xf2, 0x7d, 0x58, 0x3d, 0x27, 0x81, 0x4b, 0x46,
+	0xb2, 0x5d, 0x58, 0xa4, 0x2a, 0x78, 0xa2, 0xcb, 0x0e, 0xa8, 0xb2, 0xcb, 0x04, 0x46, 0x98, 0xa3,
+	0x26, 0xf8, 0x47, 0x11, 0xd8, 0x16, 0x14, 0x45, 0xf3, 0x8a, 0x98, 0x9f, 0x13, 0x2c, 0x4c, 0xd1,
+	0xd7, 0x86, 0xcf, 0x53, 0xbe, 0x02, 0xe6, 0xdf, 0xe8, 0x6c, 0xd6, 0x3e, 0x25, 0x6e, 0x9f, 0xbb,
+	0x8b, 0xbb, 0xf3, 0x9b, 0x4c, 0x34, 0x8c, 0x4d, 0xb4, 0x02, 0x79, 0x1c, 0x95, 0x2e, 0xb5, 0x5b,
+	0xed, 0x7d, 0x66, 0x96, 0x35, 0xa8, 0x1c, 0xb5, 0x7e, 0xa6, 0x9f, 0xf6, 0xf8, 0xad, 0x36, 0x52,
+	0x61, 0xf5, 0x69, 0x07, 0x77, 0x3b, 0x87, 0x92, 0x93, 0x47, 0x5b, 0xa0, 0x4a, 0xce, 0xa4, 0x5f,
+	0x81, 0x21, 0x88, 0xcf, 0x22, 0xbb, 0x05, 0xed, 0x3d, 0x6b, 0x9d, 0xa8, 0x25, 0xed, 0xbf, 0x73,
+	0xb0, 0x2e, 0xc2, 0x42, 0x5c, 0x64, 0xf1, 0xe6, 0x37, 0xaf, 0xe4, 0x2d, 0x4f, 0x2e, 0x7d, 0xcb,
+	0x13, 0x25, 0xa1, 0x3c, 0xaa, 0xe7, 0x27, 0x49, 0x28, 0xbf, 0x1d, 0x4a, 0xed, 0xf8, 0x85, 0x45,
+	0x76, 0xfc, 0x3a, 0xac, 0x38, 0x84, 0xc6, 0x76, 0xab, 0xe0, 0x88, 0x44, 0x36, 0x54, 0x0d, 0xd7,
+	0xf5, 0x42, 0x43, 0x5c, 0x9d, 0x96, 0x16, 0x0a, 0x86, 0x53, 0xff, 0xb8, 0xd9, 0x9a, 0x20, 0x89,
+	0x8d, 0x39, 0x89, 0xdd, 0xf8, 0x29, 0xa8, 0xd3, 0x1d, 0x16, 0x09, 0x87, 0x3f, 0xf8, 0xd1, 0x24,
+	0x1a, 0x12, 0xb6, 0x2e, 0xe4, 0x9b, 0x83, 0x7a, 0x83, 0x11, 0xf8, 0xb4, 0xdb, 0x3d, 0xe8, 0x3e,
+	0x51, 0x15, 0xf6, 0x68, 0xd1, 0xf9, 0xd9, 0x01, 0x2b, 0x87, 0xcc, 0xed, 0x7e, 0xb7, 0x09, 0x25,
+	0x21, 0x24, 0xfa, 0x46, 0x66, 0x02, 0xc9, 0x02, 0x5e, 0xf4, 0xd3, 0x85, 0x33, 0xea, 0x54, 0x51,
+	0x70, 0xe3, 0xe1, 0xd2, 0xe3, 0xe5, 0x93, 0xe6, 0x0d, 0xf4, 0xb7, 0x0a, 0xac, 0xa6, 0x5e, 0xe6,
+	0xb2, 0x5e, 0x1d, 0xcf, 0xa9, 0x17, 0x6e, 0xfc, 0x64, 0xa9, 0xb1, 0xb1, 0x2c, 0xbf, 0x52, 0xa0,
+	0x9a, 0xa8, 0x94, 0x45, 0x77, 0x97, 0xa9, 0xae, 0x15, 0x92, 0xdc, 0x5b, 0xbe, 0x30, 0x57, 0xbb,
+	0xf1, 0xa9, 0x82, 0xfe, 0x46, 0x81, 0x6a, 0xa2, 0x66, 0x34, 0xb3, 0x28, 0xb3, 0x15, 0xae, 0x8d,
+	0x7b, 0xcb, 0x0c, 0x8d, 0x75, 0xf2, 0x57, 0x0a, 0x54, 0xe2, 0xfa, 0x4f, 0x74, 0x67, 0xf1, 0x8a,
+	0x51, 0x21, 0xc4, 0xe7, 0xcb, 0x96, 0x9a, 0x6a, 0x37, 0xd0, 0x5f, 0x40, 0x39, 0x2a, 0x96, 0x44,
+	0x59, 0xa3, 0xd7, 0x54, 0x25, 0x66, 0xe3, 0xce, 0xc2, 0xe3, 0x92, 0xd3, 0x47, 0x15, 0x8c, 0x99,
+	0xa7, 0x9f, 0xaa, 0xb5, 0x6c, 0xdc, 0x59, 0x78, 0x5c, 0x3c, 0x3d, 0xf3, 0x84, 0x44, 0xa1, 0x63,
+	0x66, 0x4f, 0x98, 0xad, 0xb0, 0x6c, 0xdc, 0x5b, 0x66, 0x68, 0x4a, 0x90, 0x44, 0xa9, 0x64, 0x66,
+	0x41, 0x66, 0xcb, 0x31, 0x1b, 0xf7, 0x96, 0x19, 0x1a, 0x0b, 0xf2, 0x4b, 0x25, 0x79, 0x2e, 0xb8,
+	0xb3, 0x70, 0x45, 0xe0, 0x82, 0x2e, 0x39, 0x53, 0x93, 0xc8, 0x17, 0xe8, 0x2f, 0xe5, 0x2d, 0x86,
+	0x28, 0x28, 0x44, 0x8b, 0x80, 0xa5, 0x6a, 0x10, 0x1b, 0x9f, 0x2d, 0x17, 0x6c, 0xb8, 0x10, 0x7f,
+	0xad, 0x00, 0x4c, 0x4a, 0x0f, 0x33, 0x0b, 0x31, 0x53, 0xf3, 0xd8, 0xb8, 0xbb, 0xc4, 0xc8, 0xe4,
+	0x02, 0x89, 0x4a, 0xa3, 0x32, 0x2f, 0x90, 0xa9, 0xd2, 0xc8, 0xc6, 0x9d, 0x85, 0xc7, 0xc5, 0xd3,
+	0xff, 0xb3, 0x02, 0x1b, 0x33, 0xa5, 0x59, 0xe8, 0xe1, 0x35, 0xab, 0xf3, 0x1a, 0x5f, 0x2c, 0x0f,
+	0x10, 0x89, 0xb6, 0xa3, 0x7c, 0xaa, 0xa0, 0xbf, 0x53, 0x60, 0x2d, 0x5d, 0x1f, 0x91, 0x39, 0x4a,
+	0xcd, 0x29, 0xf2, 0x6a, 0xdc, 0x5f, 0x6e, 0x70, 0xac, 0xad, 0x7f, 0x50, 0xa0, 0x26, 0xd7, 0x77,
+	0x24, 0xcf, 0xfd, 0xc5, 0xb6, 0x85, 0x29, 0x81, 0x1e, 0x2c, 0x39, 0x3a, 0x25, 0x51, 0xba, 0xe2,
+	0x29, 0xb3, 0x44, 0x73, 0x8b, 0xad, 0x1a, 0x0f, 0x96, 0x1c, 0x9d, 0xda, 0xe9, 0x12, 0x95, 0x4f,
+	0x0b, 0x04, 0xdf, 0xe9, 0xea, 0xac, 0xc6, 0xbd, 0x65, 0x86, 0x46, 0x82, 0x3c, 0x5a, 0xf9, 0x93,
+	0xa2, 0x48, 0x6c, 0x4b, 0xfc, 0xe7, 0xc7, 0xff, 0x3f, 0x00, 0x5b, 0x4c, 0xcb, 0x3b, 0x82, 0x36,
+	0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -3986,6 +4186,12 @@ type DriverClient interface {
 	// DestroyNetwork destroys a previously created network. This rpc is only
 	// implemented if the driver needs to manage network namespace creation.
 	DestroyNetwork(ctx context.Context, in *DestroyNetworkRequest, opts ...grpc.CallOption) (*DestroyNetworkResponse, error)
+	// CheckpointTask dumps the state of a running task into a directory and
+	// stops the task, so that it can later be restored by RestoreTask.
+	CheckpointTask(ctx context.Context, in *CheckpointTaskRequest, opts ...grpc.CallOption) (*CheckpointTaskResponse, error)
+	// RestoreTask starts and tracks a task restored from the checkpoint of a
+	// previous task instead of starting it from scratch.
+	RestoreTask(ctx context.Context, in *RestoreTaskRequest, opts ...grpc.CallOption) (*RestoreTaskResponse, error)
 }
 
 type driverClient struct {
@@ -4231,6 +4437,24 @@ func (c *driverClient) DestroyNetwork(ctx context.Context, in *DestroyNetworkReq
 	return out, nil
 }
 
+func (c *driverClient) CheckpointTask(ctx context.Context, in *CheckpointTaskRequest, opts ...grpc.CallOption) (*CheckpointTaskResponse, error) {
+	out := new(CheckpointTaskResponse)
+	err := c.cc.Invoke(ctx, "/hashicorp.nomad.plugins.drivers.proto.Driver/CheckpointTask", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *driverClient) RestoreTask(ctx context.Context, in *RestoreTaskRequest, opts ...grpc.CallOption) (*RestoreTaskResponse, error) {
+	out := new(RestoreTaskResponse)
+	err := c.cc.Invoke(ctx, "/hashicorp.nomad.plugins.drivers.proto.Driver/RestoreTask", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DriverServer is the server API for Driver service.
 type DriverServer interface {
 	// TaskConfigSchema returns the schema for parsing the driver
@@ -4286,6 +4510,12 @@ type DriverServer interface {
 	// DestroyNetwork destroys a previously created network. This rpc is only
 	// implemented if the driver needs to manage network namespace creation.
 	DestroyNetwork(context.Context, *DestroyNetworkRequest) (*DestroyNetworkResponse, error)
+	// CheckpointTask dumps the state of a running task into a directory and
+	// stops the task, so that it can later be restored by RestoreTask.
+	CheckpointTask(context.Context, *CheckpointTaskRequest) (*CheckpointTaskResponse, error)
+	// RestoreTask starts and tracks a task restored from the checkpoint of a
+	// previous task instead of starting it from scratch.
+	RestoreTask(context.Context, *RestoreTaskRequest) (*RestoreTaskResponse, error)
 }
 
 // UnimplementedDriverServer can be embedded to have forward compatible implementations.
@@ -4340,6 +4570,12 @@ func (*UnimplementedDriverServer) CreateNetwork(ctx context.Context, req *Create
 func (*UnimplementedDriverServer) DestroyNetwork(ctx context.Context, req *DestroyNetworkRequest) (*DestroyNetworkResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DestroyNetwork not implemented")
 }
+func (*UnimplementedDriverServer) CheckpointTask(ctx context.Context, req *CheckpointTaskRequest) (*CheckpointTaskResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckpointTask not implemented")
+}
+func (*UnimplementedDriverServer) RestoreTask(ctx context.Context, req *RestoreTaskRequest) (*RestoreTaskResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreTask not implemented")
+}
 
 func RegisterDriverServer(s *grpc.Server, srv DriverServer) {
 	s.RegisterService(&_Driver_serviceDesc, srv)
@@ -4650,6 +4886,42 @@ func _Driver_DestroyNetwork_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Driver_CheckpointTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckpointTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServer).CheckpointTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hashicorp.nomad.plugins.drivers.proto.Driver/CheckpointTask",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServer).CheckpointTask(ctx, req.(*CheckpointTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Driver_RestoreTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServer).RestoreTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hashicorp.nomad.plugins.drivers.proto.Driver/RestoreTask",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServer).RestoreTask(ctx, req.(*RestoreTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Driver_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "hashicorp.nomad.plugins.drivers.proto.Driver",
 	HandlerType: (*DriverServer)(nil),
@@ -4702,6 +4974,14 @@ var _Driver_serviceDesc = grpc.ServiceDesc{
 			MethodName: "DestroyNetwork",
 			Handler:    _Driver_DestroyNetwork_Handler,
 		},
+		{
+			MethodName: "CheckpointTask",
+			Handler:    _Driver_CheckpointTask_Handler,
+		},
+		{
+			MethodName: "RestoreTask",
+			Handler:    _Driver_RestoreTask_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -45,6 +45,7 @@ func (b *driverPluginServer) Capabilities(ctx context.Context, req *proto.Capabi
 			MustCreateNetwork:     caps.MustInitiateNetwork,
 			NetworkIsolationModes: []proto.NetworkIsolationSpec_NetworkIsolationMode{},
 			RemoteTasks:           caps.RemoteTasks,
+			Checkpoint:            caps.Checkpoint,
 		},
 	}
 
@@ -106,31 +107,12 @@ func (b *driverPluginServer) RecoverTask(ctx context.Context, req *proto.Recover
 func (b *driverPluginServer) StartTask(ctx context.Context, req *proto.StartTaskRequest) (*proto.StartTaskResponse, error) {
 	handle, net, err := b.impl.StartTask(taskConfigFromProto(req.Task))
 	if err != nil {
-		if rec, ok := err.(structs.Recoverable); ok {
-			st := status.New(codes.FailedPrecondition, rec.Error())
-			st, err := st.WithDetails(&sproto.RecoverableError{Recoverable: rec.IsRecoverable()})
-			if err != nil {
-				// If this error, it will always error
-				panic(err)
-			}
-			return nil, st.Err()
-		}
-		return nil, err
+		return nil, startErrToProto(err)
 	}
 
-	var pbNet *proto.NetworkOverride
-	if net != nil {
-		pbNet = &proto.NetworkOverride{
-			PortMap:       map[string]int32{},
-			Addr:          net.IP,
-			AutoAdvertise: net.AutoAdvertise,
-		}
-		for k, v := range net.PortMap {
-			if v > math.MaxInt32 {
-				return nil, fmt.Errorf("port map out of bounds")
-			}
-			pbNet.PortMap[k] = int32(v)
-		}
+	pbNet, err := driverNetworkToProto(net)
+	if err != nil {
+		return nil, err
 	}
 
 	resp := &proto.StartTaskResponse{
@@ -141,6 +123,60 @@ func (b *driverPluginServer) StartTask(ctx context.Context, req *proto.StartTask
 	return resp, nil
 }
 
+func (b *driverPluginServer) RestoreTask(ctx context.Context, req *proto.RestoreTaskRequest) (*proto.RestoreTaskResponse, error) {
+	handle, net, err := b.impl.RestoreTask(taskConfigFromProto(req.Task), req.Dir)
+	if err != nil {
+		return nil, startErrToProto(err)
+	}
+
+	pbNet, err := driverNetworkToProto(net)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &proto.RestoreTaskResponse{
+		Handle:          taskHandleToProto(handle),
+		NetworkOverride: pbNet,
+	}
+
+	return resp, nil
+}
+
+// startErrToProto converts the error of starting or restoring a task into a
+// gRPC status conveying whether the error is recoverable.
+func startErrToProto(err error) error {
+	if rec, ok := err.(structs.Recoverable); ok {
+		st := status.New(codes.FailedPrecondition, rec.Error())
+		st, err := st.WithDetails(&sproto.RecoverableError{Recoverable: rec.IsRecoverable()})
+		if err != nil {
+			// If this error, it will always error
+			panic(err)
+		}
+		return st.Err()
+	}
+	return err
+}
+
+// driverNetworkToProto converts the network override of a started task.
+func driverNetworkToProto(net *DriverNetwork) (*proto.NetworkOverride, error) {
+	if net == nil {
+		return nil, nil
+	}
+
+	pbNet := &proto.NetworkOverride{
+		PortMap:       map[string]int32{},
+		Addr:          net.IP,
+		AutoAdvertise: net.AutoAdvertise,
+	}
+	for k, v := range net.PortMap {
+		if v > math.MaxInt32 {
+			return nil, fmt.Errorf("port map out of bounds")
+		}
+		pbNet.PortMap[k] = int32(v)
+	}
+	return pbNet, nil
+}
+
 func (b *driverPluginServer) WaitTask(ctx context.Context, req *proto.WaitTaskRequest) (*proto.WaitTaskResponse, error) {
 	ch, err := b.impl.WaitTask(ctx, req.TaskId)
 	if err != nil {
@@ -190,6 +226,15 @@ func (b *driverPluginServer) StopTask(ctx context.Context, req *proto.StopTaskRe
 	return &proto.StopTaskResponse{}, nil
 }
 
+func (b *driverPluginServer) CheckpointTask(ctx context.Context, req *proto.CheckpointTaskRequest) (*proto.CheckpointTaskResponse, error) {
+	err := b.impl.CheckpointTask(req.TaskId, req.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.CheckpointTaskResponse{}, nil
+}
+
 func (b *driverPluginServer) DestroyTask(ctx context.Context, req *proto.DestroyTaskRequest) (*proto.DestroyTaskResponse, error) {
 	err := b.impl.DestroyTask(req.TaskId, req.Force)
 	if err != nil {
@@ -0,0 +1,126 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+
+	plugin "github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/nomad/helper/pluginutils/grpcutils"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/scheduler/proto"
+	"google.golang.org/grpc"
+)
+
+// schedulerPluginClient implements the client side of a remote scheduler
+// plugin, using gRPC to communicate to the remote plugin.
+type schedulerPluginClient struct {
+	// basePluginClient is embedded to give access to the base plugin methods.
+	*base.BasePluginClient
+
+	client proto.SchedulerPluginClient
+
+	// broker is used to serve the state view to the plugin
+	broker *plugin.GRPCBroker
+
+	// doneCtx is closed when the plugin exits
+	doneCtx context.Context
+}
+
+// Process serves the state view to the plugin for the duration of the call
+// and returns the plan computed by the plugin.
+func (s *schedulerPluginClient) Process(eval *structs.Evaluation, state StateView) (*structs.Plan, error) {
+	evalBytes, err := encode(eval)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode evaluation: %v", err)
+	}
+
+	id := s.broker.NextId()
+	listener, err := s.broker.Accept(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serve state view: %v", err)
+	}
+	server := grpc.NewServer()
+	proto.RegisterStateViewServer(server, &stateViewServer{impl: state})
+	go server.Serve(listener)
+	defer server.Stop()
+
+	req := &proto.ProcessRequest{
+		Evaluation:  evalBytes,
+		StateServer: id,
+	}
+	resp, err := s.client.Process(s.doneCtx, req)
+	if err != nil {
+		return nil, grpcutils.HandleGrpcErr(err, s.doneCtx)
+	}
+
+	var plan structs.Plan
+	if err := decode(resp.GetPlan(), &plan); err != nil {
+		return nil, fmt.Errorf("failed to decode plan: %v", err)
+	}
+	return &plan, nil
+}
+
+// stateViewClient implements the StateView handed to scheduler plugins, using
+// gRPC to query the state snapshot served by Nomad.
+type stateViewClient struct {
+	client proto.StateViewClient
+
+	// ctx is the context of the Process call the state view is used for
+	ctx context.Context
+}
+
+func (s *stateViewClient) query(req *proto.StateQueryRequest, out interface{}) error {
+	resp, err := s.client.Query(s.ctx, req)
+	if err != nil {
+		return grpcutils.HandleGrpcErr(err, s.ctx)
+	}
+	return decode(resp.GetResult(), out)
+}
+
+func (s *stateViewClient) Nodes() ([]*structs.Node, error) {
+	var out []*structs.Node
+	err := s.query(&proto.StateQueryRequest{Method: stateMethodNodes}, &out)
+	return out, err
+}
+
+func (s *stateViewClient) NodeByID(nodeID string) (*structs.Node, error) {
+	var out *structs.Node
+	err := s.query(&proto.StateQueryRequest{Method: stateMethodNodeByID, Id: nodeID}, &out)
+	return out, err
+}
+
+func (s *stateViewClient) JobByID(namespace, jobID string) (*structs.Job, error) {
+	var out *structs.Job
+	req := &proto.StateQueryRequest{
+		Method:    stateMethodJobByID,
+		Namespace: namespace,
+		Id:        jobID,
+	}
+	err := s.query(req, &out)
+	return out, err
+}
+
+func (s *stateViewClient) AllocsByJob(namespace, jobID string, all bool) ([]*structs.Allocation, error) {
+	var out []*structs.Allocation
+	req := &proto.StateQueryRequest{
+		Method:    stateMethodAllocsByJob,
+		Namespace: namespace,
+		Id:        jobID,
+		All:       all,
+	}
+	err := s.query(req, &out)
+	return out, err
+}
+
+func (s *stateViewClient) AllocsByNode(nodeID string) ([]*structs.Allocation, error) {
+	var out []*structs.Allocation
+	err := s.query(&proto.StateQueryRequest{Method: stateMethodAllocsByNode, Id: nodeID}, &out)
+	return out, err
+}
+
+func (s *stateViewClient) SchedulerConfig() (*structs.SchedulerConfiguration, error) {
+	var out *structs.SchedulerConfiguration
+	err := s.query(&proto.StateQueryRequest{Method: stateMethodSchedulerConfig}, &out)
+	return out, err
+}
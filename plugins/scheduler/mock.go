@@ -0,0 +1,20 @@
+package scheduler
+
+import (
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/base"
+)
+
+type ProcessFn func(*structs.Evaluation, StateView) (*structs.Plan, error)
+
+// MockSchedulerPlugin is used for testing.
+// Each function can be set as a closure to make assertions about how data
+// is passed through the base plugin layer.
+type MockSchedulerPlugin struct {
+	*base.MockPlugin
+	ProcessF ProcessFn
+}
+
+func (p *MockSchedulerPlugin) Process(eval *structs.Evaluation, state StateView) (*structs.Plan, error) {
+	return p.ProcessF(eval, state)
+}
@@ -0,0 +1,52 @@
+package scheduler
+
+import (
+	"context"
+
+	log "github.com/hashicorp/go-hclog"
+	plugin "github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/nomad/plugins/base"
+	bproto "github.com/hashicorp/nomad/plugins/base/proto"
+	"github.com/hashicorp/nomad/plugins/scheduler/proto"
+	"google.golang.org/grpc"
+)
+
+// PluginScheduler wraps a SchedulerPlugin and implements go-plugins
+// GRPCPlugin interface to expose the interface over gRPC.
+type PluginScheduler struct {
+	plugin.NetRPCUnsupportedPlugin
+	Impl SchedulerPlugin
+}
+
+func (p *PluginScheduler) GRPCServer(broker *plugin.GRPCBroker, s *grpc.Server) error {
+	proto.RegisterSchedulerPluginServer(s, &schedulerPluginServer{
+		impl:   p.Impl,
+		broker: broker,
+	})
+	return nil
+}
+
+func (p *PluginScheduler) GRPCClient(ctx context.Context, broker *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
+	return &schedulerPluginClient{
+		doneCtx: ctx,
+		client:  proto.NewSchedulerPluginClient(c),
+		broker:  broker,
+		BasePluginClient: &base.BasePluginClient{
+			Client:  bproto.NewBasePluginClient(c),
+			DoneCtx: ctx,
+		},
+	}, nil
+}
+
+// Serve is used to serve a scheduler plugin
+func Serve(sched SchedulerPlugin, logger log.Logger) {
+	plugin.Serve(&plugin.ServeConfig{
+		HandshakeConfig: base.Handshake,
+		Plugins: map[string]plugin.Plugin{
+			base.PluginTypeBase:      &base.PluginBase{Impl: sched},
+			base.PluginTypeScheduler: &PluginScheduler{Impl: sched},
+		},
+		GRPCServer: plugin.DefaultGRPCServer,
+		Logger:     logger,
+	})
+}
@@ -0,0 +1,169 @@
+package scheduler
+
+import (
+	"fmt"
+	"testing"
+
+	plugin "github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/stretchr/testify/require"
+)
+
+// testStateView is a StateView backed by in-memory objects.
+type testStateView struct {
+	nodes  []*structs.Node
+	job    *structs.Job
+	allocs []*structs.Allocation
+}
+
+func (s *testStateView) Nodes() ([]*structs.Node, error) { return s.nodes, nil }
+
+func (s *testStateView) NodeByID(nodeID string) (*structs.Node, error) {
+	for _, node := range s.nodes {
+		if node.ID == nodeID {
+			return node, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *testStateView) JobByID(namespace, jobID string) (*structs.Job, error) {
+	if s.job.Namespace == namespace && s.job.ID == jobID {
+		return s.job, nil
+	}
+	return nil, nil
+}
+
+func (s *testStateView) AllocsByJob(namespace, jobID string, all bool) ([]*structs.Allocation, error) {
+	return s.allocs, nil
+}
+
+func (s *testStateView) AllocsByNode(nodeID string) ([]*structs.Allocation, error) {
+	return nil, fmt.Errorf("node %q not found", nodeID)
+}
+
+func (s *testStateView) SchedulerConfig() (*structs.SchedulerConfiguration, error) {
+	return &structs.SchedulerConfiguration{MemoryOversubscriptionEnabled: true}, nil
+}
+
+func testSchedulerPlugin(t *testing.T, impl SchedulerPlugin) SchedulerPlugin {
+	client, server := plugin.TestPluginGRPCConn(t, map[string]plugin.Plugin{
+		base.PluginTypeBase:      &base.PluginBase{Impl: impl},
+		base.PluginTypeScheduler: &PluginScheduler{Impl: impl},
+	})
+	t.Cleanup(func() {
+		client.Close()
+		server.Stop()
+	})
+
+	raw, err := client.Dispense(base.PluginTypeScheduler)
+	require.NoError(t, err)
+
+	sched, ok := raw.(SchedulerPlugin)
+	require.True(t, ok, "bad: %#v", raw)
+	return sched
+}
+
+func TestSchedulerPlugin_PluginInfo(t *testing.T) {
+	ci.Parallel(t)
+
+	mockPlugin := &MockSchedulerPlugin{
+		MockPlugin: &base.MockPlugin{
+			PluginInfoF: func() (*base.PluginInfoResponse, error) {
+				return &base.PluginInfoResponse{
+					Type:              base.PluginTypeScheduler,
+					PluginApiVersions: []string{ApiVersion010},
+					PluginVersion:     "v0.1.0",
+					Name:              "mock_scheduler",
+				}, nil
+			},
+		},
+	}
+	impl := testSchedulerPlugin(t, mockPlugin)
+
+	resp, err := impl.PluginInfo()
+	require.NoError(t, err)
+	require.Equal(t, base.PluginTypeScheduler, resp.Type)
+	require.Equal(t, []string{ApiVersion010}, resp.PluginApiVersions)
+	require.Equal(t, "mock_scheduler", resp.Name)
+}
+
+func TestSchedulerPlugin_Process(t *testing.T) {
+	ci.Parallel(t)
+
+	job := mock.Job()
+	node := mock.Node()
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	state := &testStateView{
+		nodes:  []*structs.Node{node},
+		job:    job,
+		allocs: []*structs.Allocation{alloc},
+	}
+
+	eval := mock.Eval()
+	eval.JobID = job.ID
+
+	mockPlugin := &MockSchedulerPlugin{
+		MockPlugin: &base.MockPlugin{},
+		ProcessF: func(e *structs.Evaluation, s StateView) (*structs.Plan, error) {
+			require.Equal(t, eval.ID, e.ID)
+
+			// Every method of the state view is served to the plugin
+			nodes, err := s.Nodes()
+			require.NoError(t, err)
+			require.Len(t, nodes, 1)
+			require.Equal(t, node.ID, nodes[0].ID)
+
+			out, err := s.NodeByID(node.ID)
+			require.NoError(t, err)
+			require.Equal(t, node.NodeResources, out.NodeResources)
+
+			missing, err := s.NodeByID("missing")
+			require.NoError(t, err)
+			require.Nil(t, missing)
+
+			j, err := s.JobByID(e.Namespace, e.JobID)
+			require.NoError(t, err)
+			require.Equal(t, job.TaskGroups[0].Count, j.TaskGroups[0].Count)
+
+			allocs, err := s.AllocsByJob(e.Namespace, e.JobID, false)
+			require.NoError(t, err)
+			require.Len(t, allocs, 1)
+
+			_, err = s.AllocsByNode(node.ID)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "not found")
+
+			config, err := s.SchedulerConfig()
+			require.NoError(t, err)
+			require.True(t, config.MemoryOversubscriptionEnabled)
+
+			plan := &structs.Plan{
+				EvalID:          e.ID,
+				NodeUpdate:      map[string][]*structs.Allocation{},
+				NodeAllocation:  map[string][]*structs.Allocation{node.ID: {alloc}},
+				NodePreemptions: map[string][]*structs.Allocation{},
+			}
+			return plan, nil
+		},
+	}
+	impl := testSchedulerPlugin(t, mockPlugin)
+
+	plan, err := impl.Process(eval, state)
+	require.NoError(t, err)
+	require.Equal(t, eval.ID, plan.EvalID)
+	require.Len(t, plan.NodeAllocation[node.ID], 1)
+	require.Equal(t, alloc.ID, plan.NodeAllocation[node.ID][0].ID)
+
+	// Errors of the plugin are returned
+	mockPlugin.ProcessF = func(*structs.Evaluation, StateView) (*structs.Plan, error) {
+		return nil, fmt.Errorf("no capacity")
+	}
+	_, err = impl.Process(eval, state)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no capacity")
+}
@@ -0,0 +1,44 @@
+package scheduler
+
+import (
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/base"
+)
+
+// SchedulerPlugin is the interface for a plugin that can process evaluations
+// in place of the built-in schedulers. Jobs opt into a scheduler plugin by
+// setting their scheduler field to the name of the plugin.
+type SchedulerPlugin interface {
+	base.BasePlugin
+
+	// Process is called to process an evaluation against the given view of
+	// the state. The returned plan is submitted by Nomad on behalf of the
+	// plugin and goes through the same plan apply path as the plans of the
+	// built-in schedulers, so it may only be partially committed. In that
+	// case Process is called again with a refreshed view of the state.
+	Process(eval *structs.Evaluation, state StateView) (*structs.Plan, error)
+}
+
+// StateView is a read-only view of the state snapshot an evaluation is
+// processed against.
+type StateView interface {
+	// Nodes returns all the nodes.
+	Nodes() ([]*structs.Node, error)
+
+	// NodeByID returns the node with the given ID or nil if it does not
+	// exist.
+	NodeByID(nodeID string) (*structs.Node, error)
+
+	// JobByID returns the job with the given ID or nil if it does not exist.
+	JobByID(namespace, jobID string) (*structs.Job, error)
+
+	// AllocsByJob returns the allocations of the job. If all is false, only
+	// the allocations of the current instance of the job are returned.
+	AllocsByJob(namespace, jobID string, all bool) ([]*structs.Allocation, error)
+
+	// AllocsByNode returns the allocations of the node.
+	AllocsByNode(nodeID string) ([]*structs.Allocation, error)
+
+	// SchedulerConfig returns the scheduler configuration of the cluster.
+	SchedulerConfig() (*structs.SchedulerConfiguration, error)
+}
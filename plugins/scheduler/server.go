@@ -0,0 +1,80 @@
+package scheduler
+
+import (
+	"fmt"
+
+	plugin "github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/scheduler/proto"
+	context "golang.org/x/net/context"
+)
+
+// schedulerPluginServer wraps a scheduler plugin and exposes it via gRPC.
+type schedulerPluginServer struct {
+	broker *plugin.GRPCBroker
+	impl   SchedulerPlugin
+}
+
+func (s *schedulerPluginServer) Process(ctx context.Context, req *proto.ProcessRequest) (*proto.ProcessResponse, error) {
+	var eval structs.Evaluation
+	if err := decode(req.GetEvaluation(), &eval); err != nil {
+		return nil, fmt.Errorf("failed to decode evaluation: %v", err)
+	}
+
+	conn, err := s.broker.Dial(req.GetStateServer())
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial state view: %v", err)
+	}
+	defer conn.Close()
+
+	state := &stateViewClient{
+		client: proto.NewStateViewClient(conn),
+		ctx:    ctx,
+	}
+	plan, err := s.impl.Process(&eval, state)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := encode(plan)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode plan: %v", err)
+	}
+	return &proto.ProcessResponse{Plan: out}, nil
+}
+
+// stateViewServer wraps the state view of an evaluation and exposes it to the
+// plugin processing it via gRPC.
+type stateViewServer struct {
+	impl StateView
+}
+
+func (s *stateViewServer) Query(ctx context.Context, req *proto.StateQueryRequest) (*proto.StateQueryResponse, error) {
+	var result interface{}
+	var err error
+	switch req.GetMethod() {
+	case stateMethodNodes:
+		result, err = s.impl.Nodes()
+	case stateMethodNodeByID:
+		result, err = s.impl.NodeByID(req.GetId())
+	case stateMethodJobByID:
+		result, err = s.impl.JobByID(req.GetNamespace(), req.GetId())
+	case stateMethodAllocsByJob:
+		result, err = s.impl.AllocsByJob(req.GetNamespace(), req.GetId(), req.GetAll())
+	case stateMethodAllocsByNode:
+		result, err = s.impl.AllocsByNode(req.GetId())
+	case stateMethodSchedulerConfig:
+		result, err = s.impl.SchedulerConfig()
+	default:
+		return nil, fmt.Errorf("unknown state view method %q", req.GetMethod())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := encode(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s result: %v", req.GetMethod(), err)
+	}
+	return &proto.StateQueryResponse{Result: out}, nil
+}
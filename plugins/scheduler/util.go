@@ -0,0 +1,30 @@
+package scheduler
+
+import (
+	"github.com/hashicorp/go-msgpack/codec"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// The names of the StateView methods which can be queried
+	stateMethodNodes           = "Nodes"
+	stateMethodNodeByID        = "NodeByID"
+	stateMethodJobByID         = "JobByID"
+	stateMethodAllocsByJob     = "AllocsByJob"
+	stateMethodAllocsByNode    = "AllocsByNode"
+	stateMethodSchedulerConfig = "SchedulerConfig"
+)
+
+// encode msgpack encodes the Nomad structs exchanged with scheduler plugins.
+// The same handle as the Raft log is used so that every field survives the
+// round trip.
+func encode(in interface{}) ([]byte, error) {
+	var buf []byte
+	err := codec.NewEncoderBytes(&buf, structs.MsgpackHandle).Encode(in)
+	return buf, err
+}
+
+// decode decodes a msgpack encoded Nomad struct.
+func decode(buf []byte, out interface{}) error {
+	return structs.Decode(buf, out)
+}
@@ -0,0 +1,6 @@
+package scheduler
+
+const (
+	// ApiVersion010 is the initial API version for the scheduler plugins
+	ApiVersion010 = "v0.1.0"
+)
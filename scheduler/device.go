@@ -30,6 +30,13 @@ func newDeviceAllocator(ctx Context, n *structs.Node) *deviceAllocator {
 // score for the assignment. If no assignment could be made, an error is
 // returned explaining why.
 func (d *deviceAllocator) AssignDevice(ask *structs.RequestedDevice) (out *structs.AllocatedDeviceResource, score float64, err error) {
+	return d.AssignDeviceOnNUMANode(ask, nil)
+}
+
+// AssignDeviceOnNUMANode is like AssignDevice but only assigns device
+// instances attached to the given NUMA node, if set. Instances whose NUMA node
+// is unknown are considered to be attached to every NUMA node.
+func (d *deviceAllocator) AssignDeviceOnNUMANode(ask *structs.RequestedDevice, numaNode *structs.NodeNUMANode) (out *structs.AllocatedDeviceResource, score float64, err error) {
 	// Try to hot path
 	if len(d.Devices) == 0 {
 		return nil, 0.0, fmt.Errorf("no devices available")
@@ -48,8 +55,8 @@ func (d *deviceAllocator) AssignDevice(ask *structs.RequestedDevice) (out *struc
 	for id, devInst := range d.Devices {
 		// Check if we have enough unused instances to use this
 		assignable := uint64(0)
-		for _, v := range devInst.Instances {
-			if v == 0 {
+		for id, v := range devInst.Instances {
+			if v == 0 && numaLocal(devInst.Device, id, numaNode) {
 				assignable++
 			}
 		}
@@ -112,7 +119,7 @@ func (d *deviceAllocator) AssignDevice(ask *structs.RequestedDevice) (out *struc
 
 		assigned := uint64(0)
 		for id, v := range devInst.Instances {
-			if v == 0 && assigned < ask.Count {
+			if v == 0 && assigned < ask.Count && numaLocal(devInst.Device, id, numaNode) {
 				assigned++
 				offer.DeviceIDs = append(offer.DeviceIDs, id)
 				if assigned == ask.Count {
@@ -129,3 +136,22 @@ func (d *deviceAllocator) AssignDevice(ask *structs.RequestedDevice) (out *struc
 
 	return offer, matchedWeights, nil
 }
+
+// numaLocal returns whether the device instance is attached to the NUMA node.
+// Every instance is local when no NUMA node is given, as are the instances
+// whose NUMA node is unknown.
+func numaLocal(device *structs.NodeDeviceResource, instanceID string, numaNode *structs.NodeNUMANode) bool {
+	if numaNode == nil {
+		return true
+	}
+	for _, instance := range device.Instances {
+		if instance.ID != instanceID {
+			continue
+		}
+		if instance.Locality == nil || instance.Locality.NUMANode == nil {
+			return true
+		}
+		return *instance.Locality.NUMANode == numaNode.ID
+	}
+	return true
+}
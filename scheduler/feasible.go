@@ -27,6 +27,7 @@ const (
 	FilterConstraintCSIVolumeGCdAllocationTemplate = "CSI volume %s has exhausted its available writer claims and is claimed by a garbage collected allocation %s; waiting for claim to be released"
 	FilterConstraintDrivers                        = "missing drivers"
 	FilterConstraintDevices                        = "missing devices"
+	FilterConstraintNodePool                       = "node pool mismatch"
 	FilterConstraintsCSIPluginTopology             = "did not meet topology requirement"
 )
 
@@ -520,6 +521,49 @@ func (c *DriverChecker) hasDrivers(option *structs.Node) bool {
 	return true
 }
 
+// NodePoolChecker is a FeasibilityChecker which returns whether a node
+// belongs to the node pool targeted by a job.
+type NodePoolChecker struct {
+	ctx  Context
+	pool string
+}
+
+// NewNodePoolChecker creates a NodePoolChecker for the given node pool.
+func NewNodePoolChecker(ctx Context, pool string) *NodePoolChecker {
+	return &NodePoolChecker{
+		ctx:  ctx,
+		pool: pool,
+	}
+}
+
+func (c *NodePoolChecker) SetNodePool(pool string) {
+	c.pool = pool
+}
+
+func (c *NodePoolChecker) Feasible(option *structs.Node) bool {
+	if c.inNodePool(option) {
+		return true
+	}
+	c.ctx.Metrics().FilterNode(option, FilterConstraintNodePool)
+	return false
+}
+
+// inNodePool returns whether the node belongs to the node pool. Jobs
+// registered before node pools were introduced do not have a node pool and,
+// like jobs targeting the built-in "all" node pool, may be placed on any node.
+func (c *NodePoolChecker) inNodePool(option *structs.Node) bool {
+	switch c.pool {
+	case "", structs.NodePoolAll:
+		return true
+	}
+
+	pool := option.NodePool
+	if pool == "" {
+		pool = structs.NodePoolDefault
+	}
+	return pool == c.pool
+}
+
 // DistinctHostsIterator is a FeasibleIterator which returns nodes that pass the
 // distinct_hosts constraint. The constraint ensures that multiple allocations
 // do not exist on the same node.
@@ -851,6 +851,52 @@ func Test_HealthChecks(t *testing.T) {
 	}
 }
 
+func TestNodePoolChecker(t *testing.T) {
+	ci.Parallel(t)
+
+	_, ctx := testContext(t)
+	nodes := []*structs.Node{
+		mock.Node(),
+		mock.Node(),
+		mock.Node(),
+	}
+	nodes[1].NodePool = "gpu"
+	nodes[2].NodePool = ""
+
+	cases := []struct {
+		Pool    string
+		Results []bool
+	}{
+		{
+			Pool:    structs.NodePoolDefault,
+			Results: []bool{true, false, true},
+		},
+		{
+			Pool:    "gpu",
+			Results: []bool{false, true, false},
+		},
+		{
+			Pool:    structs.NodePoolAll,
+			Results: []bool{true, true, true},
+		},
+		{
+			// Jobs without a node pool may be placed on any node
+			Pool:    "",
+			Results: []bool{true, true, true},
+		},
+	}
+
+	checker := NewNodePoolChecker(ctx, "")
+	for _, c := range cases {
+		checker.SetNodePool(c.Pool)
+		for i, node := range nodes {
+			if act := checker.Feasible(node); act != c.Results[i] {
+				t.Fatalf("pool %q node %d failed: got %v; want %v", c.Pool, i, act, c.Results[i])
+			}
+		}
+	}
+}
+
 func TestConstraintChecker(t *testing.T) {
 	ci.Parallel(t)
 
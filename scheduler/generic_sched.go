@@ -164,7 +164,8 @@ func (s *GenericScheduler) Process(eval *structs.Evaluation) (err error) {
 		structs.EvalTriggerPeriodicJob, structs.EvalTriggerMaxPlans,
 		structs.EvalTriggerDeploymentWatcher, structs.EvalTriggerRetryFailedAlloc,
 		structs.EvalTriggerFailedFollowUp, structs.EvalTriggerPreemption,
-		structs.EvalTriggerScaling, structs.EvalTriggerMaxDisconnectTimeout, structs.EvalTriggerReconnect:
+		structs.EvalTriggerScaling, structs.EvalTriggerMaxDisconnectTimeout, structs.EvalTriggerReconnect,
+		structs.EvalTriggerRebalance, structs.EvalTriggerDependency:
 	default:
 		desc := fmt.Sprintf("scheduler cannot handle '%s' evaluation reason",
 			eval.TriggeredBy)
@@ -442,21 +443,85 @@ func (s *GenericScheduler) computeJobAllocs() error {
 		return nil
 	}
 
+	// Task groups whose dependencies are not satisfied yet remain queued
+	// until an allocation of a task group they depend on is ready
+	held := heldTaskGroups(s.job, s.deployment, allocs)
+	for name := range held {
+		s.logger.Debug("holding placements until task group dependencies are satisfied", "task_group", name)
+	}
+
 	// Compute the placements
 	place := make([]placementResult, 0, len(results.place))
 	for _, p := range results.place {
 		s.queuedAllocs[p.taskGroup.Name] += 1
+		if _, ok := held[p.taskGroup.Name]; ok {
+			continue
+		}
 		place = append(place, p)
 	}
 
 	destructive := make([]placementResult, 0, len(results.destructiveUpdate))
 	for _, p := range results.destructiveUpdate {
 		s.queuedAllocs[p.placeTaskGroup.Name] += 1
+		if _, ok := held[p.placeTaskGroup.Name]; ok {
+			continue
+		}
 		destructive = append(destructive, p)
 	}
 	return s.computePlacements(destructive, place)
 }
 
+// heldTaskGroups returns the task groups of the job which depend on task
+// groups whose allocations do not satisfy the dependency condition yet. A
+// task group is satisfied once as many of its allocations as its count meet
+// the condition. While a deployment is rolling out a task group, only the
+// allocations of that deployment are considered.
+func heldTaskGroups(job *structs.Job, deployment *structs.Deployment, allocs []*structs.Allocation) map[string]struct{} {
+	if job == nil {
+		return nil
+	}
+
+	held := make(map[string]struct{})
+	for _, tg := range job.TaskGroups {
+		condition := tg.DependencyCondition()
+		for _, name := range tg.DependsOn {
+			dep := job.LookupTaskGroup(name)
+			if dep == nil {
+				continue
+			}
+
+			var deploymentID string
+			if deployment != nil && deployment.Active() {
+				if _, ok := deployment.TaskGroups[name]; ok {
+					deploymentID = deployment.ID
+				}
+			}
+
+			ready := 0
+			for _, alloc := range allocs {
+				if alloc.TaskGroup != name {
+					continue
+				}
+				if deploymentID != "" && alloc.DeploymentID != deploymentID {
+					continue
+				}
+				if condition != structs.DependencyConditionComplete && alloc.TerminalStatus() {
+					continue
+				}
+				if alloc.SatisfiesDependency(condition) {
+					ready++
+				}
+			}
+
+			if ready < dep.Count {
+				held[tg.Name] = struct{}{}
+				break
+			}
+		}
+	}
+	return held
+}
+
 // downgradedJobForPlacement returns the job appropriate for non-canary placement replacement
 func (s *GenericScheduler) downgradedJobForPlacement(p placementResult) (string, *structs.Job, error) {
 	ns, jobID := s.job.Namespace, s.job.ID
@@ -514,6 +579,10 @@ func (s *GenericScheduler) computePlacements(destructive, place []placementResul
 	// Capture current time to use as the start time for any rescheduled allocations
 	now := time.Now()
 
+	// Track the placements of the task groups which must be placed all at once
+	// so they can be undone if any allocation of the group failed to place.
+	var allOrNothing map[string][]allOrNothingPlacement
+
 	// Have to handle destructive changes first as we need to discount their
 	// resources. To understand this imagine the resources were reduced and the
 	// count was scaled up.
@@ -653,6 +722,17 @@ func (s *GenericScheduler) computePlacements(destructive, place []placementResul
 				// Track the placement
 				s.plan.AppendAlloc(alloc, downgradedJob)
 
+				if tg.IsAllOrNothing() {
+					if allOrNothing == nil {
+						allOrNothing = make(map[string][]allOrNothingPlacement)
+					}
+					placement := allOrNothingPlacement{alloc: alloc}
+					if stopPrevAlloc {
+						placement.stoppedPrev = prevAllocation
+					}
+					allOrNothing[tg.Name] = append(allOrNothing[tg.Name], placement)
+				}
+
 			} else {
 				// Lazy initialize the failed map
 				if s.failedTGAllocs == nil {
@@ -675,9 +755,74 @@ func (s *GenericScheduler) computePlacements(destructive, place []placementResul
 		}
 	}
 
+	s.undoPartialPlacements(allOrNothing)
+
+	if s.eval.AnnotatePlan && s.plan.Annotations != nil {
+		s.plan.Annotations.SpreadViolations = spreadViolations(s.ctx, s.job, nodes)
+	}
 	return nil
 }
 
+// allOrNothingPlacement is a placement of a task group which must be placed
+// all at once.
+type allOrNothingPlacement struct {
+	alloc *structs.Allocation
+
+	// stoppedPrev is the previous allocation stopped by the placement, if
+	// any.
+	stoppedPrev *structs.Allocation
+}
+
+// undoPartialPlacements removes the placements of the task groups which must
+// be placed all at once but failed to place some of their allocations. The
+// undone placements are counted as failed so the blocked evaluation retries
+// the whole task group.
+func (s *GenericScheduler) undoPartialPlacements(placements map[string][]allOrNothingPlacement) {
+	for tgName, tgPlacements := range placements {
+		metric, failed := s.failedTGAllocs[tgName]
+		if !failed {
+			continue
+		}
+
+		for _, p := range tgPlacements {
+			s.plan.RemoveAlloc(p.alloc)
+			if p.stoppedPrev != nil {
+				s.plan.RemoveUpdate(p.stoppedPrev)
+			}
+			s.removePreemptionAnnotations(tgName, p.alloc)
+			metric.CoalescedFailures += 1
+		}
+
+		s.logger.Debug("failed to place all allocations of task group, placements undone",
+			"task_group", tgName, "undone", len(tgPlacements))
+	}
+}
+
+// removePreemptionAnnotations removes the plan annotations of the
+// preemptions required by an allocation whose placement was undone.
+func (s *GenericScheduler) removePreemptionAnnotations(tgName string, alloc *structs.Allocation) {
+	if len(alloc.PreemptedAllocations) == 0 || s.plan.Annotations == nil {
+		return
+	}
+
+	preempted := make(map[string]struct{}, len(alloc.PreemptedAllocations))
+	for _, id := range alloc.PreemptedAllocations {
+		preempted[id] = struct{}{}
+	}
+
+	stubs := s.plan.Annotations.PreemptedAllocs[:0]
+	for _, stub := range s.plan.Annotations.PreemptedAllocs {
+		if _, ok := preempted[stub.ID]; !ok {
+			stubs = append(stubs, stub)
+		}
+	}
+	s.plan.Annotations.PreemptedAllocs = stubs
+
+	if desired, ok := s.plan.Annotations.DesiredTGUpdates[tgName]; ok {
+		desired.Preemptions -= uint64(len(preempted))
+	}
+}
+
 // propagateTaskState copies task handles from previous allocations to
 // replacement allocations when the previous allocation is being drained or was
 // lost. Remote task drivers rely on this to reconnect to remote tasks when the
@@ -729,6 +874,13 @@ func getSelectOptions(prevAllocation *structs.Allocation, preferredNode *structs
 		if prevAllocation.ClientStatus == structs.AllocClientStatusFailed {
 			penaltyNodes[prevAllocation.NodeID] = struct{}{}
 		}
+
+		// If alloc is migrated, penalize the node it is migrated from so
+		// that allocations migrated to rebalance the cluster do not land
+		// back on it.
+		if prevAllocation.DesiredTransition.ShouldMigrate() {
+			penaltyNodes[prevAllocation.NodeID] = struct{}{}
+		}
 		if prevAllocation.RescheduleTracker != nil {
 			for _, reschedEvent := range prevAllocation.RescheduleTracker.Events {
 				penaltyNodes[reschedEvent.PrevNodeID] = struct{}{}
@@ -108,6 +108,83 @@ func TestServiceSched_JobRegister(t *testing.T) {
 	h.AssertEvalStatus(t, structs.EvalStatusComplete)
 }
 
+func TestServiceSched_JobRegister_DependsOn(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	// Create some nodes
+	for i := 0; i < 10; i++ {
+		node := mock.Node()
+		require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+	}
+
+	// Create a job whose web group depends on its db group
+	job := mock.Job()
+	db := job.TaskGroups[0].Copy()
+	db.Name = "db"
+	db.Count = 2
+	job.TaskGroups = append(job.TaskGroups, db)
+	web := job.TaskGroups[0]
+	web.DependsOn = []string{"db"}
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+	register := func(h *Harness, triggeredBy string) {
+		eval := &structs.Evaluation{
+			Namespace:   structs.DefaultNamespace,
+			ID:          uuid.Generate(),
+			Priority:    job.Priority,
+			TriggeredBy: triggeredBy,
+			JobID:       job.ID,
+			Status:      structs.EvalStatusPending,
+		}
+		require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+		require.NoError(t, h.Process(NewServiceScheduler, eval))
+		require.Len(t, h.Evals, 1)
+		require.Equal(t, structs.EvalStatusComplete, h.Evals[0].Status)
+	}
+
+	// Only the db group is placed while the web group remains queued
+	register(h, structs.EvalTriggerJobRegister)
+	require.Equal(t, web.Count, h.Evals[0].QueuedAllocations[web.Name])
+	require.Empty(t, h.CreateEvals)
+
+	allocs, err := h.State.AllocsByJob(nil, job.Namespace, job.ID, false)
+	require.NoError(t, err)
+	require.Len(t, allocs, db.Count)
+	for _, alloc := range allocs {
+		require.Equal(t, db.Name, alloc.TaskGroup)
+	}
+
+	// The web group is still held while a db allocation is pending
+	running := make([]*structs.Allocation, 0, len(allocs))
+	for _, alloc := range allocs {
+		alloc = alloc.Copy()
+		alloc.ClientStatus = structs.AllocClientStatusRunning
+		running = append(running, alloc)
+	}
+	require.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), running[:1]))
+
+	h = NewHarnessWithState(t, h.State)
+	register(h, structs.EvalTriggerDependency)
+	require.Equal(t, web.Count, h.Evals[0].QueuedAllocations[web.Name])
+
+	allocs, err = h.State.AllocsByJob(nil, job.Namespace, job.ID, false)
+	require.NoError(t, err)
+	require.Len(t, allocs, db.Count)
+
+	// The web group is placed once every db allocation is running
+	require.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), running[1:]))
+
+	h = NewHarnessWithState(t, h.State)
+	register(h, structs.EvalTriggerDependency)
+	require.Zero(t, h.Evals[0].QueuedAllocations[web.Name])
+
+	allocs, err = h.State.AllocsByJob(nil, job.Namespace, job.ID, false)
+	require.NoError(t, err)
+	require.Len(t, allocs, db.Count+web.Count)
+}
+
 func TestServiceSched_JobRegister_MemoryMaxHonored(t *testing.T) {
 	ci.Parallel(t)
 
@@ -4925,6 +5002,78 @@ func TestBatchSched_ScaleDown_SameName(t *testing.T) {
 	h.AssertEvalStatus(t, structs.EvalStatusComplete)
 }
 
+func TestBatchSched_Placement_AllOrNothing(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name      string
+		placement string
+		placed    int
+		coalesced int
+	}{
+		{
+			name:      "best effort places what fits",
+			placement: structs.TaskGroupPlacementBestEffort,
+			placed:    2,
+			coalesced: 0,
+		},
+		{
+			name:      "all or nothing places nothing",
+			placement: structs.TaskGroupPlacementAllOrNothing,
+			placed:    0,
+			coalesced: 2,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewHarness(t)
+
+			// Create two nodes which can each fit a single allocation
+			for i := 0; i < 2; i++ {
+				node := mock.Node()
+				require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+			}
+
+			// Create a job which needs three allocations
+			job := mock.BatchJob()
+			job.TaskGroups[0].Count = 3
+			job.TaskGroups[0].Placement = tc.placement
+			job.TaskGroups[0].Tasks[0].Resources.CPU = 3000
+			require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+			eval := &structs.Evaluation{
+				Namespace:   structs.DefaultNamespace,
+				ID:          uuid.Generate(),
+				Priority:    job.Priority,
+				TriggeredBy: structs.EvalTriggerJobRegister,
+				JobID:       job.ID,
+				Status:      structs.EvalStatusPending,
+			}
+			require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+
+			// Process the evaluation
+			require.NoError(t, h.Process(NewBatchScheduler, eval))
+
+			// Ensure the placed allocations
+			out, err := h.State.AllocsByJob(nil, job.Namespace, job.ID, false)
+			require.NoError(t, err)
+			require.Len(t, out, tc.placed)
+
+			// Ensure the failed allocations are retried by a blocked eval
+			require.Len(t, h.CreateEvals, 1)
+			require.Equal(t, structs.EvalStatusBlocked, h.CreateEvals[0].Status)
+
+			require.Len(t, h.Evals, 1)
+			metrics, ok := h.Evals[0].FailedTGAllocs[job.TaskGroups[0].Name]
+			require.True(t, ok)
+			require.Equal(t, tc.coalesced, metrics.CoalescedFailures)
+			require.Equal(t, 3-tc.placed, h.Evals[0].QueuedAllocations[job.TaskGroups[0].Name])
+			h.AssertEvalStatus(t, structs.EvalStatusComplete)
+		})
+	}
+}
+
 func TestGenericSched_AllocFit_Lifecycle(t *testing.T) {
 	ci.Parallel(t)
 
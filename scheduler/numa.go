@@ -0,0 +1,194 @@
+package scheduler
+
+import (
+	"sort"
+
+	"github.com/hashicorp/nomad/lib/cpuset"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// availableCores returns the set of reservable cores of the node which are
+// not reserved by the proposed allocations nor by the tasks already assigned
+// in total.
+func availableCores(node *structs.Node, proposed []*structs.Allocation, total *structs.AllocatedResources) cpuset.CPUSet {
+	// set of reservable CPUs for the node
+	nodeCPUSet := cpuset.New(node.NodeResources.Cpu.ReservableCpuCores...)
+	// set of all reserved CPUs on the node
+	allocatedCPUSet := cpuset.New()
+	for _, alloc := range proposed {
+		allocatedCPUSet = allocatedCPUSet.Union(cpuset.New(alloc.ComparableResources().Flattened.Cpu.ReservedCores...))
+	}
+
+	// add any cores that were reserved for other tasks
+	for _, tr := range total.Tasks {
+		allocatedCPUSet = allocatedCPUSet.Union(cpuset.New(tr.Cpu.ReservedCores...))
+	}
+
+	// set of CPUs not yet reserved on the node
+	return nodeCPUSet.Difference(allocatedCPUSet)
+}
+
+// numaMemoryUsage returns the memory reserved on each NUMA node by the tasks of
+// the proposed allocations and the tasks already assigned in total whose
+// memory is bound to NUMA nodes. The memory of a task bound to several NUMA
+// nodes is split evenly between them.
+func numaMemoryUsage(proposed []*structs.Allocation, total *structs.AllocatedResources) map[uint16]int64 {
+	used := make(map[uint16]int64)
+	add := func(tr *structs.AllocatedTaskResources) {
+		if tr == nil || len(tr.Cpu.NUMANodes) == 0 {
+			return
+		}
+		share := tr.Memory.MemoryMB / int64(len(tr.Cpu.NUMANodes))
+		for _, id := range tr.Cpu.NUMANodes {
+			used[id] += share
+		}
+	}
+
+	for _, alloc := range proposed {
+		if alloc.AllocatedResources == nil {
+			continue
+		}
+		for _, tr := range alloc.AllocatedResources.Tasks {
+			add(tr)
+		}
+	}
+	for _, tr := range total.Tasks {
+		add(tr)
+	}
+	return used
+}
+
+// numaMemoryFits returns whether the memory left on the given NUMA nodes, once
+// the used memory is subtracted, is enough for memoryMB. NUMA nodes whose
+// memory is unknown are assumed to fit.
+func numaMemoryFits(topology *structs.NodeNUMAResources, ids []uint16, used map[uint16]int64, memoryMB int64) bool {
+	if topology == nil {
+		return true
+	}
+
+	var available int64
+	for _, id := range ids {
+		for _, numaNode := range topology.Nodes {
+			if numaNode.ID != id {
+				continue
+			}
+			if numaNode.MemoryMB == 0 {
+				return true
+			}
+			available += numaNode.MemoryMB - used[id]
+		}
+	}
+	return available >= memoryMB
+}
+
+// selectNUMANode returns the NUMA node of the node which has enough available
+// cores, memory and devices for the task, or nil if there is none. When
+// several NUMA nodes fit, the one with the fewest available cores is used so
+// that larger NUMA nodes remain available for larger tasks.
+func selectNUMANode(node *structs.Node, available cpuset.CPUSet, used map[uint16]int64, resources *structs.Resources, devAllocator *deviceAllocator) *structs.NodeNUMANode {
+	topology := node.NodeResources.NUMA
+	if topology == nil {
+		return nil
+	}
+
+	var best *structs.NodeNUMANode
+	bestSize := 0
+NODES:
+	for _, numaNode := range topology.Nodes {
+		size := cpuset.New(numaNode.Cores...).Intersect(available).Size()
+		if size < resources.Cores {
+			continue
+		}
+
+		// The memory of the task is bound to the NUMA node
+		if !numaMemoryFits(topology, []uint16{numaNode.ID}, used, int64(resources.MemoryMB)) {
+			continue
+		}
+
+		for _, req := range resources.Devices {
+			if offer, _, _ := devAllocator.AssignDeviceOnNUMANode(req, numaNode); offer == nil {
+				continue NODES
+			}
+		}
+
+		if best == nil || size < bestSize {
+			best, bestSize = numaNode, size
+		}
+	}
+	return best
+}
+
+// selectNUMACores returns count cores from the available set along with the
+// NUMA nodes they belong to. If numaNode is set, the cores are all taken from
+// it. Otherwise they are taken from as few NUMA nodes as possible. Within a
+// NUMA node, cores whose hardware thread siblings are all available are used
+// first so that tasks do not share physical cores.
+func selectNUMACores(topology *structs.NodeNUMAResources, numaNode *structs.NodeNUMANode, available cpuset.CPUSet, count int) ([]uint16, []uint16) {
+	if topology == nil {
+		return available.ToSlice()[0:count], nil
+	}
+
+	var nodes []*structs.NodeNUMANode
+	if numaNode != nil {
+		nodes = []*structs.NodeNUMANode{numaNode}
+	} else {
+		// Start with the NUMA nodes with the most available cores
+		nodes = make([]*structs.NodeNUMANode, len(topology.Nodes))
+		copy(nodes, topology.Nodes)
+		availableOn := func(n *structs.NodeNUMANode) int {
+			return cpuset.New(n.Cores...).Intersect(available).Size()
+		}
+		sort.SliceStable(nodes, func(i, j int) bool {
+			return availableOn(nodes[i]) > availableOn(nodes[j])
+		})
+	}
+
+	cores := make([]uint16, 0, count)
+	var numaNodes []uint16
+	for _, n := range nodes {
+		if len(cores) == count {
+			break
+		}
+
+		picked := false
+		for _, core := range orderCoresBySiblings(n, available) {
+			if len(cores) == count {
+				break
+			}
+			cores = append(cores, core)
+			picked = true
+		}
+		if picked {
+			numaNodes = append(numaNodes, n.ID)
+		}
+	}
+
+	// Cores missing from the topology are used last
+	if len(cores) < count {
+		remaining := available.Difference(cpuset.New(cores...)).ToSlice()
+		cores = append(cores, remaining[0:count-len(cores)]...)
+	}
+
+	return cpuset.New(cores...).ToSlice(), cpuset.New(numaNodes...).ToSlice()
+}
+
+// orderCoresBySiblings returns the available cores of the NUMA node, starting
+// with the cores of the physical cores whose threads are all available.
+func orderCoresBySiblings(numaNode *structs.NodeNUMANode, available cpuset.CPUSet) []uint16 {
+	var whole, partial []uint16
+	seen := cpuset.New()
+	for _, siblings := range numaNode.CoreSiblings {
+		set := cpuset.New(siblings...)
+		seen = seen.Union(set)
+		if set.IsSubsetOf(available) {
+			whole = append(whole, set.ToSlice()...)
+		} else {
+			partial = append(partial, set.Intersect(available).ToSlice()...)
+		}
+	}
+
+	// Cores without sibling information
+	partial = append(partial, cpuset.New(numaNode.Cores...).Difference(seen).Intersect(available).ToSlice()...)
+
+	return append(whole, partial...)
+}
@@ -0,0 +1,219 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// PlanPreemptionsOverBudget returns the nodes of the plan whose
+// preemptions would exceed the disruption budget of the task groups of the
+// preempted allocations, along with the reason. Nodes are evaluated in order
+// so that the preemptions of the earlier nodes take up the budgets first.
+func PlanPreemptionsOverBudget(state State, plan *structs.Plan) (map[string]string, error) {
+	if len(plan.NodePreemptions) == 0 {
+		return nil, nil
+	}
+
+	nodeIDs := make([]string, 0, len(plan.NodePreemptions))
+	for nodeID := range plan.NodePreemptions {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	sort.Strings(nodeIDs)
+
+	// budgets tracks the remaining disruption budget of each job/taskgroup
+	budgets := make(map[structs.NamespacedID]map[string]int)
+	budgetFor := func(alloc *structs.Allocation, tg *structs.TaskGroup) (int, error) {
+		id := structs.NewNamespacedID(alloc.JobID, alloc.Namespace)
+		if budget, ok := budgets[id][tg.Name]; ok {
+			return budget, nil
+		}
+
+		allocs, err := state.AllocsByJob(nil, alloc.Namespace, alloc.JobID, false)
+		if err != nil {
+			return 0, err
+		}
+		live := 0
+		for _, a := range allocs {
+			if a.TaskGroup == tg.Name && !a.TerminalStatus() {
+				live++
+			}
+		}
+
+		if budgets[id] == nil {
+			budgets[id] = make(map[string]int)
+		}
+		budgets[id][tg.Name] = tg.PreemptionBudget(live)
+		return budgets[id][tg.Name], nil
+	}
+
+	overBudget := make(map[string]string)
+	for _, nodeID := range nodeIDs {
+		used := make(map[structs.NamespacedID]map[string]int)
+		var reason string
+		for _, preempted := range plan.NodePreemptions[nodeID] {
+			alloc, err := state.AllocByID(nil, preempted.ID)
+			if err != nil {
+				return nil, err
+			}
+
+			// Terminal allocations are not preempted by the plan
+			if alloc == nil || alloc.TerminalStatus() || alloc.Job == nil {
+				continue
+			}
+			tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+			if tg == nil || tg.Preemption == nil {
+				continue
+			}
+
+			budget, err := budgetFor(alloc, tg)
+			if err != nil {
+				return nil, err
+			}
+			if budget < 0 {
+				continue
+			}
+
+			id := structs.NewNamespacedID(alloc.JobID, alloc.Namespace)
+			if used[id] == nil {
+				used[id] = make(map[string]int)
+			}
+			used[id][tg.Name]++
+			if used[id][tg.Name] > budget {
+				reason = fmt.Sprintf("preemption exceeds the disruption budget of group %q of job %q", tg.Name, alloc.JobID)
+				break
+			}
+		}
+
+		if reason != "" {
+			overBudget[nodeID] = reason
+			continue
+		}
+
+		// The node fits, so its preemptions take up the budgets
+		for id, counts := range used {
+			for tg, count := range counts {
+				budgets[id][tg] -= count
+			}
+		}
+	}
+	return overBudget, nil
+}
+
+// CorrectAllOrNothingPlacements removes from the result the new placements of
+// the task groups which must be placed all at once, if any of their
+// placements were rejected. The stops and preemptions of the removed
+// placements are removed as well.
+func CorrectAllOrNothingPlacements(plan *structs.Plan, result *structs.PlanResult, rejectedNodes []string) {
+	// Hot path
+	if plan.Job == nil || len(rejectedNodes) == 0 || result.NodeAllocation == nil {
+		return
+	}
+
+	// Find the task groups with rejected placements. New placements do not
+	// have a create index yet.
+	rejectedGroups := make(map[string]struct{})
+	for _, nodeID := range rejectedNodes {
+		for _, alloc := range plan.NodeAllocation[nodeID] {
+			if alloc.CreateIndex != 0 {
+				continue
+			}
+			if tg := plan.Job.LookupTaskGroup(alloc.TaskGroup); tg != nil && tg.IsAllOrNothing() {
+				rejectedGroups[alloc.TaskGroup] = struct{}{}
+			}
+		}
+	}
+	if len(rejectedGroups) == 0 {
+		return
+	}
+
+	// Remove the placements of these task groups, along with the previous
+	// allocations they were replacing and the allocations they preempted
+	removed := make(map[string]struct{})
+	stoppedPrev := make(map[string]struct{})
+	for nodeID, allocs := range result.NodeAllocation {
+		kept := make([]*structs.Allocation, 0, len(allocs))
+		for _, alloc := range allocs {
+			if _, ok := rejectedGroups[alloc.TaskGroup]; ok && alloc.CreateIndex == 0 {
+				removed[alloc.ID] = struct{}{}
+				if alloc.PreviousAllocation != "" {
+					stoppedPrev[alloc.PreviousAllocation] = struct{}{}
+				}
+				continue
+			}
+			kept = append(kept, alloc)
+		}
+		if len(kept) > 0 {
+			result.NodeAllocation[nodeID] = kept
+		} else {
+			delete(result.NodeAllocation, nodeID)
+		}
+	}
+
+	for nodeID, allocs := range result.NodeUpdate {
+		kept := make([]*structs.Allocation, 0, len(allocs))
+		for _, alloc := range allocs {
+			if _, ok := stoppedPrev[alloc.ID]; !ok {
+				kept = append(kept, alloc)
+			}
+		}
+		if len(kept) > 0 {
+			result.NodeUpdate[nodeID] = kept
+		} else {
+			delete(result.NodeUpdate, nodeID)
+		}
+	}
+
+	for nodeID, allocs := range result.NodePreemptions {
+		kept := make([]*structs.Allocation, 0, len(allocs))
+		for _, alloc := range allocs {
+			if _, ok := removed[alloc.PreemptedByAllocation]; !ok {
+				kept = append(kept, alloc)
+			}
+		}
+		if len(kept) > 0 {
+			result.NodePreemptions[nodeID] = kept
+		} else {
+			delete(result.NodePreemptions, nodeID)
+		}
+	}
+}
+
+// CorrectDeploymentCanaries ensures that the deployment object doesn't list any
+// canaries as placed if they didn't actually get placed. This could happen if
+// the plan had a partial commit.
+func CorrectDeploymentCanaries(result *structs.PlanResult) {
+	// Hot path
+	if result.Deployment == nil || !result.Deployment.HasPlacedCanaries() {
+		return
+	}
+
+	// Build a set of all the allocations IDs that were placed
+	placedAllocs := make(map[string]struct{}, len(result.NodeAllocation))
+	for _, placed := range result.NodeAllocation {
+		for _, alloc := range placed {
+			placedAllocs[alloc.ID] = struct{}{}
+		}
+	}
+
+	// Go through all the canaries and ensure that the result list only contains
+	// those that have been placed
+	for _, group := range result.Deployment.TaskGroups {
+		canaries := group.PlacedCanaries
+		if len(canaries) == 0 {
+			continue
+		}
+
+		// Prune the canaries in place to avoid allocating an extra slice
+		i := 0
+		for _, canaryID := range canaries {
+			if _, ok := placedAllocs[canaryID]; ok {
+				canaries[i] = canaryID
+				i++
+			}
+		}
+
+		group.PlacedCanaries = canaries[:i]
+	}
+}
@@ -12,6 +12,27 @@ import (
 // number of allocations being preempted exceeds max_parallel value in the job's migrate stanza
 const maxParallelPenalty = 50.0
 
+const (
+	// preemptionRejectedPriority is the reason recorded when allocations
+	// could not be preempted because their priority is too close to the
+	// priority of the job being placed
+	preemptionRejectedPriority = "priority too close"
+
+	// preemptionRejectedNotPreemptible is the reason recorded when
+	// allocations could not be preempted because their task group is not
+	// preemptible
+	preemptionRejectedNotPreemptible = "task group not preemptible"
+
+	// preemptionRejectedBudget is the reason recorded when allocations could
+	// not be preempted because the disruption budget of their task group is
+	// exhausted
+	preemptionRejectedBudget = "disruption budget exhausted"
+
+	// preemptionRejectedResources is the reason recorded when preempting the
+	// eligible allocations would not free enough resources
+	preemptionRejectedResources = "not enough resources"
+)
+
 type groupedAllocs struct {
 	priority int
 	allocs   []*structs.Allocation
@@ -117,6 +138,13 @@ type Preemptor struct {
 	// currentAllocs is the candidate set used to find preemptible allocations
 	currentAllocs []*structs.Allocation
 
+	// liveAllocs caches the number of non-terminal allocations per
+	// job/taskgroup, used to compute the disruption budget of task groups
+	liveAllocs map[structs.NamespacedID]map[string]int
+
+	// rejected tracks the reasons why allocations could not be preempted
+	rejected map[string]struct{}
+
 	// ctx is the context from the scheduler stack
 	ctx Context
 }
@@ -127,6 +155,8 @@ func NewPreemptor(jobPriority int, ctx Context, jobID *structs.NamespacedID) *Pr
 		jobPriority:        jobPriority,
 		jobID:              jobID,
 		allocDetails:       make(map[string]*allocInfo),
+		liveAllocs:         make(map[structs.NamespacedID]map[string]int),
+		rejected:           make(map[string]struct{}),
 		ctx:                ctx,
 	}
 }
@@ -171,6 +201,12 @@ func (p *Preemptor) SetPreemptions(allocs []*structs.Allocation) {
 	p.currentPreemptions = make(map[structs.NamespacedID]map[string]int)
 
 	// Initialize counts
+	p.addPreemptions(allocs)
+}
+
+// addPreemptions adds the allocations to the counts of preempted allocations
+// per job/task group.
+func (p *Preemptor) addPreemptions(allocs []*structs.Allocation) {
 	for _, alloc := range allocs {
 		id := structs.NewNamespacedID(alloc.JobID, alloc.Namespace)
 		countMap, ok := p.currentPreemptions[id]
@@ -182,6 +218,117 @@ func (p *Preemptor) SetPreemptions(allocs []*structs.Allocation) {
 	}
 }
 
+// RejectionReasons returns the reasons why allocations could not be
+// preempted. If no allocation was rejected, preempting the eligible
+// allocations did not free enough resources.
+func (p *Preemptor) RejectionReasons() []string {
+	if len(p.rejected) == 0 {
+		return []string{preemptionRejectedResources}
+	}
+	reasons := make([]string, 0, len(p.rejected))
+	for reason := range p.rejected {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	return reasons
+}
+
+// preemptible returns whether the allocation may be preempted by the job
+// being placed, recording the reason if it may not.
+func (p *Preemptor) preemptible(alloc *structs.Allocation) bool {
+	// Skip allocs whose priority is within a delta of 10
+	// This also skips any allocs of the current job
+	// for which we are attempting preemption
+	if p.jobPriority-alloc.Job.Priority < 10 {
+		p.rejected[preemptionRejectedPriority] = struct{}{}
+		return false
+	}
+
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	if tg != nil && tg.Preemption != nil && !tg.Preemption.Preemptible {
+		p.rejected[preemptionRejectedNotPreemptible] = struct{}{}
+		return false
+	}
+
+	if p.disruptionBudget(alloc) == 0 {
+		p.rejected[preemptionRejectedBudget] = struct{}{}
+		return false
+	}
+	return true
+}
+
+// withinBudget returns whether the allocation may be preempted along with the
+// allocations already chosen for preemption without exceeding the disruption
+// budget of its task group.
+func (p *Preemptor) withinBudget(alloc *structs.Allocation, chosen []*structs.Allocation) bool {
+	budget := p.disruptionBudget(alloc)
+	if budget < 0 {
+		return true
+	}
+
+	for _, other := range chosen {
+		if other.JobID == alloc.JobID && other.Namespace == alloc.Namespace && other.TaskGroup == alloc.TaskGroup {
+			budget--
+		}
+	}
+	if budget <= 0 {
+		p.rejected[preemptionRejectedBudget] = struct{}{}
+		return false
+	}
+	return true
+}
+
+// disruptionBudget returns the number of allocations of the task group of the
+// alloc which may still be preempted, accounting for the allocations already
+// preempted in the plan. It returns -1 if the preemption of the task group is
+// not limited.
+func (p *Preemptor) disruptionBudget(alloc *structs.Allocation) int {
+	if alloc.Job == nil {
+		return -1
+	}
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	if tg == nil || tg.Preemption == nil {
+		return -1
+	}
+
+	budget := tg.PreemptionBudget(p.getNumLiveAllocs(alloc))
+	if budget < 0 {
+		return budget
+	}
+	budget -= p.getNumPreemptions(alloc)
+	if budget < 0 {
+		return 0
+	}
+	return budget
+}
+
+// getNumLiveAllocs returns the number of non-terminal allocations of the job
+// and task group of the alloc, which are used to determine how many of them
+// have been disrupted.
+func (p *Preemptor) getNumLiveAllocs(alloc *structs.Allocation) int {
+	id := structs.NewNamespacedID(alloc.JobID, alloc.Namespace)
+	if counts, ok := p.liveAllocs[id]; ok {
+		return counts[alloc.TaskGroup]
+	}
+
+	counts := make(map[string]int)
+	p.liveAllocs[id] = counts
+
+	allocs, err := p.ctx.State().AllocsByJob(nil, alloc.Namespace, alloc.JobID, false)
+	if err != nil {
+		// Without the allocations of the job, count all of them as
+		// disrupted so that none is preempted
+		p.ctx.Logger().Named("preemption").Error("failed to lookup allocations of job", "job_id", alloc.JobID, "namespace", alloc.Namespace, "error", err)
+		return 0
+	}
+	for _, a := range allocs {
+		if !a.TerminalStatus() {
+			counts[a.TaskGroup]++
+		}
+	}
+	return counts[alloc.TaskGroup]
+}
+
 // getNumPreemptions counts the number of other allocations being preempted that match the job and task group of
 // the alloc under consideration. This is used as a scoring factor to minimize too many allocs of the same job being preempted at once
 func (p *Preemptor) getNumPreemptions(alloc *structs.Allocation) int {
@@ -205,7 +352,7 @@ func (p *Preemptor) PreemptForTaskGroup(resourceAsk *structs.AllocatedResources)
 	}
 
 	// Group candidates by priority, filter out ineligible allocs
-	allocsByPriority := filterAndGroupPreemptibleAllocs(p.jobPriority, p.currentAllocs)
+	allocsByPriority := p.filterAndGroupPreemptibleAllocs(p.currentAllocs)
 
 	var bestAllocs []*structs.Allocation
 	allRequirementsMet := false
@@ -231,6 +378,14 @@ func (p *Preemptor) PreemptForTaskGroup(resourceAsk *structs.AllocatedResources)
 				}
 			}
 			closestAlloc := allocGrp.allocs[closestAllocIndex]
+			allocGrp.allocs[closestAllocIndex] = allocGrp.allocs[len(allocGrp.allocs)-1]
+			allocGrp.allocs = allocGrp.allocs[:len(allocGrp.allocs)-1]
+
+			// Skip the alloc if its task group cannot be disrupted any further
+			if !p.withinBudget(closestAlloc, bestAllocs) {
+				continue
+			}
+
 			closestResources := p.allocDetails[closestAlloc.ID].resources
 			availableResources.Add(closestResources)
 
@@ -239,9 +394,6 @@ func (p *Preemptor) PreemptForTaskGroup(resourceAsk *structs.AllocatedResources)
 
 			bestAllocs = append(bestAllocs, closestAlloc)
 
-			allocGrp.allocs[closestAllocIndex] = allocGrp.allocs[len(allocGrp.allocs)-1]
-			allocGrp.allocs = allocGrp.allocs[:len(allocGrp.allocs)-1]
-
 			// This is the remaining total of resources needed
 			resourcesNeeded.Subtract(closestResources)
 		}
@@ -260,6 +412,7 @@ func (p *Preemptor) PreemptForTaskGroup(resourceAsk *structs.AllocatedResources)
 	basePreemptionResource := GetBasePreemptionResourceFactory()
 	resourcesNeeded = resourceAsk.Comparable()
 	filteredBestAllocs := p.filterSuperset(bestAllocs, p.nodeRemainingResources, resourcesNeeded, basePreemptionResource)
+	p.addPreemptions(filteredBestAllocs)
 	return filteredBestAllocs
 
 }
@@ -306,8 +459,9 @@ func (p *Preemptor) PreemptForNetwork(networkResourceAsk *structs.NetworkResourc
 		// We only check first network - TODO: why?!?!
 		net := networks[0]
 
-		// Filter out alloc that's ineligible due to priority
-		if p.jobPriority-alloc.Job.Priority < 10 {
+		// Filter out alloc that's ineligible due to priority or its
+		// preemption configuration
+		if !p.preemptible(alloc) {
 			// Populate any reserved ports used by
 			// this allocation that cannot be preempted
 			for _, port := range net.ReservedPorts {
@@ -376,6 +530,12 @@ OUTER:
 			for _, port := range reservedPortsNeeded {
 				alloc, ok := usedPortToAlloc[port.Value]
 				if ok {
+					// The port cannot be freed without exceeding the
+					// disruption budget of the alloc, so skip to the next
+					// device
+					if !p.withinBudget(alloc, allocsToPreempt) {
+						continue OUTER
+					}
 					allocResources := p.allocDetails[alloc.ID].resources
 					preemptedBandwidth += allocResources.Flattened.Networks[0].MBits
 					allocsToPreempt = append(allocsToPreempt, alloc)
@@ -400,7 +560,7 @@ OUTER:
 		}
 
 		// Split by priority
-		allocsByPriority := filterAndGroupPreemptibleAllocs(p.jobPriority, currentAllocs)
+		allocsByPriority := p.filterAndGroupPreemptibleAllocs(currentAllocs)
 
 		for _, allocsGrp := range allocsByPriority {
 			allocs := allocsGrp.allocs
@@ -412,6 +572,9 @@ OUTER:
 
 			// Iterate over allocs until end of if requirements have been met
 			for _, alloc := range allocs {
+				if !p.withinBudget(alloc, allocsToPreempt) {
+					continue
+				}
 				allocResources := p.allocDetails[alloc.ID].resources
 				preemptedBandwidth += allocResources.Flattened.Networks[0].MBits
 				allocsToPreempt = append(allocsToPreempt, alloc)
@@ -450,6 +613,7 @@ OUTER:
 		},
 	}
 	filteredBestAllocs := p.filterSuperset(allocsToPreempt, nodeRemainingResources, resourcesNeeded, preemptionResourceFactory)
+	p.addPreemptions(filteredBestAllocs)
 	return filteredBestAllocs
 }
 
@@ -517,7 +681,7 @@ func (p *Preemptor) PreemptForDevice(ask *structs.RequestedDevice, devAlloc *dev
 OUTER:
 	for deviceIDTuple, allocsGrp := range deviceToAllocs {
 		// First group and sort allocations using this device by priority
-		allocsByPriority := filterAndGroupPreemptibleAllocs(p.jobPriority, allocsGrp.allocs)
+		allocsByPriority := p.filterAndGroupPreemptibleAllocs(allocsGrp.allocs)
 
 		// Reset preempted count for this device
 		preemptedCount := 0
@@ -527,6 +691,10 @@ OUTER:
 
 		for _, grpAllocs := range allocsByPriority {
 			for _, alloc := range grpAllocs.allocs {
+				if !p.withinBudget(alloc, preemptedAllocs) {
+					continue
+				}
+
 				// Look up the device instance from the device allocator
 				devInst := devAlloc.Devices[deviceIDTuple]
 
@@ -548,7 +716,9 @@ OUTER:
 
 	// Find the combination of allocs with lowest net priority
 	if len(preemptionOptions) > 0 {
-		return selectBestAllocs(preemptionOptions, int(neededCount))
+		bestAllocs := selectBestAllocs(preemptionOptions, int(neededCount))
+		p.addPreemptions(bestAllocs)
+		return bestAllocs
 	}
 
 	return nil
@@ -659,18 +829,15 @@ func scoreForNetwork(resourceUsed *structs.NetworkResource, resourceNeeded *stru
 }
 
 // filterAndGroupPreemptibleAllocs groups allocations by priority after filtering allocs
-// that are not preemptible based on the jobPriority arg
-func filterAndGroupPreemptibleAllocs(jobPriority int, current []*structs.Allocation) []*groupedAllocs {
+// that are not preemptible based on the job priority and their preemption configuration
+func (p *Preemptor) filterAndGroupPreemptibleAllocs(current []*structs.Allocation) []*groupedAllocs {
 	allocsByPriority := make(map[int][]*structs.Allocation)
 	for _, alloc := range current {
 		if alloc.Job == nil {
 			continue
 		}
 
-		// Skip allocs whose priority is within a delta of 10
-		// This also skips any allocs of the current job
-		// for which we are attempting preemption
-		if jobPriority-alloc.Job.Priority < 10 {
+		if !p.preemptible(alloc) {
 			continue
 		}
 		grpAllocs, ok := allocsByPriority[alloc.Job.Priority]
@@ -1385,6 +1385,135 @@ func TestPreemption(t *testing.T) {
 	}
 }
 
+// TestPreemption_Limits tests that the preemption configuration of task
+// groups and the priority ceiling of namespaces limit the allocations which
+// may be preempted.
+func TestPreemption_Limits(t *testing.T) {
+	ci.Parallel(t)
+
+	type testCase struct {
+		desc          string
+		count         int
+		preemption    *structs.PreemptionPolicy
+		maxPriority   int
+		expPreempted  int
+		expRejections []string
+	}
+
+	testCases := []testCase{
+		{
+			desc:         "no limits",
+			count:        4,
+			expPreempted: 2,
+		},
+		{
+			desc:  "not preemptible",
+			count: 4,
+			preemption: &structs.PreemptionPolicy{
+				Preemptible: false,
+			},
+			expRejections: []string{preemptionRejectedNotPreemptible},
+		},
+		{
+			desc:  "budget too small",
+			count: 4,
+			preemption: &structs.PreemptionPolicy{
+				Preemptible:   true,
+				MaxDisruption: 1,
+			},
+			expRejections: []string{preemptionRejectedBudget},
+		},
+		{
+			desc:  "budget large enough",
+			count: 4,
+			preemption: &structs.PreemptionPolicy{
+				Preemptible:   true,
+				MaxDisruption: 2,
+			},
+			expPreempted: 2,
+		},
+		{
+			desc:  "budget used by missing allocs",
+			count: 5,
+			preemption: &structs.PreemptionPolicy{
+				Preemptible:   true,
+				MaxDisruption: 2,
+			},
+			expRejections: []string{preemptionRejectedBudget},
+		},
+		{
+			desc:          "namespace priority ceiling",
+			count:         4,
+			maxPriority:   35,
+			expRejections: []string{preemptionRejectedPriority},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			state, ctx := testContext(t)
+
+			ns := mock.Namespace()
+			ns.PreemptionConfiguration = &structs.NamespacePreemptionConfiguration{
+				MaxPriority: tc.maxPriority,
+			}
+			require.NoError(t, state.UpsertNamespaces(999, []*structs.Namespace{ns}))
+
+			node := mock.Node()
+			require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+
+			// Fill the node with the allocations of a low priority job
+			lowPrioJob := mock.Job()
+			lowPrioJob.Priority = 30
+			lowPrioJob.TaskGroups[0].Count = tc.count
+			lowPrioJob.TaskGroups[0].Preemption = tc.preemption
+
+			var allocs []*structs.Allocation
+			for i := 0; i < 4; i++ {
+				alloc := createAlloc(uuid.Generate(), lowPrioJob, &structs.Resources{
+					CPU:      900,
+					MemoryMB: 1800,
+					DiskMB:   100,
+				})
+				alloc.NodeID = node.ID
+				allocs = append(allocs, alloc)
+			}
+			require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1001, allocs))
+
+			job := mock.Job()
+			job.Namespace = ns.Name
+			job.Priority = 100
+
+			static := NewStaticRankIterator(ctx, []*RankedNode{{Node: node}})
+			binPackIter := NewBinPackIterator(ctx, static, true, job.Priority, testSchedulerConfig)
+			binPackIter.SetJob(job)
+			binPackIter.SetTaskGroup(&structs.TaskGroup{
+				EphemeralDisk: &structs.EphemeralDisk{},
+				Tasks: []*structs.Task{
+					{
+						Name: "web",
+						Resources: &structs.Resources{
+							CPU:      1500,
+							MemoryMB: 2000,
+						},
+					},
+				},
+			})
+
+			option := binPackIter.Next()
+			if tc.expPreempted == 0 {
+				require.Nil(t, option)
+				for _, reason := range tc.expRejections {
+					require.Contains(t, ctx.Metrics().PreemptionRejected, reason)
+				}
+				return
+			}
+			require.NotNil(t, option)
+			require.Len(t, option.PreemptedAllocs, tc.expPreempted)
+		})
+	}
+}
+
 // TestPreemptionMultiple tests evicting multiple allocations in the same time
 func TestPreemptionMultiple(t *testing.T) {
 	ci.Parallel(t)
@@ -0,0 +1,262 @@
+package scheduler
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// QuotaIterator is a FeasibleIterator which stops yielding nodes once the
+// placement of the task group would exceed the quota attached to the
+// namespace of the job. The quota usage is taken from the state and updated
+// with the allocations already placed or stopped by the plan.
+type QuotaIterator struct {
+	ctx    Context
+	source FeasibleIterator
+	tg     *structs.TaskGroup
+
+	// quota is the name of the quota of the namespace of the job, and limit
+	// and used its limit and usage in the region. They are only set if the
+	// namespace quota limits the region.
+	quota string
+	limit *structs.QuotaLimit
+	used  *structs.Resources
+
+	// checked is whether the quota has been checked since the last reset,
+	// and exhausted whether it was found exhausted.
+	checked   bool
+	exhausted bool
+}
+
+// NewQuotaIterator returns a QuotaIterator which filters the nodes of the
+// source once the quota of the namespace of the job is exhausted.
+func NewQuotaIterator(ctx Context, source FeasibleIterator) FeasibleIterator {
+	return &QuotaIterator{
+		ctx:    ctx,
+		source: source,
+	}
+}
+
+func (iter *QuotaIterator) SetJob(job *structs.Job) {
+	iter.quota, iter.limit, iter.used = "", nil, nil
+	iter.checked, iter.exhausted = false, false
+
+	quota, limit, used, err := quotaUsage(iter.ctx.State(), job.Namespace)
+	if err != nil {
+		iter.ctx.Logger().Named("quota").Error("failed to lookup quota usage",
+			"namespace", job.Namespace, "error", err)
+		return
+	}
+	iter.quota, iter.limit, iter.used = quota, limit, used
+}
+
+func (iter *QuotaIterator) SetTaskGroup(tg *structs.TaskGroup) {
+	iter.tg = tg
+	iter.checked, iter.exhausted = false, false
+}
+
+func (iter *QuotaIterator) Next() *structs.Node {
+	if iter.limit == nil || iter.tg == nil {
+		return iter.source.Next()
+	}
+
+	if !iter.checked {
+		iter.checked = true
+		if dims := iter.exhaustedDimensions(); len(dims) > 0 {
+			iter.exhausted = true
+			iter.ctx.Metrics().ExhaustQuota(dims)
+			iter.ctx.Eligibility().SetQuotaLimitReached(iter.quota)
+		}
+	}
+	if iter.exhausted {
+		return nil
+	}
+	return iter.source.Next()
+}
+
+func (iter *QuotaIterator) Reset() {
+	iter.source.Reset()
+
+	// The plan may have changed since the last check
+	iter.checked, iter.exhausted = false, false
+}
+
+// exhaustedDimensions returns the quota dimensions which placing the task
+// group would exceed, given the allocations already in the plan.
+func (iter *QuotaIterator) exhaustedDimensions() []string {
+	delta, err := planQuotaDelta(iter.ctx.State(), iter.ctx.Plan(), iter.quota)
+	if err != nil {
+		iter.ctx.Logger().Named("quota").Error("failed to compute plan quota usage",
+			"quota", iter.quota, "error", err)
+		return nil
+	}
+	delta.AddQuotaResources(taskGroupQuotaResources(iter.tg))
+	return iter.limit.Exhausted(iter.used, delta)
+}
+
+// QuotaExhausted returns the quota of the namespace of the plan's job and the
+// dimensions of it which the plan would exceed once applied. Only the
+// dimensions the plan increases are returned, so plans which stop or shrink
+// allocations are never rejected.
+func QuotaExhausted(state State, plan *structs.Plan) (string, []string, error) {
+	if plan.Job == nil {
+		return "", nil, nil
+	}
+
+	quota, limit, used, err := quotaUsage(state, plan.Job.Namespace)
+	if err != nil || limit == nil {
+		return "", nil, err
+	}
+
+	delta, err := planQuotaDelta(state, plan, quota)
+	if err != nil {
+		return "", nil, err
+	}
+	return quota, limit.Exhausted(used, delta), nil
+}
+
+// quotaUsage returns the quota attached to the namespace along with its
+// limit and usage in the region of the state. The limit is nil if the
+// namespace has no quota or the quota does not limit the region.
+func quotaUsage(state State, namespace string) (string, *structs.QuotaLimit, *structs.Resources, error) {
+	ns, err := state.NamespaceByName(nil, namespace)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if ns == nil || ns.Quota == "" {
+		return "", nil, nil, nil
+	}
+
+	spec, err := state.QuotaSpecByName(nil, ns.Quota)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if spec == nil {
+		return "", nil, nil, fmt.Errorf("quota %q of namespace %q not found", ns.Quota, namespace)
+	}
+
+	limit := spec.RegionLimit(state.Config().Region)
+	if limit == nil {
+		return ns.Quota, nil, nil, nil
+	}
+
+	usage, err := state.QuotaUsageByName(nil, ns.Quota)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	used := new(structs.Resources)
+	if usage != nil {
+		if regionUsage, ok := usage.Used[limit.HashKey()]; ok && regionUsage.RegionLimit != nil {
+			used = regionUsage.RegionLimit
+		}
+	}
+	return ns.Quota, limit, used, nil
+}
+
+// planQuotaDelta returns the change in the usage of the quota the plan
+// would cause once applied. Placements add to the usage, while stopped and
+// preempted allocations of namespaces attached to the quota free it.
+func planQuotaDelta(state State, plan *structs.Plan, quota string) (*structs.Resources, error) {
+	delta := new(structs.Resources)
+	if plan == nil {
+		return delta, nil
+	}
+
+	// usesQuota returns whether the namespace is attached to the quota,
+	// caching the lookups as preempted allocations may belong to many
+	// namespaces.
+	namespaces := make(map[string]bool)
+	usesQuota := func(namespace string) (bool, error) {
+		if uses, ok := namespaces[namespace]; ok {
+			return uses, nil
+		}
+		ns, err := state.NamespaceByName(nil, namespace)
+		if err != nil {
+			return false, err
+		}
+		uses := ns != nil && ns.Quota == quota
+		namespaces[namespace] = uses
+		return uses, nil
+	}
+
+	// Allocations can appear several times in the plan, for example when
+	// stopped and updated, so their existing usage is only freed once.
+	freed := make(map[string]struct{})
+	free := func(allocID string) (*structs.Allocation, error) {
+		existing, err := state.AllocByID(nil, allocID)
+		if err != nil || existing == nil {
+			return nil, err
+		}
+		if _, ok := freed[allocID]; ok || existing.TerminalStatus() {
+			return existing, nil
+		}
+		if uses, err := usesQuota(existing.Namespace); err != nil || !uses {
+			return existing, err
+		}
+		freed[allocID] = struct{}{}
+		delta.SubtractQuotaResources(existing.QuotaResources())
+		return existing, nil
+	}
+
+	for _, allocs := range plan.NodeUpdate {
+		for _, alloc := range allocs {
+			if _, err := free(alloc.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, allocs := range plan.NodePreemptions {
+		for _, alloc := range allocs {
+			if _, err := free(alloc.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, allocs := range plan.NodeAllocation {
+		for _, alloc := range allocs {
+			existing, err := free(alloc.ID)
+			if err != nil {
+				return nil, err
+			}
+			if alloc.TerminalStatus() {
+				continue
+			}
+			if uses, err := usesQuota(alloc.Namespace); err != nil {
+				return nil, err
+			} else if !uses {
+				continue
+			}
+
+			// Allocations updated by the plan may not carry their resources
+			if alloc.AllocatedResources == nil && existing != nil {
+				delta.AddQuotaResources(existing.QuotaResources())
+			} else {
+				delta.AddQuotaResources(alloc.QuotaResources())
+			}
+		}
+	}
+	return delta, nil
+}
+
+// taskGroupQuotaResources returns the resources a single allocation of the
+// task group counts against a quota. Reserved cores are not included as
+// their CPU shares depend on the node the allocation is placed on; they are
+// accounted for by the plan applier.
+func taskGroupQuotaResources(tg *structs.TaskGroup) *structs.Resources {
+	r := new(structs.Resources)
+	for _, task := range tg.Tasks {
+		if task.Resources == nil {
+			continue
+		}
+		memoryMax := task.Resources.MemoryMaxMB
+		if memoryMax < task.Resources.MemoryMB {
+			memoryMax = task.Resources.MemoryMB
+		}
+		r.AddQuotaResources(&structs.Resources{
+			CPU:         task.Resources.CPU,
+			MemoryMB:    task.Resources.MemoryMB,
+			MemoryMaxMB: memoryMax,
+		})
+	}
+	return r
+}
@@ -0,0 +1,173 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// quotaNamespace creates a quota limiting the CPU of the global region and a
+// namespace attached to it.
+func quotaNamespace(t *testing.T, state *state.StateStore, index uint64, cpu int) (*structs.QuotaSpec, *structs.Namespace) {
+	spec := mock.QuotaSpec()
+	spec.Limits[0].RegionLimit = &structs.Resources{CPU: cpu}
+	spec.SetHash()
+	require.NoError(t, state.UpsertQuotaSpecs(structs.MsgTypeTestSetup, index, []*structs.QuotaSpec{spec}))
+
+	ns := mock.Namespace()
+	ns.Quota = spec.Name
+	require.NoError(t, state.UpsertNamespaces(index+1, []*structs.Namespace{ns}))
+	return spec, ns
+}
+
+func TestQuotaIterator(t *testing.T) {
+	ci.Parallel(t)
+
+	state, ctx := testContext(t)
+	spec, ns := quotaNamespace(t, state, 1000, 1000)
+
+	// An allocation already uses half of the quota
+	alloc := mock.Alloc()
+	alloc.Namespace = ns.Name
+	alloc.Job.Namespace = ns.Name
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1002, alloc.Job))
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1003, []*structs.Allocation{alloc}))
+
+	nodes := []*structs.Node{mock.Node(), mock.Node()}
+	static := NewStaticIterator(ctx, nodes)
+
+	job := mock.Job()
+	job.Namespace = ns.Name
+	quota := NewQuotaIterator(ctx, static).(*QuotaIterator)
+	quota.SetJob(job)
+	quota.SetTaskGroup(job.TaskGroups[0])
+
+	// The task group fits in the remaining half
+	require.Len(t, collectFeasible(quota), 2)
+	require.Empty(t, ctx.Metrics().QuotaExhausted)
+
+	// Once the plan places an allocation the quota is exhausted
+	placed := mock.Alloc()
+	placed.Namespace = ns.Name
+	ctx.Plan().AppendAlloc(placed, job)
+	quota.Reset()
+	require.Empty(t, collectFeasible(quota))
+	require.Equal(t, []string{"cpu exhausted (1500 > 1000)"}, ctx.Metrics().QuotaExhausted)
+	require.Equal(t, spec.Name, ctx.Eligibility().QuotaLimitReached())
+
+	// Stopping the existing allocation frees its usage
+	ctx.Plan().AppendStoppedAlloc(alloc, "stopped", "", "")
+	quota.Reset()
+	require.Len(t, collectFeasible(quota), 2)
+
+	// Jobs of namespaces without quotas are not limited
+	other := mock.Job()
+	quota.SetJob(other)
+	quota.SetTaskGroup(other.TaskGroups[0])
+	require.Len(t, collectFeasible(quota), 2)
+}
+
+func TestQuotaExhausted(t *testing.T) {
+	ci.Parallel(t)
+
+	state := state.TestStateStore(t)
+	spec, ns := quotaNamespace(t, state, 1000, 1000)
+
+	job := mock.Job()
+	job.Namespace = ns.Name
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1002, job))
+
+	// The quota is already exceeded
+	var allocs []*structs.Allocation
+	for i := 0; i < 3; i++ {
+		alloc := mock.Alloc()
+		alloc.Namespace = ns.Name
+		alloc.Job = job
+		alloc.JobID = job.ID
+		allocs = append(allocs, alloc)
+	}
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1003, allocs))
+
+	newPlan := func() *structs.Plan {
+		return &structs.Plan{
+			Job:             job,
+			NodeUpdate:      make(map[string][]*structs.Allocation),
+			NodeAllocation:  make(map[string][]*structs.Allocation),
+			NodePreemptions: make(map[string][]*structs.Allocation),
+		}
+	}
+
+	// Plans which only free resources are allowed
+	plan := newPlan()
+	plan.AppendStoppedAlloc(allocs[0], "stopped", "", "")
+	quota, dims, err := QuotaExhausted(state, plan)
+	require.NoError(t, err)
+	require.Empty(t, dims)
+
+	// Plans which replace allocations are allowed
+	replacement := mock.Alloc()
+	replacement.Namespace = ns.Name
+	plan.AppendAlloc(replacement, job)
+	quota, dims, err = QuotaExhausted(state, plan)
+	require.NoError(t, err)
+	require.Empty(t, dims)
+
+	// Plans which increase the usage are rejected
+	plan = newPlan()
+	placed := mock.Alloc()
+	placed.Namespace = ns.Name
+	plan.AppendAlloc(placed, job)
+	quota, dims, err = QuotaExhausted(state, plan)
+	require.NoError(t, err)
+	require.Equal(t, spec.Name, quota)
+	require.Equal(t, []string{"cpu exhausted (2000 > 1000)"}, dims)
+}
+
+func TestServiceSched_JobRegister_QuotaExhausted(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+	spec, ns := quotaNamespace(t, h.State, h.NextIndex(), 1000)
+	h.NextIndex()
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), mock.Node()))
+	}
+
+	// The quota only allows two of the allocations
+	job := mock.Job()
+	job.Namespace = ns.Name
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+	eval := &structs.Evaluation{
+		Namespace:   ns.Name,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+	require.NoError(t, h.Process(NewServiceScheduler, eval))
+
+	allocs, err := h.State.AllocsByJob(nil, job.Namespace, job.ID, false)
+	require.NoError(t, err)
+	require.Len(t, allocs, 2)
+
+	// The remaining placements are blocked on the quota
+	require.Len(t, h.CreateEvals, 1)
+	blocked := h.CreateEvals[0]
+	require.Equal(t, structs.EvalStatusBlocked, blocked.Status)
+	require.Equal(t, spec.Name, blocked.QuotaLimitReached)
+
+	require.Len(t, h.Evals, 1)
+	metrics := h.Evals[0].FailedTGAllocs[job.TaskGroups[0].Name]
+	require.NotNil(t, metrics)
+	require.Equal(t, []string{"cpu exhausted (1500 > 1000)"}, metrics.QuotaExhausted)
+	require.Equal(t, 8, h.Evals[0].QueuedAllocations[job.TaskGroups[0].Name])
+}
@@ -3,8 +3,7 @@ package scheduler
 import (
 	"fmt"
 	"math"
-
-	"github.com/hashicorp/nomad/lib/cpuset"
+	"time"
 
 	"github.com/hashicorp/nomad/nomad/structs"
 )
@@ -162,28 +161,41 @@ type BinPackIterator struct {
 // NewBinPackIterator returns a BinPackIterator which tries to fit tasks
 // potentially evicting other tasks based on a given priority.
 func NewBinPackIterator(ctx Context, source RankIterator, evict bool, priority int, schedConfig *structs.SchedulerConfiguration) *BinPackIterator {
-
-	algorithm := schedConfig.EffectiveSchedulerAlgorithm()
-	scoreFn := structs.ScoreFitBinPack
-	if algorithm == structs.SchedulerAlgorithmSpread {
-		scoreFn = structs.ScoreFitSpread
-	}
-
 	iter := &BinPackIterator{
-		ctx:                    ctx,
-		source:                 source,
-		evict:                  evict,
-		priority:               priority,
-		memoryOversubscription: schedConfig != nil && schedConfig.MemoryOversubscriptionEnabled,
-		scoreFit:               scoreFn,
-	}
-	iter.ctx.Logger().Named("binpack").Trace("NewBinPackIterator created", "algorithm", algorithm)
+		ctx:      ctx,
+		source:   source,
+		evict:    evict,
+		priority: priority,
+	}
+	iter.SetSchedulerConfiguration(schedConfig)
+	iter.ctx.Logger().Named("binpack").Trace("NewBinPackIterator created", "algorithm", schedConfig.EffectiveSchedulerAlgorithm())
 	return iter
 }
 
 func (iter *BinPackIterator) SetJob(job *structs.Job) {
 	iter.priority = job.Priority
 	iter.jobId = job.NamespacedID()
+
+	// Cap the priority used for preemption to the ceiling of the namespace
+	ns, err := iter.ctx.State().NamespaceByName(nil, job.Namespace)
+	if err != nil {
+		iter.ctx.Logger().Named("binpack").Error("failed to lookup namespace", "namespace", job.Namespace, "error", err)
+		return
+	}
+	iter.priority = ns.PreemptionPriority(job.Priority)
+}
+
+// SetSchedulerConfiguration sets the scoring algorithm and memory
+// oversubscription from the scheduler configuration, which may be
+// overridden by the node pool of the job.
+func (iter *BinPackIterator) SetSchedulerConfiguration(schedConfig *structs.SchedulerConfiguration) {
+	scoreFn := structs.ScoreFitBinPack
+	if schedConfig.EffectiveSchedulerAlgorithm() == structs.SchedulerAlgorithmSpread {
+		scoreFn = structs.ScoreFitSpread
+	}
+
+	iter.scoreFit = scoreFn
+	iter.memoryOversubscription = schedConfig != nil && schedConfig.MemoryOversubscriptionEnabled
 }
 
 func (iter *BinPackIterator) SetTaskGroup(taskGroup *structs.TaskGroup) {
@@ -309,6 +321,7 @@ OUTER:
 				netPreemptions := preemptor.PreemptForNetwork(ask, netIdx)
 				if netPreemptions == nil {
 					iter.ctx.Logger().Named("binpack").Debug("preemption not possible ", "network_resource", ask)
+					iter.rejectPreemption(preemptor)
 					netIdx.Release()
 					continue OUTER
 				}
@@ -379,6 +392,7 @@ OUTER:
 					netPreemptions := preemptor.PreemptForNetwork(ask, netIdx)
 					if netPreemptions == nil {
 						iter.ctx.Logger().Named("binpack").Debug("preemption not possible ", "network_resource", ask)
+						iter.rejectPreemption(preemptor)
 						netIdx.Release()
 						continue OUTER
 					}
@@ -408,9 +422,23 @@ OUTER:
 				taskResources.Networks = []*structs.NetworkResource{offer}
 			}
 
+			// Select the NUMA node the cores and devices of the task are placed on
+			var numaNode *structs.NodeNUMANode
+			var numaMemoryUsed map[uint16]int64
+			numaRequested := task.Resources.Cores > 0 && task.Resources.NUMA.IsRequested()
+			if numaRequested {
+				numaMemoryUsed = numaMemoryUsage(proposed, total)
+				numaNode = selectNUMANode(option.Node, availableCores(option.Node, proposed, total), numaMemoryUsed, task.Resources, devAllocator)
+				if numaNode == nil && task.Resources.NUMA.IsRequired() {
+					iter.ctx.Metrics().ExhaustedNode(option.Node, "numa")
+					netIdx.Release()
+					continue OUTER
+				}
+			}
+
 			// Check if we need to assign devices
 			for _, req := range task.Resources.Devices {
-				offer, sumAffinities, err := devAllocator.AssignDevice(req)
+				offer, sumAffinities, err := devAllocator.AssignDeviceOnNUMANode(req, numaNode)
 				if offer == nil {
 					// If eviction is not enabled, mark this node as exhausted and continue
 					if !iter.evict {
@@ -424,6 +452,7 @@ OUTER:
 
 					if devicePreemptions == nil {
 						iter.ctx.Logger().Named("binpack").Debug("preemption not possible", "requested_device", req)
+						iter.rejectPreemption(preemptor)
 						netIdx.Release()
 						continue OUTER
 					}
@@ -437,7 +466,7 @@ OUTER:
 					devAllocator.AddAllocs(proposed)
 
 					// Try offer again
-					offer, sumAffinities, err = devAllocator.AssignDevice(req)
+					offer, sumAffinities, err = devAllocator.AssignDeviceOnNUMANode(req, numaNode)
 					if offer == nil {
 						iter.ctx.Logger().Named("binpack").Debug("unexpected error, unable to create device offer after considering preemption", "error", err)
 						continue OUTER
@@ -459,21 +488,8 @@ OUTER:
 
 			// Check if we need to allocate any reserved cores
 			if task.Resources.Cores > 0 {
-				// set of reservable CPUs for the node
-				nodeCPUSet := cpuset.New(option.Node.NodeResources.Cpu.ReservableCpuCores...)
-				// set of all reserved CPUs on the node
-				allocatedCPUSet := cpuset.New()
-				for _, alloc := range proposed {
-					allocatedCPUSet = allocatedCPUSet.Union(cpuset.New(alloc.ComparableResources().Flattened.Cpu.ReservedCores...))
-				}
-
-				// add any cores that were reserved for other tasks
-				for _, tr := range total.Tasks {
-					allocatedCPUSet = allocatedCPUSet.Union(cpuset.New(tr.Cpu.ReservedCores...))
-				}
-
 				// set of CPUs not yet reserved on the node
-				availableCPUSet := nodeCPUSet.Difference(allocatedCPUSet)
+				availableCPUSet := availableCores(option.Node, proposed, total)
 
 				// If not enough cores are available mark the node as exhausted
 				if availableCPUSet.Size() < task.Resources.Cores {
@@ -482,8 +498,22 @@ OUTER:
 					continue OUTER
 				}
 
-				// Set the task's reserved cores
-				taskResources.Cpu.ReservedCores = availableCPUSet.ToSlice()[0:task.Resources.Cores]
+				// Set the task's reserved cores, and the NUMA nodes its memory
+				// is bound to if NUMA aware placement is requested
+				if numaRequested {
+					topology := option.Node.NodeResources.NUMA
+					taskResources.Cpu.ReservedCores, taskResources.Cpu.NUMANodes = selectNUMACores(
+						topology, numaNode, availableCPUSet, task.Resources.Cores)
+
+					// When no single NUMA node fits the task, only bind its
+					// memory to the NUMA nodes of its cores if they have
+					// enough memory left, and let it spread otherwise
+					if numaNode == nil && !numaMemoryFits(topology, taskResources.Cpu.NUMANodes, numaMemoryUsed, int64(task.Resources.MemoryMB)) {
+						taskResources.Cpu.NUMANodes = nil
+					}
+				} else {
+					taskResources.Cpu.ReservedCores = availableCPUSet.ToSlice()[0:task.Resources.Cores]
+				}
 				// Total CPU usage on the node is still tracked by CPUShares. Even though the task will have the entire
 				// core reserved, we still track overall usage by cpu shares.
 				taskResources.Cpu.CpuShares = option.Node.NodeResources.Cpu.SharesPerCore() * int64(task.Resources.Cores)
@@ -526,6 +556,7 @@ OUTER:
 			// mark as exhausted and continue
 			if len(preemptedAllocs) == 0 {
 				iter.ctx.Metrics().ExhaustedNode(option.Node, dim)
+				iter.rejectPreemption(preemptor)
 				continue
 			}
 		}
@@ -550,6 +581,14 @@ OUTER:
 	}
 }
 
+// rejectPreemption records why the preemptor could not free enough resources
+// on the node being ranked.
+func (iter *BinPackIterator) rejectPreemption(preemptor *Preemptor) {
+	for _, reason := range preemptor.RejectionReasons() {
+		iter.ctx.Metrics().RejectPreemption(reason)
+	}
+}
+
 func (iter *BinPackIterator) Reset() {
 	iter.source.Reset()
 }
@@ -669,6 +708,85 @@ func (iter *NodeReschedulingPenaltyIterator) Reset() {
 	iter.source.Reset()
 }
 
+// UtilizationIterator is used to score nodes on the CPU and memory utilization
+// reported by their clients, rather than on the resources reserved by their
+// allocations only. Nodes that are actually idle get a higher score. Nodes
+// without recent utilization are not scored.
+type UtilizationIterator struct {
+	ctx     Context
+	source  RankIterator
+	enabled bool
+}
+
+// NewUtilizationIterator is used to create a UtilizationIterator which is
+// enabled by the scheduler configuration.
+func NewUtilizationIterator(ctx Context, source RankIterator, schedConfig *structs.SchedulerConfiguration) *UtilizationIterator {
+	iter := &UtilizationIterator{
+		ctx:    ctx,
+		source: source,
+	}
+	iter.SetSchedulerConfiguration(schedConfig)
+	return iter
+}
+
+// SetSchedulerConfiguration enables or disables the iterator from the
+// scheduler configuration.
+func (iter *UtilizationIterator) SetSchedulerConfiguration(schedConfig *structs.SchedulerConfiguration) {
+	iter.enabled = schedConfig != nil && schedConfig.UtilizationScoringEnabled
+}
+
+func (iter *UtilizationIterator) Next() *RankedNode {
+	option := iter.source.Next()
+	if option == nil || !iter.enabled {
+		return option
+	}
+
+	utilization := option.Node.Utilization
+	if utilization.IsStale(time.Now()) {
+		return option
+	}
+
+	// Determine the resources available for allocations on the node
+	capacity := option.Node.ComparableResources()
+	capacity.Subtract(option.Node.ComparableReservedResources())
+	cpuCapacity := float64(capacity.Flattened.Cpu.CpuShares)
+	memoryCapacity := float64(capacity.Flattened.Memory.MemoryMB)
+	if cpuCapacity <= 0 || memoryCapacity <= 0 {
+		return option
+	}
+
+	// The utilization does not account for the allocations placed on the
+	// node by the plan so far, nor for the one being placed, so add their
+	// reserved resources.
+	cpu := float64(utilization.CpuUsedMHz)
+	memory := float64(utilization.MemoryUsedMB)
+	for _, alloc := range iter.ctx.Plan().NodeAllocation[option.Node.ID] {
+		// In-place updates of existing allocations are already running
+		if alloc.CreateIndex != 0 {
+			continue
+		}
+		resources := alloc.ComparableResources()
+		cpu += float64(resources.Flattened.Cpu.CpuShares)
+		memory += float64(resources.Flattened.Memory.MemoryMB)
+	}
+	for _, resources := range option.TaskResources {
+		cpu += float64(resources.Cpu.CpuShares)
+		memory += float64(resources.Memory.MemoryMB)
+	}
+
+	// Score idle nodes 1 and fully utilized nodes -1
+	used := (cpu/cpuCapacity + memory/memoryCapacity) / 2
+	score := math.Max(-1, math.Min(1, 1-2*used))
+
+	option.Scores = append(option.Scores, score)
+	iter.ctx.Metrics().ScoreNode(option.Node, "utilization", score)
+	return option
+}
+
+func (iter *UtilizationIterator) Reset() {
+	iter.source.Reset()
+}
+
 // NodeAffinityIterator is used to resolve any affinity rules in the job or task group,
 // and apply a weighted score to nodes if they match.
 type NodeAffinityIterator struct {
@@ -3,6 +3,7 @@ package scheduler
 import (
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/mock"
@@ -1840,6 +1841,206 @@ func TestBinPackIterator_Devices(t *testing.T) {
 	}
 }
 
+func TestBinPackIterator_NUMA(t *testing.T) {
+	// numaNode returns a node with two NUMA nodes of two physical cores with
+	// two threads each, and a GPU attached to each NUMA node
+	numaNode := func() *structs.Node {
+		node := mock.NvidiaNode()
+		node.NodeResources.Cpu = structs.NodeCpuResources{
+			CpuShares:          8000,
+			TotalCpuCores:      8,
+			ReservableCpuCores: []uint16{0, 1, 2, 3, 4, 5, 6, 7},
+		}
+		node.NodeResources.NUMA = &structs.NodeNUMAResources{
+			Nodes: []*structs.NodeNUMANode{
+				{
+					ID:           0,
+					Socket:       0,
+					Cores:        []uint16{0, 1, 2, 3},
+					CoreSiblings: [][]uint16{{0, 2}, {1, 3}},
+					MemoryMB:     4096,
+				},
+				{
+					ID:           1,
+					Socket:       1,
+					Cores:        []uint16{4, 5, 6, 7},
+					CoreSiblings: [][]uint16{{4, 6}, {5, 7}},
+					MemoryMB:     4096,
+				},
+			},
+		}
+		for i, instance := range node.NodeResources.Devices[0].Instances {
+			numaID := uint16(i)
+			instance.Locality = &structs.NodeDeviceLocality{NUMANode: &numaID}
+		}
+		return node
+	}
+
+	taskGroup := func(cores int, affinity string, gpus uint64) *structs.TaskGroup {
+		task := &structs.Task{
+			Name: "web",
+			Resources: &structs.Resources{
+				Cores:    cores,
+				MemoryMB: 1024,
+				NUMA:     &structs.NUMA{Affinity: affinity},
+			},
+		}
+		if gpus > 0 {
+			task.Resources.Devices = []*structs.RequestedDevice{{Name: "nvidia/gpu", Count: gpus}}
+		}
+		return &structs.TaskGroup{
+			EphemeralDisk: &structs.EphemeralDisk{},
+			Tasks:         []*structs.Task{task},
+		}
+	}
+
+	// existingAlloc returns an allocation reserving the cores and GPUs
+	existingAlloc := func(node *structs.Node, cores []uint16, gpus []int) *structs.Allocation {
+		alloc := mock.Alloc()
+		alloc.NodeID = node.ID
+		alloc.AllocatedResources.Tasks["web"].Cpu.ReservedCores = cores
+		for _, i := range gpus {
+			alloc.AllocatedResources.Tasks["web"].Devices = append(alloc.AllocatedResources.Tasks["web"].Devices,
+				&structs.AllocatedDeviceResource{
+					Type:      "gpu",
+					Vendor:    "nvidia",
+					Name:      "1080ti",
+					DeviceIDs: []string{node.NodeResources.Devices[0].Instances[i].ID},
+				})
+		}
+		return alloc
+	}
+
+	cases := []struct {
+		Name          string
+		TaskGroup     *structs.TaskGroup
+		MemoryMB      int
+		ExistingCores []uint16
+		ExistingGPUs  []int
+		ExistingNUMA  []uint16
+		NoPlace       bool
+		ExpectedCores []uint16
+		ExpectedNUMA  []uint16
+		ExpectedGPU   int
+	}{
+		{
+			Name:          "require, whole physical cores",
+			TaskGroup:     taskGroup(2, structs.NUMAAffinityRequire, 0),
+			ExpectedCores: []uint16{0, 2},
+			ExpectedNUMA:  []uint16{0},
+		},
+		{
+			Name:          "require, best fit NUMA node",
+			TaskGroup:     taskGroup(2, structs.NUMAAffinityRequire, 0),
+			ExistingCores: []uint16{0, 1},
+			ExpectedCores: []uint16{2, 3},
+			ExpectedNUMA:  []uint16{0},
+		},
+		{
+			Name:          "require, NUMA node of the available device",
+			TaskGroup:     taskGroup(2, structs.NUMAAffinityRequire, 1),
+			ExistingGPUs:  []int{0},
+			ExpectedCores: []uint16{4, 6},
+			ExpectedNUMA:  []uint16{1},
+			ExpectedGPU:   1,
+		},
+		{
+			Name:          "require, no NUMA node with enough cores",
+			TaskGroup:     taskGroup(4, structs.NUMAAffinityRequire, 0),
+			ExistingCores: []uint16{0, 4},
+			NoPlace:       true,
+		},
+		{
+			Name:          "require, no NUMA node with cores and devices",
+			TaskGroup:     taskGroup(3, structs.NUMAAffinityRequire, 1),
+			ExistingCores: []uint16{0, 1},
+			ExistingGPUs:  []int{1},
+			NoPlace:       true,
+		},
+		{
+			Name:          "prefer, single NUMA node",
+			TaskGroup:     taskGroup(3, structs.NUMAAffinityPrefer, 0),
+			ExistingCores: []uint16{0, 4},
+			ExpectedCores: []uint16{1, 2, 3},
+			ExpectedNUMA:  []uint16{0},
+		},
+		{
+			Name:          "prefer, no single NUMA node fits",
+			TaskGroup:     taskGroup(5, structs.NUMAAffinityPrefer, 0),
+			ExpectedCores: []uint16{0, 1, 2, 3, 4},
+			ExpectedNUMA:  []uint16{0, 1},
+		},
+		{
+			Name:          "require, NUMA node with enough memory",
+			TaskGroup:     taskGroup(2, structs.NUMAAffinityRequire, 0),
+			MemoryMB:      3072,
+			ExistingCores: []uint16{0},
+			ExistingNUMA:  []uint16{0},
+			ExpectedCores: []uint16{4, 6},
+			ExpectedNUMA:  []uint16{1},
+		},
+		{
+			Name:      "require, no NUMA node with enough memory",
+			TaskGroup: taskGroup(2, structs.NUMAAffinityRequire, 0),
+			MemoryMB:  5120,
+			NoPlace:   true,
+		},
+		{
+			Name:          "prefer, no NUMA node with enough memory",
+			TaskGroup:     taskGroup(2, structs.NUMAAffinityPrefer, 0),
+			MemoryMB:      5120,
+			ExpectedCores: []uint16{0, 2},
+		},
+		{
+			Name:          "none",
+			TaskGroup:     taskGroup(2, structs.NUMAAffinityNone, 0),
+			ExistingCores: []uint16{0},
+			ExpectedCores: []uint16{1, 2},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			state, ctx := testContext(t)
+			node := numaNode()
+			if c.MemoryMB > 0 {
+				c.TaskGroup.Tasks[0].Resources.MemoryMB = c.MemoryMB
+			}
+
+			if len(c.ExistingCores) > 0 || len(c.ExistingGPUs) > 0 {
+				alloc := existingAlloc(node, c.ExistingCores, c.ExistingGPUs)
+				if len(c.ExistingNUMA) > 0 {
+					// The existing task uses half of the memory of its NUMA node
+					alloc.AllocatedResources.Tasks["web"].Cpu.NUMANodes = c.ExistingNUMA
+					alloc.AllocatedResources.Tasks["web"].Memory.MemoryMB = 2048
+				}
+				require.NoError(t, state.UpsertJobSummary(999, mock.JobSummary(alloc.JobID)))
+				require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000, []*structs.Allocation{alloc}))
+			}
+
+			static := NewStaticRankIterator(ctx, []*RankedNode{{Node: node}})
+			binp := NewBinPackIterator(ctx, static, false, 0, testSchedulerConfig)
+			binp.SetTaskGroup(c.TaskGroup)
+
+			out := binp.Next()
+			if c.NoPlace {
+				require.Nil(t, out)
+				return
+			}
+			require.NotNil(t, out)
+
+			tr := out.TaskResources["web"]
+			require.Equal(t, c.ExpectedCores, tr.Cpu.ReservedCores)
+			require.Equal(t, c.ExpectedNUMA, tr.Cpu.NUMANodes)
+			if len(c.TaskGroup.Tasks[0].Resources.Devices) > 0 {
+				require.Len(t, tr.Devices, 1)
+				expected := node.NodeResources.Devices[0].Instances[c.ExpectedGPU].ID
+				require.Equal(t, []string{expected}, tr.Devices[0].DeviceIDs)
+			}
+		})
+	}
+}
+
 func TestJobAntiAffinity_PlannedAlloc(t *testing.T) {
 	_, ctx := testContext(t)
 	nodes := []*RankedNode{
@@ -1956,6 +2157,80 @@ func TestNodeAntiAffinity_PenaltyNodes(t *testing.T) {
 
 }
 
+func TestUtilizationIterator(t *testing.T) {
+	_, ctx := testContext(t)
+
+	now := time.Now().Unix()
+	newNode := func(utilization *structs.NodeUtilization) *RankedNode {
+		return &RankedNode{
+			Node: &structs.Node{
+				ID: uuid.Generate(),
+				NodeResources: &structs.NodeResources{
+					Cpu:    structs.NodeCpuResources{CpuShares: 5000},
+					Memory: structs.NodeMemoryResources{MemoryMB: 5000},
+				},
+				ReservedResources: &structs.NodeReservedResources{
+					Cpu:    structs.NodeReservedCpuResources{CpuShares: 1000},
+					Memory: structs.NodeReservedMemoryResources{MemoryMB: 1000},
+				},
+				Utilization: utilization,
+			},
+			TaskResources: map[string]*structs.AllocatedTaskResources{
+				"web": {
+					Cpu:    structs.AllocatedCpuResources{CpuShares: 1000},
+					Memory: structs.AllocatedMemoryResources{MemoryMB: 1000},
+				},
+			},
+		}
+	}
+
+	idle := newNode(&structs.NodeUtilization{UpdatedAt: now})
+	busy := newNode(&structs.NodeUtilization{CpuUsedMHz: 2000, MemoryUsedMB: 2000, UpdatedAt: now})
+	planned := newNode(&structs.NodeUtilization{UpdatedAt: now})
+	stale := newNode(&structs.NodeUtilization{UpdatedAt: now - 3600})
+	unknown := newNode(nil)
+
+	// Allocations placed by the plan count towards the utilization
+	ctx.Plan().NodeAllocation[planned.Node.ID] = []*structs.Allocation{
+		{
+			AllocatedResources: &structs.AllocatedResources{
+				Tasks: map[string]*structs.AllocatedTaskResources{
+					"web": {
+						Cpu:    structs.AllocatedCpuResources{CpuShares: 2000},
+						Memory: structs.AllocatedMemoryResources{MemoryMB: 2000},
+					},
+				},
+			},
+		},
+	}
+
+	nodes := []*RankedNode{idle, busy, planned, stale, unknown}
+	static := NewStaticRankIterator(ctx, nodes)
+	iter := NewUtilizationIterator(ctx, static, &structs.SchedulerConfiguration{
+		UtilizationScoringEnabled: true,
+	})
+
+	out := collectRanked(iter)
+	require.Len(t, out, 5)
+	require.Equal(t, []float64{0.5}, idle.Scores)
+	require.Equal(t, []float64{-0.5}, busy.Scores)
+	require.Equal(t, []float64{-0.5}, planned.Scores)
+	require.Empty(t, stale.Scores)
+	require.Empty(t, unknown.Scores)
+
+	// Nodes are not scored when disabled
+	for _, n := range nodes {
+		n.Scores = nil
+	}
+	iter.Reset()
+	iter.SetSchedulerConfiguration(testSchedulerConfig)
+	out = collectRanked(iter)
+	require.Len(t, out, 5)
+	for _, n := range out {
+		require.Empty(t, n.Scores)
+	}
+}
+
 func TestScoreNormalizationIterator(t *testing.T) {
 	// Test normalized scores when there is more than one scorer
 	_, ctx := testContext(t)
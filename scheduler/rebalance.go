@@ -0,0 +1,488 @@
+package scheduler
+
+import (
+	"math"
+	"sort"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// rebalancer finds the running allocations of service jobs which should be
+// migrated to correct skewed spreads, violated affinities and overly dense
+// nodes.
+type rebalancer struct {
+	ctx    *EvalContext
+	state  State
+	config *structs.SchedulerConfiguration
+
+	// nodes are all the nodes of the cluster while readyNodes are the ones
+	// allocations may be migrated to, both in the order of the state store
+	nodes      map[string]*structs.Node
+	readyNodes []*structs.Node
+
+	// allocs are the non-terminal allocations of every node. Allocations
+	// selected for migration are moved to the node they are expected to be
+	// placed on, if known.
+	allocs map[string][]*structs.Allocation
+
+	// groups are the task groups which may be rebalanced, by key
+	groups map[string]*rebalanceGroup
+
+	migrating  map[string]struct{}
+	migrations []*structs.RebalanceMigration
+}
+
+// rebalanceGroup is a task group whose allocations may be migrated.
+type rebalanceGroup struct {
+	job    *structs.Job
+	tg     *structs.TaskGroup
+	allocs []*structs.Allocation
+
+	// nodes are the ready nodes the task group may be placed on
+	nodes []*structs.Node
+
+	// budget is the number of allocations of the task group which may still
+	// be migrated, following the max_parallel of its migrate block
+	budget int
+}
+
+// FindRebalanceMigrations returns the allocations which should be migrated to
+// rebalance the cluster, following the rebalance configuration of the
+// scheduler configuration. Only the allocations of service jobs are migrated,
+// and never more at once per task group than its migrate block allows.
+func FindRebalanceMigrations(logger log.Logger, state State, config *structs.SchedulerConfiguration) ([]*structs.RebalanceMigration, error) {
+	if config == nil {
+		config = &structs.SchedulerConfiguration{}
+	}
+
+	r := &rebalancer{
+		ctx:       NewEvalContext(nil, state, &structs.Plan{}, logger),
+		state:     state,
+		config:    config,
+		nodes:     make(map[string]*structs.Node),
+		allocs:    make(map[string][]*structs.Allocation),
+		groups:    make(map[string]*rebalanceGroup),
+		migrating: make(map[string]struct{}),
+	}
+	if err := r.setNodes(); err != nil {
+		return nil, err
+	}
+	if err := r.setGroups(); err != nil {
+		return nil, err
+	}
+
+	for _, key := range r.groupKeys() {
+		g := r.groups[key]
+		r.rebalanceSpreads(g)
+		r.rebalanceAffinities(g)
+	}
+	if config.EffectiveSchedulerAlgorithm() == structs.SchedulerAlgorithmSpread {
+		r.rebalanceDensity()
+	}
+	return r.migrations, nil
+}
+
+// setNodes indexes the nodes of the cluster and their allocations.
+func (r *rebalancer) setNodes() error {
+	iter, err := r.state.Nodes(nil)
+	if err != nil {
+		return err
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		node := raw.(*structs.Node)
+		r.nodes[node.ID] = node
+		if node.Ready() {
+			r.readyNodes = append(r.readyNodes, node)
+		}
+
+		allocs, err := r.state.AllocsByNodeTerminal(nil, node.ID, false)
+		if err != nil {
+			return err
+		}
+		r.allocs[node.ID] = allocs
+	}
+	return nil
+}
+
+// setGroups finds the task groups whose allocations may be migrated. Task
+// groups are skipped while they are being updated, migrated or are not fully
+// running and healthy so that no more than max_parallel allocations are ever
+// migrated at once.
+func (r *rebalancer) setGroups() error {
+	byGroup := make(map[string][]*structs.Allocation)
+	for _, node := range r.nodes {
+		for _, alloc := range r.allocs[node.ID] {
+			if alloc.Job == nil || alloc.Job.Type != structs.JobTypeService {
+				continue
+			}
+			key := rebalanceGroupKey(alloc.Namespace, alloc.JobID, alloc.TaskGroup)
+			byGroup[key] = append(byGroup[key], alloc)
+		}
+	}
+
+	jobs := make(map[structs.NamespacedID]*structs.Job)
+	for key, allocs := range byGroup {
+		first := allocs[0]
+		jobID := structs.NamespacedID{Namespace: first.Namespace, ID: first.JobID}
+		job, ok := jobs[jobID]
+		if !ok {
+			var err error
+			job, err = r.rebalanceableJob(first.Namespace, first.JobID)
+			if err != nil {
+				return err
+			}
+			jobs[jobID] = job
+		}
+		if job == nil {
+			continue
+		}
+
+		tg := job.LookupTaskGroup(first.TaskGroup)
+		if tg == nil || tg.Count != len(allocs) {
+			continue
+		}
+		if !rebalanceableAllocs(job, allocs) {
+			continue
+		}
+
+		budget := 1
+		if tg.Migrate != nil && tg.Migrate.MaxParallel > 0 {
+			budget = tg.Migrate.MaxParallel
+		}
+
+		sort.Slice(allocs, func(i, j int) bool {
+			return allocs[i].CreateIndex < allocs[j].CreateIndex
+		})
+		r.groups[key] = &rebalanceGroup{
+			job:    job,
+			tg:     tg,
+			allocs: allocs,
+			nodes:  r.groupNodes(job, tg),
+			budget: budget,
+		}
+	}
+	return nil
+}
+
+// rebalanceableJob returns the job if its allocations may be migrated, or nil
+// if the job is stopped or has an active deployment.
+func (r *rebalancer) rebalanceableJob(namespace, jobID string) (*structs.Job, error) {
+	job, err := r.state.JobByID(nil, namespace, jobID)
+	if err != nil || job == nil || job.Stopped() || job.Type != structs.JobTypeService {
+		return nil, err
+	}
+
+	deployment, err := r.state.LatestDeploymentByJobID(nil, namespace, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if deployment != nil && deployment.Active() {
+		return nil, nil
+	}
+	return job, nil
+}
+
+// rebalanceableAllocs returns whether all the allocations of a task group
+// are running the current version of the job, are healthy and are not
+// already being migrated.
+func rebalanceableAllocs(job *structs.Job, allocs []*structs.Allocation) bool {
+	for _, alloc := range allocs {
+		switch {
+		case alloc.DesiredStatus != structs.AllocDesiredStatusRun,
+			alloc.ClientStatus != structs.AllocClientStatusRunning,
+			alloc.DesiredTransition.ShouldMigrate(),
+			!alloc.DeploymentStatus.IsHealthy(),
+			alloc.Job.Version != job.Version:
+			return false
+		}
+	}
+	return true
+}
+
+// groupNodes returns the ready nodes the task group may be placed on.
+func (r *rebalancer) groupNodes(job *structs.Job, tg *structs.TaskGroup) []*structs.Node {
+	dcs := make(map[string]struct{}, len(job.Datacenters))
+	for _, dc := range job.Datacenters {
+		dcs[dc] = struct{}{}
+	}
+
+	constraints := append([]*structs.Constraint{}, job.Constraints...)
+	constraints = append(constraints, tg.Constraints...)
+	for _, task := range tg.Tasks {
+		constraints = append(constraints, task.Constraints...)
+	}
+	pool := NewNodePoolChecker(r.ctx, job.NodePool)
+	checker := NewConstraintChecker(r.ctx, constraints)
+
+	var nodes []*structs.Node
+	for _, node := range r.readyNodes {
+		if _, ok := dcs[node.Datacenter]; !ok {
+			continue
+		}
+		if pool.Feasible(node) && checker.Feasible(node) {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// rebalanceSpreads migrates the allocations of the task group placed on the
+// most used values of its spreads until the skew of every spread is within
+// its max skew, or within one allocation for spreads without max skew.
+// Spreads with targets are not rebalanced.
+func (r *rebalancer) rebalanceSpreads(g *rebalanceGroup) {
+	spreads := append([]*structs.Spread{}, g.job.Spreads...)
+	spreads = append(spreads, g.tg.Spreads...)
+	for _, spread := range spreads {
+		if len(spread.SpreadTarget) != 0 {
+			continue
+		}
+		maxSkew := spread.MaxSkew
+		if maxSkew == 0 {
+			maxSkew = 1
+		}
+
+		domain := spreadDomain(g.nodes, spread.Attribute)
+		if len(domain) < 2 {
+			continue
+		}
+		values := make([]string, 0, len(domain))
+		for value := range domain {
+			values = append(values, value)
+		}
+		sort.Strings(values)
+
+		byValue := make(map[string][]*structs.Allocation)
+		for _, alloc := range g.allocs {
+			if r.isMigrating(alloc) {
+				continue
+			}
+			if value, ok := getProperty(r.nodes[alloc.NodeID], spread.Attribute); ok {
+				byValue[value] = append(byValue[value], alloc)
+			}
+		}
+
+		for r.canMigrate(g) {
+			most, least := values[0], values[0]
+			for _, value := range values {
+				if len(byValue[value]) > len(byValue[most]) {
+					most = value
+				}
+				if len(byValue[value]) < len(byValue[least]) {
+					least = value
+				}
+			}
+			if len(byValue[most])-len(byValue[least]) <= maxSkew {
+				break
+			}
+
+			// The allocation is expected to be placed on the least used
+			// value, whose node is left to the scheduler.
+			last := len(byValue[most]) - 1
+			alloc := byValue[most][last]
+			byValue[most] = byValue[most][:last]
+			byValue[least] = append(byValue[least], alloc)
+			r.migrate(g, alloc, nil, structs.RebalanceReasonSpread)
+		}
+	}
+}
+
+// rebalanceAffinities migrates the allocations of the task group placed on
+// nodes which match its affinities worse than another node the allocation
+// fits on.
+func (r *rebalancer) rebalanceAffinities(g *rebalanceGroup) {
+	affinities := append([]*structs.Affinity{}, g.job.Affinities...)
+	affinities = append(affinities, g.tg.Affinities...)
+	for _, task := range g.tg.Tasks {
+		affinities = append(affinities, task.Affinities...)
+	}
+	if len(affinities) == 0 {
+		return
+	}
+
+	for _, alloc := range g.allocs {
+		if !r.canMigrate(g) {
+			return
+		}
+		if r.isMigrating(alloc) {
+			continue
+		}
+
+		current := r.affinityScore(affinities, r.nodes[alloc.NodeID])
+		var best *structs.Node
+		bestScore := current
+		for _, node := range g.nodes {
+			if node.ID == alloc.NodeID {
+				continue
+			}
+			if score := r.affinityScore(affinities, node); score > bestScore && r.fits(node, alloc) {
+				best, bestScore = node, score
+			}
+		}
+		if best != nil {
+			r.migrate(g, alloc, best, structs.RebalanceReasonAffinity)
+		}
+	}
+}
+
+// affinityScore returns the sum of the weights of the affinities the node
+// matches.
+func (r *rebalancer) affinityScore(affinities []*structs.Affinity, node *structs.Node) int {
+	score := 0
+	for _, affinity := range affinities {
+		if matchesAffinity(r.ctx, affinity, node) {
+			score += int(affinity.Weight)
+		}
+	}
+	return score
+}
+
+// rebalanceDensity migrates allocations away from the nodes whose allocated
+// resources exceed the average of the ready nodes by more than the density
+// threshold, towards nodes below the average.
+func (r *rebalancer) rebalanceDensity() {
+	if len(r.readyNodes) < 2 {
+		return
+	}
+
+	densities := make(map[string]float64, len(r.readyNodes))
+	sum := 0.0
+	for _, node := range r.readyNodes {
+		densities[node.ID] = nodeDensity(node, r.allocs[node.ID])
+		sum += densities[node.ID]
+	}
+	mean := sum / float64(len(r.readyNodes))
+	limit := mean + r.config.RebalanceConfig.EffectiveDensityThreshold()
+
+	// Start with the densest nodes
+	nodes := append([]*structs.Node{}, r.readyNodes...)
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return densities[nodes[i].ID] > densities[nodes[j].ID]
+	})
+
+	for _, node := range nodes {
+		if densities[node.ID] <= limit {
+			break
+		}
+
+	ALLOCS:
+		for densities[node.ID] > limit && !r.full() {
+			for _, alloc := range r.allocs[node.ID] {
+				g := r.groups[rebalanceGroupKey(alloc.Namespace, alloc.JobID, alloc.TaskGroup)]
+				if g == nil || r.isMigrating(alloc) || !r.canMigrate(g) {
+					continue
+				}
+
+				// Pick the least dense node below the average
+				var dest *structs.Node
+				for _, option := range g.nodes {
+					if option.ID == node.ID || densities[option.ID] >= mean {
+						continue
+					}
+					if dest != nil && densities[option.ID] >= densities[dest.ID] {
+						continue
+					}
+					if r.fits(option, alloc) {
+						dest = option
+					}
+				}
+				if dest == nil {
+					continue
+				}
+
+				r.migrate(g, alloc, dest, structs.RebalanceReasonDensity)
+				densities[node.ID] = nodeDensity(node, r.allocs[node.ID])
+				densities[dest.ID] = nodeDensity(dest, r.allocs[dest.ID])
+				continue ALLOCS
+			}
+
+			// None of the allocations of the node can be migrated
+			break
+		}
+	}
+}
+
+// nodeDensity returns the fraction of the CPU or memory of the node,
+// whichever is highest, allocated to the allocations.
+func nodeDensity(node *structs.Node, allocs []*structs.Allocation) float64 {
+	used := new(structs.ComparableResources)
+	for _, alloc := range allocs {
+		used.Add(alloc.ComparableResources())
+	}
+
+	available := node.ComparableResources()
+	available.Subtract(node.ComparableReservedResources())
+	cpu := float64(available.Flattened.Cpu.CpuShares)
+	mem := float64(available.Flattened.Memory.MemoryMB)
+	if cpu <= 0 || mem <= 0 {
+		return 0
+	}
+	return math.Max(float64(used.Flattened.Cpu.CpuShares)/cpu, float64(used.Flattened.Memory.MemoryMB)/mem)
+}
+
+// fits returns whether the allocation fits on the node along with the
+// allocations of the node.
+func (r *rebalancer) fits(node *structs.Node, alloc *structs.Allocation) bool {
+	allocs := make([]*structs.Allocation, 0, len(r.allocs[node.ID])+1)
+	allocs = append(allocs, r.allocs[node.ID]...)
+	allocs = append(allocs, alloc)
+	fit, _, _, err := structs.AllocsFit(node, allocs, nil, true)
+	return err == nil && fit
+}
+
+// migrate selects the allocation for migration. If the node the allocation
+// is expected to be placed on is known, the allocation is accounted on it.
+func (r *rebalancer) migrate(g *rebalanceGroup, alloc *structs.Allocation, dest *structs.Node, reason string) {
+	r.migrating[alloc.ID] = struct{}{}
+	g.budget--
+	r.migrations = append(r.migrations, &structs.RebalanceMigration{
+		AllocID:   alloc.ID,
+		Namespace: alloc.Namespace,
+		JobID:     alloc.JobID,
+		TaskGroup: alloc.TaskGroup,
+		NodeID:    alloc.NodeID,
+		Reason:    reason,
+	})
+
+	remaining := make([]*structs.Allocation, 0, len(r.allocs[alloc.NodeID]))
+	for _, other := range r.allocs[alloc.NodeID] {
+		if other.ID != alloc.ID {
+			remaining = append(remaining, other)
+		}
+	}
+	r.allocs[alloc.NodeID] = remaining
+	if dest != nil {
+		r.allocs[dest.ID] = append(r.allocs[dest.ID], alloc)
+	}
+}
+
+func (r *rebalancer) isMigrating(alloc *structs.Allocation) bool {
+	_, ok := r.migrating[alloc.ID]
+	return ok
+}
+
+// canMigrate returns whether another allocation of the task group may be
+// migrated.
+func (r *rebalancer) canMigrate(g *rebalanceGroup) bool {
+	return g.budget > 0 && !r.full()
+}
+
+// full returns whether the maximum number of migrations has been reached.
+func (r *rebalancer) full() bool {
+	return len(r.migrations) >= r.config.RebalanceConfig.EffectiveMaxMigrations()
+}
+
+// groupKeys returns the keys of the task groups in a stable order.
+func (r *rebalancer) groupKeys() []string {
+	keys := make([]string, 0, len(r.groups))
+	for key := range r.groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func rebalanceGroupKey(namespace, jobID, taskGroup string) string {
+	return namespace + "\x00" + jobID + "\x00" + taskGroup
+}
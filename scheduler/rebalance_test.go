@@ -0,0 +1,238 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// rebalanceAllocs returns healthy running allocations of the first task group
+// of the job, one on each of the nodes.
+func rebalanceAllocs(job *structs.Job, nodes ...*structs.Node) []*structs.Allocation {
+	var allocs []*structs.Allocation
+	for _, node := range nodes {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.TaskGroup = job.TaskGroups[0].Name
+		alloc.NodeID = node.ID
+		alloc.ClientStatus = structs.AllocClientStatusRunning
+		alloc.DeploymentStatus = &structs.AllocDeploymentStatus{Healthy: helper.BoolToPtr(true)}
+		allocs = append(allocs, alloc)
+	}
+	return allocs
+}
+
+func TestFindRebalanceMigrations_Spread(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	var nodes []*structs.Node
+	for _, rack := range []string{"r1", "r1", "r2"} {
+		node := mock.Node()
+		node.Meta["rack"] = rack
+		nodes = append(nodes, node)
+		require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+	}
+
+	// Place every allocation on r1
+	job := mock.Job()
+	job.TaskGroups[0].Count = 4
+	job.TaskGroups[0].Migrate.MaxParallel = 4
+	job.TaskGroups[0].Spreads = []*structs.Spread{{
+		Attribute: "${meta.rack}",
+		Weight:    50,
+	}}
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+	allocs := rebalanceAllocs(job, nodes[0], nodes[0], nodes[1], nodes[1])
+	require.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), allocs))
+
+	// updateJob registers a new version of the job, which the allocations
+	// are updated to in place
+	updateJob := func(job *structs.Job) {
+		require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+		for i, alloc := range allocs {
+			alloc = alloc.Copy()
+			alloc.Job = job
+			allocs[i] = alloc
+		}
+		require.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), allocs))
+	}
+
+	// Only the allocations needed to even out the spread are migrated
+	migrations, err := FindRebalanceMigrations(testlog.HCLogger(t), h.State, &structs.SchedulerConfiguration{})
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+	for _, m := range migrations {
+		require.Equal(t, structs.RebalanceReasonSpread, m.Reason)
+		require.Equal(t, job.ID, m.JobID)
+		require.NotEqual(t, nodes[2].ID, m.NodeID)
+	}
+
+	// The max parallel of the migrate block is respected
+	job = job.Copy()
+	job.TaskGroups[0].Migrate.MaxParallel = 1
+	updateJob(job)
+	migrations, err = FindRebalanceMigrations(testlog.HCLogger(t), h.State, &structs.SchedulerConfiguration{})
+	require.NoError(t, err)
+	require.Len(t, migrations, 1)
+
+	// Spreads within their max skew are not rebalanced
+	job = job.Copy()
+	job.TaskGroups[0].Spreads[0].MaxSkew = 4
+	updateJob(job)
+	migrations, err = FindRebalanceMigrations(testlog.HCLogger(t), h.State, &structs.SchedulerConfiguration{})
+	require.NoError(t, err)
+	require.Empty(t, migrations)
+}
+
+func TestFindRebalanceMigrations_Affinity(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	hdd := mock.Node()
+	hdd.Meta["disk"] = "hdd"
+	ssd := mock.Node()
+	ssd.Meta["disk"] = "ssd"
+	for _, node := range []*structs.Node{hdd, ssd} {
+		require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+	}
+
+	job := mock.Job()
+	job.TaskGroups[0].Count = 1
+	job.TaskGroups[0].Affinities = []*structs.Affinity{{
+		LTarget: "${meta.disk}",
+		RTarget: "ssd",
+		Operand: "=",
+		Weight:  100,
+	}}
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+	allocs := rebalanceAllocs(job, hdd)
+	require.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), allocs))
+
+	migrations, err := FindRebalanceMigrations(testlog.HCLogger(t), h.State, &structs.SchedulerConfiguration{})
+	require.NoError(t, err)
+	require.Equal(t, []*structs.RebalanceMigration{{
+		AllocID:   allocs[0].ID,
+		Namespace: job.Namespace,
+		JobID:     job.ID,
+		TaskGroup: job.TaskGroups[0].Name,
+		NodeID:    hdd.ID,
+		Reason:    structs.RebalanceReasonAffinity,
+	}}, migrations)
+
+	// Allocations are not migrated to nodes they do not fit on
+	ssd = ssd.Copy()
+	ssd.NodeResources.Cpu.CpuShares = 200
+	require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), ssd))
+	migrations, err = FindRebalanceMigrations(testlog.HCLogger(t), h.State, &structs.SchedulerConfiguration{})
+	require.NoError(t, err)
+	require.Empty(t, migrations)
+}
+
+func TestFindRebalanceMigrations_Density(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	var nodes []*structs.Node
+	for i := 0; i < 3; i++ {
+		node := mock.Node()
+		nodes = append(nodes, node)
+		require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+	}
+
+	// Place every allocation on the first node
+	job := mock.Job()
+	job.TaskGroups[0].Count = 6
+	job.TaskGroups[0].Migrate.MaxParallel = 6
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+	allocs := rebalanceAllocs(job, nodes[0], nodes[0], nodes[0], nodes[0], nodes[0], nodes[0])
+	require.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), allocs))
+
+	// Dense nodes are only rebalanced with the spread algorithm
+	config := &structs.SchedulerConfiguration{}
+	migrations, err := FindRebalanceMigrations(testlog.HCLogger(t), h.State, config)
+	require.NoError(t, err)
+	require.Empty(t, migrations)
+
+	// The node is 77% allocated for an average of 26%, so allocations are
+	// migrated until it is within 25% of the average
+	config.SchedulerAlgorithm = structs.SchedulerAlgorithmSpread
+	migrations, err = FindRebalanceMigrations(testlog.HCLogger(t), h.State, config)
+	require.NoError(t, err)
+	require.Len(t, migrations, 3)
+	for _, m := range migrations {
+		require.Equal(t, structs.RebalanceReasonDensity, m.Reason)
+		require.Equal(t, nodes[0].ID, m.NodeID)
+	}
+
+	// The maximum number of migrations is respected
+	config.RebalanceConfig.MaxMigrations = 1
+	migrations, err = FindRebalanceMigrations(testlog.HCLogger(t), h.State, config)
+	require.NoError(t, err)
+	require.Len(t, migrations, 1)
+}
+
+func TestFindRebalanceMigrations_Skip(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	var nodes []*structs.Node
+	for _, rack := range []string{"r1", "r2"} {
+		node := mock.Node()
+		node.Meta["rack"] = rack
+		nodes = append(nodes, node)
+		require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+	}
+
+	job := mock.Job()
+	job.TaskGroups[0].Count = 3
+	job.TaskGroups[0].Spreads = []*structs.Spread{{
+		Attribute: "${meta.rack}",
+		Weight:    50,
+	}}
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+	// An allocation whose health is not yet known
+	allocs := rebalanceAllocs(job, nodes[0], nodes[0], nodes[0])
+	allocs[2].DeploymentStatus = nil
+	require.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), allocs))
+
+	migrations, err := FindRebalanceMigrations(testlog.HCLogger(t), h.State, &structs.SchedulerConfiguration{})
+	require.NoError(t, err)
+	require.Empty(t, migrations)
+
+	// An allocation already being migrated
+	allocs[2] = allocs[2].Copy()
+	allocs[2].DeploymentStatus = &structs.AllocDeploymentStatus{Healthy: helper.BoolToPtr(true)}
+	allocs[2].DesiredTransition.Migrate = helper.BoolToPtr(true)
+	require.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), allocs[2:]))
+
+	migrations, err = FindRebalanceMigrations(testlog.HCLogger(t), h.State, &structs.SchedulerConfiguration{})
+	require.NoError(t, err)
+	require.Empty(t, migrations)
+
+	// An active deployment
+	allocs[2] = allocs[2].Copy()
+	allocs[2].DesiredTransition = structs.DesiredTransition{}
+	require.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), allocs[2:]))
+
+	deployment := mock.Deployment()
+	deployment.ID = uuid.Generate()
+	deployment.JobID = job.ID
+	require.NoError(t, h.State.UpsertDeployment(h.NextIndex(), deployment))
+
+	migrations, err = FindRebalanceMigrations(testlog.HCLogger(t), h.State, &structs.SchedulerConfiguration{})
+	require.NoError(t, err)
+	require.Empty(t, migrations)
+}
@@ -103,6 +103,19 @@ type State interface {
 	// SchedulerConfig returns config options for the scheduler
 	SchedulerConfig() (uint64, *structs.SchedulerConfiguration, error)
 
+	// NodePoolByName is used to lookup a node pool by name
+	NodePoolByName(ws memdb.WatchSet, name string) (*structs.NodePool, error)
+
+	// NamespaceByName is used to lookup a namespace by name
+	NamespaceByName(ws memdb.WatchSet, name string) (*structs.Namespace, error)
+
+	// QuotaSpecByName is used to lookup a quota specification by name
+	QuotaSpecByName(ws memdb.WatchSet, name string) (*structs.QuotaSpec, error)
+
+	// QuotaUsageByName returns the usage of a quota specification in the
+	// region
+	QuotaUsageByName(ws memdb.WatchSet, name string) (*structs.QuotaUsage, error)
+
 	// CSIVolumeByID fetch CSI volumes, containing controller jobs
 	CSIVolumeByID(memdb.WatchSet, string, string) (*structs.CSIVolume, error)
 
@@ -0,0 +1,376 @@
+package scheduler
+
+import (
+	"fmt"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/nomad/structs"
+	schedplugin "github.com/hashicorp/nomad/plugins/scheduler"
+)
+
+const (
+	// maxPluginScheduleAttempts is used to limit the number of times we will
+	// ask a scheduler plugin for a plan if we continue to hit conflicts.
+	maxPluginScheduleAttempts = 5
+)
+
+// PluginScheduler is used for jobs which set their scheduler to the name of
+// an external scheduler plugin. The plugin computes the plan from a read-only
+// view of the state, and the plan is then submitted like the plans of the
+// built-in schedulers.
+type PluginScheduler struct {
+	name    string
+	plugin  schedplugin.SchedulerPlugin
+	logger  log.Logger
+	state   State
+	planner Planner
+
+	eval       *structs.Evaluation
+	blocked    *structs.Evaluation
+	planResult *structs.PlanResult
+
+	failedTGAllocs map[string]*structs.AllocMetric
+	queuedAllocs   map[string]int
+}
+
+// NewPluginScheduler is a factory function to instantiate a scheduler backed
+// by the named scheduler plugin.
+func NewPluginScheduler(name string, plugin schedplugin.SchedulerPlugin, logger log.Logger, state State, planner Planner) Scheduler {
+	return &PluginScheduler{
+		name:    name,
+		plugin:  plugin,
+		logger:  logger.Named("plugin_sched").With("plugin", name),
+		state:   state,
+		planner: planner,
+	}
+}
+
+// Process is used to handle a single evaluation.
+func (s *PluginScheduler) Process(eval *structs.Evaluation) error {
+	s.eval = eval
+	s.logger = s.logger.With("eval_id", eval.ID, "job_id", eval.JobID, "namespace", eval.Namespace)
+
+	// Retry up to the maxPluginScheduleAttempts and reset if progress is made.
+	progress := func() bool { return progressMade(s.planResult) }
+	if err := retryMax(maxPluginScheduleAttempts, s.process, progress); err != nil {
+		if statusErr, ok := err.(*SetStatusError); ok {
+			// Scheduling was tried but made no forward progress so create a
+			// blocked eval to retry the failed placements once resources
+			// become available. Invalid plans leave no failed placements.
+			var mErr multierror.Error
+			if len(s.failedTGAllocs) != 0 && s.blocked == nil {
+				if err := s.createBlockedEval(true); err != nil {
+					mErr.Errors = append(mErr.Errors, err)
+				}
+			}
+			if err := setStatus(s.logger, s.planner, s.eval, nil, s.blocked,
+				s.failedTGAllocs, statusErr.EvalStatus, err.Error(),
+				s.queuedAllocs, ""); err != nil {
+				mErr.Errors = append(mErr.Errors, err)
+			}
+			return mErr.ErrorOrNil()
+		}
+		return err
+	}
+
+	// If the current evaluation is a blocked evaluation and we didn't place
+	// everything, do not update the status to complete.
+	if s.eval.Status == structs.EvalStatusBlocked && len(s.failedTGAllocs) != 0 {
+		newEval := s.eval.Copy()
+		newEval.EscapedComputedClass = true
+		newEval.ClassEligibility = nil
+		return s.planner.ReblockEval(newEval)
+	}
+
+	// Update the status to complete
+	return setStatus(s.logger, s.planner, s.eval, nil, s.blocked,
+		s.failedTGAllocs, structs.EvalStatusComplete, "", s.queuedAllocs, "")
+}
+
+// createBlockedEval creates a blocked eval and submits it to the planner. If
+// failure is set to true, the eval's trigger reason reflects that. As the
+// plugin does not report the nodes it considered, the blocked eval is
+// unblocked by changes to any node.
+func (s *PluginScheduler) createBlockedEval(planFailure bool) error {
+	s.blocked = s.eval.CreateBlockedEval(nil, true, "", s.failedTGAllocs)
+	if planFailure {
+		s.blocked.TriggeredBy = structs.EvalTriggerMaxPlans
+		s.blocked.StatusDescription = blockedEvalMaxPlanDesc
+	} else {
+		s.blocked.StatusDescription = blockedEvalFailedPlacements
+	}
+
+	return s.planner.CreateEval(s.blocked)
+}
+
+// process asks the plugin for a plan and submits it. It returns whether the
+// plan was fully committed.
+func (s *PluginScheduler) process() (bool, error) {
+	// Reset the failed allocations
+	s.failedTGAllocs = nil
+
+	plan, err := s.plugin.Process(s.eval, &stateView{state: s.state})
+	if err != nil {
+		return false, fmt.Errorf("scheduler plugin %q failed to process evaluation: %v", s.name, err)
+	}
+
+	// Plans which touch anything but the job of the evaluation are rejected,
+	// as the plan applier trusts the plans it is given
+	if err := s.validatePlan(plan); err != nil {
+		return false, &SetStatusError{
+			Err:        fmt.Errorf("scheduler plugin %q returned an invalid plan: %v", s.name, err),
+			EvalStatus: structs.EvalStatusFailed,
+		}
+	}
+
+	// The plan is always attributed to the evaluation being processed and
+	// its job as found in the state
+	plan.EvalID = s.eval.ID
+	plan.Priority = s.eval.Priority
+	plan.Job, err = s.state.JobByID(nil, s.eval.Namespace, s.eval.JobID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get job %q: %v", s.eval.JobID, err)
+	}
+
+	// Placed allocations run the job as found in the state rather than a
+	// copy which may have been modified by the plugin
+	for _, allocs := range plan.NodeAllocation {
+		for _, alloc := range allocs {
+			if plan.Job == nil {
+				return false, &SetStatusError{
+					Err:        fmt.Errorf("scheduler plugin %q placed allocations of deregistered job %q", s.name, s.eval.JobID),
+					EvalStatus: structs.EvalStatusFailed,
+				}
+			}
+			alloc.Job = plan.Job
+		}
+	}
+
+	if err := s.computeQueuedAllocs(plan); err != nil {
+		return false, err
+	}
+
+	// If the plugin failed to place allocations, we need to create a blocked
+	// evaluation to place them when resources become available. If the
+	// current evaluation is already a blocked eval, we reuse it.
+	if s.eval.Status != structs.EvalStatusBlocked && len(s.failedTGAllocs) != 0 && s.blocked == nil {
+		if err := s.createBlockedEval(false); err != nil {
+			s.logger.Error("failed to make blocked eval", "error", err)
+			return false, err
+		}
+		s.logger.Debug("failed to place all allocations, blocked eval created", "blocked_eval_id", s.blocked.ID)
+	}
+
+	// Nothing to do
+	if plan.IsNoOp() {
+		return true, nil
+	}
+
+	result, newState, err := s.planner.SubmitPlan(plan)
+	s.planResult = result
+	if err != nil {
+		return false, err
+	}
+
+	// Decrement the number of allocations pending per task group based on the
+	// number of allocations successfully placed
+	adjustQueuedAllocations(s.logger, result, s.queuedAllocs)
+
+	// If we got a state refresh, try again since we have stale data
+	if newState != nil {
+		s.logger.Debug("refresh forced")
+		s.state = newState
+		return false, nil
+	}
+
+	// Try again if the plan was not fully committed, potential conflict
+	fullCommit, expected, actual := result.FullCommit(plan)
+	if !fullCommit {
+		s.logger.Debug("plan didn't fully commit", "attempted", expected, "placed", actual)
+		return false, fmt.Errorf("missing state refresh after partial commit")
+	}
+
+	return true, nil
+}
+
+// computeQueuedAllocs records the number of allocations each task group of the
+// job is missing before the plan is applied, and the task groups the plan of
+// the plugin fails to place entirely as failed placements.
+func (s *PluginScheduler) computeQueuedAllocs(plan *structs.Plan) error {
+	s.queuedAllocs = make(map[string]int)
+	if plan.Job == nil || plan.Job.Stopped() {
+		return nil
+	}
+
+	allocs, err := s.state.AllocsByJob(nil, s.eval.Namespace, s.eval.JobID, false)
+	if err != nil {
+		return fmt.Errorf("failed to get allocs for job %q: %v", s.eval.JobID, err)
+	}
+
+	// Allocations stopped or preempted by the plan are not running anymore
+	stopped := make(map[string]struct{})
+	for _, updates := range []map[string][]*structs.Allocation{plan.NodeUpdate, plan.NodePreemptions} {
+		for _, nodeAllocs := range updates {
+			for _, alloc := range nodeAllocs {
+				stopped[alloc.ID] = struct{}{}
+			}
+		}
+	}
+
+	existing := make(map[string]struct{})
+	running := make(map[string]int)
+	for _, alloc := range allocs {
+		if _, ok := stopped[alloc.ID]; ok || alloc.TerminalStatus() {
+			continue
+		}
+		existing[alloc.ID] = struct{}{}
+		running[alloc.TaskGroup]++
+	}
+
+	// In-place updates of running allocations are not placements
+	placed := make(map[string]int)
+	for _, nodeAllocs := range plan.NodeAllocation {
+		for _, alloc := range nodeAllocs {
+			if _, ok := existing[alloc.ID]; !ok {
+				placed[alloc.TaskGroup]++
+			}
+		}
+	}
+
+	for _, tg := range plan.Job.TaskGroups {
+		missing := tg.Count - running[tg.Name]
+		if missing <= 0 {
+			s.queuedAllocs[tg.Name] = 0
+			continue
+		}
+		s.queuedAllocs[tg.Name] = missing
+
+		if unplaced := missing - placed[tg.Name]; unplaced > 0 {
+			if s.failedTGAllocs == nil {
+				s.failedTGAllocs = make(map[string]*structs.AllocMetric)
+			}
+			s.failedTGAllocs[tg.Name] = &structs.AllocMetric{
+				CoalescedFailures: unplaced - 1,
+			}
+		}
+	}
+	return nil
+}
+
+// validatePlan returns an error if the plan returned by the plugin sets a job
+// other than the job of the evaluation, places or stops allocations of other
+// jobs or on unknown nodes, or preempts allocations which don't exist.
+func (s *PluginScheduler) validatePlan(plan *structs.Plan) error {
+	if plan.Job != nil && (plan.Job.Namespace != s.eval.Namespace || plan.Job.ID != s.eval.JobID) {
+		return fmt.Errorf("plan is for job %q in namespace %q", plan.Job.ID, plan.Job.Namespace)
+	}
+
+	// ownAlloc checks the allocation belongs to the job of the evaluation and
+	// to the node it is planned on
+	ownAlloc := func(nodeID string, alloc *structs.Allocation) error {
+		if alloc.Namespace != s.eval.Namespace || alloc.JobID != s.eval.JobID {
+			return fmt.Errorf("allocation %q is for job %q in namespace %q", alloc.ID, alloc.JobID, alloc.Namespace)
+		}
+		if alloc.Job != nil && (alloc.Job.Namespace != s.eval.Namespace || alloc.Job.ID != s.eval.JobID) {
+			return fmt.Errorf("allocation %q is for job %q in namespace %q", alloc.ID, alloc.Job.ID, alloc.Job.Namespace)
+		}
+		if alloc.NodeID != nodeID {
+			return fmt.Errorf("allocation %q is for node %q but planned on node %q", alloc.ID, alloc.NodeID, nodeID)
+		}
+		return nil
+	}
+
+	// existingAlloc checks the allocation exists on the node it is planned on
+	existingAlloc := func(nodeID string, alloc *structs.Allocation) error {
+		existing, err := s.state.AllocByID(nil, alloc.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get allocation %q: %v", alloc.ID, err)
+		}
+		if existing == nil || existing.NodeID != nodeID {
+			return fmt.Errorf("allocation %q does not exist on node %q", alloc.ID, nodeID)
+		}
+		return nil
+	}
+
+	for nodeID, allocs := range plan.NodeAllocation {
+		node, err := s.state.NodeByID(nil, nodeID)
+		if err != nil {
+			return fmt.Errorf("failed to get node %q: %v", nodeID, err)
+		}
+		if node == nil {
+			return fmt.Errorf("node %q does not exist", nodeID)
+		}
+		for _, alloc := range allocs {
+			if err := ownAlloc(nodeID, alloc); err != nil {
+				return err
+			}
+		}
+	}
+
+	for nodeID, allocs := range plan.NodeUpdate {
+		for _, alloc := range allocs {
+			if err := ownAlloc(nodeID, alloc); err != nil {
+				return err
+			}
+			if err := existingAlloc(nodeID, alloc); err != nil {
+				return err
+			}
+		}
+	}
+
+	for nodeID, allocs := range plan.NodePreemptions {
+		for _, alloc := range allocs {
+			if err := existingAlloc(nodeID, alloc); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// stateView exposes the state an evaluation is processed against to the
+// scheduler plugin processing it. Nodes are sanitized so that their secret ID
+// is not exposed to the plugin.
+type stateView struct {
+	state State
+}
+
+func (s *stateView) Nodes() ([]*structs.Node, error) {
+	iter, err := s.state.Nodes(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []*structs.Node
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		nodes = append(nodes, raw.(*structs.Node).Sanitize())
+	}
+	return nodes, nil
+}
+
+func (s *stateView) NodeByID(nodeID string) (*structs.Node, error) {
+	node, err := s.state.NodeByID(nil, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	return node.Sanitize(), nil
+}
+
+func (s *stateView) JobByID(namespace, jobID string) (*structs.Job, error) {
+	return s.state.JobByID(nil, namespace, jobID)
+}
+
+func (s *stateView) AllocsByJob(namespace, jobID string, all bool) ([]*structs.Allocation, error) {
+	return s.state.AllocsByJob(nil, namespace, jobID, all)
+}
+
+func (s *stateView) AllocsByNode(nodeID string) ([]*structs.Allocation, error) {
+	return s.state.AllocsByNode(nil, nodeID)
+}
+
+func (s *stateView) SchedulerConfig() (*structs.SchedulerConfiguration, error) {
+	_, config, err := s.state.SchedulerConfig()
+	return config, err
+}
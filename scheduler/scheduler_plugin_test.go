@@ -0,0 +1,301 @@
+package scheduler
+
+import (
+	"fmt"
+	"testing"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/base"
+	schedplugin "github.com/hashicorp/nomad/plugins/scheduler"
+	"github.com/stretchr/testify/require"
+)
+
+func pluginSchedulerFactory(plugin schedplugin.SchedulerPlugin) Factory {
+	return func(logger log.Logger, _ chan<- interface{}, state State, planner Planner) Scheduler {
+		return NewPluginScheduler("mock", plugin, logger, state, planner)
+	}
+}
+
+func TestPluginScheduler_Process(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	node := mock.Node()
+	require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+
+	job := mock.Job()
+	job.Scheduler = "mock"
+	job.TaskGroups[0].Count = 1
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+
+	// The plugin places a single allocation on the only node
+	plugin := &schedplugin.MockSchedulerPlugin{
+		MockPlugin: &base.MockPlugin{},
+		ProcessF: func(e *structs.Evaluation, state schedplugin.StateView) (*structs.Plan, error) {
+			nodes, err := state.Nodes()
+			if err != nil {
+				return nil, err
+			}
+			if nodes[0].SecretID != "" {
+				return nil, fmt.Errorf("node secret ID exposed to plugin")
+			}
+			j, err := state.JobByID(e.Namespace, e.JobID)
+			if err != nil {
+				return nil, err
+			}
+
+			alloc := mock.Alloc()
+			alloc.Job = j
+			alloc.JobID = j.ID
+			alloc.NodeID = nodes[0].ID
+			return &structs.Plan{
+				NodeAllocation: map[string][]*structs.Allocation{
+					nodes[0].ID: {alloc},
+				},
+			}, nil
+		},
+	}
+	require.NoError(t, h.Process(pluginSchedulerFactory(plugin), eval))
+
+	// The plan is attributed to the evaluation and the job
+	require.Len(t, h.Plans, 1)
+	plan := h.Plans[0]
+	require.Equal(t, eval.ID, plan.EvalID)
+	require.Equal(t, eval.Priority, plan.Priority)
+	require.Equal(t, job.ID, plan.Job.ID)
+	require.Len(t, plan.NodeAllocation[node.ID], 1)
+
+	allocs, err := h.State.AllocsByJob(nil, job.Namespace, job.ID, false)
+	require.NoError(t, err)
+	require.Len(t, allocs, 1)
+
+	// The task group is entirely placed so no blocked eval is created
+	h.AssertEvalStatus(t, structs.EvalStatusComplete)
+	require.Empty(t, h.CreateEvals)
+	require.Empty(t, h.Evals[0].FailedTGAllocs)
+	require.Equal(t, 0, h.Evals[0].QueuedAllocations["web"])
+}
+
+func TestPluginScheduler_Process_BlockedEval(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	node := mock.Node()
+	require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+
+	job := mock.Job()
+	job.Scheduler = "mock"
+	job.TaskGroups[0].Count = 3
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+	eval := mock.Eval()
+	eval.JobID = job.ID
+	require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+
+	// The plugin only places one of the three allocations
+	plugin := &schedplugin.MockSchedulerPlugin{
+		MockPlugin: &base.MockPlugin{},
+		ProcessF: func(e *structs.Evaluation, state schedplugin.StateView) (*structs.Plan, error) {
+			j, err := state.JobByID(e.Namespace, e.JobID)
+			if err != nil {
+				return nil, err
+			}
+
+			alloc := mock.Alloc()
+			alloc.Job = j
+			alloc.JobID = j.ID
+			alloc.NodeID = node.ID
+			return &structs.Plan{
+				NodeAllocation: map[string][]*structs.Allocation{
+					node.ID: {alloc},
+				},
+			}, nil
+		},
+	}
+	require.NoError(t, h.Process(pluginSchedulerFactory(plugin), eval))
+	require.Len(t, h.Plans, 1)
+
+	// A blocked eval is created for the failed placements
+	require.Len(t, h.CreateEvals, 1)
+	blocked := h.CreateEvals[0]
+	require.Equal(t, structs.EvalStatusBlocked, blocked.Status)
+	require.Equal(t, structs.EvalTriggerQueuedAllocs, blocked.TriggeredBy)
+	require.Equal(t, eval.ID, blocked.PreviousEval)
+	require.True(t, blocked.EscapedComputedClass)
+	require.Contains(t, blocked.FailedTGAllocs, "web")
+
+	// The evaluation records the blocked eval and the queued allocations
+	h.AssertEvalStatus(t, structs.EvalStatusComplete)
+	update := h.Evals[0]
+	require.Equal(t, blocked.ID, update.BlockedEval)
+	require.Equal(t, 2, update.QueuedAllocations["web"])
+	require.Contains(t, update.FailedTGAllocs, "web")
+	require.Equal(t, 1, update.FailedTGAllocs["web"].CoalescedFailures)
+}
+
+func TestPluginScheduler_Process_Error(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	job := mock.Job()
+	job.Scheduler = "mock"
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+	eval := mock.Eval()
+	eval.JobID = job.ID
+
+	plugin := &schedplugin.MockSchedulerPlugin{
+		MockPlugin: &base.MockPlugin{},
+		ProcessF: func(*structs.Evaluation, schedplugin.StateView) (*structs.Plan, error) {
+			return nil, fmt.Errorf("plugin crashed")
+		},
+	}
+
+	// Errors of the plugin are returned so the evaluation is retried
+	err := h.Process(pluginSchedulerFactory(plugin), eval)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "plugin crashed")
+	require.Empty(t, h.Plans)
+	require.Empty(t, h.Evals)
+}
+
+func TestPluginScheduler_Process_NoOp(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	job := mock.Job()
+	job.Scheduler = "mock"
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+	eval := mock.Eval()
+	eval.JobID = job.ID
+
+	plugin := &schedplugin.MockSchedulerPlugin{
+		MockPlugin: &base.MockPlugin{},
+		ProcessF: func(*structs.Evaluation, schedplugin.StateView) (*structs.Plan, error) {
+			return &structs.Plan{}, nil
+		},
+	}
+
+	// Empty plans are not submitted
+	require.NoError(t, h.Process(pluginSchedulerFactory(plugin), eval))
+	require.Empty(t, h.Plans)
+	h.AssertEvalStatus(t, structs.EvalStatusComplete)
+}
+
+func TestPluginScheduler_Process_InvalidPlan(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name string
+		plan func(job *structs.Job, node *structs.Node, existing *structs.Allocation) *structs.Plan
+		err  string
+	}{
+		{
+			name: "other job",
+			plan: func(job *structs.Job, node *structs.Node, existing *structs.Allocation) *structs.Plan {
+				return &structs.Plan{Job: mock.Job()}
+			},
+			err: "plan is for job",
+		},
+		{
+			name: "placement of other job",
+			plan: func(job *structs.Job, node *structs.Node, existing *structs.Allocation) *structs.Plan {
+				alloc := mock.Alloc()
+				alloc.NodeID = node.ID
+				return &structs.Plan{
+					NodeAllocation: map[string][]*structs.Allocation{node.ID: {alloc}},
+				}
+			},
+			err: "allocation",
+		},
+		{
+			name: "placement on unknown node",
+			plan: func(job *structs.Job, node *structs.Node, existing *structs.Allocation) *structs.Plan {
+				alloc := mock.Alloc()
+				alloc.JobID = job.ID
+				alloc.Job = job
+				alloc.NodeID = "unknown"
+				return &structs.Plan{
+					NodeAllocation: map[string][]*structs.Allocation{"unknown": {alloc}},
+				}
+			},
+			err: "does not exist",
+		},
+		{
+			name: "stop of other job",
+			plan: func(job *structs.Job, node *structs.Node, existing *structs.Allocation) *structs.Plan {
+				return &structs.Plan{
+					NodeUpdate: map[string][]*structs.Allocation{node.ID: {existing}},
+				}
+			},
+			err: "allocation",
+		},
+		{
+			name: "unknown preemption",
+			plan: func(job *structs.Job, node *structs.Node, existing *structs.Allocation) *structs.Plan {
+				alloc := mock.Alloc()
+				alloc.NodeID = node.ID
+				return &structs.Plan{
+					NodePreemptions: map[string][]*structs.Allocation{node.ID: {alloc}},
+				}
+			},
+			err: "does not exist",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewHarness(t)
+
+			node := mock.Node()
+			require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+
+			job := mock.Job()
+			job.Scheduler = "mock"
+			require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+			// An allocation of another job runs on the node
+			existing := mock.Alloc()
+			existing.NodeID = node.ID
+			require.NoError(t, h.State.UpsertJobSummary(h.NextIndex(), mock.JobSummary(existing.JobID)))
+			require.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Allocation{existing}))
+
+			eval := mock.Eval()
+			eval.JobID = job.ID
+			require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+
+			plugin := &schedplugin.MockSchedulerPlugin{
+				MockPlugin: &base.MockPlugin{},
+				ProcessF: func(*structs.Evaluation, schedplugin.StateView) (*structs.Plan, error) {
+					return tc.plan(job, node, existing), nil
+				},
+			}
+
+			// Invalid plans are not submitted and fail the evaluation
+			require.NoError(t, h.Process(pluginSchedulerFactory(plugin), eval))
+			require.Empty(t, h.Plans)
+			require.Len(t, h.Evals, 1)
+			require.Equal(t, structs.EvalStatusFailed, h.Evals[0].Status)
+			require.Contains(t, h.Evals[0].StatusDescription, tc.err)
+		})
+	}
+}
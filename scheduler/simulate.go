@@ -0,0 +1,296 @@
+package scheduler
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Simulation is a Planner which evaluates and applies plans to its state
+// store rather than submitting them to the leader. Like the Harness it allows
+// the schedulers to be invoked without side effects, for example against the
+// state restored from a snapshot of a cluster. Jobs using scheduler plugins
+// cannot be simulated as the plugins are only loaded by the servers.
+type Simulation struct {
+	State  *state.StateStore
+	logger log.Logger
+
+	planLock sync.Mutex
+
+	Plans       []*structs.Plan
+	Evals       []*structs.Evaluation
+	CreateEvals []*structs.Evaluation
+
+	nextIndex     uint64
+	nextIndexLock sync.Mutex
+}
+
+// NewSimulation returns a Simulation applying plans to the given state. The
+// indexes it uses follow the latest index of the state.
+func NewSimulation(logger log.Logger, state *state.StateStore) (*Simulation, error) {
+	index, err := state.LatestIndex()
+	if err != nil {
+		return nil, fmt.Errorf("failed to lookup latest index: %v", err)
+	}
+
+	return &Simulation{
+		State:     state,
+		logger:    logger.Named("simulation"),
+		nextIndex: index + 1,
+	}, nil
+}
+
+// NextIndex returns the next index
+func (s *Simulation) NextIndex() uint64 {
+	s.nextIndexLock.Lock()
+	defer s.nextIndexLock.Unlock()
+	idx := s.nextIndex
+	s.nextIndex += 1
+	return idx
+}
+
+// Register upserts the job into the state and returns the evaluation its
+// registration creates. Periodic and parameterized jobs are rejected as
+// their registration does not create evaluations, as are jobs using
+// scheduler plugins.
+func (s *Simulation) Register(job *structs.Job) (*structs.Evaluation, error) {
+	if job.IsPeriodic() || job.IsParameterized() {
+		return nil, fmt.Errorf("periodic and parameterized jobs cannot be simulated")
+	}
+	if job.Scheduler != "" {
+		return nil, fmt.Errorf("jobs using scheduler plugins cannot be simulated")
+	}
+
+	if err := s.State.UpsertJob(structs.JobRegisterRequestType, s.NextIndex(), job); err != nil {
+		return nil, fmt.Errorf("failed to register job: %v", err)
+	}
+
+	now := time.Now().UTC().UnixNano()
+	eval := &structs.Evaluation{
+		ID:          uuid.Generate(),
+		Namespace:   job.Namespace,
+		Priority:    job.Priority,
+		Type:        job.Type,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+		CreateTime:  now,
+		ModifyTime:  now,
+	}
+	if err := s.State.UpsertEvals(structs.EvalUpdateRequestType, s.NextIndex(), []*structs.Evaluation{eval}); err != nil {
+		return nil, fmt.Errorf("failed to create evaluation: %v", err)
+	}
+	return eval, nil
+}
+
+// Process processes the evaluation with the scheduler of its type against a
+// snapshot of the current state. Evaluations of jobs using scheduler plugins
+// are rejected.
+func (s *Simulation) Process(eval *structs.Evaluation) error {
+	snap, err := s.State.Snapshot()
+	if err != nil {
+		return fmt.Errorf("failed to snapshot state: %v", err)
+	}
+
+	job, err := snap.JobByID(nil, eval.Namespace, eval.JobID)
+	if err != nil {
+		return fmt.Errorf("failed to lookup job: %v", err)
+	}
+	if job != nil && job.Scheduler != "" {
+		return fmt.Errorf("jobs using scheduler plugins cannot be simulated")
+	}
+
+	sched, err := NewScheduler(eval.Type, s.logger, nil, snap, s)
+	if err != nil {
+		return err
+	}
+	return sched.Process(eval)
+}
+
+// SubmitPlan evaluates the plan against the current state like the plan
+// applier of the leader and applies the result to the state. Plans exceeding
+// the quota of their namespace are rejected and nodes whose preemptions
+// exceed disruption budgets are rejected, along with the all or nothing
+// placements and canaries depending on them. As the plans are made against
+// the latest state one at a time, placements are not checked again against
+// the resources of their nodes.
+func (s *Simulation) SubmitPlan(plan *structs.Plan) (*structs.PlanResult, State, error) {
+	// Ensure sequential plan application
+	s.planLock.Lock()
+	defer s.planLock.Unlock()
+
+	s.Plans = append(s.Plans, plan)
+
+	snap, err := s.State.Snapshot()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to snapshot state: %v", err)
+	}
+
+	result, err := s.evaluatePlan(snap, plan)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !result.IsNoOp() {
+		result.AllocIndex = s.NextIndex()
+		if err := s.applyPlan(plan, result); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Force the scheduler to refresh its state when the plan was not
+	// applied in full
+	if result.RefreshIndex != 0 {
+		refreshed, err := s.State.Snapshot()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to snapshot state: %v", err)
+		}
+		return result, refreshed, nil
+	}
+	return result, nil, nil
+}
+
+// evaluatePlan returns the portion of the plan which can be applied.
+func (s *Simulation) evaluatePlan(snap *state.StateSnapshot, plan *structs.Plan) (*structs.PlanResult, error) {
+	_, dims, err := QuotaExhausted(snap, plan)
+	if err != nil {
+		return nil, err
+	}
+	if len(dims) > 0 {
+		index, err := snap.LatestIndex()
+		if err != nil {
+			return nil, err
+		}
+		return &structs.PlanResult{RefreshIndex: index}, nil
+	}
+
+	overBudget, err := PlanPreemptionsOverBudget(snap, plan)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &structs.PlanResult{
+		NodeUpdate:        make(map[string][]*structs.Allocation),
+		NodeAllocation:    make(map[string][]*structs.Allocation),
+		Deployment:        plan.Deployment.Copy(),
+		DeploymentUpdates: plan.DeploymentUpdates,
+		NodePreemptions:   make(map[string][]*structs.Allocation),
+	}
+
+	var rejectedNodes []string
+	for nodeID, allocs := range plan.NodeUpdate {
+		if _, ok := overBudget[nodeID]; !ok {
+			result.NodeUpdate[nodeID] = allocs
+		}
+	}
+	for nodeID, allocs := range plan.NodeAllocation {
+		if _, ok := overBudget[nodeID]; ok {
+			rejectedNodes = append(rejectedNodes, nodeID)
+			continue
+		}
+		result.NodeAllocation[nodeID] = allocs
+		if preemptions := plan.NodePreemptions[nodeID]; len(preemptions) > 0 {
+			result.NodePreemptions[nodeID] = preemptions
+		}
+	}
+	if len(rejectedNodes) == 0 {
+		return result, nil
+	}
+
+	index, err := snap.LatestIndex()
+	if err != nil {
+		return nil, err
+	}
+	if plan.AllAtOnce {
+		return &structs.PlanResult{RefreshIndex: index}, nil
+	}
+
+	result.RefreshIndex = index
+	CorrectAllOrNothingPlacements(plan, result, rejectedNodes)
+	CorrectDeploymentCanaries(result)
+	return result, nil
+}
+
+// applyPlan upserts the result of the plan into the state.
+func (s *Simulation) applyPlan(plan *structs.Plan, result *structs.PlanResult) error {
+	now := time.Now().UTC().UnixNano()
+	req := structs.ApplyPlanResultsRequest{
+		AllocUpdateRequest: structs.AllocUpdateRequest{
+			Job: plan.Job,
+		},
+		Deployment:        result.Deployment,
+		DeploymentUpdates: result.DeploymentUpdates,
+		EvalID:            plan.EvalID,
+	}
+	for _, allocs := range result.NodeUpdate {
+		for _, alloc := range allocs {
+			diff := alloc.AllocationDiff()
+			diff.ModifyTime = now
+			req.AllocsStopped = append(req.AllocsStopped, diff)
+		}
+	}
+	for _, allocs := range result.NodeAllocation {
+		for _, alloc := range allocs {
+			if alloc.CreateTime == 0 {
+				alloc.CreateTime = now
+			}
+			alloc.ModifyTime = now
+			req.AllocsUpdated = append(req.AllocsUpdated, alloc)
+		}
+	}
+	for _, allocs := range result.NodePreemptions {
+		for _, alloc := range allocs {
+			diff := alloc.AllocationDiff()
+			diff.ModifyTime = now
+			req.AllocsPreempted = append(req.AllocsPreempted, diff)
+		}
+	}
+
+	return s.State.UpsertPlanResults(structs.ApplyPlanResultsRequestType, result.AllocIndex, &req)
+}
+
+func (s *Simulation) UpdateEval(eval *structs.Evaluation) error {
+	s.planLock.Lock()
+	defer s.planLock.Unlock()
+
+	s.Evals = append(s.Evals, eval)
+	return s.State.UpsertEvals(structs.EvalUpdateRequestType, s.NextIndex(), []*structs.Evaluation{eval})
+}
+
+func (s *Simulation) CreateEval(eval *structs.Evaluation) error {
+	s.planLock.Lock()
+	defer s.planLock.Unlock()
+
+	s.CreateEvals = append(s.CreateEvals, eval)
+	return s.State.UpsertEvals(structs.EvalUpdateRequestType, s.NextIndex(), []*structs.Evaluation{eval})
+}
+
+func (s *Simulation) ReblockEval(eval *structs.Evaluation) error {
+	s.planLock.Lock()
+	defer s.planLock.Unlock()
+
+	// Check that the evaluation was already blocked.
+	old, err := s.State.EvalByID(nil, eval.ID)
+	if err != nil {
+		return err
+	}
+	if old == nil {
+		return fmt.Errorf("evaluation does not exist to be reblocked")
+	}
+	if old.Status != structs.EvalStatusBlocked {
+		return fmt.Errorf("evaluation %q is not already in a blocked state", old.ID)
+	}
+	return nil
+}
+
+// ServersMeetMinimumVersion always returns true as the simulation runs the
+// schedulers of the local binary.
+func (s *Simulation) ServersMeetMinimumVersion(_ *version.Version, _ bool) bool {
+	return true
+}
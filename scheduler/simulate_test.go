@@ -0,0 +1,119 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulation_Process(t *testing.T) {
+	ci.Parallel(t)
+
+	store := state.TestStateStore(t)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), mock.Node()))
+	}
+
+	// The simulation indexes follow the ones of the state
+	latest, err := store.LatestIndex()
+	require.NoError(t, err)
+	sim, err := NewSimulation(testlog.HCLogger(t), store)
+	require.NoError(t, err)
+	require.Equal(t, latest+1, sim.NextIndex())
+
+	// Register a job which does not fit entirely
+	job := mock.Job()
+	job.TaskGroups[0].Count = 30
+	eval, err := sim.Register(job)
+	require.NoError(t, err)
+	require.Equal(t, job.Type, eval.Type)
+	require.NoError(t, sim.Process(eval))
+
+	require.Len(t, sim.Plans, 1)
+	require.Len(t, sim.Evals, 1)
+	require.Equal(t, structs.EvalStatusComplete, sim.Evals[0].Status)
+	require.Contains(t, sim.Evals[0].FailedTGAllocs, job.TaskGroups[0].Name)
+
+	// The placements are applied to the state
+	var planned int
+	for _, allocs := range sim.Plans[0].NodeAllocation {
+		planned += len(allocs)
+	}
+	allocs, err := store.AllocsByJob(nil, job.Namespace, job.ID, false)
+	require.NoError(t, err)
+	require.NotEmpty(t, allocs)
+	require.Len(t, allocs, planned)
+
+	// The remaining placements are blocked
+	require.Len(t, sim.CreateEvals, 1)
+	require.Equal(t, structs.EvalStatusBlocked, sim.CreateEvals[0].Status)
+
+	// Periodic jobs cannot be simulated
+	_, err = sim.Register(mock.PeriodicJob())
+	require.EqualError(t, err, "periodic and parameterized jobs cannot be simulated")
+
+	// Nor can jobs using scheduler plugins
+	pluginJob := mock.Job()
+	pluginJob.Scheduler = "custom"
+	_, err = sim.Register(pluginJob)
+	require.EqualError(t, err, "jobs using scheduler plugins cannot be simulated")
+}
+
+func TestSimulation_SubmitPlan_Quota(t *testing.T) {
+	ci.Parallel(t)
+
+	store := state.TestStateStore(t)
+	node := mock.Node()
+	require.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, 100, node))
+	_, ns := quotaNamespace(t, store, 101, 500)
+
+	job := mock.Job()
+	job.Namespace = ns.Name
+	require.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 103, job))
+
+	sim, err := NewSimulation(testlog.HCLogger(t), store)
+	require.NoError(t, err)
+
+	newAlloc := func() *structs.Allocation {
+		alloc := mock.Alloc()
+		alloc.Namespace = ns.Name
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = node.ID
+		return alloc
+	}
+
+	// A plan exceeding the quota is rejected and forces a refresh
+	plan := &structs.Plan{
+		Job: job,
+		NodeAllocation: map[string][]*structs.Allocation{
+			node.ID: {newAlloc(), newAlloc()},
+		},
+	}
+	result, refreshed, err := sim.SubmitPlan(plan)
+	require.NoError(t, err)
+	require.NotNil(t, refreshed)
+	require.NotZero(t, result.RefreshIndex)
+	require.Empty(t, result.NodeAllocation)
+
+	allocs, err := store.AllocsByJob(nil, job.Namespace, job.ID, false)
+	require.NoError(t, err)
+	require.Empty(t, allocs)
+
+	// A plan within the quota is applied
+	plan.NodeAllocation[node.ID] = []*structs.Allocation{newAlloc()}
+	result, refreshed, err = sim.SubmitPlan(plan)
+	require.NoError(t, err)
+	require.Nil(t, refreshed)
+	require.Zero(t, result.RefreshIndex)
+	require.Len(t, result.NodeAllocation[node.ID], 1)
+
+	allocs, err = store.AllocsByJob(nil, job.Namespace, job.ID, false)
+	require.NoError(t, err)
+	require.Len(t, allocs, 1)
+}
@@ -1,6 +1,8 @@
 package scheduler
 
 import (
+	"fmt"
+
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -270,3 +272,206 @@ func (iter *SpreadIterator) computeSpreadInfo(tg *structs.TaskGroup) {
 	}
 	iter.tgSpreadInfo[tg.Name] = spreadInfos
 }
+
+// SpreadSkewIterator is a FeasibleIterator which filters the nodes on which a
+// placement would exceed the max skew of a spread of the job or task group.
+// The skew of a spread is the difference between the number of allocations of
+// the task group on the most and the least used values of its attribute,
+// among the values found on the nodes being considered.
+type SpreadSkewIterator struct {
+	ctx    Context
+	source FeasibleIterator
+	job    *structs.Job
+	tg     *structs.TaskGroup
+
+	// nodes is the set of nodes allocations may be placed on. The values of
+	// the spread attributes found on them make up the domain of the skew.
+	nodes   []*structs.Node
+	domains map[string]map[string]struct{}
+
+	// groupSpreads is a memoized map from task group to the spreads with a
+	// max skew which apply to it, along with their property sets
+	groupSpreads map[string][]*skewedSpread
+}
+
+// skewedSpread is a spread with a max skew and the property set tracking the
+// use of the values of its attribute.
+type skewedSpread struct {
+	spread *structs.Spread
+	pset   *propertySet
+}
+
+// NewSpreadSkewIterator creates a SpreadSkewIterator from a source.
+func NewSpreadSkewIterator(ctx Context, source FeasibleIterator) *SpreadSkewIterator {
+	return &SpreadSkewIterator{
+		ctx:          ctx,
+		source:       source,
+		domains:      make(map[string]map[string]struct{}),
+		groupSpreads: make(map[string][]*skewedSpread),
+	}
+}
+
+// SetNodes sets the nodes whose attribute values make up the domain of the
+// spreads.
+func (iter *SpreadSkewIterator) SetNodes(nodes []*structs.Node) {
+	iter.nodes = nodes
+	iter.domains = make(map[string]map[string]struct{})
+}
+
+func (iter *SpreadSkewIterator) SetJob(job *structs.Job) {
+	iter.job = job
+	iter.groupSpreads = make(map[string][]*skewedSpread)
+}
+
+func (iter *SpreadSkewIterator) SetTaskGroup(tg *structs.TaskGroup) {
+	iter.tg = tg
+
+	if _, ok := iter.groupSpreads[tg.Name]; !ok {
+		var spreads []*skewedSpread
+		for _, spread := range maxSkewSpreads(iter.job, tg) {
+			pset := NewPropertySet(iter.ctx, iter.job)
+			pset.SetTargetAttribute(spread.Attribute, tg.Name)
+			spreads = append(spreads, &skewedSpread{spread: spread, pset: pset})
+		}
+		iter.groupSpreads[tg.Name] = spreads
+	}
+}
+
+func (iter *SpreadSkewIterator) Next() *structs.Node {
+OUTER:
+	for {
+		option := iter.source.Next()
+
+		// Hot path if there is nothing to check
+		if option == nil || len(iter.groupSpreads[iter.tg.Name]) == 0 {
+			return option
+		}
+
+		for _, s := range iter.groupSpreads[iter.tg.Name] {
+			if !iter.satisfiesSkew(option, s) {
+				continue OUTER
+			}
+		}
+		return option
+	}
+}
+
+// satisfiesSkew returns whether placing an allocation on the option keeps
+// the skew of the spread within its max skew.
+func (iter *SpreadSkewIterator) satisfiesSkew(option *structs.Node, s *skewedSpread) bool {
+	attribute := s.spread.Attribute
+	if err := s.pset.errorBuilding; err != nil {
+		iter.ctx.Metrics().FilterNode(option, err.Error())
+		return false
+	}
+
+	value, ok := getProperty(option, attribute)
+	if !ok {
+		iter.ctx.Metrics().FilterNode(option, fmt.Sprintf("missing property %q", attribute))
+		return false
+	}
+
+	used := s.pset.GetCombinedUseMap()
+	count := used[value] + 1
+	min := count
+	for v := range iter.domain(attribute) {
+		if v != value && used[v] < min {
+			min = used[v]
+		}
+	}
+
+	if count-min > uint64(s.spread.MaxSkew) {
+		iter.ctx.Metrics().ExceedSpreadSkew(option, attribute)
+		return false
+	}
+	return true
+}
+
+// domain returns the values of the attribute found on the nodes.
+func (iter *SpreadSkewIterator) domain(attribute string) map[string]struct{} {
+	if domain, ok := iter.domains[attribute]; ok {
+		return domain
+	}
+	domain := spreadDomain(iter.nodes, attribute)
+	iter.domains[attribute] = domain
+	return domain
+}
+
+func (iter *SpreadSkewIterator) Reset() {
+	iter.source.Reset()
+	for _, spreads := range iter.groupSpreads {
+		for _, s := range spreads {
+			s.pset.PopulateProposed()
+		}
+	}
+}
+
+// maxSkewSpreads returns the spreads of the job and task group which have a
+// max skew.
+func maxSkewSpreads(job *structs.Job, tg *structs.TaskGroup) []*structs.Spread {
+	var spreads []*structs.Spread
+	for _, spread := range job.Spreads {
+		if spread.MaxSkew > 0 {
+			spreads = append(spreads, spread)
+		}
+	}
+	for _, spread := range tg.Spreads {
+		if spread.MaxSkew > 0 {
+			spreads = append(spreads, spread)
+		}
+	}
+	return spreads
+}
+
+// spreadDomain returns the set of values of the attribute found on the nodes.
+func spreadDomain(nodes []*structs.Node, attribute string) map[string]struct{} {
+	domain := make(map[string]struct{})
+	for _, node := range nodes {
+		if value, ok := getProperty(node, attribute); ok {
+			domain[value] = struct{}{}
+		}
+	}
+	return domain
+}
+
+// spreadViolations returns the spreads with a max skew of the task groups of
+// the job which the allocations of the job would still exceed once the plan
+// is applied. The nodes are the ones allocations may be placed on.
+func spreadViolations(ctx Context, job *structs.Job, nodes []*structs.Node) []*structs.SpreadViolation {
+	var violations []*structs.SpreadViolation
+	for _, tg := range job.TaskGroups {
+		for _, spread := range maxSkewSpreads(job, tg) {
+			domain := spreadDomain(nodes, spread.Attribute)
+			if len(domain) == 0 {
+				continue
+			}
+
+			pset := NewPropertySet(ctx, job)
+			pset.SetTargetAttribute(spread.Attribute, tg.Name)
+			used := pset.GetCombinedUseMap()
+
+			var min, max uint64
+			first := true
+			for value := range domain {
+				count := used[value]
+				if first || count < min {
+					min = count
+				}
+				if first || count > max {
+					max = count
+				}
+				first = false
+			}
+
+			if skew := int(max - min); skew > spread.MaxSkew {
+				violations = append(violations, &structs.SpreadViolation{
+					TaskGroup: tg.Name,
+					Attribute: spread.Attribute,
+					Skew:      skew,
+					MaxSkew:   spread.MaxSkew,
+				})
+			}
+		}
+	}
+	return violations
+}
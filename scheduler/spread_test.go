@@ -918,3 +918,181 @@ func TestSpreadPanicDowngrade(t *testing.T) {
 	require.NoError(t, processErr, "failed to process eval")
 	require.Len(t, h.Plans, 1)
 }
+
+func TestSpreadSkewIterator(t *testing.T) {
+	ci.Parallel(t)
+
+	state, ctx := testContext(t)
+	racks := []string{"r1", "r1", "r2", "r3", ""}
+	var nodes []*structs.Node
+	for i, rack := range racks {
+		node := mock.Node()
+		if rack != "" {
+			node.Meta["rack"] = rack
+		}
+		require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), node))
+		nodes = append(nodes, node)
+	}
+
+	job := mock.Job()
+	tg := job.TaskGroups[0]
+	tg.Spreads = []*structs.Spread{{
+		Attribute: "${meta.rack}",
+		Weight:    50,
+		MaxSkew:   1,
+	}}
+
+	// Place 2 allocations on r1 and 1 on r2
+	var allocs []*structs.Allocation
+	for _, node := range []*structs.Node{nodes[0], nodes[1], nodes[2]} {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.TaskGroup = tg.Name
+		alloc.NodeID = node.ID
+		allocs = append(allocs, alloc)
+	}
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000, allocs))
+
+	static := NewStaticIterator(ctx, nodes)
+	iter := NewSpreadSkewIterator(ctx, static)
+	iter.SetNodes(nodes)
+	iter.SetJob(job)
+	iter.SetTaskGroup(tg)
+
+	// Only r3 can take a placement without exceeding the max skew
+	out := collectFeasible(iter)
+	require.Len(t, out, 1)
+	require.Equal(t, nodes[3].ID, out[0].ID)
+
+	metrics := ctx.Metrics()
+	require.Equal(t, 4, metrics.NodesFiltered)
+	require.Equal(t, map[string]int{"${meta.rack}": 3}, metrics.SpreadSkewExceeded)
+	require.Equal(t, 1, metrics.ConstraintFiltered[`missing property "${meta.rack}"`])
+
+	// Once the plan places an allocation on r3, r2 is allowed again
+	ctx.Plan().NodeAllocation[nodes[3].ID] = []*structs.Allocation{{
+		ID:        uuid.Generate(),
+		Namespace: job.Namespace,
+		JobID:     job.ID,
+		TaskGroup: tg.Name,
+		NodeID:    nodes[3].ID,
+	}}
+	ctx.Reset()
+	iter.Reset()
+
+	out = collectFeasible(iter)
+	require.Len(t, out, 2)
+	require.ElementsMatch(t, []string{nodes[2].ID, nodes[3].ID}, []string{out[0].ID, out[1].ID})
+}
+
+func TestServiceSched_Spread_MaxSkew(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	// Create two large nodes on r1 and r2 and a node on r3 which only fits
+	// one allocation
+	for _, rack := range []string{"r1", "r2", "r3"} {
+		node := mock.Node()
+		node.Meta["rack"] = rack
+		if rack == "r3" {
+			node.NodeResources.Cpu.CpuShares = 600
+		}
+		require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+	}
+
+	job := mock.Job()
+	job.TaskGroups[0].Count = 6
+	job.TaskGroups[0].Spreads = []*structs.Spread{{
+		Attribute: "${meta.rack}",
+		Weight:    50,
+		MaxSkew:   1,
+	}}
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+	require.NoError(t, h.Process(NewServiceScheduler, eval))
+
+	// The last allocation is not placed rather than exceeding the max skew
+	require.Len(t, h.Plans, 1)
+	perRack := map[string]int{}
+	for nodeID, allocs := range h.Plans[0].NodeAllocation {
+		node, err := h.State.NodeByID(nil, nodeID)
+		require.NoError(t, err)
+		perRack[node.Meta["rack"]] += len(allocs)
+	}
+	require.Equal(t, map[string]int{"r1": 2, "r2": 2, "r3": 1}, perRack)
+
+	require.Len(t, h.Evals, 1)
+	metrics := h.Evals[0].FailedTGAllocs[job.TaskGroups[0].Name]
+	require.NotNil(t, metrics)
+	require.Equal(t, 2, metrics.SpreadSkewExceeded["${meta.rack}"])
+}
+
+func TestServiceSched_Spread_MaxSkewViolation(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	var nodes []*structs.Node
+	for _, rack := range []string{"r1", "r1", "r2"} {
+		node := mock.Node()
+		node.Meta["rack"] = rack
+		require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+		nodes = append(nodes, node)
+	}
+
+	job := mock.Job()
+	job.TaskGroups[0].Count = 4
+	job.TaskGroups[0].Spreads = []*structs.Spread{{
+		Attribute: "${meta.rack}",
+		Weight:    50,
+		MaxSkew:   1,
+	}}
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+	// Create allocations placed on r1 only, as if r2 had been unavailable
+	var allocs []*structs.Allocation
+	for i := 0; i < 3; i++ {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.Name = structs.AllocName(job.ID, job.TaskGroups[0].Name, uint(i))
+		alloc.NodeID = nodes[i%2].ID
+		allocs = append(allocs, alloc)
+	}
+	require.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), allocs))
+
+	eval := &structs.Evaluation{
+		Namespace:    structs.DefaultNamespace,
+		ID:           uuid.Generate(),
+		Priority:     job.Priority,
+		TriggeredBy:  structs.EvalTriggerJobRegister,
+		JobID:        job.ID,
+		AnnotatePlan: true,
+		Status:       structs.EvalStatusPending,
+	}
+	require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+	require.NoError(t, h.Process(NewServiceScheduler, eval))
+
+	// The missing allocation is placed on r2 but the allocations would still
+	// exceed the max skew
+	require.Len(t, h.Plans, 1)
+	plan := h.Plans[0]
+	require.Len(t, plan.NodeAllocation[nodes[2].ID], 1)
+	require.Equal(t, []*structs.SpreadViolation{{
+		TaskGroup: job.TaskGroups[0].Name,
+		Attribute: "${meta.rack}",
+		Skew:      2,
+		MaxSkew:   1,
+	}}, plan.Annotations.SpreadViolations)
+}
@@ -49,6 +49,7 @@ type GenericStack struct {
 	quota                FeasibleIterator
 	jobVersion           *uint64
 	jobConstraint        *ConstraintChecker
+	jobNodePool          *NodePoolChecker
 	taskGroupDrivers     *DriverChecker
 	taskGroupConstraint  *ConstraintChecker
 	taskGroupDevices     *DeviceChecker
@@ -58,7 +59,9 @@ type GenericStack struct {
 
 	distinctHostsConstraint    *DistinctHostsIterator
 	distinctPropertyConstraint *DistinctPropertyIterator
+	spreadSkew                 *SpreadSkewIterator
 	binPack                    *BinPackIterator
+	utilization                *UtilizationIterator
 	jobAntiAff                 *JobAntiAffinityIterator
 	nodeReschedulingPenalty    *NodeReschedulingPenaltyIterator
 	limit                      *LimitIterator
@@ -75,6 +78,7 @@ func (s *GenericStack) SetNodes(baseNodes []*structs.Node) {
 
 	// Update the set of base nodes
 	s.source.SetNodes(baseNodes)
+	s.spreadSkew.SetNodes(baseNodes)
 
 	// Apply a limit function. This is to avoid scanning *every* possible node.
 	// For batch jobs we only need to evaluate 2 options and depend on the
@@ -100,9 +104,14 @@ func (s *GenericStack) SetJob(job *structs.Job) {
 	s.jobVersion = &jobVer
 
 	s.jobConstraint.SetConstraints(job.Constraints)
+	s.jobNodePool.SetNodePool(job.NodePool)
 	s.distinctHostsConstraint.SetJob(job)
 	s.distinctPropertyConstraint.SetJob(job)
+	s.spreadSkew.SetJob(job)
 	s.binPack.SetJob(job)
+	schedConfig := nodePoolSchedulerConfig(s.ctx.State(), job.NodePool)
+	s.binPack.SetSchedulerConfiguration(schedConfig)
+	s.utilization.SetSchedulerConfiguration(schedConfig)
 	s.jobAntiAff.SetJob(job)
 	s.nodeAffinity.SetJob(job)
 	s.spread.SetJob(job)
@@ -151,6 +160,7 @@ func (s *GenericStack) Select(tg *structs.TaskGroup, options *SelectOptions) *Ra
 	}
 	s.distinctHostsConstraint.SetTaskGroup(tg)
 	s.distinctPropertyConstraint.SetTaskGroup(tg)
+	s.spreadSkew.SetTaskGroup(tg)
 	s.wrappedChecks.SetTaskGroup(tg.Name)
 	s.binPack.SetTaskGroup(tg)
 	if options != nil {
@@ -195,6 +205,7 @@ type SystemStack struct {
 	wrappedChecks        *FeasibilityWrapper
 	quota                FeasibleIterator
 	jobConstraint        *ConstraintChecker
+	jobNodePool          *NodePoolChecker
 	taskGroupDrivers     *DriverChecker
 	taskGroupConstraint  *ConstraintChecker
 	taskGroupDevices     *DeviceChecker
@@ -223,6 +234,9 @@ func NewSystemStack(sysbatch bool, ctx Context) *SystemStack {
 	// Attach the job constraints. The job is filled in later.
 	s.jobConstraint = NewConstraintChecker(ctx, nil)
 
+	// Filter on the node pool of the job. The job is filled in later.
+	s.jobNodePool = NewNodePoolChecker(ctx, "")
+
 	// Filter on task group drivers first as they are faster
 	s.taskGroupDrivers = NewDriverChecker(ctx, nil)
 
@@ -245,7 +259,7 @@ func NewSystemStack(sysbatch bool, ctx Context) *SystemStack {
 	// which feasibility checking can be skipped if the computed node class has
 	// previously been marked as eligible or ineligible. Generally this will be
 	// checks that only needs to examine the single node to determine feasibility.
-	jobs := []FeasibilityChecker{s.jobConstraint}
+	jobs := []FeasibilityChecker{s.jobNodePool, s.jobConstraint}
 	tgs := []FeasibilityChecker{
 		s.taskGroupDrivers,
 		s.taskGroupConstraint,
@@ -297,8 +311,10 @@ func (s *SystemStack) SetNodes(baseNodes []*structs.Node) {
 
 func (s *SystemStack) SetJob(job *structs.Job) {
 	s.jobConstraint.SetConstraints(job.Constraints)
+	s.jobNodePool.SetNodePool(job.NodePool)
 	s.distinctPropertyConstraint.SetJob(job)
 	s.binPack.SetJob(job)
+	s.binPack.SetSchedulerConfiguration(nodePoolSchedulerConfig(s.ctx.State(), job.NodePool))
 	s.ctx.Eligibility().SetJob(job)
 
 	if contextual, ok := s.quota.(ContextualIterator); ok {
@@ -356,6 +372,9 @@ func NewGenericStack(batch bool, ctx Context) *GenericStack {
 	// Attach the job constraints. The job is filled in later.
 	s.jobConstraint = NewConstraintChecker(ctx, nil)
 
+	// Filter on the node pool of the job. The job is filled in later.
+	s.jobNodePool = NewNodePoolChecker(ctx, "")
+
 	// Filter on task group drivers first as they are faster
 	s.taskGroupDrivers = NewDriverChecker(ctx, nil)
 
@@ -378,7 +397,7 @@ func NewGenericStack(batch bool, ctx Context) *GenericStack {
 	// which feasibility checking can be skipped if the computed node class has
 	// previously been marked as eligible or ineligible. Generally this will be
 	// checks that only needs to examine the single node to determine feasibility.
-	jobs := []FeasibilityChecker{s.jobConstraint}
+	jobs := []FeasibilityChecker{s.jobNodePool, s.jobConstraint}
 	tgs := []FeasibilityChecker{
 		s.taskGroupDrivers,
 		s.taskGroupConstraint,
@@ -395,12 +414,15 @@ func NewGenericStack(batch bool, ctx Context) *GenericStack {
 	// Filter on distinct property constraints.
 	s.distinctPropertyConstraint = NewDistinctPropertyIterator(ctx, s.distinctHostsConstraint)
 
+	// Filter on the max skew of spreads
+	s.spreadSkew = NewSpreadSkewIterator(ctx, s.distinctPropertyConstraint)
+
 	// Create the quota iterator to determine if placements would result in
 	// the quota attached to the namespace of the job to go over.
 	// Note: the quota iterator must be the last feasibility iterator before
 	// we upgrade to ranking, or our quota usage will include ineligible
 	// nodes!
-	s.quota = NewQuotaIterator(ctx, s.distinctPropertyConstraint)
+	s.quota = NewQuotaIterator(ctx, s.spreadSkew)
 
 	// Upgrade from feasible to rank iterator
 	rankSource := NewFeasibleRankIterator(ctx, s.quota)
@@ -410,9 +432,13 @@ func NewGenericStack(batch bool, ctx Context) *GenericStack {
 	_, schedConfig, _ := ctx.State().SchedulerConfig()
 	s.binPack = NewBinPackIterator(ctx, rankSource, false, 0, schedConfig)
 
+	// Apply scores based on the utilization reported by the nodes, if
+	// enabled. This favors the nodes which are actually idle.
+	s.utilization = NewUtilizationIterator(ctx, s.binPack, schedConfig)
+
 	// Apply the job anti-affinity iterator. This is to avoid placing
 	// multiple allocations on the same node for this job.
-	s.jobAntiAff = NewJobAntiAffinityIterator(ctx, s.binPack, "")
+	s.jobAntiAff = NewJobAntiAffinityIterator(ctx, s.utilization, "")
 
 	// Apply node rescheduling penalty. This tries to avoid placing on a
 	// node where the allocation failed previously
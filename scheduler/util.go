@@ -346,6 +346,19 @@ func diffSystemAllocs(
 	return result
 }
 
+// nodePoolSchedulerConfig returns the scheduler configuration with the
+// overrides of the node pool applied. The cluster wide scheduler
+// configuration is returned if the node pool cannot be found.
+func nodePoolSchedulerConfig(state State, pool string) *structs.SchedulerConfiguration {
+	_, schedConfig, _ := state.SchedulerConfig()
+
+	nodePool, err := state.NodePoolByName(nil, pool)
+	if err != nil || nodePool == nil {
+		return schedConfig
+	}
+	return schedConfig.WithNodePool(nodePool)
+}
+
 // readyNodesInDCs returns all the ready nodes in the given datacenters and a
 // mapping of each data center to the count of ready nodes.
 func readyNodesInDCs(state State, dcs []string) ([]*structs.Node, map[string]struct{}, map[string]int, error) {
@@ -564,6 +577,11 @@ func tasksUpdated(jobA, jobB *structs.Job, taskGroup string) bool {
 			return true
 		}
 
+		// The log rotation and sinks are set up when the task starts
+		if !at.LogConfig.Equals(bt.LogConfig) {
+			return true
+		}
+
 		// Check the metadata
 		if !reflect.DeepEqual(
 			jobA.CombinedTaskMeta(taskGroup, at.Name),
@@ -837,6 +837,25 @@ func TestTasksUpdated(t *testing.T) {
 	j28 := j27.Copy()
 	j28.TaskGroups[0].Tasks[0].CSIPluginConfig.Type = "monolith"
 	require.True(t, tasksUpdated(j27, j28, name))
+
+	// Add a log sink
+	j29 := mock.Job()
+	j30 := j29.Copy()
+	j30.TaskGroups[0].Tasks[0].LogConfig.Sinks = []*structs.LogSink{{
+		Type:    structs.LogSinkTypeSyslog,
+		Address: "udp://127.0.0.1:514",
+	}}
+	require.True(t, tasksUpdated(j29, j30, name))
+
+	// Alter a log sink
+	j31 := j30.Copy()
+	j31.TaskGroups[0].Tasks[0].LogConfig.Sinks[0].Tag = "web"
+	require.True(t, tasksUpdated(j30, j31, name))
+
+	// Alter the log rotation
+	j32 := mock.Job()
+	j32.TaskGroups[0].Tasks[0].LogConfig.MaxFiles++
+	require.True(t, tasksUpdated(j29, j32, name))
 }
 
 func TestTasksUpdated_connectServiceUpdated(t *testing.T) {
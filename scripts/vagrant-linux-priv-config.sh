@@ -18,6 +18,7 @@ apt-get install -y \
 	git \
 	libc6-dev-i386 \
 	libpcre3-dev \
+	libseccomp-dev \
 	linux-libc-dev:i386 \
 	pkg-config \
 	zip \